@@ -54,6 +54,20 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("registering tangent: %w", err)
 	}
 	session.Init()
+	session.RecoverSessions(ctx)
+	go session.RunHeartbeats(slog.WithContext(ctx))
+
+	shutdownTracing, err := session.InitTracing(ctx)
+	if err != nil {
+		return fmt.Errorf("initializing tracing: %w", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	shutdownMetrics, err := session.InitMetrics(ctx)
+	if err != nil {
+		return fmt.Errorf("initializing metrics: %w", err)
+	}
+	defer shutdownMetrics(context.Background())
 
 	// Start the tangent server
 	serverErrors, shutdownTangent, err := createTangentServer(ctx)
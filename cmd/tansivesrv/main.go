@@ -14,6 +14,7 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	zerolog "github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
@@ -55,6 +56,13 @@ func run(ctx context.Context) error {
 	config.Init()
 	db.Init()
 	session.Init()
+	catalogmanager.SetSkillSetLimits(catalogmanager.SkillSetLimits{
+		MaxSources:      config.Config().SkillSet.MaxSources,
+		MaxSkills:       config.Config().SkillSet.MaxSkills,
+		MaxContexts:     config.Config().SkillSet.MaxContexts,
+		MaxDependencies: config.Config().SkillSet.MaxDependencies,
+		MaxJSONDepth:    config.Config().SkillSet.MaxJSONDepth,
+	})
 
 	if config.Config().ServerPort == "" {
 		return fmt.Errorf("server port not defined")
@@ -66,6 +74,10 @@ func run(ctx context.Context) error {
 		}
 	}
 
+	go session.RunAuditLogJanitor(log.WithContext(ctx))
+	go session.RunSessionWatchdog(log.WithContext(ctx))
+	go catalogmanager.RunSkillSetSearchBackfill(log.WithContext(ctx))
+
 	s, err := server.CreateNewServer()
 	if err != nil {
 		return fmt.Errorf("creating server: %w", err)
@@ -132,6 +144,17 @@ func run(ctx context.Context) error {
 				log.Error().Err(err).Msg("could not stop server")
 			}
 		}
+
+		// Let in-flight session callbacks and audit log exports finish.
+		waitCtx, waitCancel := context.WithTimeout(ctx, 30*time.Second)
+		defer waitCancel()
+		if err := session.WaitForBackgroundDeliveries(waitCtx); err != nil {
+			log.Error().Err(err).Msg("could not finish background deliveries")
+		}
+
+		if err := db.Close(); err != nil {
+			log.Error().Err(err).Msg("could not close database connections")
+		}
 	}
 
 	log.Info().Msg("server stopped")
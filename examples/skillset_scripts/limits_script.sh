@@ -0,0 +1,21 @@
+#!/bin/bash
+
+# Report the directory and niceness the script runs with
+echo "Working directory: $(pwd)"
+echo "Niceness: $(nice)"
+
+# Allocate a large string to exercise memory limits
+if echo "$1" | grep -q "allocate_memory"; then
+    data=$(head -c 268435456 /dev/zero | tr '\0' 'a')
+    echo "Allocated ${#data} bytes"
+fi
+
+# Allocate the same amount in a forked child, so that only the child's memory grows
+if echo "$1" | grep -q "allocate_child_memory"; then
+    (data=$(head -c 268435456 /dev/zero | tr '\0' 'a'); echo "Allocated in child ${#data} bytes")
+fi
+
+# Run until the invocation is cancelled
+if echo "$1" | grep -q "sleep"; then
+    sleep 30
+fi
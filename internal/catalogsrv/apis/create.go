@@ -1,13 +1,19 @@
 package apis
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	schemaerr "github.com/tansive/tansive/internal/catalogsrv/schema/errors"
 	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/pkg/types"
 )
 
 // createObject creates a new resource object
@@ -55,6 +61,240 @@ func createObject(r *http.Request) (*httpx.Response, error) {
 		Location:   resourceLoc,
 		Response:   nil,
 	}
+	if reporter, ok := manager.(interfaces.ResultReporter); ok {
+		resp.Response = reporter.Result()
+	}
 
 	return resp, nil
 }
+
+// CloneViewReq is the request body for cloning a view.
+type CloneViewReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// cloneView creates a new view from the rules of an existing view and returns the created view
+func cloneView(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+
+	var req CloneViewReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, httpx.ErrInvalidRequest("unable to parse request")
+	}
+	if req.Name == "" {
+		return nil, httpx.ErrInvalidRequest("name is required")
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := policy.CloneView(ctx, reqContext.CatalogID, chi.URLParam(r, "viewName"), req.Name, req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	reqContext.ObjectName = view.Label
+	manager, err := catalogmanager.ResourceManagerForKind(ctx, catcommon.ViewKind, reqContext)
+	if err != nil {
+		return nil, err
+	}
+
+	rsrc, err := manager.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   manager.Location(),
+		Response:   rsrc,
+	}, nil
+}
+
+// RevokeViewTokensRsp is the response to a view token revocation request.
+type RevokeViewTokensRsp struct {
+	TokenEpoch int64 `json:"tokenEpoch"`
+}
+
+// revokeViewTokens invalidates all outstanding access tokens bound to a view
+func revokeViewTokens(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	epoch, err := policy.RevokeViewTokens(ctx, reqContext.CatalogID, chi.URLParam(r, "viewName"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   &RevokeViewTokensRsp{TokenEpoch: epoch},
+	}, nil
+}
+
+// ValidationError describes a problem found in a resource definition.
+type ValidationError struct {
+	Field string `json:"field"`
+	Value any    `json:"value,omitempty"`
+	Error string `json:"error"`
+}
+
+// ValidateViewRsp is the response to a view validation request.
+type ValidateViewRsp struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// validateView runs the validation performed when creating a view without persisting the view
+func validateView(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+
+	req, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	if err := validateRequest(req, catcommon.ViewKind); err != nil {
+		return nil, err
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &interfaces.Metadata{Catalog: reqContext.Catalog}
+	if reqContext.Variant != "" {
+		m.Variant = types.NullableStringFrom(reqContext.Variant)
+	}
+	if reqContext.Namespace != "" {
+		m.Namespace = types.NullableStringFrom(reqContext.Namespace)
+	}
+
+	validationErrors, appErr := policy.ValidateView(ctx, req, m)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	rsp := ValidateViewRsp{Valid: len(validationErrors) == 0}
+	for _, ve := range validationErrors {
+		rsp.Errors = append(rsp.Errors, NewValidationError(ve))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// NewValidationError converts a schema validation error for a validation response.
+func NewValidationError(ve schemaerr.ValidationError) ValidationError {
+	item := ValidationError{Field: ve.Field, Error: ve.ErrStr}
+	// validation errors carry their values as variadic arguments
+	if values, ok := ve.Value.([]any); ok {
+		if len(values) == 1 {
+			item.Value = values[0]
+		} else if len(values) > 1 {
+			item.Value = values
+		}
+	} else {
+		item.Value = ve.Value
+	}
+	return item
+}
+
+// ValidateSkillSetRsp is the response to a skillset validation request.
+type ValidateSkillSetRsp struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// validateSkillSet runs the validation performed when creating a skillset without persisting it.
+// With stream=true, each error is written as a line of NDJSON as soon as it is found, so that
+// problems in a large skillset can be shown incrementally.
+func validateSkillSet(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+
+	req, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	if err := validateRequest(req, catcommon.SkillSetKind); err != nil {
+		return nil, err
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &interfaces.Metadata{
+		Catalog:   reqContext.Catalog,
+		Variant:   types.NullableStringFrom(reqContext.Variant),
+		Namespace: types.NullableStringFrom(reqContext.Namespace),
+	}
+	skillSet, appErr := catalogmanager.ParseSkillSet(ctx, req, m)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if appErr := skillSet.LoadCatalogSpec(ctx); appErr != nil {
+		return nil, appErr
+	}
+
+	if r.URL.Query().Get("stream") != "true" {
+		validationErrors := skillSet.Validate()
+		rsp := ValidateSkillSetRsp{Valid: len(validationErrors) == 0}
+		for _, ve := range validationErrors {
+			rsp.Errors = append(rsp.Errors, NewValidationError(ve))
+		}
+		return &httpx.Response{
+			StatusCode: http.StatusOK,
+			Response:   rsp,
+		}, nil
+	}
+
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "application/x-ndjson",
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			errs := make(chan schemaerr.ValidationError)
+			go skillSet.ValidateStream(ctx, errs)
+
+			flusher, _ := w.(http.Flusher)
+			encoder := json.NewEncoder(w)
+			for ve := range errs {
+				if err := encoder.Encode(NewValidationError(ve)); err != nil {
+					// drain the stream so that validation can finish
+					for range errs {
+					}
+					return err
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			return nil
+		},
+	}, nil
+}
@@ -2,12 +2,17 @@ package apis
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
 	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/pkg/types"
 )
 
 func getObject(r *http.Request) (*httpx.Response, error) {
@@ -34,6 +39,14 @@ func getObject(r *http.Request) (*httpx.Response, error) {
 		return nil, err
 	}
 
+	// Skillsets can be fetched with only some spec sections, e.g. ?fields=skills,sources
+	if fields := fieldsParam(r); kind == catcommon.KindNameSkillsets && len(fields) > 0 {
+		rsrc, err = catalogmanager.SelectSkillSetFields(rsrc, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	rsp := &httpx.Response{
 		StatusCode: http.StatusOK,
 		Response:   rsrc,
@@ -41,6 +54,212 @@ func getObject(r *http.Request) (*httpx.Response, error) {
 	return rsp, nil
 }
 
+// fieldsParam returns the comma separated values of the fields query parameter.
+func fieldsParam(r *http.Request) []string {
+	var fields []string
+	for _, v := range r.URL.Query()["fields"] {
+		for _, field := range strings.Split(v, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// describeSkillSet returns a summary of a skillset suitable for generating documentation.
+// It is served at GET /skillsets/{path}/describe.
+func describeSkillSet(r *http.Request) (*httpx.Response, error) {
+	sm, err := loadRequestSkillSet(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   sm.Describe(),
+	}, nil
+}
+
+// skillTransformRsp holds the transform source code of a skill.
+type skillTransformRsp struct {
+	Skill string `json:"skill"`
+	Input string `json:"input"`
+}
+
+// getSkillTransform returns the source code of a skill's input transform, or no content if the
+// skill has none, so that transforms can be reviewed without fetching the skillset.
+// It is served at GET /skillsets/{path}/skills/{name}/transform.
+func getSkillTransform(r *http.Request) (*httpx.Response, error) {
+	sm, err := loadRequestSkillSet(r)
+	if err != nil {
+		return nil, err
+	}
+	skillName := skillNameFromContext(r.Context())
+	skill, apperr := sm.GetSkill(skillName)
+	if apperr != nil {
+		return nil, catalogmanager.ErrObjectNotFound.Msg("skill " + skillName + " not found")
+	}
+
+	if skill.Transform.IsNil() || skill.Transform.String() == "" {
+		return &httpx.Response{StatusCode: http.StatusNoContent}, nil
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &skillTransformRsp{
+			Skill: skill.Name,
+			Input: skill.Transform.String(),
+		},
+	}, nil
+}
+
+// exportSkillSet returns a bundle of the skillset that can be applied to promote it standalone.
+// With the withDeps=true query parameter the bundle includes the skillset's transitive SkillSet
+// and Resource dependencies. It is served at GET /skillsets/{path}/export.
+func exportSkillSet(r *http.Request) (*httpx.Response, error) {
+	withDeps := false
+	if v := r.URL.Query().Get("withDeps"); v != "" {
+		var err error
+		withDeps, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, httpx.ErrInvalidRequest("withDeps must be true or false")
+		}
+	}
+
+	sm, err := loadRequestSkillSet(r)
+	if err != nil {
+		return nil, err
+	}
+	bundle, apperr := catalogmanager.BundleSkillSet(r.Context(), sm, withDeps)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   bundle,
+	}, nil
+}
+
+// diffSkillSet returns what changed between two saved versions of a skillset, given by the from
+// and to query parameters. It is served at GET /skillsets/{path}/diff.
+func diffSkillSet(r *http.Request) (*httpx.Response, error) {
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		return nil, httpx.ErrInvalidRequest("from and to versions are required")
+	}
+
+	sm, err := loadRequestSkillSet(r)
+	if err != nil {
+		return nil, err
+	}
+	m := sm.Metadata()
+	diff, apperr := catalogmanager.DiffSkillSetVersions(r.Context(), &m, from, to)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   diff,
+	}, nil
+}
+
+// getViewPermissions returns the actions a view allows and denies on the resource given by the
+// resource query parameter. It is served at GET /views/{viewName}/permissions.
+func getViewPermissions(r *http.Request) (*httpx.Response, error) {
+	resource := r.URL.Query().Get("resource")
+	if resource == "" {
+		return nil, httpx.ErrInvalidRequest("resource is required")
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, apperr := policy.EffectivePermissionsForView(r.Context(), reqContext.CatalogID, chi.URLParam(r, "viewName"), resource)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   permissions,
+	}, nil
+}
+
+// Page sizes for view coverage
+const (
+	DefaultViewCoveragePageSize = 100
+	MaxViewCoveragePageSize     = 1000
+)
+
+// getViewCoverage returns, for each resource in the catalog, whether the view grants any action
+// on it. Resources are ordered by path. The limit query parameter sets the page size and cursor
+// continues after the resource of that path. It is served at GET /views/{viewName}/coverage.
+func getViewCoverage(r *http.Request) (*httpx.Response, error) {
+	limit := DefaultViewCoveragePageSize
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > MaxViewCoveragePageSize {
+			return nil, httpx.ErrInvalidRequest("limit must be between 1 and " + strconv.Itoa(MaxViewCoveragePageSize))
+		}
+		limit = n
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage, apperr := policy.CoverageForView(r.Context(), reqContext.CatalogID, chi.URLParam(r, "viewName"), r.URL.Query().Get("cursor"), limit)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   coverage,
+	}, nil
+}
+
+// loadRequestSkillSet loads the skillset addressed by the request path.
+func loadRequestSkillSet(r *http.Request) (catalogmanager.SkillSetManager, error) {
+	m, err := requestSkillSetMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	sm, apperr := catalogmanager.LoadSkillSetManagerByPath(r.Context(), m)
+	if apperr != nil {
+		return nil, apperr
+	}
+	return sm, nil
+}
+
+// requestSkillSetMetadata returns the metadata of the skillset addressed by the request path.
+func requestSkillSetMetadata(r *http.Request) (*interfaces.Metadata, error) {
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+	if reqContext.ObjectType != catcommon.CatalogObjectTypeSkillset || reqContext.ObjectName == "" {
+		return nil, httpx.ErrInvalidRequest("missing skillset path")
+	}
+
+	m := &interfaces.Metadata{
+		Catalog:   reqContext.Catalog,
+		Variant:   types.NullableStringFrom(reqContext.Variant),
+		Namespace: types.NullableStringFrom(reqContext.Namespace),
+		Path:      reqContext.ObjectPath,
+		Name:      reqContext.ObjectName,
+	}
+	if err := m.Validate(); err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+	return m, nil
+}
+
 type StatusRsp struct {
 	UserID        string                 `json:"userID,omitempty"`
 	ServerTime    string                 `json:"serverTime,omitempty"`
@@ -2,10 +2,24 @@ package apis
 
 import (
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
 	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// Page sizes for listing adoptable views
+const (
+	DefaultAdoptableViewsPageSize = 100
+	MaxAdoptableViewsPageSize     = 1000
 )
 
 func listObjects(r *http.Request) (*httpx.Response, error) {
@@ -38,3 +52,75 @@ func listObjects(r *http.Request) (*httpx.Response, error) {
 	}
 	return rsp, nil
 }
+
+type adoptableView struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// adoptableViewsRsp is a page of adoptable views. NextCursor is set when more views follow
+// and is passed as the cursor query parameter to fetch the next page.
+type adoptableViewsRsp struct {
+	Views      []adoptableView `json:"views"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+// listAdoptableViews lists the views in the catalog that the caller's view is allowed to adopt,
+// ordered by name. The limit query parameter sets the page size and cursor continues after
+// the view of that name.
+func listAdoptableViews(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	limit := DefaultAdoptableViewsPageSize
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > MaxAdoptableViewsPageSize {
+			return nil, httpx.ErrInvalidRequest("limit must be between 1 and " + strconv.Itoa(MaxAdoptableViewsPageSize))
+		}
+		limit = n
+	}
+	cursor := r.URL.Query().Get("cursor")
+
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		return nil, httpx.ErrInvalidRequest("missing catalog context")
+	}
+	views, err := db.DB(ctx).ListViewsByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load views")
+		return nil, err
+	}
+	adoptable, err := policy.AdoptableViews(ctx, views)
+	if err != nil {
+		return nil, err
+	}
+	// the cursor relies on byte-wise ordering, which may differ from the database collation
+	slices.SortFunc(adoptable, func(a, b *models.View) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   paginateAdoptableViews(adoptable, cursor, limit),
+	}, nil
+}
+
+// paginateAdoptableViews returns up to limit views whose names sort after cursor.
+// Views must be sorted by name.
+func paginateAdoptableViews(views []*models.View, cursor string, limit int) *adoptableViewsRsp {
+	rsp := &adoptableViewsRsp{Views: []adoptableView{}}
+	for _, view := range views {
+		if cursor != "" && view.Label <= cursor {
+			continue
+		}
+		if len(rsp.Views) == limit {
+			rsp.NextCursor = rsp.Views[len(rsp.Views)-1].Name
+			break
+		}
+		rsp.Views = append(rsp.Views, adoptableView{
+			Name:        view.Label,
+			Description: view.Description,
+		})
+	}
+	return rsp
+}
@@ -0,0 +1,46 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+)
+
+func TestPaginateAdoptableViews(t *testing.T) {
+	var views []*models.View
+	for _, label := range []string{"a", "b", "c", "d", "e"} {
+		views = append(views, &models.View{Label: label, Description: "view " + label})
+	}
+	names := func(rsp *adoptableViewsRsp) []string {
+		var n []string
+		for _, v := range rsp.Views {
+			n = append(n, v.Name)
+		}
+		return n
+	}
+
+	rsp := paginateAdoptableViews(views, "", 2)
+	assert.Equal(t, []string{"a", "b"}, names(rsp))
+	assert.Equal(t, "view a", rsp.Views[0].Description)
+	assert.Equal(t, "b", rsp.NextCursor)
+
+	rsp = paginateAdoptableViews(views, rsp.NextCursor, 2)
+	assert.Equal(t, []string{"c", "d"}, names(rsp))
+	assert.Equal(t, "d", rsp.NextCursor)
+
+	rsp = paginateAdoptableViews(views, rsp.NextCursor, 2)
+	assert.Equal(t, []string{"e"}, names(rsp))
+	assert.Empty(t, rsp.NextCursor)
+
+	// an exact final page has no next cursor
+	rsp = paginateAdoptableViews(views, "", 5)
+	assert.Len(t, rsp.Views, 5)
+	assert.Empty(t, rsp.NextCursor)
+
+	// a cursor past the last view returns an empty page
+	rsp = paginateAdoptableViews(views, "z", 2)
+	assert.NotNil(t, rsp.Views)
+	assert.Empty(t, rsp.Views)
+	assert.Empty(t, rsp.NextCursor)
+}
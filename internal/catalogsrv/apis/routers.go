@@ -1,8 +1,10 @@
 package apis
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
@@ -112,6 +114,24 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		AllowedActions: []policy.Action{policy.ActionAllow},
 		Options:        []policy.HandlerOptions{policy.SkipViewDefValidation(true)},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/views/validate",
+		Handler:        validateView,
+		AllowedActions: []policy.Action{policy.ActionCatalogCreateView},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/search",
+		Handler:        searchCatalog,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/views/adoptable",
+		Handler:        listAdoptableViews,
+		AllowedActions: []policy.Action{policy.ActionAllow},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/views/{viewName}",
@@ -130,6 +150,30 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        deleteObject,
 		AllowedActions: []policy.Action{policy.ActionViewAdmin},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/views/{viewName}/clone",
+		Handler:        cloneView,
+		AllowedActions: []policy.Action{policy.ActionCatalogCreateView},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/views/{viewName}/revoke-tokens",
+		Handler:        revokeViewTokens,
+		AllowedActions: []policy.Action{policy.ActionViewAdmin},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/views/{viewName}/permissions",
+		Handler:        getViewPermissions,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/views/{viewName}/coverage",
+		Handler:        getViewCoverage,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
 	{
 		Method:         http.MethodPost,
 		Path:           "/resources",
@@ -178,6 +222,12 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        createObject,
 		AllowedActions: []policy.Action{policy.ActionSkillSetCreate},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/skillsets/validate",
+		Handler:        validateSkillSet,
+		AllowedActions: []policy.Action{policy.ActionSkillSetCreate},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/skillsets",
@@ -202,6 +252,125 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        deleteObject,
 		AllowedActions: []policy.Action{policy.ActionSkillSetAdmin},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/skillsets/*",
+		Handler:        skillSetActionNotFound,
+		AllowedActions: []policy.Action{policy.ActionSkillSetAdmin},
+	},
+}
+
+// subResourceHandlers serve sub-resources of objects whose routes end in a wildcard, keyed by
+// the method and path of the object's route. A request whose path ends in the sub-resource suffix
+// is dispatched to its handler with the suffix removed, so that it is authorized against the object.
+var subResourceHandlers = map[string][]subResourceHandler{
+	http.MethodGet + " /skillsets/*": {
+		{
+			Suffix: "/describe",
+			ResponseHandlerParam: policy.ResponseHandlerParam{
+				Handler:        describeSkillSet,
+				AllowedActions: []policy.Action{policy.ActionSkillSetRead, policy.ActionSkillSetUse},
+			},
+		},
+		{
+			Suffix: "/export",
+			ResponseHandlerParam: policy.ResponseHandlerParam{
+				Handler:        exportSkillSet,
+				AllowedActions: []policy.Action{policy.ActionSkillSetRead},
+			},
+		},
+		{
+			Suffix: "/diff",
+			ResponseHandlerParam: policy.ResponseHandlerParam{
+				Handler:        diffSkillSet,
+				AllowedActions: []policy.Action{policy.ActionSkillSetRead},
+			},
+		},
+		{
+			Suffix:      "/transform",
+			SkillScoped: true,
+			ResponseHandlerParam: policy.ResponseHandlerParam{
+				Handler:        getSkillTransform,
+				AllowedActions: []policy.Action{policy.ActionSkillSetRead},
+			},
+		},
+	},
+	http.MethodPost + " /skillsets/*": {
+		{
+			Suffix:      "/rename",
+			SkillScoped: true,
+			ResponseHandlerParam: policy.ResponseHandlerParam{
+				Handler:        renameSkill,
+				AllowedActions: []policy.Action{policy.ActionSkillSetAdmin},
+			},
+		},
+	},
+}
+
+type subResourceHandler struct {
+	Suffix string
+	// SkillScoped sub-resources belong to a skill and are addressed as {object}/skills/{name}{Suffix}.
+	// The skill name is passed to the handler in the request context.
+	SkillScoped bool
+	policy.ResponseHandlerParam
+}
+
+type skillNameCtxKey struct{}
+
+// skillNameFromContext returns the skill name of a skill scoped sub-resource request.
+func skillNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(skillNameCtxKey{}).(string)
+	return name
+}
+
+// cutSkillPath splits a path of the form {object}/skills/{name} into the object path and skill name.
+func cutSkillPath(p string) (objectPath string, skillName string, ok bool) {
+	const marker = "/skills/"
+	i := strings.LastIndex(p, marker)
+	if i <= 0 {
+		return "", "", false
+	}
+	skillName = p[i+len(marker):]
+	if skillName == "" || strings.Contains(skillName, "/") {
+		return "", "", false
+	}
+	return p[:i], skillName, true
+}
+
+// withSubResources dispatches requests for sub-resources to their policy enforced handlers and
+// all other requests to next.
+func withSubResources(next httpx.RequestHandler, subResources []subResourceHandler) httpx.RequestHandler {
+	handlers := make([]httpx.RequestHandler, len(subResources))
+	for i, sub := range subResources {
+		handlers[i] = policy.EnforceViewPolicyMiddleware(sub.ResponseHandlerParam)
+	}
+	return func(r *http.Request) (*httpx.Response, error) {
+		for i, sub := range subResources {
+			objectPath, ok := strings.CutSuffix(r.URL.Path, sub.Suffix)
+			if !ok {
+				continue
+			}
+			ctx := r.Context()
+			if sub.SkillScoped {
+				var skillName string
+				if objectPath, skillName, ok = cutSkillPath(objectPath); !ok {
+					continue
+				}
+				ctx = context.WithValue(ctx, skillNameCtxKey{}, skillName)
+			}
+			r = r.Clone(ctx)
+			r.URL.Path = objectPath
+			r.URL.RawPath = ""
+			return handlers[i](r)
+		}
+		return next(r)
+	}
+}
+
+// skillSetActionNotFound serves POST requests for skillset paths that name no action. Skillsets
+// are created at POST /skillsets; only sub-resource actions are posted to a skillset path.
+func skillSetActionNotFound(r *http.Request) (*httpx.Response, error) {
+	return nil, httpx.ErrPostReqNotSupported()
 }
 
 // Router creates and configures a new router for catalog service API endpoints.
@@ -211,6 +380,7 @@ func Router(r chi.Router) chi.Router {
 	//Load the group that needs only user session/identity validation
 	r.Group(func(r chi.Router) {
 		r.Use(auth.UserAuthMiddleware)
+		r.Use(YAMLMiddleware)
 		r.Use(CatalogContextLoader)
 		for _, handler := range userSessionHandlers {
 			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
@@ -220,10 +390,14 @@ func Router(r chi.Router) chi.Router {
 	//Load the group that needs session validation and catalog context
 	r.Group(func(r chi.Router) {
 		r.Use(auth.ContextMiddleware)
+		r.Use(YAMLMiddleware)
 		r.Use(CatalogContextLoader)
 		for _, handler := range resourceObjectHandlers {
 			//Wrap the request handler with view policy enforcement
 			policyEnforcedHandler := policy.EnforceViewPolicyMiddleware(handler)
+			if subResources, ok := subResourceHandlers[handler.Method+" "+handler.Path]; ok {
+				policyEnforcedHandler = withSubResources(policyEnforcedHandler, subResources)
+			}
 			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(policyEnforcedHandler))
 		}
 	})
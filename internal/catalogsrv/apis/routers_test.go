@@ -0,0 +1,37 @@
+package apis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+)
+
+func TestCutSkillPath(t *testing.T) {
+	objectPath, skillName, ok := cutSkillPath("/skillsets/tools/k8s/skills/list_pods")
+	assert.True(t, ok)
+	assert.Equal(t, "/skillsets/tools/k8s", objectPath)
+	assert.Equal(t, "list_pods", skillName)
+
+	// the last skills segment separates the skill from the skillset path
+	objectPath, skillName, ok = cutSkillPath("/skillsets/skills/k8s/skills/list_pods")
+	assert.True(t, ok)
+	assert.Equal(t, "/skillsets/skills/k8s", objectPath)
+	assert.Equal(t, "list_pods", skillName)
+
+	for _, p := range []string{"/skillsets/k8s", "/skillsets/k8s/skills/", "/skills/list_pods", "/skillsets/k8s/skills/a/b"} {
+		_, _, ok := cutSkillPath(p)
+		assert.False(t, ok, p)
+	}
+}
+
+func TestSkillSetSubResourcesAreReserved(t *testing.T) {
+	// a skillset named like a sub-resource of its parent's path would be shadowed by it
+	for _, handlers := range subResourceHandlers {
+		for _, h := range handlers {
+			name := strings.TrimPrefix(h.Suffix, "/")
+			assert.True(t, catalogmanager.IsReservedSkillSetName(name), name)
+		}
+	}
+}
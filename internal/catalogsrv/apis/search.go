@@ -0,0 +1,185 @@
+package apis
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// Page sizes and query limits for catalog search
+const (
+	DefaultSearchPageSize = 50
+	MaxSearchPageSize     = 500
+	MaxSearchQueryLength  = 256
+)
+
+// searchRsp is a page of search results. NextCursor is set when more results follow
+// and is passed as the cursor query parameter to fetch the next page.
+type searchRsp struct {
+	Results    []*models.SearchResult `json:"results"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
+}
+
+// searchCatalog searches the skillsets and views of the catalog. Skillsets match on their name,
+// description and skill names, and views on their name and description. Every word of the q
+// query parameter must match the start of a word in the object. Objects the caller's view
+// cannot read are left out of the results.
+func searchCatalog(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	q := r.URL.Query().Get("q")
+	if len(q) > MaxSearchQueryLength {
+		return nil, httpx.ErrInvalidRequest("q must be at most " + strconv.Itoa(MaxSearchQueryLength) + " characters")
+	}
+	tsQuery := searchTextQuery(q)
+	if tsQuery == "" {
+		return nil, httpx.ErrInvalidRequest("q must contain at least one letter or digit")
+	}
+
+	limit := DefaultSearchPageSize
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 || n > MaxSearchPageSize {
+			return nil, httpx.ErrInvalidRequest("limit must be between 1 and " + strconv.Itoa(MaxSearchPageSize))
+		}
+		limit = n
+	}
+	after, err := decodeSearchCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		return nil, httpx.ErrInvalidRequest("missing catalog context")
+	}
+	vd := policy.GetViewDefinition(ctx)
+	if vd == nil {
+		return nil, httpx.ErrUnAuthorized("unable to resolve view definition")
+	}
+	catalog := catcommon.GetCatalog(ctx)
+
+	// fetch one more than the page size to learn whether another page follows, and keep
+	// fetching while results the view cannot read leave the page short
+	results := []*models.SearchResult{}
+	for len(results) <= limit {
+		fetched, err := db.DB(ctx).SearchCatalog(ctx, catalogID, tsQuery, after, limit+1)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to search catalog")
+			return nil, err
+		}
+		for _, result := range fetched {
+			if canReadSearchResult(vd, catalog, result) {
+				results = append(results, result)
+			}
+		}
+		if len(fetched) <= limit {
+			break
+		}
+		after = fetched[len(fetched)-1]
+	}
+
+	rsp := &searchRsp{Results: results}
+	if len(results) > limit {
+		rsp.Results = results[:limit]
+		rsp.NextCursor = encodeSearchCursor(rsp.Results[limit-1])
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// canReadSearchResult reports whether the view may read the object of a search result. Views
+// need the same actions as to get the object, and skillsets may be either read or used.
+func canReadSearchResult(vd *policy.ViewDefinition, catalog string, result *models.SearchResult) bool {
+	resource := "res://catalogs/" + catalog
+	actionSets := [][]policy.Action{{policy.ActionCatalogList}}
+	switch result.Kind {
+	case catcommon.ViewKind:
+		resource += "/views/" + result.Name
+	case catcommon.SkillSetKind:
+		resource += "/variants/" + result.Variant
+		if result.Namespace != "" && result.Namespace != catcommon.DefaultNamespace {
+			resource += "/namespaces/" + result.Namespace
+		}
+		resource += "/skillsets/" + strings.TrimPrefix(result.Name, "/")
+		actionSets = [][]policy.Action{{policy.ActionSkillSetRead}, {policy.ActionSkillSetUse}}
+	default:
+		return false
+	}
+	for _, actions := range actionSets {
+		if allowed, _, err := policy.AreActionsAllowedOnResource(vd, resource, actions); err == nil && allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// searchTextQuery converts a free text search term into a postgresql text search query
+// matching objects that contain a word starting with each word of the term. Characters
+// other than letters and digits separate words, so the query never contains operators
+// supplied by the caller. It returns an empty string if the term has no words.
+func searchTextQuery(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// searchCursor is the content of an opaque search cursor. It holds the sort key of the
+// last result the client has seen.
+type searchCursor struct {
+	Kind      string `json:"k"`
+	Variant   string `json:"v,omitempty"`
+	Namespace string `json:"ns,omitempty"`
+	Name      string `json:"n"`
+}
+
+// encodeSearchCursor returns an opaque cursor continuing after result.
+func encodeSearchCursor(result *models.SearchResult) string {
+	data, _ := json.Marshal(searchCursor{
+		Kind:      result.Kind,
+		Variant:   result.Variant,
+		Namespace: result.Namespace,
+		Name:      result.Name,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor returns the result a cursor continues after. An empty cursor starts at the beginning.
+func decodeSearchCursor(cursor string) (*models.SearchResult, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var c searchCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Kind == "" || c.Name == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	return &models.SearchResult{
+		Kind:      c.Kind,
+		Variant:   c.Variant,
+		Namespace: c.Namespace,
+		Name:      c.Name,
+	}, nil
+}
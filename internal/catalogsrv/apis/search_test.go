@@ -0,0 +1,54 @@
+package apis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+)
+
+func TestSearchTextQuery(t *testing.T) {
+	assert.Equal(t, "pods:*", searchTextQuery("pods"))
+	assert.Equal(t, "list:* & pods:*", searchTextQuery("List_Pods"))
+	assert.Equal(t, "k8s:* & tools:*", searchTextQuery("  k8s-tools "))
+	// text search operators are treated as separators
+	assert.Equal(t, "a:* & b:*", searchTextQuery("a | !b & ('"))
+	assert.Empty(t, searchTextQuery(""))
+	assert.Empty(t, searchTextQuery("&|!:*"))
+}
+
+func TestSearchCursor(t *testing.T) {
+	result := &models.SearchResult{Kind: "SkillSet", Variant: "dev", Namespace: "ops", Name: "/tools/k8s", Description: "ignored"}
+	after, err := decodeSearchCursor(encodeSearchCursor(result))
+	require.NoError(t, err)
+	assert.Equal(t, &models.SearchResult{Kind: "SkillSet", Variant: "dev", Namespace: "ops", Name: "/tools/k8s"}, after)
+
+	after, err = decodeSearchCursor("")
+	require.NoError(t, err)
+	assert.Nil(t, after)
+
+	for _, cursor := range []string{"not base64!", "bnVsbA", "e30"} {
+		_, err := decodeSearchCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
+
+func TestCanReadSearchResult(t *testing.T) {
+	vd := &policy.ViewDefinition{
+		Scope: policy.Scope{Catalog: "demo", Variant: "dev"},
+		Rules: policy.Rules{
+			{Intent: policy.IntentAllow, Actions: []policy.Action{policy.ActionSkillSetUse}, Targets: []policy.TargetResource{"res://catalogs/demo/variants/dev/skillsets/tools/*"}},
+			{Intent: policy.IntentAllow, Actions: []policy.Action{policy.ActionCatalogList}, Targets: []policy.TargetResource{"res://catalogs/demo/views/dev-view"}},
+		},
+	}
+
+	assert.True(t, canReadSearchResult(vd, "demo", &models.SearchResult{Kind: catcommon.SkillSetKind, Variant: "dev", Namespace: catcommon.DefaultNamespace, Name: "/tools/k8s"}))
+	assert.False(t, canReadSearchResult(vd, "demo", &models.SearchResult{Kind: catcommon.SkillSetKind, Variant: "dev", Name: "/admin/k8s"}))
+	assert.False(t, canReadSearchResult(vd, "demo", &models.SearchResult{Kind: catcommon.SkillSetKind, Variant: "prod", Name: "/tools/k8s"}))
+	assert.False(t, canReadSearchResult(vd, "demo", &models.SearchResult{Kind: catcommon.SkillSetKind, Variant: "dev", Namespace: "ops", Name: "/tools/k8s"}))
+	assert.True(t, canReadSearchResult(vd, "demo", &models.SearchResult{Kind: catcommon.ViewKind, Name: "dev-view"}))
+	assert.False(t, canReadSearchResult(vd, "demo", &models.SearchResult{Kind: catcommon.ViewKind, Name: "admin-view"}))
+}
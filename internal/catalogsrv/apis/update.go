@@ -1,10 +1,12 @@
 package apis
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/common/httpx"
 )
@@ -48,5 +50,55 @@ func updateObject(r *http.Request) (*httpx.Response, error) {
 		StatusCode: http.StatusOK,
 		Response:   nil,
 	}
+	if reporter, ok := rm.(interfaces.ResultReporter); ok {
+		rsp.Response = reporter.Result()
+	}
 	return rsp, nil
 }
+
+// renameSkillReq is the body of a skill rename request.
+type renameSkillReq struct {
+	Name string `json:"name"`
+}
+
+// renameSkillRsp reports the name and aliases of a renamed skill.
+type renameSkillRsp struct {
+	Skill   string   `json:"skill"`
+	Aliases []string `json:"aliases"`
+}
+
+// renameSkill renames a skill of a skillset to the name in the request body. The old name is
+// kept as an alias of the skill. It is served at POST /skillsets/{path}/skills/{name}/rename.
+func renameSkill(r *http.Request) (*httpx.Response, error) {
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest()
+	}
+	var req renameSkillReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, httpx.ErrUnableToParseReqData()
+	}
+	if req.Name == "" {
+		return nil, httpx.ErrInvalidRequest("new skill name is required")
+	}
+
+	m, err := requestSkillSetMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	skillName := skillNameFromContext(r.Context())
+	sm, apperr := catalogmanager.RenameSkill(r.Context(), m, skillName, req.Name)
+	if apperr != nil {
+		return nil, apperr
+	}
+	skill, apperr := sm.GetSkill(req.Name)
+	if apperr != nil {
+		return nil, apperr
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &renameSkillRsp{
+			Skill:   skill.Name,
+			Aliases: skill.Aliases,
+		},
+	}, nil
+}
@@ -0,0 +1,133 @@
+package apis
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"sigs.k8s.io/yaml"
+)
+
+// ContentTypeYAML is the media type for YAML request and response bodies.
+const ContentTypeYAML = "application/yaml"
+
+// yamlMediaTypes are the media types accepted as YAML.
+var yamlMediaTypes = []string{ContentTypeYAML, "application/x-yaml", "text/yaml", "text/x-yaml"}
+
+func isYAMLMediaType(mediaType string) bool {
+	for _, t := range yamlMediaTypes {
+		if strings.EqualFold(mediaType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestIsYAML reports whether the request body is declared as YAML.
+func requestIsYAML(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && isYAMLMediaType(mediaType)
+}
+
+// acceptsYAML reports whether the client asked for a YAML response.
+func acceptsYAML(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err == nil && isYAMLMediaType(mediaType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// YAMLMiddleware lets clients use YAML with the resource endpoints. YAML request bodies are
+// converted to JSON before they reach the handlers, so they follow the same validation and
+// storage path as JSON. JSON responses are converted to YAML when the client accepts YAML.
+func YAMLMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if r.Body != nil && requestIsYAML(r) {
+			r.Body = http.MaxBytesReader(w, r.Body, config.Config().MaxRequestBodySize)
+			body, err := io.ReadAll(r.Body)
+			_ = r.Body.Close()
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					httpx.ErrRequestTooLarge(maxErr.Limit).Send(w)
+				} else {
+					httpx.ErrUnableToReadRequest().Send(w)
+				}
+				return
+			}
+			jsonBody, err := yaml.YAMLToJSON(body)
+			if err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("failed to convert YAML request")
+				httpx.ErrInvalidRequest("unable to parse YAML request").Send(w)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(jsonBody))
+			r.ContentLength = int64(len(jsonBody))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Content-Length", strconv.Itoa(len(jsonBody)))
+		}
+
+		if !acceptsYAML(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		yw := &yamlResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(yw, r)
+		yw.flush(r)
+	})
+}
+
+// yamlResponseWriter buffers a response so that a JSON body can be re-encoded as YAML.
+type yamlResponseWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *yamlResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *yamlResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buf.Write(b)
+}
+
+// flush writes the buffered response, converting it to YAML if it is JSON.
+func (w *yamlResponseWriter) flush(r *http.Request) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	body := w.buf.Bytes()
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if mediaType == "application/json" && len(body) > 0 {
+		if y, err := yaml.JSONToYAML(body); err == nil {
+			body = y
+			w.Header().Set("Content-Type", ContentTypeYAML)
+		} else {
+			log.Ctx(r.Context()).Error().Err(err).Msg("failed to convert response to YAML")
+		}
+	}
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,67 @@
+package apis
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/common/httpx"
+)
+
+func TestYAMLMiddleware(t *testing.T) {
+	config.TestInit()
+
+	var received []byte
+	var receivedType string
+	handler := YAMLMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		receivedType = r.Header.Get("Content-Type")
+		httpx.SendJsonRsp(r.Context(), w, http.StatusCreated, map[string]any{"kind": "SkillSet", "metadata": map[string]any{"name": "s1"}})
+	}))
+
+	t.Run("YAML request is converted to JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/skillsets", strings.NewReader("kind: SkillSet\nmetadata:\n  name: s1\n"))
+		req.Header.Set("Content-Type", "application/yaml")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.JSONEq(t, `{"kind":"SkillSet","metadata":{"name":"s1"}}`, string(received))
+		assert.Equal(t, "application/json", receivedType)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	})
+
+	t.Run("JSON request is passed through", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/skillsets", strings.NewReader(`{"kind":"SkillSet"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, `{"kind":"SkillSet"}`, string(received))
+	})
+
+	t.Run("malformed YAML is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/skillsets", strings.NewReader("kind: [SkillSet"))
+		req.Header.Set("Content-Type", "application/x-yaml")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("YAML response when accepted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/skillsets/s1", nil)
+		req.Header.Set("Accept", "application/json;q=0.5, application/yaml")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, ContentTypeYAML, rr.Header().Get("Content-Type"))
+		assert.Equal(t, "kind: SkillSet\nmetadata:\n  name: s1\n", rr.Body.String())
+	})
+}
@@ -2,11 +2,14 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
@@ -66,14 +69,28 @@ func adoptView(r *http.Request) (*httpx.Response, error) {
 		return nil, ErrDisallowedByPolicy.Msg("view is not allowed to be adopted")
 	}
 
+	adoptionDepth, err := NextAdoptionDepth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	wantView, err := db.DB(ctx).GetViewByLabel(ctx, viewLabel, catalog.CatalogID)
 	if err != nil {
 		return nil, ErrViewNotFound.Err(err)
 	}
 
+	var wantViewDef policy.ViewDefinition
+	if err := json.Unmarshal(wantView.Rules, &wantViewDef); err != nil {
+		return nil, ErrInvalidViewRules.Msg(err.Error())
+	}
+	if wantViewDef.IsExpired(time.Now()) {
+		return nil, policy.ErrViewExpired.Msg("view " + viewLabel + " has expired and cannot be adopted")
+	}
+
 	token, tokenExpiry, err := CreateAccessToken(ctx,
 		wantView,
 		WithAdditionalClaims(getAccessTokenClaims(ctx)),
+		WithAdoptionDepth(adoptionDepth),
 	)
 	if err != nil {
 		return nil, ErrTokenGeneration.Msg(err.Error())
@@ -88,6 +105,17 @@ func adoptView(r *http.Request) (*httpx.Response, error) {
 	}, nil
 }
 
+// NextAdoptionDepth returns the adoption depth of a view adopted from the current view. Each
+// adoption, whether of a view or by a session, extends the chain of views by one. Returns an
+// error if the chain would exceed the configured maximum depth.
+func NextAdoptionDepth(ctx context.Context) (int, apperrors.Error) {
+	depth := catcommon.GetAdoptionDepth(ctx) + 1
+	if maxDepth := config.Config().Auth.GetMaxViewAdoptionDepthOrDefault(); depth > maxDepth {
+		return 0, ErrAdoptionDepth.Msg(fmt.Sprintf("view adoption depth %d exceeds the maximum of %d", depth, maxDepth))
+	}
+	return depth, nil
+}
+
 // adoptDefaultCatalogView adopts the default view for a catalog.
 func adoptDefaultCatalogView(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,6 +23,8 @@ type TokenOptions struct {
 	ParentView        *policy.ViewDefinition
 	CreateDerivedView bool
 	AdditionalClaims  map[string]any
+	AdoptionDepth     int
+	SessionID         uuid.UUID
 }
 
 // TokenOption is a function that modifies TokenOptions
@@ -51,6 +54,21 @@ func WithAdditionalClaims(claims map[string]any) TokenOption {
 	}
 }
 
+// WithAdoptionDepth sets the number of view adoptions that led to the token's view
+func WithAdoptionDepth(depth int) TokenOption {
+	return func(o *TokenOptions) {
+		o.AdoptionDepth = depth
+	}
+}
+
+// WithSessionID records the session the token is issued to, so that the token can be revoked
+// with the session
+func WithSessionID(id uuid.UUID) TokenOption {
+	return func(o *TokenOptions) {
+		o.SessionID = id
+	}
+}
+
 // CreateDerivedView indicates that a derived view should be created
 func CreateDerivedView() TokenOption {
 	return func(o *TokenOptions) {
@@ -60,15 +78,17 @@ func CreateDerivedView() TokenOption {
 
 // Reserved JWT claims that cannot be overwritten
 var reservedClaims = map[string]bool{
-	"view_id":   true,
-	"tenant_id": true,
-	"iss":       true,
-	"exp":       true,
-	"iat":       true,
-	"nbf":       true,
-	"aud":       true,
-	"jti":       true,
-	"ver":       true,
+	"view_id":        true,
+	"tenant_id":      true,
+	"iss":            true,
+	"exp":            true,
+	"iat":            true,
+	"nbf":            true,
+	"aud":            true,
+	"jti":            true,
+	"ver":            true,
+	"view_epoch":     true,
+	"adoption_depth": true,
 }
 
 // CreateAccessToken creates a new JWT token for the given view
@@ -97,12 +117,18 @@ func CreateAccessToken(ctx context.Context, derivedView *models.View, opts ...To
 		log.Ctx(ctx).Error().Err(goerr).Msg("unable to parse token duration")
 		return "", time.Time{}, ErrUnableToParseTokenDuration.MsgErr("unable to parse token duration", goerr)
 	}
+	tokenDuration, err := viewTokenLifetime(derivedView, tokenDuration, config.Config().Auth.GetMaxTokenAgeOrDefault())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to parse view token lifetime")
+		return "", time.Time{}, err
+	}
 
 	tokenExpiry := time.Now().Add(tokenDuration)
 
 	v := &models.ViewToken{
-		ViewID:   derivedView.ViewID,
-		ExpireAt: tokenExpiry,
+		ViewID:    derivedView.ViewID,
+		SessionID: options.SessionID,
+		ExpireAt:  tokenExpiry,
 	}
 	if err := db.DB(ctx).CreateViewToken(ctx, v); err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("unable to create view token")
@@ -110,6 +136,9 @@ func CreateAccessToken(ctx context.Context, derivedView *models.View, opts ...To
 	}
 
 	claims := createTokenClaims(ctx, derivedView, v, tokenExpiry, options.AdditionalClaims)
+	if options.AdoptionDepth > 0 {
+		claims["adoption_depth"] = options.AdoptionDepth
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
 
 	signingKey, err := keymanager.GetKeyManager().GetActiveKey(ctx)
@@ -129,20 +158,42 @@ func CreateAccessToken(ctx context.Context, derivedView *models.View, opts ...To
 	return tokenString, tokenExpiry, nil
 }
 
+// viewTokenLifetime returns how long a token for the view is valid. The view's tokenTTL replaces
+// defaultTTL if set, and the lifetime is clamped to maxTTL since older tokens are rejected.
+func viewTokenLifetime(view *models.View, defaultTTL, maxTTL time.Duration) (time.Duration, apperrors.Error) {
+	ttl := defaultTTL
+	var viewDef policy.ViewDefinition
+	if err := json.Unmarshal(view.Rules, &viewDef); err != nil {
+		return 0, ErrInvalidViewRules.MsgErr("unable to parse rules of view "+view.Label, err)
+	}
+	if viewDef.TokenTTL != "" {
+		d, goerr := config.ParseDuration(viewDef.TokenTTL)
+		if goerr != nil {
+			return 0, ErrUnableToParseTokenDuration.MsgErr("invalid tokenTTL in view "+view.Label, goerr)
+		}
+		ttl = d
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl, nil
+}
+
 // createTokenClaims creates the JWT claims for the token
 func createTokenClaims(ctx context.Context, view *models.View, token *models.ViewToken, expiry time.Time, additionalClaims map[string]any) jwt.MapClaims {
 	now := time.Now()
 	claims := jwt.MapClaims{
-		"token_use": catcommon.AccessTokenType,
-		"view_id":   view.ViewID.String(),
-		"tenant_id": catcommon.GetTenantID(ctx),
-		"iss":       config.Config().ServerHostName + ":" + config.Config().ServerPort,
-		"exp":       jwt.NewNumericDate(expiry),
-		"iat":       jwt.NewNumericDate(now),
-		"nbf":       jwt.NewNumericDate(now.Add(-2 * time.Minute)), // 2-minute skew buffer
-		"aud":       []string{"tansivesrv"},
-		"jti":       token.TokenID.String(),
-		"ver":       string(catcommon.TokenVersionV0_1),
+		"token_use":  catcommon.AccessTokenType,
+		"view_id":    view.ViewID.String(),
+		"view_epoch": view.TokenEpoch,
+		"tenant_id":  catcommon.GetTenantID(ctx),
+		"iss":        config.Config().ServerHostName + ":" + config.Config().ServerPort,
+		"exp":        jwt.NewNumericDate(expiry),
+		"iat":        jwt.NewNumericDate(now),
+		"nbf":        jwt.NewNumericDate(now.Add(-2 * time.Minute)), // 2-minute skew buffer
+		"aud":        []string{"tansivesrv"},
+		"jti":        token.TokenID.String(),
+		"ver":        string(catcommon.TokenVersionV0_1),
 	}
 
 	for k, v := range additionalClaims {
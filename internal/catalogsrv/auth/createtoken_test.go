@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"errors"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgtype"
@@ -211,6 +212,45 @@ func TestCreateToken(t *testing.T) {
 		require.Equal(t, derivedView.ViewID, storedToken.ViewID)
 	})
 
+	t.Run("view token TTL", func(t *testing.T) {
+		config.Config().Auth.MaxTokenAge = "24h"
+		tokenExp := func(label, tokenTTL string) time.Duration {
+			viewDef := &policy.ViewDefinition{
+				Scope:    policy.Scope{Catalog: "test-catalog"},
+				Rules:    policy.Rules{{Intent: policy.IntentAllow, Actions: []policy.Action{policy.ActionCatalogList}, Targets: []policy.TargetResource{"res://catalogs/test-catalog"}}},
+				TokenTTL: tokenTTL,
+			}
+			viewDefJSON, err := json.Marshal(viewDef)
+			require.NoError(t, err)
+			view := &models.View{
+				Label:     label,
+				Rules:     viewDefJSON,
+				CatalogID: catalogID,
+				TenantID:  catcommon.TenantId("TABCDE"),
+				CreatedBy: "user/test_user",
+				UpdatedBy: "user/test_user",
+			}
+			require.NoError(t, db.DB(ctx).CreateView(ctx, view))
+
+			issued := time.Now()
+			token, expiry, appErr := CreateAccessToken(ctx, view)
+			require.NoError(t, appErr)
+			claims := jwt.MapClaims{}
+			_, _, parseErr := jwt.NewParser().ParseUnverified(token, claims)
+			require.NoError(t, parseErr)
+			exp, parseErr := claims.GetExpirationTime()
+			require.NoError(t, parseErr)
+			assert.WithinDuration(t, expiry, exp.Time, time.Second)
+			return exp.Sub(issued).Round(time.Minute)
+		}
+
+		assert.Equal(t, time.Hour, tokenExp("default-ttl-view", ""))
+		assert.Equal(t, 10*time.Minute, tokenExp("short-ttl-view", "10m"))
+		assert.Equal(t, 12*time.Hour, tokenExp("long-ttl-view", "12h"))
+		// a TTL beyond the maximum token age is clamped
+		assert.Equal(t, 24*time.Hour, tokenExp("excessive-ttl-view", "30d"))
+	})
+
 	t.Run("invalid view", func(t *testing.T) {
 		token, expiry, err := CreateAccessToken(ctx, nil)
 		assert.Error(t, err)
@@ -233,3 +273,35 @@ func TestCreateToken(t *testing.T) {
 	// 	assert.True(t, expiry.IsZero())
 	// })
 }
+
+func TestViewTokenLifetime(t *testing.T) {
+	view := func(rules string) *models.View {
+		return &models.View{Label: "test-view", Rules: []byte(rules)}
+	}
+
+	ttl, err := viewTokenLifetime(view(`{"rules": []}`), time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, ttl)
+
+	ttl, err = viewTokenLifetime(view(`{"rules": [], "tokenTTL": "5m"}`), time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, ttl)
+
+	ttl, err = viewTokenLifetime(view(`{"rules": [], "tokenTTL": "2d"}`), time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, ttl)
+
+	// the default validity is also bounded by the maximum token age
+	ttl, err = viewTokenLifetime(view(`{"rules": []}`), 48*time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, ttl)
+
+	_, err = viewTokenLifetime(view(`{"rules": [], "tokenTTL": "soon"}`), time.Hour, 24*time.Hour)
+	assert.ErrorIs(t, err, ErrUnableToParseTokenDuration)
+
+	_, err = viewTokenLifetime(view(`{"rules": [], "tokenTTL": 5}`), time.Hour, 24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidViewRules)
+
+	_, err = viewTokenLifetime(view(`not json`), time.Hour, 24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidViewRules)
+}
@@ -32,6 +32,7 @@ var (
 	ErrInvalidToken       apperrors.Error = ErrAuth.New("invalid token").SetStatusCode(http.StatusUnauthorized)
 	ErrUnableToParseToken apperrors.Error = ErrAuth.New("unable to parse token").SetStatusCode(http.StatusForbidden)
 	ErrDisallowedByPolicy apperrors.Error = ErrAuth.New("disallowed by policy").SetStatusCode(http.StatusForbidden)
+	ErrAdoptionDepth      apperrors.Error = ErrAuth.New("view adoption depth exceeded").SetStatusCode(http.StatusForbidden)
 )
 
 // Token errors
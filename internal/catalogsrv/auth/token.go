@@ -12,6 +12,7 @@ import (
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive/internal/common/apperrors"
 	"github.com/tansive/tansive/internal/common/uuid"
@@ -163,6 +164,16 @@ func (t *Token) Validate(ctx context.Context) apperrors.Error {
 		return err
 	}
 
+	// Check view token epoch
+	if err := t.validateViewEpoch(ctx); err != nil {
+		return err
+	}
+
+	// Check the token has not been revoked on its own
+	if err := t.validateViewToken(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -324,6 +335,52 @@ func (t *Token) validateJWTID(ctx context.Context) apperrors.Error {
 	return nil
 }
 
+// validateViewEpoch rejects tokens minted before the view's tokens were last revoked.
+// Tokens without a view_epoch claim are treated as epoch 0.
+func (t *Token) validateViewEpoch(ctx context.Context) apperrors.Error {
+	if t.view == nil {
+		return nil
+	}
+	var epoch int64
+	if v, ok := t.claims["view_epoch"]; ok {
+		f, ok := v.(float64)
+		if !ok {
+			log.Ctx(ctx).Debug().Type("type", v).Msg("invalid view_epoch claim type")
+			return ErrInvalidToken.Msg("invalid view_epoch claim type")
+		}
+		epoch = int64(f)
+	}
+	if epoch < t.view.TokenEpoch {
+		log.Ctx(ctx).Debug().Int64("got", epoch).Int64("current", t.view.TokenEpoch).Msg("token revoked by view epoch")
+		return ErrInvalidToken.Msg("token revoked")
+	}
+	return nil
+}
+
+// validateViewToken rejects tokens whose view token record was expired early, such as the tokens
+// of sessions ended by a tangent drain. Tokens without a record are not checked.
+func (t *Token) validateViewToken(ctx context.Context) apperrors.Error {
+	if t.view == nil {
+		return nil
+	}
+	tokenID, goerr := uuid.Parse(t.claims["jti"].(string))
+	if goerr != nil {
+		return ErrInvalidToken.Msg("invalid jti claim")
+	}
+	viewToken, err := db.DB(ctx).GetViewToken(ctx, tokenID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if time.Now().After(viewToken.ExpireAt) {
+		log.Ctx(ctx).Debug().Str("jti", tokenID.String()).Msg("token revoked by view token expiry")
+		return ErrInvalidToken.Msg("token revoked")
+	}
+	return nil
+}
+
 // Get retrieves a claim value from the token
 func (t *Token) Get(key string) (any, bool) {
 	if t.claims == nil {
@@ -399,6 +456,19 @@ func (t *Token) GetUUID(key string) (uuid.UUID, bool) {
 	return id, true
 }
 
+// GetAdoptionDepth returns the number of view adoptions that led to the token's view
+func (t *Token) GetAdoptionDepth() int {
+	depth, ok := t.Get("adoption_depth")
+	if !ok {
+		return 0
+	}
+	d, ok := depth.(float64)
+	if !ok {
+		return 0
+	}
+	return int(d)
+}
+
 // GetViewID returns the view ID associated with the token
 func (t *Token) GetViewID() uuid.UUID {
 	if t.view == nil {
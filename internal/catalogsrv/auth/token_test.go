@@ -11,6 +11,8 @@ import (
 	"github.com/tansive/tansive/internal/catalogsrv/auth/keymanager"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/common/apperrors"
 	"github.com/tansive/tansive/internal/common/uuid"
 )
 
@@ -594,3 +596,75 @@ func TestParseAndValidateToken(t *testing.T) {
 	assert.Equal(t, viewID, parsedToken.view.ViewID)
 	assert.Equal(t, tenantID, parsedToken.view.TenantID)
 }
+
+func TestViewTokenEpoch(t *testing.T) {
+	ctx, _, _, viewID, _, _ := setupTest(t)
+
+	resolve := func(tokenString string) apperrors.Error {
+		_, jwtToken, err := ParseAndValidateToken(ctx, tokenString)
+		require.NoError(t, err)
+		_, err = ResolveAccessToken(ctx, jwtToken)
+		return err
+	}
+
+	view, err := db.DB(ctx).GetView(ctx, viewID)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), view.TokenEpoch)
+
+	oldToken, _, err := CreateAccessToken(ctx, view)
+	require.NoError(t, err)
+	require.NoError(t, resolve(oldToken))
+
+	epoch, err := db.DB(ctx).BumpViewTokenEpoch(ctx, viewID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), epoch)
+
+	// A token minted before the revocation is rejected
+	err = resolve(oldToken)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// A freshly minted token carries the new epoch
+	view, err = db.DB(ctx).GetView(ctx, viewID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), view.TokenEpoch)
+	newToken, _, err := CreateAccessToken(ctx, view)
+	require.NoError(t, err)
+	require.NoError(t, resolve(newToken))
+}
+
+func TestViewAdoptionDepth(t *testing.T) {
+	ctx, _, _, viewID, _, _ := setupTest(t)
+
+	maxDepth := config.Config().Auth.MaxViewAdoptionDepth
+	config.Config().Auth.MaxViewAdoptionDepth = 3
+	defer func() { config.Config().Auth.MaxViewAdoptionDepth = maxDepth }()
+
+	view, err := db.DB(ctx).GetView(ctx, viewID)
+	require.NoError(t, err)
+
+	// adoptDepth validates a token at the given depth and returns the depth of a view adopted with it
+	adoptDepth := func(depth int) (int, apperrors.Error) {
+		token, _, err := CreateAccessToken(ctx, view,
+			WithAdditionalClaims(map[string]any{"adoption_depth": 0}),
+			WithAdoptionDepth(depth),
+		)
+		require.NoError(t, err)
+		tokenCtx, goerr := ValidateToken(ctx, token)
+		require.NoError(t, goerr)
+		assert.Equal(t, depth, catcommon.GetAdoptionDepth(tokenCtx))
+		return NextAdoptionDepth(tokenCtx)
+	}
+
+	for depth := 0; depth < 3; depth++ {
+		next, err := adoptDepth(depth)
+		require.NoError(t, err)
+		assert.Equal(t, depth+1, next)
+	}
+
+	// a chain at the limit cannot be extended
+	_, err = adoptDepth(3)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrAdoptionDepth)
+	assert.Contains(t, err.Error(), "view adoption depth 4 exceeds the maximum of 3")
+}
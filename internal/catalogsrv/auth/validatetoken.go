@@ -18,10 +18,11 @@ import (
 func setCatalogContext(ctx context.Context, viewDef *policy.ViewDefinition, tokenObj *Token) (*catcommon.CatalogContext, apperrors.Error) {
 	_ = ctx
 	catalogContext := &catcommon.CatalogContext{
-		Catalog:   viewDef.Scope.Catalog,
-		Variant:   viewDef.Scope.Variant,
-		Namespace: viewDef.Scope.Namespace,
-		CatalogID: tokenObj.GetCatalogID(),
+		Catalog:       viewDef.Scope.Catalog,
+		Variant:       viewDef.Scope.Variant,
+		Namespace:     viewDef.Scope.Namespace,
+		CatalogID:     tokenObj.GetCatalogID(),
+		AdoptionDepth: tokenObj.GetAdoptionDepth(),
 	}
 
 	sub := tokenObj.GetSubject()
@@ -0,0 +1,133 @@
+package catalogmanager
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// AnnotationSchema declares the annotation keys the skills of a catalog may use. Catalogs opt in
+// by setting spec.annotations; skill annotations are not checked otherwise.
+type AnnotationSchema struct {
+	Keys         []AnnotationKeySchema `json:"keys"`
+	AllowUnknown bool                  `json:"allowUnknown,omitempty"` // whether keys not matched by Keys are allowed
+}
+
+// AnnotationKeySchema declares an annotation key. A key ending in "*" matches every key with
+// that prefix, e.g. "llm:*". Values and Pattern restrict the values of matching annotations.
+type AnnotationKeySchema struct {
+	Key      string   `json:"key"`
+	Required bool     `json:"required,omitempty"` // whether every skill must set the key; not allowed for prefix keys
+	Values   []string `json:"values,omitempty"`   // allowed values; any value is allowed if empty
+	Pattern  string   `json:"pattern,omitempty"`  // regular expression the whole value must match
+}
+
+// isPrefix reports whether the key schema matches keys by prefix.
+func (k *AnnotationKeySchema) isPrefix() bool {
+	return strings.HasSuffix(k.Key, "*")
+}
+
+// matches reports whether the key schema applies to the annotation key.
+func (k *AnnotationKeySchema) matches(key string) bool {
+	if k.isPrefix() {
+		return strings.HasPrefix(key, strings.TrimSuffix(k.Key, "*"))
+	}
+	return k.Key == key
+}
+
+// checkValue checks an annotation value against the restrictions of the key schema.
+func (k *AnnotationKeySchema) checkValue(key, value string) error {
+	if len(k.Values) > 0 && !slices.Contains(k.Values, value) {
+		return fmt.Errorf("annotation %q must be one of %s", key, strings.Join(k.Values, ", "))
+	}
+	if k.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + k.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("annotation %q has an invalid pattern: %v", key, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("annotation %q value %q does not match %s", key, value, k.Pattern)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the schema is well formed.
+func (a *AnnotationSchema) Validate() error {
+	seen := make(map[string]bool, len(a.Keys))
+	for _, k := range a.Keys {
+		if k.Key == "" || k.Key == "*" {
+			return fmt.Errorf("annotation keys must not be empty")
+		}
+		if strings.Contains(strings.TrimSuffix(k.Key, "*"), "*") {
+			return fmt.Errorf("annotation key %q may only end in a wildcard", k.Key)
+		}
+		if seen[k.Key] {
+			return fmt.Errorf("annotation key %q is declared more than once", k.Key)
+		}
+		seen[k.Key] = true
+		if k.Required && k.isPrefix() {
+			return fmt.Errorf("annotation key %q cannot be required", k.Key)
+		}
+		if k.Pattern != "" {
+			if _, err := regexp.Compile(k.Pattern); err != nil {
+				return fmt.Errorf("annotation key %q has an invalid pattern: %v", k.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// keySchema returns the key schema that applies to an annotation key. An exact key takes
+// precedence over prefix keys, and a longer prefix over a shorter one.
+func (a *AnnotationSchema) keySchema(key string) *AnnotationKeySchema {
+	var match *AnnotationKeySchema
+	for i := range a.Keys {
+		k := &a.Keys[i]
+		if !k.matches(key) {
+			continue
+		}
+		if !k.isPrefix() {
+			return k
+		}
+		if match == nil || len(k.Key) > len(match.Key) {
+			match = k
+		}
+	}
+	return match
+}
+
+// CheckAnnotations checks skill annotations against the schema. Returns an error for each
+// missing required key, unknown key and disallowed value.
+func (a *AnnotationSchema) CheckAnnotations(annotations map[string]string) []error {
+	var errs []error
+	for _, k := range a.Keys {
+		if !k.Required {
+			continue
+		}
+		if _, ok := annotations[k.Key]; !ok {
+			errs = append(errs, fmt.Errorf("missing required annotation %q", k.Key))
+		}
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		k := a.keySchema(key)
+		if k == nil {
+			if !a.AllowUnknown {
+				errs = append(errs, fmt.Errorf("annotation %q is not declared by the catalog", key))
+			}
+			continue
+		}
+		if err := k.checkValue(key, annotations[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
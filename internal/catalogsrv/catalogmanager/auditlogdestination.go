@@ -0,0 +1,114 @@
+package catalogmanager
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+)
+
+// Placeholders that may appear in an audit log destination template.
+const (
+	AuditLogPlaceholderTenant  = "{tenant}"
+	AuditLogPlaceholderProject = "{project}"
+	AuditLogPlaceholderCatalog = "{catalog}"
+	AuditLogPlaceholderSession = "{session}"
+)
+
+var (
+	auditLogPlaceholders       = []string{AuditLogPlaceholderTenant, AuditLogPlaceholderProject, AuditLogPlaceholderCatalog, AuditLogPlaceholderSession}
+	auditLogPlaceholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+)
+
+// AuditLogDestinationVars holds the values substituted into an audit log destination template.
+type AuditLogDestinationVars struct {
+	Tenant    string
+	Project   string
+	Catalog   string
+	SessionID string
+}
+
+// ValidateAuditLogDestination checks that template only uses known placeholders and resolves to a
+// supported destination. Supported destinations are object-store prefixes on the local filesystem
+// (file:///path) under audit_log.export_base_dir, and external sinks that accept the log over HTTP
+// (http:// or https://) on a host in audit_log.export_allowed_hosts.
+func ValidateAuditLogDestination(template string) error {
+	for _, p := range auditLogPlaceholderPattern.FindAllString(template, -1) {
+		if !slices.Contains(auditLogPlaceholders, p) {
+			return fmt.Errorf("unknown placeholder %s", p)
+		}
+	}
+	if strings.ContainsAny(auditLogPlaceholderPattern.ReplaceAllString(template, ""), "{}") {
+		return fmt.Errorf("unbalanced braces in template")
+	}
+	_, err := ResolveAuditLogDestination(template, AuditLogDestinationVars{
+		Tenant:    "tenant",
+		Project:   "project",
+		Catalog:   "catalog",
+		SessionID: "session",
+	})
+	return err
+}
+
+// ResolveAuditLogDestination substitutes vars into template and returns the destination URL.
+// The destination must be permitted by the server's audit log configuration.
+func ResolveAuditLogDestination(template string, vars AuditLogDestinationVars) (*url.URL, error) {
+	resolved := strings.NewReplacer(
+		AuditLogPlaceholderTenant, url.PathEscape(vars.Tenant),
+		AuditLogPlaceholderProject, url.PathEscape(vars.Project),
+		AuditLogPlaceholderCatalog, url.PathEscape(vars.Catalog),
+		AuditLogPlaceholderSession, url.PathEscape(vars.SessionID),
+	).Replace(template)
+
+	u, err := url.Parse(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" || !path.IsAbs(u.Path) {
+			return nil, fmt.Errorf("file destination must be an absolute path")
+		}
+		if slices.Contains(strings.Split(u.Path, "/"), "..") {
+			return nil, fmt.Errorf("file destination must not contain relative path elements")
+		}
+		baseDir := config.Config().AuditLog.ExportBaseDir
+		if baseDir == "" {
+			return nil, fmt.Errorf("file destinations are not enabled on this server")
+		}
+		baseDir = path.Clean(baseDir)
+		if p := path.Clean(u.Path); p != baseDir && !strings.HasPrefix(p, strings.TrimSuffix(baseDir, "/")+"/") {
+			return nil, fmt.Errorf("file destination must be under %s", baseDir)
+		}
+	case "http", "https":
+		host := strings.ToLower(u.Hostname())
+		if host == "" {
+			return nil, fmt.Errorf("sink URL must include a host")
+		}
+		if !isAllowedAuditLogHost(host) {
+			return nil, fmt.Errorf("sink host %s is not allowed", host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported destination scheme %q", u.Scheme)
+	}
+	return u, nil
+}
+
+// isAllowedAuditLogHost reports whether host is in the configured allowlist of audit log sinks.
+// An entry of the form "*.example.com" matches any subdomain of example.com.
+func isAllowedAuditLogHost(host string) bool {
+	for _, allowed := range config.Config().AuditLog.ExportAllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok && strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package catalogmanager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+)
+
+// setAuditLogExportConfig sets the file base directory and sink allowlist for the test.
+func setAuditLogExportConfig(t *testing.T, baseDir string, hosts ...string) {
+	t.Helper()
+	config.TestInit()
+	origDir, origHosts := config.Config().AuditLog.ExportBaseDir, config.Config().AuditLog.ExportAllowedHosts
+	t.Cleanup(func() {
+		config.Config().AuditLog.ExportBaseDir = origDir
+		config.Config().AuditLog.ExportAllowedHosts = origHosts
+	})
+	config.Config().AuditLog.ExportBaseDir = baseDir
+	config.Config().AuditLog.ExportAllowedHosts = hosts
+}
+
+func TestValidateAuditLogDestination(t *testing.T) {
+	setAuditLogExportConfig(t, "/var/log/tansive", "logs.example.com", "localhost", "*.sinks.example.com")
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "file prefix", template: "file:///var/log/tansive/{tenant}/{catalog}"},
+		{name: "https sink", template: "https://logs.example.com/ingest/{catalog}?session={session}"},
+		{name: "http sink without placeholders", template: "http://localhost:9000/auditlogs"},
+		{name: "wildcard sink host", template: "https://eu.sinks.example.com/{catalog}"},
+		{name: "unknown placeholder", template: "file:///var/log/tansive/{bucket}", wantErr: true},
+		{name: "unbalanced braces", template: "file:///var/log/tansive/{catalog", wantErr: true},
+		{name: "relative file path", template: "file://logs/{catalog}", wantErr: true},
+		{name: "parent path element", template: "file:///var/log/tansive/../{catalog}", wantErr: true},
+		{name: "file outside base directory", template: "file:///etc/{catalog}", wantErr: true},
+		{name: "file sharing base directory prefix", template: "file:///var/log/tansive-other/{catalog}", wantErr: true},
+		{name: "sink host not allowed", template: "https://attacker.example.net/{catalog}", wantErr: true},
+		{name: "loopback sink not allowed", template: "http://127.0.0.1:8080/{catalog}", wantErr: true},
+		{name: "metadata sink not allowed", template: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "unsupported scheme", template: "ftp://logs.example.com/{catalog}", wantErr: true},
+		{name: "sink without host", template: "https:///{catalog}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAuditLogDestination(tt.template)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuditLogFileDestinationDisabled(t *testing.T) {
+	setAuditLogExportConfig(t, "")
+	assert.Error(t, ValidateAuditLogDestination("file:///logs/{catalog}"))
+}
+
+func TestResolveAuditLogDestination(t *testing.T) {
+	setAuditLogExportConfig(t, "/logs", "sink.example.com")
+	vars := AuditLogDestinationVars{Tenant: "t1", Project: "p1", Catalog: "finance", SessionID: "s1"}
+
+	u, err := ResolveAuditLogDestination("file:///logs/{tenant}/{project}/{catalog}", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "/logs/t1/p1/finance", u.Path)
+
+	u, err = ResolveAuditLogDestination("https://sink.example.com/{catalog}?session={session}", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "sink.example.com", u.Host)
+	assert.Equal(t, "/finance", u.Path)
+	assert.Equal(t, "s1", u.Query().Get("session"))
+}
+
+func TestCatalogSchemaAuditLogDestination(t *testing.T) {
+	setAuditLogExportConfig(t, "/logs")
+	valid := []byte(`{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "Catalog",
+		"metadata": {"name": "finance"},
+		"spec": {"auditLog": {"destination": "file:///logs/{catalog}"}}
+	}`)
+	schema := &catalogSchema{}
+	require.NoError(t, json.Unmarshal(valid, schema))
+	assert.Empty(t, schema.Validate())
+
+	info, err := catalogInfo(schema.Spec)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"auditLog": {"destination": "file:///logs/{catalog}"}}`, string(info.Bytes))
+
+	schema.Spec.AuditLog.Destination = "s3://bucket/{unknown}"
+	errs := schema.Validate()
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "spec.auditLog.destination")
+}
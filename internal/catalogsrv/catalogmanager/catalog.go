@@ -37,6 +37,7 @@ type catalogSchema struct {
 	ApiVersion string          `json:"apiVersion" validate:"required,validateVersion"`
 	Kind       string          `json:"kind" validate:"required,kindValidator"`
 	Metadata   catalogMetadata `json:"metadata" validate:"required"`
+	Spec       *CatalogSpec    `json:"spec,omitempty"`
 }
 
 // catalogMetadata contains metadata about a catalog
@@ -45,6 +46,20 @@ type catalogMetadata struct {
 	Description string `json:"description"`
 }
 
+// CatalogSpec contains catalog-level settings. It is stored in the catalog's info.
+type CatalogSpec struct {
+	AuditLog    *CatalogAuditLogSettings `json:"auditLog,omitempty"`
+	Annotations *AnnotationSchema        `json:"annotations,omitempty"` // schema enforced on the annotations of the catalog's skills
+	// DependencyDefaults are the actions of skillset dependencies that list none
+	DependencyDefaults DependencyDefaults `json:"dependencyDefaults,omitempty"`
+}
+
+// CatalogAuditLogSettings configures where audit logs of the catalog's sessions are shipped.
+// Destination is a template that may use the {tenant}, {project}, {catalog} and {session} placeholders.
+type CatalogAuditLogSettings struct {
+	Destination string `json:"destination,omitempty"`
+}
+
 // catalogManager implements the schemamanager.CatalogManager interface
 type catalogManager struct {
 	catalog models.Catalog
@@ -58,6 +73,21 @@ func (cs *catalogSchema) Validate() schemaerr.ValidationErrors {
 	if cs.Kind != catcommon.CatalogKind {
 		validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind("kind"))
 	}
+	if cs.Spec != nil && cs.Spec.AuditLog != nil && cs.Spec.AuditLog.Destination != "" {
+		if err := ValidateAuditLogDestination(cs.Spec.AuditLog.Destination); err != nil {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.auditLog.destination", err.Error()))
+		}
+	}
+	if cs.Spec != nil && cs.Spec.Annotations != nil {
+		if err := cs.Spec.Annotations.Validate(); err != nil {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.annotations", err.Error()))
+		}
+	}
+	if cs.Spec != nil {
+		if err := cs.Spec.DependencyDefaults.Validate(); err != nil {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.dependencyDefaults", err.Error()))
+		}
+	}
 
 	err := schemavalidator.V().Struct(cs)
 	if err == nil {
@@ -114,11 +144,16 @@ func NewCatalogManager(ctx context.Context, resourceJSON []byte, name string) (C
 		return nil, ErrInvalidSchema.Err(validationErrors)
 	}
 
+	info, err := catalogInfo(schema.Spec)
+	if err != nil {
+		return nil, err
+	}
+
 	catalog := models.Catalog{
 		Name:        schema.Metadata.Name,
 		Description: schema.Metadata.Description,
 		ProjectID:   projectID,
-		Info:        pgtype.JSONB{Status: pgtype.Null},
+		Info:        info,
 	}
 
 	return &catalogManager{
@@ -126,6 +161,44 @@ func NewCatalogManager(ctx context.Context, resourceJSON []byte, name string) (C
 	}, nil
 }
 
+// catalogInfo converts the catalog spec to the info stored with the catalog.
+func catalogInfo(spec *CatalogSpec) (pgtype.JSONB, apperrors.Error) {
+	if spec == nil {
+		return pgtype.JSONB{Status: pgtype.Null}, nil
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return pgtype.JSONB{}, ErrInvalidSchema.Err(err)
+	}
+	return pgtype.JSONB{Bytes: b, Status: pgtype.Present}, nil
+}
+
+// GetCatalogSpec returns the catalog-level settings stored with catalog.
+// Returns nil if the catalog has no settings.
+func GetCatalogSpec(catalog *models.Catalog) (*CatalogSpec, apperrors.Error) {
+	if catalog == nil || catalog.Info.Status != pgtype.Present || len(catalog.Info.Bytes) == 0 {
+		return nil, nil
+	}
+	spec := &CatalogSpec{}
+	if err := json.Unmarshal(catalog.Info.Bytes, spec); err != nil {
+		return nil, ErrUnableToLoadObject.Msg("invalid catalog settings: " + err.Error())
+	}
+	return spec, nil
+}
+
+// loadCatalogSpec loads the settings of the named catalog. Returns nil if the catalog has none.
+func loadCatalogSpec(ctx context.Context, catalog string) (*CatalogSpec, apperrors.Error) {
+	c, err := db.DB(ctx).GetCatalogByName(ctx, catalog)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrCatalogNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load catalog")
+		return nil, err
+	}
+	return GetCatalogSpec(c)
+}
+
 // ID returns the catalog's UUID
 func (cm *catalogManager) ID() uuid.UUID {
 	return cm.catalog.CatalogID
@@ -163,6 +236,9 @@ func (cm *catalogManager) Save(ctx context.Context) apperrors.Error {
 		if errors.Is(err, dberror.ErrAlreadyExists) {
 			return ErrAlreadyExists.New("catalog already exists")
 		}
+		if errors.Is(err, dberror.ErrQuotaExceeded) {
+			return ErrQuotaExceeded.Msg(err.Error())
+		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to create catalog")
 		return err
 	}
@@ -171,6 +247,10 @@ func (cm *catalogManager) Save(ctx context.Context) apperrors.Error {
 
 // ToJson converts the catalog to its JSON representation
 func (cm *catalogManager) ToJson(ctx context.Context) ([]byte, apperrors.Error) {
+	spec, err := GetCatalogSpec(&cm.catalog)
+	if err != nil {
+		return nil, err
+	}
 	schema := catalogSchema{
 		ApiVersion: catcommon.ApiVersion,
 		Kind:       catcommon.CatalogKind,
@@ -178,11 +258,12 @@ func (cm *catalogManager) ToJson(ctx context.Context) ([]byte, apperrors.Error)
 			Name:        cm.catalog.Name,
 			Description: cm.catalog.Description,
 		},
+		Spec: spec,
 	}
 
-	jsonData, err := json.Marshal(schema)
-	if err != nil {
-		log.Ctx(ctx).Error().Err(err).Msg("failed to marshal catalog to JSON")
+	jsonData, goerr := json.Marshal(schema)
+	if goerr != nil {
+		log.Ctx(ctx).Error().Err(goerr).Msg("failed to marshal catalog to JSON")
 		return nil, ErrUnableToLoadObject
 	}
 	return jsonData, nil
@@ -342,6 +423,11 @@ func (c *catalogKind) Update(ctx context.Context, resourceJSON []byte) apperrors
 	}
 
 	catalog.Description = schema.Metadata.Description
+	info, err := catalogInfo(schema.Spec)
+	if err != nil {
+		return err
+	}
+	catalog.Info = info
 
 	err = db.DB(ctx).UpdateCatalog(ctx, catalog)
 	if err != nil {
@@ -0,0 +1,76 @@
+package catalogmanager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/tansive/tansive/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ContextFormatValidator checks that a context value conforms to a declared format.
+// Validators are applied in addition to the context's JSON schema.
+type ContextFormatValidator func(value any) error
+
+var (
+	contextFormatValidatorsMu sync.RWMutex
+	contextFormatValidators   = map[string]ContextFormatValidator{
+		"base64": validateBase64Format,
+		"yaml":   validateYAMLFormat,
+	}
+)
+
+// RegisterContextFormatValidator registers a validator for the given format name.
+// Registering an existing format replaces its validator.
+func RegisterContextFormatValidator(format string, validator ContextFormatValidator) {
+	contextFormatValidatorsMu.Lock()
+	defer contextFormatValidatorsMu.Unlock()
+	contextFormatValidators[format] = validator
+}
+
+func getContextFormatValidator(format string) (ContextFormatValidator, bool) {
+	contextFormatValidatorsMu.RLock()
+	defer contextFormatValidatorsMu.RUnlock()
+	v, ok := contextFormatValidators[format]
+	return v, ok
+}
+
+// validateContextFormat validates a context value against the named format.
+// An empty format accepts any value.
+func validateContextFormat(format string, value types.NullableAny) error {
+	if format == "" {
+		return nil
+	}
+	validator, ok := getContextFormatValidator(format)
+	if !ok {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+	if value.IsNil() {
+		return nil
+	}
+	return validator(value.Get())
+}
+
+func validateBase64Format(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("base64 value must be a string")
+	}
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return fmt.Errorf("invalid base64: %v", err)
+	}
+	return nil
+}
+
+func validateYAMLFormat(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("yaml value must be a string")
+	}
+	var v any
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return fmt.Errorf("invalid yaml: %v", err)
+	}
+	return nil
+}
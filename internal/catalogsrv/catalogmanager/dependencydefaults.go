@@ -0,0 +1,61 @@
+package catalogmanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/internal/common/apperrors"
+)
+
+// DependencyDefaults declares, by dependency kind, the actions of the dependencies of a catalog's
+// skillsets that do not list their own. Catalogs set them in spec.dependencyDefaults, e.g.
+// {"Resource": ["system.resource.read"]}. Actions listed by a dependency always take precedence.
+type DependencyDefaults map[DependencyKind][]policy.Action
+
+// Validate checks that defaults are declared for dependency kinds only and are not empty.
+func (d DependencyDefaults) Validate() error {
+	for kind, actions := range d {
+		if kind != KindSkillSet && kind != KindResource {
+			return fmt.Errorf("unsupported dependency kind %q", kind)
+		}
+		if len(actions) == 0 {
+			return fmt.Errorf("default actions for %s dependencies must not be empty", kind)
+		}
+		for _, action := range actions {
+			if action == "" {
+				return fmt.Errorf("default actions for %s dependencies must not be empty", kind)
+			}
+		}
+	}
+	return nil
+}
+
+// ActionsFor returns the actions of the dependency: its own actions if it lists any, and the
+// default actions of its kind otherwise.
+func (d DependencyDefaults) ActionsFor(dep Dependency) []policy.Action {
+	if len(dep.Actions) > 0 {
+		return dep.Actions
+	}
+	return d[dep.Kind]
+}
+
+// Apply returns the dependencies with the default actions of their kind set on those that list
+// no actions. deps is not modified.
+func (d DependencyDefaults) Apply(deps []Dependency) []Dependency {
+	applied := make([]Dependency, 0, len(deps))
+	for _, dep := range deps {
+		dep.Actions = d.ActionsFor(dep)
+		applied = append(applied, dep)
+	}
+	return applied
+}
+
+// LoadDependencyDefaults returns the dependency defaults of the named catalog, nil if it sets none.
+func LoadDependencyDefaults(ctx context.Context, catalog string) (DependencyDefaults, apperrors.Error) {
+	spec, err := loadCatalogSpec(ctx, catalog)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+	return spec.DependencyDefaults, nil
+}
@@ -32,6 +32,12 @@ var (
 var (
 	ErrAlreadyExists         apperrors.Error = ErrCatalogError.New("object already exists").SetStatusCode(http.StatusConflict)
 	ErrEqualToExistingObject apperrors.Error = ErrCatalogError.New("object is identical to existing object").SetStatusCode(http.StatusConflict)
+	ErrConcurrentUpdate      apperrors.Error = ErrCatalogError.New("object was modified concurrently").SetStatusCode(http.StatusConflict)
+)
+
+// Quota errors
+var (
+	ErrQuotaExceeded apperrors.Error = ErrCatalogError.New("quota exceeded").SetStatusCode(http.StatusForbidden)
 )
 
 // Validation errors
@@ -52,6 +58,9 @@ var (
 	ErrInvalidResourceDefinition apperrors.Error = ErrCatalogError.New("invalid resource definition").SetStatusCode(http.StatusBadRequest)
 	ErrAmbiguousMatch            apperrors.Error = ErrCatalogError.New("ambiguous resource match").SetStatusCode(http.StatusBadRequest)
 	ErrInvalidInput              apperrors.Error = ErrCatalogError.New("invalid input").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidOutput             apperrors.Error = ErrCatalogError.New("invalid output").SetStatusCode(http.StatusUnprocessableEntity)
+	ErrJSONTooDeep               apperrors.Error = ErrInvalidInput.New("JSON document is nested too deeply").SetStatusCode(http.StatusBadRequest)
+	ErrMissingDependency         apperrors.Error = ErrCatalogError.New("missing dependency").SetStatusCode(http.StatusUnprocessableEntity)
 )
 
 // Schema validation errors
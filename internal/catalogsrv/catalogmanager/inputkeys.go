@@ -0,0 +1,88 @@
+package catalogmanager
+
+import (
+	"strings"
+	"unicode"
+)
+
+// InputKeyStyle is the case convention that top-level skill input keys are normalized to.
+type InputKeyStyle string
+
+const (
+	InputKeyStyleCamel InputKeyStyle = "camel"
+	InputKeyStyleSnake InputKeyStyle = "snake"
+)
+
+// NormalizeInput rewrites the top-level keys of input to the skill's declared input key style.
+// Nested objects are left untouched. If a key and its normalized form are both present, the
+// value of the key already in the declared style is kept. Returns input unchanged if the skill
+// does not declare a style.
+func (s *Skill) NormalizeInput(input map[string]any) map[string]any {
+	var convert func(string) string
+	switch s.InputKeyStyle {
+	case InputKeyStyleCamel:
+		convert = toCamelCase
+	case InputKeyStyleSnake:
+		convert = toSnakeCase
+	default:
+		return input
+	}
+	if input == nil {
+		return nil
+	}
+
+	normalized := make(map[string]any, len(input))
+	for k, v := range input {
+		if convert(k) == k {
+			normalized[k] = v
+		}
+	}
+	for k, v := range input {
+		key := convert(k)
+		if _, exists := normalized[key]; !exists {
+			normalized[key] = v
+		}
+	}
+	return normalized
+}
+
+// toSnakeCase converts a camelCase or PascalCase key to snake_case.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toCamelCase converts a snake_case key to camelCase. Leading underscores are preserved.
+func toCamelCase(s string) string {
+	trimmed := strings.TrimLeft(s, "_")
+	prefix := s[:len(s)-len(trimmed)]
+	parts := strings.Split(trimmed, "_")
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i == 0 {
+			b.WriteString(part)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package catalogmanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// RedactedInputValue replaces the value of a redacted input argument.
+const RedactedInputValue = "***"
+
+// parseJSONPointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%q is not a JSON pointer", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// validateRedactInputPaths validates that each of the skill's redacted input paths is a JSON
+// pointer to a value the input schema allows.
+func (s *Skill) validateRedactInputPaths() error {
+	if len(s.RedactInputPaths) == 0 {
+		return nil
+	}
+	if len(s.InputSchema) == 0 || string(s.InputSchema) == "null" {
+		return fmt.Errorf("redactInputPaths requires an input schema")
+	}
+	schema, err := compileSchemaWithDraft(string(s.InputSchema), s.schemaDraft)
+	if err != nil {
+		return err
+	}
+	for _, pointer := range s.RedactInputPaths {
+		tokens, err := parseJSONPointer(pointer)
+		if err != nil {
+			return err
+		}
+		if !schemaHasPath(schema, tokens) {
+			return fmt.Errorf("%s does not refer to a value of the input schema", pointer)
+		}
+	}
+	return nil
+}
+
+// schemaHasPath reports whether the schema allows a value at the path given by tokens.
+func schemaHasPath(schema *jsonschema.Schema, tokens []string) bool {
+	if schema == nil {
+		return false
+	}
+	if len(tokens) == 0 {
+		return true
+	}
+	if schema.Ref != nil && schemaHasPath(schema.Ref, tokens) {
+		return true
+	}
+	for _, sub := range append(append(append([]*jsonschema.Schema{}, schema.AllOf...), schema.AnyOf...), schema.OneOf...) {
+		if schemaHasPath(sub, tokens) {
+			return true
+		}
+	}
+
+	token, rest := tokens[0], tokens[1:]
+	if prop, ok := schema.Properties[token]; ok {
+		return schemaHasPath(prop, rest)
+	}
+	if additional, ok := schema.AdditionalProperties.(*jsonschema.Schema); ok {
+		return schemaHasPath(additional, rest)
+	}
+	if _, err := strconv.Atoi(token); err == nil {
+		if schema.Items2020 != nil {
+			return schemaHasPath(schema.Items2020, rest)
+		}
+		if items, ok := schema.Items.(*jsonschema.Schema); ok {
+			return schemaHasPath(items, rest)
+		}
+	}
+	return false
+}
+
+// RedactInput returns a copy of input with the values at the skill's redacted input paths
+// replaced with RedactedInputValue. Top-level keys are first normalized to the skill's input key
+// style, since the paths refer to the normalized input. The input itself is not modified. Paths
+// that are not present in input are ignored.
+func (s *Skill) RedactInput(input map[string]any) map[string]any {
+	if len(s.RedactInputPaths) == 0 || input == nil {
+		return input
+	}
+	var redacted any = copyInputMap(s.NormalizeInput(input))
+	for _, pointer := range s.RedactInputPaths {
+		tokens, err := parseJSONPointer(pointer)
+		if err != nil {
+			continue
+		}
+		redactPath(redacted, tokens)
+	}
+	return redacted.(map[string]any)
+}
+
+// redactPath replaces the value at the path given by tokens, copying the maps and slices along
+// the path so that values shared with the original input are not modified.
+func redactPath(value any, tokens []string) {
+	token, rest := tokens[0], tokens[1:]
+	switch v := value.(type) {
+	case map[string]any:
+		child, ok := v[token]
+		if !ok {
+			return
+		}
+		if len(rest) == 0 {
+			v[token] = RedactedInputValue
+			return
+		}
+		v[token] = copyInputValue(child)
+		redactPath(v[token], rest)
+	case []any:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(v) {
+			return
+		}
+		if len(rest) == 0 {
+			v[i] = RedactedInputValue
+			return
+		}
+		v[i] = copyInputValue(v[i])
+		redactPath(v[i], rest)
+	}
+}
+
+// copyInputValue returns a shallow copy of maps and slices and any other value as is.
+func copyInputValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return copyInputMap(v)
+	case []any:
+		return append([]any(nil), v...)
+	}
+	return value
+}
+
+func copyInputMap(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
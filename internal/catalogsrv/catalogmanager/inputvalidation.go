@@ -0,0 +1,17 @@
+package catalogmanager
+
+// InputValidationMode sets how a skill handles input that does not conform to its input schema.
+type InputValidationMode string
+
+const (
+	// InputValidationReject fails invocations whose input is invalid. It is the default.
+	InputValidationReject InputValidationMode = "reject"
+	// InputValidationWarn reports invalid input but runs the skill with it, so that callers keep
+	// working while they migrate to a changed input schema.
+	InputValidationWarn InputValidationMode = "warn"
+)
+
+// WarnOnInvalidInput reports whether the skill runs with input that fails validation.
+func (s *Skill) WarnOnInvalidInput() bool {
+	return s.InputValidation == InputValidationWarn
+}
@@ -18,6 +18,12 @@ type KindHandler interface {
 	Location() string
 }
 
+// ResultReporter is implemented by kind handlers that report details of a Create or Update.
+// A non-nil Result is returned as the response body.
+type ResultReporter interface {
+	Result() any
+}
+
 type RequestContext struct {
 	Catalog        string
 	CatalogID      uuid.UUID
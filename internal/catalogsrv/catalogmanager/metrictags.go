@@ -0,0 +1,44 @@
+package catalogmanager
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxSkillMetricTags bounds the number of metric tags a skill may declare, keeping the
+	// cardinality of skill metrics in check.
+	MaxSkillMetricTags = 8
+	// maxMetricTagValueLength is the maximum length of a metric tag value.
+	maxMetricTagValueLength = 128
+)
+
+var metricTagKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,62}$`)
+
+// reservedMetricTags are the labels set by Tansive on skill metrics, which a skill may not override.
+var reservedMetricTags = []string{"skill", "status", "runner"}
+
+// validateMetricTags validates the metric tags declared by the skill. Keys must be valid metric
+// label names and may not be one of the labels reserved by Tansive.
+func (s *Skill) validateMetricTags() error {
+	if len(s.MetricTags) > MaxSkillMetricTags {
+		return fmt.Errorf("at most %d metric tags may be declared, got %d", MaxSkillMetricTags, len(s.MetricTags))
+	}
+	for key, value := range s.MetricTags {
+		if !metricTagKeyPattern.MatchString(key) || strings.HasPrefix(key, "__") {
+			return fmt.Errorf("invalid metric tag key %q", key)
+		}
+		if slices.Contains(reservedMetricTags, key) {
+			return fmt.Errorf("metric tag key %q is reserved", key)
+		}
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("metric tag %q must have a value", key)
+		}
+		if len(value) > maxMetricTagValueLength {
+			return fmt.Errorf("metric tag %q value exceeds %d characters", key, maxMetricTagValueLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package catalogmanager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/common/apperrors"
+)
+
+// TenantQuota returns the resource quota of the tenant in the context.
+func TenantQuota(ctx context.Context) config.TenantQuota {
+	return config.Config().Quotas.ForTenant(string(catcommon.GetTenantID(ctx)))
+}
+
+// CheckQuota returns ErrQuotaExceeded if the tenant already owns limit objects of the named
+// kind, as reported by count. A zero limit is unlimited and count is not called. It is used for
+// quotas on objects that end on their own, such as sessions that expire. Catalogs, variants
+// and skillsets are counted by the database when they are stored.
+func CheckQuota(ctx context.Context, kind string, limit int, count func(context.Context) (int, apperrors.Error)) apperrors.Error {
+	if limit <= 0 {
+		return nil
+	}
+	n, err := count(ctx)
+	if err != nil {
+		return err
+	}
+	if n >= limit {
+		return ErrQuotaExceeded.Msg(fmt.Sprintf("tenant quota of %d %s reached", limit, kind))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package catalogmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/pkg/types"
+)
+
+func TestCheckQuota(t *testing.T) {
+	ctx := context.Background()
+	counted := false
+	count := func(n int) func(context.Context) (int, apperrors.Error) {
+		return func(context.Context) (int, apperrors.Error) {
+			counted = true
+			return n, nil
+		}
+	}
+
+	assert.NoError(t, CheckQuota(ctx, "skillsets", 0, count(100)))
+	assert.False(t, counted, "an unlimited quota should not be counted")
+
+	assert.NoError(t, CheckQuota(ctx, "skillsets", 3, count(2)))
+	assert.True(t, counted)
+
+	err := CheckQuota(ctx, "skillsets", 3, count(3))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+
+	quotas := config.QuotaConfig{
+		Default: config.TenantQuota{MaxSkillSets: 10},
+		Tenants: map[string]config.TenantQuota{"TSMALL": {MaxSkillSets: 1}},
+	}
+	assert.Equal(t, 1, quotas.ForTenant("TSMALL").MaxSkillSets)
+	assert.Equal(t, 10, quotas.ForTenant("TOTHER").MaxSkillSets)
+}
+
+func TestSkillSetQuota(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	quotas := config.Config().Quotas
+	config.Config().Quotas = config.QuotaConfig{
+		Tenants: map[string]config.TenantQuota{"TQUOTA": {MaxSkillSets: 2}},
+	}
+	defer func() { config.Config().Quotas = quotas }()
+
+	setupTenant := func(tenantID catcommon.TenantId) context.Context {
+		ctx := catcommon.WithTenantID(ctx, tenantID)
+		ctx = catcommon.WithProjectID(ctx, "P12345")
+		require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+		t.Cleanup(func() { db.DB(ctx).DeleteTenant(ctx, tenantID) })
+		require.NoError(t, db.DB(ctx).CreateProject(ctx, "P12345"))
+
+		var info pgtype.JSONB
+		require.NoError(t, info.Set(`{}`))
+		catalog := models.Catalog{Name: "test-catalog", Info: info}
+		require.NoError(t, db.DB(ctx).CreateCatalog(ctx, &catalog))
+		ctx = catcommon.WithCatalogID(ctx, catalog.CatalogID)
+
+		variant := models.Variant{Name: "test-variant", CatalogID: catalog.CatalogID, Info: info}
+		require.NoError(t, db.DB(ctx).CreateVariant(ctx, &variant))
+		ctx = catcommon.WithVariantID(ctx, variant.VariantID)
+		return catcommon.WithVariant(ctx, variant.Name)
+	}
+	saveSkillSet := func(ctx context.Context, name string) apperrors.Error {
+		sm, err := NewSkillSetManager(ctx, []byte(fmt.Sprintf(`{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "SkillSet",
+			"metadata": {
+				"name": "%s",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"path": "/"
+			},
+			"spec": {
+				"version": "1.0.0",
+				"sources": [
+					{
+						"name": "command-runner",
+						"runner": "system.commandrunner",
+						"config": {
+							"command": "python3 test.py"
+						}
+					}
+				],
+				"skills": [
+					{
+						"name": "run",
+						"description": "Run the command",
+						"source": "command-runner",
+						"inputSchema": {"type": "object"},
+						"outputSchema": {"type": "object"},
+						"exportedActions": ["system.skillset.use"]
+					}
+				]
+			}
+		}`, name)), nil)
+		require.NoError(t, err)
+		return sm.Save(ctx)
+	}
+
+	limited := setupTenant("TQUOTA")
+	other := setupTenant("TOTHER")
+
+	require.NoError(t, saveSkillSet(limited, "first"))
+	require.NoError(t, saveSkillSet(limited, "second"))
+
+	err := saveSkillSet(limited, "third")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+
+	// replacing an existing skillset does not add to the count
+	assert.NoError(t, saveSkillSet(limited, "second"))
+
+	// other tenants are unaffected
+	for _, name := range []string{"first", "second", "third"} {
+		assert.NoError(t, saveSkillSet(other, name))
+	}
+
+	// deleting a skillset releases its place in the quota
+	require.NoError(t, DeleteSkillSet(limited, &interfaces.Metadata{
+		Name:    "first",
+		Catalog: "test-catalog",
+		Variant: types.NullableStringFrom("test-variant"),
+		Path:    "/",
+	}))
+	assert.NoError(t, saveSkillSet(limited, "third"))
+}
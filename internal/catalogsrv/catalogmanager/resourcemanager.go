@@ -9,6 +9,7 @@ import (
 	"path"
 	"reflect"
 	"slices"
+	"strings"
 
 	"encoding/json"
 
@@ -148,23 +149,34 @@ func (r *Resource) ValidateValue(value types.NullableAny, optsCompiledSchema ...
 	return compiledSchema.Validate(value.Get())
 }
 
+// AssertFormatKeyword is the schema keyword that sets whether a schema asserts the format keyword.
+// By default string values must be well-formed for their format (e.g. email, uuid, date-time,
+// uri), unless the schema declares draft 2019-09 or later, where the format keyword is an
+// annotation. When the root of a schema sets this keyword, it overrides the default of its draft.
+const AssertFormatKeyword = "x-assertFormat"
+
+// annotativeFormat accepts any value, treating the format keyword as an annotation.
+func annotativeFormat(any) bool { return true }
+
 // compileSchema compiles a JSON schema string into a jsonschema.Schema.
 // It validates the schema is valid JSON and handles self-referential schemas.
 func compileSchema(schema string) (*jsonschema.Schema, error) {
+	return compileSchemaWithDraft(schema, "")
+}
+
+// compileSchemaWithDraft compiles a JSON schema string as compileSchema does, using draft for
+// schemas that do not declare one with $schema.
+func compileSchemaWithDraft(schema string, draft SchemaDraft) (*jsonschema.Schema, error) {
 	// First validate that the schema is valid JSON using gjson
 	if !gjson.Valid(schema) {
 		return nil, fmt.Errorf("invalid JSON schema")
 	}
 
-	compiler := jsonschema.NewCompiler()
-	// Allow schemas with $id to refer to themselves
-	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
-		if url == "inline://schema" {
-			return io.NopCloser(bytes.NewReader([]byte(schema))), nil
-		}
-		return nil, fmt.Errorf("unsupported schema ref: %s", url)
+	compiler, err := newSchemaCompiler(schema, draft)
+	if err != nil {
+		return nil, err
 	}
-	err := compiler.AddResource("inline://schema", bytes.NewReader([]byte(schema)))
+	err = compiler.AddResource("inline://schema", bytes.NewReader([]byte(schema)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add schema resource: %w", err)
 	}
@@ -176,6 +188,57 @@ func compileSchema(schema string) (*jsonschema.Schema, error) {
 	return compiledSchema, nil
 }
 
+// newSchemaCompiler returns a compiler dedicated to schema, configured for its draft and for the
+// format assertion it asks for with AssertFormatKeyword. Without the keyword, the compiler keeps
+// the format assertion of the draft.
+func newSchemaCompiler(schema string, draft SchemaDraft) (*jsonschema.Compiler, error) {
+	compiler := jsonschema.NewCompiler()
+	jsDraft, err := draft.jsonschemaDraft()
+	if err != nil {
+		return nil, err
+	}
+	if jsDraft != nil {
+		compiler.Draft = jsDraft
+	}
+	assertFormat := gjson.Get(schema, AssertFormatKeyword)
+	if assertFormat.Exists() && !assertFormat.IsBool() {
+		return nil, fmt.Errorf("%s must be a boolean", AssertFormatKeyword)
+	}
+	if assertFormat.Bool() {
+		compiler.AssertFormat = true
+	} else if assertFormat.Exists() && assertsFormatByDefault(schema) {
+		// Known formats are overridden to accept any value in this compiler only, so that
+		// other schemas and the package defaults keep their format checks.
+		for name := range jsonschema.Formats {
+			compiler.Formats[name] = annotativeFormat
+		}
+	}
+	// Allow schemas with $id to refer to themselves
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		if url == "inline://schema" {
+			return io.NopCloser(bytes.NewReader([]byte(schema))), nil
+		}
+		return nil, fmt.Errorf("unsupported schema ref: %s", url)
+	}
+	return compiler, nil
+}
+
+// assertsFormatByDefault reports whether the compiler asserts the format keyword of schema
+// without AssertFormat being set. This is the case for drafts before 2019-09, and for schemas
+// without a $schema keyword, which the compiler compiles without a meta-schema vocabulary.
+func assertsFormatByDefault(schema string) bool {
+	declared := gjson.Get(schema, "$schema")
+	if !declared.Exists() {
+		return true
+	}
+	for _, d := range []SchemaDraft{SchemaDraft2019, SchemaDraft2020} {
+		if strings.Contains(declared.String(), string(d)) {
+			return false
+		}
+	}
+	return true
+}
+
 // resourceManager implements the ResourceManager interface for managing a single resource.
 type resourceManager struct {
 	resource Resource
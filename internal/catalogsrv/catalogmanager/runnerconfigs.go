@@ -0,0 +1,150 @@
+package catalogmanager
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	schemaerr "github.com/tansive/tansive/internal/catalogsrv/schema/errors"
+)
+
+// processLimitsProperties are the working directory and resource limit settings shared by
+// runners that spawn a process. Limits of 0 mean no limit.
+const processLimitsProperties = `
+	"workingDir": {"type": "string", "pattern": "^/"},
+	"cpuLimit": {"type": "integer", "minimum": 0},
+	"memoryLimitMB": {"type": "integer", "minimum": 0},
+	"niceness": {"type": "integer", "minimum": 0, "maximum": 19}`
+
+// builtinRunnerConfigSchemas are the config schemas of the runners known to the catalog.
+// Runners without a registered schema accept any config.
+var builtinRunnerConfigSchemas = map[catcommon.RunnerID]string{
+	catcommon.StdioRunnerID: `{
+		"type": "object",
+		"required": ["version", "runtime", "script"],
+		"properties": {
+			"version": {"type": "string", "minLength": 1},
+			"runtime": {"enum": ["bash", "python", "node", "npx", "npm", "binary"]},
+			"runtimeConfig": {"type": ["object", "null"]},
+			"env": {"type": ["object", "null"]},
+			"script": {"type": "string", "minLength": 1},
+			"security": {
+				"type": ["object", "null"],
+				"properties": {
+					"type": {"enum": ["", "default", "sandboxed"]}
+				}
+			},
+			` + processLimitsProperties + `
+		}
+	}`,
+	catcommon.CommandRunnerID: `{
+		"type": "object",
+		"required": ["command"],
+		"properties": {
+			"command": {"type": "string", "minLength": 1},
+			` + processLimitsProperties + `
+		}
+	}`,
+	catcommon.PythonRunnerID: `{
+		"type": "object",
+		"properties": {
+			"module": {"type": "string", "minLength": 1},
+			"function": {"type": "string", "minLength": 1},
+			"script": {"type": "string", "minLength": 1}
+		},
+		"oneOf": [
+			{"required": ["module", "function"], "not": {"required": ["script"]}},
+			{"required": ["script"], "not": {"anyOf": [{"required": ["module"]}, {"required": ["function"]}]}}
+		]
+	}`,
+}
+
+var (
+	runnerConfigSchemasMu sync.RWMutex
+	runnerConfigSchemas   = mustCompileRunnerConfigSchemas(builtinRunnerConfigSchemas)
+)
+
+func mustCompileRunnerConfigSchemas(schemas map[catcommon.RunnerID]string) map[catcommon.RunnerID]*jsonschema.Schema {
+	compiled := make(map[catcommon.RunnerID]*jsonschema.Schema, len(schemas))
+	for runner, schema := range schemas {
+		s, err := compileSchema(schema)
+		if err != nil {
+			panic(fmt.Sprintf("invalid config schema for runner %s: %v", runner, err))
+		}
+		compiled[runner] = s
+	}
+	return compiled
+}
+
+// RegisterRunnerConfigSchema registers the JSON schema that the config of a skillset source
+// using the given runner must satisfy. Registering an existing runner replaces its schema.
+func RegisterRunnerConfigSchema(runner catcommon.RunnerID, schema string) error {
+	compiled, err := compileSchema(schema)
+	if err != nil {
+		return fmt.Errorf("invalid config schema for runner %s: %w", runner, err)
+	}
+	runnerConfigSchemasMu.Lock()
+	defer runnerConfigSchemasMu.Unlock()
+	runnerConfigSchemas[runner] = compiled
+	return nil
+}
+
+func getRunnerConfigSchema(runner catcommon.RunnerID) (*jsonschema.Schema, bool) {
+	runnerConfigSchemasMu.RLock()
+	defer runnerConfigSchemasMu.RUnlock()
+	s, ok := runnerConfigSchemas[runner]
+	return s, ok
+}
+
+// validateRunnerConfig validates a source's config against the schema registered for its runner.
+// field is the path of the config in the skillset and prefixes the field of each returned error.
+func validateRunnerConfig(runner catcommon.RunnerID, config map[string]any, field string) schemaerr.ValidationErrors {
+	schema, ok := getRunnerConfigSchema(runner)
+	if !ok {
+		return nil
+	}
+	var value any = map[string]any{}
+	if config != nil {
+		value = config
+	}
+	err := schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return schemaerr.ValidationErrors{schemaerr.ErrInvalidValue(field, err.Error())}
+	}
+	var validationErrors schemaerr.ValidationErrors
+	for _, leaf := range leafValidationErrors(ve) {
+		leafField := field + strings.ReplaceAll(leaf.InstanceLocation, "/", ".")
+		if missing := missingPropertiesPattern.FindStringSubmatch(leaf.Message); missing != nil {
+			for _, name := range quotedNamePattern.FindAllStringSubmatch(missing[1], -1) {
+				validationErrors = append(validationErrors, schemaerr.ErrMissingRequiredAttribute(leafField+"."+name[1]))
+			}
+			continue
+		}
+		validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(leafField, leaf.Message))
+	}
+	return validationErrors
+}
+
+var (
+	missingPropertiesPattern = regexp.MustCompile(`^missing properties?: (.+)$`)
+	quotedNamePattern        = regexp.MustCompile(`'([^']*)'`)
+)
+
+// leafValidationErrors returns the innermost causes of a schema validation error.
+func leafValidationErrors(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range ve.Causes {
+		leaves = append(leaves, leafValidationErrors(cause)...)
+	}
+	return leaves
+}
@@ -0,0 +1,59 @@
+package catalogmanager
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// SchemaDraft names the JSON Schema draft that a skillset's schemas are written against.
+// Schemas that declare a draft with the $schema keyword are compiled with the draft they declare.
+type SchemaDraft string
+
+const (
+	SchemaDraft4    SchemaDraft = "draft-04"
+	SchemaDraft6    SchemaDraft = "draft-06"
+	SchemaDraft7    SchemaDraft = "draft-07"
+	SchemaDraft2019 SchemaDraft = "2019-09"
+	SchemaDraft2020 SchemaDraft = "2020-12"
+)
+
+var schemaDrafts = map[SchemaDraft]*jsonschema.Draft{
+	SchemaDraft4:    jsonschema.Draft4,
+	SchemaDraft6:    jsonschema.Draft6,
+	SchemaDraft7:    jsonschema.Draft7,
+	SchemaDraft2019: jsonschema.Draft2019,
+	SchemaDraft2020: jsonschema.Draft2020,
+}
+
+// jsonschemaDraft returns the compiler draft for d. An empty draft selects the compiler's default.
+func (d SchemaDraft) jsonschemaDraft() (*jsonschema.Draft, error) {
+	if d == "" {
+		return nil, nil
+	}
+	draft, ok := schemaDrafts[d]
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema draft %q, expected one of %s, %s, %s, %s or %s",
+			string(d), SchemaDraft4, SchemaDraft6, SchemaDraft7, SchemaDraft2019, SchemaDraft2020)
+	}
+	return draft, nil
+}
+
+// UnmarshalJSON decodes the spec and binds the skillset's schema draft to each of its skills, so
+// that skill schemas are compiled with the draft wherever the skill is used.
+func (s *SkillSetSpec) UnmarshalJSON(data []byte) error {
+	type skillSetSpec SkillSetSpec
+	if err := json.Unmarshal(data, (*skillSetSpec)(s)); err != nil {
+		return err
+	}
+	s.bindSchemaDraft()
+	return nil
+}
+
+// bindSchemaDraft sets the schema draft of each skill to the draft of the spec.
+func (s *SkillSetSpec) bindSchemaDraft() {
+	for i := range s.Skills {
+		s.Skills[i].schemaDraft = s.SchemaDraft
+	}
+}
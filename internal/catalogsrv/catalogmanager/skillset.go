@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"slices"
+	"strings"
 
 	"encoding/json"
 
@@ -37,21 +39,64 @@ type SkillSetManager interface {
 	GetStoragePath() string
 	StorageRepresentation() *objectstore.ObjectStorageRepresentation
 	GetSkillMetadata() (SkillMetadata, apperrors.Error)
+	Describe() SkillSetDescription
 	GetResourcePath() string
 	GetSourceForSkill(skillName string) (SkillSetSource, apperrors.Error)
 	GetSourceByName(sourceName string) (SkillSetSource, apperrors.Error)
+	ApplySourceOverrides(source SkillSetSource, environment string) SkillSetSource
 	GetSkill(name string) (Skill, apperrors.Error)
 	GetAllSkills() []Skill
-	GetAllSkillsAsLLMTools(viewDef *policy.ViewDefinition) []api.LLMTool
+	ResolveDependencies(sessionVariables map[string]any) []Dependency
+	GetAllSkillsAsLLMTools(viewDef *policy.ViewDefinition, category string) []api.LLMTool
 	GetContext(name string) (SkillSetContext, apperrors.Error)
 	GetContextValue(name string, viewDef ...*policy.ViewDefinition) (types.NullableAny, apperrors.Error)
+	GetContextInputArgs(name string, viewDef *policy.ViewDefinition) (map[string]any, apperrors.Error)
 	SetContextValue(name string, value types.NullableAny) apperrors.Error
+	ValidateContextOverrides(overrides map[string]any) apperrors.Error
+	ApplyContextOverrides(overrides map[string]any) apperrors.Error
 	GetRunnerTypes() []catcommon.RunnerID
+	GetPreflightSkill() string
 	ValidateInputForSkill(ctx context.Context, skillName string, input map[string]any) apperrors.Error
 }
 
 // NewSkillSetManager creates a new Sk sillSetManager instance from the pro vided JSON schema and metadata.
 func NewSkillSetManager(ctx context.Context, rsrcJSON []byte, m *interfaces.Metadata) (SkillSetManager, apperrors.Error) {
+	skillset, err := ParseSkillSet(ctx, rsrcJSON, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if validationErrs := skillset.Validate(); validationErrs != nil {
+		log.Ctx(ctx).Error().Err(validationErrs).Msg("Skillset validation failed")
+		return nil, ErrSchemaValidation.Msg(validationErrs.Error())
+	}
+
+	return &skillSetManager{skillSet: *skillset}, nil
+}
+
+// newCatalogSkillSetManager creates a skillset manager like NewSkillSetManager, additionally
+// enforcing the settings of the skillset's catalog. It is used when skillsets are written, so
+// that skillsets stored before their catalog changed its settings can still be loaded.
+func newCatalogSkillSetManager(ctx context.Context, rsrcJSON []byte, m *interfaces.Metadata) (SkillSetManager, apperrors.Error) {
+	skillset, err := ParseSkillSet(ctx, rsrcJSON, m)
+	if err != nil {
+		return nil, err
+	}
+	if err := skillset.LoadCatalogSpec(ctx); err != nil {
+		return nil, err
+	}
+
+	if validationErrs := skillset.Validate(); validationErrs != nil {
+		log.Ctx(ctx).Error().Err(validationErrs).Msg("Skillset validation failed")
+		return nil, ErrSchemaValidation.Msg(validationErrs.Error())
+	}
+
+	return &skillSetManager{skillSet: *skillset}, nil
+}
+
+// ParseSkillSet parses a skillset document, replacing its metadata with the provided metadata
+// as NewSkillSetManager does. The skillset is not validated.
+func ParseSkillSet(ctx context.Context, rsrcJSON []byte, m *interfaces.Metadata) (*SkillSet, apperrors.Error) {
 	if len(rsrcJSON) == 0 {
 		return nil, ErrEmptySchema
 	}
@@ -68,15 +113,9 @@ func NewSkillSetManager(ctx context.Context, rsrcJSON []byte, m *interfaces.Meta
 		log.Ctx(ctx).Error().Err(err).Msg("Failed to unmarshal skillset")
 		return nil, ErrSchemaValidation
 	}
-
-	if validationErrs := skillset.Validate(); validationErrs != nil {
-		log.Ctx(ctx).Error().Err(validationErrs).Msg("Skillset validation failed")
-		return nil, ErrSchemaValidation.Msg(validationErrs.Error())
-	}
-
 	skillset.Metadata = *m
 
-	return &skillSetManager{skillSet: skillset}, nil
+	return &skillset, nil
 }
 
 // GetSkillSetManager gets a skillset manager given a skillset path.
@@ -125,6 +164,52 @@ func LoadSkillSetManagerByPath(ctx context.Context, m *interfaces.Metadata) (Ski
 		return nil, ErrInvalidObject.Msg("unable to infer object metadata")
 	}
 
+	directoryID, pathWithName, err := skillSetLocation(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := db.DB(ctx).GetSkillSetObject(ctx, pathWithName, directoryID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrObjectNotFound.Msg("skillset not found")
+		}
+		return nil, err
+	}
+
+	sm, err := skillSetManagerFromObject(ctx, obj, m)
+	if err != nil {
+		return nil, err
+	}
+	sm.(*skillSetManager).prevHash = obj.Hash
+	return sm, nil
+}
+
+// LoadSkillSetManagerVersion loads the given spec version of a skillset from the database.
+// Every version a skillset has been saved with is retained until the skillset is deleted.
+func LoadSkillSetManagerVersion(ctx context.Context, m *interfaces.Metadata, version string) (SkillSetManager, apperrors.Error) {
+	if m == nil {
+		return nil, ErrInvalidObject.Msg("unable to infer object metadata")
+	}
+
+	directoryID, pathWithName, err := skillSetLocation(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := db.DB(ctx).GetSkillSetVersionObject(ctx, pathWithName, version, directoryID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrObjectNotFound.Msg("skillset version " + version + " not found")
+		}
+		return nil, err
+	}
+
+	return skillSetManagerFromObject(ctx, obj, m)
+}
+
+// skillSetLocation returns the skillset directory of the variant in m and the path of the skillset in it.
+func skillSetLocation(ctx context.Context, m *interfaces.Metadata) (uuid.UUID, string, apperrors.Error) {
 	// Get the directory ID for the skillset
 	catalogID := catcommon.GetCatalogID(ctx)
 	var err apperrors.Error
@@ -133,27 +218,18 @@ func LoadSkillSetManagerByPath(ctx context.Context, m *interfaces.Metadata) (Ski
 		catalogID, err = db.DB(ctx).GetCatalogIDByName(ctx, m.Catalog)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Str("catalog", m.Catalog).Msg("Failed to get catalog ID by name")
-			return nil, err
+			return uuid.Nil, "", err
 		}
 	}
 
 	variant, err := db.DB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("catalogID", catalogID.String()).Str("name", m.Name).Msg("Failed to get variant")
-		return nil, err
+		return uuid.Nil, "", err
 	}
 
 	pathWithName := path.Clean(m.GetStoragePath(catcommon.CatalogObjectTypeSkillset) + "/" + m.Name)
-
-	obj, err := db.DB(ctx).GetSkillSetObject(ctx, pathWithName, variant.SkillsetDirectoryID)
-	if err != nil {
-		if errors.Is(err, dberror.ErrNotFound) {
-			return nil, ErrObjectNotFound.Msg("skillset not found")
-		}
-		return nil, err
-	}
-
-	return skillSetManagerFromObject(ctx, obj, m)
+	return variant.SkillsetDirectoryID, pathWithName, nil
 }
 
 // LoadSkillSetManagerByHash loads a skillset manager from the database by hash.
@@ -242,7 +318,7 @@ func (h *skillsetKindHandler) Create(ctx context.Context, skillsetJSON []byte) (
 		Namespace: types.NullableStringFrom(h.req.Namespace),
 	}
 
-	sm, err := NewSkillSetManager(ctx, skillsetJSON, m)
+	sm, err := newCatalogSkillSetManager(ctx, skillsetJSON, m)
 	if err != nil {
 		return "", err
 	}
@@ -307,6 +383,55 @@ func (h *skillsetKindHandler) Get(ctx context.Context) ([]byte, apperrors.Error)
 	return jsonData, nil
 }
 
+// SkillSetSpecFields are the spec sections that can be selected when fetching a skillset.
+var SkillSetSpecFields = []string{"version", "sources", "context", "skills", "dependencies", "annotations", "overrides", "schemaDraft", "preflight"}
+
+// SelectSkillSetFields returns the skillset JSON with only the given spec sections. Selecting
+// skills also selects sources so that the source of every skill can be resolved, and selecting
+// the preflight skill also selects the skills it must be one of. The version is
+// always kept since it is required, and so is the schema draft since the schemas of the selected
+// sections are compiled with it. The rest of the object, such as the metadata, is returned
+// unchanged.
+func SelectSkillSetFields(skillset []byte, fields []string) ([]byte, apperrors.Error) {
+	selected := map[string]bool{"version": true, "schemaDraft": true}
+	for _, field := range fields {
+		if !slices.Contains(SkillSetSpecFields, field) {
+			return nil, ErrInvalidRequest.Msg("unknown skillset field " + field + ", expected one of " + strings.Join(SkillSetSpecFields, ", "))
+		}
+		selected[field] = true
+	}
+	if selected["preflight"] {
+		selected["skills"] = true
+	}
+	if selected["skills"] {
+		selected["sources"] = true
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(skillset, &obj); err != nil {
+		return nil, ErrUnableToLoadObject.Msg("failed to parse skillset")
+	}
+	var spec map[string]json.RawMessage
+	if err := json.Unmarshal(obj["spec"], &spec); err != nil {
+		return nil, ErrUnableToLoadObject.Msg("failed to parse skillset spec")
+	}
+	for field := range spec {
+		if !selected[field] {
+			delete(spec, field)
+		}
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, ErrUnableToLoadObject.Msg("failed to encode skillset spec")
+	}
+	obj["spec"] = specJSON
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return nil, ErrUnableToLoadObject.Msg("failed to encode skillset")
+	}
+	return out, nil
+}
+
 // hashHiddenContextValues processes the JSON to find context values with hidden=true
 // and replaces their values with SHA256 hashes (8 characters)
 func (h *skillsetKindHandler) hashHiddenContextValues(jsonData []byte) ([]byte, apperrors.Error) {
@@ -365,13 +490,90 @@ func (h *skillsetKindHandler) Update(ctx context.Context, skillsetJSON []byte) a
 		return ErrObjectNotFound
 	}
 
-	sm, err := NewSkillSetManager(ctx, skillsetJSON, m)
+	sm, err := newCatalogSkillSetManager(ctx, skillsetJSON, m)
 	if err != nil {
 		return err
 	}
+	return saveReplacing(ctx, existing, sm)
+}
+
+// saveReplacing saves sm in place of existing, a skillset loaded from the database. The save
+// fails with ErrConcurrentUpdate if the stored skillset changed after existing was loaded.
+func saveReplacing(ctx context.Context, existing, sm SkillSetManager) apperrors.Error {
+	if prev, ok := existing.(*skillSetManager); ok {
+		if next, ok := sm.(*skillSetManager); ok {
+			next.prevHash = prev.prevHash
+		}
+	}
 	return sm.Save(ctx)
 }
 
+// RenameSkill renames a skill of the skillset at m and saves the skillset.
+// The old name is kept as an alias of the skill so that existing callers keep working, and the
+// skillset is validated against its catalog's settings before it is saved. Like Update, the
+// rename fails with ErrConcurrentUpdate if the skillset is modified while it is being renamed.
+func RenameSkill(ctx context.Context, m *interfaces.Metadata, oldName, newName string) (SkillSetManager, apperrors.Error) {
+	existing, err := LoadSkillSetManagerByPath(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	skillsetJSON, err := existing.JSON(ctx)
+	if err != nil {
+		return nil, err
+	}
+	skillset, err := ParseSkillSet(ctx, skillsetJSON, m)
+	if err != nil {
+		return nil, err
+	}
+	if err := skillset.renameSkill(oldName, newName); err != nil {
+		return nil, err
+	}
+
+	skillsetJSON, goerr := json.Marshal(skillset)
+	if goerr != nil {
+		log.Ctx(ctx).Error().Err(goerr).Msg("failed to marshal skillset")
+		return nil, ErrInvalidSkillSetDefinition
+	}
+	sm, err := newCatalogSkillSetManager(ctx, skillsetJSON, m)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveReplacing(ctx, existing, sm); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// renameSkill renames the skill named oldName to newName and adds oldName to its aliases.
+// The preflight skill follows the rename. Returns ErrAlreadyExists if another skill is named
+// or aliased newName; renaming a skill to one of its own aliases is allowed.
+func (s *SkillSet) renameSkill(oldName, newName string) apperrors.Error {
+	if newName == "" {
+		return ErrInvalidObject.Msg("new skill name is required")
+	}
+	idx := slices.IndexFunc(s.Spec.Skills, func(skill Skill) bool { return skill.Name == oldName })
+	if idx < 0 {
+		return ErrObjectNotFound.Msg("skill " + oldName + " not found")
+	}
+	if newName == oldName {
+		return ErrInvalidObject.Msg("skill " + oldName + " already has that name")
+	}
+	for i, skill := range s.Spec.Skills {
+		if i != idx && (skill.Name == newName || slices.Contains(skill.Aliases, newName)) {
+			return ErrAlreadyExists.Msg("skill name " + newName + " is already used by skill " + skill.Name)
+		}
+	}
+
+	skill := &s.Spec.Skills[idx]
+	skill.Aliases = slices.DeleteFunc(slices.Clone(skill.Aliases), func(alias string) bool { return alias == newName })
+	skill.Aliases = append(skill.Aliases, oldName)
+	skill.Name = newName
+	if s.Spec.Preflight == oldName {
+		s.Spec.Preflight = newName
+	}
+	return nil
+}
+
 // Delete removes a skillset from storage.
 // It validates the metadata and deletes the skillset if it exists.
 func (h *skillsetKindHandler) Delete(ctx context.Context) apperrors.Error {
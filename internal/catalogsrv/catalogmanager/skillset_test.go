@@ -571,3 +571,77 @@ func TestHashHiddenContextValuesNoContexts(t *testing.T) {
 	// The result should be identical to the input since there are no contexts
 	assert.Equal(t, skillsetJSON, string(result))
 }
+
+func TestSelectSkillSetFields(t *testing.T) {
+	skillset := []byte(`{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "SkillSet",
+		"metadata": {"name": "test-skillset", "catalog": "test-catalog", "path": "/skillsets"},
+		"spec": {
+			"version": "1.0.0",
+			"sources": [{"name": "command-runner", "runner": "system.commandrunner"}],
+			"context": [{"name": "test-context", "value": {"k": "v"}}],
+			"skills": [{"name": "test-skill", "source": "command-runner"}],
+			"dependencies": [{"path": "/resources/kubeconfig", "kind": "Resource", "alias": "kubeconfig"}],
+			"annotations": {"team": "infra"},
+			"preflight": "test-skill"
+		}
+	}`)
+
+	specKeys := func(t *testing.T, data []byte) []string {
+		var obj struct {
+			Metadata map[string]any             `json:"metadata"`
+			Spec     map[string]json.RawMessage `json:"spec"`
+		}
+		require.NoError(t, json.Unmarshal(data, &obj))
+		assert.Equal(t, "test-skillset", obj.Metadata["name"])
+		keys := make([]string, 0, len(obj.Spec))
+		for k := range obj.Spec {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		name     string
+		fields   []string
+		expected []string
+	}{
+		{name: "skills include sources", fields: []string{"skills"}, expected: []string{"version", "skills", "sources"}},
+		{name: "skills and sources", fields: []string{"skills", "sources"}, expected: []string{"version", "skills", "sources"}},
+		{name: "context only", fields: []string{"context"}, expected: []string{"version", "context"}},
+		{name: "absent section", fields: []string{"overrides"}, expected: []string{"version"}},
+		{name: "several sections", fields: []string{"dependencies", "annotations"}, expected: []string{"version", "dependencies", "annotations"}},
+		{name: "preflight includes skills", fields: []string{"preflight"}, expected: []string{"version", "preflight", "skills", "sources"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := SelectSkillSetFields(skillset, tt.fields)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, specKeys(t, data))
+		})
+	}
+
+	t.Run("selected skillset can be loaded", func(t *testing.T) {
+		data, err := SelectSkillSetFields(skillset, []string{"skills"})
+		require.NoError(t, err)
+		sm, err := SkillSetManagerFromJSON(context.Background(), data)
+		require.NoError(t, err)
+		source, err := sm.GetSourceForSkill("test-skill")
+		require.NoError(t, err)
+		assert.Equal(t, "command-runner", source.Name)
+	})
+
+	t.Run("selected preflight can be loaded", func(t *testing.T) {
+		data, err := SelectSkillSetFields(skillset, []string{"preflight"})
+		require.NoError(t, err)
+		sm, err := SkillSetManagerFromJSON(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "test-skill", sm.GetPreflightSkill())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := SelectSkillSetFields(skillset, []string{"skills", "metadata"})
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+	})
+}
@@ -0,0 +1,152 @@
+package catalogmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/internal/common/apperrors"
+)
+
+// SkillSetBundle is a standalone export of a skillset. Objects holds the skillset followed by its
+// dependencies in the order they were discovered, each in the form accepted when creating it.
+type SkillSetBundle struct {
+	Objects []json.RawMessage `json:"objects"`
+}
+
+// BundleSkillSet exports the skillset managed by sm. If withDeps is set, the bundle also contains
+// the transitive closure of the skillset's SkillSet and Resource dependencies, resolved in the
+// skillset's catalog, variant and namespace. Inline dependencies carry their own value and are not
+// bundled. If any dependency does not exist, ErrMissingDependency lists every missing path.
+// Each dependency is only bundled if the view in ctx may read it, otherwise ErrDisallowedByPolicy
+// is returned.
+func BundleSkillSet(ctx context.Context, sm SkillSetManager, withDeps bool) (*SkillSetBundle, apperrors.Error) {
+	root, err := sm.JSON(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &SkillSetBundle{Objects: []json.RawMessage{root}}
+	if !withDeps {
+		return bundle, nil
+	}
+
+	scope := sm.Metadata()
+	visited := map[string]bool{path.Clean("/skillsets/" + sm.FullyQualifiedName()): true}
+	pending := []SkillSetManager{sm}
+	var missing []string
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		metadata, err := current.GetSkillMetadata()
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range metadata.Dependencies {
+			if dep.IsInline() || (dep.Kind != KindSkillSet && dep.Kind != KindResource) {
+				continue
+			}
+			depPath := path.Clean(dep.Path)
+			if visited[depPath] {
+				continue
+			}
+			visited[depPath] = true
+
+			m, err := dependencyMetadata(scope, dep.Kind, depPath)
+			if err != nil {
+				return nil, err
+			}
+			if err := authorizeDependencyRead(ctx, m, dep.Kind, depPath); err != nil {
+				return nil, err
+			}
+
+			var obj []byte
+			if dep.Kind == KindSkillSet {
+				var dsm SkillSetManager
+				dsm, err = LoadSkillSetManagerByPath(ctx, m)
+				if err == nil {
+					pending = append(pending, dsm)
+					obj, err = dsm.JSON(ctx)
+				}
+			} else {
+				var rm ResourceManager
+				rm, err = LoadResourceManagerByPath(ctx, m)
+				if err == nil {
+					obj, err = rm.JSON(ctx)
+				}
+			}
+			if err != nil {
+				if errors.Is(err, ErrObjectNotFound) || errors.Is(err, dberror.ErrNotFound) {
+					missing = append(missing, depPath)
+					continue
+				}
+				return nil, err
+			}
+			bundle.Objects = append(bundle.Objects, obj)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, ErrMissingDependency.Msg("missing dependencies: " + strings.Join(missing, ", "))
+	}
+	return bundle, nil
+}
+
+// authorizeDependencyRead checks that the view in ctx may read the dependency at depPath, resolved
+// in the catalog, variant and namespace of m. SkillSet dependencies need ActionSkillSetRead and
+// Resource dependencies need ActionResourceRead.
+func authorizeDependencyRead(ctx context.Context, m *interfaces.Metadata, kind DependencyKind, depPath string) apperrors.Error {
+	vd := policy.GetViewDefinition(ctx)
+	if vd == nil {
+		return ErrDisallowedByPolicy.Msg("unable to resolve view definition")
+	}
+	action := policy.ActionResourceRead
+	if kind == KindSkillSet {
+		action = policy.ActionSkillSetRead
+	}
+
+	resource := "res://catalogs/" + m.Catalog
+	if !m.Variant.IsNil() && m.Variant.String() != "" {
+		resource += "/variants/" + m.Variant.String()
+	}
+	if !m.Namespace.IsNil() && m.Namespace.String() != "" {
+		resource += "/namespaces/" + m.Namespace.String()
+	}
+	resource += depPath
+
+	allowed, _, err := policy.AreActionsAllowedOnResource(vd, resource, []policy.Action{action})
+	if err != nil {
+		return ErrDisallowedByPolicy.Msg("unable to authorize dependency " + depPath + ": " + err.Error())
+	}
+	if !allowed {
+		return ErrDisallowedByPolicy.Msg("not allowed to read dependency " + depPath)
+	}
+	return nil
+}
+
+// dependencyMetadata returns the metadata of the object a dependency path refers to, in the
+// catalog, variant and namespace of scope. Paths are of the form /skillsets/{path} or /resources/{path}.
+func dependencyMetadata(scope interfaces.Metadata, kind DependencyKind, depPath string) (*interfaces.Metadata, apperrors.Error) {
+	prefix := "/resources/"
+	if kind == KindSkillSet {
+		prefix = "/skillsets/"
+	}
+	objectPath, ok := strings.CutPrefix(depPath, prefix)
+	if !ok || objectPath == "" {
+		return nil, ErrInvalidSkillSetDefinition.Msg("dependency path " + depPath + " must start with " + prefix)
+	}
+	objectPath = "/" + objectPath
+	return &interfaces.Metadata{
+		Catalog:   scope.Catalog,
+		Variant:   scope.Variant,
+		Namespace: scope.Namespace,
+		Path:      path.Dir(objectPath),
+		Name:      path.Base(objectPath),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package catalogmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/pkg/types"
+)
+
+func TestDependencyMetadata(t *testing.T) {
+	scope := interfaces.Metadata{
+		Catalog:   "test-catalog",
+		Variant:   types.NullableStringFrom("dev"),
+		Namespace: types.NullableStringFrom("team"),
+	}
+
+	m, err := dependencyMetadata(scope, KindSkillSet, "/skillsets/ops/base-tools")
+	require.NoError(t, err)
+	assert.Equal(t, "test-catalog", m.Catalog)
+	assert.Equal(t, "dev", m.Variant.String())
+	assert.Equal(t, "team", m.Namespace.String())
+	assert.Equal(t, "/ops", m.Path)
+	assert.Equal(t, "base-tools", m.Name)
+
+	m, err = dependencyMetadata(scope, KindResource, "/resources/resource1")
+	require.NoError(t, err)
+	assert.Equal(t, "/", m.Path)
+	assert.Equal(t, "resource1", m.Name)
+
+	for _, tc := range []struct {
+		kind DependencyKind
+		path string
+	}{
+		{KindSkillSet, "/resources/resource1"},
+		{KindResource, "/skillsets/ops/base-tools"},
+		{KindResource, "/resources/"},
+	} {
+		_, err := dependencyMetadata(scope, tc.kind, tc.path)
+		assert.Error(t, err, tc.path)
+	}
+}
+
+func TestBundleSkillSetDependencyDenied(t *testing.T) {
+	sm := &skillSetManager{skillSet: SkillSet{
+		ApiVersion: "0.1.0-alpha.1",
+		Kind:       catcommon.SkillSetKind,
+		Metadata: interfaces.Metadata{
+			Name:      "deploy-tools",
+			Catalog:   "test-catalog",
+			Variant:   types.NullableStringFrom("dev"),
+			Namespace: types.NullableStringFrom("team"),
+			Path:      "/ops",
+		},
+		Spec: SkillSetSpec{
+			Version: "1.0.0",
+			Dependencies: []Dependency{
+				{Path: "/skillsets/ops/base-tools", Kind: KindSkillSet, Alias: "base", Actions: []policy.Action{"system.skillset.use"}},
+			},
+		},
+	}}
+	viewCtx := func(targets ...policy.TargetResource) context.Context {
+		return policy.WithViewDefinition(context.Background(), &policy.ViewDefinition{
+			Scope: policy.Scope{Catalog: "test-catalog", Variant: "dev", Namespace: "team"},
+			Rules: policy.Rules{{Intent: policy.IntentAllow, Actions: []policy.Action{policy.ActionSkillSetRead}, Targets: targets}},
+		})
+	}
+
+	// The root skillset alone is exported without checking its dependencies
+	bundle, err := BundleSkillSet(viewCtx("res://skillsets/ops/deploy-tools"), sm, false)
+	require.NoError(t, err)
+	assert.Len(t, bundle.Objects, 1)
+
+	// A dependency the view may not read is not bundled
+	_, err = BundleSkillSet(viewCtx("res://skillsets/ops/deploy-tools"), sm, true)
+	require.ErrorIs(t, err, ErrDisallowedByPolicy)
+	assert.Contains(t, err.Error(), "/skillsets/ops/base-tools")
+
+	// A read rule in another namespace does not grant access to the dependency
+	_, err = BundleSkillSet(policy.WithViewDefinition(context.Background(), &policy.ViewDefinition{
+		Scope: policy.Scope{Catalog: "test-catalog"},
+		Rules: policy.Rules{{Intent: policy.IntentAllow, Actions: []policy.Action{policy.ActionSkillSetRead},
+			Targets: []policy.TargetResource{"res://catalogs/test-catalog/variants/dev/namespaces/other/skillsets/ops/base-tools"}}},
+	}), sm, true)
+	require.ErrorIs(t, err, ErrDisallowedByPolicy)
+
+	// Without a view nothing is bundled
+	_, err = BundleSkillSet(context.Background(), sm, true)
+	require.ErrorIs(t, err, ErrDisallowedByPolicy)
+}
@@ -0,0 +1,127 @@
+package catalogmanager
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive/internal/common/apperrors"
+)
+
+// SkillSetDiff lists what changed between two versions of a skillset.
+type SkillSetDiff struct {
+	From         string      `json:"from"`
+	To           string      `json:"to"`
+	Skills       ElementDiff `json:"skills"`
+	Sources      ElementDiff `json:"sources"`
+	Context      ElementDiff `json:"context"`
+	Dependencies ElementDiff `json:"dependencies"`
+}
+
+// ElementDiff lists the named elements of a skillset spec that were added, removed or changed.
+// Skills, sources and contexts are named by their name, and dependencies by their alias.
+type ElementDiff struct {
+	Added   []string         `json:"added"`
+	Removed []string         `json:"removed"`
+	Changed []ChangedElement `json:"changed"`
+}
+
+// ChangedElement is an element present in both versions, with the fields that differ.
+type ChangedElement struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// DiffSkillSetVersions returns the differences between the from and to versions of the skillset
+// described by m. Both versions must have been saved.
+func DiffSkillSetVersions(ctx context.Context, m *interfaces.Metadata, from, to string) (*SkillSetDiff, apperrors.Error) {
+	fromSM, err := LoadSkillSetManagerVersion(ctx, m, from)
+	if err != nil {
+		return nil, err
+	}
+	toSM, err := LoadSkillSetManagerVersion(ctx, m, to)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffSkillSets(&fromSM.(*skillSetManager).skillSet.Spec, &toSM.(*skillSetManager).skillSet.Spec)
+	diff.From, diff.To = from, to
+	return diff, nil
+}
+
+// diffSkillSets compares two skillset specs.
+func diffSkillSets(from, to *SkillSetSpec) *SkillSetDiff {
+	return &SkillSetDiff{
+		Skills:       diffElements(from.Skills, to.Skills, func(s Skill) string { return s.Name }),
+		Sources:      diffElements(from.Sources, to.Sources, func(s SkillSetSource) string { return s.Name }),
+		Context:      diffElements(from.Context, to.Context, func(c SkillSetContext) string { return c.Name }),
+		Dependencies: diffElements(from.Dependencies, to.Dependencies, func(d Dependency) string { return d.Alias }),
+	}
+}
+
+// diffElements matches the elements of from and to by name. Added and changed elements are listed
+// in the order of to, and removed elements in the order of from.
+func diffElements[T any](from, to []T, name func(T) string) ElementDiff {
+	diff := ElementDiff{Added: []string{}, Removed: []string{}, Changed: []ChangedElement{}}
+
+	fromByName := make(map[string]T, len(from))
+	for _, e := range from {
+		fromByName[name(e)] = e
+	}
+	toNames := make(map[string]bool, len(to))
+	for _, e := range to {
+		n := name(e)
+		toNames[n] = true
+		prev, ok := fromByName[n]
+		if !ok {
+			diff.Added = append(diff.Added, n)
+			continue
+		}
+		if fields := changedFields(prev, e); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ChangedElement{Name: n, Fields: fields})
+		}
+	}
+	for _, e := range from {
+		if n := name(e); !toNames[n] {
+			diff.Removed = append(diff.Removed, n)
+		}
+	}
+	return diff
+}
+
+// changedFields returns the sorted JSON field names whose values differ between a and b.
+// Values are compared as decoded JSON so formatting differences are ignored.
+func changedFields(a, b any) []string {
+	fa, fb := jsonFields(a), jsonFields(b)
+	var fields []string
+	for k, v := range fa {
+		if w, ok := fb[k]; !ok || !reflect.DeepEqual(v, w) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range fb {
+		if _, ok := fa[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// jsonFields decodes the JSON encoding of v into its top-level fields. Null fields are omitted
+// so that a missing field and a null field compare equal.
+func jsonFields(v any) map[string]any {
+	fields := map[string]any{}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(b, &fields)
+	for k, f := range fields {
+		if f == nil {
+			delete(fields, k)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,87 @@
+package catalogmanager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/pkg/types"
+)
+
+func TestDiffSkillSets(t *testing.T) {
+	contextValue, err := types.NullableAnyFrom(map[string]any{"a": 1})
+	require.NoError(t, err)
+	from := &SkillSetSpec{
+		Version: "1.0.0",
+		Sources: []SkillSetSource{
+			{Name: "command-runner", Runner: "system.commandrunner", Config: map[string]any{"command": "python3 run.py"}},
+		},
+		Context: []SkillSetContext{
+			{Name: "config", Schema: json.RawMessage(`{"type": "object"}`), Value: contextValue},
+		},
+		Skills: []Skill{
+			{
+				Name:            "list",
+				Source:          "command-runner",
+				InputSchema:     json.RawMessage(`{"type": "object"}`),
+				Transform:       types.NullableStringFrom("function(session, input) { return input; }"),
+				ExportedActions: []policy.Action{"test.list"},
+			},
+			{Name: "stale", Source: "command-runner", ExportedActions: []policy.Action{"test.stale"}},
+		},
+		Dependencies: []Dependency{
+			{Path: "/resources/first", Kind: KindResource, Alias: "first", Actions: []policy.Action{policy.ActionResourceGet}},
+			{Path: "/resources/second", Kind: KindResource, Alias: "second", Actions: []policy.Action{policy.ActionResourceGet}},
+		},
+	}
+	to := &SkillSetSpec{
+		Version: "1.1.0",
+		Sources: from.Sources,
+		Context: []SkillSetContext{
+			// reformatting the schema is not a change
+			{Name: "config", Schema: json.RawMessage(`{ "type":"object" }`), Value: contextValue},
+		},
+		Skills: []Skill{
+			{
+				Name:            "list",
+				Source:          "command-runner",
+				InputSchema:     json.RawMessage(`{"type": "object"}`),
+				Transform:       types.NullableStringFrom("function(session, input) { return {filter: input.filter}; }"),
+				ExportedActions: []policy.Action{"test.list"},
+			},
+			{Name: "create", Source: "command-runner", ExportedActions: []policy.Action{"test.create"}},
+		},
+		Dependencies: []Dependency{
+			{Path: "/resources/first", Kind: KindResource, Alias: "first", Actions: []policy.Action{policy.ActionResourceGet}},
+		},
+	}
+
+	diff := diffSkillSets(from, to)
+
+	assert.Equal(t, []string{"create"}, diff.Skills.Added)
+	assert.Equal(t, []string{"stale"}, diff.Skills.Removed)
+	require.Len(t, diff.Skills.Changed, 1)
+	assert.Equal(t, ChangedElement{Name: "list", Fields: []string{"transform"}}, diff.Skills.Changed[0])
+
+	assert.Empty(t, diff.Dependencies.Added)
+	assert.Equal(t, []string{"second"}, diff.Dependencies.Removed)
+	assert.Empty(t, diff.Dependencies.Changed)
+
+	assert.Equal(t, ElementDiff{Added: []string{}, Removed: []string{}, Changed: []ChangedElement{}}, diff.Sources)
+	assert.Equal(t, ElementDiff{Added: []string{}, Removed: []string{}, Changed: []ChangedElement{}}, diff.Context)
+
+	// the diff in the other direction is the reverse
+	reverse := diffSkillSets(to, from)
+	assert.Equal(t, []string{"stale"}, reverse.Skills.Added)
+	assert.Equal(t, []string{"create"}, reverse.Skills.Removed)
+	assert.Equal(t, []string{"second"}, reverse.Dependencies.Added)
+
+	// a changed source config is reported by field
+	changedSource := *to
+	changedSource.Sources = []SkillSetSource{
+		{Name: "command-runner", Runner: "system.commandrunner", Config: map[string]any{"command": "python3 run2.py"}},
+	}
+	assert.Equal(t, []ChangedElement{{Name: "command-runner", Fields: []string{"config"}}}, diffSkillSets(from, &changedSource).Sources.Changed)
+}
@@ -6,14 +6,21 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"encoding/json"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/objectstore"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
@@ -25,12 +32,15 @@ import (
 	"github.com/tansive/tansive/internal/common/uuid"
 	"github.com/tansive/tansive/pkg/api"
 	"github.com/tansive/tansive/pkg/types"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
 type DependencyKind string
 
 const (
 	KindSkill    DependencyKind = "Skill"
+	KindSkillSet DependencyKind = "SkillSet"
 	KindResource DependencyKind = "Resource"
 )
 
@@ -41,6 +51,8 @@ type SkillSet struct {
 	Kind       string              `json:"kind" validate:"required,oneof=SkillSet"`
 	Metadata   interfaces.Metadata `json:"metadata" validate:"required"`
 	Spec       SkillSetSpec        `json:"spec,omitempty"`
+
+	catalogSpec *CatalogSpec // settings of the skillset's catalog, nil unless loaded by LoadCatalogSpec
 }
 
 // SkillSetSpec defines the specification for a skillset, including its schema,
@@ -52,15 +64,42 @@ type SkillSetSpec struct {
 	Skills       []Skill           `json:"skills" validate:"required,dive"`
 	Dependencies []Dependency      `json:"dependencies,omitempty" validate:"omitempty,dive"`
 	Annotations  map[string]string `json:"annotations,omitempty" validate:"omitempty"`
+	// Overrides holds source config overrides keyed by environment name and then by source name.
+	// The overrides of a session's environment are merged into the config of each named source.
+	Overrides map[string]map[string]any `json:"overrides,omitempty" validate:"omitempty"`
+	// SchemaDraft is the JSON Schema draft of the skillset's schemas. The schema library's
+	// default draft is used when not set.
+	SchemaDraft SchemaDraft `json:"schemaDraft,omitempty" validate:"omitempty"`
+	// Preflight names a skill of the skillset that runs before each skill invoked in a session.
+	// The invocation is aborted if the preflight skill fails.
+	Preflight string `json:"preflight,omitempty" validate:"omitempty"`
 }
 
 type SkillSetContext struct {
 	Name          string                 `json:"name" validate:"required,resourceNameValidator"`
 	Provider      ResourceProvider       `json:"provider,omitempty" validate:"required_without=Schema,omitempty,resourceNameValidator"`
 	Schema        json.RawMessage        `json:"schema" validate:"required_without=Provider,omitempty,jsonSchemaValidator"`
+	Format        string                 `json:"format,omitempty" validate:"omitempty"`
 	Value         types.NullableAny      `json:"value" validate:"omitempty"`
 	ValueByAction []ContextValueByAction `json:"valueByAction" validate:"omitempty,dive"`
 	Attributes    ContextAttributes      `json:"attributes" validate:"omitempty"`
+	// FailMode selects whether retrieving the context fails or yields nil when its value
+	// cannot be obtained. Contexts fail closed by default.
+	FailMode ContextFailMode `json:"failMode,omitempty" validate:"omitempty"`
+}
+
+// ContextFailMode selects how context retrieval behaves when the value cannot be obtained.
+type ContextFailMode string
+
+const (
+	ContextFailOpen   ContextFailMode = "open"
+	ContextFailClosed ContextFailMode = "closed"
+)
+
+// FailsOpen reports whether retrieval of the context yields nil instead of failing when its
+// value cannot be obtained.
+func (c SkillSetContext) FailsOpen() bool {
+	return c.FailMode == ContextFailOpen
 }
 
 type ContextValueByAction struct {
@@ -72,17 +111,33 @@ type SkillSetSource struct {
 	Name   string             `json:"name" validate:"required,resourceNameValidator"`
 	Runner catcommon.RunnerID `json:"runner" validate:"required"`
 	Config map[string]any     `json:"config" validate:"required"`
+	// EnvFromVars maps environment variable names of the runner process to the session
+	// variables they are set from. Tangents only inject the variables they allow.
+	EnvFromVars map[string]string `json:"envFromVars,omitempty"`
 }
 
 type Skill struct {
-	Name            string               `json:"name" validate:"required,skillNameValidator"`
-	Description     string               `json:"description"`
-	Source          string               `json:"source" validate:"required"`
-	InputSchema     json.RawMessage      `json:"inputSchema" validate:"omitempty,jsonSchemaValidator"`
-	OutputSchema    json.RawMessage      `json:"outputSchema" validate:"omitempty,jsonSchemaValidator"`
-	Transform       types.NullableString `json:"transform" validate:"omitempty"`
-	ExportedActions []policy.Action      `json:"exportedActions" validate:"required,dive"`
-	Annotations     map[string]string    `json:"annotations" validate:"omitempty"`
+	Name             string               `json:"name" validate:"required,skillNameValidator"`
+	Aliases          []string             `json:"aliases,omitempty" validate:"omitempty,dive,skillNameValidator"`
+	Description      string               `json:"description"`
+	Category         string               `json:"category,omitempty" validate:"omitempty,resourceNameValidator"`
+	Source           string               `json:"source" validate:"required"`
+	InputSchema      json.RawMessage      `json:"inputSchema" validate:"omitempty,jsonSchemaValidator"`
+	InputKeyStyle    InputKeyStyle        `json:"inputKeyStyle,omitempty" validate:"omitempty,oneof=camel snake"`
+	InputValidation  InputValidationMode  `json:"inputValidation,omitempty" validate:"omitempty,oneof=reject warn"`
+	DefaultInputArgs map[string]any       `json:"defaultInputArgs,omitempty" validate:"omitempty"`
+	RedactInputPaths []string             `json:"redactInputPaths,omitempty" validate:"omitempty"`
+	OutputSchema     json.RawMessage      `json:"outputSchema" validate:"omitempty,jsonSchemaValidator"`
+	ValidateOutput   bool                 `json:"validateOutput,omitempty"`
+	MaxConcurrent    int                  `json:"maxConcurrent,omitempty"`
+	MaxRestarts      int                  `json:"maxRestarts,omitempty"`
+	Cacheable        bool                 `json:"cacheable,omitempty"` // whether results are reused for identical input within CacheTTL
+	CacheTTL         string               `json:"cacheTTL,omitempty"`
+	MetricTags       map[string]string    `json:"metricTags,omitempty"`
+	Transform        types.NullableString `json:"transform" validate:"omitempty"`
+	ExportedActions  []policy.Action      `json:"exportedActions" validate:"required,dive"`
+	Annotations      map[string]string    `json:"annotations" validate:"omitempty"`
+	schemaDraft      SchemaDraft          // the schema draft of the skill's skillset
 }
 
 type ContextAttributes struct {
@@ -94,27 +149,323 @@ func (s *Skill) GetExportedActions() []policy.Action {
 	return s.ExportedActions
 }
 
+// GetCacheTTL returns how long results of a cacheable skill are reused. Returns 0 if the
+// skill's results are not cached.
+func (s *Skill) GetCacheTTL() time.Duration {
+	if !s.Cacheable {
+		return 0
+	}
+	ttl, err := time.ParseDuration(s.CacheTTL)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// validateCache checks that a cacheable skill sets a positive cacheTTL, and that only
+// cacheable skills set one.
+func (s *Skill) validateCache() error {
+	if s.CacheTTL == "" {
+		if s.Cacheable {
+			return fmt.Errorf("cacheable skills must set cacheTTL")
+		}
+		return nil
+	}
+	if !s.Cacheable {
+		return fmt.Errorf("cacheTTL is only allowed on cacheable skills")
+	}
+	ttl, err := time.ParseDuration(s.CacheTTL)
+	if err != nil {
+		return fmt.Errorf("invalid cacheTTL: %v", err)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("cacheTTL must be positive")
+	}
+	return nil
+}
+
+// LLMExamplesAnnotation is the skill annotation holding a JSON array of example outputs
+// that are surfaced to LLM-based agents alongside the output schema.
+const LLMExamplesAnnotation = "llm:examples"
+
+// GetOutputExamples returns the example outputs declared in the skill's annotations.
+func (s *Skill) GetOutputExamples() ([]json.RawMessage, error) {
+	raw, ok := s.Annotations[LLMExamplesAnnotation]
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var examples []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &examples); err != nil {
+		return nil, fmt.Errorf("%s must be a JSON array: %w", LLMExamplesAnnotation, err)
+	}
+	return examples, nil
+}
+
+// LLMResultFormatAnnotation is the skill annotation declaring the format, one of json, text
+// or markdown, in which the skill's results are returned to LLM-based agents.
+const LLMResultFormatAnnotation = "llm:resultFormat"
+
+// GetResultFormat returns the result format declared in the skill's annotations, or an empty
+// format if none is declared.
+func (s *Skill) GetResultFormat() (api.ResultFormat, error) {
+	raw, ok := s.Annotations[LLMResultFormatAnnotation]
+	if !ok || strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	format := api.ResultFormat(strings.TrimSpace(raw))
+	if !format.IsValid() {
+		return "", fmt.Errorf("%s must be one of json, text or markdown, got %q", LLMResultFormatAnnotation, raw)
+	}
+	return format, nil
+}
+
+// validateOutputExamples validates the skill's example outputs against its output schema.
+func (s *Skill) validateOutputExamples() error {
+	examples, err := s.GetOutputExamples()
+	if err != nil || len(examples) == 0 {
+		return err
+	}
+	if len(s.OutputSchema) == 0 || string(s.OutputSchema) == "null" {
+		return nil
+	}
+	schema, err := compileSchemaWithDraft(string(s.OutputSchema), s.schemaDraft)
+	if err != nil {
+		return err
+	}
+	for i, example := range examples {
+		var v any
+		if err := json.Unmarshal(example, &v); err != nil {
+			return fmt.Errorf("example %d: %w", i, err)
+		}
+		if err := schema.Validate(v); err != nil {
+			return fmt.Errorf("example %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// outputSchemaWithExamples returns the output schema with the examples added under the
+// JSON Schema "examples" keyword. The schema is returned unchanged if it is not an object.
+func outputSchemaWithExamples(schema json.RawMessage, examples []json.RawMessage) json.RawMessage {
+	if len(examples) == 0 || !gjson.ValidBytes(schema) || !gjson.ParseBytes(schema).IsObject() {
+		return schema
+	}
+	enriched, err := sjson.SetBytes(schema, "examples", examples)
+	if err != nil {
+		return schema
+	}
+	return enriched
+}
+
+// ApplyDefaultInputArgs returns input merged over the skill's default input args. Keys
+// provided by the caller take precedence over the defaults.
+func (s *Skill) ApplyDefaultInputArgs(input map[string]any) map[string]any {
+	if len(s.DefaultInputArgs) == 0 {
+		return input
+	}
+	merged := make(map[string]any, len(s.DefaultInputArgs)+len(input))
+	for k, v := range s.DefaultInputArgs {
+		merged[k] = v
+	}
+	for k, v := range input {
+		merged[k] = v
+	}
+	return merged
+}
+
+// validateDefaultInputArgs validates each default input arg against the input schema.
+// Defaults only cover part of the input, so they are checked against the schema of the
+// property they fill rather than against the whole schema.
+func (s *Skill) validateDefaultInputArgs() error {
+	if len(s.DefaultInputArgs) == 0 || len(s.InputSchema) == 0 || string(s.InputSchema) == "null" {
+		return nil
+	}
+	schema, err := compileSchemaWithDraft(string(s.InputSchema), s.schemaDraft)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(s.DefaultInputArgs))
+	for k := range s.DefaultInputArgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		propSchema, ok := schema.Properties[k]
+		if !ok {
+			switch additional := schema.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s is not a property of the input schema", k)
+				}
+				continue
+			case *jsonschema.Schema:
+				propSchema = additional
+			default:
+				continue
+			}
+		}
+		if err := propSchema.Validate(s.DefaultInputArgs[k]); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	return nil
+}
+
 func (s *Skill) ValidateInput(input map[string]any) apperrors.Error {
+	if err := CheckJSONDepth("input args", input); err != nil {
+		return err
+	}
 	if len(s.InputSchema) == 0 || string(s.InputSchema) == "null" {
 		return nil
 	}
-	schema, err := compileSchema(string(s.InputSchema))
+	schema, err := compileSchemaWithDraft(string(s.InputSchema), s.schemaDraft)
 	if err != nil {
 		return ErrInvalidObject.Msg("failed to compile input schema")
 	}
-	err = schema.Validate(input)
+	err = schema.Validate(s.NormalizeInput(input))
 	if err != nil {
 		return ErrInvalidInput.Msg("failed to validate input schema: " + err.Error())
 	}
 	return nil
 }
 
+// ValidateOutputAgainstSchema validates the output of a skill run against the skill's output schema.
+// The output must be a JSON document. Skills without an output schema accept any output.
+func (s *Skill) ValidateOutputAgainstSchema(output []byte) apperrors.Error {
+	if len(s.OutputSchema) == 0 || string(s.OutputSchema) == "null" {
+		return nil
+	}
+	schema, err := compileSchemaWithDraft(string(s.OutputSchema), s.schemaDraft)
+	if err != nil {
+		return ErrInvalidObject.Msg("failed to compile output schema")
+	}
+	var v any
+	if err := json.Unmarshal(output, &v); err != nil {
+		return ErrInvalidOutput.Msg("output is not valid JSON: " + err.Error())
+	}
+	if err := schema.Validate(v); err != nil {
+		return ErrInvalidOutput.Msg("failed to validate output schema: " + err.Error())
+	}
+	return nil
+}
+
 type Dependency struct {
-	Path    string          `json:"path" validate:"required,resourcePathValidator"`
+	Path    string          `json:"path" validate:"omitempty,resourcePathValidator"`
 	Kind    DependencyKind  `json:"kind" validate:"required,oneof=SkillSet Resource"`
 	Alias   string          `json:"alias" validate:"required,resourceNameValidator"`
 	Export  bool            `json:"export" validate:"omitempty"`
-	Actions []policy.Action `json:"actions" validate:"required,dive"`
+	Actions []policy.Action `json:"actions" validate:"omitempty,dive"` // defaults to the catalog's dependencyDefaults for the kind
+	// When limits the dependency to sessions whose variables satisfy the condition.
+	When *DependencyCondition `json:"when,omitempty" validate:"omitempty"`
+	// Inline supplies the resource in place of a catalog path so a skillset can be tested
+	// without the referenced resource existing. Exactly one of Path and Inline must be set.
+	// Skillsets with inline dependencies are only stored by servers in single user mode.
+	Inline *InlineResource `json:"inline,omitempty" validate:"omitempty"`
+}
+
+// InlineResource is a resource value carried directly on a dependency. If Schema is set,
+// Value must conform to it.
+type InlineResource struct {
+	Schema json.RawMessage   `json:"schema,omitempty"`
+	Value  types.NullableAny `json:"value"`
+}
+
+// validate checks the dependency declares exactly one of a path or an inline value, and that
+// an inline value matches the dependency kind and its schema.
+func (d *Dependency) validate(field string) schemaerr.ValidationErrors {
+	var validationErrors schemaerr.ValidationErrors
+	switch {
+	case d.Path == "" && d.Inline == nil:
+		return append(validationErrors, schemaerr.ErrMissingRequiredAttribute(field+".path"))
+	case d.Path != "" && d.Inline != nil:
+		return append(validationErrors, schemaerr.ErrInvalidValue(field, "path and inline are mutually exclusive"))
+	case d.Inline == nil:
+		return nil
+	}
+	if d.Kind != KindResource {
+		return append(validationErrors, schemaerr.ErrInvalidValue(field+".inline", "inline values are only supported for Resource dependencies"))
+	}
+	if len(d.Inline.Schema) > 0 {
+		r := Resource{Spec: ResourceSpec{Schema: d.Inline.Schema}}
+		if err := r.ValidateValue(d.Inline.Value); err != nil {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(field+".inline.value", err.Error()))
+		}
+	}
+	return validationErrors
+}
+
+// IsInline reports whether the dependency carries its resource inline rather than by path.
+func (d *Dependency) IsInline() bool {
+	return d.Inline != nil
+}
+
+// checkInlineDependencies rejects inline dependencies unless the server runs in single user
+// or test mode. Inline values bypass the view that governs access to catalog resources, so
+// they are limited to local testing.
+func (s *SkillSet) checkInlineDependencies() apperrors.Error {
+	if config.Config().SingleUserMode || config.IsTest() {
+		return nil
+	}
+	for i, dep := range s.Spec.Dependencies {
+		if dep.IsInline() {
+			return ErrInvalidSkillSetDefinition.Msg(fmt.Sprintf("spec.dependencies[%d]: inline values are only allowed in single user mode", i))
+		}
+	}
+	return nil
+}
+
+// DependencyCondition is evaluated against the session variables of a session to decide
+// whether a dependency is needed. Exactly one of Equals, In or Exists must be set.
+type DependencyCondition struct {
+	Variable string `json:"variable"`
+	Equals   any    `json:"equals,omitempty"`
+	In       []any  `json:"in,omitempty"`
+	Exists   *bool  `json:"exists,omitempty"`
+}
+
+// Matches reports whether the session variables satisfy the condition.
+func (c *DependencyCondition) Matches(sessionVariables map[string]any) bool {
+	value, ok := sessionVariables[c.Variable]
+	switch {
+	case c.Exists != nil:
+		return ok == *c.Exists
+	case c.Equals != nil:
+		return ok && reflect.DeepEqual(value, c.Equals)
+	case c.In != nil:
+		return ok && slices.ContainsFunc(c.In, func(v any) bool { return reflect.DeepEqual(value, v) })
+	}
+	return false
+}
+
+// validate checks that the condition names a variable and sets exactly one operator.
+func (c *DependencyCondition) validate(field string) schemaerr.ValidationErrors {
+	var validationErrors schemaerr.ValidationErrors
+	if c.Variable == "" {
+		validationErrors = append(validationErrors, schemaerr.ErrMissingRequiredAttribute(field+".variable"))
+	}
+	operators := 0
+	if c.Equals != nil {
+		operators++
+	}
+	if c.In != nil {
+		operators++
+		if len(c.In) == 0 {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(field+".in", "must not be empty"))
+		}
+	}
+	if c.Exists != nil {
+		operators++
+	}
+	if operators != 1 {
+		validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(field, "exactly one of equals, in or exists must be set"))
+	}
+	return validationErrors
+}
+
+// IsRequired reports whether the dependency is needed by a session with the given variables.
+// Dependencies without a condition are always required.
+func (d *Dependency) IsRequired(sessionVariables map[string]any) bool {
+	return d.When == nil || d.When.Matches(sessionVariables)
 }
 
 // SkillMetadata represents the metadata extracted from skills and dependencies
@@ -137,9 +488,33 @@ func (m *SkillMetadata) GetSkill(name string) (SkillSummary, bool) {
 	return SkillSummary{}, false
 }
 
+// SkillSetDescription is a summary of a skillset intended for generating documentation.
+type SkillSetDescription struct {
+	Name         string             `json:"name"`
+	Path         string             `json:"path"`
+	Description  string             `json:"description,omitempty"`
+	Version      string             `json:"version"`
+	Skills       []SkillDescription `json:"skills"`
+	Contexts     []string           `json:"contexts"`
+	Dependencies []Dependency       `json:"dependencies"`
+}
+
+// SkillDescription summarizes a skill and the runner of its source.
+type SkillDescription struct {
+	Name            string             `json:"name"`
+	Aliases         []string           `json:"aliases,omitempty"`
+	Description     string             `json:"description"`
+	Source          string             `json:"source"`
+	Runner          catcommon.RunnerID `json:"runner"`
+	ExportedActions []policy.Action    `json:"exportedActions"`
+}
+
 // skillSetManager implements the SkillSetManager interface for managing a single skillset.
 type skillSetManager struct {
 	skillSet SkillSet
+	// prevHash is the hash of the stored skillset this manager updates. When set, Save fails
+	// with ErrConcurrentUpdate if the stored skillset no longer has this hash.
+	prevHash string
 }
 
 // Metadata returns the skillset's metadata.
@@ -201,6 +576,40 @@ func (sm *skillSetManager) GetSkillMetadata() (SkillMetadata, apperrors.Error) {
 	return metadata, nil
 }
 
+// Describe returns a summary of the skillset. Context values and source configs are not included.
+func (sm *skillSetManager) Describe() SkillSetDescription {
+	d := SkillSetDescription{
+		Name:         sm.skillSet.Metadata.Name,
+		Path:         sm.FullyQualifiedName(),
+		Description:  sm.skillSet.Metadata.Description,
+		Version:      sm.skillSet.Spec.Version,
+		Skills:       make([]SkillDescription, 0, len(sm.skillSet.Spec.Skills)),
+		Contexts:     make([]string, 0, len(sm.skillSet.Spec.Context)),
+		Dependencies: sm.skillSet.Spec.Dependencies,
+	}
+	if d.Dependencies == nil {
+		d.Dependencies = []Dependency{}
+	}
+	for _, skill := range sm.skillSet.Spec.Skills {
+		var runner catcommon.RunnerID
+		if source, err := sm.GetSourceByName(skill.Source); err == nil {
+			runner = source.Runner
+		}
+		d.Skills = append(d.Skills, SkillDescription{
+			Name:            skill.Name,
+			Aliases:         skill.Aliases,
+			Description:     skill.Description,
+			Source:          skill.Source,
+			Runner:          runner,
+			ExportedActions: skill.ExportedActions,
+		})
+	}
+	for _, c := range sm.skillSet.Spec.Context {
+		d.Contexts = append(d.Contexts, c.Name)
+	}
+	return d
+}
+
 // Save saves the skillset to the database.
 // It handles the creation or update of both the skillset and its associated catalog object.
 func (sm *skillSetManager) Save(ctx context.Context) apperrors.Error {
@@ -208,6 +617,10 @@ func (sm *skillSetManager) Save(ctx context.Context) apperrors.Error {
 		return ErrEmptySchema
 	}
 
+	if err := sm.skillSet.checkInlineDependencies(); err != nil {
+		return err
+	}
+
 	t := catcommon.CatalogObjectTypeSkillset
 
 	m := sm.Metadata()
@@ -265,18 +678,41 @@ func (sm *skillSetManager) Save(ctx context.Context) apperrors.Error {
 		Hash:      newHash,
 		VariantID: variant.VariantID,
 		Metadata:  skillMetadataJSON,
+		Search:    sm.searchText(),
+		Version:   sm.skillSet.Spec.Version,
+		PrevHash:  sm.prevHash,
 	}
 
 	// Store the object
 	err = db.DB(ctx).UpsertSkillSetObject(ctx, ss, &obj, variant.SkillsetDirectoryID)
 	if err != nil {
+		if errors.Is(err, dberror.ErrQuotaExceeded) {
+			return ErrQuotaExceeded.Msg(err.Error())
+		}
+		if errors.Is(err, dberror.ErrConflict) {
+			return ErrConcurrentUpdate.Msg("skillset " + m.Name + " was modified by another request")
+		}
 		log.Ctx(ctx).Error().Err(err).Str("path", storagePath).Msg("Failed to store object")
 		return err
 	}
+	sm.prevHash = newHash
 
 	return nil
 }
 
+// searchText returns the text of the skillset indexed for catalog search.
+func (sm *skillSetManager) searchText() *models.SkillSetSearch {
+	search := &models.SkillSetSearch{
+		Name:        sm.FullyQualifiedName(),
+		Namespace:   sm.skillSet.Metadata.Namespace.String(),
+		Description: sm.skillSet.Metadata.Description,
+	}
+	for _, skill := range sm.skillSet.Spec.Skills {
+		search.SkillNames = append(search.SkillNames, skill.Name)
+	}
+	return search
+}
+
 // JSON returns the JSON representation of the skillset.
 func (sm *skillSetManager) JSON(ctx context.Context) ([]byte, apperrors.Error) {
 	j, err := json.Marshal(sm.skillSet)
@@ -298,18 +734,31 @@ func (sm *skillSetManager) SpecJSON(ctx context.Context) ([]byte, apperrors.Erro
 }
 
 func (sm *skillSetManager) GetSourceForSkill(skillName string) (SkillSetSource, apperrors.Error) {
-	for _, skill := range sm.skillSet.Spec.Skills {
-		if skill.Name == skillName {
-			for _, source := range sm.skillSet.Spec.Sources {
-				if source.Name == skill.Source {
-					return source, nil
-				}
+	if skill, err := sm.GetSkill(skillName); err == nil {
+		for _, source := range sm.skillSet.Spec.Sources {
+			if source.Name == skill.Source {
+				return source, nil
 			}
 		}
 	}
 	return SkillSetSource{}, ErrInvalidObject.Msg("source not found for skill " + skillName)
 }
 
+// ApplySourceOverrides returns the source with the skillset's overrides for the environment merged
+// into its config. The source is returned unchanged if the environment is empty or has no
+// overrides for it.
+func (sm *skillSetManager) ApplySourceOverrides(source SkillSetSource, environment string) SkillSetSource {
+	if environment == "" {
+		return source
+	}
+	override, ok := sm.skillSet.Spec.Overrides[environment][source.Name].(map[string]any)
+	if !ok {
+		return source
+	}
+	source.Config = mergeConfig(source.Config, override)
+	return source
+}
+
 func (sm *skillSetManager) GetSourceByName(sourceName string) (SkillSetSource, apperrors.Error) {
 	for _, source := range sm.skillSet.Spec.Sources {
 		if source.Name == sourceName {
@@ -319,22 +768,45 @@ func (sm *skillSetManager) GetSourceByName(sourceName string) (SkillSetSource, a
 	return SkillSetSource{}, ErrInvalidObject.Msg("source not found")
 }
 
+// GetSkill returns the skill with the given name or alias.
 func (sm *skillSetManager) GetSkill(name string) (Skill, apperrors.Error) {
 	for _, skill := range sm.skillSet.Spec.Skills {
 		if skill.Name == name {
 			return skill, nil
 		}
 	}
+	for _, skill := range sm.skillSet.Spec.Skills {
+		if slices.Contains(skill.Aliases, name) {
+			return skill, nil
+		}
+	}
 	return Skill{}, ErrInvalidObject.Msg("skill not found")
 }
 
+// ResolveDependencies returns the dependencies required by a session with the given variables.
+// Dependencies whose condition does not match are omitted.
+func (sm *skillSetManager) ResolveDependencies(sessionVariables map[string]any) []Dependency {
+	deps := []Dependency{}
+	for _, dep := range sm.skillSet.Spec.Dependencies {
+		if dep.IsRequired(sessionVariables) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
 func (sm *skillSetManager) GetAllSkills() []Skill {
 	return sm.skillSet.Spec.Skills
 }
 
-func (sm *skillSetManager) GetAllSkillsAsLLMTools(viewDef *policy.ViewDefinition) []api.LLMTool {
+// GetAllSkillsAsLLMTools returns the skills that have an LLM description as LLM tools.
+// If category is not empty, only skills in that category are returned.
+func (sm *skillSetManager) GetAllSkillsAsLLMTools(viewDef *policy.ViewDefinition, category string) []api.LLMTool {
 	tools := []api.LLMTool{}
 	for _, skill := range sm.skillSet.Spec.Skills {
+		if category != "" && skill.Category != category {
+			continue
+		}
 		//if viewDef is provided, validate if our policy allows access to this skill
 		if viewDef != nil {
 			isAllowed, _, err := policy.AreActionsAllowedOnResource(viewDef, sm.GetResourcePath(), skill.GetExportedActions())
@@ -344,11 +816,17 @@ func (sm *skillSetManager) GetAllSkillsAsLLMTools(viewDef *policy.ViewDefinition
 		}
 		// add the skill to the tools
 		if desc, ok := skill.Annotations["llm:description"]; ok {
+			// examples are validated when the skillset is saved, so a parse failure here just omits them
+			examples, _ := skill.GetOutputExamples()
+			resultFormat, _ := skill.GetResultFormat()
 			tools = append(tools, api.LLMTool{
 				Name:         skill.Name,
 				Description:  desc,
+				Category:     skill.Category,
 				InputSchema:  skill.InputSchema,
-				OutputSchema: skill.OutputSchema,
+				OutputSchema: outputSchemaWithExamples(skill.OutputSchema, examples),
+				Examples:     examples,
+				ResultFormat: resultFormat,
 			})
 		}
 	}
@@ -398,27 +876,110 @@ func (sm *skillSetManager) GetContextValue(name string, viewDef ...*policy.ViewD
 	return ctx.Value, nil
 }
 
+// GetContextInputArgs returns the value of the named context for use as a skill's input args,
+// resolved for viewDef as in GetContextValue. The value must be a JSON object.
+func (sm *skillSetManager) GetContextInputArgs(name string, viewDef *policy.ViewDefinition) (map[string]any, apperrors.Error) {
+	if _, err := sm.GetContext(name); err != nil {
+		return nil, ErrInvalidInput.Msg("input args context " + name + " not found")
+	}
+	value, err := sm.GetContextValue(name, viewDef)
+	if err != nil {
+		return nil, err
+	}
+	inputArgs, ok := value.Get().(map[string]any)
+	if !ok {
+		return nil, ErrInvalidInput.Msg("input args context " + name + " must hold an object")
+	}
+	return inputArgs, nil
+}
+
 func (sm *skillSetManager) SetContextValue(name string, value types.NullableAny) apperrors.Error {
+	i, err := sm.validateContextValue(name, value)
+	if err != nil {
+		return err
+	}
+	sm.skillSet.Spec.Context[i].Value = value
+	return nil
+}
+
+// validateContextValue checks that value can be stored in the named context and returns
+// the index of the context.
+func (sm *skillSetManager) validateContextValue(name string, value types.NullableAny) (int, apperrors.Error) {
 	for i, ctx := range sm.skillSet.Spec.Context {
 		if ctx.Name == name {
 			if ctx.Attributes.ReadOnly {
-				return ErrInvalidObject.Msg("context is read only")
+				return -1, ErrInvalidObject.Msg("context is read only")
 			}
 			if !value.IsNil() {
-				compiledSchema, err := compileSchema(string(ctx.Schema))
+				if err := CheckJSONDepth("context value", value.Get()); err != nil {
+					return -1, err
+				}
+				compiledSchema, err := compileSchemaWithDraft(string(ctx.Schema), sm.skillSet.Spec.SchemaDraft)
 				if err != nil {
-					return ErrInvalidObject.Msg("failed to compile schema")
+					return -1, ErrInvalidObject.Msg("failed to compile schema")
 				}
 				err = compiledSchema.Validate(value.Get())
 				if err != nil {
-					return ErrInvalidObject.Msg("failed to validate schema")
+					return -1, ErrInvalidObject.Msg("failed to validate schema")
 				}
 			}
-			sm.skillSet.Spec.Context[i].Value = value
-			return nil
+			if err := validateContextFormat(ctx.Format, value); err != nil {
+				return -1, ErrInvalidObject.Msg("failed to validate format: " + err.Error())
+			}
+			return i, nil
 		}
 	}
-	return ErrObjectNotFound.Msg("context not found")
+	return -1, ErrObjectNotFound.Msg("context not found")
+}
+
+// ValidateContextOverrides checks that each override, keyed by context name, can be stored in
+// its context. Overrides are subject to the same checks as setting a context value.
+func (sm *skillSetManager) ValidateContextOverrides(overrides map[string]any) apperrors.Error {
+	_, err := sm.contextOverrideValues(overrides)
+	return err
+}
+
+// ApplyContextOverrides replaces the values of the contexts named in overrides. Nothing is
+// changed if any override is invalid. Values for specific actions still take precedence over
+// an overridden value.
+func (sm *skillSetManager) ApplyContextOverrides(overrides map[string]any) apperrors.Error {
+	values, err := sm.contextOverrideValues(overrides)
+	if err != nil {
+		return err
+	}
+	for i, value := range values {
+		sm.skillSet.Spec.Context[i].Value = value
+	}
+	return nil
+}
+
+// contextOverrideValues validates overrides and returns their values keyed by context index.
+func (sm *skillSetManager) contextOverrideValues(overrides map[string]any) (map[int]types.NullableAny, apperrors.Error) {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[int]types.NullableAny, len(overrides))
+	for _, name := range names {
+		value, goerr := types.NullableAnyFrom(overrides[name])
+		if goerr != nil {
+			return nil, ErrInvalidObject.Msg("invalid override for context " + name + ": " + goerr.Error())
+		}
+		i, err := sm.validateContextValue(name, value)
+		if err != nil {
+			return nil, err.Msg("invalid override for context " + name + ": " + err.Error())
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// GetPreflightSkill returns the name of the skill that runs before each skill of the skillset,
+// or an empty string if the skillset has no preflight skill.
+func (sm *skillSetManager) GetPreflightSkill() string {
+	return sm.skillSet.Spec.Preflight
 }
 
 func (sm *skillSetManager) GetRunnerTypes() []catcommon.RunnerID {
@@ -482,91 +1043,508 @@ func DeleteSkillSet(ctx context.Context, m *interfaces.Metadata) apperrors.Error
 // - Schema validation
 // - Value validation against the schema
 func (s *SkillSet) Validate() schemaerr.ValidationErrors {
-	var validationErrors schemaerr.ValidationErrors
+	return collectValidationErrors(s.validate)
+}
+
+// ValidateStream performs the same validation as Validate, sending each error on errs as soon as
+// it is found. errs is closed when validation is done. Errors are dropped
+// once ctx is cancelled.
+func (s *SkillSet) ValidateStream(ctx context.Context, errs chan<- schemaerr.ValidationError) {
+	defer close(errs)
+	s.validate(func(err schemaerr.ValidationError) {
+		select {
+		case errs <- err:
+		case <-ctx.Done():
+		}
+	})
+}
 
+// validate runs the skillset checks in order, passing each error found to report.
+func (s *SkillSet) validate(report func(schemaerr.ValidationError)) {
 	// Validate kind
 	if s.Kind != catcommon.SkillSetKind {
-		validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind("kind"))
+		report(schemaerr.ErrUnsupportedKind("kind"))
+	}
+
+	// Validate the number of entries before validating each of them
+	exceeded := false
+	s.validateLimits(func(err schemaerr.ValidationError) {
+		exceeded = true
+		report(err)
+	})
+	if exceeded {
+		return
 	}
 
 	// Validate struct using schema validator
-	err := schemavalidator.V().Struct(s)
-	if err != nil {
-		return s.handleStructValidationErrors(err, validationErrors)
+	if err := schemavalidator.V().Struct(s); err != nil {
+		reportEach(report, s.handleStructValidationErrors(err, nil))
+		return
 	}
 
-	// Validate skills
-	validationErrors = append(validationErrors, s.validateSkills()...)
+	// Validate the schema draft before compiling schemas with it
+	if _, err := s.Spec.SchemaDraft.jsonschemaDraft(); err != nil {
+		report(schemaerr.ErrInvalidValue("spec.schemaDraft", err.Error()))
+		return
+	}
+	s.Spec.bindSchemaDraft()
+
+	checks := []func(report func(schemaerr.ValidationError)){
+		s.validateName,         // skillset name is not reserved
+		s.validateSources,      // source configs
+		s.validateOverrides,    // source config overrides
+		s.validateSkills,       // skills
+		s.validateSkillNames,   // skill names and aliases are unique
+		s.validateContexts,     // contexts
+		s.validateDependencies, // dependency conditions
+		s.validatePreflight,    // preflight skill
+		s.validateAnnotations,  // skill annotations against the catalog's schema
+	}
+	for _, check := range checks {
+		check(report)
+	}
+}
 
-	// Validate contexts
-	validationErrors = append(validationErrors, s.validateContexts()...)
+// reportEach passes each of errs to report.
+func reportEach(report func(schemaerr.ValidationError), errs schemaerr.ValidationErrors) {
+	for _, err := range errs {
+		report(err)
+	}
+}
 
+// collectValidationErrors runs check and returns the errors it reports.
+func collectValidationErrors(check func(report func(schemaerr.ValidationError))) schemaerr.ValidationErrors {
+	var validationErrors schemaerr.ValidationErrors
+	check(func(err schemaerr.ValidationError) {
+		validationErrors = append(validationErrors, err)
+	})
 	return validationErrors
 }
 
+// SkillSetLimits caps the number of entries in a skillset, and the nesting depth of the JSON
+// values validated against its schemas, so that pathological documents cannot exhaust the
+// resources of the server or tangents.
+type SkillSetLimits struct {
+	MaxSources      int
+	MaxSkills       int
+	MaxContexts     int
+	MaxDependencies int
+	MaxJSONDepth    int // nesting depth of input args, context values and session variables
+}
+
+// DefaultSkillSetLimits are the limits used until SetSkillSetLimits is called.
+var DefaultSkillSetLimits = SkillSetLimits{
+	MaxSources:      config.DefaultSkillSetMaxSources,
+	MaxSkills:       config.DefaultSkillSetMaxSkills,
+	MaxContexts:     config.DefaultSkillSetMaxContexts,
+	MaxDependencies: config.DefaultSkillSetMaxDependencies,
+	MaxJSONDepth:    config.DefaultSkillSetMaxJSONDepth,
+}
+
+var skillSetLimits = DefaultSkillSetLimits
+
+// SetSkillSetLimits sets the limits enforced when validating skillsets. Limits that are not
+// positive use the default.
+func SetSkillSetLimits(limits SkillSetLimits) {
+	if limits.MaxSources <= 0 {
+		limits.MaxSources = DefaultSkillSetLimits.MaxSources
+	}
+	if limits.MaxSkills <= 0 {
+		limits.MaxSkills = DefaultSkillSetLimits.MaxSkills
+	}
+	if limits.MaxContexts <= 0 {
+		limits.MaxContexts = DefaultSkillSetLimits.MaxContexts
+	}
+	if limits.MaxDependencies <= 0 {
+		limits.MaxDependencies = DefaultSkillSetLimits.MaxDependencies
+	}
+	if limits.MaxJSONDepth <= 0 {
+		limits.MaxJSONDepth = DefaultSkillSetLimits.MaxJSONDepth
+	}
+	skillSetLimits = limits
+}
+
+// CheckJSONDepth returns ErrJSONTooDeep if a decoded JSON value nests objects and arrays deeper
+// than the MaxJSONDepth skillset limit. A scalar has depth 0 and an object of scalars depth 1.
+// Values are checked before schema validation, which recurses over the value.
+func CheckJSONDepth(what string, value any) apperrors.Error {
+	if exceedsJSONDepth(value, skillSetLimits.MaxJSONDepth) {
+		return ErrJSONTooDeep.Msg(fmt.Sprintf("%s is nested deeper than the maximum depth of %d", what, skillSetLimits.MaxJSONDepth))
+	}
+	return nil
+}
+
+// exceedsJSONDepth reports whether value nests deeper than remaining levels. It stops descending
+// once the limit is exceeded, so its own recursion is bounded by the limit.
+func exceedsJSONDepth(value any, remaining int) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		if remaining <= 0 {
+			return true
+		}
+		for _, elem := range v {
+			if exceedsJSONDepth(elem, remaining-1) {
+				return true
+			}
+		}
+	case []any:
+		if remaining <= 0 {
+			return true
+		}
+		for _, elem := range v {
+			if exceedsJSONDepth(elem, remaining-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateLimits validates the number of sources, skills, contexts and dependencies against the skillset limits
+func (s *SkillSet) validateLimits(report func(schemaerr.ValidationError)) {
+	limits := []struct {
+		field string
+		count int
+		max   int
+	}{
+		{"spec.sources", len(s.Spec.Sources), skillSetLimits.MaxSources},
+		{"spec.skills", len(s.Spec.Skills), skillSetLimits.MaxSkills},
+		{"spec.context", len(s.Spec.Context), skillSetLimits.MaxContexts},
+		{"spec.dependencies", len(s.Spec.Dependencies), skillSetLimits.MaxDependencies},
+	}
+	for _, l := range limits {
+		if l.count > l.max {
+			report(schemaerr.ErrInvalidValue(l.field, fmt.Sprintf("has %d entries, at most %d are allowed", l.count, l.max)))
+		}
+	}
+}
+
+// validateSources validates the config of each source against the schema of its runner
+func (s *SkillSet) validateSources(report func(schemaerr.ValidationError)) {
+	for i, source := range s.Spec.Sources {
+		reportEach(report, validateRunnerConfig(source.Runner, source.Config, fmt.Sprintf("spec.sources[%d].config", i)))
+		validateEnvFromVars(source.EnvFromVars, fmt.Sprintf("spec.sources[%d].envFromVars", i), report)
+	}
+}
+
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvVars are the environment variables envFromVars may not set, since they control how
+// the runner process loads code or finds its files.
+var reservedEnvVars = []string{"PATH", "HOME"}
+
+// reservedEnvVarPrefixes are the prefixes of environment variables envFromVars may not set: those
+// of the dynamic loader and those of tansive itself.
+var reservedEnvVarPrefixes = []string{"LD_", "DYLD_", "TANSIVE_"}
+
+// IsReservedEnvVar reports whether a session variable may not be injected into a runner
+// environment as the variable name. Names are compared case-insensitively.
+func IsReservedEnvVar(name string) bool {
+	name = strings.ToUpper(name)
+	if slices.Contains(reservedEnvVars, name) {
+		return true
+	}
+	for _, prefix := range reservedEnvVarPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateEnvFromVars validates that each mapping of a source's envFromVars sets a valid
+// environment variable name from a session variable
+func validateEnvFromVars(envFromVars map[string]string, field string, report func(schemaerr.ValidationError)) {
+	names := make([]string, 0, len(envFromVars))
+	for name := range envFromVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !envVarNamePattern.MatchString(name) {
+			report(schemaerr.ErrInvalidValue(field, "invalid environment variable name "+name))
+			continue
+		}
+		if IsReservedEnvVar(name) {
+			report(schemaerr.ErrInvalidValue(field, "environment variable "+name+" is reserved"))
+			continue
+		}
+		if envFromVars[name] == "" {
+			report(schemaerr.ErrInvalidValue(field+"."+name, "session variable key must not be empty"))
+		}
+	}
+}
+
+// validateOverrides validates that source config overrides name existing sources and that
+// the merged config of each source is valid for its runner
+func (s *SkillSet) validateOverrides(report func(schemaerr.ValidationError)) {
+	environments := make([]string, 0, len(s.Spec.Overrides))
+	for env := range s.Spec.Overrides {
+		environments = append(environments, env)
+	}
+	sort.Strings(environments)
+
+	for _, env := range environments {
+		if env == "" {
+			report(schemaerr.ErrInvalidValue("spec.overrides", "environment name must not be empty"))
+			continue
+		}
+		sourceNames := make([]string, 0, len(s.Spec.Overrides[env]))
+		for name := range s.Spec.Overrides[env] {
+			sourceNames = append(sourceNames, name)
+		}
+		sort.Strings(sourceNames)
+
+		for _, name := range sourceNames {
+			field := fmt.Sprintf("spec.overrides.%s.%s", env, name)
+			source, ok := s.getSource(name)
+			if !ok {
+				report(schemaerr.ErrInvalidValue(field, "override references unknown source "+name))
+				continue
+			}
+			override, ok := s.Spec.Overrides[env][name].(map[string]any)
+			if !ok {
+				report(schemaerr.ErrInvalidValue(field, "override must be an object"))
+				continue
+			}
+			reportEach(report, validateRunnerConfig(source.Runner, mergeConfig(source.Config, override), field))
+		}
+	}
+}
+
+func (s *SkillSet) getSource(name string) (SkillSetSource, bool) {
+	for _, source := range s.Spec.Sources {
+		if source.Name == name {
+			return source, true
+		}
+	}
+	return SkillSetSource{}, false
+}
+
+// mergeConfig returns a copy of config with override merged in. Nested objects are merged
+// recursively; any other override value replaces the value in config.
+func mergeConfig(config, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(config)+len(override))
+	for k, v := range config {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]any); ok {
+			if configMap, ok := merged[k].(map[string]any); ok {
+				merged[k] = mergeConfig(configMap, overrideMap)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
 // validateSkills validates all skills in the skillset
-func (s *SkillSet) validateSkills() schemaerr.ValidationErrors {
-	var validationErrors schemaerr.ValidationErrors
+func (s *SkillSet) validateSkills(report func(schemaerr.ValidationError)) {
+	for i, skill := range s.Spec.Skills {
+		field := fmt.Sprintf("spec.skills[%d]", i)
 
-	for _, skill := range s.Spec.Skills {
 		// Validate skill has a runner
 		if !s.hasRunnerForSkill(skill) {
-			validationErrors = append(validationErrors,
-				schemaerr.ErrValidationFailed(fmt.Sprintf("skill %s has no runner", skill.Name)))
+			report(schemaerr.ErrInvalidValue(field+".source", fmt.Sprintf("skill %s has no runner", skill.Name)))
 		}
 
 		// Validate input schema
 		if len(skill.InputSchema) > 0 {
 			if err := s.validateSchema(skill.InputSchema); err != nil {
-				validationErrors = append(validationErrors,
-					schemaerr.ErrValidationFailed(fmt.Sprintf("skill %s input schema: %v", skill.Name, err)))
+				report(schemaerr.ErrInvalidValue(field+".inputSchema", fmt.Sprintf("skill %s input schema: %v", skill.Name, err)))
 			}
 		}
 
 		// Validate output schema
 		if len(skill.OutputSchema) > 0 {
 			if err := s.validateSchema(skill.OutputSchema); err != nil {
-				validationErrors = append(validationErrors,
-					schemaerr.ErrValidationFailed(fmt.Sprintf("skill %s output schema: %v", skill.Name, err)))
+				report(schemaerr.ErrInvalidValue(field+".outputSchema", fmt.Sprintf("skill %s output schema: %v", skill.Name, err)))
 			}
 		}
 
+		if skill.ValidateOutput && (len(skill.OutputSchema) == 0 || string(skill.OutputSchema) == "null") {
+			report(schemaerr.ErrInvalidValue(field+".validateOutput", fmt.Sprintf("skill %s enables validateOutput but has no output schema", skill.Name)))
+		}
+
+		if skill.MaxConcurrent < 0 {
+			report(schemaerr.ErrInvalidValue(field+".maxConcurrent", fmt.Sprintf("skill %s maxConcurrent must not be negative", skill.Name)))
+		}
+
+		if skill.MaxRestarts < 0 {
+			report(schemaerr.ErrInvalidValue(field+".maxRestarts", fmt.Sprintf("skill %s maxRestarts must not be negative", skill.Name)))
+		}
+
+		if err := skill.validateCache(); err != nil {
+			report(schemaerr.ErrInvalidValue(field+".cacheTTL", fmt.Sprintf("skill %s: %v", skill.Name, err)))
+		}
+
+		if err := skill.validateMetricTags(); err != nil {
+			report(schemaerr.ErrInvalidValue(field+".metricTags", fmt.Sprintf("skill %s: %v", skill.Name, err)))
+		}
+
+		// Validate default input args
+		if err := skill.validateDefaultInputArgs(); err != nil {
+			report(schemaerr.ErrInvalidValue(field+".defaultInputArgs", fmt.Sprintf("skill %s default input args: %v", skill.Name, err)))
+		}
+
+		// Validate redacted input paths
+		if err := skill.validateRedactInputPaths(); err != nil {
+			report(schemaerr.ErrInvalidValue(field+".redactInputPaths", fmt.Sprintf("skill %s: %v", skill.Name, err)))
+		}
+
+		// Validate output examples
+		if err := skill.validateOutputExamples(); err != nil {
+			report(schemaerr.ErrInvalidValue(field+".annotations", fmt.Sprintf("skill %s output examples: %v", skill.Name, err)))
+		}
+
+		// Validate result format
+		if _, err := skill.GetResultFormat(); err != nil {
+			report(schemaerr.ErrInvalidValue(field+".annotations", fmt.Sprintf("skill %s: %v", skill.Name, err)))
+		}
+
 		// Validate transform
 		if !skill.Transform.IsNil() {
 			if err := s.validateTransform(skill.Transform); err != nil {
-				validationErrors = append(validationErrors,
-					schemaerr.ErrValidationFailed(fmt.Sprintf("skill %s transform: %v", skill.Name, err)))
+				report(schemaerr.ErrInvalidValue(field+".transform", fmt.Sprintf("skill %s transform: %v", skill.Name, err)))
 			}
 		}
 	}
+}
 
-	return validationErrors
+// LoadCatalogSpec loads the settings of the skillset's catalog that Validate enforces: the
+// annotation schema of skill annotations and the default actions of dependencies. Without them,
+// skill annotations are not checked and dependencies may omit their actions.
+func (s *SkillSet) LoadCatalogSpec(ctx context.Context) apperrors.Error {
+	s.catalogSpec = nil
+	if s.Metadata.Catalog == "" {
+		return nil
+	}
+	spec, err := loadCatalogSpec(ctx, s.Metadata.Catalog)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		spec = &CatalogSpec{}
+	}
+	s.catalogSpec = spec
+	return nil
+}
+
+// validateAnnotations validates skill annotations against the annotation schema of the
+// skillset's catalog. Does nothing if the catalog has no annotation schema.
+func (s *SkillSet) validateAnnotations(report func(schemaerr.ValidationError)) {
+	if s.catalogSpec == nil || s.catalogSpec.Annotations == nil {
+		return
+	}
+	for i, skill := range s.Spec.Skills {
+		field := fmt.Sprintf("spec.skills[%d].annotations", i)
+		for _, err := range s.catalogSpec.Annotations.CheckAnnotations(skill.Annotations) {
+			report(schemaerr.ErrInvalidValue(field, fmt.Sprintf("skill %s: %v", skill.Name, err)))
+		}
+	}
+}
+
+// reservedSkillSetNames are the sub-resources served under the path of a skillset or of one of
+// its skills. A skillset with one of these names would be shadowed by the sub-resource of its
+// parent's path.
+var reservedSkillSetNames = []string{"describe", "export", "diff", "transform", "rename"}
+
+// IsReservedSkillSetName reports whether name is reserved for a skillset sub-resource.
+func IsReservedSkillSetName(name string) bool {
+	return slices.Contains(reservedSkillSetNames, name)
+}
+
+// validateName validates that the skillset name is not reserved
+func (s *SkillSet) validateName(report func(schemaerr.ValidationError)) {
+	if IsReservedSkillSetName(s.Metadata.Name) {
+		report(schemaerr.ErrInvalidValue("metadata.name", fmt.Sprintf("%s is reserved and cannot be used as a skillset name", s.Metadata.Name)))
+	}
+}
+
+// validateSkillNames validates that no skill name or alias is used more than once in the skillset
+func (s *SkillSet) validateSkillNames(report func(schemaerr.ValidationError)) {
+	owners := make(map[string]string)
+	for i, skill := range s.Spec.Skills {
+		if _, ok := owners[skill.Name]; ok {
+			report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.skills[%d].name", i), fmt.Sprintf("duplicate skill name %s", skill.Name)))
+			continue
+		}
+		owners[skill.Name] = skill.Name
+	}
+	for i, skill := range s.Spec.Skills {
+		for j, alias := range skill.Aliases {
+			if owner, ok := owners[alias]; ok {
+				report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.skills[%d].aliases[%d]", i, j), fmt.Sprintf("skill %s alias %s collides with skill %s", skill.Name, alias, owner)))
+				continue
+			}
+			owners[alias] = skill.Name
+		}
+	}
+}
+
+// validatePreflight validates that the preflight skill is a skill of the skillset
+func (s *SkillSet) validatePreflight(report func(schemaerr.ValidationError)) {
+	if s.Spec.Preflight == "" {
+		return
+	}
+	for _, skill := range s.Spec.Skills {
+		if skill.Name == s.Spec.Preflight {
+			return
+		}
+	}
+	report(schemaerr.ErrInvalidValue("spec.preflight", fmt.Sprintf("skill %s is not defined in the skillset", s.Spec.Preflight)))
 }
 
 // validateContexts validates all contexts in the skillset
-func (s *SkillSet) validateContexts() schemaerr.ValidationErrors {
-	var validationErrors schemaerr.ValidationErrors
+func (s *SkillSet) validateContexts(report func(schemaerr.ValidationError)) {
+	for i, ctx := range s.Spec.Context {
+		switch ctx.FailMode {
+		case "", ContextFailOpen, ContextFailClosed:
+		default:
+			report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.context[%d].failMode", i), "must be one of open, closed"))
+		}
+
+		// Validate context value against the declared format
+		if err := validateContextFormat(ctx.Format, ctx.Value); err != nil {
+			report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.context[%d].value", i), fmt.Sprintf("context %s format: %v", ctx.Name, err)))
+		}
 
-	for _, ctx := range s.Spec.Context {
 		if len(ctx.Schema) > 0 {
-			compiledSchema, err := compileSchema(string(ctx.Schema))
+			compiledSchema, err := compileSchemaWithDraft(string(ctx.Schema), s.Spec.SchemaDraft)
 			if err != nil {
-				validationErrors = append(validationErrors,
-					schemaerr.ErrValidationFailed(fmt.Sprintf("context %s schema: %v", ctx.Name, err)))
+				report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.context[%d].schema", i), fmt.Sprintf("context %s schema: %v", ctx.Name, err)))
 				continue
 			}
 
 			// Validate context value against schema if present
 			if !ctx.Value.IsNil() {
+				if err := CheckJSONDepth("context value", ctx.Value.Get()); err != nil {
+					report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.context[%d].value", i), fmt.Sprintf("context %s value: %v", ctx.Name, err)))
+					continue
+				}
 				if err := compiledSchema.Validate(ctx.Value.Get()); err != nil {
-					validationErrors = append(validationErrors,
-						schemaerr.ErrValidationFailed(fmt.Sprintf("context %s value: %v", ctx.Name, err)))
+					report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.context[%d].value", i), fmt.Sprintf("context %s value: %v", ctx.Name, err)))
 				}
 			}
 		}
 	}
+}
 
-	return validationErrors
+// validateDependencies validates the targets and conditions of the skillset's dependencies
+func (s *SkillSet) validateDependencies(report func(schemaerr.ValidationError)) {
+	for i, dep := range s.Spec.Dependencies {
+		reportEach(report, dep.validate(fmt.Sprintf("spec.dependencies[%d]", i)))
+		if len(dep.Actions) == 0 && s.catalogSpec != nil && len(s.catalogSpec.DependencyDefaults[dep.Kind]) == 0 {
+			report(schemaerr.ErrInvalidValue(fmt.Sprintf("spec.dependencies[%d].actions", i),
+				fmt.Sprintf("actions are required since catalog %s declares no default actions for %s dependencies", s.Metadata.Catalog, dep.Kind)))
+		}
+		if dep.When != nil {
+			reportEach(report, dep.When.validate(fmt.Sprintf("spec.dependencies[%d].when", i)))
+		}
+	}
 }
 
 // hasRunnerForSkill checks if a skill has a corresponding runner
@@ -581,7 +1559,7 @@ func (s *SkillSet) hasRunnerForSkill(skill Skill) bool {
 
 // validateSchema validates a JSON schema
 func (s *SkillSet) validateSchema(schema json.RawMessage) error {
-	_, err := compileSchema(string(schema))
+	_, err := compileSchemaWithDraft(string(schema), s.Spec.SchemaDraft)
 	return err
 }
 
@@ -1,7 +1,9 @@
 package catalogmanager
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"path"
 	"strings"
 	"testing"
@@ -9,15 +11,19 @@ import (
 	"encoding/json"
 
 	"github.com/jackc/pgtype"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	schemaerr "github.com/tansive/tansive/internal/catalogsrv/schema/errors"
 	_ "github.com/tansive/tansive/internal/catalogsrv/schema/schemavalidator"
+	"github.com/tansive/tansive/pkg/api"
 	"github.com/tansive/tansive/pkg/types"
 )
 
@@ -383,6 +389,44 @@ func TestSkillSetValidation(t *testing.T) {
 			}`,
 			expectedError: false,
 		},
+		{
+			name: "invalid skillset - command runner config missing command",
+			jsonInput: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "SkillSet",
+				"metadata": {
+					"name": "test-skillset",
+					"catalog": "test-catalog",
+					"namespace": "default",
+					"variant": "default",
+					"path": "/skillsets/test-skillset"
+				},
+				"spec": {
+					"version": "1.0.0",
+					"sources": [
+						{
+							"name": "command-runner",
+							"runner": "system.commandrunner",
+							"config": {
+								"args": ["test.py"]
+							}
+						}
+					],
+					"skills": [
+						{
+							"name": "test-skill",
+							"description": "A test skill",
+							"source": "command-runner",
+							"inputSchema": {"type": "object"},
+							"outputSchema": {"type": "object"},
+							"exportedActions": ["test.action"]
+						}
+					]
+				}
+			}`,
+			expectedError: true,
+			errorTypes:    []string{"spec.sources[0].config.command: missing required attribute"},
+		},
 		{
 			name: "valid skillset with system.mcp.stdio runner",
 			jsonInput: `{
@@ -598,6 +642,26 @@ func TestSkillSetManagerSave(t *testing.T) {
 		require.Equal(t, []policy.Action{"read"}, metadata.Dependencies[0].Actions)
 	})
 
+	t.Run("update of a skillset modified since it was loaded is rejected", func(t *testing.T) {
+		existing, err := LoadSkillSetManagerByPath(ctx, &metadata)
+		require.NoError(t, err)
+
+		// another request saves the skillset after it was loaded
+		other := &skillSetManager{skillSet: manager.skillSet}
+		other.skillSet.Metadata.Description = "updated by another request"
+		require.NoError(t, other.Save(ctx))
+
+		stale := &skillSetManager{skillSet: manager.skillSet}
+		stale.skillSet.Metadata.Description = "stale update"
+		assert.ErrorIs(t, saveReplacing(ctx, existing, stale), ErrConcurrentUpdate)
+
+		current, err := LoadSkillSetManagerByPath(ctx, &metadata)
+		require.NoError(t, err)
+		assert.Equal(t, "updated by another request", current.Metadata().Description)
+		stale.skillSet.Metadata.Description = "fresh update"
+		assert.NoError(t, saveReplacing(ctx, current, stale))
+	})
+
 	t.Run("saves skillset with multiple skills in metadata", func(t *testing.T) {
 		// Create a skillset with multiple skills
 		ss := &SkillSet{
@@ -909,6 +973,78 @@ func TestSkillValidateInput(t *testing.T) {
 	}
 }
 
+func TestSkillValidateInputFormat(t *testing.T) {
+	newSkill := func(inputSchema string) Skill {
+		return Skill{
+			Name:            "test-skill",
+			Description:     "A test skill",
+			Source:          "command-runner",
+			InputSchema:     json.RawMessage(inputSchema),
+			OutputSchema:    json.RawMessage(`{"type": "object"}`),
+			ExportedActions: []policy.Action{"test.action"},
+		}
+	}
+	properties := `"properties": {
+		"email": {"type": "string", "format": "email"},
+		"id": {"type": "string", "format": "uuid"},
+		"at": {"type": "string", "format": "date-time"},
+		"link": {"type": "string", "format": "uri"}
+	}`
+	valid := map[string]any{
+		"email": "user@example.com",
+		"id":    "8e1b9d3c-5f0a-4c2e-9b7d-1a2b3c4d5e6f",
+		"at":    "2025-01-02T03:04:05Z",
+		"link":  "https://example.com/path",
+	}
+
+	// Formats are asserted unless the schema declares a draft that treats them as annotations
+	byDefault := newSkill(`{"type": "object", ` + properties + `}`)
+	assert.NoError(t, byDefault.ValidateInput(valid))
+	assert.ErrorIs(t, byDefault.ValidateInput(map[string]any{"email": "not-an-email"}), ErrInvalidInput)
+	annotative := newSkill(`{"type": "object", "$schema": "https://json-schema.org/draft/2020-12/schema", ` + properties + `}`)
+	assert.NoError(t, annotative.ValidateInput(map[string]any{"email": "not-an-email"}))
+
+	asserted := newSkill(`{"type": "object", "$schema": "https://json-schema.org/draft/2020-12/schema", "x-assertFormat": true, ` + properties + `}`)
+	assert.NoError(t, asserted.ValidateInput(valid))
+	for field, bad := range map[string]string{
+		"email": "not-an-email",
+		"id":    "1234",
+		"at":    "yesterday",
+		"link":  "not a uri",
+	} {
+		input := map[string]any{field: bad}
+		err := asserted.ValidateInput(input)
+		require.Error(t, err, field)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	}
+
+	disabled := newSkill(`{"type": "object", "x-assertFormat": false, ` + properties + `}`)
+	assert.NoError(t, disabled.ValidateInput(map[string]any{"email": "not-an-email"}))
+
+	_, err := compileSchema(`{"type": "object", "x-assertFormat": "yes"}`)
+	assert.ErrorContains(t, err, "x-assertFormat must be a boolean")
+
+	// The override applies only to the schema that opts out
+	emailSchema := `{"type": "string", "format": "email"%s}`
+	annotativeSchema, err := compileSchema(fmt.Sprintf(emailSchema, `, "x-assertFormat": false`))
+	require.NoError(t, err)
+	assertedSchema, err := compileSchema(fmt.Sprintf(emailSchema, ""))
+	require.NoError(t, err)
+	assert.NoError(t, annotativeSchema.Validate("not-an-email"))
+	assert.Error(t, assertedSchema.Validate("not-an-email"))
+	assert.False(t, jsonschema.Formats["email"]("not-an-email"))
+
+	// Schemas declaring a draft that treats formats as annotations are compiled without an override
+	for _, draft := range []string{"https://json-schema.org/draft/2019-09/schema", "https://json-schema.org/draft/2020-12/schema"} {
+		schema := fmt.Sprintf(emailSchema, `, "$schema": "`+draft+`"`)
+		assert.False(t, assertsFormatByDefault(schema), draft)
+		compiled, err := compileSchema(schema)
+		require.NoError(t, err, draft)
+		assert.NoError(t, compiled.Validate("not-an-email"), draft)
+	}
+	assert.True(t, assertsFormatByDefault(fmt.Sprintf(emailSchema, `, "$schema": "http://json-schema.org/draft-07/schema#"`)))
+}
+
 func TestSkillSetManagerContextOperations(t *testing.T) {
 	validJSON := `{
 		"apiVersion": "0.1.0-alpha.1",
@@ -1034,6 +1170,29 @@ func TestSkillSetManagerContextOperations(t *testing.T) {
 		assert.Error(t, appErr)
 	})
 
+	t.Run("ContextOverrides - validated and applied together", func(t *testing.T) {
+		valid := map[string]any{"test-context": map[string]any{"name": "Override"}}
+		assert.NoError(t, manager.ValidateContextOverrides(valid))
+		assert.NoError(t, manager.ValidateContextOverrides(nil))
+
+		// an invalid override leaves every context unchanged
+		invalid := map[string]any{
+			"test-context": map[string]any{"name": "Override"},
+			"non-existent": "value",
+		}
+		assert.Error(t, manager.ValidateContextOverrides(invalid))
+		assert.Error(t, manager.ApplyContextOverrides(invalid))
+		value, appErr := manager.GetContextValue("test-context")
+		require.NoError(t, appErr)
+		assert.Equal(t, "Jane", value.Get().(map[string]any)["name"])
+		assert.Error(t, manager.ApplyContextOverrides(map[string]any{"test-context": map[string]any{"age": 1}}))
+
+		require.NoError(t, manager.ApplyContextOverrides(valid))
+		value, appErr = manager.GetContextValue("test-context")
+		require.NoError(t, appErr)
+		assert.Equal(t, map[string]any{"name": "Override"}, value.Get())
+	})
+
 	t.Run("GetContextValue - with viewDef and exported actions", func(t *testing.T) {
 		// Set up a context with exported actions and valueByAction
 		barVal, err := types.NullableAnyFrom(map[string]any{"foo": "bar"})
@@ -1091,3 +1250,1542 @@ func TestSkillSetManagerContextOperations(t *testing.T) {
 		assert.True(t, value.IsNil())
 	})
 }
+
+func TestSkillSetManagerContextFormat(t *testing.T) {
+	manager := &skillSetManager{}
+	manager.skillSet.Spec.Context = []SkillSetContext{
+		{
+			Name:   "cert-context",
+			Schema: json.RawMessage(`{"type": "string"}`),
+			Format: "base64",
+			Value:  types.NilAny(),
+		},
+		{
+			Name:   "config-context",
+			Schema: json.RawMessage(`{"type": "string"}`),
+			Format: "yaml",
+			Value:  types.NilAny(),
+		},
+	}
+
+	t.Run("base64 - valid value", func(t *testing.T) {
+		value, err := types.NullableAnyFrom("aGVsbG8gd29ybGQ=")
+		require.NoError(t, err)
+		assert.NoError(t, manager.SetContextValue("cert-context", value))
+	})
+
+	t.Run("base64 - malformed value", func(t *testing.T) {
+		value, err := types.NullableAnyFrom("not base64!!")
+		require.NoError(t, err)
+		appErr := manager.SetContextValue("cert-context", value)
+		assert.Error(t, appErr)
+		assert.ErrorIs(t, appErr, ErrInvalidObject)
+
+		// the previous value is retained
+		current, appErr := manager.GetContextValue("cert-context")
+		require.NoError(t, appErr)
+		assert.Equal(t, "aGVsbG8gd29ybGQ=", current.Get())
+	})
+
+	t.Run("yaml - valid and malformed values", func(t *testing.T) {
+		value, err := types.NullableAnyFrom("key: value\nlist:\n  - a\n")
+		require.NoError(t, err)
+		assert.NoError(t, manager.SetContextValue("config-context", value))
+
+		value, err = types.NullableAnyFrom("key: [unterminated")
+		require.NoError(t, err)
+		assert.Error(t, manager.SetContextValue("config-context", value))
+	})
+
+	t.Run("custom format validator", func(t *testing.T) {
+		RegisterContextFormatValidator("uppercase", func(value any) error {
+			s, _ := value.(string)
+			if s != strings.ToUpper(s) {
+				return errors.New("value must be uppercase")
+			}
+			return nil
+		})
+		manager.skillSet.Spec.Context = append(manager.skillSet.Spec.Context, SkillSetContext{
+			Name:   "upper-context",
+			Schema: json.RawMessage(`{"type": "string"}`),
+			Format: "uppercase",
+			Value:  types.NilAny(),
+		})
+		value, err := types.NullableAnyFrom("ABC")
+		require.NoError(t, err)
+		assert.NoError(t, manager.SetContextValue("upper-context", value))
+		value, err = types.NullableAnyFrom("abc")
+		require.NoError(t, err)
+		assert.Error(t, manager.SetContextValue("upper-context", value))
+	})
+
+	t.Run("unsupported format fails skillset validation", func(t *testing.T) {
+		ss := SkillSet{
+			Spec: SkillSetSpec{
+				Context: []SkillSetContext{
+					{Name: "bad-format", Schema: json.RawMessage(`{"type": "string"}`), Format: "xml"},
+				},
+			},
+		}
+		assert.NotEmpty(t, collectValidationErrors(ss.validateContexts))
+	})
+}
+
+func TestContextFailModeValidation(t *testing.T) {
+	newSkillSet := func(failMode ContextFailMode) SkillSet {
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Context: []SkillSetContext{
+					{Name: "advisory", Schema: json.RawMessage(`{"type": "object"}`), FailMode: failMode},
+				},
+			},
+		}
+	}
+	for _, failMode := range []ContextFailMode{"", ContextFailOpen, ContextFailClosed} {
+		ss := newSkillSet(failMode)
+		assert.Empty(t, collectValidationErrors(ss.validateContexts), failMode)
+	}
+	ss := newSkillSet("sometimes")
+	errs := collectValidationErrors(ss.validateContexts)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs.Error(), "spec.context[0].failMode")
+
+	assert.True(t, SkillSetContext{FailMode: ContextFailOpen}.FailsOpen())
+	assert.False(t, SkillSetContext{FailMode: ContextFailClosed}.FailsOpen())
+	assert.False(t, SkillSetContext{}.FailsOpen())
+}
+
+func TestSkillOutputExamples(t *testing.T) {
+	newSkillSet := func(examples string) SkillSet {
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{{Name: "runner"}},
+				Skills: []Skill{
+					{
+						Name:         "get-status",
+						Source:       "runner",
+						OutputSchema: json.RawMessage(`{"type": "object", "properties": {"status": {"type": "string"}}, "required": ["status"]}`),
+						Annotations: map[string]string{
+							"llm:description":     "Get the status",
+							LLMExamplesAnnotation: examples,
+						},
+						ExportedActions: []policy.Action{"test.action"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("examples are included in LLM tools", func(t *testing.T) {
+		ss := newSkillSet(`[{"status": "ok"}, {"status": "degraded"}]`)
+		require.Empty(t, collectValidationErrors(ss.validateSkills))
+
+		manager := &skillSetManager{skillSet: ss}
+		tools := manager.GetAllSkillsAsLLMTools(nil, "")
+		require.Len(t, tools, 1)
+		require.Len(t, tools[0].Examples, 2)
+		assert.JSONEq(t, `{"status": "ok"}`, string(tools[0].Examples[0]))
+
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(tools[0].OutputSchema, &schema))
+		assert.Equal(t, []any{map[string]any{"status": "ok"}, map[string]any{"status": "degraded"}}, schema["examples"])
+		assert.Equal(t, "object", schema["type"])
+	})
+
+	t.Run("example not matching output schema fails validation", func(t *testing.T) {
+		ss := newSkillSet(`[{"code": 200}]`)
+		assert.NotEmpty(t, collectValidationErrors(ss.validateSkills))
+	})
+
+	t.Run("malformed examples fail validation", func(t *testing.T) {
+		ss := newSkillSet(`{"status": "ok"}`)
+		assert.NotEmpty(t, collectValidationErrors(ss.validateSkills))
+	})
+
+	t.Run("no examples leaves output schema unchanged", func(t *testing.T) {
+		ss := newSkillSet("")
+		manager := &skillSetManager{skillSet: ss}
+		tools := manager.GetAllSkillsAsLLMTools(nil, "")
+		require.Len(t, tools, 1)
+		assert.Empty(t, tools[0].Examples)
+		assert.JSONEq(t, string(ss.Spec.Skills[0].OutputSchema), string(tools[0].OutputSchema))
+	})
+}
+
+func TestSkillResultFormat(t *testing.T) {
+	newSkillSet := func(format string) SkillSet {
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{{Name: "runner"}},
+				Skills: []Skill{
+					{
+						Name:   "get-report",
+						Source: "runner",
+						Annotations: map[string]string{
+							"llm:description":         "Get the report",
+							LLMResultFormatAnnotation: format,
+						},
+						ExportedActions: []policy.Action{"test.action"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("declared format is included in LLM tools", func(t *testing.T) {
+		for _, format := range []api.ResultFormat{api.ResultFormatJSON, api.ResultFormatText, api.ResultFormatMarkdown} {
+			ss := newSkillSet(string(format))
+			require.Empty(t, collectValidationErrors(ss.validateSkills), format)
+			manager := &skillSetManager{skillSet: ss}
+			tools := manager.GetAllSkillsAsLLMTools(nil, "")
+			require.Len(t, tools, 1)
+			assert.Equal(t, format, tools[0].ResultFormat)
+		}
+	})
+
+	t.Run("no format leaves the tool's format unset", func(t *testing.T) {
+		ss := newSkillSet("")
+		require.Empty(t, collectValidationErrors(ss.validateSkills))
+		manager := &skillSetManager{skillSet: ss}
+		tools := manager.GetAllSkillsAsLLMTools(nil, "")
+		require.Len(t, tools, 1)
+		assert.Empty(t, tools[0].ResultFormat)
+	})
+
+	t.Run("unknown format fails validation", func(t *testing.T) {
+		ss := newSkillSet("html")
+		errs := collectValidationErrors(ss.validateSkills)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs.Error(), "spec.skills[0].annotations")
+		assert.Contains(t, errs.Error(), LLMResultFormatAnnotation)
+	})
+}
+
+func TestSkillSetSchemaDraft(t *testing.T) {
+	// prefixItems is a 2020-12 keyword and is ignored by earlier drafts
+	newSkillSet := func(draft SchemaDraft) SkillSet {
+		spec := fmt.Sprintf(`{
+			"version": "1.0.0",
+			"schemaDraft": %q,
+			"sources": [{"name": "runner", "runner": "system.testrunner", "config": {}}],
+			"skills": [{
+				"name": "add-pair",
+				"source": "runner",
+				"inputSchema": {
+					"type": "object",
+					"properties": {"pair": {"type": "array", "prefixItems": [{"type": "integer"}, {"type": "integer"}]}}
+				},
+				"exportedActions": ["test.action"]
+			}]
+		}`, draft)
+		ss := SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "math-tools", Catalog: "test-catalog", Path: "/"},
+		}
+		require.NoError(t, json.Unmarshal([]byte(spec), &ss.Spec))
+		return ss
+	}
+	input := map[string]any{"pair": []any{"one", "two"}}
+
+	t.Run("declared draft applies its keywords", func(t *testing.T) {
+		ss := newSkillSet(SchemaDraft2020)
+		require.Empty(t, ss.Validate())
+		manager := &skillSetManager{skillSet: ss}
+		skill, err := manager.GetSkill("add-pair")
+		require.NoError(t, err)
+		assert.Error(t, skill.ValidateInput(input))
+		assert.NoError(t, skill.ValidateInput(map[string]any{"pair": []any{1, 2}}))
+	})
+
+	t.Run("earlier draft ignores later keywords", func(t *testing.T) {
+		ss := newSkillSet(SchemaDraft7)
+		require.Empty(t, ss.Validate())
+		manager := &skillSetManager{skillSet: ss}
+		skill, err := manager.GetSkill("add-pair")
+		require.NoError(t, err)
+		assert.NoError(t, skill.ValidateInput(input))
+	})
+
+	t.Run("draft is kept when fields are selected", func(t *testing.T) {
+		ss := newSkillSet(SchemaDraft7)
+		data, goerr := json.Marshal(ss)
+		require.NoError(t, goerr)
+		selected, err := SelectSkillSetFields(data, []string{"skills"})
+		require.NoError(t, err)
+		var parsed SkillSet
+		require.NoError(t, json.Unmarshal(selected, &parsed))
+		assert.Equal(t, SchemaDraft7, parsed.Spec.SchemaDraft)
+		assert.NoError(t, parsed.Spec.Skills[0].ValidateInput(input))
+	})
+
+	t.Run("unsupported draft fails validation", func(t *testing.T) {
+		ss := newSkillSet("draft-03")
+		errs := ss.Validate()
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs.Error(), "spec.schemaDraft")
+	})
+}
+
+func TestSkillMetricTags(t *testing.T) {
+	newSkillSet := func(tags map[string]string) SkillSet {
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{{Name: "runner"}},
+				Skills: []Skill{
+					{
+						Name:            "deploy",
+						Source:          "runner",
+						MetricTags:      tags,
+						ExportedActions: []policy.Action{"test.action"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("valid tags", func(t *testing.T) {
+		ss := newSkillSet(map[string]string{"team": "platform", "pipeline_id": "release-1"})
+		assert.Empty(t, collectValidationErrors(ss.validateSkills))
+	})
+
+	tooMany := map[string]string{}
+	for i := 0; i <= MaxSkillMetricTags; i++ {
+		tooMany[fmt.Sprintf("tag_%d", i)] = "value"
+	}
+	invalid := []struct {
+		name string
+		tags map[string]string
+	}{
+		{"invalid key", map[string]string{"team-name": "platform"}},
+		{"key starting with a digit", map[string]string{"1team": "platform"}},
+		{"double underscore prefix", map[string]string{"__name__": "platform"}},
+		{"reserved key", map[string]string{"skill": "other"}},
+		{"empty value", map[string]string{"team": " "}},
+		{"long value", map[string]string{"team": strings.Repeat("a", 129)}},
+		{"too many tags", tooMany},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			ss := newSkillSet(tc.tags)
+			errs := collectValidationErrors(ss.validateSkills)
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs.Error(), "spec.skills[0].metricTags")
+		})
+	}
+}
+
+func TestSkillRedactInputPaths(t *testing.T) {
+	inputSchema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"user": {"type": "string"},
+			"credentials": {
+				"type": "object",
+				"properties": {"password": {"type": "string"}, "a/b": {"type": "string"}}
+			},
+			"headers": {"type": "object", "additionalProperties": {"type": "string"}},
+			"tokens": {"type": "array", "items": {"type": "string"}}
+		}
+	}`)
+	newSkillSet := func(schema json.RawMessage, paths ...string) SkillSet {
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{{Name: "runner"}},
+				Skills: []Skill{
+					{
+						Name:             "login",
+						Source:           "runner",
+						InputSchema:      schema,
+						RedactInputPaths: paths,
+						ExportedActions:  []policy.Action{"test.action"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("valid paths", func(t *testing.T) {
+		ss := newSkillSet(inputSchema, "/credentials/password", "/credentials/a~1b", "/headers/authorization", "/tokens/0")
+		assert.Empty(t, collectValidationErrors(ss.validateSkills))
+	})
+
+	invalid := []struct {
+		name   string
+		schema json.RawMessage
+		path   string
+	}{
+		{"path not in schema", inputSchema, "/credentials/token"},
+		{"path below a string", inputSchema, "/user/name"},
+		{"not a JSON pointer", inputSchema, "credentials/password"},
+		{"no input schema", nil, "/password"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			ss := newSkillSet(tc.schema, tc.path)
+			errs := collectValidationErrors(ss.validateSkills)
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs.Error(), "spec.skills[0].redactInputPaths")
+		})
+	}
+
+	t.Run("redacts input without modifying it", func(t *testing.T) {
+		skill := &Skill{RedactInputPaths: []string{"/credentials/password", "/tokens/1", "/missing"}}
+		input := map[string]any{
+			"user":        "jane",
+			"credentials": map[string]any{"password": "hunter2"},
+			"tokens":      []any{"a", "b"},
+		}
+		redacted := skill.RedactInput(input)
+		assert.Equal(t, map[string]any{
+			"user":        "jane",
+			"credentials": map[string]any{"password": RedactedInputValue},
+			"tokens":      []any{"a", RedactedInputValue},
+		}, redacted)
+		assert.Equal(t, map[string]any{
+			"user":        "jane",
+			"credentials": map[string]any{"password": "hunter2"},
+			"tokens":      []any{"a", "b"},
+		}, input)
+	})
+
+	t.Run("keys are normalized before redacting", func(t *testing.T) {
+		skill := &Skill{InputKeyStyle: InputKeyStyleSnake, RedactInputPaths: []string{"/api_key"}}
+		redacted := skill.RedactInput(map[string]any{"apiKey": "s3cr3t", "userName": "jane"})
+		assert.Equal(t, map[string]any{
+			"api_key":   RedactedInputValue,
+			"user_name": "jane",
+		}, redacted)
+	})
+}
+
+func TestSkillCategories(t *testing.T) {
+	newSkillSet := func(categories ...string) SkillSet {
+		ss := SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "billing-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version: "1.0.0",
+				Sources: []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}}},
+			},
+		}
+		for i, category := range categories {
+			ss.Spec.Skills = append(ss.Spec.Skills, Skill{
+				Name:            fmt.Sprintf("skill-%d", i),
+				Category:        category,
+				Source:          "runner",
+				Annotations:     map[string]string{"llm:description": "A skill"},
+				ExportedActions: []policy.Action{"test.action"},
+			})
+		}
+		return ss
+	}
+
+	t.Run("category is included in LLM tools", func(t *testing.T) {
+		ss := newSkillSet("payments", "")
+		require.Empty(t, ss.Validate())
+		manager := &skillSetManager{skillSet: ss}
+		tools := manager.GetAllSkillsAsLLMTools(nil, "")
+		require.Len(t, tools, 2)
+		assert.Equal(t, "payments", tools[0].Category)
+		assert.Empty(t, tools[1].Category)
+
+		data, err := json.Marshal(tools[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"category":"payments"`)
+	})
+
+	t.Run("tools filtered by category", func(t *testing.T) {
+		manager := &skillSetManager{skillSet: newSkillSet("payments", "refunds", "payments", "")}
+		tools := manager.GetAllSkillsAsLLMTools(nil, "payments")
+		require.Len(t, tools, 2)
+		assert.Equal(t, "skill-0", tools[0].Name)
+		assert.Equal(t, "skill-2", tools[1].Name)
+		assert.Empty(t, manager.GetAllSkillsAsLLMTools(nil, "shipping"))
+	})
+
+	t.Run("invalid category fails validation", func(t *testing.T) {
+		for _, category := range []string{"Payments", "payments tools", "-payments", "payments_v2"} {
+			ss := newSkillSet(category)
+			assert.NotEmpty(t, ss.Validate(), category)
+		}
+	})
+}
+
+func TestPreflightSkill(t *testing.T) {
+	newSkillSet := func(preflight string) SkillSet {
+		return SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "deploy-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version:   "1.0.0",
+				Sources:   []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}}},
+				Preflight: preflight,
+				Skills: []Skill{
+					{Name: "authorize", Source: "runner", ExportedActions: []policy.Action{"test.action"}},
+					{Name: "deploy", Source: "runner", Aliases: []string{"ship"}, ExportedActions: []policy.Action{"test.action"}},
+				},
+			},
+		}
+	}
+
+	t.Run("preflight skill of the skillset", func(t *testing.T) {
+		ss := newSkillSet("authorize")
+		require.Empty(t, ss.Validate())
+		manager := &skillSetManager{skillSet: ss}
+		assert.Equal(t, "authorize", manager.GetPreflightSkill())
+	})
+
+	t.Run("no preflight skill", func(t *testing.T) {
+		ss := newSkillSet("")
+		require.Empty(t, ss.Validate())
+		assert.Empty(t, (&skillSetManager{skillSet: ss}).GetPreflightSkill())
+	})
+
+	for _, preflight := range []string{"check", "ship"} {
+		t.Run("undefined preflight skill "+preflight, func(t *testing.T) {
+			ss := newSkillSet(preflight)
+			errs := ss.Validate()
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs.Error(), "spec.preflight")
+		})
+	}
+}
+
+func TestSourceEnvFromVars(t *testing.T) {
+	newSkillSet := func(envFromVars map[string]string) SkillSet {
+		return SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "deploy-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version: "1.0.0",
+				Sources: []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}, EnvFromVars: envFromVars}},
+				Skills: []Skill{
+					{Name: "deploy", Source: "runner", ExportedActions: []policy.Action{"test.action"}},
+				},
+			},
+		}
+	}
+
+	t.Run("valid mapping", func(t *testing.T) {
+		ss := newSkillSet(map[string]string{"AWS_REGION": "region", "_API_KEY": "api-key"})
+		assert.Empty(t, ss.Validate())
+	})
+
+	invalid := map[string]map[string]string{
+		"invalid env name":   {"1REGION": "region"},
+		"env name with dash": {"AWS-REGION": "region"},
+		"empty variable key": {"AWS_REGION": ""},
+		"loader variable":    {"LD_PRELOAD": "region"},
+		"path":               {"PATH": "region"},
+		"home":               {"HOME": "region"},
+		"tansive variable":   {"TANSIVE_SERVER_URL": "region"},
+		"lowercase reserved": {"ld_preload": "region"},
+	}
+	for name, envFromVars := range invalid {
+		t.Run(name, func(t *testing.T) {
+			ss := newSkillSet(envFromVars)
+			errs := ss.Validate()
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs.Error(), "spec.sources[0].envFromVars")
+		})
+	}
+}
+
+func TestSkillAnnotationSchema(t *testing.T) {
+	schema := &AnnotationSchema{
+		Keys: []AnnotationKeySchema{
+			{Key: "owner", Required: true},
+			{Key: "llm:description"},
+			{Key: "llm:*", Values: []string{"text", "json"}},
+			{Key: "tier", Pattern: "[0-9]"},
+		},
+	}
+	newSkillSet := func(annotations map[string]string) SkillSet {
+		return SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "deploy-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version: "1.0.0",
+				Sources: []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}}},
+				Skills: []Skill{
+					{Name: "deploy", Source: "runner", Annotations: annotations, ExportedActions: []policy.Action{"test.action"}},
+				},
+			},
+		}
+	}
+
+	t.Run("missing required annotation passes without a schema", func(t *testing.T) {
+		ss := newSkillSet(map[string]string{"llm:descripton": "Deploys the service"})
+		assert.Empty(t, ss.Validate())
+	})
+
+	t.Run("valid annotations", func(t *testing.T) {
+		ss := newSkillSet(map[string]string{"owner": "platform", "llm:description": "Deploys the service", "llm:format": "json", "tier": "1"})
+		ss.catalogSpec = &CatalogSpec{Annotations: schema}
+		assert.Empty(t, ss.Validate())
+	})
+
+	invalid := map[string]map[string]string{
+		"missing required annotation": {"llm:description": "Deploys the service"},
+		"unknown annotation":          {"owner": "platform", "team": "infra"},
+		"disallowed prefix value":     {"owner": "platform", "llm:descripton": "Deploys the service"},
+		"value not matching pattern":  {"owner": "platform", "tier": "gold"},
+	}
+	for name, annotations := range invalid {
+		t.Run(name, func(t *testing.T) {
+			ss := newSkillSet(annotations)
+			ss.catalogSpec = &CatalogSpec{Annotations: schema}
+			errs := ss.Validate()
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs.Error(), "spec.skills[0].annotations")
+		})
+	}
+
+	t.Run("unknown annotations allowed", func(t *testing.T) {
+		ss := newSkillSet(map[string]string{"owner": "platform", "team": "infra"})
+		ss.catalogSpec = &CatalogSpec{Annotations: &AnnotationSchema{Keys: schema.Keys, AllowUnknown: true}}
+		assert.Empty(t, ss.Validate())
+	})
+}
+
+func TestAnnotationSchemaValidate(t *testing.T) {
+	valid := &AnnotationSchema{Keys: []AnnotationKeySchema{{Key: "owner", Required: true}, {Key: "llm:*", Pattern: "[a-z]+"}}}
+	assert.NoError(t, valid.Validate())
+
+	invalid := map[string][]AnnotationKeySchema{
+		"empty key":             {{Key: ""}},
+		"bare wildcard":         {{Key: "*"}},
+		"inner wildcard":        {{Key: "llm:*:x"}},
+		"duplicate key":         {{Key: "owner"}, {Key: "owner"}},
+		"required prefix key":   {{Key: "llm:*", Required: true}},
+		"invalid value pattern": {{Key: "tier", Pattern: "[0-9"}},
+	}
+	for name, keys := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, (&AnnotationSchema{Keys: keys}).Validate())
+		})
+	}
+}
+
+func TestDependencyDefaults(t *testing.T) {
+	defaults := DependencyDefaults{
+		KindResource: {"system.resource.read"},
+		KindSkillSet: {"system.skillset.use"},
+	}
+	require.NoError(t, defaults.Validate())
+	assert.Error(t, DependencyDefaults{"Tool": {"system.resource.read"}}.Validate())
+	assert.Error(t, DependencyDefaults{KindResource: {}}.Validate())
+
+	deps := []Dependency{
+		{Path: "/resources/config", Kind: KindResource, Alias: "config"},
+		{Path: "/resources/secrets", Kind: KindResource, Alias: "secrets", Actions: []policy.Action{"system.resource.edit"}},
+		{Path: "/skillsets/tools", Kind: KindSkillSet, Alias: "tools"},
+	}
+
+	t.Run("defaults apply to dependencies without actions", func(t *testing.T) {
+		applied := defaults.Apply(deps)
+		assert.Equal(t, []policy.Action{"system.resource.read"}, applied[0].Actions)
+		assert.Equal(t, []policy.Action{"system.skillset.use"}, applied[2].Actions)
+		assert.Empty(t, deps[0].Actions)
+	})
+
+	t.Run("explicit actions override defaults", func(t *testing.T) {
+		assert.Equal(t, []policy.Action{"system.resource.edit"}, defaults.Apply(deps)[1].Actions)
+		assert.Equal(t, []policy.Action{"system.resource.edit"}, defaults.ActionsFor(deps[1]))
+	})
+
+	t.Run("no defaults", func(t *testing.T) {
+		var none DependencyDefaults
+		assert.Empty(t, none.ActionsFor(deps[0]))
+		assert.Equal(t, []policy.Action{"system.resource.edit"}, none.ActionsFor(deps[1]))
+	})
+
+	t.Run("validation requires actions without a default", func(t *testing.T) {
+		ss := SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "deploy-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version:      "1.0.0",
+				Sources:      []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}}},
+				Skills:       []Skill{{Name: "deploy", Source: "runner", ExportedActions: []policy.Action{"test.action"}}},
+				Dependencies: deps,
+			},
+		}
+		// skillsets loaded without their catalog's settings are not checked
+		assert.Empty(t, ss.Validate())
+
+		ss.catalogSpec = &CatalogSpec{DependencyDefaults: defaults}
+		assert.Empty(t, ss.Validate())
+
+		ss.catalogSpec = &CatalogSpec{DependencyDefaults: DependencyDefaults{KindResource: {"system.resource.read"}}}
+		errs := ss.Validate()
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs.Error(), "spec.dependencies[2].actions")
+	})
+}
+
+func TestConditionalDependencies(t *testing.T) {
+	exists := true
+	newSkillSet := func(conditions ...*DependencyCondition) SkillSet {
+		ss := SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "deploy-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version: "1.0.0",
+				Sources: []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}}},
+				Skills: []Skill{
+					{Name: "deploy", Source: "runner", ExportedActions: []policy.Action{"test.action"}},
+				},
+			},
+		}
+		for i, condition := range conditions {
+			ss.Spec.Dependencies = append(ss.Spec.Dependencies, Dependency{
+				Path:    fmt.Sprintf("/resources/dep-%d", i),
+				Kind:    KindResource,
+				Alias:   fmt.Sprintf("dep-%d", i),
+				Actions: []policy.Action{"system.resource.read"},
+				When:    condition,
+			})
+		}
+		return ss
+	}
+
+	ss := newSkillSet(
+		nil,
+		&DependencyCondition{Variable: "mode", Equals: "prod"},
+		&DependencyCondition{Variable: "region", In: []any{"us-east-1", "us-west-2"}},
+		&DependencyCondition{Variable: "debug", Exists: &exists},
+	)
+	require.Empty(t, ss.Validate())
+	manager := &skillSetManager{skillSet: ss}
+	aliases := func(deps []Dependency) []string {
+		names := []string{}
+		for _, dep := range deps {
+			names = append(names, dep.Alias)
+		}
+		return names
+	}
+
+	t.Run("dependencies included when conditions match", func(t *testing.T) {
+		deps := manager.ResolveDependencies(map[string]any{"mode": "prod", "region": "us-west-2", "debug": false})
+		assert.Equal(t, []string{"dep-0", "dep-1", "dep-2", "dep-3"}, aliases(deps))
+	})
+
+	t.Run("dependencies skipped when conditions do not match", func(t *testing.T) {
+		deps := manager.ResolveDependencies(map[string]any{"mode": "dev", "region": "eu-west-1"})
+		assert.Equal(t, []string{"dep-0"}, aliases(deps))
+		assert.Equal(t, []string{"dep-0"}, aliases(manager.ResolveDependencies(nil)))
+	})
+
+	t.Run("condition round trips through JSON", func(t *testing.T) {
+		var dep Dependency
+		require.NoError(t, json.Unmarshal([]byte(`{"path": "/resources/db", "kind": "Resource", "alias": "db", "actions": ["read"], "when": {"variable": "replicas", "equals": 3}}`), &dep))
+		assert.True(t, dep.IsRequired(map[string]any{"replicas": float64(3)}))
+		assert.False(t, dep.IsRequired(map[string]any{"replicas": float64(2)}))
+	})
+
+	t.Run("invalid conditions fail validation", func(t *testing.T) {
+		invalid := map[string]*DependencyCondition{
+			"spec.dependencies[0].when.variable": {Equals: "prod"},
+			"spec.dependencies[0].when":          {Variable: "mode"},
+			"spec.dependencies[0].when.in":       {Variable: "mode", In: []any{}},
+		}
+		for field, condition := range invalid {
+			ss := newSkillSet(condition)
+			errs := ss.Validate()
+			require.NotEmpty(t, errs, field)
+			assert.Equal(t, field, errs[0].Field)
+		}
+
+		ss := newSkillSet(&DependencyCondition{Variable: "mode", Equals: "prod", Exists: &exists})
+		assert.NotEmpty(t, ss.Validate())
+	})
+}
+
+func TestInlineDependencies(t *testing.T) {
+	newSkillSet := func(deps ...Dependency) *SkillSet {
+		return &SkillSet{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.SkillSetKind,
+			Metadata:   interfaces.Metadata{Name: "deploy-tools", Catalog: "test-catalog", Path: "/"},
+			Spec: SkillSetSpec{
+				Version: "1.0.0",
+				Sources: []SkillSetSource{{Name: "runner", Runner: "system.testrunner", Config: map[string]any{}}},
+				Skills: []Skill{
+					{Name: "deploy", Source: "runner", ExportedActions: []policy.Action{"test.action"}},
+				},
+				Dependencies: deps,
+			},
+		}
+	}
+	inline := func(schema, value string) *InlineResource {
+		return &InlineResource{Schema: json.RawMessage(schema), Value: types.NullableAnySetRaw(json.RawMessage(value))}
+	}
+	dep := func(path string, inline *InlineResource) Dependency {
+		return Dependency{
+			Path:    path,
+			Kind:    KindResource,
+			Alias:   "kubeconfig",
+			Actions: []policy.Action{"system.resource.read"},
+			Inline:  inline,
+		}
+	}
+
+	t.Run("inline value accepted", func(t *testing.T) {
+		ss := newSkillSet(dep("", inline(`{"type": "object", "required": ["cluster"]}`, `{"cluster": "kind-dev"}`)))
+		assert.Empty(t, ss.Validate())
+		assert.True(t, ss.Spec.Dependencies[0].IsInline())
+	})
+
+	t.Run("inline value without schema accepted", func(t *testing.T) {
+		ss := newSkillSet(dep("", inline("", `"kind-dev"`)))
+		assert.Empty(t, ss.Validate())
+	})
+
+	t.Run("path and inline together rejected", func(t *testing.T) {
+		ss := newSkillSet(dep("/resources/kubeconfig", inline("", `{"cluster": "kind-dev"}`)))
+		errs := ss.Validate()
+		require.NotEmpty(t, errs)
+		assert.Equal(t, "spec.dependencies[0]", errs[0].Field)
+	})
+
+	t.Run("neither path nor inline rejected", func(t *testing.T) {
+		errs := newSkillSet(dep("", nil)).Validate()
+		require.NotEmpty(t, errs)
+		assert.Equal(t, "spec.dependencies[0].path", errs[0].Field)
+	})
+
+	t.Run("inline value must match the schema", func(t *testing.T) {
+		ss := newSkillSet(dep("", inline(`{"type": "object", "required": ["cluster"]}`, `{"region": "us-east-1"}`)))
+		errs := ss.Validate()
+		require.NotEmpty(t, errs)
+		assert.Equal(t, "spec.dependencies[0].inline.value", errs[0].Field)
+	})
+
+	t.Run("inline value only allowed for resources", func(t *testing.T) {
+		d := dep("", inline("", `{}`))
+		d.Kind = "SkillSet"
+		errs := newSkillSet(d).Validate()
+		require.NotEmpty(t, errs)
+		assert.Equal(t, "spec.dependencies[0].inline", errs[0].Field)
+	})
+
+	t.Run("inline dependencies are only stored in single user or test mode", func(t *testing.T) {
+		config.TestInit()
+		singleUser := config.Config().SingleUserMode
+		t.Cleanup(func() {
+			config.SetTestMode(true)
+			config.Config().SingleUserMode = singleUser
+		})
+		ss := newSkillSet(dep("", inline("", `"kind-dev"`)))
+		assert.NoError(t, ss.checkInlineDependencies())
+
+		config.SetTestMode(false)
+		config.Config().SingleUserMode = false
+		assert.ErrorIs(t, ss.checkInlineDependencies(), ErrInvalidSkillSetDefinition)
+		assert.NoError(t, newSkillSet(dep("/resources/kubeconfig", nil)).checkInlineDependencies())
+
+		config.Config().SingleUserMode = true
+		assert.NoError(t, ss.checkInlineDependencies())
+	})
+
+	t.Run("inline round trips through JSON", func(t *testing.T) {
+		var d Dependency
+		require.NoError(t, json.Unmarshal([]byte(`{"kind": "Resource", "alias": "db", "actions": ["read"], "inline": {"value": {"host": "localhost"}}}`), &d))
+		require.True(t, d.IsInline())
+		assert.Equal(t, map[string]any{"host": "localhost"}, d.Inline.Value.Get())
+	})
+}
+
+func TestSkillInputKeyStyle(t *testing.T) {
+	t.Run("camelCase input accepted by snake_case skill", func(t *testing.T) {
+		skill := Skill{
+			Name:          "get-user",
+			InputKeyStyle: InputKeyStyleSnake,
+			InputSchema:   json.RawMessage(`{"type": "object", "properties": {"user_id": {"type": "string"}, "include_roles": {"type": "boolean"}}, "required": ["user_id"], "additionalProperties": false}`),
+		}
+		input := map[string]any{"userId": "u1", "includeRoles": true}
+		assert.NoError(t, skill.ValidateInput(input))
+		assert.Equal(t, map[string]any{"user_id": "u1", "include_roles": true}, skill.NormalizeInput(input))
+	})
+
+	t.Run("snake_case input accepted by camelCase skill", func(t *testing.T) {
+		skill := Skill{
+			Name:          "get-user",
+			InputKeyStyle: InputKeyStyleCamel,
+			InputSchema:   json.RawMessage(`{"type": "object", "properties": {"userId": {"type": "string"}, "includeRoles": {"type": "boolean"}}, "required": ["userId"], "additionalProperties": false}`),
+		}
+		input := map[string]any{"user_id": "u1", "include_roles": true}
+		assert.NoError(t, skill.ValidateInput(input))
+		assert.Equal(t, map[string]any{"userId": "u1", "includeRoles": true}, skill.NormalizeInput(input))
+	})
+
+	t.Run("keys already in target style are untouched", func(t *testing.T) {
+		skill := Skill{Name: "get-user", InputKeyStyle: InputKeyStyleSnake}
+		input := map[string]any{
+			"user_id": "u1",
+			"userId":  "ignored",
+			"limit":   10,
+			"filter":  map[string]any{"roleName": "admin"},
+		}
+		assert.Equal(t, map[string]any{
+			"user_id": "u1",
+			"limit":   10,
+			"filter":  map[string]any{"roleName": "admin"},
+		}, skill.NormalizeInput(input))
+	})
+
+	t.Run("no style leaves input unchanged", func(t *testing.T) {
+		skill := Skill{
+			Name:        "get-user",
+			InputSchema: json.RawMessage(`{"type": "object", "properties": {"user_id": {"type": "string"}}, "required": ["user_id"]}`),
+		}
+		input := map[string]any{"userId": "u1"}
+		assert.Equal(t, input, skill.NormalizeInput(input))
+		assert.Error(t, skill.ValidateInput(input))
+	})
+
+	t.Run("key conversions", func(t *testing.T) {
+		assert.Equal(t, "http_server_url", toSnakeCase("HTTPServerURL"))
+		assert.Equal(t, "pod_name2", toSnakeCase("podName2"))
+		assert.Equal(t, "userId", toCamelCase("user_id"))
+		assert.Equal(t, "_internalId", toCamelCase("_internal_id"))
+	})
+}
+
+func TestSkillDefaultInputArgs(t *testing.T) {
+	newSkillSet := func(defaults string) SkillSet {
+		skill := Skill{
+			Name:            "list-pods",
+			Source:          "runner",
+			InputSchema:     json.RawMessage(`{"type": "object", "properties": {"namespace": {"type": "string"}, "labelSelector": {"type": "string"}, "limit": {"type": "integer", "minimum": 1}}, "required": ["namespace", "labelSelector"], "additionalProperties": false}`),
+			ExportedActions: []policy.Action{"test.action"},
+		}
+		require.NoError(t, json.Unmarshal([]byte(defaults), &skill.DefaultInputArgs))
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{{Name: "runner"}},
+				Skills:  []Skill{skill},
+			},
+		}
+	}
+
+	t.Run("defaults fill missing fields", func(t *testing.T) {
+		ss := newSkillSet(`{"namespace": "default", "limit": 50}`)
+		require.Empty(t, collectValidationErrors(ss.validateSkills))
+
+		skill := ss.Spec.Skills[0]
+		input := skill.ApplyDefaultInputArgs(map[string]any{"labelSelector": "app=web"})
+		assert.Equal(t, map[string]any{"namespace": "default", "labelSelector": "app=web", "limit": float64(50)}, input)
+		assert.NoError(t, skill.ValidateInput(input))
+
+		// without defaults the required namespace is missing
+		assert.Error(t, skill.ValidateInput(map[string]any{"labelSelector": "app=web"}))
+	})
+
+	t.Run("caller overrides defaults", func(t *testing.T) {
+		ss := newSkillSet(`{"namespace": "default"}`)
+		skill := ss.Spec.Skills[0]
+		input := skill.ApplyDefaultInputArgs(map[string]any{"namespace": "kube-system", "labelSelector": "app=dns"})
+		assert.Equal(t, "kube-system", input["namespace"])
+		assert.Equal(t, map[string]any{"namespace": "default"}, skill.DefaultInputArgs)
+	})
+
+	t.Run("invalid defaults fail validation", func(t *testing.T) {
+		for _, defaults := range []string{
+			`{"namespace": 42}`,
+			`{"limit": 0}`,
+			`{"unknown": "value"}`,
+		} {
+			ss := newSkillSet(defaults)
+			assert.NotEmpty(t, ss.Validate(), defaults)
+		}
+	})
+
+	t.Run("no defaults leaves input unchanged", func(t *testing.T) {
+		skill := Skill{Name: "list-pods"}
+		input := map[string]any{"namespace": "default"}
+		assert.Equal(t, input, skill.ApplyDefaultInputArgs(input))
+	})
+}
+
+func TestSkillAliases(t *testing.T) {
+	newSkillSet := func(t *testing.T, skills string) SkillSet {
+		t.Helper()
+		var ss SkillSet
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "SkillSet",
+			"metadata": {
+				"name": "test-skillset",
+				"catalog": "test-catalog",
+				"path": "/skillsets/test-skillset"
+			},
+			"spec": {
+				"version": "1.0.0",
+				"sources": [
+					{
+						"name": "command-runner",
+						"runner": "system.commandrunner",
+						"config": {"command": "python3 test.py"}
+					}
+				],
+				"skills": `+skills+`
+			}
+		}`), &ss))
+		return ss
+	}
+
+	t.Run("alias resolves to canonical skill", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "aliases": ["get-pods", "pods"], "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]},
+			{"name": "restart-deployment", "source": "command-runner", "exportedActions": ["kubernetes.deployments.restart"]}
+		]`)
+		require.Empty(t, ss.Validate())
+
+		manager := &skillSetManager{skillSet: ss}
+		skill, err := manager.GetSkill("get-pods")
+		require.NoError(t, err)
+		assert.Equal(t, "list-pods", skill.Name)
+
+		skill, err = manager.GetSkill("list-pods")
+		require.NoError(t, err)
+		assert.Equal(t, "list-pods", skill.Name)
+
+		source, err := manager.GetSourceForSkill("pods")
+		require.NoError(t, err)
+		assert.Equal(t, "command-runner", source.Name)
+
+		_, err = manager.GetSkill("unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("alias colliding with another skill name is rejected", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "aliases": ["restart-deployment"], "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]},
+			{"name": "restart-deployment", "source": "command-runner", "exportedActions": ["kubernetes.deployments.restart"]}
+		]`)
+		validationErrors := ss.Validate()
+		require.Len(t, validationErrors, 1)
+		assert.Contains(t, validationErrors[0].Error(), "alias restart-deployment collides with skill restart-deployment")
+	})
+
+	t.Run("duplicate skill name is rejected", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]},
+			{"name": "list-pods", "source": "command-runner", "exportedActions": ["kubernetes.pods.describe"]}
+		]`)
+		validationErrors := ss.Validate()
+		require.Len(t, validationErrors, 1)
+		assert.Contains(t, validationErrors[0].Error(), "duplicate skill name list-pods")
+	})
+
+	t.Run("alias colliding with another alias is rejected", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "aliases": ["pods"], "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]},
+			{"name": "describe-pods", "aliases": ["pods"], "source": "command-runner", "exportedActions": ["kubernetes.pods.describe"]}
+		]`)
+		validationErrors := ss.Validate()
+		require.Len(t, validationErrors, 1)
+		assert.Contains(t, validationErrors[0].Error(), "alias pods collides with skill list-pods")
+	})
+
+	t.Run("invalid alias name is rejected", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "aliases": ["list pods"], "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]}
+		]`)
+		assert.NotEmpty(t, ss.Validate())
+	})
+
+	t.Run("rename keeps the old name as an alias", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "aliases": ["pods"], "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]},
+			{"name": "restart-deployment", "source": "command-runner", "exportedActions": ["kubernetes.deployments.restart"]}
+		]`)
+		ss.Spec.Preflight = "list-pods"
+		require.NoError(t, ss.renameSkill("list-pods", "get-pods"))
+		require.Empty(t, ss.Validate())
+		assert.Equal(t, "get-pods", ss.Spec.Skills[0].Name)
+		assert.Equal(t, []string{"pods", "list-pods"}, ss.Spec.Skills[0].Aliases)
+		assert.Equal(t, "get-pods", ss.Spec.Preflight)
+
+		manager := &skillSetManager{skillSet: ss}
+		skill, err := manager.GetSkill("list-pods")
+		require.NoError(t, err)
+		assert.Equal(t, "get-pods", skill.Name)
+
+		// renaming to one of the skill's own aliases swaps the names
+		require.NoError(t, ss.renameSkill("get-pods", "pods"))
+		require.Empty(t, ss.Validate())
+		assert.Equal(t, []string{"list-pods", "get-pods"}, ss.Spec.Skills[0].Aliases)
+	})
+
+	t.Run("colliding rename is rejected", func(t *testing.T) {
+		ss := newSkillSet(t, `[
+			{"name": "list-pods", "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]},
+			{"name": "restart-deployment", "aliases": ["restart"], "source": "command-runner", "exportedActions": ["kubernetes.deployments.restart"]}
+		]`)
+		assert.ErrorIs(t, ss.renameSkill("list-pods", "restart-deployment"), ErrAlreadyExists)
+		assert.ErrorIs(t, ss.renameSkill("list-pods", "restart"), ErrAlreadyExists)
+		assert.ErrorIs(t, ss.renameSkill("unknown", "other"), ErrObjectNotFound)
+		assert.Equal(t, "list-pods", ss.Spec.Skills[0].Name)
+		assert.Empty(t, ss.Spec.Skills[0].Aliases)
+	})
+}
+
+func TestSkillValidateOutput(t *testing.T) {
+	skill := Skill{
+		Name:            "get-status",
+		Source:          "runner",
+		OutputSchema:    json.RawMessage(`{"type": "object", "properties": {"status": {"type": "string"}}, "required": ["status"]}`),
+		ValidateOutput:  true,
+		ExportedActions: []policy.Action{"test.action"},
+	}
+
+	t.Run("conforming output", func(t *testing.T) {
+		assert.NoError(t, skill.ValidateOutputAgainstSchema([]byte(`{"status": "ok"}`)))
+	})
+
+	t.Run("non-conforming output", func(t *testing.T) {
+		err := skill.ValidateOutputAgainstSchema([]byte(`{"code": 200}`))
+		assert.ErrorIs(t, err, ErrInvalidOutput)
+	})
+
+	t.Run("output that is not JSON", func(t *testing.T) {
+		err := skill.ValidateOutputAgainstSchema([]byte("status: ok"))
+		assert.ErrorIs(t, err, ErrInvalidOutput)
+	})
+
+	t.Run("no output schema accepts any output", func(t *testing.T) {
+		lenient := Skill{Name: "get-status"}
+		assert.NoError(t, lenient.ValidateOutputAgainstSchema([]byte("anything")))
+	})
+
+	t.Run("validateOutput requires an output schema", func(t *testing.T) {
+		ss := SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{{Name: "runner"}},
+				Skills:  []Skill{skill},
+			},
+		}
+		assert.Empty(t, collectValidationErrors(ss.validateSkills))
+
+		ss.Spec.Skills[0].OutputSchema = nil
+		assert.NotEmpty(t, collectValidationErrors(ss.validateSkills))
+	})
+}
+
+func TestSkillMaxConcurrent(t *testing.T) {
+	ss := SkillSet{
+		Spec: SkillSetSpec{
+			Sources: []SkillSetSource{{Name: "runner"}},
+			Skills: []Skill{{
+				Name:            "scarce-skill",
+				Source:          "runner",
+				MaxConcurrent:   1,
+				ExportedActions: []policy.Action{"test.action"},
+			}},
+		},
+	}
+	assert.Empty(t, collectValidationErrors(ss.validateSkills))
+
+	ss.Spec.Skills[0].MaxConcurrent = -1
+	assert.NotEmpty(t, collectValidationErrors(ss.validateSkills))
+}
+
+func TestRunnerConfigValidation(t *testing.T) {
+	t.Run("missing required field", func(t *testing.T) {
+		errs := validateRunnerConfig(catcommon.CommandRunnerID, map[string]any{}, "spec.sources[0].config")
+		require.Len(t, errs, 1)
+		assert.Equal(t, "spec.sources[0].config.command", errs[0].Field)
+	})
+
+	t.Run("field of the wrong type", func(t *testing.T) {
+		errs := validateRunnerConfig(catcommon.CommandRunnerID, map[string]any{"command": 42}, "spec.sources[1].config")
+		require.Len(t, errs, 1)
+		assert.Equal(t, "spec.sources[1].config.command", errs[0].Field)
+	})
+
+	t.Run("python runner needs module and function or script", func(t *testing.T) {
+		field := "spec.sources[0].config"
+		assert.Empty(t, validateRunnerConfig(catcommon.PythonRunnerID, map[string]any{"module": "m", "function": "f"}, field))
+		assert.Empty(t, validateRunnerConfig(catcommon.PythonRunnerID, map[string]any{"script": "run.py"}, field))
+		assert.NotEmpty(t, validateRunnerConfig(catcommon.PythonRunnerID, map[string]any{"module": "m"}, field))
+		assert.NotEmpty(t, validateRunnerConfig(catcommon.PythonRunnerID, map[string]any{"module": "m", "function": "f", "script": "run.py"}, field))
+	})
+
+	t.Run("stdio runner", func(t *testing.T) {
+		field := "spec.sources[0].config"
+		config := map[string]any{"version": "0.1.0-alpha.1", "runtime": "python", "script": "run.py"}
+		assert.Empty(t, validateRunnerConfig(catcommon.StdioRunnerID, config, field))
+
+		config["runtime"] = "ruby"
+		errs := validateRunnerConfig(catcommon.StdioRunnerID, config, field)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "spec.sources[0].config.runtime", errs[0].Field)
+	})
+
+	t.Run("working directory and resource limits", func(t *testing.T) {
+		field := "spec.sources[0].config"
+		config := map[string]any{"command": "run.sh", "workingDir": "/var/lib/skills", "cpuLimit": 30, "memoryLimitMB": 512, "niceness": 10}
+		assert.Empty(t, validateRunnerConfig(catcommon.CommandRunnerID, config, field))
+
+		invalid := map[string]any{
+			"workingDir":    "relative/dir",
+			"cpuLimit":      -1,
+			"memoryLimitMB": 1.5,
+			"niceness":      20,
+		}
+		for name, value := range invalid {
+			config := map[string]any{"command": "run.sh", name: value}
+			errs := validateRunnerConfig(catcommon.CommandRunnerID, config, field)
+			require.Len(t, errs, 1, name)
+			assert.Equal(t, field+"."+name, errs[0].Field)
+		}
+
+		stdioConfig := map[string]any{"version": "0.1.0-alpha.1", "runtime": "bash", "script": "run.sh", "memoryLimitMB": -1}
+		errs := validateRunnerConfig(catcommon.StdioRunnerID, stdioConfig, field)
+		require.Len(t, errs, 1)
+		assert.Equal(t, field+".memoryLimitMB", errs[0].Field)
+	})
+
+	t.Run("runner without a schema accepts any config", func(t *testing.T) {
+		assert.Empty(t, validateRunnerConfig("system.testrunner", map[string]any{"anything": true}, "spec.sources[0].config"))
+	})
+
+	t.Run("registered schema", func(t *testing.T) {
+		runner := catcommon.RunnerID("test.registeredrunner")
+		require.Error(t, RegisterRunnerConfigSchema(runner, `{"type": `))
+		require.NoError(t, RegisterRunnerConfigSchema(runner, `{"type": "object", "required": ["endpoint"]}`))
+		errs := validateRunnerConfig(runner, nil, "spec.sources[0].config")
+		require.Len(t, errs, 1)
+		assert.Equal(t, "spec.sources[0].config.endpoint", errs[0].Field)
+	})
+}
+
+func TestSkillSetDescribe(t *testing.T) {
+	sm := &skillSetManager{skillSet: SkillSet{
+		Metadata: interfaces.Metadata{Name: "k8s-tools", Path: "/ops", Description: "Kubernetes tools"},
+		Spec: SkillSetSpec{
+			Version: "1.2.0",
+			Sources: []SkillSetSource{
+				{Name: "scripts", Runner: catcommon.StdioRunnerID, Config: map[string]any{"script": "secret.sh"}},
+				{Name: "mcp", Runner: catcommon.MCPStdioRunnerID},
+			},
+			Context: []SkillSetContext{{Name: "kubeconfig", Value: types.NilAny()}},
+			Skills: []Skill{
+				{Name: "list_pods", Aliases: []string{"pods"}, Description: "List pods", Source: "scripts", ExportedActions: []policy.Action{"k8s.pods.list"}},
+				{Name: "github", Description: "GitHub tools", Source: "mcp", ExportedActions: []policy.Action{"github.read"}},
+			},
+		},
+	}}
+
+	d := sm.Describe()
+	assert.Equal(t, "k8s-tools", d.Name)
+	assert.Equal(t, "/ops/k8s-tools", d.Path)
+	assert.Equal(t, "Kubernetes tools", d.Description)
+	assert.Equal(t, "1.2.0", d.Version)
+	assert.Equal(t, []SkillDescription{
+		{Name: "list_pods", Aliases: []string{"pods"}, Description: "List pods", Source: "scripts", Runner: catcommon.StdioRunnerID, ExportedActions: []policy.Action{"k8s.pods.list"}},
+		{Name: "github", Description: "GitHub tools", Source: "mcp", Runner: catcommon.MCPStdioRunnerID, ExportedActions: []policy.Action{"github.read"}},
+	}, d.Skills)
+	assert.Equal(t, []string{"kubeconfig"}, d.Contexts)
+	assert.NotNil(t, d.Dependencies)
+
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret.sh")
+}
+
+func TestReservedSkillSetName(t *testing.T) {
+	for _, name := range []string{"describe", "export", "diff", "transform", "rename"} {
+		var ss SkillSet
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "SkillSet",
+			"metadata": {"name": "`+name+`", "catalog": "test-catalog", "path": "/skillsets"},
+			"spec": {
+				"version": "1.0.0",
+				"sources": [{"name": "command-runner", "runner": "system.commandrunner", "config": {"command": "python3 test.py"}}],
+				"skills": [{"name": "list-pods", "source": "command-runner", "exportedActions": ["kubernetes.pods.list"]}]
+			}
+		}`), &ss))
+		validationErrors := ss.Validate()
+		require.Len(t, validationErrors, 1, name)
+		assert.Contains(t, validationErrors[0].Error(), name+" is reserved")
+	}
+	assert.False(t, IsReservedSkillSetName("describe-tools"))
+}
+
+func TestSkillSetSourceOverrides(t *testing.T) {
+	newSkillSet := func(overrides map[string]map[string]any) SkillSet {
+		return SkillSet{
+			Spec: SkillSetSpec{
+				Sources: []SkillSetSource{
+					{
+						Name:   "command-runner",
+						Runner: catcommon.CommandRunnerID,
+						Config: map[string]any{"command": "python3 dev.py", "env": map[string]any{"LOG_LEVEL": "debug", "REGION": "us-west-2"}},
+					},
+				},
+				Overrides: overrides,
+			},
+		}
+	}
+
+	t.Run("override applied for the selected environment", func(t *testing.T) {
+		sm := &skillSetManager{skillSet: newSkillSet(map[string]map[string]any{
+			"prod": {"command-runner": map[string]any{"command": "python3 prod.py", "env": map[string]any{"LOG_LEVEL": "warn"}}},
+		})}
+		source := sm.skillSet.Spec.Sources[0]
+
+		prod := sm.ApplySourceOverrides(source, "prod")
+		assert.Equal(t, "python3 prod.py", prod.Config["command"])
+		assert.Equal(t, map[string]any{"LOG_LEVEL": "warn", "REGION": "us-west-2"}, prod.Config["env"])
+
+		// the skillset's own source config is unchanged
+		assert.Equal(t, "python3 dev.py", sm.skillSet.Spec.Sources[0].Config["command"])
+	})
+
+	t.Run("override ignored for other environments", func(t *testing.T) {
+		sm := &skillSetManager{skillSet: newSkillSet(map[string]map[string]any{
+			"prod": {"command-runner": map[string]any{"command": "python3 prod.py"}},
+		})}
+		source := sm.skillSet.Spec.Sources[0]
+		assert.Equal(t, source, sm.ApplySourceOverrides(source, ""))
+		assert.Equal(t, source, sm.ApplySourceOverrides(source, "staging"))
+	})
+
+	t.Run("overrides must reference existing sources", func(t *testing.T) {
+		ss := newSkillSet(map[string]map[string]any{
+			"prod": {"python-runner": map[string]any{"module": "prod"}},
+		})
+		errs := collectValidationErrors(ss.validateOverrides)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "spec.overrides.prod.python-runner", errs[0].Field)
+	})
+
+	t.Run("merged config is validated for the runner", func(t *testing.T) {
+		ss := newSkillSet(map[string]map[string]any{
+			"prod": {"command-runner": map[string]any{"command": ""}},
+		})
+		assert.NotEmpty(t, collectValidationErrors(ss.validateOverrides))
+
+		ss = newSkillSet(map[string]map[string]any{
+			"prod": {"command-runner": "python3 prod.py"},
+		})
+		assert.NotEmpty(t, collectValidationErrors(ss.validateOverrides))
+
+		ss = newSkillSet(map[string]map[string]any{
+			"prod": {"command-runner": map[string]any{"command": "python3 prod.py"}},
+		})
+		assert.Empty(t, collectValidationErrors(ss.validateOverrides))
+	})
+}
+
+func TestSkillSetLimits(t *testing.T) {
+	SetSkillSetLimits(SkillSetLimits{MaxSources: 2, MaxSkills: 3, MaxContexts: 4, MaxDependencies: 5})
+	t.Cleanup(func() { SetSkillSetLimits(DefaultSkillSetLimits) })
+
+	newSkillSet := func(sources, skills, contexts, dependencies int) SkillSet {
+		ss := SkillSet{Kind: catcommon.SkillSetKind}
+		ss.Spec.Sources = make([]SkillSetSource, sources)
+		ss.Spec.Skills = make([]Skill, skills)
+		ss.Spec.Context = make([]SkillSetContext, contexts)
+		ss.Spec.Dependencies = make([]Dependency, dependencies)
+		return ss
+	}
+
+	ss := newSkillSet(2, 3, 4, 5)
+	assert.Empty(t, collectValidationErrors(ss.validateLimits))
+
+	tests := []struct {
+		field string
+		ss    SkillSet
+	}{
+		{"spec.sources", newSkillSet(3, 3, 4, 5)},
+		{"spec.skills", newSkillSet(2, 4, 4, 5)},
+		{"spec.context", newSkillSet(2, 3, 5, 5)},
+		{"spec.dependencies", newSkillSet(2, 3, 4, 6)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			errs := collectValidationErrors(tt.ss.validateLimits)
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.field, errs[0].Field)
+			assert.Contains(t, errs[0].ErrStr, "at most")
+
+			// Limits are checked before the entries themselves
+			errs = tt.ss.Validate()
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.field, errs[0].Field)
+		})
+	}
+
+	// Limits that are not positive fall back to the defaults
+	SetSkillSetLimits(SkillSetLimits{})
+	assert.Equal(t, DefaultSkillSetLimits, skillSetLimits)
+}
+
+func TestCheckJSONDepth(t *testing.T) {
+	SetSkillSetLimits(SkillSetLimits{MaxJSONDepth: 3})
+	t.Cleanup(func() { SetSkillSetLimits(DefaultSkillSetLimits) })
+
+	// nested returns an object nesting depth levels of objects and arrays
+	nested := func(depth int) map[string]any {
+		var v any = "leaf"
+		for i := 1; i < depth; i++ {
+			if i%2 == 0 {
+				v = map[string]any{"level": v}
+			} else {
+				v = []any{v}
+			}
+		}
+		return map[string]any{"root": v}
+	}
+
+	assert.NoError(t, CheckJSONDepth("input args", "scalar"))
+	assert.NoError(t, CheckJSONDepth("input args", nested(3)))
+	err := CheckJSONDepth("input args", nested(4))
+	assert.ErrorIs(t, err, ErrJSONTooDeep)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.Contains(t, err.Error(), "maximum depth of 3")
+
+	t.Run("input args", func(t *testing.T) {
+		skill := Skill{Name: "deploy", InputSchema: json.RawMessage(`{"type": "object"}`)}
+		assert.NoError(t, skill.ValidateInput(nested(3)))
+		assert.ErrorIs(t, skill.ValidateInput(nested(4)), ErrJSONTooDeep)
+	})
+
+	t.Run("context values", func(t *testing.T) {
+		sm := &skillSetManager{skillSet: SkillSet{Spec: SkillSetSpec{
+			Context: []SkillSetContext{{Name: "config", Schema: json.RawMessage(`{"type": "object"}`)}},
+		}}}
+		atLimit, err := types.NullableAnyFrom(nested(3))
+		require.NoError(t, err)
+		_, apperr := sm.validateContextValue("config", atLimit)
+		assert.NoError(t, apperr)
+		beyond, err := types.NullableAnyFrom(nested(4))
+		require.NoError(t, err)
+		_, apperr = sm.validateContextValue("config", beyond)
+		assert.ErrorIs(t, apperr, ErrJSONTooDeep)
+	})
+
+	t.Run("context values in the skillset", func(t *testing.T) {
+		validate := func(value map[string]any) schemaerr.ValidationErrors {
+			nullable, err := types.NullableAnyFrom(value)
+			require.NoError(t, err)
+			ss := SkillSet{Spec: SkillSetSpec{
+				Context: []SkillSetContext{{Name: "config", Schema: json.RawMessage(`{"type": "object"}`), Value: nullable}},
+			}}
+			var errs schemaerr.ValidationErrors
+			ss.validateContexts(func(e schemaerr.ValidationError) { errs = append(errs, e) })
+			return errs
+		}
+		assert.Empty(t, validate(nested(3)))
+		errs := validate(nested(4))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs.Error(), "maximum depth of 3")
+	})
+}
+
+func TestSkillSetValidateStream(t *testing.T) {
+	var ss SkillSet
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "SkillSet",
+		"metadata": {"name": "test-skillset", "catalog": "test-catalog", "path": "/skillsets"},
+		"spec": {
+			"version": "1.0.0",
+			"sources": [
+				{"name": "command-runner", "runner": "system.commandrunner", "config": {"command": "python3 test.py"}}
+			],
+			"context": [
+				{"name": "config", "schema": {"type": "object"}, "value": "not-an-object"}
+			],
+			"skills": [
+				{
+					"name": "list",
+					"source": "missing-runner",
+					"inputSchema": {"type": "object"},
+					"exportedActions": ["test.list"]
+				},
+				{
+					"name": "create",
+					"aliases": ["list"],
+					"source": "command-runner",
+					"inputSchema": {"type": "object"},
+					"maxConcurrent": -1,
+					"exportedActions": ["test.create"]
+				}
+			]
+		}
+	}`), &ss))
+
+	errs := make(chan schemaerr.ValidationError)
+	go ss.ValidateStream(context.Background(), errs)
+	var streamed schemaerr.ValidationErrors
+	for err := range errs {
+		streamed = append(streamed, err)
+	}
+
+	fields := make([]string, 0, len(streamed))
+	for _, err := range streamed {
+		fields = append(fields, err.Field)
+	}
+	assert.Equal(t, []string{
+		"spec.skills[0].source",
+		"spec.skills[1].maxConcurrent",
+		"spec.skills[1].aliases[0]",
+		"spec.context[0].value",
+	}, fields)
+	assert.Equal(t, ss.Validate(), streamed, "the stream reports the same errors as Validate")
+
+	// validation stops sending once the caller goes away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = make(chan schemaerr.ValidationError)
+	done := make(chan struct{})
+	go func() {
+		ss.ValidateStream(ctx, errs)
+		close(done)
+	}()
+	<-done
+	_, ok := <-errs
+	assert.False(t, ok)
+}
+
+func TestSkillSetValidateReportsEachErrorAsFound(t *testing.T) {
+	var ss SkillSet
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "SkillSet",
+		"metadata": {"name": "test-skillset", "catalog": "test-catalog", "path": "/skillsets"},
+		"spec": {
+			"version": "1.0.0",
+			"sources": [
+				{"name": "command-runner", "runner": "system.commandrunner", "config": {"command": "python3 test.py"}}
+			],
+			"skills": [
+				{"name": "list", "source": "command-runner", "maxConcurrent": -1, "exportedActions": ["test.list"]},
+				{"name": "create", "source": "command-runner", "maxConcurrent": -1, "exportedActions": ["test.create"]}
+			]
+		}
+	}`), &ss))
+
+	// Each error is reported before the next skill is checked, so fixing the second skill
+	// when the first error is reported leaves only the first error.
+	var fields []string
+	ss.validate(func(err schemaerr.ValidationError) {
+		fields = append(fields, err.Field)
+		ss.Spec.Skills[1].MaxConcurrent = 0
+	})
+	assert.Equal(t, []string{"spec.skills[0].maxConcurrent"}, fields)
+}
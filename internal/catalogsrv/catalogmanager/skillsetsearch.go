@@ -0,0 +1,85 @@
+package catalogmanager
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/pkg/types"
+)
+
+// skillSetSearchBackfillBatchSize is the number of skillsets indexed per database query
+const skillSetSearchBackfillBatchSize = 100
+
+// RunSkillSetSearchBackfill indexes skillsets stored before catalog search existed so that
+// they appear in search results. It runs once at startup.
+func RunSkillSetSearchBackfill(ctx context.Context) {
+	dbCtx, err := db.ConnCtx(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to get db connection for skillset search backfill")
+		return
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	indexed, err := BackfillSkillSetSearch(dbCtx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to backfill skillset search")
+	}
+	if indexed > 0 {
+		log.Ctx(ctx).Info().Int("count", indexed).Msg("indexed skillsets for catalog search")
+	}
+}
+
+// BackfillSkillSetSearch adds a catalog search entry for every skillset that does not have
+// one and returns the number of skillsets indexed. Skillsets that cannot be loaded are
+// skipped and retried on the next run.
+func BackfillSkillSetSearch(ctx context.Context) (int, error) {
+	indexed := 0
+	for {
+		entries, err := db.DB(ctx).ListUnindexedSkillSets(ctx, skillSetSearchBackfillBatchSize)
+		if err != nil {
+			return indexed, err
+		}
+		progressed := false
+		for _, entry := range entries {
+			if err := indexSkillSet(ctx, entry); err != nil {
+				log.Ctx(ctx).Error().Err(err).Str("path", entry.Path).Msg("failed to index skillset for catalog search")
+				continue
+			}
+			progressed = true
+			indexed++
+		}
+		// stop when all unindexed skillsets were seen, or none in this batch could be indexed
+		if len(entries) < skillSetSearchBackfillBatchSize || !progressed {
+			return indexed, nil
+		}
+	}
+}
+
+// indexSkillSet loads a stored skillset and writes its catalog search entry.
+func indexSkillSet(ctx context.Context, entry *models.UnindexedSkillSet) error {
+	ctx = catcommon.WithTenantID(ctx, entry.TenantID)
+
+	m := &interfaces.Metadata{}
+	storagePath := entry.Path
+	if entry.Namespace != "" {
+		m.Namespace = types.NullableStringFrom(entry.Namespace)
+		storagePath = strings.TrimPrefix(storagePath, "/"+catcommon.DefaultNamespace+"/"+entry.Namespace)
+	}
+	m.SetNameAndPathFromStoragePath(storagePath)
+
+	sm, err := LoadSkillSetManagerByHash(ctx, entry.Hash, m)
+	if err != nil {
+		return err
+	}
+	ss := &models.SkillSet{
+		Path:     entry.Path,
+		TenantID: entry.TenantID,
+		Search:   sm.(*skillSetManager).searchText(),
+	}
+	return db.DB(ctx).UpsertSkillSetSearch(ctx, ss, entry.DirectoryID)
+}
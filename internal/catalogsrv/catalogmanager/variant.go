@@ -196,6 +196,9 @@ func (vm *variantManager) Save(ctx context.Context) apperrors.Error {
 		if errors.Is(err, dberror.ErrAlreadyExists) {
 			return ErrAlreadyExists.Msg("variant already exists")
 		}
+		if errors.Is(err, dberror.ErrQuotaExceeded) {
+			return ErrQuotaExceeded.Msg(err.Error())
+		}
 		if errors.Is(err, dberror.ErrInvalidCatalog) {
 			return ErrInvalidVariant.Msg("catalog does not exist or is invalid")
 		}
@@ -48,6 +48,8 @@ type CatalogContext struct {
 	SessionContext *SessionContext
 	// Subject is the type of principal that is acting on the catalog
 	Subject SubjectType
+	// AdoptionDepth is the number of view adoptions that led to the current view
+	AdoptionDepth int
 }
 
 // UserContext represents the context of an authenticated user in the system.
@@ -254,6 +256,14 @@ func GetSubjectType(ctx context.Context) SubjectType {
 	return SubjectType("")
 }
 
+// GetAdoptionDepth retrieves the number of view adoptions that led to the current view.
+func GetAdoptionDepth(ctx context.Context) int {
+	if catalogContext, ok := ctx.Value(ctxCatalogContextKey).(*CatalogContext); ok {
+		return catalogContext.AdoptionDepth
+	}
+	return 0
+}
+
 // WithTestContext sets the test context in the provided context.
 func WithTestContext(ctx context.Context, isTest bool) context.Context {
 	return context.WithValue(ctx, ctxTestContextKey, isTest)
@@ -43,6 +43,10 @@ const (
 	KindNameSkillsets  = "skillsets"
 )
 
+// SearchPathName is the path of catalog search. Search spans all variants of a catalog,
+// so it is authorized at the catalog level.
+const SearchPathName = "search"
+
 func ValidKindNames() []string {
 	return []string{
 		KindNameCatalogs,
@@ -96,7 +100,7 @@ func KindNameFromObjectType(t CatalogObjectType) string {
 }
 
 func IsCatalogLevelKind(kind string) bool {
-	return kind == KindNameViews
+	return kind == KindNameViews || kind == SearchPathName
 }
 
 type CatalogObjectType string
@@ -128,6 +132,8 @@ const (
 	StdioRunnerID     = "system.stdiorunner"
 	MCPStdioRunnerID  = "system.mcp.stdio"
 	MCPRemoteRunnerID = "system.mcp.remote"
+	CommandRunnerID   = "system.commandrunner"
+	PythonRunnerID    = "system.pythonrunner"
 )
 
 type TokenType string
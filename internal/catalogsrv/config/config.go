@@ -9,14 +9,32 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/tansive/tansive/internal/common/certs"
+	"github.com/tansive/tansive/internal/common/middleware"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 )
 
 // SessionConfig holds session-related configuration
 type SessionConfig struct {
-	ExpirationTime string `toml:"expiration_time"` // Default session expiration time
-	MaxVariables   int    `toml:"max_variables"`   // Maximum number of variables allowed in a session
+	ExpirationTime   string `toml:"expiration_time"`    // Default session expiration time
+	MaxVariables     int    `toml:"max_variables"`      // Maximum number of variables allowed in a session
+	MaxVariablesSize int    `toml:"max_variables_size"` // Maximum total size in bytes of a session's variables
+
+	CallbackAllowedHosts []string `toml:"callback_allowed_hosts"` // Hosts that session callback URLs may point to
+	CallbackMaxAttempts  int      `toml:"callback_max_attempts"`  // Maximum number of attempts to deliver a session callback
+
+	HeartbeatTimeout string `toml:"heartbeat_timeout"` // Sessions whose tangent heartbeat lapses for this long are marked stale (empty disables the watchdog)
+	WatchdogInterval string `toml:"watchdog_interval"` // How often sessions are checked for lapsed heartbeats
 }
 
+// DefaultSessionMaxVariablesSize is used when session.max_variables_size is not set
+const DefaultSessionMaxVariablesSize = 64 * 1024
+
+// DefaultSessionCallbackMaxAttempts is used when session.callback_max_attempts is not set
+const DefaultSessionCallbackMaxAttempts = 3
+
+// DefaultSessionWatchdogInterval is used when session.watchdog_interval is not set
+const DefaultSessionWatchdogInterval = "1m"
+
 // GetExpirationTime returns the session expiration time as time.Duration
 func (s *SessionConfig) GetExpirationTime() (time.Duration, error) {
 	return ParseDuration(s.ExpirationTime)
@@ -32,13 +50,43 @@ func (s *SessionConfig) GetExpirationTimeOrDefault() time.Duration {
 	return duration
 }
 
+// GetHeartbeatTimeout returns how long a session may go without a heartbeat before it is
+// marked stale. Returns 0 if stale sessions are not detected.
+func (s *SessionConfig) GetHeartbeatTimeout() time.Duration {
+	if s.HeartbeatTimeout == "" {
+		return 0
+	}
+	duration, err := ParseDuration(s.HeartbeatTimeout)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// GetWatchdogInterval returns the interval between checks for stale sessions as time.Duration
+func (s *SessionConfig) GetWatchdogInterval() (time.Duration, error) {
+	return ParseDuration(s.WatchdogInterval)
+}
+
 // AuthConfig holds authentication-related configuration
 type AuthConfig struct {
-	MaxTokenAge          string `toml:"max_token_age"`          // Maximum age for tokens
-	ClockSkew            string `toml:"clock_skew"`             // Allowed clock skew for time-based claims
-	KeyEncryptionPasswd  string `toml:"key_encryption_passwd"`  // Password for key encryption
-	DefaultTokenValidity string `toml:"default_token_validity"` // Default token validity duration
-	TestUserToken        string `toml:"-"`                      // Token for internal unit test mode
+	MaxTokenAge          string `toml:"max_token_age"`           // Maximum age for tokens
+	ClockSkew            string `toml:"clock_skew"`              // Allowed clock skew for time-based claims
+	KeyEncryptionPasswd  string `toml:"key_encryption_passwd"`   // Password for key encryption
+	DefaultTokenValidity string `toml:"default_token_validity"`  // Default token validity duration
+	MaxViewAdoptionDepth int    `toml:"max_view_adoption_depth"` // Maximum length of a chain of view adoptions
+	TestUserToken        string `toml:"-"`                       // Token for internal unit test mode
+}
+
+// DefaultMaxViewAdoptionDepth is used when auth.max_view_adoption_depth is not set
+const DefaultMaxViewAdoptionDepth = 8
+
+// GetMaxViewAdoptionDepthOrDefault returns the maximum length of a chain of view adoptions
+func (a *AuthConfig) GetMaxViewAdoptionDepthOrDefault() int {
+	if a.MaxViewAdoptionDepth <= 0 {
+		return DefaultMaxViewAdoptionDepth
+	}
+	return a.MaxViewAdoptionDepth
 }
 
 // GetMaxTokenAge returns the maximum token age as time.Duration
@@ -88,16 +136,149 @@ func (a *AuthConfig) GetDefaultTokenValidityOrDefault() time.Duration {
 
 // AuditLogConfig holds audit log-related configuration
 type AuditLogConfig struct {
-	Path string `toml:"path"`
+	Path          string `toml:"path"`
+	RetentionDays int    `toml:"retention_days"` // Days to keep the audit logs of ended sessions. 0 keeps them forever.
+	PruneInterval string `toml:"prune_interval"` // How often expired audit logs are pruned
+	PruneSessions bool   `toml:"prune_sessions"` // Whether to also delete the session records of pruned audit logs
+
+	ExportBaseDir      string   `toml:"export_base_dir"`      // Directory that file:// catalog audit log destinations must be under (empty disables them)
+	ExportAllowedHosts []string `toml:"export_allowed_hosts"` // Hosts that http(s):// catalog audit log destinations may point to
+	ExportMaxAttempts  int      `toml:"export_max_attempts"`  // Maximum number of attempts to export an audit log to its catalog destination
 }
 
+// DefaultAuditLogExportMaxAttempts is used when audit_log.export_max_attempts is not set
+const DefaultAuditLogExportMaxAttempts = 3
+
+// DefaultAuditLogPruneInterval is used when audit_log.prune_interval is not set
+const DefaultAuditLogPruneInterval = "1h"
+
 func (a *AuditLogConfig) GetPath() string {
 	return a.Path
 }
 
+// GetRetention returns how long audit logs are kept after a session ends.
+// Returns 0 if audit logs are kept forever.
+func (a *AuditLogConfig) GetRetention() time.Duration {
+	return time.Duration(a.RetentionDays) * 24 * time.Hour
+}
+
+// GetPruneInterval returns the interval between audit log pruning runs as time.Duration
+func (a *AuditLogConfig) GetPruneInterval() (time.Duration, error) {
+	return ParseDuration(a.PruneInterval)
+}
+
+// DBConfig holds database connection, pooling and timeout configuration
+type DBConfig struct {
+	Host     string `toml:"host"`     // Database host
+	Port     int    `toml:"port"`     // Database port
+	DBName   string `toml:"dbname"`   // Database name
+	User     string `toml:"user"`     // Database user
+	Password string `toml:"password"` // Database password
+	SSLMode  string `toml:"sslmode"`  // SSL mode for database connection
+
+	MaxOpenConns     int    `toml:"max_open_conns"`     // Maximum number of open connections in the pool
+	MaxIdleConns     int    `toml:"max_idle_conns"`     // Maximum number of idle connections kept in the pool
+	ConnMaxLifetime  string `toml:"conn_max_lifetime"`  // Maximum time a connection may be reused
+	ConnMaxIdleTime  string `toml:"conn_max_idle_time"` // Maximum time a connection may sit idle
+	StatementTimeout string `toml:"statement_timeout"`  // Maximum time a single statement may run before it is cancelled
+
+	SlowQueryThreshold string `toml:"slow_query_threshold"` // Queries taking at least this long are logged (empty disables slow-query logging)
+
+	Replica DBReplicaConfig `toml:"replica"` // Optional read replica for read-heavy queries
+}
+
+// DBReplicaConfig holds the connection settings of a read replica. The replica is used only when
+// Host is set. Settings left empty are taken from the primary database.
+type DBReplicaConfig struct {
+	Host     string `toml:"host"`     // Replica host
+	Port     int    `toml:"port"`     // Replica port
+	DBName   string `toml:"dbname"`   // Replica database name
+	User     string `toml:"user"`     // Replica user
+	Password string `toml:"password"` // Replica password
+	SSLMode  string `toml:"sslmode"`  // SSL mode for replica connection
+}
+
+// Defaults used when the corresponding db settings are not set
+const (
+	DefaultDBMaxOpenConns     = 50
+	DefaultDBMaxIdleConns     = 10
+	DefaultDBConnMaxLifetime  = "30m"
+	DefaultDBConnMaxIdleTime  = "5m"
+	DefaultDBStatementTimeout = "5s"
+)
+
+// GetConnMaxLifetime returns the maximum connection lifetime as time.Duration
+func (d *DBConfig) GetConnMaxLifetime() (time.Duration, error) {
+	return ParseDuration(d.ConnMaxLifetime)
+}
+
+// GetConnMaxIdleTime returns the maximum connection idle time as time.Duration
+func (d *DBConfig) GetConnMaxIdleTime() (time.Duration, error) {
+	return ParseDuration(d.ConnMaxIdleTime)
+}
+
+// GetStatementTimeout returns the statement timeout as time.Duration
+func (d *DBConfig) GetStatementTimeout() (time.Duration, error) {
+	return ParseDuration(d.StatementTimeout)
+}
+
+// GetSlowQueryThreshold returns the slow-query logging threshold as time.Duration.
+// It returns 0 if slow-query logging is disabled. Unlike the other durations, the threshold
+// takes Go duration syntax, so that sub-second thresholds such as "200ms" can be set.
+func (d *DBConfig) GetSlowQueryThreshold() (time.Duration, error) {
+	if d.SlowQueryThreshold == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(d.SlowQueryThreshold)
+}
+
+// SkillSetConfig holds limits on the size of skillset documents
+type SkillSetConfig struct {
+	MaxSources      int `toml:"max_sources"`      // Maximum number of sources in a skillset
+	MaxSkills       int `toml:"max_skills"`       // Maximum number of skills in a skillset
+	MaxContexts     int `toml:"max_contexts"`     // Maximum number of context entries in a skillset
+	MaxDependencies int `toml:"max_dependencies"` // Maximum number of dependencies in a skillset
+	MaxJSONDepth    int `toml:"max_json_depth"`   // Maximum nesting depth of input args, context values and session variables
+}
+
+// Defaults used when the corresponding skillset limits are not set
+const (
+	DefaultSkillSetMaxSources      = 64
+	DefaultSkillSetMaxSkills       = 1024
+	DefaultSkillSetMaxContexts     = 256
+	DefaultSkillSetMaxDependencies = 256
+	DefaultSkillSetMaxJSONDepth    = 64
+)
+
+// TenantQuota holds limits on the number of objects a tenant may own. A zero limit is unlimited.
+type TenantQuota struct {
+	MaxCatalogs       int `toml:"max_catalogs"`        // Maximum number of catalogs
+	MaxVariants       int `toml:"max_variants"`        // Maximum number of variants across all catalogs
+	MaxSkillSets      int `toml:"max_skillsets"`       // Maximum number of skillsets across all variants
+	MaxActiveSessions int `toml:"max_active_sessions"` // Maximum number of sessions that have not ended
+}
+
+// QuotaConfig holds the resource quotas of tenants. Tenants without an entry in Tenants
+// use the Default quota; an entry replaces the default quota of that tenant entirely.
+type QuotaConfig struct {
+	Default TenantQuota            `toml:"default"` // Quota of tenants without their own entry
+	Tenants map[string]TenantQuota `toml:"tenants"` // Quotas keyed by tenant ID
+}
+
+// ForTenant returns the quota of the given tenant.
+func (q QuotaConfig) ForTenant(tenantID string) TenantQuota {
+	if quota, ok := q.Tenants[tenantID]; ok {
+		return quota
+	}
+	return q.Default
+}
+
 // TangentConfig holds tangent-related configuration
 type TangentConfig struct {
 	OnboardingKey string `toml:"onboarding_key"`
+	// SignatureHeaders names the headers tangents sign requests with. It must match the
+	// tangents' tansive_server.signature_headers.
+	SignatureHeaders tangentsig.Headers `toml:"signature_headers"`
 }
 
 // ConfigParam holds all configuration parameters for the catalog service
@@ -118,12 +299,21 @@ type ConfigParam struct {
 	TLSCertPEM         []byte `toml:"-"`                     // PEM encoded TLS certificate
 	TLSKeyPEM          []byte `toml:"-"`                     // PEM encoded TLS key
 
+	// CORS configuration, applied when HandleCORS is set
+	CORS middleware.CORSConfig `toml:"cors"`
+
 	// Session configuration
 	Session SessionConfig `toml:"session"`
 
 	// Audit log configuration
 	AuditLog AuditLogConfig `toml:"audit_log"`
 
+	// Skillset limits
+	SkillSet SkillSetConfig `toml:"skillset"`
+
+	// Tenant resource quotas
+	Quotas QuotaConfig `toml:"quotas"`
+
 	// Auth configuration
 	Auth AuthConfig `toml:"auth"`
 
@@ -134,14 +324,7 @@ type ConfigParam struct {
 	DefaultProjectID       string `toml:"default_project_id"` // Default project ID for single user mode
 
 	// Database configuration
-	DB struct {
-		Host     string `toml:"host"`     // Database host
-		Port     int    `toml:"port"`     // Database port
-		DBName   string `toml:"dbname"`   // Database name
-		User     string `toml:"user"`     // Database user
-		Password string `toml:"password"` // Database password
-		SSLMode  string `toml:"sslmode"`  // SSL mode for database connection
-	} `toml:"db"`
+	DB DBConfig `toml:"db"`
 
 	// Tangent configuration
 	Tangent TangentConfig `toml:"tangent"`
@@ -160,6 +343,17 @@ func (c *ConfigParam) DSN() string {
 		c.DB.Host, c.DB.Port, c.DB.User, c.DB.Password, c.DB.DBName, c.DB.SSLMode)
 }
 
+// ReplicaDSN returns the database connection string of the read replica, or an empty string
+// if no replica is configured
+func (c *ConfigParam) ReplicaDSN() string {
+	r := c.DB.Replica
+	if r.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		r.Host, r.Port, r.User, r.Password, r.DBName, r.SSLMode)
+}
+
 // HatchCatalogDSN returns the DSN for the Hatch Catalog database
 func HatchCatalogDSN() string {
 	return cfg.DSN()
@@ -170,6 +364,7 @@ func HatchCatalogDSN() string {
 // - d: days
 // - h: hours
 // - m: minutes
+// - s: seconds
 func ParseDuration(input string) (time.Duration, error) {
 	if len(input) < 2 {
 		return 0, fmt.Errorf("invalid input format")
@@ -192,6 +387,8 @@ func ParseDuration(input string) (time.Duration, error) {
 		duration = time.Duration(value) * time.Hour
 	case "m":
 		duration = time.Duration(value) * time.Minute
+	case "s":
+		duration = time.Duration(value) * time.Second
 	case "y":
 		// Assuming 1 year = 365 days for simplicity
 		duration = time.Duration(value) * 365 * 24 * time.Hour
@@ -210,6 +407,9 @@ func ValidateConfig(cfg *ConfigParam) error {
 	if err := validateServerConfig(cfg); err != nil {
 		return err
 	}
+	if err := validateCORSConfig(cfg); err != nil {
+		return err
+	}
 	if err := validateSessionConfig(cfg); err != nil {
 		return err
 	}
@@ -225,6 +425,15 @@ func ValidateConfig(cfg *ConfigParam) error {
 	if err := validateAuditLogConfig(cfg); err != nil {
 		return err
 	}
+	if err := validateSkillSetConfig(cfg); err != nil {
+		return err
+	}
+	if err := validateQuotaConfig(cfg); err != nil {
+		return err
+	}
+	if err := cfg.Tangent.SignatureHeaders.Validate(); err != nil {
+		return fmt.Errorf("tangent.signature_headers: %v", err)
+	}
 	if err := validateTLSConfig(cfg); err != nil {
 		return err
 	}
@@ -245,6 +454,19 @@ func validateServerConfig(cfg *ConfigParam) error {
 	return nil
 }
 
+// DefaultCORSAllowedOrigin is the origin allowed when CORS is handled and cors.allowed_origins is not set
+const DefaultCORSAllowedOrigin = "http://local.tansive.dev:8190"
+
+func validateCORSConfig(cfg *ConfigParam) error {
+	if !cfg.HandleCORS {
+		return nil
+	}
+	if len(cfg.CORS.AllowedOrigins) == 0 {
+		cfg.CORS.AllowedOrigins = []string{DefaultCORSAllowedOrigin}
+	}
+	return cfg.CORS.Validate()
+}
+
 func validateSessionConfig(cfg *ConfigParam) error {
 	if cfg.Session.ExpirationTime == "" {
 		return fmt.Errorf("session.expiration_time is required")
@@ -255,6 +477,29 @@ func validateSessionConfig(cfg *ConfigParam) error {
 	if cfg.Session.MaxVariables <= 0 {
 		return fmt.Errorf("session.max_variables must be positive")
 	}
+	if cfg.Session.MaxVariablesSize < 0 {
+		return fmt.Errorf("session.max_variables_size must not be negative")
+	}
+	if cfg.Session.MaxVariablesSize == 0 {
+		cfg.Session.MaxVariablesSize = DefaultSessionMaxVariablesSize
+	}
+	if cfg.Session.CallbackMaxAttempts < 0 {
+		return fmt.Errorf("session.callback_max_attempts must not be negative")
+	}
+	if cfg.Session.CallbackMaxAttempts == 0 {
+		cfg.Session.CallbackMaxAttempts = DefaultSessionCallbackMaxAttempts
+	}
+	if cfg.Session.HeartbeatTimeout != "" {
+		if timeout, err := ParseDuration(cfg.Session.HeartbeatTimeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid session.heartbeat_timeout: %s", cfg.Session.HeartbeatTimeout)
+		}
+	}
+	if cfg.Session.WatchdogInterval == "" {
+		cfg.Session.WatchdogInterval = DefaultSessionWatchdogInterval
+	}
+	if interval, err := cfg.Session.GetWatchdogInterval(); err != nil || interval <= 0 {
+		return fmt.Errorf("invalid session.watchdog_interval: %s", cfg.Session.WatchdogInterval)
+	}
 	return nil
 }
 
@@ -312,6 +557,124 @@ func validateDBConfig(cfg *ConfigParam) error {
 	if cfg.DB.SSLMode == "" {
 		return fmt.Errorf("db.sslmode is required")
 	}
+	if cfg.DB.Replica.Host != "" {
+		if cfg.DB.Replica.Port < 0 {
+			return fmt.Errorf("db.replica.port must be positive")
+		}
+		if cfg.DB.Replica.Port == 0 {
+			cfg.DB.Replica.Port = cfg.DB.Port
+		}
+		if cfg.DB.Replica.DBName == "" {
+			cfg.DB.Replica.DBName = cfg.DB.DBName
+		}
+		if cfg.DB.Replica.User == "" {
+			cfg.DB.Replica.User = cfg.DB.User
+		}
+		if cfg.DB.Replica.Password == "" {
+			cfg.DB.Replica.Password = cfg.DB.Password
+		}
+		if cfg.DB.Replica.SSLMode == "" {
+			cfg.DB.Replica.SSLMode = cfg.DB.SSLMode
+		}
+	}
+	if cfg.DB.MaxOpenConns < 0 {
+		return fmt.Errorf("db.max_open_conns must not be negative")
+	}
+	if cfg.DB.MaxOpenConns == 0 {
+		cfg.DB.MaxOpenConns = DefaultDBMaxOpenConns
+	}
+	if cfg.DB.MaxIdleConns < 0 {
+		return fmt.Errorf("db.max_idle_conns must not be negative")
+	}
+	if cfg.DB.MaxIdleConns == 0 {
+		cfg.DB.MaxIdleConns = min(DefaultDBMaxIdleConns, cfg.DB.MaxOpenConns)
+	}
+	if cfg.DB.MaxIdleConns > cfg.DB.MaxOpenConns {
+		return fmt.Errorf("db.max_idle_conns must not exceed db.max_open_conns")
+	}
+	if cfg.DB.ConnMaxLifetime == "" {
+		cfg.DB.ConnMaxLifetime = DefaultDBConnMaxLifetime
+	}
+	if d, err := cfg.DB.GetConnMaxLifetime(); err != nil || d <= 0 {
+		return fmt.Errorf("invalid db.conn_max_lifetime: %s", cfg.DB.ConnMaxLifetime)
+	}
+	if cfg.DB.ConnMaxIdleTime == "" {
+		cfg.DB.ConnMaxIdleTime = DefaultDBConnMaxIdleTime
+	}
+	if d, err := cfg.DB.GetConnMaxIdleTime(); err != nil || d <= 0 {
+		return fmt.Errorf("invalid db.conn_max_idle_time: %s", cfg.DB.ConnMaxIdleTime)
+	}
+	if cfg.DB.StatementTimeout == "" {
+		cfg.DB.StatementTimeout = DefaultDBStatementTimeout
+	}
+	if d, err := cfg.DB.GetStatementTimeout(); err != nil || d <= 0 {
+		return fmt.Errorf("invalid db.statement_timeout: %s", cfg.DB.StatementTimeout)
+	}
+	if d, err := cfg.DB.GetSlowQueryThreshold(); err != nil || d < 0 {
+		return fmt.Errorf("invalid db.slow_query_threshold: %s", cfg.DB.SlowQueryThreshold)
+	}
+	return nil
+}
+
+func validateSkillSetConfig(cfg *ConfigParam) error {
+	if cfg.SkillSet.MaxSources < 0 {
+		return fmt.Errorf("skillset.max_sources must not be negative")
+	}
+	if cfg.SkillSet.MaxSources == 0 {
+		cfg.SkillSet.MaxSources = DefaultSkillSetMaxSources
+	}
+	if cfg.SkillSet.MaxSkills < 0 {
+		return fmt.Errorf("skillset.max_skills must not be negative")
+	}
+	if cfg.SkillSet.MaxSkills == 0 {
+		cfg.SkillSet.MaxSkills = DefaultSkillSetMaxSkills
+	}
+	if cfg.SkillSet.MaxContexts < 0 {
+		return fmt.Errorf("skillset.max_contexts must not be negative")
+	}
+	if cfg.SkillSet.MaxContexts == 0 {
+		cfg.SkillSet.MaxContexts = DefaultSkillSetMaxContexts
+	}
+	if cfg.SkillSet.MaxDependencies < 0 {
+		return fmt.Errorf("skillset.max_dependencies must not be negative")
+	}
+	if cfg.SkillSet.MaxDependencies == 0 {
+		cfg.SkillSet.MaxDependencies = DefaultSkillSetMaxDependencies
+	}
+	if cfg.SkillSet.MaxJSONDepth < 0 {
+		return fmt.Errorf("skillset.max_json_depth must not be negative")
+	}
+	if cfg.SkillSet.MaxJSONDepth == 0 {
+		cfg.SkillSet.MaxJSONDepth = DefaultSkillSetMaxJSONDepth
+	}
+	return nil
+}
+
+func validateQuotaConfig(cfg *ConfigParam) error {
+	if err := validateTenantQuota("quotas.default", cfg.Quotas.Default); err != nil {
+		return err
+	}
+	for tenantID, quota := range cfg.Quotas.Tenants {
+		if err := validateTenantQuota("quotas.tenants."+tenantID, quota); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTenantQuota(name string, quota TenantQuota) error {
+	if quota.MaxCatalogs < 0 {
+		return fmt.Errorf("%s.max_catalogs must not be negative", name)
+	}
+	if quota.MaxVariants < 0 {
+		return fmt.Errorf("%s.max_variants must not be negative", name)
+	}
+	if quota.MaxSkillSets < 0 {
+		return fmt.Errorf("%s.max_skillsets must not be negative", name)
+	}
+	if quota.MaxActiveSessions < 0 {
+		return fmt.Errorf("%s.max_active_sessions must not be negative", name)
+	}
 	return nil
 }
 
@@ -326,6 +689,24 @@ func validateAuditLogConfig(cfg *ConfigParam) error {
 			return fmt.Errorf("error creating audit log directory: %v", err)
 		}
 	}
+	if cfg.AuditLog.RetentionDays < 0 {
+		return fmt.Errorf("audit_log.retention_days must not be negative")
+	}
+	if cfg.AuditLog.PruneInterval == "" {
+		cfg.AuditLog.PruneInterval = DefaultAuditLogPruneInterval
+	}
+	if interval, err := cfg.AuditLog.GetPruneInterval(); err != nil || interval <= 0 {
+		return fmt.Errorf("invalid audit_log.prune_interval: %s", cfg.AuditLog.PruneInterval)
+	}
+	if cfg.AuditLog.ExportBaseDir != "" && !filepath.IsAbs(cfg.AuditLog.ExportBaseDir) {
+		return fmt.Errorf("audit_log.export_base_dir must be an absolute path")
+	}
+	if cfg.AuditLog.ExportMaxAttempts < 0 {
+		return fmt.Errorf("audit_log.export_max_attempts must not be negative")
+	}
+	if cfg.AuditLog.ExportMaxAttempts == 0 {
+		cfg.AuditLog.ExportMaxAttempts = DefaultAuditLogExportMaxAttempts
+	}
 	return nil
 }
 
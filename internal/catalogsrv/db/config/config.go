@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/tansive/tansive/internal/catalogsrv/config"
 )
 
@@ -9,4 +11,51 @@ func HatchCatalogDsn() string {
 	return config.HatchCatalogDSN()
 }
 
+// HatchCatalogReplicaDsn returns the DSN for the read replica of the Hatch Catalog database.
+// It returns an empty string if no replica is configured.
+func HatchCatalogReplicaDsn() string {
+	return config.Config().ReplicaDSN()
+}
+
+// PoolSettings holds the connection pool and timeout settings for the Hatch Catalog database
+type PoolSettings struct {
+	MaxOpenConns     int
+	MaxIdleConns     int
+	ConnMaxLifetime  time.Duration
+	ConnMaxIdleTime  time.Duration
+	StatementTimeout time.Duration
+}
+
+// HatchCatalogPoolSettings returns the configured pool settings for the Hatch Catalog database.
+// Values are validated when the configuration is loaded.
+func HatchCatalogPoolSettings() PoolSettings {
+	db := &config.Config().DB
+	lifetime, _ := db.GetConnMaxLifetime()
+	idleTime, _ := db.GetConnMaxIdleTime()
+	timeout, _ := db.GetStatementTimeout()
+	return PoolSettings{
+		MaxOpenConns:     db.MaxOpenConns,
+		MaxIdleConns:     db.MaxIdleConns,
+		ConnMaxLifetime:  lifetime,
+		ConnMaxIdleTime:  idleTime,
+		StatementTimeout: timeout,
+	}
+}
+
+// HatchCatalogSlowQueryThreshold returns the duration at or above which queries against the
+// Hatch Catalog database are logged as slow. It returns 0 if slow-query logging is disabled.
+func HatchCatalogSlowQueryThreshold() time.Duration {
+	cfg := config.Config()
+	if cfg == nil {
+		return 0
+	}
+	threshold, _ := cfg.DB.GetSlowQueryThreshold()
+	return threshold
+}
+
+// TenantQuota returns the limits on the number of objects the given tenant may own.
+func TenantQuota(tenantID string) config.TenantQuota {
+	return config.Config().Quotas.ForTenant(tenantID)
+}
+
 const CompressCatalogObjects = config.CompressCatalogObjects
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -68,20 +69,28 @@ type MetadataManager interface {
 	GetView(ctx context.Context, viewID uuid.UUID) (*models.View, apperrors.Error)
 	GetViewByLabel(ctx context.Context, label string, catalogID uuid.UUID) (*models.View, apperrors.Error)
 	UpdateView(ctx context.Context, view *models.View) apperrors.Error
+	BumpViewTokenEpoch(ctx context.Context, viewID uuid.UUID) (int64, apperrors.Error)
 	DeleteView(ctx context.Context, viewID uuid.UUID) apperrors.Error
 	DeleteViewByLabel(ctx context.Context, label string, catalogID uuid.UUID) apperrors.Error
 	ListViewsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.View, apperrors.Error)
 
+	// Search
+	SearchCatalog(ctx context.Context, catalogID uuid.UUID, tsQuery string, after *models.SearchResult, limit int) ([]*models.SearchResult, apperrors.Error)
+	ListCatalogResourcePaths(ctx context.Context, catalogID uuid.UUID, after string, limit int) ([]string, apperrors.Error)
+
 	// Tangent
 	CreateTangent(ctx context.Context, tangent *models.Tangent) apperrors.Error
 	GetTangent(ctx context.Context, id uuid.UUID) (*models.Tangent, apperrors.Error)
 	UpdateTangent(ctx context.Context, tangent *models.Tangent) apperrors.Error
+	UpdateTangentStatus(ctx context.Context, id uuid.UUID, status string) apperrors.Error
+	UpdateTangentHeartbeat(ctx context.Context, id uuid.UUID, heartbeatAt time.Time) apperrors.Error
 	DeleteTangent(ctx context.Context, id uuid.UUID) apperrors.Error
 	ListTangents(ctx context.Context) ([]*models.Tangent, apperrors.Error)
 
 	// ViewToken
 	CreateViewToken(ctx context.Context, token *models.ViewToken) apperrors.Error
 	GetViewToken(ctx context.Context, tokenID uuid.UUID) (*models.ViewToken, apperrors.Error)
+	ListSessionViewTokens(ctx context.Context, sessionID uuid.UUID) ([]*models.ViewToken, apperrors.Error)
 	UpdateViewTokenExpiry(ctx context.Context, tokenID uuid.UUID, expireAt time.Time) apperrors.Error
 	DeleteViewToken(ctx context.Context, tokenID uuid.UUID) apperrors.Error
 
@@ -96,10 +105,19 @@ type MetadataManager interface {
 	UpsertSession(ctx context.Context, session *models.Session) apperrors.Error
 	GetSession(ctx context.Context, sessionID uuid.UUID) (*models.Session, apperrors.Error)
 	UpdateSessionStatus(ctx context.Context, sessionID uuid.UUID, statusSummary string, status json.RawMessage) apperrors.Error
-	UpdateSessionEnd(ctx context.Context, sessionID uuid.UUID, statusSummary string, status json.RawMessage) apperrors.Error
+	UpdateSessionEnd(ctx context.Context, sessionID uuid.UUID, statusSummary string, status json.RawMessage) (string, apperrors.Error)
 	UpdateSessionInfo(ctx context.Context, sessionID uuid.UUID, info json.RawMessage) apperrors.Error
+	UpdateSessionHeartbeat(ctx context.Context, sessionID uuid.UUID, heartbeatAt time.Time, statusSummaries []string) apperrors.Error
 	DeleteSession(ctx context.Context, sessionID uuid.UUID) apperrors.Error
-	ListSessionsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.Session, apperrors.Error)
+	ListSessionsByCatalog(ctx context.Context, catalogID uuid.UUID, labels map[string]string) ([]*models.Session, apperrors.Error)
+	ListSessionsByIDs(ctx context.Context, sessionIDs []uuid.UUID) ([]*models.Session, apperrors.Error)
+	ListSessionsByTangent(ctx context.Context, catalogID uuid.UUID, tangentID uuid.UUID, statusSummaries []string) ([]*models.Session, apperrors.Error)
+	ListPrunableSessions(ctx context.Context, endedBefore time.Time, statusSummaries []string, limit int) ([]*models.Session, apperrors.Error)
+	ListStaleSessions(ctx context.Context, heartbeatBefore time.Time, statusSummaries []string, limit int) ([]*models.Session, apperrors.Error)
+
+	// Usage
+	CountActiveSessions(ctx context.Context, statusSummaries []string) (int, apperrors.Error)
+	CountActiveSessionsByTangent(ctx context.Context, statusSummaries []string) (map[uuid.UUID]int, apperrors.Error)
 }
 
 // ObjectManager handles all object-related operations in the catalog service.
@@ -128,6 +146,9 @@ type ObjectManager interface {
 	DeleteSkillSet(ctx context.Context, path string, directoryID uuid.UUID) (string, apperrors.Error)
 	UpsertSkillSetObject(ctx context.Context, ss *models.SkillSet, obj *models.CatalogObject, directoryID uuid.UUID) apperrors.Error
 	ListSkillSets(ctx context.Context, directoryID uuid.UUID) ([]models.SkillSet, apperrors.Error)
+	GetSkillSetVersionObject(ctx context.Context, path string, version string, directoryID uuid.UUID) (*models.CatalogObject, apperrors.Error)
+	UpsertSkillSetSearch(ctx context.Context, ss *models.SkillSet, directoryID uuid.UUID) apperrors.Error
+	ListUnindexedSkillSets(ctx context.Context, limit int) ([]*models.UnindexedSkillSet, apperrors.Error)
 
 	// Schema Directory
 	CreateSchemaDirectory(ctx context.Context, t catcommon.CatalogObjectType, dir *models.SchemaDirectory) apperrors.Error
@@ -205,6 +226,24 @@ func Conn(ctx context.Context) (dbmanager.ScopedConn, error) {
 	return nil, fmt.Errorf("database pool not initialized")
 }
 
+// Close closes the database connection pool, including that of the read replica.
+func Close() error {
+	if pool == nil {
+		return nil
+	}
+	return pool.Close()
+}
+
+// PoolStats returns the statistics of the database connection pool along with the number of
+// connection requests and returns. ok is false if the pool has not been initialized.
+func PoolStats() (stats sql.DBStats, requests, returns uint64, ok bool) {
+	if pool == nil {
+		return sql.DBStats{}, 0, 0, false
+	}
+	requests, returns = pool.Stats()
+	return pool.PoolStats(), requests, returns, true
+}
+
 type ctxDbKeyType string
 
 const ctxDbKey ctxDbKeyType = "TansiveCatalogDb"
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+)
+
+func TestListCatalogResourcePaths(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	assert.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	var info pgtype.JSONB
+	assert.NoError(t, info.Set(`{"key": "value"}`))
+
+	catalog := models.Catalog{
+		Name:        "coverage_catalog",
+		Description: "Catalog for resource listing",
+		Info:        info,
+	}
+	require.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	variant := models.Variant{
+		Name:      "dev",
+		CatalogID: catalog.CatalogID,
+		Info:      info,
+	}
+	require.NoError(t, DB(ctx).CreateVariant(ctx, &variant))
+	defer DB(ctx).DeleteVariant(ctx, catalog.CatalogID, variant.VariantID, "")
+
+	require.NoError(t, DB(ctx).CreateNamespace(ctx, &models.Namespace{
+		Name:      "team-a",
+		VariantID: variant.VariantID,
+		Info:      info.Bytes,
+	}))
+
+	view := models.View{
+		Label:     "readers",
+		Info:      info.Bytes,
+		Rules:     []byte(`{"rules": []}`),
+		CatalogID: catalog.CatalogID,
+		CreatedBy: "test_user",
+		UpdatedBy: "test_user",
+	}
+	require.NoError(t, DB(ctx).CreateView(ctx, &view))
+
+	ref := models.ObjectRef{Hash: "test_hash_123456789012345"}
+	require.NoError(t, DB(ctx).AddOrUpdateObjectByPath(ctx, catcommon.CatalogObjectTypeResource, variant.ResourceDirectoryID, "/--root--/app/config", ref))
+	require.NoError(t, DB(ctx).AddOrUpdateObjectByPath(ctx, catcommon.CatalogObjectTypeResource, variant.ResourceDirectoryID, "/--root--/team-a/app/config", ref))
+	require.NoError(t, DB(ctx).AddOrUpdateObjectByPath(ctx, catcommon.CatalogObjectTypeSkillset, variant.SkillsetDirectoryID, "/--root--/tools", ref))
+
+	// page through two paths at a time, continuing after the last path of each page
+	var paths []string
+	after := ""
+	for {
+		page, err := DB(ctx).ListCatalogResourcePaths(ctx, catalog.CatalogID, after, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page), 2)
+		if len(page) == 0 {
+			break
+		}
+		paths = append(paths, page...)
+		after = page[len(page)-1]
+	}
+
+	catalogPath := "res://catalogs/coverage_catalog"
+	variantPath := catalogPath + "/variants/dev"
+	assert.Subset(t, paths, []string{
+		catalogPath,
+		catalogPath + "/views/readers",
+		variantPath,
+		variantPath + "/namespaces/team-a",
+		variantPath + "/resources/app/config",
+		variantPath + "/namespaces/team-a/resources/app/config",
+		variantPath + "/skillsets/tools",
+	})
+	assert.True(t, slices.IsSorted(paths), "paths must be ordered")
+	assert.Len(t, slices.Compact(slices.Clone(paths)), len(paths), "paths must not repeat across pages")
+
+	_, err := DB(ctx).ListCatalogResourcePaths(ctx, catalog.CatalogID, "", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	config.TestInit()
+	origTimeout := config.Config().DB.StatementTimeout
+	t.Cleanup(func() {
+		config.Config().DB.StatementTimeout = origTimeout
+		Init()
+	})
+	config.Config().DB.StatementTimeout = "1s"
+	Init()
+
+	ctx := log.Logger.WithContext(context.Background())
+	scoped, err := Conn(ctx)
+	require.NoError(t, err)
+	defer scoped.Close(ctx)
+
+	_, err = scoped.Conn().ExecContext(ctx, "SELECT pg_sleep(0.1)")
+	require.NoError(t, err)
+
+	_, err = scoped.Conn().ExecContext(ctx, "SELECT pg_sleep(3)")
+	require.Error(t, err)
+	appErr := dberror.FromErr(err)
+	assert.ErrorIs(t, appErr, dberror.ErrStatementTimeout)
+	assert.Contains(t, appErr.Error(), "statement timed out")
+
+	stats, requests, _, ok := PoolStats()
+	require.True(t, ok)
+	assert.Equal(t, config.DefaultDBMaxOpenConns, stats.MaxOpenConnections)
+	assert.GreaterOrEqual(t, requests, uint64(1))
+}
+
+func TestFromErr(t *testing.T) {
+	assert.ErrorIs(t, dberror.FromErr(&pgconn.PgError{Code: "57014"}), dberror.ErrStatementTimeout)
+	assert.ErrorIs(t, dberror.FromErr(context.DeadlineExceeded), dberror.ErrStatementTimeout)
+
+	err := dberror.FromErr(&pgconn.PgError{Code: "23505"})
+	assert.ErrorIs(t, err, dberror.ErrDatabase)
+	assert.False(t, errors.Is(err, dberror.ErrStatementTimeout))
+}
@@ -325,8 +325,9 @@ func TestUpdateSessionEnd(t *testing.T) {
 	// Update status and end session
 	var newStatus pgtype.JSONB
 	assert.NoError(t, newStatus.Set(`{"state": "completed"}`))
-	err := DB(ctx).UpdateSessionEnd(ctx, session.SessionID, "completed", newStatus.Bytes)
+	previous, err := DB(ctx).UpdateSessionEnd(ctx, session.SessionID, "completed", newStatus.Bytes)
 	assert.NoError(t, err)
+	assert.Equal(t, session.StatusSummary, previous)
 
 	retrieved, err := DB(ctx).GetSession(ctx, session.SessionID)
 	assert.NoError(t, err)
@@ -336,7 +337,13 @@ func TestUpdateSessionEnd(t *testing.T) {
 	assert.True(t, retrieved.EndedAt.After(time.Now().Add(-time.Second)))
 
 	// Update non-existent session
-	err = DB(ctx).UpdateSessionEnd(ctx, uuid.New(), "completed", newStatus.Bytes)
+	// Ending it again reports the status it was ended with
+	previous, err = DB(ctx).UpdateSessionEnd(ctx, session.SessionID, "failed", newStatus.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", previous)
+
+	// Update non-existent session
+	_, err = DB(ctx).UpdateSessionEnd(ctx, uuid.New(), "completed", newStatus.Bytes)
 	assert.ErrorIs(t, err, dberror.ErrNotFound)
 }
 
@@ -596,16 +603,169 @@ func TestListSessionsByCatalog(t *testing.T) {
 		},
 	}
 
+	sessions[0].Labels = []byte(`{"team": "data", "pipeline": "nightly"}`)
+	sessions[1].Labels = []byte(`{"team": "data"}`)
+
 	for i := range sessions {
 		assert.NoError(t, DB(ctx).UpsertSession(ctx, &sessions[i]))
 	}
 
 	// List sessions
-	retrieved, err := DB(ctx).ListSessionsByCatalog(ctx, catalog.CatalogID)
+	retrieved, err := DB(ctx).ListSessionsByCatalog(ctx, catalog.CatalogID, nil)
 	assert.NoError(t, err)
 	require.Len(t, retrieved, 3)
 
 	// Verify order (should be by created_at DESC)
 	assert.True(t, retrieved[0].CreatedAt.After(retrieved[1].CreatedAt))
 	assert.True(t, retrieved[1].CreatedAt.After(retrieved[2].CreatedAt))
+
+	// Sessions created without labels have none
+	assert.JSONEq(t, `{}`, string(retrieved[0].Labels))
+
+	// Filter by labels
+	retrieved, err = DB(ctx).ListSessionsByCatalog(ctx, catalog.CatalogID, map[string]string{"team": "data"})
+	assert.NoError(t, err)
+	assert.Len(t, retrieved, 2)
+
+	retrieved, err = DB(ctx).ListSessionsByCatalog(ctx, catalog.CatalogID, map[string]string{"team": "data", "pipeline": "nightly"})
+	assert.NoError(t, err)
+	require.Len(t, retrieved, 1)
+	assert.Equal(t, sessions[0].SessionID, retrieved[0].SessionID)
+	assert.JSONEq(t, `{"team": "data", "pipeline": "nightly"}`, string(retrieved[0].Labels))
+
+	retrieved, err = DB(ctx).ListSessionsByCatalog(ctx, catalog.CatalogID, map[string]string{"team": "web"})
+	assert.NoError(t, err)
+	assert.Empty(t, retrieved)
+}
+
+func TestListPrunableSessions(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	assert.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	var info pgtype.JSONB
+	assert.NoError(t, info.Set(`{"meta": "prune_test"}`))
+
+	catalog := models.Catalog{Name: "test_catalog", Info: info}
+	assert.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	cutoff := time.Now().Add(-24 * time.Hour)
+	newSession := func(statusSummary string, status string, endedAt time.Time) uuid.UUID {
+		session := models.Session{
+			SessionID:     uuid.New(),
+			SkillSet:      "skillset",
+			Skill:         "skill",
+			ViewID:        uuid.New(),
+			TangentID:     uuid.New(),
+			StatusSummary: statusSummary,
+			Status:        json.RawMessage(status),
+			Info:          info.Bytes,
+			UserID:        "test_user",
+			CatalogID:     catalog.CatalogID,
+			VariantID:     uuid.New(),
+			StartedAt:     endedAt.Add(-time.Hour),
+			EndedAt:       endedAt,
+			ExpiresAt:     endedAt.Add(time.Hour),
+		}
+		require.NoError(t, DB(ctx).UpsertSession(ctx, &session))
+		return session.SessionID
+	}
+
+	oldest := newSession("completed", `{"auditLog": "/logs/a.tlog"}`, cutoff.Add(-48*time.Hour))
+	older := newSession("failed", `{"auditLog": "/logs/b.tlog"}`, cutoff.Add(-time.Hour))
+	newSession("completed", `{"auditLog": "/logs/c.tlog"}`, cutoff.Add(time.Hour)) // within retention
+	newSession("running", `{"auditLog": "/logs/d.tlog"}`, cutoff.Add(-time.Hour))  // not in a prunable status
+	newSession("completed", `{"auditLog": ""}`, cutoff.Add(-time.Hour))            // already pruned
+
+	statuses := []string{"completed", "failed"}
+	sessions, err := DB(ctx).ListPrunableSessions(ctx, cutoff, statuses, 10)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, oldest, sessions[0].SessionID)
+	assert.Equal(t, older, sessions[1].SessionID)
+	assert.Equal(t, tenantID, sessions[0].TenantID)
+
+	sessions, err = DB(ctx).ListPrunableSessions(ctx, cutoff, statuses, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, oldest, sessions[0].SessionID)
+
+	_, err = DB(ctx).ListPrunableSessions(ctx, cutoff, statuses, 0)
+	assert.Error(t, err)
+}
+
+func TestListSessionsByIDs(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	assert.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	var info pgtype.JSONB
+	assert.NoError(t, info.Set(`{"meta": "batch_test"}`))
+
+	catalog := models.Catalog{Name: "test_catalog", Info: info}
+	assert.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	var sessionIDs []uuid.UUID
+	for range 3 {
+		session := models.Session{
+			SessionID:     uuid.New(),
+			SkillSet:      "skillset",
+			Skill:         "skill",
+			ViewID:        uuid.New(),
+			TangentID:     uuid.New(),
+			StatusSummary: "running",
+			Status:        json.RawMessage(`{}`),
+			Info:          info.Bytes,
+			UserID:        "test_user",
+			CatalogID:     catalog.CatalogID,
+			VariantID:     uuid.New(),
+			StartedAt:     time.Now(),
+			ExpiresAt:     time.Now().Add(time.Hour),
+		}
+		require.NoError(t, DB(ctx).UpsertSession(ctx, &session))
+		sessionIDs = append(sessionIDs, session.SessionID)
+	}
+
+	missing := uuid.New()
+	sessions, err := DB(ctx).ListSessionsByIDs(ctx, []uuid.UUID{sessionIDs[0], missing, sessionIDs[2]})
+	require.NoError(t, err)
+	var found []uuid.UUID
+	for _, s := range sessions {
+		found = append(found, s.SessionID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{sessionIDs[0], sessionIDs[2]}, found)
+
+	sessions, err = DB(ctx).ListSessionsByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	// sessions of other tenants are not visible
+	otherCtx := catcommon.WithTenantID(ctx, "TOTHER")
+	sessions, err = DB(otherCtx).ListSessionsByIDs(otherCtx, sessionIDs)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
 }
@@ -136,6 +136,25 @@ func TestSkillSetOperations(t *testing.T) {
 	err = DB(ctx).UpsertSkillSetObject(ctx, ss, obj, variant.SkillsetDirectoryID)
 	require.NoError(t, err)
 
+	// A skillset saved without search text is listed for backfill until it is indexed
+	isUnindexed := func() bool {
+		entries, err := DB(ctx).ListUnindexedSkillSets(ctx, 1000)
+		require.NoError(t, err)
+		for _, e := range entries {
+			if e.TenantID == tenantID && e.DirectoryID == variant.SkillsetDirectoryID && e.Path == ss.Path {
+				assert.Equal(t, ss.Hash, e.Hash)
+				assert.Empty(t, e.Namespace)
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, isUnindexed())
+	indexed := *ss
+	indexed.Search = &models.SkillSetSearch{Name: "/test/skillset", SkillNames: []string{"test-skill"}}
+	require.NoError(t, DB(ctx).UpsertSkillSetSearch(ctx, &indexed, variant.SkillsetDirectoryID))
+	assert.False(t, isUnindexed())
+
 	// Test GetSkillSet
 	retrievedSS, err := DB(ctx).GetSkillSet(ctx, ss.Path, variant.VariantID, variant.SkillsetDirectoryID)
 	assert.NoError(t, err)
@@ -217,6 +236,18 @@ func TestSkillSetOperations(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUpdatedMetadata, listedMetadata)
 
+	// An object still referenced by a recorded version is not deleted
+	versioned := *ss
+	versioned.Hash = "versioned_hash_123456789012"
+	versioned.Version = "1.0.0"
+	versionedObj := *obj
+	versionedObj.Hash = versioned.Hash
+	require.NoError(t, DB(ctx).UpsertSkillSetObject(ctx, &versioned, &versionedObj, variant.SkillsetDirectoryID))
+	require.NoError(t, DB(ctx).UpdateSkillSet(ctx, ss, variant.SkillsetDirectoryID))
+	require.NoError(t, DB(ctx).DeleteCatalogObject(ctx, catcommon.CatalogObjectTypeSkillset, versioned.Hash))
+	_, err = DB(ctx).GetSkillSetVersionObject(ctx, ss.Path, versioned.Version, variant.SkillsetDirectoryID)
+	assert.NoError(t, err)
+
 	// Test DeleteSkillSet
 	deletedHash, err := DB(ctx).DeleteSkillSet(ctx, ss.Path, variant.SkillsetDirectoryID)
 	assert.NoError(t, err)
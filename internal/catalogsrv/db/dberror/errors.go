@@ -1,8 +1,11 @@
 package dberror
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
+	"github.com/jackc/pgconn"
 	"github.com/tansive/tansive/internal/common/apperrors"
 )
 
@@ -10,11 +13,28 @@ var (
 	ErrDatabase                  apperrors.Error = apperrors.New("db error").SetStatusCode(http.StatusInternalServerError)
 	ErrAlreadyExists             apperrors.Error = ErrDatabase.New("already exists").SetStatusCode(http.StatusConflict)
 	ErrNotFound                  apperrors.Error = ErrDatabase.New("not found").SetStatusCode(http.StatusNotFound)
+	ErrConflict                  apperrors.Error = ErrDatabase.New("conflicting update").SetStatusCode(http.StatusConflict)
 	ErrInvalidInput              apperrors.Error = ErrDatabase.New("invalid input").SetStatusCode(http.StatusBadRequest)
 	ErrInvalidCatalog            apperrors.Error = ErrDatabase.New("invalid catalog").SetStatusCode(http.StatusBadRequest)
 	ErrInvalidVariant            apperrors.Error = ErrDatabase.New("invalid variant").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidObject             apperrors.Error = ErrDatabase.New("invalid object").SetStatusCode(http.StatusInternalServerError)
 	ErrMissingTenantID           apperrors.Error = ErrInvalidInput.New("missing tenant ID").SetStatusCode(http.StatusBadRequest)
 	ErrMissingProjecID           apperrors.Error = ErrInvalidInput.New("missing project ID").SetStatusCode(http.StatusBadRequest)
 	ErrMissingUserContext        apperrors.Error = ErrInvalidInput.New("missing user context").SetStatusCode(http.StatusBadRequest)
 	ErrNoAncestorReferencesFound apperrors.Error = ErrDatabase.New("no ancestor references found").SetStatusCode(http.StatusBadRequest)
+	ErrStatementTimeout          apperrors.Error = ErrDatabase.New("statement timed out").SetStatusCode(http.StatusServiceUnavailable)
+	ErrQuotaExceeded             apperrors.Error = ErrDatabase.New("quota exceeded").SetStatusCode(http.StatusForbidden)
 )
+
+// pgQueryCanceled is the SQLSTATE reported when a statement is cancelled, including by statement_timeout
+const pgQueryCanceled = "57014"
+
+// FromErr wraps a query error. Statements cancelled by the statement timeout or by an expired
+// context map to ErrStatementTimeout so callers get a clear error instead of a generic db error.
+func FromErr(err error) apperrors.Error {
+	var pgErr *pgconn.PgError
+	if (errors.As(err, &pgErr) && pgErr.Code == pgQueryCanceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrStatementTimeout.Err(err)
+	}
+	return ErrDatabase.Err(err)
+}
@@ -13,6 +13,10 @@ type ScopedDb interface {
 	Conn(ctx context.Context) (ScopedConn, error)
 	// Stats returns the number of connection requests and returns.
 	Stats() (requests, returns uint64)
+	// PoolStats returns the statistics of the underlying connection pool.
+	PoolStats() sql.DBStats
+	// Close closes the connection pools of the database, including that of the read replica.
+	Close() error
 }
 
 type ScopedConn interface {
@@ -29,6 +33,13 @@ type ScopedConn interface {
 	// Conn returns the underlying *sql.Conn. Do not close this directly.
 	// Use ScopedConn.Close(ctx) to ensure scopes are dropped safely.
 	Conn() *sql.Conn
+	// ReadConn returns a connection for read-only queries. It is a connection to the read replica
+	// if one is configured and reachable, and the connection returned by Conn otherwise.
+	// Do not close this directly.
+	ReadConn(ctx context.Context) *sql.Conn
+	// ReadFailed reports that a query on the connection returned by ReadConn failed. The replica
+	// is then bypassed for a while, so that ReadConn returns the connection returned by Conn.
+	ReadFailed(ctx context.Context)
 	// Close drops all scopes and returns the connection back to the pool.
 	Close(ctx context.Context)
 }
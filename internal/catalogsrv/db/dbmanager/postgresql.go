@@ -4,6 +4,7 @@ package dbmanager
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"sync/atomic"
@@ -19,6 +20,7 @@ import (
 // postgresConn represents a connection to the PostgreSQL database.
 type postgresConn struct {
 	conn             *sql.Conn
+	replicaConn      *sql.Conn
 	cancel           context.CancelFunc
 	scopes           map[string]string
 	configuredScopes []string
@@ -31,6 +33,26 @@ type postgresPool struct {
 	connRequests     uint64
 	connReturns      uint64
 	db               *sql.DB
+	replica          *replicaDb
+	statementTimeout time.Duration
+}
+
+// replicaRetryInterval is how long the read replica is bypassed after it failed to provide a connection
+const replicaRetryInterval = 30 * time.Second
+
+// replicaDb is the read replica of a postgresPool. Read-only queries fall back to the primary
+// while the replica is unhealthy.
+type replicaDb struct {
+	db             *sql.DB
+	unhealthyUntil atomic.Int64
+}
+
+func (r *replicaDb) healthy() bool {
+	return time.Now().UnixNano() >= r.unhealthyUntil.Load()
+}
+
+func (r *replicaDb) markUnhealthy() {
+	r.unhealthyUntil.Store(time.Now().Add(replicaRetryInterval).UnixNano())
 }
 
 // validScopeNameRegex ensures scope names are valid PostgreSQL identifiers
@@ -51,6 +73,7 @@ func NewPostgresqlDb(configuredScopes []string) (ScopedDb, error) {
 	}
 
 	dsn := config.HatchCatalogDsn()
+	settings := config.HatchCatalogPoolSettings()
 
 	sqlDB, err := sql.Open("pgx", dsn)
 	if err != nil {
@@ -59,10 +82,10 @@ func NewPostgresqlDb(configuredScopes []string) (ScopedDb, error) {
 	}
 
 	// Configure connection pool settings
-	sqlDB.SetMaxOpenConns(50)
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetConnMaxLifetime(30 * time.Minute)
-	sqlDB.SetConnMaxIdleTime(5 * time.Minute)
+	sqlDB.SetMaxOpenConns(settings.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(settings.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(settings.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(settings.ConnMaxIdleTime)
 
 	err = sqlDB.Ping()
 	if err != nil {
@@ -70,10 +93,32 @@ func NewPostgresqlDb(configuredScopes []string) (ScopedDb, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &postgresPool{
+	pool := &postgresPool{
 		configuredScopes: configuredScopes,
 		db:               sqlDB,
-	}, nil
+		statementTimeout: settings.StatementTimeout,
+	}
+
+	if replicaDsn := config.HatchCatalogReplicaDsn(); replicaDsn != "" {
+		replicaDB, err := sql.Open("pgx", replicaDsn)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to open read replica")
+			return nil, fmt.Errorf("failed to open read replica connection: %w", err)
+		}
+		replicaDB.SetMaxOpenConns(settings.MaxOpenConns)
+		replicaDB.SetMaxIdleConns(settings.MaxIdleConns)
+		replicaDB.SetConnMaxLifetime(settings.ConnMaxLifetime)
+		replicaDB.SetConnMaxIdleTime(settings.ConnMaxIdleTime)
+
+		pool.replica = &replicaDb{db: replicaDB}
+		// An unreachable replica does not prevent startup; reads use the primary until it recovers
+		if err := replicaDB.Ping(); err != nil {
+			log.Warn().Err(err).Msg("failed to ping read replica, using primary for reads")
+			pool.replica.markUnhealthy()
+		}
+	}
+
+	return pool, nil
 }
 
 // Conn returns a new connection to the PostgreSQL database from the connection pool.
@@ -95,21 +140,10 @@ func (p *postgresPool) Conn(ctx context.Context) (ScopedConn, error) {
 		}
 	}()
 
-	sessionParams := map[string]string{
-		"lock_timeout":                        "5s",
-		"statement_timeout":                   "5s",
-		"idle_in_transaction_session_timeout": "5s",
-	}
-
-	for param, value := range sessionParams {
-		// For SET commands, we need to properly quote both the parameter and value
-		query := fmt.Sprintf("SET %s = %s", formatSQLIdentifier(param), pq.QuoteLiteral(value))
-		_, err = conn.ExecContext(ctx, query)
-		if err != nil {
-			cancel()
-			conn.Close()
-			return nil, fmt.Errorf("failed to set %s: %w", param, err)
-		}
+	if err := p.setSessionParams(ctx, conn); err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
 	}
 
 	h := &postgresConn{
@@ -130,11 +164,45 @@ func (p *postgresPool) Conn(ctx context.Context) (ScopedConn, error) {
 	return h, nil
 }
 
+// setSessionParams sets the timeouts of a connection newly obtained from the primary or replica pool.
+func (p *postgresPool) setSessionParams(ctx context.Context, conn *sql.Conn) error {
+	statementTimeout := fmt.Sprintf("%dms", p.statementTimeout.Milliseconds())
+	sessionParams := map[string]string{
+		"lock_timeout":                        "5s",
+		"statement_timeout":                   statementTimeout,
+		"idle_in_transaction_session_timeout": "5s",
+	}
+
+	for param, value := range sessionParams {
+		// For SET commands, we need to properly quote both the parameter and value
+		query := fmt.Sprintf("SET %s = %s", formatSQLIdentifier(param), pq.QuoteLiteral(value))
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to set %s: %w", param, err)
+		}
+	}
+	return nil
+}
+
 // Stats returns the number of connection requests and returns made to the PostgreSQL database.
 func (p *postgresPool) Stats() (requests, returns uint64) {
 	return atomic.LoadUint64(&p.connRequests), atomic.LoadUint64(&p.connReturns)
 }
 
+// PoolStats returns the connection pool statistics of the underlying database handle.
+func (p *postgresPool) PoolStats() sql.DBStats {
+	return p.db.Stats()
+}
+
+// Close closes the read replica and primary connection pools.
+func (p *postgresPool) Close() error {
+	var errs []error
+	if p.replica != nil {
+		errs = append(errs, p.replica.db.Close())
+	}
+	errs = append(errs, p.db.Close())
+	return errors.Join(errs...)
+}
+
 // OpenConns returns the number of open connections in the pool.
 func (p *postgresPool) OpenConns() int {
 	return p.db.Stats().OpenConnections
@@ -146,6 +214,8 @@ func (h *postgresConn) Close(ctx context.Context) {
 		return
 	}
 
+	h.releaseReplica(ctx)
+
 	if err := h.DropAllScopes(ctx); err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to drop all scopes during connection close")
 	}
@@ -176,6 +246,9 @@ func (h *postgresConn) AddScopes(ctx context.Context, scopes map[string]string)
 		return fmt.Errorf("no active connection")
 	}
 
+	// The replica connection is obtained again with the new scopes when next needed
+	h.releaseReplica(ctx)
+
 	for scope := range scopes {
 		if !validScopeNameRegex.MatchString(scope) {
 			return fmt.Errorf("invalid scope name: %s", scope)
@@ -212,6 +285,8 @@ func (h *postgresConn) AddScope(ctx context.Context, scope, value string) error
 		return fmt.Errorf("no active connection")
 	}
 
+	h.releaseReplica(ctx)
+
 	if !validScopeNameRegex.MatchString(scope) {
 		return fmt.Errorf("invalid scope name: %s", scope)
 	}
@@ -243,6 +318,8 @@ func (h *postgresConn) DropScopes(ctx context.Context, scopes []string) error {
 		return nil
 	}
 
+	h.releaseReplica(ctx)
+
 	for _, scope := range scopes {
 		if !validScopeNameRegex.MatchString(scope) {
 			return fmt.Errorf("invalid scope name: %s", scope)
@@ -277,6 +354,8 @@ func (h *postgresConn) DropScope(ctx context.Context, scope string) error {
 		return nil
 	}
 
+	h.releaseReplica(ctx)
+
 	if !validScopeNameRegex.MatchString(scope) {
 		return fmt.Errorf("invalid scope name: %s", scope)
 	}
@@ -299,3 +378,65 @@ func (h *postgresConn) DropAllScopes(ctx context.Context) error {
 func (h *postgresConn) Conn() *sql.Conn {
 	return h.conn
 }
+
+// ReadConn returns the replica connection of the PostgresConn, obtaining it with the current scopes
+// on first use. It returns the primary connection if no replica is configured or the replica is
+// unhealthy.
+func (h *postgresConn) ReadConn(ctx context.Context) *sql.Conn {
+	if h.replicaConn != nil {
+		return h.replicaConn
+	}
+	replica := h.pool.replica
+	if replica == nil || !replica.healthy() {
+		return h.conn
+	}
+
+	conn, err := replica.db.Conn(ctx)
+	if err == nil {
+		err = h.pool.setSessionParams(ctx, conn)
+		for scope, value := range h.scopes {
+			if err != nil {
+				break
+			}
+			query := fmt.Sprintf("SET %s = %s", formatSQLIdentifier(scope), pq.QuoteLiteral(value))
+			_, err = conn.ExecContext(ctx, query)
+		}
+		if err != nil {
+			conn.Close()
+		}
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("read replica unavailable, using primary for reads")
+		replica.markUnhealthy()
+		return h.conn
+	}
+
+	h.replicaConn = conn
+	return conn
+}
+
+// ReadFailed releases the replica connection of the PostgresConn and marks the replica unhealthy,
+// so that reads use the primary until the replica is retried.
+func (h *postgresConn) ReadFailed(ctx context.Context) {
+	if h.replicaConn == nil {
+		return
+	}
+	h.releaseReplica(ctx)
+	h.pool.replica.markUnhealthy()
+}
+
+// releaseReplica resets the scopes of the replica connection and returns it to the replica pool.
+func (h *postgresConn) releaseReplica(ctx context.Context) {
+	if h.replicaConn == nil {
+		return
+	}
+	for _, scope := range h.configuredScopes {
+		query := fmt.Sprintf("RESET %s", formatSQLIdentifier(scope))
+		if _, err := h.replicaConn.ExecContext(ctx, query); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to reset replica scopes")
+			break
+		}
+	}
+	h.replicaConn.Close()
+	h.replicaConn = nil
+}
@@ -0,0 +1,115 @@
+package dbmanager
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver that accepts every statement. Opening the database named
+// "down" fails, as an unreachable server would.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	if name == "down" {
+		return nil, errors.New("connection refused")
+	}
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+func (fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func init() {
+	sql.Register("dbmanager-fake", fakeDriver{})
+}
+
+func TestReadConn(t *testing.T) {
+	ctx := context.Background()
+	open := func(name string) *sql.DB {
+		sqlDB, err := sql.Open("dbmanager-fake", name)
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		return sqlDB
+	}
+	scopes := []string{"tansive.curr_tenantid"}
+
+	t.Run("replica", func(t *testing.T) {
+		pool := &postgresPool{configuredScopes: scopes, db: open("primary"), replica: &replicaDb{db: open("replica")}}
+		conn, err := pool.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+		require.NoError(t, conn.AddScope(ctx, "tansive.curr_tenantid", "TABCDE"))
+
+		readConn := conn.ReadConn(ctx)
+		assert.NotSame(t, conn.Conn(), readConn)
+		assert.Same(t, readConn, conn.ReadConn(ctx), "the replica connection should be reused")
+
+		// changing scopes releases the replica connection so it is obtained again with the new scopes
+		require.NoError(t, conn.DropScope(ctx, "tansive.curr_tenantid"))
+		assert.Nil(t, conn.(*postgresConn).replicaConn)
+		assert.NotSame(t, conn.Conn(), conn.ReadConn(ctx))
+	})
+
+	t.Run("failed read", func(t *testing.T) {
+		replica := &replicaDb{db: open("replica")}
+		pool := &postgresPool{configuredScopes: scopes, db: open("primary"), replica: replica}
+		conn, err := pool.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+
+		assert.NotSame(t, conn.Conn(), conn.ReadConn(ctx))
+		conn.ReadFailed(ctx)
+		assert.Nil(t, conn.(*postgresConn).replicaConn)
+		assert.False(t, replica.healthy())
+		assert.Same(t, conn.Conn(), conn.ReadConn(ctx))
+	})
+
+	t.Run("no replica", func(t *testing.T) {
+		pool := &postgresPool{configuredScopes: scopes, db: open("primary")}
+		conn, err := pool.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+		assert.Same(t, conn.Conn(), conn.ReadConn(ctx))
+	})
+
+	t.Run("unhealthy replica", func(t *testing.T) {
+		replica := &replicaDb{db: open("down")}
+		pool := &postgresPool{configuredScopes: scopes, db: open("primary"), replica: replica}
+		conn, err := pool.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+
+		assert.Same(t, conn.Conn(), conn.ReadConn(ctx))
+		assert.False(t, replica.healthy())
+	})
+}
+
+func TestPoolClose(t *testing.T) {
+	primary, err := sql.Open("dbmanager-fake", "primary")
+	require.NoError(t, err)
+	replica, err := sql.Open("dbmanager-fake", "replica")
+	require.NoError(t, err)
+	pool := &postgresPool{db: primary, replica: &replicaDb{db: replica}}
+
+	require.NoError(t, pool.Close())
+	assert.Error(t, primary.Ping())
+	assert.Error(t, replica.Ping())
+}
@@ -0,0 +1,11 @@
+package models
+
+// SearchResult is a catalog object matching a search query.
+// Variant and Namespace are empty for objects that are not scoped to them, such as views.
+type SearchResult struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Variant     string `json:"variant,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -17,6 +17,7 @@ type Session struct {
 	StatusSummary string             `db:"status_summary"`
 	Status        json.RawMessage    `db:"status"`
 	Info          json.RawMessage    `db:"info"`
+	Labels        json.RawMessage    `db:"labels"`
 	UserID        string             `db:"user_id"`
 	CatalogID     uuid.UUID          `db:"catalog_id"`
 	VariantID     uuid.UUID          `db:"variant_id"`
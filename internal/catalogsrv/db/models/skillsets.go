@@ -17,4 +17,28 @@ type SkillSet struct {
 	TenantID  catcommon.TenantId `db:"tenant_id"`
 	CreatedAt time.Time          `db:"created_at"`
 	UpdatedAt time.Time          `db:"updated_at"`
+	// Search holds the text indexed for catalog search. It is not part of the directory entry.
+	Search *SkillSetSearch `db:"-"`
+	// Version is the spec version of the skillset. When set, the hash is recorded as that version.
+	Version string `db:"-"`
+	// PrevHash, when set, is the hash the skillset must still be stored with for an update to apply.
+	PrevHash string `db:"-"`
+}
+
+// SkillSetSearch is the searchable text of a skillset.
+type SkillSetSearch struct {
+	Name        string   `db:"name"`
+	Namespace   string   `db:"namespace"`
+	Description string   `db:"description"`
+	SkillNames  []string `db:"skills"`
+}
+
+// UnindexedSkillSet is a skillset directory entry that has no catalog search entry.
+// Namespace is empty for skillsets in the default namespace.
+type UnindexedSkillSet struct {
+	TenantID    catcommon.TenantId `db:"tenant_id"`
+	DirectoryID uuid.UUID          `db:"directory_id"`
+	Path        string             `db:"path"`
+	Hash        string             `db:"hash"`
+	Namespace   string             `db:"namespace"`
 }
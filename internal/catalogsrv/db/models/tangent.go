@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tansive/tansive/internal/common/uuid"
 )
 
@@ -13,6 +14,13 @@ type Tangent struct {
 	PublicKey []byte          `db:"public_key"`
 	Status    string          `db:"status"`
 	TenantID  string          `db:"tenant_id"`
-	CreatedAt time.Time       `db:"created_at"`
-	UpdatedAt time.Time       `db:"updated_at"`
+	// LastHeartbeatAt is when the tangent last registered or sent a heartbeat.
+	LastHeartbeatAt time.Time `db:"last_heartbeat_at"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// KeyAlgorithm returns the signing algorithm of the tangent's registered public key.
+func (t *Tangent) KeyAlgorithm() (string, error) {
+	return tangentsig.PublicKeyAlgorithm(t.PublicKey)
 }
@@ -15,6 +15,7 @@ Column    |           Type           | Collation | Nullable | Default
  description | character varying(1024)  |           |          |
  info        | jsonb                    |           |          |
  rules       | jsonb                    |           | not null |
+ token_epoch | bigint                   |           | not null | 0
  catalog_id  | uuid                     |           | not null |
  tenant_id   | character varying(10)    |           | not null |
  created_at  | timestamp with time zone |           |          | now()
@@ -35,6 +36,7 @@ type View struct {
 	Description string             `db:"description"`
 	Info        []byte             `db:"info"`
 	Rules       []byte             `db:"rules"`
+	TokenEpoch  int64              `db:"token_epoch"`
 	CatalogID   uuid.UUID          `db:"catalog_id"`
 	TenantID    catcommon.TenantId `db:"tenant_id"`
 	Catalog     string             `db:"-"`
@@ -12,6 +12,7 @@ type ViewToken struct {
 	TokenID   uuid.UUID          `json:"token_id"`
 	ViewID    uuid.UUID          `json:"view_id"`
 	TenantID  catcommon.TenantId `json:"tenant_id"`
+	SessionID uuid.UUID          `json:"session_id"` // the session the token was issued to, if any
 	ExpireAt  time.Time          `json:"expire_at"`
 	CreatedAt time.Time          `json:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at"`
@@ -74,6 +74,10 @@ func (mm *metadataManager) CreateCatalog(ctx context.Context, catalog *models.Ca
 	}
 	catalog.CatalogID = insertedCatalogID
 
+	if err = reserveUsageInTx(ctx, tx, tenantID, usageCatalogs); err != nil {
+		return err
+	}
+
 	// create default variant
 	variant := models.Variant{
 		Name:        catcommon.DefaultVariant,
@@ -117,7 +121,7 @@ func (mm *metadataManager) CreateCatalog(ctx context.Context, catalog *models.Ca
 	// Commit the transaction if both insertions succeed
 	if err := tx.Commit(); err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to commit transaction")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -299,14 +303,14 @@ func (mm *metadataManager) DeleteCatalog(ctx context.Context, catalogID uuid.UUI
 		_, err := mm.conn().ExecContext(ctx, query, tenantID, projectID, catalogID)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Str("catalog_id", catalogID.String()).Msg("failed to delete catalog")
-			return dberror.ErrDatabase.Err(err)
+			return dberror.FromErr(err)
 		}
 	} else {
 		query += "tenant_id = $1 AND project_id = $2 AND name = $3;"
 		_, err := mm.conn().ExecContext(ctx, query, tenantID, projectID, name)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Str("name", name).Msg("failed to delete catalog")
-			return dberror.ErrDatabase.Err(err)
+			return dberror.FromErr(err)
 		}
 	}
 
@@ -334,7 +338,7 @@ func (mm *metadataManager) ListCatalogs(ctx context.Context) ([]*models.Catalog,
 
 	rows, err := mm.conn().QueryContext(ctx, query, tenantID, projectID)
 	if err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	defer rows.Close()
 
@@ -345,13 +349,13 @@ func (mm *metadataManager) ListCatalogs(ctx context.Context) ([]*models.Catalog,
 		err := rows.Scan(&catalog.CatalogID, &catalog.Name, &catalog.Description, &catalog.Info, &catalog.ProjectID)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan catalog row")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 		catalogs = append(catalogs, &catalog)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return catalogs, nil
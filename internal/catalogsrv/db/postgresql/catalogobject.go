@@ -55,13 +55,13 @@ func (om *objectManager) CreateCatalogObject(ctx context.Context, obj *models.Ca
 	`
 	result, err := om.conn().ExecContext(ctx, query, obj.HashID, obj.Hash, obj.Type, obj.Version, tenantID, dataZ)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	// Check if the row was inserted
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	// If no rows were affected, it means the object already exists
@@ -104,7 +104,7 @@ func (om *objectManager) GetCatalogObject(ctx context.Context, hash string) (*mo
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("catalog object not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	// Uncompress the data
@@ -112,7 +112,7 @@ func (om *objectManager) GetCatalogObject(ctx context.Context, hash string) (*mo
 		obj.Data, err = snappy.Decode(nil, obj.Data)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to uncompress catalog object data")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 	}
 
@@ -161,6 +161,22 @@ func (om *objectManager) DeleteCatalogObject(ctx context.Context, t catcommon.Ca
 		// do nothing. There are other references to this object
 		return nil
 	}
+	if t == catcommon.CatalogObjectTypeSkillset {
+		// recorded versions of a skillset keep referring to its object after the skillset changes
+		query = `
+			SELECT 1
+			FROM skillset_versions
+			WHERE tenant_id = $1 AND hash = $2
+			LIMIT 1;
+		`
+		dberr = om.conn().QueryRowContext(ctx, query, tenantID, hash).Scan(&exists)
+		if dberr != nil && dberr != sql.ErrNoRows {
+			return dberror.ErrDatabase.Err(dberr)
+		}
+		if exists {
+			return nil
+		}
+	}
 	hashID := hash[:16]
 	query = `
 		DELETE FROM catalog_objects
@@ -168,13 +184,13 @@ func (om *objectManager) DeleteCatalogObject(ctx context.Context, t catcommon.Ca
 	`
 	result, err := om.conn().ExecContext(ctx, query, tenantID, hashID, hash)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	// Check if the row was deleted
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	// If no rows were affected, it means the object does not exist
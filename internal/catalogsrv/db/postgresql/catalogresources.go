@@ -0,0 +1,119 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// ListCatalogResourcePaths returns the resource paths of the catalog itself and of its views,
+// variants, namespaces, resources and skillsets, such as
+// res://catalogs/<catalog>/variants/<variant>/namespaces/<namespace>/skillsets/<path>. Resources
+// and skillsets stored under a namespace of their variant are addressed through that namespace.
+// Paths are ordered bytewise, and up to limit paths sorting after the after path are returned.
+func (mm *metadataManager) ListCatalogResourcePaths(ctx context.Context, catalogID uuid.UUID, after string, limit int) ([]string, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+	if catalogID == uuid.Nil {
+		return nil, dberror.ErrInvalidInput.Msg("invalid catalog ID")
+	}
+	if limit <= 0 {
+		return nil, dberror.ErrInvalidInput.Msg("limit must be positive")
+	}
+
+	// The storage path of a directory object starts with the default namespace, followed by
+	// the namespace name when the object is in another namespace of the variant.
+	query := `
+		WITH c AS (
+			SELECT 'res://' || $5::text || '/' || name AS path
+			FROM catalogs
+			WHERE tenant_id = $1 AND catalog_id = $2
+		),
+		v AS (
+			SELECT v.variant_id, v.resource_directory, v.skillset_directory, c.path || '/' || $6::text || '/' || v.name AS path
+			FROM variants v, c
+			WHERE v.tenant_id = $1 AND v.catalog_id = $2
+		),
+		objects AS (
+			SELECT v.variant_id, v.path AS variant_path, $9::text AS kind, k.key AS storage_path
+			FROM v
+			JOIN resource_directory d ON d.tenant_id = $1 AND d.directory_id = v.resource_directory
+			CROSS JOIN LATERAL jsonb_object_keys(d.directory) k(key)
+			UNION ALL
+			SELECT v.variant_id, v.path AS variant_path, $10::text AS kind, k.key AS storage_path
+			FROM v
+			JOIN skillset_directory d ON d.tenant_id = $1 AND d.directory_id = v.skillset_directory
+			CROSS JOIN LATERAL jsonb_object_keys(d.directory) k(key)
+		),
+		trimmed AS (
+			SELECT variant_id, variant_path, kind,
+				CASE WHEN starts_with(storage_path, '/' || $3::text)
+					THEN substr(storage_path, length($3::text) + 2)
+					ELSE storage_path END AS rel_path
+			FROM objects
+		),
+		rel AS (
+			SELECT variant_id, variant_path, kind,
+				CASE WHEN starts_with(rel_path, '/') THEN substr(rel_path, 2) ELSE rel_path END AS rel_path
+			FROM trimmed
+		)
+		SELECT DISTINCT p.path COLLATE "C" AS path FROM (
+			SELECT path FROM c
+			UNION ALL
+			SELECT c.path || '/' || $7::text || '/' || views.label
+			FROM views, c
+			WHERE views.tenant_id = $1 AND views.catalog_id = $2 AND views.label IS NOT NULL
+			UNION ALL
+			SELECT path FROM v
+			UNION ALL
+			SELECT v.path || '/' || $8::text || '/' || n.name
+			FROM namespaces n
+			JOIN v ON n.variant_id = v.variant_id
+			WHERE n.tenant_id = $1
+			UNION ALL
+			SELECT r.variant_path || CASE WHEN n.name IS NULL
+				THEN '/' || r.kind || '/' || r.rel_path
+				ELSE '/' || $8::text || '/' || n.name || '/' || r.kind || '/' || substr(r.rel_path, length(n.name) + 2) END
+			FROM rel r
+			LEFT JOIN LATERAL (
+				SELECT ns.name FROM namespaces ns
+				WHERE ns.tenant_id = $1 AND ns.variant_id = r.variant_id
+					AND starts_with(r.rel_path, ns.name || '/')
+				LIMIT 1
+			) n ON true
+		) p
+		WHERE p.path COLLATE "C" > $4::text
+		ORDER BY path
+		LIMIT $11;`
+
+	rows, err := mm.conn().QueryContext(ctx, query,
+		tenantID, catalogID, catcommon.DefaultNamespace, after,
+		catcommon.KindNameCatalogs, catcommon.KindNameVariants, catcommon.KindNameViews,
+		catcommon.KindNameNamespaces, catcommon.KindNameResources, catcommon.KindNameSkillsets, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("catalog_id", catalogID.String()).Msg("failed to list catalog resources")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	paths := []string{}
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan catalog resource path")
+			return nil, dberror.FromErr(err)
+		}
+		paths = append(paths, path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+
+	return paths, nil
+}
@@ -3,35 +3,53 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/db/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db/dbmanager"
 )
 
 // Metadata Manager
 type metadataManager struct {
-	c dbmanager.ScopedConn
+	c                  dbmanager.ScopedConn
+	slowQueryThreshold time.Duration
+}
+
+func (mm *metadataManager) conn() *tracedConn {
+	return &tracedConn{Conn: mm.c.Conn(), slowQueryThreshold: mm.slowQueryThreshold}
 }
 
-func (mm *metadataManager) conn() *sql.Conn {
-	return mm.c.Conn()
+// readQuery runs a read-heavy query that tolerates replication lag. It is served from the read
+// replica when one is configured and reachable, and run again on the primary if it fails there.
+func (mm *metadataManager) readQuery(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	readConn := mm.c.ReadConn(ctx)
+	rows, err := (&tracedConn{Conn: readConn, slowQueryThreshold: mm.slowQueryThreshold}).QueryContext(ctx, query, args...)
+	if err == nil || readConn == mm.c.Conn() || ctx.Err() != nil {
+		return rows, err
+	}
+	log.Ctx(ctx).Warn().Err(err).Msg("query on read replica failed, retrying on primary")
+	mm.c.ReadFailed(ctx)
+	return mm.conn().QueryContext(ctx, query, args...)
 }
 
 func newMetadataManager(c dbmanager.ScopedConn) *metadataManager {
-	return &metadataManager{c: c}
+	return &metadataManager{c: c, slowQueryThreshold: config.HatchCatalogSlowQueryThreshold()}
 }
 
 // Object Manager
 type objectManager struct {
-	c dbmanager.ScopedConn
-	m *metadataManager
+	c                  dbmanager.ScopedConn
+	m                  *metadataManager
+	slowQueryThreshold time.Duration
 }
 
-func (om *objectManager) conn() *sql.Conn {
-	return om.c.Conn()
+func (om *objectManager) conn() *tracedConn {
+	return &tracedConn{Conn: om.c.Conn(), slowQueryThreshold: om.slowQueryThreshold}
 }
 
 func newObjectManager(c dbmanager.ScopedConn) *objectManager {
-	return &objectManager{c: c}
+	return &objectManager{c: c, slowQueryThreshold: config.HatchCatalogSlowQueryThreshold()}
 }
 
 // Connection Manager
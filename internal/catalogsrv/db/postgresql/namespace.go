@@ -48,7 +48,7 @@ func (mm *metadataManager) CreateNamespace(ctx context.Context, ns *models.Names
 	return nil
 }
 
-func (mm *metadataManager) createNamespaceWithTransaction(ctx context.Context, ns *models.Namespace, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createNamespaceWithTransaction(ctx context.Context, ns *models.Namespace, tx *tracedTx) apperrors.Error {
 	if ns.Name == "" {
 		ns.Name = catcommon.DefaultNamespace
 	}
@@ -78,7 +78,7 @@ func (mm *metadataManager) createNamespaceWithTransaction(ctx context.Context, n
 			}
 		}
 		log.Ctx(ctx).Error().Err(err).Str("name", ns.Name).Msg("failed to insert namespace")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -123,7 +123,7 @@ func (mm *metadataManager) GetNamespace(ctx context.Context, name string, varian
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("namespace not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return &ns, nil
@@ -149,12 +149,12 @@ func (mm *metadataManager) UpdateNamespace(ctx context.Context, ns *models.Names
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, ns.VariantID, ns.Name, ns.Description, ns.Info)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update namespace")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("namespace not found")
@@ -179,12 +179,12 @@ func (mm *metadataManager) DeleteNamespace(ctx context.Context, name string, var
 
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, variantID, name)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("namespace not found")
@@ -208,7 +208,7 @@ func (mm *metadataManager) ListNamespacesByVariant(ctx context.Context, variantI
 
 	rows, err := mm.conn().QueryContext(ctx, query, tenantID, variantID)
 	if err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	defer rows.Close()
 
@@ -219,13 +219,13 @@ func (mm *metadataManager) ListNamespacesByVariant(ctx context.Context, variantI
 		err := rows.Scan(&ns.Name, &ns.VariantID, &ns.TenantID, &ns.Description, &ns.Info)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan namespace row")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 		result = append(result, &ns)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return result, nil
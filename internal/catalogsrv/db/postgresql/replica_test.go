@@ -0,0 +1,134 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// recordDriver is a database/sql driver that records the name of the database each statement
+// was sent to. Queries return no rows and statements affect one row. Queries sent to the database
+// named "broken" fail.
+type recordDriver struct {
+	statements *[]string
+}
+
+func (d recordDriver) Open(name string) (driver.Conn, error) {
+	return recordConn{name: name, statements: d.statements}, nil
+}
+
+type recordConn struct {
+	name       string
+	statements *[]string
+}
+
+func (recordConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (recordConn) Close() error                        { return nil }
+func (recordConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// CheckNamedValue accepts arguments of any type, such as the slices bound to ANY($n).
+func (recordConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c recordConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	*c.statements = append(*c.statements, c.name)
+	return driver.RowsAffected(1), nil
+}
+
+func (c recordConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	*c.statements = append(*c.statements, c.name)
+	if c.name == "broken" {
+		return nil, errors.New("replica unavailable")
+	}
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"session_id"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+var recordedStatements []string
+
+func init() {
+	sql.Register("postgresql-record", recordDriver{statements: &recordedStatements})
+}
+
+// replicaScopedConn is a scoped connection with separate primary and replica connections.
+type replicaScopedConn struct {
+	primary    *sql.Conn
+	replica    *sql.Conn
+	readFailed bool
+}
+
+func (c *replicaScopedConn) AddScopes(context.Context, map[string]string) error { return nil }
+func (c *replicaScopedConn) DropScopes(context.Context, []string) error         { return nil }
+func (c *replicaScopedConn) AddScope(context.Context, string, string) error     { return nil }
+func (c *replicaScopedConn) DropScope(context.Context, string) error            { return nil }
+func (c *replicaScopedConn) DropAllScopes(context.Context) error                { return nil }
+func (c *replicaScopedConn) Conn() *sql.Conn                                    { return c.primary }
+func (c *replicaScopedConn) ReadFailed(context.Context)                         { c.readFailed = true }
+func (c *replicaScopedConn) Close(context.Context)                              {}
+
+func (c *replicaScopedConn) ReadConn(context.Context) *sql.Conn {
+	if c.readFailed {
+		return c.primary
+	}
+	return c.replica
+}
+
+func TestReadReplicaRouting(t *testing.T) {
+	ctx := catcommon.WithTenantID(context.Background(), "TABCDE")
+	open := func(name string) *sql.Conn {
+		sqlDB, err := sql.Open("postgresql-record", name)
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		conn, err := sqlDB.Conn(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	mm, _, _ := NewHatchCatalogDb(&replicaScopedConn{primary: open("primary"), replica: open("replica")})
+
+	recordedStatements = nil
+	_, err := mm.ListSessionsByCatalog(ctx, uuid.New(), nil)
+	require.NoError(t, err)
+	_, err = mm.ListSessionsByIDs(ctx, []uuid.UUID{uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replica", "replica"}, recordedStatements)
+
+	recordedStatements = nil
+	err = mm.UpdateSessionStatus(ctx, uuid.New(), "running", json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary"}, recordedStatements)
+}
+
+func TestReadReplicaFallback(t *testing.T) {
+	ctx := catcommon.WithTenantID(context.Background(), "TABCDE")
+	open := func(name string) *sql.Conn {
+		sqlDB, err := sql.Open("postgresql-record", name)
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		conn, err := sqlDB.Conn(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	scoped := &replicaScopedConn{primary: open("primary"), replica: open("broken")}
+	mm, _, _ := NewHatchCatalogDb(scoped)
+
+	recordedStatements = nil
+	_, err := mm.ListSessionsByIDs(ctx, []uuid.UUID{uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"broken", "primary"}, recordedStatements)
+	assert.True(t, scoped.readFailed)
+}
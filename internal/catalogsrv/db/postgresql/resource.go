@@ -180,7 +180,7 @@ func (om *objectManager) ListResources(ctx context.Context, directoryID uuid.UUI
 	var directory models.Directory
 
 	if err := json.Unmarshal(dir.Directory, &directory); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.ErrInvalidObject.Err(err)
 	}
 
 	for path, objRef := range directory {
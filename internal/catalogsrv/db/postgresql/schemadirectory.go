@@ -41,7 +41,7 @@ func (om *objectManager) CreateSchemaDirectory(ctx context.Context, t catcommon.
 	tx, err := om.conn().BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to start transaction")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	defer func() {
 		if err != nil {
@@ -56,7 +56,7 @@ func (om *objectManager) CreateSchemaDirectory(ctx context.Context, t catcommon.
 	}
 
 	if err := tx.Commit(); err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	return nil
 }
@@ -78,7 +78,7 @@ func (om *objectManager) SetDirectory(ctx context.Context, t catcommon.CatalogOb
 
 	_, err := om.conn().ExecContext(ctx, query, dir, tenantID, id)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -105,12 +105,12 @@ func (om *objectManager) GetDirectory(ctx context.Context, t catcommon.CatalogOb
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("directory not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	return dir, nil
 }
 
-func (om *objectManager) createSchemaDirectoryWithTransaction(ctx context.Context, t catcommon.CatalogObjectType, dir *models.SchemaDirectory, tx *sql.Tx) apperrors.Error {
+func (om *objectManager) createSchemaDirectoryWithTransaction(ctx context.Context, t catcommon.CatalogObjectType, dir *models.SchemaDirectory, tx *tracedTx) apperrors.Error {
 	tableName := getSchemaDirectoryTableName(t)
 	if tableName == "" {
 		return dberror.ErrInvalidInput.Msg("invalid catalog object type")
@@ -130,7 +130,7 @@ func (om *objectManager) createSchemaDirectoryWithTransaction(ctx context.Contex
 		if err == sql.ErrNoRows {
 			return dberror.ErrAlreadyExists.Msg("schema directory already exists")
 		} else {
-			return dberror.ErrDatabase.Err(err)
+			return dberror.FromErr(err)
 		}
 	}
 
@@ -159,7 +159,7 @@ func (om *objectManager) GetSchemaDirectory(ctx context.Context, t catcommon.Cat
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("schema directory not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	return dir, nil
 }
@@ -185,7 +185,7 @@ func (om *objectManager) GetObjectRefByPath(ctx context.Context, t catcommon.Cat
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("object not found in directory")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	if len(objectData) == 0 {
@@ -194,7 +194,7 @@ func (om *objectManager) GetObjectRefByPath(ctx context.Context, t catcommon.Cat
 
 	var obj models.ObjectRef
 	if err := json.Unmarshal(objectData, &obj); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.ErrInvalidObject.Err(err)
 	}
 
 	return &obj, nil
@@ -241,7 +241,7 @@ func (om *objectManager) LoadObjectByPath(ctx context.Context, t catcommon.Catal
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("object not found in directory or catalog")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	// Create and populate the CatalogObject
@@ -257,7 +257,7 @@ func (om *objectManager) LoadObjectByPath(ctx context.Context, t catcommon.Catal
 	if config.CompressCatalogObjects {
 		catalogObj.Data, err = snappy.Decode(nil, data)
 		if err != nil {
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 	}
 
@@ -281,7 +281,7 @@ func (om *objectManager) AddOrUpdateObjectByPath(ctx context.Context, t catcommo
 	// Convert the object to JSON
 	data, err := json.Marshal(obj)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.ErrInvalidObject.Err(err)
 	}
 
 	query := `
@@ -291,12 +291,12 @@ func (om *objectManager) AddOrUpdateObjectByPath(ctx context.Context, t catcommo
 
 	result, err := om.conn().ExecContext(ctx, query, path, data, tenantID, directoryID)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	if rowsAffected == 0 {
@@ -343,7 +343,7 @@ func (om *objectManager) DeleteObjectByPath(ctx context.Context, t catcommon.Cat
 	if err == sql.ErrNoRows {
 		return hash, nil // Key did not exist, so nothing was removed
 	} else if err != nil {
-		return hash, dberror.ErrDatabase.Err(err)
+		return hash, dberror.FromErr(err)
 	} else if !result.Valid {
 		return hash, dberror.ErrNotFound.Msg("object not found")
 	}
@@ -370,7 +370,7 @@ func (om *objectManager) PathExists(ctx context.Context, t catcommon.CatalogObje
 	var exists bool
 	err := om.conn().QueryRowContext(ctx, query, path, tenantID, directoryID).Scan(&exists)
 	if err != nil {
-		return false, dberror.ErrDatabase.Err(err)
+		return false, dberror.FromErr(err)
 	}
 
 	return exists, nil
@@ -441,6 +441,12 @@ func (om *objectManager) DeleteNamespaceObjects(ctx context.Context, t catcommon
 		return nil, err
 	}
 
+	if t == catcommon.CatalogObjectTypeSkillset {
+		if err := releaseUsageInTx(ctx, tx, tenantID, usageSkillSets, len(deletedPaths)); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := om.commitTx(tx); err != nil {
 		return nil, err
 	}
@@ -448,18 +454,18 @@ func (om *objectManager) DeleteNamespaceObjects(ctx context.Context, t catcommon
 	return deletedPaths, nil
 }
 
-func (om *objectManager) beginSerializableTx(ctx context.Context) (*sql.Tx, apperrors.Error) {
+func (om *objectManager) beginSerializableTx(ctx context.Context) (*tracedTx, apperrors.Error) {
 	tx, err := om.conn().BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
 	})
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to start transaction")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	return tx, nil
 }
 
-func (om *objectManager) fetchDirectoryForUpdate(ctx context.Context, tx *sql.Tx, tableName string, tenantID catcommon.TenantId, directoryID uuid.UUID) ([]byte, apperrors.Error) {
+func (om *objectManager) fetchDirectoryForUpdate(ctx context.Context, tx *tracedTx, tableName string, tenantID catcommon.TenantId, directoryID uuid.UUID) ([]byte, apperrors.Error) {
 	var query string
 	switch tableName {
 	case "resource_directory":
@@ -476,7 +482,7 @@ func (om *objectManager) fetchDirectoryForUpdate(ctx context.Context, tx *sql.Tx
 			return nil, dberror.ErrNotFound.Msg("directory not found")
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to get directory")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	return dir, nil
 }
@@ -485,7 +491,7 @@ func (om *objectManager) parseDirectory(dir []byte) (models.Directory, apperrors
 	directory, err := models.JSONToDirectory(dir)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to unmarshal directory")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.ErrInvalidObject.Err(err)
 	}
 	return directory, nil
 }
@@ -506,12 +512,12 @@ func (om *objectManager) marshalDirectory(directory models.Directory) ([]byte, a
 	updatedDir, err := models.DirectoryToJSON(directory)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to marshal directory")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.ErrInvalidObject.Err(err)
 	}
 	return updatedDir, nil
 }
 
-func (om *objectManager) updateDirectoryInTx(ctx context.Context, tx *sql.Tx, tableName string, updatedDir []byte, directoryID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
+func (om *objectManager) updateDirectoryInTx(ctx context.Context, tx *tracedTx, tableName string, updatedDir []byte, directoryID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
 	var query string
 	switch tableName {
 	case "resource_directory":
@@ -524,15 +530,15 @@ func (om *objectManager) updateDirectoryInTx(ctx context.Context, tx *sql.Tx, ta
 	_, err := tx.ExecContext(ctx, query, updatedDir, directoryID, tenantID)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update directory")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	return nil
 }
 
-func (om *objectManager) commitTx(tx *sql.Tx) apperrors.Error {
+func (om *objectManager) commitTx(tx *tracedTx) apperrors.Error {
 	if err := tx.Commit(); err != nil {
 		log.Error().Err(err).Msg("failed to commit transaction")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	return nil
 }
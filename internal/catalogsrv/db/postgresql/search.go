@@ -0,0 +1,80 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// SearchCatalog returns the skillsets and views in the catalog whose indexed text matches
+// the text search query tsQuery. Results are ordered by kind, variant, namespace and name, and only
+// those sorting after the after result are returned when after is non-nil.
+func (mm *metadataManager) SearchCatalog(ctx context.Context, catalogID uuid.UUID, tsQuery string, after *models.SearchResult, limit int) ([]*models.SearchResult, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+	if catalogID == uuid.Nil {
+		return nil, dberror.ErrInvalidInput.Msg("invalid catalog ID")
+	}
+	if tsQuery == "" || limit <= 0 {
+		return nil, dberror.ErrInvalidInput.Msg("invalid search query")
+	}
+
+	var afterKind, afterVariant, afterNamespace, afterName string
+	if after != nil {
+		afterKind, afterVariant, afterNamespace, afterName = after.Kind, after.Variant, after.Namespace, after.Name
+	}
+
+	// Skillset search entries are only returned while their path is still in the
+	// directory, so entries removed along with a namespace never surface.
+	query := `
+		SELECT kind, variant, namespace, name, description FROM (
+			SELECT $3::text AS kind, v.name AS variant, s.namespace, s.name, COALESCE(s.description, '') AS description
+			FROM skillset_search s
+			JOIN skillset_directory d ON d.tenant_id = s.tenant_id AND d.directory_id = s.directory_id
+			JOIN variants v ON v.tenant_id = d.tenant_id AND v.variant_id = d.variant_id
+			WHERE s.tenant_id = $1 AND v.catalog_id = $2
+				AND s.search_vector @@ to_tsquery('simple', $5)
+				AND d.directory ? s.path
+			UNION ALL
+			SELECT $4::text AS kind, '' AS variant, '' AS namespace, label AS name, COALESCE(description, '') AS description
+			FROM views
+			WHERE tenant_id = $1 AND catalog_id = $2 AND label IS NOT NULL
+				AND search_vector @@ to_tsquery('simple', $5)
+		) r
+		WHERE $6::text = ''
+			OR (r.kind COLLATE "C", r.variant COLLATE "C", r.namespace COLLATE "C", r.name COLLATE "C") >
+				($6::text, $7::text, $8::text, $9::text)
+		ORDER BY r.kind COLLATE "C", r.variant COLLATE "C", r.namespace COLLATE "C", r.name COLLATE "C"
+		LIMIT $10;`
+
+	rows, err := mm.conn().QueryContext(ctx, query,
+		tenantID, catalogID, catcommon.SkillSetKind, catcommon.ViewKind, tsQuery,
+		afterKind, afterVariant, afterNamespace, afterName, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to search catalog")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	results := []*models.SearchResult{}
+	for rows.Next() {
+		var result models.SearchResult
+		if err := rows.Scan(&result.Kind, &result.Variant, &result.Namespace, &result.Name, &result.Description); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan search result")
+			return nil, dberror.FromErr(err)
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+
+	return results, nil
+}
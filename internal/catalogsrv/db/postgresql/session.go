@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
@@ -43,7 +44,7 @@ func (mm *metadataManager) UpsertSession(ctx context.Context, session *models.Se
 }
 
 // upsertSessionWithTransaction handles the actual session creation within a transaction.
-func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, session *models.Session, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, session *models.Session, tx *tracedTx) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -55,9 +56,9 @@ func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, ses
 		INSERT INTO sessions (
 			session_id, skillset, skill, view_id, 
 			tangent_id, status_summary, status, info, user_id, catalog_id, 
-			variant_id, tenant_id, started_at, ended_at, expires_at
+			variant_id, tenant_id, started_at, ended_at, expires_at, labels
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, COALESCE($16, '{}'::jsonb))
 		ON CONFLICT (tenant_id, session_id) DO UPDATE SET
 			skillset = EXCLUDED.skillset,
 			skill = EXCLUDED.skill,
@@ -72,6 +73,7 @@ func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, ses
 			started_at = EXCLUDED.started_at,
 			ended_at = EXCLUDED.ended_at,
 			expires_at = EXCLUDED.expires_at,
+			labels = EXCLUDED.labels,
 			updated_at = NOW()
 		RETURNING session_id
 	`
@@ -92,11 +94,12 @@ func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, ses
 		session.StartedAt,
 		session.EndedAt,
 		session.ExpiresAt,
+		session.Labels,
 	).Scan(&session.SessionID)
 
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to insert/update session")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -128,7 +131,8 @@ func (mm *metadataManager) GetSession(ctx context.Context, sessionID uuid.UUID)
 			s.started_at,
 			s.ended_at,
 			s.updated_at,
-			s.expires_at
+			s.expires_at,
+			s.labels
 		FROM 
 			sessions s
 		WHERE 
@@ -156,13 +160,14 @@ func (mm *metadataManager) GetSession(ctx context.Context, sessionID uuid.UUID)
 			&session.EndedAt,
 			&session.UpdatedAt,
 			&session.ExpiresAt,
+			&session.Labels,
 		)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("session not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return &session, nil
@@ -192,12 +197,12 @@ func (mm *metadataManager) UpdateSessionStatus(ctx context.Context, sessionID uu
 	)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update session status")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("session not found")
@@ -206,37 +211,78 @@ func (mm *metadataManager) UpdateSessionStatus(ctx context.Context, sessionID uu
 	return nil
 }
 
-// UpdateSessionEnd marks a session as ended and updates its status.
-func (mm *metadataManager) UpdateSessionEnd(ctx context.Context, sessionID uuid.UUID, statusSummary string, status json.RawMessage) apperrors.Error {
+// UpdateSessionEnd marks a session as ended and updates its status. It returns the status
+// summary the session had before the update, read under the same row lock, so that concurrent
+// callers can tell which of them ended the session.
+func (mm *metadataManager) UpdateSessionEnd(ctx context.Context, sessionID uuid.UUID, statusSummary string, status json.RawMessage) (string, apperrors.Error) {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
-		return dberror.ErrMissingTenantID
+		return "", dberror.ErrMissingTenantID
 	}
 
 	query := `
-		UPDATE sessions
+		UPDATE sessions s
 		SET 
 			status_summary = $3,
 			status = $4,
 			ended_at = NOW(),
 			updated_at = NOW()
-		WHERE tenant_id = $1 AND session_id = $2
+		FROM (
+			SELECT session_id, status_summary
+			FROM sessions
+			WHERE tenant_id = $1 AND session_id = $2
+			FOR UPDATE
+		) previous
+		WHERE s.tenant_id = $1 AND s.session_id = previous.session_id
+		RETURNING previous.status_summary
 	`
 
-	result, err := mm.conn().ExecContext(ctx, query,
+	var previous string
+	err := mm.conn().QueryRowContext(ctx, query,
 		tenantID,
 		sessionID,
 		statusSummary,
 		status,
-	)
+	).Scan(&previous)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", dberror.ErrNotFound.Msg("session not found")
+		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update session end")
-		return dberror.ErrDatabase.Err(err)
+		return "", dberror.FromErr(err)
+	}
+
+	return previous, nil
+}
+
+// UpdateSessionInfo updates the info field of a session.
+func (mm *metadataManager) UpdateSessionInfo(ctx context.Context, sessionID uuid.UUID, info json.RawMessage) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE sessions
+		SET 
+			info = $3,
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND session_id = $2
+	`
+
+	result, err := mm.conn().ExecContext(ctx, query,
+		tenantID,
+		sessionID,
+		info,
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to update session info")
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("session not found")
@@ -245,8 +291,10 @@ func (mm *metadataManager) UpdateSessionEnd(ctx context.Context, sessionID uuid.
 	return nil
 }
 
-// UpdateSessionInfo updates the info field of a session.
-func (mm *metadataManager) UpdateSessionInfo(ctx context.Context, sessionID uuid.UUID, info json.RawMessage) apperrors.Error {
+// UpdateSessionHeartbeat records a heartbeat for a session. Only sessions in one of the given
+// status summaries are updated, so a session that has already ended or been marked stale cannot
+// be revived by a late heartbeat. Returns ErrNotFound if no such session exists.
+func (mm *metadataManager) UpdateSessionHeartbeat(ctx context.Context, sessionID uuid.UUID, heartbeatAt time.Time, statusSummaries []string) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -255,24 +303,25 @@ func (mm *metadataManager) UpdateSessionInfo(ctx context.Context, sessionID uuid
 	query := `
 		UPDATE sessions
 		SET 
-			info = $3,
+			heartbeat_at = $3,
 			updated_at = NOW()
-		WHERE tenant_id = $1 AND session_id = $2
+		WHERE tenant_id = $1 AND session_id = $2 AND status_summary = ANY($4)
 	`
 
 	result, err := mm.conn().ExecContext(ctx, query,
 		tenantID,
 		sessionID,
-		info,
+		heartbeatAt,
+		statusSummaries,
 	)
 	if err != nil {
-		log.Ctx(ctx).Error().Err(err).Msg("failed to update session info")
-		return dberror.ErrDatabase.Err(err)
+		log.Ctx(ctx).Error().Err(err).Msg("failed to update session heartbeat")
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("session not found")
@@ -295,12 +344,12 @@ func (mm *metadataManager) DeleteSession(ctx context.Context, sessionID uuid.UUI
 
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, sessionID)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("session not found")
@@ -309,9 +358,156 @@ func (mm *metadataManager) DeleteSession(ctx context.Context, sessionID uuid.UUI
 	return nil
 }
 
-// ListSessionsByCatalog retrieves all sessions for a specific catalog.
-// Sessions are ordered by creation time in descending order (newest first).
-func (mm *metadataManager) ListSessionsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.Session, apperrors.Error) {
+// ListSessionsByCatalog retrieves all sessions for a specific catalog that carry every one of the
+// given labels. Sessions are ordered by creation time in descending order (newest first).
+// The query is served from the read replica if one is configured.
+func (mm *metadataManager) ListSessionsByCatalog(ctx context.Context, catalogID uuid.UUID, labels map[string]string) ([]*models.Session, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labelsJSON, goerr := json.Marshal(labels)
+	if goerr != nil {
+		return nil, dberror.ErrInvalidInput.Msg("invalid labels")
+	}
+
+	query := `
+		SELECT 
+			session_id, skillset, skill, view_id,
+			tangent_id, status_summary, status, info, user_id, catalog_id,
+			variant_id, tenant_id, created_at, started_at,
+			ended_at, updated_at, expires_at, labels
+		FROM sessions
+		WHERE tenant_id = $1 AND catalog_id = $2 AND labels @> $3::jsonb
+		ORDER BY created_at DESC
+	`
+
+	rows, err := mm.readQuery(ctx, query, tenantID, catalogID, string(labelsJSON))
+	if err != nil {
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	var result []*models.Session
+
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(
+			&session.SessionID,
+			&session.SkillSet,
+			&session.Skill,
+			&session.ViewID,
+			&session.TangentID,
+			&session.StatusSummary,
+			&session.Status,
+			&session.Info,
+			&session.UserID,
+			&session.CatalogID,
+			&session.VariantID,
+			&session.TenantID,
+			&session.CreatedAt,
+			&session.StartedAt,
+			&session.EndedAt,
+			&session.UpdatedAt,
+			&session.ExpiresAt,
+			&session.Labels,
+		)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan session row")
+			return nil, dberror.FromErr(err)
+		}
+		result = append(result, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+
+	return result, nil
+}
+
+// ListSessionsByIDs retrieves the sessions of the tenant with the given IDs in a single query.
+// IDs that do not match a session are omitted from the result, which is in no particular order.
+// The query is served from the read replica if one is configured.
+func (mm *metadataManager) ListSessionsByIDs(ctx context.Context, sessionIDs []uuid.UUID) ([]*models.Session, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(sessionIDs))
+	for i, id := range sessionIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT 
+			session_id, skillset, skill, view_id,
+			tangent_id, status_summary, status, info, user_id, catalog_id,
+			variant_id, tenant_id, created_at, started_at,
+			ended_at, updated_at, expires_at, labels
+		FROM sessions
+		WHERE tenant_id = $1 AND session_id = ANY($2::uuid[])
+	`
+
+	rows, err := mm.readQuery(ctx, query, tenantID, ids)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list sessions by ID")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	var result []*models.Session
+
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(
+			&session.SessionID,
+			&session.SkillSet,
+			&session.Skill,
+			&session.ViewID,
+			&session.TangentID,
+			&session.StatusSummary,
+			&session.Status,
+			&session.Info,
+			&session.UserID,
+			&session.CatalogID,
+			&session.VariantID,
+			&session.TenantID,
+			&session.CreatedAt,
+			&session.StartedAt,
+			&session.EndedAt,
+			&session.UpdatedAt,
+			&session.ExpiresAt,
+			&session.Labels,
+		)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan session row")
+			return nil, dberror.FromErr(err)
+		}
+		result = append(result, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+
+	return result, nil
+}
+
+// ListSessionsByTangent retrieves the sessions of a catalog that are bound to the given tangent and
+// are in one of the given status summaries. A nil catalog ID retrieves the sessions of every
+// catalog of the tenant. Sessions are ordered by creation time in descending order
+// (newest first). The query is served from the primary so that callers acting on the sessions see
+// their current status.
+func (mm *metadataManager) ListSessionsByTangent(ctx context.Context, catalogID uuid.UUID, tangentID uuid.UUID, statusSummaries []string) ([]*models.Session, apperrors.Error) {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return nil, dberror.ErrMissingTenantID
@@ -322,15 +518,150 @@ func (mm *metadataManager) ListSessionsByCatalog(ctx context.Context, catalogID
 			session_id, skillset, skill, view_id,
 			tangent_id, status_summary, status, info, user_id, catalog_id,
 			variant_id, tenant_id, created_at, started_at,
-			ended_at, updated_at, expires_at
+			ended_at, updated_at, expires_at, labels
 		FROM sessions
-		WHERE tenant_id = $1 AND catalog_id = $2
+		WHERE tenant_id = $1 AND ($2::uuid = $5::uuid OR catalog_id = $2) AND tangent_id = $3 AND status_summary = ANY($4)
 		ORDER BY created_at DESC
 	`
 
-	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
+	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID, tangentID, statusSummaries, uuid.Nil)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list sessions by tangent")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	var result []*models.Session
+
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(
+			&session.SessionID,
+			&session.SkillSet,
+			&session.Skill,
+			&session.ViewID,
+			&session.TangentID,
+			&session.StatusSummary,
+			&session.Status,
+			&session.Info,
+			&session.UserID,
+			&session.CatalogID,
+			&session.VariantID,
+			&session.TenantID,
+			&session.CreatedAt,
+			&session.StartedAt,
+			&session.EndedAt,
+			&session.UpdatedAt,
+			&session.ExpiresAt,
+			&session.Labels,
+		)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan session row")
+			return nil, dberror.FromErr(err)
+		}
+		result = append(result, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+
+	return result, nil
+}
+
+// ListPrunableSessions retrieves sessions across all tenants that ended before endedBefore in one of
+// the given status summaries and still reference a stored audit log. It is not scoped to a tenant
+// since it is used by the server's audit log janitor. At most limit sessions are returned, oldest first.
+func (mm *metadataManager) ListPrunableSessions(ctx context.Context, endedBefore time.Time, statusSummaries []string, limit int) ([]*models.Session, apperrors.Error) {
+	if limit <= 0 {
+		return nil, dberror.ErrInvalidInput.Msg("limit must be positive")
+	}
+
+	query := `
+		SELECT 
+			session_id, skillset, skill, view_id,
+			tangent_id, status_summary, status, info, user_id, catalog_id,
+			variant_id, tenant_id, created_at, started_at,
+			ended_at, updated_at, expires_at, labels
+		FROM sessions
+		WHERE ended_at < $1 
+			AND status_summary = ANY($2)
+			AND COALESCE(status->>'auditLog', '') <> ''
+		ORDER BY ended_at ASC
+		LIMIT $3
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query, endedBefore, statusSummaries, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list prunable sessions")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	var result []*models.Session
+
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(
+			&session.SessionID,
+			&session.SkillSet,
+			&session.Skill,
+			&session.ViewID,
+			&session.TangentID,
+			&session.StatusSummary,
+			&session.Status,
+			&session.Info,
+			&session.UserID,
+			&session.CatalogID,
+			&session.VariantID,
+			&session.TenantID,
+			&session.CreatedAt,
+			&session.StartedAt,
+			&session.EndedAt,
+			&session.UpdatedAt,
+			&session.ExpiresAt,
+			&session.Labels,
+		)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan session row")
+			return nil, dberror.FromErr(err)
+		}
+		result = append(result, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+
+	return result, nil
+}
+
+// ListStaleSessions retrieves sessions across all tenants in one of the given status summaries whose
+// last heartbeat is older than heartbeatBefore. Sessions that never sent a heartbeat are not
+// returned. It is not scoped to a tenant since it is used by the server's session watchdog.
+// At most limit sessions are returned, longest silent first.
+func (mm *metadataManager) ListStaleSessions(ctx context.Context, heartbeatBefore time.Time, statusSummaries []string, limit int) ([]*models.Session, apperrors.Error) {
+	if limit <= 0 {
+		return nil, dberror.ErrInvalidInput.Msg("limit must be positive")
+	}
+
+	query := `
+		SELECT 
+			session_id, skillset, skill, view_id,
+			tangent_id, status_summary, status, info, user_id, catalog_id,
+			variant_id, tenant_id, created_at, started_at,
+			ended_at, updated_at, expires_at, labels
+		FROM sessions
+		WHERE heartbeat_at < $1 
+			AND status_summary = ANY($2)
+		ORDER BY heartbeat_at ASC
+		LIMIT $3
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query, heartbeatBefore, statusSummaries, limit)
 	if err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list stale sessions")
+		return nil, dberror.FromErr(err)
 	}
 	defer rows.Close()
 
@@ -356,16 +687,17 @@ func (mm *metadataManager) ListSessionsByCatalog(ctx context.Context, catalogID
 			&session.EndedAt,
 			&session.UpdatedAt,
 			&session.ExpiresAt,
+			&session.Labels,
 		)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan session row")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 		result = append(result, &session)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return result, nil
@@ -2,10 +2,13 @@ package postgresql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strings"
 
 	"encoding/json"
 
+	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
@@ -41,7 +44,7 @@ func (om *objectManager) UpsertSkillSet(ctx context.Context, ss *models.SkillSet
 		return err
 	}
 
-	return nil
+	return om.UpsertSkillSetSearch(ctx, ss, directoryID)
 }
 
 func (om *objectManager) GetSkillSet(ctx context.Context, path string, variantID uuid.UUID, directoryID uuid.UUID) (*models.SkillSet, apperrors.Error) {
@@ -119,14 +122,76 @@ func (om *objectManager) DeleteSkillSet(ctx context.Context, path string, direct
 		return "", dberror.ErrInvalidInput.Msg("invalid directory ID")
 	}
 
-	deletedHash, err := om.DeleteObjectByPath(ctx, catcommon.CatalogObjectTypeSkillset, directoryID, path)
+	deletedHash, err := om.deleteSkillSetPath(ctx, path, directoryID)
 	if err != nil {
 		return "", err
 	}
 
+	if _, err := om.conn().ExecContext(ctx, `
+		DELETE FROM skillset_search
+		WHERE tenant_id = $1 AND directory_id = $2 AND path = $3;`,
+		tenantID, directoryID, path); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("path", path).Msg("failed to delete skillset search entry")
+		return "", dberror.FromErr(err)
+	}
+
+	if _, err := om.conn().ExecContext(ctx, `
+		DELETE FROM skillset_versions
+		WHERE tenant_id = $1 AND directory_id = $2 AND path = $3;`,
+		tenantID, directoryID, path); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("path", path).Msg("failed to delete skillset versions")
+		return "", dberror.FromErr(err)
+	}
+
 	return string(deletedHash), nil
 }
 
+// deleteSkillSetPath removes the directory entry of the skillset at path and returns the hash it
+// referred to. The removed path is released from the skillset quota of the tenant in the same
+// transaction. Returns an empty hash if there is no skillset at path.
+func (om *objectManager) deleteSkillSetPath(ctx context.Context, path string, directoryID uuid.UUID) (hash catcommon.Hash, err apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+
+	tx, errdb := om.conn().BeginTx(ctx, &sql.TxOptions{})
+	if errdb != nil {
+		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
+		return "", dberror.FromErr(errdb)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var result sql.NullString
+	errdb = tx.QueryRowContext(ctx, `
+		WITH to_delete AS (
+			SELECT directory -> $1 ->> 'hash' AS deleted_hash
+			FROM skillset_directory
+			WHERE tenant_id = $2 AND directory_id = $3 AND directory ? $1
+		)
+		UPDATE skillset_directory
+		SET directory = directory - $1
+		WHERE tenant_id = $2 AND directory_id = $3 AND directory ? $1
+		RETURNING (SELECT deleted_hash FROM to_delete);`,
+		path, tenantID, directoryID).Scan(&result)
+	if errdb == sql.ErrNoRows {
+		return "", om.commitTx(tx) // Key did not exist, so nothing was removed
+	} else if errdb != nil {
+		return "", dberror.FromErr(errdb)
+	} else if !result.Valid {
+		return "", dberror.ErrNotFound.Msg("object not found")
+	}
+
+	if err = releaseUsageInTx(ctx, tx, tenantID, usageSkillSets, 1); err != nil {
+		return "", err
+	}
+	if err = om.commitTx(tx); err != nil {
+		return "", err
+	}
+	return catcommon.Hash(result.String), nil
+}
+
 func (om *objectManager) UpsertSkillSetObject(ctx context.Context, ss *models.SkillSet, obj *models.CatalogObject, directoryID uuid.UUID) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
@@ -148,20 +213,77 @@ func (om *objectManager) UpsertSkillSetObject(ctx context.Context, ss *models.Sk
 	}
 
 	// Then add/update the directory entry
-	err = om.AddOrUpdateObjectByPath(ctx,
-		catcommon.CatalogObjectTypeSkillset,
-		directoryID,
-		ss.Path,
-		models.ObjectRef{
-			Hash:     ss.Hash,
-			Metadata: ss.Metadata,
-		},
-	)
-	if err != nil {
+	if err := om.putSkillSetPath(ctx, ss, directoryID); err != nil {
 		return err
 	}
 
-	return nil
+	if err := om.upsertSkillSetVersion(ctx, ss, directoryID); err != nil {
+		return err
+	}
+
+	return om.UpsertSkillSetSearch(ctx, ss, directoryID)
+}
+
+// putSkillSetPath adds or updates the directory entry of the skillset. A new path is counted
+// against the skillset quota of the tenant in the same transaction, with the directory locked
+// so that concurrent saves of the same path count it once. If ss.PrevHash is set, the entry is
+// only updated if it still refers to that hash; otherwise ErrConflict is returned.
+func (om *objectManager) putSkillSetPath(ctx context.Context, ss *models.SkillSet, directoryID uuid.UUID) (err apperrors.Error) {
+	if !isValidPath(ss.Path) {
+		return dberror.ErrInvalidInput.Msg("invalid path")
+	}
+	data, errJSON := json.Marshal(models.ObjectRef{
+		Hash:     ss.Hash,
+		Metadata: ss.Metadata,
+	})
+	if errJSON != nil {
+		return dberror.ErrInvalidObject.Err(errJSON)
+	}
+
+	tx, errdb := om.conn().BeginTx(ctx, &sql.TxOptions{})
+	if errdb != nil {
+		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
+		return dberror.FromErr(errdb)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var exists bool
+	var current sql.NullString
+	errdb = tx.QueryRowContext(ctx, `
+		SELECT directory ? $1, directory -> $1 ->> 'hash' FROM skillset_directory
+		WHERE tenant_id = $2 AND directory_id = $3
+		FOR UPDATE;`,
+		ss.Path, ss.TenantID, directoryID).Scan(&exists, &current)
+	if errdb != nil {
+		if errdb == sql.ErrNoRows {
+			return dberror.ErrNotFound.Msg("object not found")
+		}
+		log.Ctx(ctx).Error().Err(errdb).Str("path", ss.Path).Msg("failed to get skillset directory")
+		return dberror.FromErr(errdb)
+	}
+	if ss.PrevHash != "" && current.String != ss.PrevHash {
+		return dberror.ErrConflict.Msg("skillset was modified since it was loaded")
+	}
+	if !exists {
+		if err = reserveUsageInTx(ctx, tx, ss.TenantID, usageSkillSets); err != nil {
+			return err
+		}
+	}
+
+	if _, errdb = tx.ExecContext(ctx, `
+		UPDATE skillset_directory
+		SET directory = jsonb_set(directory, ARRAY[$1], $2::jsonb)
+		WHERE tenant_id = $3 AND directory_id = $4;`,
+		ss.Path, data, ss.TenantID, directoryID); errdb != nil {
+		log.Ctx(ctx).Error().Err(errdb).Str("path", ss.Path).Msg("failed to update skillset directory")
+		return dberror.FromErr(errdb)
+	}
+
+	return om.commitTx(tx)
 }
 
 func (om *objectManager) ListSkillSets(ctx context.Context, directoryID uuid.UUID) ([]models.SkillSet, apperrors.Error) {
@@ -184,7 +306,7 @@ func (om *objectManager) ListSkillSets(ctx context.Context, directoryID uuid.UUI
 	var directory models.Directory
 
 	if err := json.Unmarshal(dir.Directory, &directory); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.ErrInvalidObject.Err(err)
 	}
 
 	for path, objRef := range directory {
@@ -198,3 +320,124 @@ func (om *objectManager) ListSkillSets(ctx context.Context, directoryID uuid.UUI
 
 	return skillsets, nil
 }
+
+// UpsertSkillSetSearch stores the searchable text of a skillset. Skillsets saved without
+// search text are left out of catalog search.
+func (om *objectManager) UpsertSkillSetSearch(ctx context.Context, ss *models.SkillSet, directoryID uuid.UUID) apperrors.Error {
+	if ss.Search == nil {
+		return nil
+	}
+
+	query := `
+		INSERT INTO skillset_search (tenant_id, directory_id, path, name, namespace, description, skills)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, directory_id, path) DO UPDATE
+		SET name = EXCLUDED.name,
+			namespace = EXCLUDED.namespace,
+			description = EXCLUDED.description,
+			skills = EXCLUDED.skills;`
+
+	_, err := om.conn().ExecContext(ctx, query,
+		ss.TenantID, directoryID, ss.Path,
+		ss.Search.Name, ss.Search.Namespace, ss.Search.Description, strings.Join(ss.Search.SkillNames, " "))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("path", ss.Path).Msg("failed to update skillset search entry")
+		return dberror.FromErr(err)
+	}
+	return nil
+}
+
+// upsertSkillSetVersion records the hash of the skillset as its spec version. Saving a version
+// again replaces the recorded hash. Skillsets saved without a version are not recorded.
+func (om *objectManager) upsertSkillSetVersion(ctx context.Context, ss *models.SkillSet, directoryID uuid.UUID) apperrors.Error {
+	if ss.Version == "" {
+		return nil
+	}
+
+	query := `
+		INSERT INTO skillset_versions (tenant_id, directory_id, path, version, hash)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, directory_id, path, version) DO UPDATE
+		SET hash = EXCLUDED.hash;`
+
+	_, err := om.conn().ExecContext(ctx, query, ss.TenantID, directoryID, ss.Path, ss.Version, ss.Hash)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("path", ss.Path).Str("version", ss.Version).Msg("failed to record skillset version")
+		return dberror.FromErr(err)
+	}
+	return nil
+}
+
+// GetSkillSetVersionObject returns the stored object of the given version of the skillset at path.
+func (om *objectManager) GetSkillSetVersionObject(ctx context.Context, path string, version string, directoryID uuid.UUID) (*models.CatalogObject, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	if directoryID == uuid.Nil {
+		return nil, dberror.ErrInvalidInput.Msg("invalid directory ID")
+	}
+
+	var hash string
+	err := om.conn().QueryRowContext(ctx, `
+		SELECT hash FROM skillset_versions
+		WHERE tenant_id = $1 AND directory_id = $2 AND path = $3 AND version = $4;`,
+		tenantID, directoryID, path, version).Scan(&hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dberror.ErrNotFound.Msg("skillset version not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("path", path).Str("version", version).Msg("failed to get skillset version")
+		return nil, dberror.FromErr(err)
+	}
+
+	return om.GetCatalogObject(ctx, hash)
+}
+
+// ListUnindexedSkillSets returns up to limit skillset directory entries, across all tenants,
+// that have no catalog search entry. These are skillsets stored before catalog search existed.
+// The namespace of an entry is the namespace of its variant that prefixes its storage path.
+func (om *objectManager) ListUnindexedSkillSets(ctx context.Context, limit int) ([]*models.UnindexedSkillSet, apperrors.Error) {
+	if limit <= 0 {
+		return nil, dberror.ErrInvalidInput.Msg("limit must be positive")
+	}
+
+	query := `
+		SELECT d.tenant_id, d.directory_id, e.key, e.value->>'hash', COALESCE(n.name, '')
+		FROM skillset_directory d
+		CROSS JOIN LATERAL jsonb_each(d.directory) e
+		LEFT JOIN LATERAL (
+			SELECT ns.name FROM namespaces ns
+			WHERE ns.tenant_id = d.tenant_id AND ns.variant_id = d.variant_id AND ns.name <> $1
+				AND starts_with(e.key, '/' || $1 || '/' || ns.name || '/')
+			LIMIT 1
+		) n ON true
+		WHERE NOT EXISTS (
+			SELECT 1 FROM skillset_search s
+			WHERE s.tenant_id = d.tenant_id AND s.directory_id = d.directory_id AND s.path = e.key
+		)
+		ORDER BY d.tenant_id, d.directory_id, e.key
+		LIMIT $2;`
+
+	rows, err := om.conn().QueryContext(ctx, query, catcommon.DefaultNamespace, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list unindexed skillsets")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	var result []*models.UnindexedSkillSet
+	for rows.Next() {
+		var ss models.UnindexedSkillSet
+		if err := rows.Scan(&ss.TenantID, &ss.DirectoryID, &ss.Path, &ss.Hash, &ss.Namespace); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan unindexed skillset")
+			return nil, dberror.FromErr(err)
+		}
+		result = append(result, &ss)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.FromErr(err)
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/rs/zerolog/log"
@@ -41,7 +42,7 @@ func (mm *metadataManager) CreateTangent(ctx context.Context, tangent *models.Ta
 	return nil
 }
 
-func (mm *metadataManager) createTangentWithTransaction(ctx context.Context, tangent *models.Tangent, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createTangentWithTransaction(ctx context.Context, tangent *models.Tangent, tx *tracedTx) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -75,7 +76,7 @@ func (mm *metadataManager) createTangentWithTransaction(ctx context.Context, tan
 			}
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to insert tangent")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -85,22 +86,25 @@ func (mm *metadataManager) GetTangent(ctx context.Context, id uuid.UUID) (*model
 	tenantID := catcommon.GetTenantID(ctx)
 
 	query := `
-		SELECT id, info, public_key, status, tenant_id, created_at, updated_at
+		SELECT id, info, public_key, status, tenant_id, last_heartbeat_at, created_at, updated_at
 		FROM tangents
 		WHERE id = $1
 	`
 
 	var tangent models.Tangent
+	var lastHeartbeatAt sql.NullTime
 	err := mm.conn().QueryRowContext(ctx, query, id).
-		Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.TenantID, &tangent.CreatedAt, &tangent.UpdatedAt)
+		Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.TenantID, &lastHeartbeatAt, &tangent.CreatedAt, &tangent.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("tangent not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
+	tangent.LastHeartbeatAt = lastHeartbeatAt.Time
+
 	if tenantID != "" {
 		if tangent.TenantID != string(tenantID) {
 			log.Ctx(ctx).Error().Msgf("tangent %s is not in tenant %s", id, tenantID)
@@ -122,6 +126,7 @@ func (mm *metadataManager) UpdateTangent(ctx context.Context, tangent *models.Ta
 		SET info = $3,
 			public_key = $4,
 			status = $5,
+			last_heartbeat_at = NOW(),
 			updated_at = NOW()
 		WHERE tenant_id = $1 AND id = $2
 	`
@@ -129,12 +134,74 @@ func (mm *metadataManager) UpdateTangent(ctx context.Context, tangent *models.Ta
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, tangent.ID, tangent.Info, tangent.PublicKey, tangent.Status)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update tangent")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.FromErr(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("tangent not found")
+	}
+
+	return nil
+}
+
+// UpdateTangentStatus sets the status of the tangent, leaving its registration unchanged.
+func (mm *metadataManager) UpdateTangentStatus(ctx context.Context, id uuid.UUID, status string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE tangents
+		SET status = $3,
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, id, status)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", id.String()).Msg("failed to update tangent status")
+		return dberror.FromErr(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.FromErr(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("tangent not found")
+	}
+
+	return nil
+}
+
+// UpdateTangentHeartbeat records a heartbeat of the tangent at heartbeatAt. Returns ErrNotFound
+// if the tangent is not registered.
+func (mm *metadataManager) UpdateTangentHeartbeat(ctx context.Context, id uuid.UUID, heartbeatAt time.Time) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE tangents
+		SET last_heartbeat_at = $3
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, id, heartbeatAt)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", id.String()).Msg("failed to update tangent heartbeat")
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("tangent not found")
@@ -156,12 +223,12 @@ func (mm *metadataManager) DeleteTangent(ctx context.Context, id uuid.UUID) appe
 
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, id)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("tangent not found")
@@ -177,7 +244,7 @@ func (mm *metadataManager) ListTangents(ctx context.Context) ([]*models.Tangent,
 	}
 
 	query := `
-		SELECT id, info, public_key, status, tenant_id, created_at, updated_at
+		SELECT id, info, public_key, status, tenant_id, last_heartbeat_at, created_at, updated_at
 		FROM tangents
 		WHERE tenant_id = $1
 		ORDER BY updated_at DESC
@@ -185,7 +252,7 @@ func (mm *metadataManager) ListTangents(ctx context.Context) ([]*models.Tangent,
 
 	rows, err := mm.conn().QueryContext(ctx, query, tenantID)
 	if err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	defer rows.Close()
 
@@ -193,16 +260,18 @@ func (mm *metadataManager) ListTangents(ctx context.Context) ([]*models.Tangent,
 
 	for rows.Next() {
 		var tangent models.Tangent
-		err := rows.Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.TenantID, &tangent.CreatedAt, &tangent.UpdatedAt)
+		var lastHeartbeatAt sql.NullTime
+		err := rows.Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.TenantID, &lastHeartbeatAt, &tangent.CreatedAt, &tangent.UpdatedAt)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan tangent row")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
+		tangent.LastHeartbeatAt = lastHeartbeatAt.Time
 		result = append(result, &tangent)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return result, nil
@@ -53,7 +53,7 @@ func (mm *metadataManager) CreateTenant(ctx context.Context, tenantID catcommon.
 			return dberror.ErrAlreadyExists.Msg("tenant already exists")
 		}
 		log.Ctx(ctx).Error().Str("tenant_id", string(tenantID)).Msg("failed to insert tenant")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -78,7 +78,7 @@ func (mm *metadataManager) GetTenant(ctx context.Context, tenantID catcommon.Ten
 			return nil, dberror.ErrNotFound.Msg("tenant not found")
 		}
 		log.Ctx(ctx).Error().Str("tenant_id", string(tenantID)).Msg("failed to retrieve tenant")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return &tenant, nil
@@ -93,7 +93,7 @@ func (mm *metadataManager) DeleteTenant(ctx context.Context, tenantID catcommon.
 	_, err := mm.conn().ExecContext(ctx, query, string(tenantID))
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("tenant_id", string(tenantID)).Msg("failed to delete tenant")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	return nil
 }
@@ -125,7 +125,7 @@ func (mm *metadataManager) CreateProject(ctx context.Context, projectID catcommo
 			return dberror.ErrAlreadyExists.Msg("project already exists")
 		}
 		log.Ctx(ctx).Error().Str("project_id", string(projectID)).Msg("failed to insert project")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -162,7 +162,7 @@ func (mm *metadataManager) GetProject(ctx context.Context, projectID catcommon.P
 		log.Ctx(ctx).Error().
 			Str("project_id", string(projectID)).
 			Msg("failed to retrieve project")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return &project, nil
@@ -188,7 +188,7 @@ func (mm *metadataManager) DeleteProject(ctx context.Context, projectID catcommo
 			Err(err).
 			Str("project_id", string(projectID)).
 			Msg("failed to delete project")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tracedConn wraps a database connection to log queries that take at least slowQueryThreshold.
+// Slow queries are logged with the name of the manager method that issued them and their
+// duration, but never their arguments. The logger is taken from the context so entries carry
+// the request correlation ID. A zero threshold disables logging.
+type tracedConn struct {
+	*sql.Conn
+	slowQueryThreshold time.Duration
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer c.trace(ctx, time.Now())
+	return c.Conn.QueryContext(ctx, query, args...)
+}
+
+func (c *tracedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer c.trace(ctx, time.Now())
+	return c.Conn.QueryRowContext(ctx, query, args...)
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer c.trace(ctx, time.Now())
+	return c.Conn.ExecContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction whose statements are traced like those on the connection. The
+// transaction as a whole is also logged if it was slow, named after the caller of BeginTx.
+func (c *tracedConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*tracedTx, error) {
+	tx, err := c.Conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedTx{
+		Tx:                 tx,
+		ctx:                ctx,
+		name:               queryName(2),
+		start:              time.Now(),
+		slowQueryThreshold: c.slowQueryThreshold,
+	}, nil
+}
+
+// trace logs the query started at start if it was slow. It must be deferred directly by a
+// tracedConn query method so the caller of that method can be named.
+func (c *tracedConn) trace(ctx context.Context, start time.Time) {
+	if c.slowQueryThreshold <= 0 {
+		return
+	}
+	elapsed := time.Since(start)
+	if elapsed < c.slowQueryThreshold {
+		return
+	}
+	// skip queryName, trace and the tracedConn method
+	logSlowQuery(ctx, queryName(3), elapsed, c.slowQueryThreshold)
+}
+
+// logSlowQuery logs a warning for the named query if elapsed is at least threshold.
+func logSlowQuery(ctx context.Context, name string, elapsed, threshold time.Duration) {
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+	log.Ctx(ctx).Warn().
+		Str("query", name).
+		Str("duration", fmt.Sprintf("%dms", elapsed.Milliseconds())).
+		Str("threshold", fmt.Sprintf("%dms", threshold.Milliseconds())).
+		Msg("slow query")
+}
+
+// queryName returns the name of the function skip frames above the caller of queryName,
+// without its package path, e.g. "(*metadataManager).GetCatalog".
+func queryName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// tracedTx wraps a transaction started by tracedConn. Its statements are traced like those on
+// the connection, and the time from BeginTx to Commit or Rollback is logged as a slow
+// transaction if it reaches the threshold. ctx is the context the transaction was started with.
+type tracedTx struct {
+	*sql.Tx
+	ctx                context.Context
+	name               string
+	start              time.Time
+	slowQueryThreshold time.Duration
+}
+
+func (t *tracedTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer t.trace(ctx, time.Now())
+	return t.Tx.QueryContext(ctx, query, args...)
+}
+
+func (t *tracedTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer t.trace(ctx, time.Now())
+	return t.Tx.QueryRowContext(ctx, query, args...)
+}
+
+func (t *tracedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer t.trace(ctx, time.Now())
+	return t.Tx.ExecContext(ctx, query, args...)
+}
+
+func (t *tracedTx) Commit() error {
+	defer t.traceTx()
+	return t.Tx.Commit()
+}
+
+func (t *tracedTx) Rollback() error {
+	defer t.traceTx()
+	return t.Tx.Rollback()
+}
+
+// trace logs the statement started at start if it was slow. It must be deferred directly by a
+// tracedTx query method so the caller of that method can be named.
+func (t *tracedTx) trace(ctx context.Context, start time.Time) {
+	if t.slowQueryThreshold <= 0 {
+		return
+	}
+	// skip queryName, trace and the tracedTx method
+	logSlowQuery(ctx, queryName(3), time.Since(start), t.slowQueryThreshold)
+}
+
+// traceTx logs the transaction if it was slow. A rollback after a commit is not logged again.
+func (t *tracedTx) traceTx() {
+	if t.start.IsZero() {
+		return
+	}
+	elapsed := time.Since(t.start)
+	t.start = time.Time{}
+	if t.slowQueryThreshold <= 0 || elapsed < t.slowQueryThreshold {
+		return
+	}
+	log.Ctx(t.ctx).Warn().
+		Str("transaction", t.name).
+		Str("duration", fmt.Sprintf("%dms", elapsed.Milliseconds())).
+		Str("threshold", fmt.Sprintf("%dms", t.slowQueryThreshold.Milliseconds())).
+		Msg("slow transaction")
+}
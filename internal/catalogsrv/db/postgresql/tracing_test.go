@@ -0,0 +1,124 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepDriver is a database/sql driver whose statements sleep for the duration given as the query text.
+type sleepDriver struct{}
+
+func (sleepDriver) Open(string) (driver.Conn, error) { return sleepConn{}, nil }
+
+type sleepConn struct{}
+
+func (sleepConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (sleepConn) Close() error                        { return nil }
+func (sleepConn) Begin() (driver.Tx, error)           { return sleepTx{}, nil }
+
+type sleepTx struct{}
+
+func (sleepTx) Commit() error   { return nil }
+func (sleepTx) Rollback() error { return nil }
+
+func (sleepConn) ExecContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	d, err := time.ParseDuration(query)
+	if err != nil {
+		return nil, err
+	}
+	time.Sleep(d)
+	return driver.RowsAffected(0), nil
+}
+
+func init() {
+	sql.Register("postgresql-sleep", sleepDriver{})
+}
+
+func TestSlowQueryLogging(t *testing.T) {
+	sqlDB, err := sql.Open("postgresql-sleep", "")
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	conn, err := sqlDB.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var logs strings.Builder
+	ctx := zerolog.New(&logs).With().Str("request_id", "req-123").Logger().WithContext(context.Background())
+
+	c := &tracedConn{Conn: conn, slowQueryThreshold: 20 * time.Millisecond}
+
+	// A query under the threshold is not logged
+	_, err = c.ExecContext(ctx, "1ms", "secret-arg")
+	require.NoError(t, err)
+	require.Empty(t, logs.String())
+
+	// A query over the threshold is logged with its name and the request ID, but not its arguments
+	_, err = c.ExecContext(ctx, "30ms", "secret-arg")
+	require.NoError(t, err)
+	entry := logs.String()
+	require.Contains(t, entry, `"message":"slow query"`)
+	require.Contains(t, entry, `"level":"warn"`)
+	require.Contains(t, entry, `"request_id":"req-123"`)
+	require.Contains(t, entry, `"query":"TestSlowQueryLogging"`)
+	require.Contains(t, entry, `"threshold":"20ms"`)
+	require.NotContains(t, entry, "secret-arg")
+
+	// Slow-query logging is off with a zero threshold
+	logs.Reset()
+	c.slowQueryThreshold = 0
+	_, err = c.ExecContext(ctx, "30ms")
+	require.NoError(t, err)
+	require.Empty(t, logs.String())
+}
+
+func TestSlowTransactionLogging(t *testing.T) {
+	sqlDB, err := sql.Open("postgresql-sleep", "")
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	conn, err := sqlDB.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var logs strings.Builder
+	ctx := zerolog.New(&logs).With().Str("request_id", "req-123").Logger().WithContext(context.Background())
+	c := &tracedConn{Conn: conn, slowQueryThreshold: 20 * time.Millisecond}
+
+	// Statements in a transaction are traced, and so is the transaction as a whole
+	tx, err := c.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "30ms", "secret-arg")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	entries := logs.String()
+	require.Contains(t, entries, `"message":"slow query"`)
+	require.Contains(t, entries, `"query":"TestSlowTransactionLogging"`)
+	require.Contains(t, entries, `"message":"slow transaction"`)
+	require.Contains(t, entries, `"transaction":"TestSlowTransactionLogging"`)
+	require.Contains(t, entries, `"request_id":"req-123"`)
+	require.NotContains(t, entries, "secret-arg")
+
+	// A rollback after the commit is not logged again
+	logs.Reset()
+	_ = tx.Rollback()
+	require.Empty(t, logs.String())
+
+	// A transaction made of fast statements is slow if it stays open past the threshold
+	tx, err = c.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	for range 3 {
+		_, err = tx.ExecContext(ctx, "10ms")
+		require.NoError(t, err)
+	}
+	require.NoError(t, tx.Rollback())
+	entries = logs.String()
+	require.NotContains(t, entries, `"message":"slow query"`)
+	require.Contains(t, entries, `"message":"slow transaction"`)
+}
@@ -0,0 +1,142 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// Kinds of objects counted in tenant_usage.
+const (
+	usageCatalogs  = "catalogs"
+	usageVariants  = "variants"
+	usageSkillSets = "skillsets"
+)
+
+// usageLimit returns the quota of the tenant for objects of kind. A limit that is not positive
+// is unlimited.
+func usageLimit(tenantID catcommon.TenantId, kind string) int {
+	quota := config.TenantQuota(string(tenantID))
+	switch kind {
+	case usageCatalogs:
+		return quota.MaxCatalogs
+	case usageVariants:
+		return quota.MaxVariants
+	case usageSkillSets:
+		return quota.MaxSkillSets
+	}
+	return 0
+}
+
+// reserveUsageInTx counts one more object of kind for the tenant in tx. It returns
+// ErrQuotaExceeded, leaving the count unchanged, if the tenant already owns as many objects as
+// its quota allows. The counter row stays locked until tx ends, so concurrent reservations of
+// the same tenant cannot exceed the quota together.
+func reserveUsageInTx(ctx context.Context, tx *tracedTx, tenantID catcommon.TenantId, kind string) apperrors.Error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO tenant_usage (tenant_id, kind, count)
+		VALUES ($1, $2, 0)
+		ON CONFLICT (tenant_id, kind) DO NOTHING;`,
+		tenantID, kind)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("kind", kind).Msg("failed to create usage counter")
+		return dberror.FromErr(err)
+	}
+
+	limit := usageLimit(tenantID, kind)
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tenant_usage SET count = count + 1
+		WHERE tenant_id = $1 AND kind = $2 AND ($3 <= 0 OR count < $3);`,
+		tenantID, kind, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("kind", kind).Msg("failed to update usage counter")
+		return dberror.FromErr(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.FromErr(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrQuotaExceeded.Msg(fmt.Sprintf("tenant quota of %d %s reached", limit, kind))
+	}
+	return nil
+}
+
+// releaseUsageInTx counts n fewer objects of kind for the tenant in tx.
+func releaseUsageInTx(ctx context.Context, tx *tracedTx, tenantID catcommon.TenantId, kind string, n int) apperrors.Error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE tenant_usage SET count = GREATEST(count - $3, 0)
+		WHERE tenant_id = $1 AND kind = $2;`,
+		tenantID, kind, n)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("kind", kind).Msg("failed to update usage counter")
+		return dberror.FromErr(err)
+	}
+	return nil
+}
+
+// CountActiveSessions returns the number of unexpired sessions of the tenant in one of the given
+// status summaries.
+func (mm *metadataManager) CountActiveSessions(ctx context.Context, statusSummaries []string) (int, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return 0, dberror.ErrMissingTenantID
+	}
+
+	var count int
+	err := mm.conn().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sessions
+		WHERE tenant_id = $1
+			AND status_summary = ANY($2)
+			AND expires_at > NOW();`,
+		tenantID, statusSummaries).Scan(&count)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tenant_id", string(tenantID)).Msg("failed to count active sessions")
+		return 0, dberror.FromErr(err)
+	}
+	return count, nil
+}
+
+// CountActiveSessionsByTangent returns the number of unexpired sessions of the tenant in one of
+// the given statuses, keyed by the tangent they are assigned to.
+func (mm *metadataManager) CountActiveSessionsByTangent(ctx context.Context, statusSummaries []string) (map[uuid.UUID]int, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	rows, err := mm.conn().QueryContext(ctx, `
+		SELECT tangent_id, COUNT(*) FROM sessions
+		WHERE tenant_id = $1
+			AND status_summary = ANY($2)
+			AND expires_at > NOW()
+		GROUP BY tangent_id;`,
+		tenantID, statusSummaries)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tenant_id", string(tenantID)).Msg("failed to count active sessions by tangent")
+		return nil, dberror.FromErr(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var tangentID uuid.UUID
+		var count int
+		if err := rows.Scan(&tangentID, &count); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan active session count")
+			return nil, dberror.FromErr(err)
+		}
+		counts[tangentID] = count
+	}
+	if err := rows.Err(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to count active sessions by tangent")
+		return nil, dberror.FromErr(err)
+	}
+	return counts, nil
+}
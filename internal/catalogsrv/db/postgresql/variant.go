@@ -48,7 +48,7 @@ func (mm *metadataManager) CreateVariant(ctx context.Context, variant *models.Va
 	return nil
 }
 
-func (mm *metadataManager) createVariantWithTransaction(ctx context.Context, variant *models.Variant, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createVariantWithTransaction(ctx context.Context, variant *models.Variant, tx *tracedTx) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -68,6 +68,9 @@ func (mm *metadataManager) createVariantWithTransaction(ctx context.Context, var
 	if err := mm.insertVariantInTx(ctx, tx, variant, variantID, rgDirID, ssDirID, tenantID); err != nil {
 		return err
 	}
+	if err := reserveUsageInTx(ctx, tx, tenantID, usageVariants); err != nil {
+		return err
+	}
 
 	// Create default namespace
 	if err := mm.createDefaultNamespaceInTx(ctx, tx, variant, tenantID); err != nil {
@@ -87,7 +90,7 @@ func (mm *metadataManager) createVariantWithTransaction(ctx context.Context, var
 	return nil
 }
 
-func (mm *metadataManager) insertVariantInTx(ctx context.Context, tx *sql.Tx, variant *models.Variant, variantID, rgDirID, ssDirID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
+func (mm *metadataManager) insertVariantInTx(ctx context.Context, tx *tracedTx, variant *models.Variant, variantID, rgDirID, ssDirID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
 	queryVariant := `
 		INSERT INTO variants (variant_id, name, description, info, catalog_id, resource_directory, skillset_directory, tenant_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
@@ -127,10 +130,10 @@ func (mm *metadataManager) handleVariantInsertError(ctx context.Context, err err
 		}
 	}
 	log.Ctx(ctx).Error().Err(err).Str("name", variant.Name).Str("variant_id", variant.VariantID.String()).Msg("failed to insert variant")
-	return dberror.ErrDatabase.Err(err)
+	return dberror.FromErr(err)
 }
 
-func (mm *metadataManager) createDefaultNamespaceInTx(ctx context.Context, tx *sql.Tx, variant *models.Variant, tenantID catcommon.TenantId) apperrors.Error {
+func (mm *metadataManager) createDefaultNamespaceInTx(ctx context.Context, tx *tracedTx, variant *models.Variant, tenantID catcommon.TenantId) apperrors.Error {
 	namespace := models.Namespace{
 		Name:        catcommon.DefaultNamespace,
 		VariantID:   variant.VariantID,
@@ -146,7 +149,7 @@ func (mm *metadataManager) createDefaultNamespaceInTx(ctx context.Context, tx *s
 	return nil
 }
 
-func (mm *metadataManager) createResourceDirectoryInTx(ctx context.Context, tx *sql.Tx, variant *models.Variant, rgDirID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
+func (mm *metadataManager) createResourceDirectoryInTx(ctx context.Context, tx *tracedTx, variant *models.Variant, rgDirID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
 	dir := models.SchemaDirectory{
 		DirectoryID: rgDirID,
 		VariantID:   variant.VariantID,
@@ -162,7 +165,7 @@ func (mm *metadataManager) createResourceDirectoryInTx(ctx context.Context, tx *
 	return mm.insertDirectoryInTx(ctx, tx, dir, tableName, "resource groups directory")
 }
 
-func (mm *metadataManager) createSkillsetDirectoryInTx(ctx context.Context, tx *sql.Tx, variant *models.Variant, ssDirID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
+func (mm *metadataManager) createSkillsetDirectoryInTx(ctx context.Context, tx *tracedTx, variant *models.Variant, ssDirID uuid.UUID, tenantID catcommon.TenantId) apperrors.Error {
 	ssDir := models.SchemaDirectory{
 		DirectoryID: ssDirID,
 		VariantID:   variant.VariantID,
@@ -178,7 +181,7 @@ func (mm *metadataManager) createSkillsetDirectoryInTx(ctx context.Context, tx *
 	return mm.insertDirectoryInTx(ctx, tx, ssDir, tableName, "skillset directory")
 }
 
-func (mm *metadataManager) insertDirectoryInTx(ctx context.Context, tx *sql.Tx, dir models.SchemaDirectory, tableName, directoryType string) apperrors.Error {
+func (mm *metadataManager) insertDirectoryInTx(ctx context.Context, tx *tracedTx, dir models.SchemaDirectory, tableName, directoryType string) apperrors.Error {
 	query := ` INSERT INTO ` + tableName + ` (directory_id, variant_id, tenant_id, directory)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (tenant_id, directory_id) DO NOTHING RETURNING directory_id;`
@@ -190,7 +193,7 @@ func (mm *metadataManager) insertDirectoryInTx(ctx context.Context, tx *sql.Tx,
 			log.Ctx(ctx).Info().Str("directory_id", dir.DirectoryID.String()).Msgf("%s already exists, skipping", directoryType)
 			return nil
 		} else {
-			return dberror.ErrDatabase.Err(err)
+			return dberror.FromErr(err)
 		}
 	}
 	dir.DirectoryID = directoryID
@@ -236,7 +239,7 @@ func (mm *metadataManager) GetVariant(ctx context.Context, catalogID uuid.UUID,
 			return nil, dberror.ErrNotFound.Msg("variant not found")
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve variant")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return variant, nil
@@ -263,7 +266,7 @@ func (mm *metadataManager) GetVariantByID(ctx context.Context, variantID uuid.UU
 			return nil, dberror.ErrNotFound.Msg("variant not found")
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve variant")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return variant, nil
@@ -289,7 +292,7 @@ func (mm *metadataManager) GetVariantIDFromName(ctx context.Context, catalogID u
 			return uuid.Nil, dberror.ErrNotFound.Msg("variant not found")
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve variant ID")
-		return uuid.Nil, dberror.ErrDatabase.Err(err)
+		return uuid.Nil, dberror.FromErr(err)
 	}
 
 	return variantID, nil
@@ -348,7 +351,7 @@ func (mm *metadataManager) UpdateVariant(ctx context.Context, variantID uuid.UUI
 			}
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update variant")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -387,13 +390,13 @@ func (mm *metadataManager) DeleteVariant(ctx context.Context, catalogID, variant
 
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to delete variant")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve result information")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	if rowsAffected == 0 {
@@ -425,7 +428,7 @@ func (mm *metadataManager) GetMetadataNames(ctx context.Context, catalogID uuid.
 			return "", "", dberror.ErrNotFound.Msg("variant not found")
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve variant")
-		return "", "", dberror.ErrDatabase.Err(err)
+		return "", "", dberror.FromErr(err)
 	}
 
 	return catalogName, variantName, nil
@@ -450,7 +453,7 @@ func (mm *metadataManager) ListVariantsByCatalog(ctx context.Context, catalogID
 	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("catalog_id", catalogID.String()).Msg("failed to query variants")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	defer rows.Close()
 
@@ -460,14 +463,14 @@ func (mm *metadataManager) ListVariantsByCatalog(ctx context.Context, catalogID
 		err := rows.Scan(&variant.VariantID, &variant.Name, &variant.ResourceDirectoryID, &variant.SkillsetDirectoryID)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan variant row")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 		variants = append(variants, variant)
 	}
 
 	if err = rows.Err(); err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("error iterating over variant rows")
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return variants, nil
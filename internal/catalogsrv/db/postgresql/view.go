@@ -47,7 +47,7 @@ func (mm *metadataManager) CreateView(ctx context.Context, view *models.View) (e
 	return nil
 }
 
-func (mm *metadataManager) createViewWithTransaction(ctx context.Context, view *models.View, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createViewWithTransaction(ctx context.Context, view *models.View, tx *tracedTx) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -88,7 +88,7 @@ func (mm *metadataManager) createViewWithTransaction(ctx context.Context, view *
 			}
 		}
 		log.Ctx(ctx).Error().Err(err).Str("label", view.Label).Msg("failed to insert view")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	return nil
@@ -107,6 +107,7 @@ func (mm *metadataManager) GetView(ctx context.Context, viewID uuid.UUID) (*mode
 			v.description,
 			v.info,
 			v.rules,
+			v.token_epoch,
 			v.catalog_id,
 			v.tenant_id,
 			c.name AS catalog
@@ -121,13 +122,13 @@ func (mm *metadataManager) GetView(ctx context.Context, viewID uuid.UUID) (*mode
 
 	var view models.View
 	err := mm.conn().QueryRowContext(ctx, query, tenantID, viewID).
-		Scan(&view.ViewID, &view.Label, &view.Description, &view.Info, &view.Rules, &view.CatalogID, &view.TenantID, &view.Catalog)
+		Scan(&view.ViewID, &view.Label, &view.Description, &view.Info, &view.Rules, &view.TokenEpoch, &view.CatalogID, &view.TenantID, &view.Catalog)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("view not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return &view, nil
@@ -146,6 +147,7 @@ func (mm *metadataManager) GetViewByLabel(ctx context.Context, label string, cat
 			v.description,
 			v.info,
 			v.rules,
+			v.token_epoch,
 			v.catalog_id,
 			v.tenant_id,
 			c.name AS catalog
@@ -161,13 +163,13 @@ func (mm *metadataManager) GetViewByLabel(ctx context.Context, label string, cat
 
 	var view models.View
 	err := mm.conn().QueryRowContext(ctx, query, tenantID, catalogID, label).
-		Scan(&view.ViewID, &view.Label, &view.Description, &view.Info, &view.Rules, &view.CatalogID, &view.TenantID, &view.Catalog)
+		Scan(&view.ViewID, &view.Label, &view.Description, &view.Info, &view.Rules, &view.TokenEpoch, &view.CatalogID, &view.TenantID, &view.Catalog)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, dberror.ErrNotFound.Msg("view not found")
 		}
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return &view, nil
@@ -217,12 +219,12 @@ func (mm *metadataManager) UpdateView(ctx context.Context, view *models.View) ap
 	result, err := mm.conn().ExecContext(ctx, query, args...)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update view")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("view not found")
@@ -231,6 +233,35 @@ func (mm *metadataManager) UpdateView(ctx context.Context, view *models.View) ap
 	return nil
 }
 
+// BumpViewTokenEpoch increments the token epoch of a view and returns the new epoch.
+// Access tokens minted before the bump carry an older epoch and are rejected on validation.
+func (mm *metadataManager) BumpViewTokenEpoch(ctx context.Context, viewID uuid.UUID) (int64, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return 0, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE views
+		SET token_epoch = token_epoch + 1,
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND view_id = $2
+		RETURNING token_epoch
+	`
+
+	var epoch int64
+	err := mm.conn().QueryRowContext(ctx, query, tenantID, viewID).Scan(&epoch)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, dberror.ErrNotFound.Msg("view not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to bump view token epoch")
+		return 0, dberror.FromErr(err)
+	}
+
+	return epoch, nil
+}
+
 func (mm *metadataManager) DeleteView(ctx context.Context, viewID uuid.UUID) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
@@ -244,12 +275,12 @@ func (mm *metadataManager) DeleteView(ctx context.Context, viewID uuid.UUID) app
 
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, viewID)
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 	if rowsAffected == 0 {
 		return dberror.ErrNotFound.Msg("view not found")
@@ -280,13 +311,13 @@ func (mm *metadataManager) DeleteViewByLabel(ctx context.Context, label string,
 	result, err := mm.conn().ExecContext(ctx, query, tenantID, catalogID, label)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to delete view")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve result information")
-		return dberror.ErrDatabase.Err(err)
+		return dberror.FromErr(err)
 	}
 
 	if rowsAffected == 0 {
@@ -312,7 +343,7 @@ func (mm *metadataManager) ListViewsByCatalog(ctx context.Context, catalogID uui
 
 	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
 	if err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 	defer rows.Close()
 
@@ -324,7 +355,7 @@ func (mm *metadataManager) ListViewsByCatalog(ctx context.Context, catalogID uui
 		err := rows.Scan(&view.ViewID, &view.Label, &description, &view.Info, &view.Rules, &view.CatalogID, &view.TenantID)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan view row")
-			return nil, dberror.ErrDatabase.Err(err)
+			return nil, dberror.FromErr(err)
 		}
 		if description.Valid {
 			view.Description = description.String
@@ -333,7 +364,7 @@ func (mm *metadataManager) ListViewsByCatalog(ctx context.Context, catalogID uui
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, dberror.ErrDatabase.Err(err)
+		return nil, dberror.FromErr(err)
 	}
 
 	return result, nil
@@ -28,12 +28,13 @@ func (mm *metadataManager) CreateViewToken(ctx context.Context, token *models.Vi
 	}
 
 	query := `
-		INSERT INTO view_tokens (view_id, tenant_id, expire_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO view_tokens (view_id, tenant_id, session_id, expire_at)
+		VALUES ($1, $2, $3, $4)
 		RETURNING token_id, created_at, updated_at`
 
+	sessionID := sql.NullString{String: token.SessionID.String(), Valid: token.SessionID != uuid.Nil}
 	errDb := mm.conn().QueryRowContext(ctx, query,
-		token.ViewID, tenantID, token.ExpireAt).
+		token.ViewID, tenantID, sessionID, token.ExpireAt).
 		Scan(&token.TokenID, &token.CreatedAt, &token.UpdatedAt)
 
 	if errDb != nil {
@@ -51,13 +52,14 @@ func (mm *metadataManager) GetViewToken(ctx context.Context, tokenID uuid.UUID)
 	}
 
 	query := `
-		SELECT token_id, view_id, tenant_id, expire_at, created_at, updated_at
+		SELECT token_id, view_id, tenant_id, session_id, expire_at, created_at, updated_at
 		FROM view_tokens
 		WHERE tenant_id = $1 AND token_id = $2`
 
 	token := &models.ViewToken{}
+	var sessionID sql.NullString
 	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, tokenID).
-		Scan(&token.TokenID, &token.ViewID, &token.TenantID, &token.ExpireAt, &token.CreatedAt, &token.UpdatedAt)
+		Scan(&token.TokenID, &token.ViewID, &token.TenantID, &sessionID, &token.ExpireAt, &token.CreatedAt, &token.UpdatedAt)
 
 	if errDb != nil {
 		if errDb == sql.ErrNoRows {
@@ -68,9 +70,49 @@ func (mm *metadataManager) GetViewToken(ctx context.Context, tokenID uuid.UUID)
 		return nil, dberror.ErrDatabase.Err(errDb)
 	}
 
+	if sessionID.Valid {
+		token.SessionID, _ = uuid.Parse(sessionID.String)
+	}
+
 	return token, nil
 }
 
+// ListSessionViewTokens returns the view tokens issued to the session that have not expired.
+func (mm *metadataManager) ListSessionViewTokens(ctx context.Context, sessionID uuid.UUID) ([]*models.ViewToken, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT token_id, view_id, tenant_id, expire_at, created_at, updated_at
+		FROM view_tokens
+		WHERE tenant_id = $1 AND session_id = $2 AND expire_at > NOW()`
+
+	rows, errDb := mm.conn().QueryContext(ctx, query, tenantID, sessionID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("session_id", sessionID.String()).Msg("failed to list view tokens of session")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	var tokens []*models.ViewToken
+	for rows.Next() {
+		token := &models.ViewToken{SessionID: sessionID}
+		if errDb := rows.Scan(&token.TokenID, &token.ViewID, &token.TenantID, &token.ExpireAt, &token.CreatedAt, &token.UpdatedAt); errDb != nil {
+			log.Ctx(ctx).Error().Err(errDb).Msg("failed to scan view token")
+			return nil, dberror.ErrDatabase.Err(errDb)
+		}
+		tokens = append(tokens, token)
+	}
+	if errDb := rows.Err(); errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("session_id", sessionID.String()).Msg("failed to list view tokens of session")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+
+	return tokens, nil
+}
+
 func (mm *metadataManager) UpdateViewTokenExpiry(ctx context.Context, tokenID uuid.UUID, expireAt time.Time) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
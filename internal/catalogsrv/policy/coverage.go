@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// ResourceCoverage reports whether a view grants any action on a catalog resource.
+type ResourceCoverage struct {
+	Resource TargetResource `json:"resource"`
+	Covered  bool           `json:"covered"`
+	Allowed  []Action       `json:"allowed,omitempty"`
+}
+
+// ViewCoverage is a page of the resources of a catalog and the coverage of a view on each.
+// NextCursor is set when more resources follow and is passed as the cursor to fetch the next page.
+type ViewCoverage struct {
+	View       string             `json:"view"`
+	Resources  []ResourceCoverage `json:"resources"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// CoverageOnResources evaluates the view on each resource. Resources outside the view's scope
+// are not covered.
+func CoverageOnResources(vd *ViewDefinition, resources []TargetResource) ([]ResourceCoverage, apperrors.Error) {
+	if vd == nil {
+		return nil, ErrInvalidView.Msg("view definition is nil")
+	}
+	coverage := make([]ResourceCoverage, 0, len(resources))
+	for _, resource := range resources {
+		if _, err := CanonicalizeResource(vd.Scope, resource); errors.Is(err, ErrResourceOutsideScope) {
+			coverage = append(coverage, ResourceCoverage{Resource: resource})
+			continue
+		}
+		permissions, err := EffectivePermissionsOnResource(vd, string(resource))
+		if err != nil {
+			return nil, err
+		}
+		coverage = append(coverage, ResourceCoverage{
+			Resource: resource,
+			Covered:  len(permissions.Allowed) > 0,
+			Allowed:  permissions.Allowed,
+		})
+	}
+	return coverage, nil
+}
+
+// CoverageForView evaluates the named view on the resources of the catalog, which are the
+// catalog itself and its views, variants, namespaces, resources and skillsets. Resources are
+// ordered by path, and up to limit resources whose path sorts after cursor are evaluated.
+func CoverageForView(ctx context.Context, catalogID uuid.UUID, viewName, cursor string, limit int) (*ViewCoverage, apperrors.Error) {
+	if catalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+
+	view, err := db.DB(ctx).GetViewByLabel(ctx, viewName, catalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrViewNotFound.New("view not found: " + viewName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view")
+		return nil, ErrUnableToLoadObject.Msg("unable to load view")
+	}
+	vd, err := unmarshalViewDefinition(view)
+	if err != nil {
+		return nil, err
+	}
+
+	page, nextCursor, err := listCatalogResources(ctx, catalogID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage, err := CoverageOnResources(vd, page)
+	if err != nil {
+		return nil, err
+	}
+	return &ViewCoverage{
+		View:       viewName,
+		Resources:  coverage,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// listCatalogResources returns up to limit resource paths of the catalog that sort after cursor,
+// and the cursor of the next page, which is empty on the last page.
+func listCatalogResources(ctx context.Context, catalogID uuid.UUID, cursor string, limit int) ([]TargetResource, string, apperrors.Error) {
+	// one more path than the page holds tells whether another page follows
+	paths, err := db.DB(ctx).ListCatalogResourcePaths(ctx, catalogID, cursor, limit+1)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list catalog resources")
+		return nil, "", ErrUnableToLoadObject.Msg("unable to list catalog resources")
+	}
+
+	nextCursor := ""
+	if len(paths) > limit {
+		paths = paths[:limit]
+		nextCursor = paths[limit-1]
+	}
+	resources := make([]TargetResource, 0, len(paths))
+	for _, path := range paths {
+		resources = append(resources, TargetResource(path))
+	}
+	return resources, nextCursor, nil
+}
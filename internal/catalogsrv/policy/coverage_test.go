@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageOnResources(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog", Variant: "dev"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceRead},
+				Targets: []TargetResource{"res://resources/app/*"},
+			},
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionSkillSetUse},
+				Targets: []TargetResource{"res://skillsets/tools/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourceRead},
+				Targets: []TargetResource{"res://resources/app/secrets"},
+			},
+		},
+	}
+
+	resources := []TargetResource{
+		"res://catalogs/test-catalog/variants/dev/resources/app/config",
+		"res://catalogs/test-catalog/variants/dev/resources/app/secrets",
+		"res://catalogs/test-catalog/variants/dev/resources/db/config",
+		"res://catalogs/test-catalog/variants/dev/skillsets/tools/kubernetes",
+		"res://catalogs/test-catalog/variants/prod/resources/app/config",
+	}
+	coverage, err := CoverageOnResources(vd, resources)
+	require.NoError(t, err)
+	require.Len(t, coverage, len(resources))
+
+	covered := make(map[TargetResource]bool)
+	for _, c := range coverage {
+		covered[c.Resource] = c.Covered
+	}
+	assert.Equal(t, map[TargetResource]bool{
+		"res://catalogs/test-catalog/variants/dev/resources/app/config":       true,
+		"res://catalogs/test-catalog/variants/dev/resources/app/secrets":      false, // denied by a rule
+		"res://catalogs/test-catalog/variants/dev/resources/db/config":        false, // not targeted by any rule
+		"res://catalogs/test-catalog/variants/dev/skillsets/tools/kubernetes": true,
+		"res://catalogs/test-catalog/variants/prod/resources/app/config":      false, // outside the view's scope
+	}, covered)
+
+	assert.Equal(t, []Action{ActionResourceRead}, coverage[0].Allowed)
+	assert.Contains(t, coverage[3].Allowed, ActionSkillSetUse)
+	assert.Empty(t, coverage[4].Allowed)
+
+	_, err = CoverageOnResources(nil, resources)
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package policy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultDecisionCacheSize is the number of policy decisions kept by the decision cache.
+const DefaultDecisionCacheSize = 4096
+
+// decisionCacheKey identifies a policy decision. The view is identified by a hash of the parts of
+// the view definition that affect the decision, so a changed view never matches an older entry.
+type decisionCacheKey struct {
+	viewHash [sha256.Size]byte
+	resource string
+	actions  string
+}
+
+type decisionCacheEntry struct {
+	key     decisionCacheKey
+	allowed bool
+	basis   map[Intent][]Rule
+}
+
+// decisionCache is a concurrency-safe LRU cache of policy decisions.
+type decisionCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // most recently used at the front
+	entries    map[decisionCacheKey]*list.Element
+}
+
+func newDecisionCache(maxEntries int) *decisionCache {
+	return &decisionCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[decisionCacheKey]*list.Element),
+	}
+}
+
+// policyDecisions caches the results of AreActionsAllowedOnResource.
+var policyDecisions = newDecisionCache(DefaultDecisionCacheSize)
+
+// get returns the cached decision for key. The returned basis is a copy that the caller may modify.
+func (c *decisionCache) get(key decisionCacheKey) (bool, map[Intent][]Rule, bool) {
+	if c == nil || c.maxEntries <= 0 {
+		return false, nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return false, nil, false
+	}
+	c.order.MoveToFront(elem)
+	entry := elem.Value.(*decisionCacheEntry)
+	return entry.allowed, copyBasis(entry.basis), true
+}
+
+// put caches a decision, evicting the least recently used entry if the cache is full.
+func (c *decisionCache) put(key decisionCacheKey, allowed bool, basis map[Intent][]Rule) {
+	if c == nil || c.maxEntries <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*decisionCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&decisionCacheEntry{
+		key:     key,
+		allowed: allowed,
+		basis:   copyBasis(basis),
+	})
+}
+
+// len returns the number of cached decisions.
+func (c *decisionCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// newDecisionCacheKey builds the cache key for a decision. Actions must already be sorted.
+func newDecisionCacheKey(vd *ViewDefinition, resource string, actions []Action) decisionCacheKey {
+	var sb strings.Builder
+	for i, action := range actions {
+		if i > 0 {
+			sb.WriteByte(0)
+		}
+		sb.WriteString(string(action))
+	}
+	return decisionCacheKey{
+		viewHash: hashViewRules(vd),
+		resource: resource,
+		actions:  sb.String(),
+	}
+}
+
+// hashViewRules hashes the scope and rules of a view definition. Fields are separated by a
+// zero byte and lists are terminated with their own markers so that different views cannot
+// produce the same input.
+func hashViewRules(vd *ViewDefinition) [sha256.Size]byte {
+	h := sha256.New()
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	write(vd.Scope.Catalog)
+	write(vd.Scope.Variant)
+	write(vd.Scope.Namespace)
+	for _, namespace := range vd.Scope.Namespaces {
+		write(namespace)
+	}
+	h.Write([]byte{1})
+	for _, rule := range vd.Rules {
+		write(string(rule.Intent))
+		for _, action := range rule.Actions {
+			write(string(action))
+		}
+		h.Write([]byte{1})
+		for _, target := range rule.Targets {
+			write(string(target))
+		}
+		h.Write([]byte{2})
+		// the reason does not affect the decision but is reported in the cached basis
+		write(rule.Reason)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// sortedActions returns a sorted copy of actions.
+func sortedActions(actions []Action) []Action {
+	sorted := slices.Clone(actions)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// copyBasis returns a copy of a decision basis so that cached rules are never shared with callers.
+func copyBasis(basis map[Intent][]Rule) map[Intent][]Rule {
+	if basis == nil {
+		return nil
+	}
+	copied := make(map[Intent][]Rule, len(basis))
+	for intent, rules := range basis {
+		copied[intent] = Rules(rules).DeepCopy()
+	}
+	return copied
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCacheTestView() *ViewDefinition {
+	return &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog", Variant: "test-variant", Namespace: "test-namespace"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceRead, ActionResourceEdit},
+				Targets: []TargetResource{"res://resources/app/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourceEdit},
+				Targets: []TargetResource{"res://resources/app/locked"},
+			},
+		},
+	}
+}
+
+func TestDecisionCache(t *testing.T) {
+	t.Run("changing the view definition changes the cached result", func(t *testing.T) {
+		vd := newCacheTestView()
+		actions := []Action{ActionResourceEdit, ActionResourceRead}
+
+		allowed, _, err := AreActionsAllowedOnResource(vd, "/resources/app/config", actions)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		_, _, cached := policyDecisions.get(newDecisionCacheKey(vd, "/resources/app/config", sortedActions(actions)))
+		assert.True(t, cached)
+
+		// the same view object is modified in place
+		vd.Rules = append(vd.Rules, Rule{
+			Intent:  IntentDeny,
+			Actions: []Action{ActionResourceEdit},
+			Targets: []TargetResource{"res://resources/app/config"},
+		})
+		allowed, basis, err := AreActionsAllowedOnResource(vd, "/resources/app/config", actions)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Len(t, basis[IntentDeny], 1)
+
+		vd.Rules = vd.Rules[:2]
+		allowed, _, err = AreActionsAllowedOnResource(vd, "/resources/app/config", actions)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("cached decisions match uncached evaluation", func(t *testing.T) {
+		vd := newCacheTestView()
+		for _, resource := range []string{"/resources/app/config", "/resources/app/locked", "/resources/other"} {
+			for _, actions := range [][]Action{{ActionResourceRead}, {ActionResourceEdit}, {ActionResourceEdit, ActionResourceRead}} {
+				want, wantBasis, err := evaluateActionsOnResource(vd, resource, sortedActions(actions))
+				require.NoError(t, err)
+				for range 2 {
+					got, gotBasis, err := AreActionsAllowedOnResource(vd, resource, actions)
+					require.NoError(t, err)
+					assert.Equal(t, want, got, "%s %v", resource, actions)
+					assert.Equal(t, wantBasis, gotBasis, "%s %v", resource, actions)
+				}
+			}
+		}
+	})
+
+	t.Run("action order does not affect the key", func(t *testing.T) {
+		vd := newCacheTestView()
+		a := newDecisionCacheKey(vd, "/resources/app/config", sortedActions([]Action{ActionResourceRead, ActionResourceEdit}))
+		b := newDecisionCacheKey(vd, "/resources/app/config", sortedActions([]Action{ActionResourceEdit, ActionResourceRead}))
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("callers cannot modify cached rules", func(t *testing.T) {
+		vd := newCacheTestView()
+		_, basis, err := AreActionsAllowedOnResource(vd, "/resources/app/locked", []Action{ActionResourceEdit})
+		require.NoError(t, err)
+		require.NotEmpty(t, basis[IntentDeny])
+		basis[IntentDeny][0].Intent = IntentAllow
+
+		_, basis, err = AreActionsAllowedOnResource(vd, "/resources/app/locked", []Action{ActionResourceEdit})
+		require.NoError(t, err)
+		assert.Equal(t, IntentDeny, basis[IntentDeny][0].Intent)
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		cache := newDecisionCache(2)
+		vd := newCacheTestView()
+		k1 := newDecisionCacheKey(vd, "/resources/a", []Action{ActionResourceRead})
+		k2 := newDecisionCacheKey(vd, "/resources/b", []Action{ActionResourceRead})
+		k3 := newDecisionCacheKey(vd, "/resources/c", []Action{ActionResourceRead})
+
+		cache.put(k1, true, nil)
+		cache.put(k2, false, nil)
+		_, _, ok := cache.get(k1) // k1 is now the most recently used
+		require.True(t, ok)
+		cache.put(k3, true, nil)
+
+		assert.Equal(t, 2, cache.len())
+		_, _, ok = cache.get(k2)
+		assert.False(t, ok)
+		_, _, ok = cache.get(k1)
+		assert.True(t, ok)
+		_, _, ok = cache.get(k3)
+		assert.True(t, ok)
+	})
+
+	t.Run("concurrent evaluation", func(t *testing.T) {
+		vd := newCacheTestView()
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 100 {
+					resource := fmt.Sprintf("/resources/app/item%d", (i+j)%10)
+					allowed, _, err := AreActionsAllowedOnResource(vd, resource, []Action{ActionResourceEdit})
+					assert.NoError(t, err)
+					assert.True(t, allowed)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func newBenchmarkView() *ViewDefinition {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog", Variant: "test-variant", Namespace: "test-namespace"},
+	}
+	for i := range 50 {
+		vd.Rules = append(vd.Rules, Rule{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceRead, ActionResourceEdit, ActionSkillSetUse},
+			Targets: []TargetResource{TargetResource(fmt.Sprintf("res://resources/app%d/*", i))},
+		})
+	}
+	return vd
+}
+
+func BenchmarkAreActionsAllowedOnResource(b *testing.B) {
+	vd := newBenchmarkView()
+	actions := []Action{ActionResourceRead, ActionResourceEdit}
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := AreActionsAllowedOnResource(vd, "/resources/app49/config", actions); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := evaluateActionsOnResource(vd, "/resources/app49/config", actions); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
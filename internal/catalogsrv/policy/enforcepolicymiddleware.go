@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"errors"
 	"net/http"
 	"slices"
 
@@ -59,6 +60,10 @@ func EnforceViewPolicyMiddleware(handler ResponseHandlerParam) httpx.RequestHand
 
 		// Resolve the target resource
 		targetResource, err := resolveTargetResource(targetScope, r.URL.Path)
+		if errors.Is(err, ErrResourceOutsideScope) {
+			log.Ctx(ctx).Warn().Str("path", r.URL.Path).Msg("access denied to resource outside the view scope")
+			return nil, ErrDisallowedByPolicy
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -37,6 +37,9 @@ var (
 	ErrInvalidCatalog  apperrors.Error = ErrViewError.New("invalid catalog").SetStatusCode(http.StatusBadRequest)
 	ErrInvalidView     apperrors.Error = ErrViewError.New("invalid view").SetStatusCode(http.StatusBadRequest)
 	ErrInvalidSkillSet apperrors.Error = ErrViewError.New("invalid skillset").SetStatusCode(http.StatusBadRequest)
+
+	ErrInvalidResourcePath  apperrors.Error = ErrViewError.New("invalid resource path").SetStatusCode(http.StatusBadRequest)
+	ErrResourceOutsideScope apperrors.Error = ErrInvalidResourcePath.New("resource is outside the scope").SetStatusCode(http.StatusBadRequest)
 )
 
 // Schema validation errors
@@ -50,6 +53,7 @@ var (
 	ErrAuthError                apperrors.Error = ErrViewError.New("authorization error").SetStatusCode(http.StatusForbidden)
 	ErrUnauthorizedToCreateView apperrors.Error = ErrAuthError.New("unauthorized to create view").SetStatusCode(http.StatusForbidden)
 	ErrDisallowedByPolicy       apperrors.Error = ErrAuthError.New("not allowed by policy").SetStatusCode(http.StatusForbidden)
+	ErrViewExpired              apperrors.Error = ErrAuthError.New("view has expired").SetStatusCode(http.StatusForbidden)
 )
 
 var (
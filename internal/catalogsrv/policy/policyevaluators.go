@@ -2,11 +2,16 @@ package policy
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive/internal/common/apperrors"
 	"github.com/tansive/tansive/internal/common/httpx"
 )
@@ -135,7 +140,9 @@ func ValidateDerivedView(ctx context.Context, parent *ViewDefinition, child *Vie
 //
 // Note: The function validates that the view definition, resource, and actions are non-empty.
 // It resolves the target scope and resource before performing the permission check.
-// All actions must be allowed for the function to return true.
+// All actions must be allowed for the function to return true. Actions are evaluated in sorted
+// order and decisions are cached by a hash of the view's scope and rules, so a changed view is
+// always evaluated afresh.
 func AreActionsAllowedOnResource(vd *ViewDefinition, resource string, actions []Action) (bool, map[Intent][]Rule, apperrors.Error) {
 	if vd == nil {
 		return false, nil, ErrInvalidView.Msg("view definition is nil")
@@ -147,10 +154,40 @@ func AreActionsAllowedOnResource(vd *ViewDefinition, resource string, actions []
 		return false, nil, ErrInvalidView.Msg("actions are empty")
 	}
 
+	actions = sortedActions(actions)
+	key := newDecisionCacheKey(vd, resource, actions)
+	if allowed, basis, ok := policyDecisions.get(key); ok {
+		return allowed, basis, nil
+	}
+
+	allowed, basis, err := evaluateActionsOnResource(vd, resource, actions)
+	if err != nil {
+		return false, nil, err
+	}
+	policyDecisions.put(key, allowed, basis)
+	return allowed, basis, nil
+}
+
+// evaluateActionsOnResource evaluates the view's rules for each action in turn, stopping at
+// the first action that is not allowed.
+func evaluateActionsOnResource(vd *ViewDefinition, resource string, actions []Action) (bool, map[Intent][]Rule, apperrors.Error) {
 	targetResource, err := resolveTargetResource(vd.Scope, resource)
+	if errors.Is(err, ErrResourceOutsideScope) {
+		// resources outside the view's scope are never allowed
+		return false, map[Intent][]Rule{
+			IntentAllow: {},
+			IntentDeny:  {},
+		}, nil
+	}
 	if err != nil {
 		return false, nil, ErrInvalidView.New(err.Error())
 	}
+	if namespace, ok := targetNamespace(targetResource); ok && !vd.Scope.AllowsNamespace(namespace) {
+		return false, map[Intent][]Rule{
+			IntentAllow: {},
+			IntentDeny:  {},
+		}, nil
+	}
 
 	vd = canonicalizeViewDefinition(vd)
 	var basis map[Intent][]Rule
@@ -165,6 +202,62 @@ func AreActionsAllowedOnResource(vd *ViewDefinition, resource string, actions []
 	return true, basis, nil
 }
 
+// MaxEffectivePermissionActions caps the number of actions evaluated by EffectivePermissionsOnResource.
+const MaxEffectivePermissionActions = 256
+
+// EffectivePermissions lists the actions a view allows and denies on a single resource.
+type EffectivePermissions struct {
+	Resource  string   `json:"resource"`
+	Allowed   []Action `json:"allowed"`
+	Denied    []Action `json:"denied"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// EffectivePermissionsOnResource evaluates every known action on the resource using the view's rules.
+// The known actions are the system actions in ValidActions together with the actions named in the
+// view's rules, evaluated in sorted order. At most MaxEffectivePermissionActions actions are evaluated,
+// and Truncated is set if the view names more.
+func EffectivePermissionsOnResource(vd *ViewDefinition, resource string) (*EffectivePermissions, apperrors.Error) {
+	if vd == nil {
+		return nil, ErrInvalidView.Msg("view definition is nil")
+	}
+	if resource == "" {
+		return nil, ErrInvalidView.Msg("resource is empty")
+	}
+	targetResource, err := resolveTargetResource(vd.Scope, resource)
+	if err != nil {
+		return nil, ErrInvalidView.New(err.Error())
+	}
+
+	actions := slices.Clone(ValidActions)
+	for _, rule := range vd.Rules {
+		actions = append(actions, rule.Actions...)
+	}
+	actions = removeDuplicates(sortedActions(actions))
+
+	permissions := &EffectivePermissions{
+		Resource: string(targetResource),
+		Allowed:  []Action{},
+		Denied:   []Action{},
+	}
+	if len(actions) > MaxEffectivePermissionActions {
+		actions = actions[:MaxEffectivePermissionActions]
+		permissions.Truncated = true
+	}
+	for _, action := range actions {
+		allowed, _, err := evaluateActionsOnResource(vd, resource, []Action{action})
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			permissions.Allowed = append(permissions.Allowed, action)
+		} else {
+			permissions.Denied = append(permissions.Denied, action)
+		}
+	}
+	return permissions, nil
+}
+
 // CanAdoptView determines if the current view has permission to adopt another view
 // within the catalog context.
 //
@@ -195,6 +288,48 @@ func CanAdoptView(ctx context.Context, view string) (bool, apperrors.Error) {
 	return allowed, nil
 }
 
+// AdoptableViews returns the views that the current view is allowed to adopt, in the order given.
+// Each view is checked the same way as CanAdoptView, using the ActionCatalogAdoptView permission
+// on the view's target. Internal views, whose labels start with "_", and expired views are left out.
+func AdoptableViews(ctx context.Context, views []*models.View) ([]*models.View, apperrors.Error) {
+	catalog := catcommon.GetCatalog(ctx)
+	if catalog == "" {
+		return nil, ErrInvalidView.Msg("unable to resolve catalog")
+	}
+	ourViewDef, err := ResolveAuthorizedViewDef(ctx)
+	if err != nil {
+		return nil, ErrInvalidView.Msg(err.Error())
+	}
+	if ourViewDef == nil {
+		return nil, ErrInvalidView.Msg("unable to resolve view definition")
+	}
+
+	now := time.Now()
+	adoptable := []*models.View{}
+	for _, view := range views {
+		if strings.HasPrefix(view.Label, "_") {
+			continue
+		}
+		var vd ViewDefinition
+		if err := json.Unmarshal(view.Rules, &vd); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("view", view.Label).Msg("failed to parse view definition")
+			continue
+		}
+		if vd.IsExpired(now) {
+			continue
+		}
+		viewResource, _ := resolveTargetResource(Scope{Catalog: catalog}, "/views/"+view.Label)
+		allowed, _ := ourViewDef.Rules.IsActionAllowedOnResource(ActionCatalogAdoptView, viewResource)
+		if !allowed {
+			allowed = CanAdoptViewAsUser(ctx, view.Label)
+		}
+		if allowed {
+			adoptable = append(adoptable, view)
+		}
+	}
+	return adoptable, nil
+}
+
 // CanUseSkillSet checks if the current view has permission to use a skill set
 // within the catalog context.
 //
@@ -241,6 +376,16 @@ func CanAdoptViewAsUser(ctx context.Context, view string) bool {
 	return false
 }
 
+// IsTenantAdmin checks if the current user may manage objects shared by all catalogs of the
+// tenant, such as tangents. View tokens are scoped to a catalog and never qualify. We currently
+// allow users by default in single user mode, where the user owns the tenant.
+func IsTenantAdmin(ctx context.Context) bool {
+	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser {
+		return false
+	}
+	return config.Config().SingleUserMode
+}
+
 func getResourceKindFromPath(resourcePath string) string {
 	path := strings.Trim(resourcePath, "/")
 	segments := strings.Split(path, "/")
@@ -262,15 +407,18 @@ func normalizeResourcePath(resourceKind string, resource TargetResource) TargetR
 	return resource
 }
 
+// ResolveResourcePath returns the canonical form of resourcePath within scope, which is the
+// path that view rules are evaluated against.
+func ResolveResourcePath(scope Scope, resourcePath string) (string, apperrors.Error) {
+	targetResource, err := resolveTargetResource(scope, resourcePath)
+	if err != nil {
+		return "", ErrInvalidView.New(err.Error())
+	}
+	return string(targetResource), nil
+}
+
 func resolveTargetResource(scope Scope, resourcePath string) (TargetResource, error) {
-	resourcePath = strings.TrimPrefix(resourcePath, "res://")
-	targetResource := TargetResource(resourcePath)
-	targetResource = normalizeResourcePath(getResourceKindFromPath(resourcePath), targetResource)
-	targetResource = canonicalizeResourcePath(scope, TargetResource("res://"+strings.TrimPrefix(string(targetResource), "/")))
-	if targetResource == "" {
-		return "", httpx.ErrApplicationError("unable to canonicalize resource path")
-	}
-	return targetResource, nil
+	return CanonicalizeResource(scope, TargetResource(resourcePath))
 }
 
 func ResolveAuthorizedViewDef(ctx context.Context) (*ViewDefinition, error) {
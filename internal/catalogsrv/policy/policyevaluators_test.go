@@ -3,9 +3,11 @@ package policy
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 )
 
 // These tests cover a mixture of scenarios several of which are not even valid
@@ -882,3 +884,191 @@ func TestAreActionsAllowedOnResource(t *testing.T) {
 		})
 	}
 }
+
+func TestAreActionsAllowedOnResourceNamespaces(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{
+			Catalog:    "test-catalog",
+			Variant:    "test-variant",
+			Namespaces: []string{"team-a", catcommon.DefaultNamespace},
+		},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionSkillSetUse},
+				Targets: []TargetResource{"res://namespaces/team-a/skillsets/*", "res://namespaces/team-b/skillsets/*", "res://skillsets/*"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		resource   string
+		want       bool
+	}{
+		{
+			name:       "listed namespace is allowed",
+			namespaces: []string{"team-a", catcommon.DefaultNamespace},
+			resource:   "/namespaces/team-a/skillsets/deploy",
+			want:       true,
+		},
+		{
+			name:       "unlisted namespace is denied",
+			namespaces: []string{"team-a", catcommon.DefaultNamespace},
+			resource:   "/namespaces/team-b/skillsets/deploy",
+			want:       false,
+		},
+		{
+			name:       "default namespace is allowed when listed",
+			namespaces: []string{"team-a", catcommon.DefaultNamespace},
+			resource:   "/skillsets/deploy",
+			want:       true,
+		},
+		{
+			name:       "default namespace is denied when not listed",
+			namespaces: []string{"team-a"},
+			resource:   "/skillsets/deploy",
+			want:       false,
+		},
+		{
+			name:       "without a restriction every namespace is allowed",
+			namespaces: nil,
+			resource:   "/namespaces/team-b/skillsets/deploy",
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := vd.DeepCopy()
+			view.Scope.Namespaces = tt.namespaces
+			allowed, basis, err := AreActionsAllowedOnResource(&view, tt.resource, []Action{ActionSkillSetUse})
+			if err != nil {
+				t.Fatalf("AreActionsAllowedOnResource() error = %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("AreActionsAllowedOnResource() = %v, want %v", allowed, tt.want)
+			}
+			if !tt.want && len(basis[IntentAllow]) != 0 {
+				t.Errorf("AreActionsAllowedOnResource() allow basis = %v, want none", basis[IntentAllow])
+			}
+		})
+	}
+}
+
+func TestEffectivePermissionsOnResource(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog", Variant: "test-variant"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceRead, ActionResourceGet, ActionResourcePut, "kubernetes.pods.list"},
+				Targets: []TargetResource{"res://resources/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourcePut},
+				Targets: []TargetResource{"res://resources/secrets"},
+			},
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionSkillSetUse},
+				Targets: []TargetResource{"res://skillsets/*"},
+			},
+		},
+	}
+
+	permissions, err := EffectivePermissionsOnResource(vd, "res://resources/secrets")
+	if err != nil {
+		t.Fatalf("EffectivePermissionsOnResource() error = %v", err)
+	}
+	wantAllowed := []Action{"kubernetes.pods.list", ActionResourceGet, ActionResourceRead}
+	if !slices.Equal(permissions.Allowed, wantAllowed) {
+		t.Errorf("Allowed = %v, want %v", permissions.Allowed, wantAllowed)
+	}
+	for _, action := range []Action{ActionResourcePut, ActionSkillSetUse, ActionCatalogAdmin} {
+		if !slices.Contains(permissions.Denied, action) {
+			t.Errorf("Denied = %v, want it to contain %s", permissions.Denied, action)
+		}
+	}
+	if len(permissions.Allowed)+len(permissions.Denied) != len(ValidActions)+1 {
+		t.Errorf("evaluated %d actions, want every known action", len(permissions.Allowed)+len(permissions.Denied))
+	}
+	if permissions.Resource != "res://catalogs/test-catalog/variants/test-variant/resources/secrets" {
+		t.Errorf("Resource = %s, want the canonical path", permissions.Resource)
+	}
+	if permissions.Truncated {
+		t.Error("Truncated = true, want false")
+	}
+
+	// the deny rule only covers the secrets resource
+	permissions, err = EffectivePermissionsOnResource(vd, "res://resources/config")
+	if err != nil {
+		t.Fatalf("EffectivePermissionsOnResource() error = %v", err)
+	}
+	if !slices.Contains(permissions.Allowed, ActionResourcePut) {
+		t.Errorf("Allowed = %v, want it to contain %s", permissions.Allowed, ActionResourcePut)
+	}
+
+	if _, err := EffectivePermissionsOnResource(vd, ""); err == nil {
+		t.Error("EffectivePermissionsOnResource() with an empty resource succeeded")
+	}
+}
+
+func TestAdoptableViews(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionCatalogAdoptView},
+				Targets: []TargetResource{"res://views/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionCatalogAdoptView},
+				Targets: []TargetResource{"res://views/prod-admin"},
+			},
+		},
+	}
+	ctx := catcommon.WithCatalogContext(context.Background(), &catcommon.CatalogContext{Catalog: "test-catalog"})
+	ctx = WithViewDefinition(ctx, vd)
+
+	newView := func(label string, def string) *models.View {
+		return &models.View{Label: label, Rules: []byte(def)}
+	}
+	rules := `{"scope": {"catalog": "test-catalog"}, "rules": []}`
+	views := []*models.View{
+		newView("dev-reader", rules),
+		newView("prod-admin", rules),
+		newView("_default", rules),
+		newView("expired-view", `{"scope": {"catalog": "test-catalog"}, "rules": [], "expiresAt": "2020-01-01T00:00:00Z"}`),
+		newView("prod-reader", rules),
+	}
+
+	adoptable, err := AdoptableViews(ctx, views)
+	if err != nil {
+		t.Fatalf("AdoptableViews() error = %v", err)
+	}
+	var got []string
+	for _, view := range adoptable {
+		got = append(got, view.Label)
+	}
+	want := []string{"dev-reader", "prod-reader"}
+	if !slices.Equal(got, want) {
+		t.Errorf("AdoptableViews() = %v, want %v", got, want)
+	}
+
+	// a view without the adopt action can adopt nothing
+	ctx = WithViewDefinition(ctx, &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog"},
+		Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogList}, Targets: []TargetResource{"res://views/*"}}},
+	})
+	adoptable, err = AdoptableViews(ctx, views)
+	if err != nil {
+		t.Fatalf("AdoptableViews() error = %v", err)
+	}
+	if len(adoptable) != 0 {
+		t.Errorf("AdoptableViews() returned %d views, want none", len(adoptable))
+	}
+}
@@ -2,6 +2,8 @@ package policy
 
 import (
 	"encoding/json"
+	"slices"
+	"time"
 
 	"github.com/tansive/tansive/internal/common/httpx"
 )
@@ -76,6 +78,9 @@ type Rule struct {
 	Intent  Intent           `json:"intent" validate:"required,viewRuleIntentValidator"`
 	Actions []Action         `json:"actions" validate:"required,dive,viewRuleActionValidator"`
 	Targets []TargetResource `json:"targets" validate:"-"`
+	// Reason is an optional human-readable explanation reported when the rule blocks a request.
+	// It does not take part in rule matching or subset checks.
+	Reason string `json:"reason,omitempty" validate:"-"`
 }
 
 type TargetResource string
@@ -84,24 +89,116 @@ type Scope struct {
 	Catalog   string `json:"catalog"`
 	Variant   string `json:"variant"`
 	Namespace string `json:"namespace"`
+	// Namespaces restricts a view without a namespace to the listed namespaces. Objects in
+	// the default namespace are only covered if catcommon.DefaultNamespace is listed.
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 func (v Scope) Equals(other Scope) bool {
 	return v.Catalog == other.Catalog &&
 		v.Variant == other.Variant &&
-		v.Namespace == other.Namespace
+		v.Namespace == other.Namespace &&
+		slices.Equal(v.Namespaces, other.Namespaces)
+}
+
+// AllowsNamespace reports whether objects in the namespace fall within the scope.
+func (v Scope) AllowsNamespace(namespace string) bool {
+	return len(v.Namespaces) == 0 || slices.Contains(v.Namespaces, namespace)
+}
+
+// Covers reports whether every object within the other scope also falls within the scope.
+func (v Scope) Covers(other Scope) bool {
+	if v.Catalog != other.Catalog {
+		return false
+	}
+	if v.Variant != "" && v.Variant != other.Variant {
+		return false
+	}
+	if v.Namespace != "" && v.Namespace != other.Namespace {
+		return false
+	}
+	if len(v.Namespaces) == 0 {
+		return true
+	}
+	if other.Namespace != "" {
+		return v.AllowsNamespace(other.Namespace)
+	}
+	if len(other.Namespaces) == 0 {
+		return false
+	}
+	for _, namespace := range other.Namespaces {
+		if !v.AllowsNamespace(namespace) {
+			return false
+		}
+	}
+	return true
 }
 
 type ViewDefinition struct {
-	Scope Scope `json:"scope" validate:"required"`
-	Rules Rules `json:"rules" validate:"required,dive"`
+	Scope         Scope      `json:"scope" validate:"required"`
+	Rules         Rules      `json:"rules" validate:"required,dive"`
+	BlockedSkills []string   `json:"blockedSkills,omitempty" validate:"omitempty,dive,skillNameValidator"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	AuditMode     AuditMode  `json:"auditMode,omitempty" validate:"omitempty,oneof=verbose compact"`
+	TokenTTL      string     `json:"tokenTTL,omitempty"`
 }
 
+// AuditMode controls how much detail the policy decisions of a view record in the audit log.
+type AuditMode string
+
+const (
+	// AuditModeCompact records allowed decisions without the rules that allowed them. It is the default.
+	AuditModeCompact AuditMode = "compact"
+	// AuditModeVerbose records the full rule basis of every decision.
+	AuditModeVerbose AuditMode = "verbose"
+)
+
 func (v ViewDefinition) DeepCopy() ViewDefinition {
+	var blockedSkills []string
+	if v.BlockedSkills != nil {
+		blockedSkills = make([]string, len(v.BlockedSkills))
+		copy(blockedSkills, v.BlockedSkills)
+	}
+	var expiresAt *time.Time
+	if v.ExpiresAt != nil {
+		t := *v.ExpiresAt
+		expiresAt = &t
+	}
+	scope := v.Scope
+	scope.Namespaces = slices.Clone(v.Scope.Namespaces)
 	return ViewDefinition{
-		Scope: v.Scope, // Scope is a struct of strings (safe to copy)
-		Rules: v.Rules.DeepCopy(),
+		Scope:         scope,
+		Rules:         v.Rules.DeepCopy(),
+		BlockedSkills: blockedSkills,
+		ExpiresAt:     expiresAt,
+		AuditMode:     v.AuditMode,
+		TokenTTL:      v.TokenTTL,
+	}
+}
+
+// IsExpired reports whether the view has an expiry time that is not after now.
+// Views without an expiry never expire.
+func (v *ViewDefinition) IsExpired(now time.Time) bool {
+	if v == nil || v.ExpiresAt == nil {
+		return false
+	}
+	return !now.Before(*v.ExpiresAt)
+}
+
+// IsAuditVerbose reports whether the view records the rule basis of allowed decisions.
+func (v *ViewDefinition) IsAuditVerbose() bool {
+	return v != nil && v.AuditMode == AuditModeVerbose
+}
+
+// IsSkillBlocked reports whether the skill is on the view's blocklist under its name or any of
+// its aliases, so that a skill cannot escape the blocklist by being renamed.
+// Blocked skills are denied regardless of the actions granted by the rules.
+func (v *ViewDefinition) IsSkillBlocked(skillName string, aliases ...string) bool {
+	if v == nil {
+		return false
 	}
+	return slices.Contains(v.BlockedSkills, skillName) ||
+		slices.ContainsFunc(aliases, func(alias string) bool { return slices.Contains(v.BlockedSkills, alias) })
 }
 
 func (r Rules) DeepCopy() Rules {
@@ -123,6 +220,7 @@ func (r Rule) DeepCopy() Rule {
 		Intent:  r.Intent,
 		Actions: actionsCopy,
 		Targets: targetsCopy,
+		Reason:  r.Reason,
 	}
 }
 
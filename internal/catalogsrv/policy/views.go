@@ -3,8 +3,11 @@ package policy
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"encoding/json"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
@@ -32,7 +36,12 @@ type viewSchema struct {
 
 // viewSpec contains the spec of a view
 type viewSpec struct {
-	Rules Rules `json:"rules" validate:"required,dive"`
+	Rules         Rules      `json:"rules" validate:"required,dive"`
+	BlockedSkills []string   `json:"blockedSkills,omitempty" validate:"omitempty,dive,skillNameValidator"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	Namespaces    []string   `json:"namespaces,omitempty"`
+	AuditMode     AuditMode  `json:"auditMode,omitempty" validate:"omitempty,oneof=verbose compact"`
+	TokenTTL      string     `json:"tokenTTL,omitempty"`
 }
 
 // Validate performs validation on the view schema and returns any validation errors.
@@ -47,14 +56,23 @@ func (v *viewSchema) Validate() schemaerr.ValidationErrors {
 		if len(v.Spec.Rules) == 0 {
 			validationErrors = append(validationErrors, schemaerr.ErrMissingRequiredAttribute("spec.rules"))
 		}
-		for _, rule := range v.Spec.Rules {
-			for _, target := range rule.Targets {
+		for i, rule := range v.Spec.Rules {
+			for j, target := range rule.Targets {
 				err := validateResourceURI(string(target))
 				if err != nil {
-					validationErrors = append(validationErrors, schemaerr.ErrInvalidResourceURI(string(target)+": "+err.Error()))
+					validationErrors = append(validationErrors,
+						schemaerr.ErrInvalidResourceURI(fmt.Sprintf("spec.rules[%d].targets[%d]", i, j), string(target)+": "+err.Error()))
 				}
 			}
 		}
+		if v.Spec.TokenTTL != "" {
+			if err := validateTokenTTL(v.Spec.TokenTTL); err != nil {
+				validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.tokenTTL", err.Error()))
+			}
+		}
+		if IsReservedViewName(v.Metadata.Name) {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("metadata.name", fmt.Sprintf("%s is reserved and cannot be used as a view name", v.Metadata.Name)))
+		}
 		return validationErrors
 	}
 
@@ -82,8 +100,13 @@ func (v *viewSchema) Validate() schemaerr.ValidationErrors {
 		case "viewRuleIntentValidator":
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidViewRuleIntent(jsonFieldName))
 		case "viewRuleActionValidator":
-			fieldName, _ := e.Value().(Action)
-			validationErrors = append(validationErrors, schemaerr.ErrInvalidViewRuleAction(string(fieldName)))
+			action, _ := e.Value().(Action)
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidViewRuleAction(viewFieldPath(e.Namespace()), string(action)))
+		case "skillNameValidator":
+			val, _ := e.Value().(string)
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidNameFormat(jsonFieldName, val))
+		case "oneof":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(jsonFieldName, "must be one of "+strings.ReplaceAll(e.Param(), " ", ", ")))
 		default:
 			validationErrors = append(validationErrors, schemaerr.ErrValidationFailed(jsonFieldName))
 		}
@@ -92,12 +115,123 @@ func (v *viewSchema) Validate() schemaerr.ValidationErrors {
 	return validationErrors
 }
 
-// parseAndValidateView parses a JSON byte slice into a viewSchema, validates it,
-// and optionally overrides the name and catalog fields.
-// Returns an error if the JSON is invalid or the schema validation fails.
-func parseAndValidateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*viewSchema, apperrors.Error) {
+// reservedViewNames are the routes served alongside views under /views. A view with one of these
+// names would be shadowed by the route.
+var reservedViewNames = []string{"adoptable"}
+
+// IsReservedViewName reports whether name is reserved for a route under /views.
+func IsReservedViewName(name string) bool {
+	return slices.Contains(reservedViewNames, name)
+}
+
+// validateTokenTTL checks that ttl is a positive duration that does not exceed the maximum token
+// age, since a token is rejected once it is older than that.
+func validateTokenTTL(ttl string) error {
+	d, err := config.ParseDuration(ttl)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v", ttl, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("tokenTTL must be positive")
+	}
+	if maxAge, err := config.Config().Auth.GetMaxTokenAge(); err == nil && d > maxAge {
+		return fmt.Errorf("tokenTTL must not exceed the maximum token age of %s", config.Config().Auth.MaxTokenAge)
+	}
+	return nil
+}
+
+// validateForCreate performs the validation required before a view is created. In addition to
+// the schema checks in Validate, the expiry must be in the future and every rule target must fall
+// within the catalog of the view, which bounds anything a view can grant.
+func (v *viewSchema) validateForCreate() schemaerr.ValidationErrors {
+	validationErrors := v.Validate()
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	if v.Spec.ExpiresAt != nil && !v.Spec.ExpiresAt.After(time.Now()) {
+		validationErrors = append(validationErrors,
+			schemaerr.ErrInvalidValue("spec.expiresAt", "expiresAt must be in the future"))
+	}
+
+	return append(validationErrors, validateViewBounds(v.scope(), v.Spec.Rules)...)
+}
+
+// scope returns the scope of the view as it is stored in its view definition.
+func (v *viewSchema) scope() Scope {
+	return Scope{
+		Catalog:    v.Metadata.Catalog,
+		Variant:    v.Metadata.Variant.String(),
+		Namespace:  v.Metadata.Namespace.String(),
+		Namespaces: v.Spec.Namespaces,
+	}
+}
+
+// validateViewBounds checks that a view with the given scope and rules stays within its
+// catalog: namespaces may only restrict a view scoped to a variant, and every rule target must
+// fall within the scope of the view and its catalog.
+func validateViewBounds(scope Scope, rules Rules) schemaerr.ValidationErrors {
+	var validationErrors schemaerr.ValidationErrors
+	if len(scope.Namespaces) > 0 {
+		if scope.Variant == "" {
+			validationErrors = append(validationErrors,
+				schemaerr.ErrInvalidValue("spec.namespaces", "namespaces require a view scoped to a variant"))
+		}
+		if scope.Namespace != "" {
+			validationErrors = append(validationErrors,
+				schemaerr.ErrInvalidValue("spec.namespaces", "namespaces cannot be used in a view scoped to a namespace"))
+		}
+	}
+
+	catalogAdmin := canonicalizeViewDefinition(&ViewDefinition{
+		Scope: Scope{Catalog: scope.Catalog},
+		Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogAdmin}, Targets: []TargetResource{}}},
+	})
+	for i, rule := range rules {
+		for j, target := range rule.Targets {
+			resource, err := CanonicalizeResource(scope, target)
+			if err != nil {
+				reason := "invalid target"
+				if errors.Is(err, ErrResourceOutsideScope) {
+					reason = "target is outside the view scope"
+				}
+				validationErrors = append(validationErrors,
+					schemaerr.ErrInvalidResourceURI(fmt.Sprintf("spec.rules[%d].targets[%d]", i, j), string(target)+": "+reason))
+				continue
+			}
+			if allowed, _ := catalogAdmin.Rules.matchesAdmin(string(resource)); !allowed {
+				validationErrors = append(validationErrors,
+					schemaerr.ErrInvalidResourceURI(fmt.Sprintf("spec.rules[%d].targets[%d]", i, j), string(target)+": target is outside the catalog"))
+			}
+		}
+	}
+
+	return validationErrors
+}
+
+// viewFieldPath converts a validator namespace such as "viewSchema.Spec.Rules[0].Actions[1]"
+// into the JSON path of the field, e.g. "spec.rules[0].actions[1]".
+func viewFieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+	for i, segment := range segments {
+		if segment != "" {
+			segments[i] = strings.ToLower(segment[:1]) + segment[1:]
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// parseView parses a JSON byte slice into a viewSchema and optionally overrides
+// the catalog, variant and namespace fields.
+func parseView(resourceJSON []byte, m *interfaces.Metadata) (*viewSchema, apperrors.Error) {
 	view := &viewSchema{}
 	if err := json.Unmarshal(resourceJSON, view); err != nil {
+		if reasonErr := checkRuleReasons(resourceJSON); reasonErr != nil {
+			return nil, reasonErr
+		}
 		return nil, ErrInvalidView.Msg("failed to parse view spec")
 	}
 
@@ -113,10 +247,51 @@ func parseAndValidateView(ctx context.Context, resourceJSON []byte, m *interface
 		view.Metadata.Namespace = m.Namespace
 	}
 
+	return view, nil
+}
+
+// checkRuleReasons reports a rule whose reason is not a string, so that the author gets a
+// more useful error than a generic parse failure.
+func checkRuleReasons(resourceJSON []byte) apperrors.Error {
+	var raw struct {
+		Spec struct {
+			Rules []map[string]json.RawMessage `json:"rules"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(resourceJSON, &raw); err != nil {
+		return nil
+	}
+	for i, rule := range raw.Spec.Rules {
+		reason, ok := rule["reason"]
+		if !ok {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(reason, &s); err != nil && string(reason) != "null" {
+			return ErrInvalidView.Msg(fmt.Sprintf("spec.rules[%d].reason must be a string", i))
+		}
+	}
+	return nil
+}
+
+// parseAndValidateView parses a JSON byte slice into a viewSchema, validates it,
+// and optionally overrides the name and catalog fields. Like create, it checks that the view
+// stays within its catalog, so an update cannot widen a view past those bounds.
+// Returns an error if the JSON is invalid or the schema validation fails.
+func parseAndValidateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*viewSchema, apperrors.Error) {
+	view, err := parseView(resourceJSON, m)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := view.Validate(); err != nil {
 		return nil, ErrInvalidSchema.Err(err)
 	}
 
+	if err := validateViewBounds(view.scope(), view.Spec.Rules); len(err) > 0 {
+		return nil, ErrInvalidSchema.Err(err)
+	}
+
 	if err := resolveMetadataIDS(ctx, &view.Metadata); err != nil {
 		return nil, err
 	}
@@ -124,6 +299,33 @@ func parseAndValidateView(ctx context.Context, resourceJSON []byte, m *interface
 	return view, nil
 }
 
+// ValidateView runs the validation performed by CreateView on a view definition without
+// persisting it. Problems with the definition are returned as validation errors. An error is
+// returned only if the definition cannot be parsed or its catalog or variant cannot be resolved.
+func ValidateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (schemaerr.ValidationErrors, apperrors.Error) {
+	view, err := parseView(resourceJSON, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if validationErrors := view.validateForCreate(); len(validationErrors) > 0 {
+		return validationErrors, nil
+	}
+
+	if err := resolveMetadataIDS(ctx, &view.Metadata); err != nil {
+		return nil, err
+	}
+
+	if err := validateViewNamespaces(ctx, view); err != nil {
+		if errors.Is(err, ErrNamespaceNotFound) {
+			return schemaerr.ValidationErrors{schemaerr.ErrInvalidValue("spec.namespaces", err.Error())}, nil
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 // resolveMetadataIDS resolves the catalogID and variantID
 func resolveMetadataIDS(ctx context.Context, m *interfaces.Metadata) apperrors.Error {
 	if m.IDS.CatalogID == uuid.Nil {
@@ -153,6 +355,24 @@ func resolveMetadataIDS(ctx context.Context, m *interfaces.Metadata) apperrors.E
 	return nil
 }
 
+// validateViewNamespaces checks that every namespace the view is restricted to exists in the
+// view's variant. The default namespace always exists.
+func validateViewNamespaces(ctx context.Context, view *viewSchema) apperrors.Error {
+	for _, namespace := range view.Spec.Namespaces {
+		if namespace == catcommon.DefaultNamespace {
+			continue
+		}
+		if _, err := db.DB(ctx).GetNamespace(ctx, namespace, view.Metadata.IDS.VariantID); err != nil {
+			if errors.Is(err, dberror.ErrNotFound) {
+				return ErrNamespaceNotFound.New("namespace not found: " + namespace)
+			}
+			log.Ctx(ctx).Error().Err(err).Msg("failed to load namespace")
+			return ErrUnableToLoadObject.Msg("unable to load namespace")
+		}
+	}
+	return nil
+}
+
 // createViewModel creates a view model from a view schema and catalog ID.
 // The view definition is bound to the view metadata.  The rules specified are relative to
 // the scope of the view definition.
@@ -167,7 +387,12 @@ func createViewModel(ctx context.Context, view *viewSchema, purpose string) (*mo
 	viewDef.Scope.Catalog = view.Metadata.Catalog
 	viewDef.Scope.Variant = view.Metadata.Variant.String()
 	viewDef.Scope.Namespace = view.Metadata.Namespace.String()
+	viewDef.Scope.Namespaces = removeDuplicates(view.Spec.Namespaces)
 	viewDef.Rules = view.Spec.Rules
+	viewDef.BlockedSkills = view.Spec.BlockedSkills
+	viewDef.ExpiresAt = view.Spec.ExpiresAt
+	viewDef.AuditMode = view.Spec.AuditMode
+	viewDef.TokenTTL = view.Spec.TokenTTL
 
 	rulesJSON, err := viewDef.ToJSON()
 	if err != nil {
@@ -198,58 +423,221 @@ func createViewModel(ctx context.Context, view *viewSchema, purpose string) (*mo
 
 // CreateView creates a new view in the database.
 func CreateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*models.View, apperrors.Error) {
-	view, err := parseAndValidateView(ctx, resourceJSON, m)
-	if err != nil || view == nil {
-		return nil, err
+	v, _, err := createView(ctx, resourceJSON, m)
+	return v, err
+}
+
+// createView creates a new view like CreateView, and reports the duplicates removed from its rules.
+func createView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*models.View, *DuplicateReport, apperrors.Error) {
+	view, err := parseView(resourceJSON, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if validationErrors := view.validateForCreate(); len(validationErrors) > 0 {
+		return nil, nil, ErrInvalidSchema.Err(validationErrors)
+	}
+
+	if err := resolveMetadataIDS(ctx, &view.Metadata); err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateViewNamespaces(ctx, view); err != nil {
+		return nil, nil, err
 	}
 
 	// Remove duplicates from rules
+	report := reportDuplicates(view.Spec.Rules, view.Spec.BlockedSkills)
 	view.Spec.Rules = deduplicateRules(view.Spec.Rules)
+	view.Spec.BlockedSkills = removeDuplicates(view.Spec.BlockedSkills)
 
 	v, err := createViewModel(ctx, view, ViewPurposeCreate)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := db.DB(ctx).CreateView(ctx, v); err != nil {
 		if errors.Is(err, dberror.ErrAlreadyExists) {
-			return nil, ErrAlreadyExists.New("view already exists: " + view.Metadata.Name)
+			return nil, nil, ErrAlreadyExists.New("view already exists: " + view.Metadata.Name)
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to create view")
-		return nil, ErrViewError.New("failed to create view: " + err.Error())
+		return nil, nil, ErrViewError.New("failed to create view: " + err.Error())
 	}
 
-	return v, nil
+	return v, report, nil
 }
 
 // UpdateView updates an existing view in the database.
 func UpdateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*models.View, apperrors.Error) {
+	v, _, err := updateView(ctx, resourceJSON, m)
+	return v, err
+}
+
+// updateView updates a view like UpdateView, and reports the duplicates removed from its rules.
+func updateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*models.View, *DuplicateReport, apperrors.Error) {
 	view, err := parseAndValidateView(ctx, resourceJSON, m)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	if err := validateViewNamespaces(ctx, view); err != nil {
+		return nil, nil, err
+	}
+
+	report := reportDuplicates(view.Spec.Rules, view.Spec.BlockedSkills)
 	view.Spec.Rules = deduplicateRules(view.Spec.Rules)
+	view.Spec.BlockedSkills = removeDuplicates(view.Spec.BlockedSkills)
 
 	v, err := createViewModel(ctx, view, ViewPurposeUpdate)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := db.DB(ctx).UpdateView(ctx, v); err != nil {
 		if errors.Is(err, dberror.ErrNotFound) {
-			return nil, ErrViewNotFound.New("view not found: " + view.Metadata.Name)
+			return nil, nil, ErrViewNotFound.New("view not found: " + view.Metadata.Name)
 		}
 		log.Ctx(ctx).Error().Err(err).Msg("failed to update view")
-		return nil, ErrViewError.New("failed to update view: " + err.Error())
+		return nil, nil, ErrViewError.New("failed to update view: " + err.Error())
+	}
+
+	return v, report, nil
+}
+
+// CloneView creates a new view named newName with the scope, rules and blocked skills of an
+// existing view. If description is empty, the source view's description is used.
+// The source view must not have expired, must pass the bounds checks of CreateView, and its
+// scope and rules must be within the view authorized in ctx, so a clone can never grant more
+// than the caller already holds.
+func CloneView(ctx context.Context, catalogID uuid.UUID, sourceName, newName, description string) (*models.View, apperrors.Error) {
+	if catalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+	if err := schemavalidator.V().Var(newName, "required,resourceNameValidator"); err != nil {
+		return nil, ErrInvalidView.New("invalid view name: " + newName)
+	}
+
+	source, err := db.DB(ctx).GetViewByLabel(ctx, sourceName, catalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrViewNotFound.New("view not found: " + sourceName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view")
+		return nil, ErrUnableToLoadObject.Msg("unable to load view")
+	}
+
+	viewDef, err := unmarshalViewDefinition(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewDef.IsExpired(time.Now()) {
+		return nil, ErrViewExpired.Msg("view has expired: " + sourceName)
+	}
+	if validationErrors := validateViewBounds(viewDef.Scope, viewDef.Rules); len(validationErrors) > 0 {
+		return nil, ErrInvalidSchema.Err(validationErrors)
+	}
+
+	callerView := GetViewDefinition(ctx)
+	if err := ValidateDerivedView(ctx, callerView, viewDef); err != nil || !callerView.Scope.Covers(viewDef.Scope) {
+		return nil, ErrUnauthorizedToCreateView.New("cloned view exceeds the caller's authorized view")
+	}
+
+	userContext := catcommon.GetUserContext(ctx)
+	if userContext == nil || userContext.UserID == "" {
+		return nil, dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+
+	if description == "" {
+		description = source.Description
+	}
+
+	clone := &models.View{
+		Label:       newName,
+		Description: description,
+		Info:        nil,
+		Rules:       source.Rules,
+		CatalogID:   catalogID,
+		CreatedBy:   "user/" + userContext.UserID,
+	}
+
+	if err := db.DB(ctx).CreateView(ctx, clone); err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return nil, ErrAlreadyExists.New("view already exists: " + newName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to create view")
+		return nil, ErrViewError.New("failed to create view: " + err.Error())
 	}
 
-	return v, nil
+	return clone, nil
+}
+
+// RevokeViewTokens invalidates every outstanding access token bound to the named view by
+// bumping the view's token epoch. Tokens minted afterwards carry the new epoch and remain valid.
+func RevokeViewTokens(ctx context.Context, catalogID uuid.UUID, viewName string) (int64, apperrors.Error) {
+	if catalogID == uuid.Nil {
+		return 0, ErrInvalidCatalog
+	}
+
+	view, err := db.DB(ctx).GetViewByLabel(ctx, viewName, catalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return 0, ErrViewNotFound.New("view not found: " + viewName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view")
+		return 0, ErrUnableToLoadObject.Msg("unable to load view")
+	}
+
+	epoch, err := db.DB(ctx).BumpViewTokenEpoch(ctx, view.ViewID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to revoke view tokens")
+		return 0, ErrViewError.New("failed to revoke view tokens: " + err.Error())
+	}
+
+	log.Ctx(ctx).Info().Str("view", viewName).Int64("token_epoch", epoch).Msg("revoked view tokens")
+	return epoch, nil
+}
+
+// EffectivePermissionsForView returns the actions the named view allows and denies on the resource.
+func EffectivePermissionsForView(ctx context.Context, catalogID uuid.UUID, viewName, resource string) (*EffectivePermissions, apperrors.Error) {
+	if catalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+
+	view, err := db.DB(ctx).GetViewByLabel(ctx, viewName, catalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrViewNotFound.New("view not found: " + viewName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view")
+		return nil, ErrUnableToLoadObject.Msg("unable to load view")
+	}
+
+	vd, err := unmarshalViewDefinition(view)
+	if err != nil {
+		return nil, err
+	}
+	return EffectivePermissionsOnResource(vd, resource)
 }
 
 type viewKind struct {
-	reqCtx interfaces.RequestContext
-	view   *models.View
+	reqCtx     interfaces.RequestContext
+	view       *models.View
+	duplicates *DuplicateReport // set by Create and Update when the warnOnDuplicates query parameter is true
+}
+
+// Result returns the duplicates removed from the rules of the created or updated view when the
+// warnOnDuplicates query parameter is true, and nil otherwise.
+func (v *viewKind) Result() any {
+	if v.duplicates == nil {
+		return nil
+	}
+	return v.duplicates
+}
+
+// warnOnDuplicates reports whether the request asks for the duplicates removed from view rules.
+func (v *viewKind) warnOnDuplicates() bool {
+	return v.reqCtx.QueryParams.Get("warnOnDuplicates") == "true"
 }
 
 // Name returns the name of the view resource.
@@ -275,10 +663,13 @@ func (v *viewKind) Create(ctx context.Context, resourceJSON []byte) (string, app
 	m.IDS.CatalogID = v.reqCtx.CatalogID
 	m.IDS.VariantID = v.reqCtx.VariantID
 
-	view, err := CreateView(ctx, resourceJSON, m)
+	view, duplicates, err := createView(ctx, resourceJSON, m)
 	if err != nil {
 		return "", err
 	}
+	if v.warnOnDuplicates() {
+		v.duplicates = duplicates
+	}
 	v.view = view
 	return v.Location(), nil
 }
@@ -318,6 +709,11 @@ func (v *viewKind) Get(ctx context.Context) ([]byte, apperrors.Error) {
 	}
 
 	viewSchema.Spec.Rules = viewDef.Rules
+	viewSchema.Spec.BlockedSkills = viewDef.BlockedSkills
+	viewSchema.Spec.ExpiresAt = viewDef.ExpiresAt
+	viewSchema.Spec.AuditMode = viewDef.AuditMode
+	viewSchema.Spec.TokenTTL = viewDef.TokenTTL
+	viewSchema.Spec.Namespaces = viewDef.Scope.Namespaces
 
 	if viewDef.Scope.Catalog != v.reqCtx.Catalog {
 		return nil, ErrInvalidView.New("view catalog does not match request catalog")
@@ -371,10 +767,13 @@ func (v *viewKind) Update(ctx context.Context, resourceJSON []byte) apperrors.Er
 	m.IDS.CatalogID = v.reqCtx.CatalogID
 	m.IDS.VariantID = v.reqCtx.VariantID
 
-	_, err := UpdateView(ctx, resourceJSON, m)
+	_, duplicates, err := updateView(ctx, resourceJSON, m)
 	if err != nil {
 		return err
 	}
+	if v.warnOnDuplicates() {
+		v.duplicates = duplicates
+	}
 
 	return nil
 }
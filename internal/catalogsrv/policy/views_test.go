@@ -4,13 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
@@ -109,6 +112,94 @@ func TestCreateView(t *testing.T) {
 		}`,
 			expected: nil,
 		},
+		{
+			name: "valid view with blocked skills",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "blocked-skills-view",
+		        "catalog": "validcatalog",
+		        "description": "View with blocked skills"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.skillset.use"],
+		            "targets": ["res://skillsets/*"]
+		        }],
+		        "blockedSkills": ["restart_deployment", "delete-pods"]
+		    }
+		}`,
+			expected: nil,
+		},
+		{
+			name: "invalid blocked skill name",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "invalid-blocked-skills-view",
+		        "catalog": "validcatalog",
+		        "description": "View with an invalid blocked skill"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.skillset.use"],
+		            "targets": ["res://skillsets/*"]
+		        }],
+		        "blockedSkills": ["Restart Deployment"]
+		    }
+		}`,
+			expected: ErrInvalidSchema,
+		},
+		{
+			name: "valid view with future expiry",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "expiring-view",
+		        "catalog": "validcatalog",
+		        "description": "Temporary access grant"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.catalog.list"],
+		            "targets": ["res://variants/my-variant"]
+		        }],
+		        "expiresAt": "2999-01-01T00:00:00Z"
+		    }
+		}`,
+			expected: nil,
+		},
+		{
+			name: "expiry in the past",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "expired-view",
+		        "catalog": "validcatalog",
+		        "description": "Already expired"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.catalog.list"],
+		            "targets": ["res://variants/my-variant"]
+		        }],
+		        "expiresAt": "2020-01-01T00:00:00Z"
+		    }
+		}`,
+			expected: ErrInvalidSchema,
+		},
 		{
 			name: "empty rules",
 			jsonData: `
@@ -385,6 +476,71 @@ func TestCreateView(t *testing.T) {
 		}`,
 			expected: nil,
 		},
+		{
+			name: "namespaces without a variant",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "namespaces-no-variant",
+		        "catalog": "validcatalog"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.skillset.use"],
+		            "targets": ["res://skillsets/*"]
+		        }],
+		        "namespaces": ["team-a"]
+		    }
+		}`,
+			expected: ErrInvalidSchema,
+		},
+		{
+			name: "namespaces that do not exist in the variant",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "namespaces-missing",
+		        "catalog": "validcatalog",
+		        "variant": "default"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.skillset.use"],
+		            "targets": ["res://skillsets/*"]
+		        }],
+		        "namespaces": ["missing-namespace"]
+		    }
+		}`,
+			expected: ErrNamespaceNotFound,
+		},
+		{
+			name: "default namespace is always available",
+			jsonData: `
+		{
+		    "apiVersion": "0.1.0-alpha.1",
+		    "kind": "View",
+		    "metadata": {
+		        "name": "namespaces-default",
+		        "catalog": "validcatalog",
+		        "variant": "default"
+		    },
+		    "spec": {
+		        "rules": [{
+		            "intent": "Allow",
+		            "actions": ["system.skillset.use"],
+		            "targets": ["res://skillsets/*"]
+		        }],
+		        "namespaces": ["--root--"]
+		    }
+		}`,
+			expected: nil,
+		},
 	}
 
 	// Initialize context with logger and database connection
@@ -560,6 +716,49 @@ func TestUpdateView(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrViewNotFound))
 
+	// Test updating a view past the bounds of its catalog
+	outOfBoundsView := `{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "View",
+		"metadata": {
+			"name": "test-view",
+			"catalog": "test-catalog",
+			"description": "Should fail"
+		},
+		"spec": {
+			"rules": [{
+				"intent": "Allow",
+				"actions": ["system.catalog.list"],
+				"targets": ["res://catalogs/other-catalog"]
+			}]
+		}
+	}`
+
+	_, err = UpdateView(ctx, []byte(outOfBoundsView), metadata)
+	assert.ErrorIs(t, err, ErrInvalidSchema)
+
+	// Test restricting namespaces of a view not scoped to a variant
+	namespacesWithoutVariantView := `{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "View",
+		"metadata": {
+			"name": "test-view",
+			"catalog": "test-catalog",
+			"description": "Should fail"
+		},
+		"spec": {
+			"namespaces": ["team-a"],
+			"rules": [{
+				"intent": "Allow",
+				"actions": ["system.catalog.list"],
+				"targets": []
+			}]
+		}
+	}`
+
+	_, err = UpdateView(ctx, []byte(namespacesWithoutVariantView), metadata)
+	assert.ErrorIs(t, err, ErrInvalidSchema)
+
 	// Test updating with invalid catalog
 	invalidCatalogView := `{
 		"apiVersion": "0.1.0-alpha.1",
@@ -661,6 +860,112 @@ func TestUpdateView(t *testing.T) {
 	assert.ElementsMatch(t, expectedTargets, viewDef2.Rules[0].Targets)
 }
 
+func TestViewWarnOnDuplicates(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	catalogID := uuid.New()
+	err := db.DB(ctx).CreateCatalog(ctx, &models.Catalog{
+		CatalogID:   catalogID,
+		Name:        "test-catalog",
+		Description: "Test catalog",
+		ProjectID:   projectID,
+		Info:        pgtype.JSONB{Status: pgtype.Null},
+	})
+	require.NoError(t, err)
+
+	viewWithDuplicates := func(name string) []byte {
+		return []byte(`{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "` + name + `",
+				"catalog": "test-catalog"
+			},
+			"spec": {
+				"rules": [
+					{
+						"intent": "Allow",
+						"actions": ["system.catalog.list", "system.variant.list", "system.catalog.list"],
+						"targets": ["res://variants/dev", "res://variants/dev"]
+					}
+				]
+			}
+		}`)
+	}
+	handler := func(name string, query url.Values) interfaces.KindHandler {
+		h, err := NewViewKindHandler(ctx, interfaces.RequestContext{
+			Catalog:     "test-catalog",
+			CatalogID:   catalogID,
+			ObjectName:  name,
+			QueryParams: query,
+		})
+		require.NoError(t, err)
+		return h
+	}
+	storedRule := func(name string) Rule {
+		view, err := db.DB(ctx).GetViewByLabel(ctx, name, catalogID)
+		require.NoError(t, err)
+		var vd ViewDefinition
+		require.NoError(t, json.Unmarshal(view.Rules, &vd))
+		require.Len(t, vd.Rules, 1)
+		return vd.Rules[0]
+	}
+
+	t.Run("duplicates are reported when requested", func(t *testing.T) {
+		h := handler("warn-view", url.Values{"warnOnDuplicates": []string{"true"}})
+		_, err := h.Create(ctx, viewWithDuplicates("warn-view"))
+		require.NoError(t, err)
+
+		report, ok := h.(interfaces.ResultReporter).Result().(*DuplicateReport)
+		require.True(t, ok)
+		assert.True(t, report.HasDuplicates())
+		assert.Equal(t, DuplicateCounts{Actions: 3, Targets: 2}, report.Original)
+		assert.Equal(t, DuplicateCounts{Actions: 2, Targets: 1}, report.Deduplicated)
+		assert.Equal(t, []RuleDuplicates{{
+			Rule:    0,
+			Actions: []Action{ActionCatalogList},
+			Targets: []TargetResource{"res://variants/dev"},
+		}}, report.Rules)
+
+		// the view is deduplicated all the same
+		rule := storedRule("warn-view")
+		assert.Len(t, rule.Actions, 2)
+		assert.Len(t, rule.Targets, 1)
+
+		h = handler("warn-view", url.Values{"warnOnDuplicates": []string{"true"}})
+		require.NoError(t, h.Update(ctx, viewWithDuplicates("warn-view")))
+		report, ok = h.(interfaces.ResultReporter).Result().(*DuplicateReport)
+		require.True(t, ok)
+		assert.True(t, report.HasDuplicates())
+	})
+
+	t.Run("duplicates are removed silently by default", func(t *testing.T) {
+		h := handler("silent-view", nil)
+		_, err := h.Create(ctx, viewWithDuplicates("silent-view"))
+		require.NoError(t, err)
+		assert.Nil(t, h.(interfaces.ResultReporter).Result())
+
+		rule := storedRule("silent-view")
+		assert.Len(t, rule.Actions, 2)
+		assert.Len(t, rule.Targets, 1)
+
+		require.NoError(t, h.Update(ctx, viewWithDuplicates("silent-view")))
+		assert.Nil(t, h.(interfaces.ResultReporter).Result())
+	})
+}
+
 func TestDeleteView(t *testing.T) {
 	ctx := newDb()
 	defer db.DB(ctx).Close(ctx)
@@ -973,3 +1278,328 @@ func TestGetView(t *testing.T) {
 		assert.True(t, errors.Is(err, ErrViewNotFound))
 	})
 }
+
+func TestCloneView(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TCLONE")
+	projectID := catcommon.ProjectId("PCLONE")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	catalogID := uuid.New()
+	err := db.DB(ctx).CreateCatalog(ctx, &models.Catalog{
+		CatalogID:   catalogID,
+		Name:        "clone-catalog",
+		Description: "Test catalog",
+		ProjectID:   projectID,
+		Info:        pgtype.JSONB{Status: pgtype.Null},
+	})
+	require.NoError(t, err)
+
+	// the caller holds catalog admin, so any view in the catalog can be cloned
+	ctx = WithViewDefinition(ctx, &ViewDefinition{
+		Scope: Scope{Catalog: "clone-catalog"},
+		Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogAdmin}, Targets: []TargetResource{}}},
+	})
+
+	baseView := `{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "View",
+		"metadata": {
+			"name": "base-view",
+			"catalog": "clone-catalog",
+			"description": "Base view"
+		},
+		"spec": {
+			"rules": [{
+				"intent": "Allow",
+				"actions": ["system.catalog.list", "system.variant.list"],
+				"targets": ["res://variants/my-variant"]
+			}],
+			"blockedSkills": ["restart_deployment"]
+		}
+	}`
+	_, err = CreateView(ctx, []byte(baseView), &interfaces.Metadata{Catalog: "clone-catalog"})
+	require.NoError(t, err)
+
+	t.Run("successful clone", func(t *testing.T) {
+		clone, err := CloneView(ctx, catalogID, "base-view", "team-a-view", "Team A view")
+		require.NoError(t, err)
+		assert.Equal(t, "team-a-view", clone.Label)
+		assert.Equal(t, "Team A view", clone.Description)
+
+		source, err := db.DB(ctx).GetViewByLabel(ctx, "base-view", catalogID)
+		require.NoError(t, err)
+		cloned, err := db.DB(ctx).GetViewByLabel(ctx, "team-a-view", catalogID)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(source.Rules), string(cloned.Rules))
+
+		// description defaults to the source view's
+		clone, err = CloneView(ctx, catalogID, "base-view", "team-b-view", "")
+		require.NoError(t, err)
+		assert.Equal(t, "Base view", clone.Description)
+	})
+
+	t.Run("name collision", func(t *testing.T) {
+		_, err := CloneView(ctx, catalogID, "base-view", "base-view", "")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrAlreadyExists)
+	})
+
+	t.Run("source not found", func(t *testing.T) {
+		_, err := CloneView(ctx, catalogID, "missing-view", "new-view", "")
+		assert.ErrorIs(t, err, ErrViewNotFound)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		_, err := CloneView(ctx, catalogID, "base-view", "Invalid Name", "")
+		assert.ErrorIs(t, err, ErrInvalidView)
+	})
+
+	t.Run("clone exceeding caller's view", func(t *testing.T) {
+		limitedCtx := WithViewDefinition(ctx, &ViewDefinition{
+			Scope: Scope{Catalog: "clone-catalog"},
+			Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogList}, Targets: []TargetResource{"res://variants/my-variant"}}},
+		})
+		_, err := CloneView(limitedCtx, catalogID, "base-view", "escalated-view", "")
+		assert.ErrorIs(t, err, ErrUnauthorizedToCreateView)
+	})
+
+	t.Run("clone outside the caller's namespaces", func(t *testing.T) {
+		namespacedCtx := WithViewDefinition(ctx, &ViewDefinition{
+			Scope: Scope{Catalog: "clone-catalog", Variant: "my-variant", Namespaces: []string{"team-a"}},
+			Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogAdmin}, Targets: []TargetResource{}}},
+		})
+		_, err := CloneView(namespacedCtx, catalogID, "base-view", "escaped-view", "")
+		assert.ErrorIs(t, err, ErrUnauthorizedToCreateView)
+	})
+
+	t.Run("expired source", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+		rules, err := json.Marshal(ViewDefinition{
+			Scope:     Scope{Catalog: "clone-catalog"},
+			Rules:     Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogList}, Targets: []TargetResource{}}},
+			ExpiresAt: &expiresAt,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.DB(ctx).CreateView(ctx, &models.View{
+			Label:     "expired-view",
+			Rules:     rules,
+			CatalogID: catalogID,
+			CreatedBy: "user/test",
+		}))
+		_, err = CloneView(ctx, catalogID, "expired-view", "revived-view", "")
+		assert.ErrorIs(t, err, ErrViewExpired)
+	})
+}
+
+func TestScopeCovers(t *testing.T) {
+	catalog := Scope{Catalog: "c"}
+	variant := Scope{Catalog: "c", Variant: "v"}
+	namespaced := Scope{Catalog: "c", Variant: "v", Namespaces: []string{"a", "b"}}
+
+	assert.True(t, catalog.Covers(variant))
+	assert.True(t, catalog.Covers(namespaced))
+	assert.False(t, catalog.Covers(Scope{Catalog: "other"}))
+	assert.False(t, variant.Covers(catalog))
+	assert.False(t, variant.Covers(Scope{Catalog: "c", Variant: "w"}))
+	assert.True(t, namespaced.Covers(Scope{Catalog: "c", Variant: "v", Namespaces: []string{"a"}}))
+	assert.True(t, namespaced.Covers(Scope{Catalog: "c", Variant: "v", Namespace: "b"}))
+	assert.False(t, namespaced.Covers(variant))
+	assert.False(t, namespaced.Covers(Scope{Catalog: "c", Variant: "v", Namespaces: []string{"a", "c"}}))
+	assert.False(t, Scope{Catalog: "c", Variant: "v", Namespace: "a"}.Covers(variant))
+}
+
+func TestValidateView(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TVALIDATE")
+	projectID := catcommon.ProjectId("PVALIDATE")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	err := db.DB(ctx).CreateCatalog(ctx, &models.Catalog{
+		CatalogID:   uuid.New(),
+		Name:        "validate-catalog",
+		Description: "Test catalog",
+		ProjectID:   projectID,
+		Info:        pgtype.JSONB{Status: pgtype.Null},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		rules      string
+		wantFields []string
+	}{
+		{
+			name: "valid view",
+			rules: `[{
+				"intent": "Allow",
+				"actions": ["system.catalog.list", "system.variant.list"],
+				"targets": ["res://variants/my-variant"]
+			}]`,
+		},
+		{
+			name: "invalid action",
+			rules: `[{
+				"intent": "Allow",
+				"actions": ["system.catalog.list", "system.Invalid"],
+				"targets": ["res://variants/my-variant"]
+			}]`,
+			wantFields: []string{"spec.rules[0].actions[1]"},
+		},
+		{
+			name: "invalid targets",
+			rules: `[{
+				"intent": "Allow",
+				"actions": ["system.catalog.list"],
+				"targets": ["res://variants/my-variant"]
+			},
+			{
+				"intent": "Allow",
+				"actions": ["system.catalog.list"],
+				"targets": ["res://variants/my-variant", "variants/no-prefix", "res://unknown/kind"]
+			}]`,
+			wantFields: []string{"spec.rules[1].targets[1]", "spec.rules[1].targets[2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := fmt.Sprintf(`{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "View",
+				"metadata": {
+					"name": "validate-view",
+					"catalog": "validate-catalog"
+				},
+				"spec": {
+					"rules": %s
+				}
+			}`, tt.rules)
+
+			validationErrors, err := ValidateView(ctx, []byte(view), &interfaces.Metadata{})
+			require.NoError(t, err)
+
+			var fields []string
+			for _, ve := range validationErrors {
+				fields = append(fields, ve.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+
+			// nothing is persisted
+			_, getErr := db.DB(ctx).GetViewByLabel(ctx, "validate-view", uuid.Nil)
+			assert.Error(t, getErr)
+		})
+	}
+
+	t.Run("target outside the catalog", func(t *testing.T) {
+		view := &viewSchema{
+			ApiVersion: "0.1.0-alpha.1",
+			Kind:       catcommon.ViewKind,
+			Metadata:   interfaces.Metadata{Name: "escape-view", Catalog: "validate-catalog"},
+			Spec: viewSpec{
+				Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogList}, Targets: []TargetResource{"res://variants/../../other-catalog"}}},
+			},
+		}
+		validationErrors := view.validateForCreate()
+		require.NotEmpty(t, validationErrors)
+		assert.Equal(t, "spec.rules[0].targets[0]", validationErrors[len(validationErrors)-1].Field)
+	})
+}
+
+func TestViewTokenTTLValidation(t *testing.T) {
+	config.TestInit()
+	config.Config().Auth.MaxTokenAge = "24h"
+
+	tests := []struct {
+		tokenTTL string
+		wantErr  string
+	}{
+		{tokenTTL: ""},
+		{tokenTTL: "15m"},
+		{tokenTTL: "24h"},
+		{tokenTTL: "1d"},
+		{tokenTTL: "2d", wantErr: "must not exceed the maximum token age of 24h"},
+		{tokenTTL: "0m", wantErr: "tokenTTL must be positive"},
+		{tokenTTL: "-5m", wantErr: "tokenTTL must be positive"},
+		{tokenTTL: "soon", wantErr: "invalid duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tokenTTL, func(t *testing.T) {
+			view := &viewSchema{
+				ApiVersion: "0.1.0-alpha.1",
+				Kind:       catcommon.ViewKind,
+				Metadata:   interfaces.Metadata{Name: "ttl-view", Catalog: "validcatalog"},
+				Spec: viewSpec{
+					Rules:    Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogList}, Targets: []TargetResource{"res://variants/my-variant"}}},
+					TokenTTL: tt.tokenTTL,
+				},
+			}
+			validationErrors := view.Validate()
+			if tt.wantErr == "" {
+				assert.Empty(t, validationErrors)
+				return
+			}
+			require.Len(t, validationErrors, 1)
+			assert.Equal(t, "spec.tokenTTL", validationErrors[0].Field)
+			assert.Contains(t, validationErrors[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseViewRuleReason(t *testing.T) {
+	view := func(reason string) []byte {
+		return []byte(`{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {"name": "frozen-view", "catalog": "test-catalog"},
+			"spec": {"rules": [
+				{"intent": "Allow", "actions": ["system.catalog.list"], "targets": []},
+				{"intent": "Deny", "actions": ["system.catalog.list"], "targets": [], "reason": ` + reason + `}
+			]}
+		}`)
+	}
+
+	v, err := parseView(view(`"catalog listing is frozen"`), &interfaces.Metadata{})
+	require.NoError(t, err)
+	assert.Empty(t, v.Validate())
+	assert.Equal(t, "catalog listing is frozen", v.Spec.Rules[1].Reason)
+	assert.Empty(t, v.Spec.Rules[0].Reason)
+
+	_, err = parseView(view(`42`), &interfaces.Metadata{})
+	require.ErrorIs(t, err, ErrInvalidView)
+	assert.Contains(t, err.Error(), "spec.rules[1].reason must be a string")
+}
+
+func TestReservedViewName(t *testing.T) {
+	view := &viewSchema{
+		ApiVersion: "0.1.0-alpha.1",
+		Kind:       catcommon.ViewKind,
+		Metadata:   interfaces.Metadata{Name: "adoptable", Catalog: "validcatalog"},
+		Spec: viewSpec{
+			Rules: Rules{{Intent: IntentAllow, Actions: []Action{ActionCatalogList}, Targets: []TargetResource{"res://variants/my-variant"}}},
+		},
+	}
+	validationErrors := view.Validate()
+	require.Len(t, validationErrors, 1)
+	assert.Equal(t, "metadata.name", validationErrors[0].Field)
+	assert.Contains(t, validationErrors[0].Error(), "adoptable is reserved")
+	assert.False(t, IsReservedViewName("adoptable-views"))
+}
@@ -45,18 +45,163 @@ func deduplicateRules(rules Rules) Rules {
 			Intent:  rule.Intent,
 			Actions: removeDuplicates(rule.Actions),
 			Targets: removeDuplicates(rule.Targets),
+			Reason:  rule.Reason,
 		}
 	}
 	return result
 }
 
-// canonicalizeResourcePath transforms a resource string based on the provided scope.
-// It handles the conversion of resource paths and ensures proper formatting
-// of catalog, variant, workspace, and namespace components.
+// DuplicateReport compares the rules and blocked skills of a view as submitted with those stored
+// after duplicates were removed.
+type DuplicateReport struct {
+	Original      DuplicateCounts  `json:"original"`
+	Deduplicated  DuplicateCounts  `json:"deduplicated"`
+	Rules         []RuleDuplicates `json:"rules,omitempty"`
+	BlockedSkills []string         `json:"blockedSkills,omitempty"` // blocked skills listed more than once
+}
+
+// DuplicateCounts counts the actions and targets of all rules and the blocked skills of a view.
+type DuplicateCounts struct {
+	Actions       int `json:"actions"`
+	Targets       int `json:"targets"`
+	BlockedSkills int `json:"blockedSkills"`
+}
+
+// RuleDuplicates lists the actions and targets repeated within a rule, identified by its index.
+type RuleDuplicates struct {
+	Rule    int              `json:"rule"`
+	Actions []Action         `json:"actions,omitempty"`
+	Targets []TargetResource `json:"targets,omitempty"`
+}
+
+// HasDuplicates reports whether any duplicates were removed.
+func (r *DuplicateReport) HasDuplicates() bool {
+	return r.Original != r.Deduplicated
+}
+
+// reportDuplicates returns the duplicates deduplicateRules and removeDuplicates remove from the
+// rules and blocked skills of a view.
+func reportDuplicates(rules Rules, blockedSkills []string) *DuplicateReport {
+	report := &DuplicateReport{
+		BlockedSkills: repeated(blockedSkills),
+	}
+	report.Original.BlockedSkills = len(blockedSkills)
+	report.Deduplicated.BlockedSkills = len(removeDuplicates(blockedSkills))
+	for i, rule := range rules {
+		report.Original.Actions += len(rule.Actions)
+		report.Original.Targets += len(rule.Targets)
+		report.Deduplicated.Actions += len(removeDuplicates(rule.Actions))
+		report.Deduplicated.Targets += len(removeDuplicates(rule.Targets))
+		actions, targets := repeated(rule.Actions), repeated(rule.Targets)
+		if len(actions) > 0 || len(targets) > 0 {
+			report.Rules = append(report.Rules, RuleDuplicates{Rule: i, Actions: actions, Targets: targets})
+		}
+	}
+	return report
+}
+
+// repeated returns the elements that occur more than once in slice, in order of their first repeat.
+func repeated[T comparable](slice []T) []T {
+	var result []T
+	seen := make(map[T]int, len(slice))
+	for _, v := range slice {
+		seen[v]++
+		if seen[v] == 2 {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// CanonicalizeResource returns the canonical form of a resource within scope, which is the
+// absolute form view rules are evaluated against, such as
+// res://catalogs/my-catalog/variants/dev/namespaces/team-a/resources/config.
+//
+// The resource may be relative to the scope, as in /resources/config or res://resources/config,
+// or absolute, as in res://catalogs/my-catalog/variants/dev/resources/config. A relative and an
+// absolute resource naming the same object canonicalize identically. Leading, trailing and
+// repeated slashes are ignored, and resources/definition/<path> names the same resource as
+// resources/<path>. Views are resolved in the scope's catalog regardless of its variant and
+// namespace.
+//
+// Returns ErrResourceOutsideScope if the resource names a catalog, variant or namespace other
+// than the scope's, or escapes the scope with "..".
+func CanonicalizeResource(scope Scope, resource TargetResource) (TargetResource, error) {
+	s := strings.TrimPrefix(string(resource), "res://")
+	if strings.Contains(s, "://") {
+		return "", ErrInvalidResourcePath.Msg("unsupported scheme in resource " + string(resource))
+	}
+	s = strings.Trim(s, "/")
+
+	var canonicalized string
+	if getResourceKindFromPath(s) == catcommon.KindNameCatalogs {
+		canonicalized = path.Clean(s)
+	} else {
+		canonicalized = string(scopedResourcePath(scope, s))
+	}
+	canonicalized = strings.TrimPrefix(canonicalized, "res://")
+	if canonicalized == "." || canonicalized == "/" {
+		canonicalized = ""
+	}
+
+	segments := strings.Split(canonicalized, "/")
+	if !scopeContains(scope, segments) {
+		return "", ErrResourceOutsideScope.Msg(string(resource) + " is outside the scope")
+	}
+	segments = removeDefinitionSegment(segments)
+
+	return TargetResource("res://" + strings.Join(segments, "/")), nil
+}
+
+// scopeContains reports whether the segments of a canonicalized resource lie within scope.
+func scopeContains(scope Scope, segments []string) bool {
+	if scope.Catalog == "" {
+		return true
+	}
+	if len(segments) < 2 || segments[0] != catcommon.KindNameCatalogs || segments[1] != scope.Catalog {
+		return false
+	}
+	if scope.Variant != "" && len(segments) > 2 && segments[2] == catcommon.KindNameVariants {
+		if len(segments) < 4 || segments[3] != scope.Variant {
+			return false
+		}
+	}
+	if scope.Namespace != "" && len(segments) > 4 && segments[4] == catcommon.KindNameNamespaces {
+		if len(segments) < 6 || segments[5] != scope.Namespace {
+			return false
+		}
+	}
+	return true
+}
+
+// removeDefinitionSegment rewrites the segments of .../resources/definition/<path> to
+// .../resources/<path>, which name the same resource.
+func removeDefinitionSegment(segments []string) []string {
+	i := 0
+	for _, kind := range []string{catcommon.KindNameCatalogs, catcommon.KindNameVariants, catcommon.KindNameNamespaces} {
+		if i+2 < len(segments) && segments[i] == kind {
+			i += 2
+		}
+	}
+	if i+1 < len(segments) && segments[i] == catcommon.KindNameResources && segments[i+1] == "definition" {
+		return append(segments[:i+1:i+1], segments[i+2:]...)
+	}
+	return segments
+}
+
+// canonicalizeResourcePath canonicalizes a view rule target to the scope of the view. Targets
+// outside the scope are nested under the scope so that they match no resource.
 func canonicalizeResourcePath(scope Scope, resource TargetResource) TargetResource {
-	s := string(resource)
-	s = strings.TrimPrefix(s, "res://")
-	s = strings.TrimPrefix(s, "/") // just in case, the res:// prefix was missing
+	if canonicalized, err := CanonicalizeResource(scope, resource); err == nil {
+		return canonicalized
+	}
+	return scopedResourcePath(scope, strings.TrimPrefix(string(resource), "res://"))
+}
+
+// scopedResourcePath qualifies a resource path relative to the scope with the scope's catalog,
+// variant and namespace. Catalog level objects are qualified with the catalog only.
+func scopedResourcePath(scope Scope, resource string) TargetResource {
+	s := strings.TrimPrefix(resource, "/")
 	catalogLevel := catcommon.IsCatalogLevelKind(getResourceKindFromPath(s))
 	metadataPath := strings.Builder{}
 	if scope.Catalog != "" {
@@ -80,6 +225,43 @@ func canonicalizeResourcePath(scope Scope, resource TargetResource) TargetResour
 	return TargetResource(canonicalized)
 }
 
+// targetNamespace returns the namespace of a canonicalized resource. Namespaces, resources and
+// skillsets outside a named namespace belong to the default namespace. Returns false for
+// objects that do not belong to a namespace, such as catalogs, variants and views.
+func targetNamespace(target TargetResource) (string, bool) {
+	segments := strings.Split(strings.TrimPrefix(string(target), "res://"), "/")
+	if len(segments) < 5 || segments[0] != catcommon.KindNameCatalogs || segments[2] != catcommon.KindNameVariants {
+		return "", false
+	}
+	switch segments[4] {
+	case catcommon.KindNameNamespaces:
+		if len(segments) > 5 && segments[5] != "" && segments[5] != "*" {
+			return segments[5], true
+		}
+		return "", false
+	case catcommon.KindNameResources, catcommon.KindNameSkillsets:
+		return catcommon.DefaultNamespace, true
+	}
+	return "", false
+}
+
+// NamespacedResourcePath qualifies a resource or skillset path with namespace, so that it is
+// evaluated against a view whose scope is not bound to a namespace. The path is returned
+// unchanged for the default namespace, for views scoped to a namespace and for paths that
+// already name a namespace.
+func NamespacedResourcePath(scope Scope, namespace string, resourcePath string) string {
+	if namespace == "" || namespace == catcommon.DefaultNamespace || scope.Namespace != "" {
+		return resourcePath
+	}
+	p := "/" + strings.TrimPrefix(strings.TrimPrefix(resourcePath, "res://"), "/")
+	kind := getResourceKindFromPath(p)
+	if kind != catcommon.KindNameResources && kind != catcommon.KindNameSkillsets {
+		return resourcePath
+	}
+	p = string(normalizeResourcePath(kind, TargetResource(p)))
+	return "/" + catcommon.KindNameNamespaces + "/" + namespace + p
+}
+
 // canonicalizeViewDefinition canonicalizes all targets in the view definition to its scope
 func canonicalizeViewDefinition(v *ViewDefinition) *ViewDefinition {
 	if v == nil {
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 )
 
 func TestRemoveDuplicates(t *testing.T) {
@@ -130,6 +132,32 @@ func TestDeduplicateRules(t *testing.T) {
 	}
 }
 
+func TestReportDuplicates(t *testing.T) {
+	rules := Rules{
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionCatalogList},
+			Targets: []TargetResource{"res://catalogs/my-catalog"},
+		},
+		{
+			Intent:  IntentDeny,
+			Actions: []Action{ActionCatalogList, ActionVariantList, ActionCatalogList, ActionCatalogList},
+			Targets: []TargetResource{"res://catalogs/other-catalog"},
+		},
+	}
+	report := reportDuplicates(rules, []string{"restart", "delete", "restart"})
+	assert.True(t, report.HasDuplicates())
+	assert.Equal(t, DuplicateCounts{Actions: 5, Targets: 2, BlockedSkills: 3}, report.Original)
+	assert.Equal(t, DuplicateCounts{Actions: 3, Targets: 2, BlockedSkills: 2}, report.Deduplicated)
+	assert.Equal(t, []RuleDuplicates{{Rule: 1, Actions: []Action{ActionCatalogList}}}, report.Rules)
+	assert.Equal(t, []string{"restart"}, report.BlockedSkills)
+
+	report = reportDuplicates(deduplicateRules(rules), []string{"restart"})
+	assert.False(t, report.HasDuplicates())
+	assert.Empty(t, report.Rules)
+	assert.Empty(t, report.BlockedSkills)
+}
+
 func TestCanonicalizeResourcePath(t *testing.T) {
 	// Note: Resource paths should be defined relative to the scope.
 	// For example, if the scope is catalog/my-catalog/variant/my-variant,
@@ -346,6 +374,125 @@ func TestCanonicalizeResourcePath(t *testing.T) {
 	}
 }
 
+func TestCanonicalizeResource(t *testing.T) {
+	variantScope := Scope{Catalog: "my-catalog", Variant: "dev"}
+	namespaceScope := Scope{Catalog: "my-catalog", Variant: "dev", Namespace: "team-a"}
+
+	tests := []struct {
+		name      string
+		scope     Scope
+		resources []TargetResource // all canonicalize to want
+		want      TargetResource
+	}{
+		{
+			name:  "resource in a variant",
+			scope: variantScope,
+			resources: []TargetResource{
+				"res://resources/config",
+				"/resources/config",
+				"resources/config/",
+				"res:///resources//config",
+				"res://resources/definition/config",
+				"res://catalogs/my-catalog/variants/dev/resources/config",
+				"/catalogs/my-catalog/variants/dev/resources/definition/config",
+			},
+			want: "res://catalogs/my-catalog/variants/dev/resources/config",
+		},
+		{
+			name:  "resource in a namespace",
+			scope: namespaceScope,
+			resources: []TargetResource{
+				"res://resources/config",
+				"/resources/config",
+				"res://catalogs/my-catalog/variants/dev/namespaces/team-a/resources/config",
+				"res://catalogs/my-catalog/variants/dev/namespaces/team-a/resources/definition/config",
+			},
+			want: "res://catalogs/my-catalog/variants/dev/namespaces/team-a/resources/config",
+		},
+		{
+			name:  "namespaced resource in a variant",
+			scope: variantScope,
+			resources: []TargetResource{
+				"res://namespaces/team-a/skillsets/tools",
+				"res://catalogs/my-catalog/variants/dev/namespaces/team-a/skillsets/tools",
+			},
+			want: "res://catalogs/my-catalog/variants/dev/namespaces/team-a/skillsets/tools",
+		},
+		{
+			name:  "variant of a catalog",
+			scope: Scope{Catalog: "my-catalog"},
+			resources: []TargetResource{
+				"res://variants/dev/*",
+				"res://catalogs/my-catalog/variants/dev/*",
+			},
+			want: "res://catalogs/my-catalog/variants/dev/*",
+		},
+		{
+			name:  "view",
+			scope: namespaceScope,
+			resources: []TargetResource{
+				"/views/my-view",
+				"res://catalogs/my-catalog/views/my-view",
+			},
+			want: "res://catalogs/my-catalog/views/my-view",
+		},
+		{
+			name:  "catalog",
+			scope: variantScope,
+			resources: []TargetResource{
+				"res://catalogs/my-catalog",
+				"res://catalogs/my-catalog/",
+			},
+			want: "res://catalogs/my-catalog",
+		},
+		{
+			name:  "scope",
+			scope: variantScope,
+			resources: []TargetResource{
+				"",
+				"res://",
+				"res://.",
+				"/",
+				"res://catalogs/my-catalog/variants/dev",
+			},
+			want: "res://catalogs/my-catalog/variants/dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, resource := range tt.resources {
+				got, err := CanonicalizeResource(tt.scope, resource)
+				require.NoError(t, err, "resource %q", resource)
+				assert.Equal(t, tt.want, got, "resource %q", resource)
+			}
+		})
+	}
+
+	outside := []struct {
+		name     string
+		scope    Scope
+		resource TargetResource
+	}{
+		{"other catalog", variantScope, "res://catalogs/other-catalog/variants/dev/resources/config"},
+		{"other variant", variantScope, "res://catalogs/my-catalog/variants/prod/resources/config"},
+		{"other namespace", namespaceScope, "res://catalogs/my-catalog/variants/dev/namespaces/team-b/resources/config"},
+		{"escapes the catalog", Scope{Catalog: "my-catalog"}, "res://variants/../../other-catalog"},
+		{"escapes the variant", variantScope, "res://../prod/resources/config"},
+	}
+	for _, tt := range outside {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CanonicalizeResource(tt.scope, tt.resource)
+			assert.ErrorIs(t, err, ErrResourceOutsideScope)
+		})
+	}
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, err := CanonicalizeResource(variantScope, "https://resources/config")
+		assert.ErrorIs(t, err, ErrInvalidResourcePath)
+	})
+}
+
 func TestCanonicalizeViewDefinition(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -651,3 +798,29 @@ func TestCanonicalizeViewDefinition(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespacedResourcePath(t *testing.T) {
+	scope := Scope{Catalog: "test-catalog", Variant: "dev"}
+	tests := []struct {
+		name      string
+		scope     Scope
+		namespace string
+		path      string
+		expected  string
+	}{
+		{name: "no namespace", scope: scope, path: "/resources/kubeconfig", expected: "/resources/kubeconfig"},
+		{name: "default namespace", scope: scope, namespace: catcommon.DefaultNamespace, path: "/resources/kubeconfig", expected: "/resources/kubeconfig"},
+		{name: "resource", scope: scope, namespace: "team-a", path: "/resources/kubeconfig", expected: "/namespaces/team-a/resources/kubeconfig"},
+		{name: "resource definition", scope: scope, namespace: "team-a", path: "/resources/definition/kubeconfig", expected: "/namespaces/team-a/resources/kubeconfig"},
+		{name: "skillset", scope: scope, namespace: "team-a", path: "/skillsets/tools/deploy", expected: "/namespaces/team-a/skillsets/tools/deploy"},
+		{name: "res prefix", scope: scope, namespace: "team-a", path: "res://skillsets/tools/deploy", expected: "/namespaces/team-a/skillsets/tools/deploy"},
+		{name: "already namespaced", scope: scope, namespace: "team-a", path: "/namespaces/team-b/resources/kubeconfig", expected: "/namespaces/team-b/resources/kubeconfig"},
+		{name: "not namespaced kind", scope: scope, namespace: "team-a", path: "/views/dev-view", expected: "/views/dev-view"},
+		{name: "view bound to namespace", scope: Scope{Catalog: "test-catalog", Variant: "dev", Namespace: "team-a"}, namespace: "team-a", path: "/resources/kubeconfig", expected: "/resources/kubeconfig"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NamespacedResourcePath(tt.scope, tt.namespace, tt.path))
+		})
+	}
+}
@@ -172,6 +172,12 @@ func ValidatePathSegment(segment string) bool {
 	return re.MatchString(segment)
 }
 
+// schemaDrafts are the drafts a schema may be written against, the compiler's default first.
+var schemaDrafts = []*jsonschema.Draft{jsonschema.Draft2020, jsonschema.Draft2019, jsonschema.Draft7, jsonschema.Draft6, jsonschema.Draft4}
+
+// JsonSchemaValidator reports whether the field holds a JSON schema that compiles under one of
+// the supported drafts. Objects that declare the draft of their schemas compile them with that
+// draft when they are validated.
 func JsonSchemaValidator(fl validator.FieldLevel) bool {
 	schema := fl.Field().Bytes()
 	// First validate that the schema is valid JSON using gjson
@@ -179,7 +185,18 @@ func JsonSchemaValidator(fl validator.FieldLevel) bool {
 		return false
 	}
 
+	for _, draft := range schemaDrafts {
+		if compileJsonSchema(schema, draft) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// compileJsonSchema compiles schema with draft as the default draft.
+func compileJsonSchema(schema []byte, draft *jsonschema.Draft) error {
 	compiler := jsonschema.NewCompiler()
+	compiler.Draft = draft
 	// Allow schemas with $id to refer to themselves
 	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
 		if url == "inline://schema" {
@@ -187,13 +204,11 @@ func JsonSchemaValidator(fl validator.FieldLevel) bool {
 		}
 		return nil, fmt.Errorf("unsupported schema ref: %s", url)
 	}
-	err := compiler.AddResource("inline://schema", bytes.NewReader([]byte(schema)))
-	if err != nil {
-		return false
+	if err := compiler.AddResource("inline://schema", bytes.NewReader([]byte(schema))); err != nil {
+		return err
 	}
-	_, err = compiler.Compile("inline://schema")
-
-	return err == nil
+	_, err := compiler.Compile("inline://schema")
+	return err
 }
 
 func init() {
@@ -225,6 +225,195 @@ func setupTest(t *testing.T) *testSetup {
 	}
 }
 
+func TestListAdoptableViews(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	// Create a view that may only adopt the read-only and full-access views
+	httpReq, _ := http.NewRequest("POST", "/views", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "picker-view",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"description": "View that can adopt some views"
+			},
+			"spec": {
+				"rules": [{
+					"intent": "Allow",
+					"actions": ["system.catalog.adoptView"],
+					"targets": ["res://views/read-only-view", "res://views/full-access-view"]
+				}]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code)
+	pickerToken := adoptView(t, "test-catalog", "picker-view", token)
+
+	type adoptableViews struct {
+		Views []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"views"`
+		NextCursor string `json:"nextCursor"`
+	}
+	listAdoptable := func(query string) adoptableViews {
+		httpReq, _ := http.NewRequest("GET", "/views/adoptable"+query, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+pickerToken)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusOK, response.Code)
+		var rsp adoptableViews
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	rsp := listAdoptable("")
+	require.Len(t, rsp.Views, 2)
+	require.Equal(t, "full-access-view", rsp.Views[0].Name)
+	require.Equal(t, "View with full resource access", rsp.Views[0].Description)
+	require.Equal(t, "read-only-view", rsp.Views[1].Name)
+	require.Empty(t, rsp.NextCursor)
+
+	// Page through the views one at a time
+	rsp = listAdoptable("?limit=1")
+	require.Len(t, rsp.Views, 1)
+	require.Equal(t, "full-access-view", rsp.Views[0].Name)
+	require.Equal(t, "full-access-view", rsp.NextCursor)
+	rsp = listAdoptable("?limit=1&cursor=" + rsp.NextCursor)
+	require.Len(t, rsp.Views, 1)
+	require.Equal(t, "read-only-view", rsp.Views[0].Name)
+	require.Empty(t, rsp.NextCursor)
+
+	httpReq, _ = http.NewRequest("GET", "/views/adoptable?limit=0", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+pickerToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	// A view without adopt permissions can adopt nothing
+	readOnlyToken := adoptView(t, "test-catalog", "read-only-view", token)
+	httpReq, _ = http.NewRequest("GET", "/views/adoptable", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+readOnlyToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var emptyRsp adoptableViews
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &emptyRsp))
+	require.Empty(t, emptyRsp.Views)
+}
+
+func TestRevokeViewTokens(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	readOnlyToken := adoptView(t, "test-catalog", "read-only-view", token)
+	getResource := func(token string) int {
+		httpReq, _ := http.NewRequest("GET", "/resources/resource1", nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+	require.Equal(t, http.StatusOK, getResource(readOnlyToken))
+
+	// A view without admin rights on the target view cannot revoke its tokens
+	httpReq, _ := http.NewRequest("POST", "/views/read-only-view/revoke-tokens", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+readOnlyToken)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/views/read-only-view/revoke-tokens", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var rsp struct {
+		TokenEpoch int64 `json:"tokenEpoch"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Equal(t, int64(1), rsp.TokenEpoch)
+
+	// The pre-revocation token is rejected
+	require.Equal(t, http.StatusUnauthorized, getResource(readOnlyToken))
+
+	// A freshly minted token works, and tokens for other views are unaffected
+	require.Equal(t, http.StatusOK, getResource(adoptView(t, "test-catalog", "read-only-view", token)))
+	require.Equal(t, http.StatusOK, getResource(token))
+
+	httpReq, _ = http.NewRequest("POST", "/views/no-such-view/revoke-tokens", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestViewPermissions(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	httpReq, _ := http.NewRequest("POST", "/views", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "mixed-view",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"description": "View with a denied action on one resource"
+			},
+			"spec": {
+				"rules": [{
+					"intent": "Allow",
+					"actions": ["system.resource.get", "system.resource.put"],
+					"targets": ["res://resources/*"]
+				}, {
+					"intent": "Deny",
+					"actions": ["system.resource.put"],
+					"targets": ["res://resources/resource1"]
+				}]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	type permissionsRsp struct {
+		Resource string   `json:"resource"`
+		Allowed  []string `json:"allowed"`
+		Denied   []string `json:"denied"`
+	}
+	getPermissions := func(view, query string) (int, permissionsRsp) {
+		httpReq, _ := http.NewRequest("GET", "/views/"+view+"/permissions"+query, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		var rsp permissionsRsp
+		if response.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		}
+		return response.Code, rsp
+	}
+
+	code, rsp := getPermissions("mixed-view", "?resource=res://resources/resource1")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"system.resource.get"}, rsp.Allowed)
+	require.Contains(t, rsp.Denied, "system.resource.put")
+	require.Contains(t, rsp.Denied, "system.resource.edit")
+
+	code, rsp = getPermissions("mixed-view", "?resource=res://resources/resource2")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"system.resource.get", "system.resource.put"}, rsp.Allowed)
+
+	code, _ = getPermissions("mixed-view", "")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = getPermissions("no-such-view", "?resource=res://resources/resource1")
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
 func setupObjects(t *testing.T, token string) {
 	// Create a variant
 	httpReq, _ := http.NewRequest("POST", "/variants", nil)
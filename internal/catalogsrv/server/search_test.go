@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCatalog(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	// Create a skillset whose skill names and description match the search terms
+	httpReq, _ := http.NewRequest("POST", "/skillsets", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "SkillSet",
+			"metadata": {
+				"name": "cluster-tools",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"path": "/ops",
+				"description": "Tools with read access to the cluster"
+			},
+			"spec": {
+				"version": "1.0.0",
+				"sources": [
+					{
+						"name": "command-runner",
+						"runner": "system.commandrunner",
+						"config": {
+							"command": "python3 test.py"
+						}
+					}
+				],
+				"skills": [
+					{
+						"name": "list_pods",
+						"description": "List pods",
+						"source": "command-runner",
+						"inputSchema": {
+							"type": "object"
+						},
+						"outputSchema": {
+							"type": "object"
+						},
+						"exportedActions": ["kubernetes.pods.list"]
+					}
+				]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	// Create another catalog with a view that also matches
+	httpReq, _ = http.NewRequest("POST", "/catalogs", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "other-catalog",
+				"description": "Catalog outside the search scope"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+setup.userToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code)
+	otherToken := adoptDefaultView(t, "other-catalog", setup.userToken)
+
+	httpReq, _ = http.NewRequest("POST", "/views", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "other-access-view",
+				"catalog": "other-catalog",
+				"description": "View with read access in another catalog"
+			},
+			"spec": {
+				"rules": [{
+					"intent": "Allow",
+					"actions": ["system.resource.get"],
+					"targets": ["res://resources/*"]
+				}]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+otherToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	type searchResults struct {
+		Results []struct {
+			Kind        string `json:"kind"`
+			Name        string `json:"name"`
+			Variant     string `json:"variant"`
+			Description string `json:"description"`
+		} `json:"results"`
+		NextCursor string `json:"nextCursor"`
+	}
+	search := func(query string) searchResults {
+		httpReq, _ := http.NewRequest("GET", "/search"+query, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var rsp searchResults
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	// A term matches both skillsets and views, and only within the catalog
+	rsp := search("?q=read")
+	require.Len(t, rsp.Results, 3)
+	require.Equal(t, "SkillSet", rsp.Results[0].Kind)
+	require.Equal(t, "/ops/cluster-tools", rsp.Results[0].Name)
+	require.Equal(t, "test-variant", rsp.Results[0].Variant)
+	require.Equal(t, "Tools with read access to the cluster", rsp.Results[0].Description)
+	require.Equal(t, "View", rsp.Results[1].Kind)
+	require.Equal(t, "read-only-view", rsp.Results[1].Name)
+	require.Equal(t, "View", rsp.Results[2].Kind)
+	require.Equal(t, "read-write-view", rsp.Results[2].Name)
+	require.Empty(t, rsp.NextCursor)
+
+	// Skill names and path segments are searchable, and words match by prefix
+	rsp = search("?q=pod")
+	require.Len(t, rsp.Results, 1)
+	require.Equal(t, "/ops/cluster-tools", rsp.Results[0].Name)
+	rsp = search("?q=ops+clust")
+	require.Len(t, rsp.Results, 1)
+	require.Equal(t, "/ops/cluster-tools", rsp.Results[0].Name)
+
+	// Page through the results one at a time
+	rsp = search("?q=read&limit=2")
+	require.Len(t, rsp.Results, 2)
+	require.NotEmpty(t, rsp.NextCursor)
+	rsp = search("?q=read&limit=2&cursor=" + rsp.NextCursor)
+	require.Len(t, rsp.Results, 1)
+	require.Equal(t, "read-write-view", rsp.Results[0].Name)
+	require.Empty(t, rsp.NextCursor)
+
+	rsp = search("?q=nomatch")
+	require.Empty(t, rsp.Results)
+
+	for _, query := range []string{"", "?q=%26%7C", "?q=read&limit=0", "?q=read&cursor=bogus"} {
+		httpReq, _ = http.NewRequest("GET", "/search"+query, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response = executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusBadRequest, response.Code, query)
+	}
+
+	// Deleted skillsets are no longer found
+	httpReq, _ = http.NewRequest("DELETE", "/skillsets/ops/cluster-tools?variant=test-variant", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	rsp = search("?q=pod")
+	require.Empty(t, rsp.Results)
+}
@@ -37,9 +37,10 @@ func CreateNewServer() (*CatalogServer, error) {
 func (s *CatalogServer) MountHandlers() {
 	s.Router.Use(commonmiddleware.RequestLogger)
 	s.Router.Use(commonmiddleware.PanicHandler)
+	s.Router.Use(commonmiddleware.PrettyJSON)
 	s.Router.Use(db.LoadScopedDBMiddleware)
 	if config.Config().HandleCORS {
-		s.Router.Use(s.HandleCORS)
+		s.Router.Use(commonmiddleware.CORS(config.Config().CORS))
 	}
 	//s.Router.Route("/", s.mountResourceHandlers)
 	s.mountResourceHandlers(s.Router)
@@ -60,9 +61,12 @@ func (s *CatalogServer) mountResourceHandlers(r chi.Router) {
 	apis.Router(r)
 	r.Mount("/auth", auth.Router(r))
 	r.Mount("/sessions", session.Router())
-	r.Mount("/tangents", tangent.Router())
+	tangentRouter := tangent.Router()
+	session.TangentRoutes(tangentRouter)
+	r.Mount("/tangents", tangentRouter)
 	r.Get("/version", s.getVersion)
 	r.Get("/ready", s.getReadiness)
+	r.Get("/ready/db", s.getDBPoolStats)
 	r.Get("/.well-known/jwks.json", auth.GetJWKSHandler(s.km))
 }
 
@@ -101,20 +105,41 @@ func (s *CatalogServer) getReadiness(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (s *CatalogServer) HandleCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "http://local.tansive.dev:8190")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")                                                       // Allowed methods
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Hatch-IDToken") // Allowed headers
+// DBPoolStatsRsp reports the state of the database connection pool
+type DBPoolStatsRsp struct {
+	MaxOpenConnections int    `json:"maxOpenConnections"`
+	OpenConnections    int    `json:"openConnections"`
+	InUse              int    `json:"inUse"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"waitCount"`
+	WaitDuration       string `json:"waitDuration"`
+	MaxIdleClosed      int64  `json:"maxIdleClosed"`
+	MaxIdleTimeClosed  int64  `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed  int64  `json:"maxLifetimeClosed"`
+	ConnRequests       uint64 `json:"connRequests"`
+	ConnReturns        uint64 `json:"connReturns"`
+}
 
-		// Check if the request method is OPTIONS
-		if r.Method == "OPTIONS" {
-			log.Ctx(r.Context()).Debug().Msg("OPTIONS request")
-			// Respond with appropriate headers and no body
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		next.ServeHTTP(w, r)
+func (s *CatalogServer) getDBPoolStats(w http.ResponseWriter, r *http.Request) {
+	stats, requests, returns, ok := db.PoolStats()
+	if !ok {
+		httpx.SendJsonRsp(r.Context(), w, http.StatusServiceUnavailable, map[string]string{
+			"status": "not ready",
+			"error":  "database pool not initialized",
+		})
+		return
+	}
+	httpx.SendJsonRsp(r.Context(), w, http.StatusOK, &DBPoolStatsRsp{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		ConnRequests:       requests,
+		ConnReturns:        returns,
 	})
 }
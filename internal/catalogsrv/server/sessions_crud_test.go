@@ -572,6 +572,125 @@ func TestSessionCrud(t *testing.T) {
 		assert.GreaterOrEqual(t, len(sessions), 1) // Should have at least one session from previous tests
 	})
 
+	// Test filtering sessions by label
+	t.Run("get sessions by label", func(t *testing.T) {
+		runID := uuid.New().String()
+		httpReq, _ := http.NewRequest("POST", "/sessions?code_challenge=test_challenge", nil)
+		req := `
+			{
+				"skillPath": "/valid-skillset/test-skill",
+				"viewName": "valid-view",
+				"inputArgs": {
+					"input": "test input"
+				},
+				"labels": {
+					"team": "data",
+					"run-id": "` + runID + `"
+				}
+			}`
+		setRequestBodyAndHeader(t, httpReq, req)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+		httpReq, _ = http.NewRequest("GET", "/sessions?label.team=data&label.run-id="+runID, nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+		var sessions []session.SessionSummaryInfo
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &sessions))
+		require.Len(t, sessions, 1)
+		assert.Equal(t, map[string]string{"team": "data", "run-id": runID}, sessions[0].Labels)
+
+		httpReq, _ = http.NewRequest("GET", "/sessions?label.team=web&label.run-id="+runID, nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+		sessions = nil
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &sessions))
+		assert.Empty(t, sessions)
+
+		// invalid labels are rejected when creating and when filtering
+		httpReq, _ = http.NewRequest("GET", "/sessions?label.team=data%20science", nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		httpReq, _ = http.NewRequest("POST", "/sessions?code_challenge=test_challenge", nil)
+		setRequestBodyAndHeader(t, httpReq, `{"skillPath": "/valid-skillset/test-skill", "viewName": "valid-view", "labels": {"bad key": "x"}}`)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	// Test session dependencies API
+	t.Run("get session dependencies", func(t *testing.T) {
+		runID := uuid.New().String()
+		httpReq, _ := http.NewRequest("POST", "/sessions?code_challenge=test_challenge", nil)
+		setRequestBodyAndHeader(t, httpReq, `{"skillPath": "/valid-skillset/test-skill", "viewName": "valid-view", "inputArgs": {"input": "test input"}, "labels": {"run-id": "`+runID+`"}}`)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+		httpReq, _ = http.NewRequest("GET", "/sessions?label.run-id="+runID, nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+		var sessions []session.SessionSummaryInfo
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &sessions))
+		require.Len(t, sessions, 1)
+		sessionID := sessions[0].SessionID
+
+		httpReq, _ = http.NewRequest("GET", "/sessions/"+sessionID.String()+"/dependencies", nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var deps session.SessionDependenciesRsp
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &deps))
+		assert.Equal(t, sessionID, deps.SessionID)
+		assert.Empty(t, deps.Dependencies)
+
+		httpReq, _ = http.NewRequest("GET", "/sessions/invalid-uuid/dependencies", nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "invalid sessionID")
+
+		httpReq, _ = http.NewRequest("GET", "/sessions/"+uuid.New().String()+"/dependencies", nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		assert.NotEqual(t, http.StatusOK, response.Code)
+	})
+
+	// Test batch session summary API
+	t.Run("get session summary batch", func(t *testing.T) {
+		httpReq, _ := http.NewRequest("GET", "/sessions", nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+		var sessions []session.SessionSummaryInfo
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &sessions))
+		require.NotEmpty(t, sessions)
+
+		existingID := sessions[0].SessionID.String()
+		missingID := uuid.New().String()
+		httpReq, _ = http.NewRequest("POST", "/sessions/summary/batch", nil)
+		setRequestBodyAndHeader(t, httpReq, `{"sessionIDs": ["`+missingID+`", "`+existingID+`", "`+missingID+`"]}`)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var batch session.SessionSummaryBatchRsp
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &batch))
+		require.Len(t, batch.Sessions, 3)
+		assert.Equal(t, missingID, batch.Sessions[0].SessionID)
+		assert.True(t, batch.Sessions[0].NotFound)
+		assert.Nil(t, batch.Sessions[0].Summary)
+		assert.Equal(t, existingID, batch.Sessions[1].SessionID)
+		assert.False(t, batch.Sessions[1].NotFound)
+		if assert.NotNil(t, batch.Sessions[1].Summary) {
+			assert.Equal(t, sessions[0].SessionID, batch.Sessions[1].Summary.SessionID)
+			assert.Equal(t, sessions[0].StatusSummary, batch.Sessions[1].Summary.StatusSummary)
+		}
+		assert.True(t, batch.Sessions[2].NotFound)
+
+		// invalid IDs and empty lists are rejected
+		for _, body := range []string{`{"sessionIDs": ["not-a-uuid"]}`, `{"sessionIDs": []}`} {
+			httpReq, _ = http.NewRequest("POST", "/sessions/summary/batch", nil)
+			setRequestBodyAndHeader(t, httpReq, body)
+			response = executeTestRequest(t, httpReq, nil, testContext)
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+		}
+	})
+
 	// Test getSessionSummaryByID API
 	t.Run("get session summary by ID", func(t *testing.T) {
 		// First create a session to get its ID
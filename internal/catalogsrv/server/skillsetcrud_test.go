@@ -204,7 +204,7 @@ func TestSkillSetCrud(t *testing.T) {
 								}
 							}
 						},
-						"transform": null,
+						"transform": "function(session, input) { return input; }",
 						"exportedActions": ["python.action"]
 					}
 				],
@@ -245,6 +245,90 @@ func TestSkillSetCrud(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, reqType, rspType)
 
+	// Get only some sections of the skillset spec; skills bring their sources along
+	httpReq, _ = http.NewRequest("GET", "/skillsets/valid-skillset?fields=skills", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	partial := make(map[string]any)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &partial))
+	assert.Equal(t, reqType["metadata"], partial["metadata"])
+	partialSpec := partial["spec"].(map[string]any)
+	reqSpec := reqType["spec"].(map[string]any)
+	assert.Equal(t, reqSpec["skills"], partialSpec["skills"])
+	assert.Equal(t, reqSpec["sources"], partialSpec["sources"])
+	assert.Equal(t, reqSpec["version"], partialSpec["version"])
+	assert.NotContains(t, partialSpec, "context")
+
+	httpReq, _ = http.NewRequest("GET", "/skillsets/valid-skillset?fields=skills,unknown", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Describe the skillset
+	httpReq, _ = http.NewRequest("GET", "/skillsets/valid-skillset/describe", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+	var description struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Skills  []struct {
+			Name            string   `json:"name"`
+			Description     string   `json:"description"`
+			Runner          string   `json:"runner"`
+			ExportedActions []string `json:"exportedActions"`
+		} `json:"skills"`
+		Contexts     []string `json:"contexts"`
+		Dependencies []struct {
+			Alias string `json:"alias"`
+		} `json:"dependencies"`
+	}
+	err = json.Unmarshal(response.Body.Bytes(), &description)
+	assert.NoError(t, err)
+	assert.Equal(t, "valid-skillset", description.Name)
+	assert.Equal(t, "1.0.0", description.Version)
+	if assert.Len(t, description.Skills, 2) {
+		assert.Equal(t, "test-skill", description.Skills[0].Name)
+		assert.Equal(t, "system.commandrunner", description.Skills[0].Runner)
+		assert.Equal(t, []string{"test.action"}, description.Skills[0].ExportedActions)
+		assert.Equal(t, "python-skill", description.Skills[1].Name)
+		assert.Equal(t, "system.pythonrunner", description.Skills[1].Runner)
+		assert.Equal(t, "Python test skill", description.Skills[1].Description)
+	}
+	assert.Equal(t, []string{"test-context", "test-context"}, description.Contexts)
+	if assert.Len(t, description.Dependencies, 1) {
+		assert.Equal(t, "test-resource", description.Dependencies[0].Alias)
+	}
+	assert.NotContains(t, response.Body.String(), "python3 test.py")
+
+	// Describing a skillset that does not exist fails
+	httpReq, _ = http.NewRequest("GET", "/skillsets/missing-skillset/describe", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Download a skill's transform
+	httpReq, _ = http.NewRequest("GET", "/skillsets/valid-skillset/skills/python-skill/transform", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+	compareJson(t, map[string]any{
+		"skill": "python-skill",
+		"input": "function(session, input) { return input; }",
+	}, response.Body.String())
+
+	// A skill without a transform has no content
+	httpReq, _ = http.NewRequest("GET", "/skillsets/valid-skillset/skills/test-skill/transform", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Empty(t, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/skillsets/valid-skillset/skills/missing-skill/transform", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
 	// Update the skillset
 	req = `
 		{
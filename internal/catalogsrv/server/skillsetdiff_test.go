@@ -0,0 +1,117 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+)
+
+func TestSkillSetDiff(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	skillSet := func(version, skills, dependencies string) string {
+		return `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "SkillSet",
+				"metadata": {
+					"name": "diff-tools",
+					"catalog": "test-catalog",
+					"variant": "test-variant",
+					"path": "/"
+				},
+				"spec": {
+					"version": "` + version + `",
+					"sources": [
+						{
+							"name": "command-runner",
+							"runner": "system.commandrunner",
+							"config": {
+								"command": "python3 test.py"
+							}
+						}
+					],
+					"skills": ` + skills + `,
+					"dependencies": ` + dependencies + `
+				}
+			}`
+	}
+	save := func(method, path, body string) {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Contains(t, []int{http.StatusCreated, http.StatusOK}, response.Code, response.Body.String())
+	}
+
+	save("POST", "/skillsets", skillSet("1.0.0", `[
+		{
+			"name": "run",
+			"description": "Run the command",
+			"source": "command-runner",
+			"inputSchema": {"type": "object"},
+			"outputSchema": {"type": "object"},
+			"transform": "function(session, input) { return input; }",
+			"exportedActions": ["system.skillset.use"]
+		}
+	]`, `[
+		{"path": "/resources/resource1", "kind": "Resource", "alias": "first", "actions": ["system.resource.get"]},
+		{"path": "/resources/resource2", "kind": "Resource", "alias": "second", "actions": ["system.resource.get"]}
+	]`))
+	save("PUT", "/skillsets/diff-tools?variant=test-variant", skillSet("1.1.0", `[
+		{
+			"name": "run",
+			"description": "Run the command",
+			"source": "command-runner",
+			"inputSchema": {"type": "object"},
+			"outputSchema": {"type": "object"},
+			"transform": "function(session, input) { return {args: input}; }",
+			"exportedActions": ["system.skillset.use"]
+		},
+		{
+			"name": "check",
+			"description": "Check the command",
+			"source": "command-runner",
+			"inputSchema": {"type": "object"},
+			"outputSchema": {"type": "object"},
+			"exportedActions": ["system.skillset.use"]
+		}
+	]`, `[
+		{"path": "/resources/resource1", "kind": "Resource", "alias": "first", "actions": ["system.resource.get"]}
+	]`))
+
+	getDiff := func(query string) (int, *catalogmanager.SkillSetDiff, string) {
+		httpReq, _ := http.NewRequest("GET", "/skillsets/diff-tools/diff?variant=test-variant&"+query, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		if response.Code != http.StatusOK {
+			return response.Code, nil, response.Body.String()
+		}
+		var diff catalogmanager.SkillSetDiff
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &diff))
+		return response.Code, &diff, ""
+	}
+
+	code, diff, body := getDiff("from=1.0.0&to=1.1.0")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, "1.0.0", diff.From)
+	assert.Equal(t, "1.1.0", diff.To)
+	assert.Equal(t, []string{"check"}, diff.Skills.Added)
+	assert.Empty(t, diff.Skills.Removed)
+	assert.Equal(t, []catalogmanager.ChangedElement{{Name: "run", Fields: []string{"transform"}}}, diff.Skills.Changed)
+	assert.Equal(t, []string{"second"}, diff.Dependencies.Removed)
+	assert.Empty(t, diff.Dependencies.Added)
+	assert.Empty(t, diff.Sources.Changed)
+
+	code, _, _ = getDiff("from=1.0.0&to=9.9.9")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _, _ = getDiff("from=1.0.0")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
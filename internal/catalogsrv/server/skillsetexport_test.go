@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillSetExport(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	createSkillSet := func(name, path, dependencies string) {
+		httpReq, _ := http.NewRequest("POST", "/skillsets", nil)
+		req := `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "SkillSet",
+				"metadata": {
+					"name": "` + name + `",
+					"catalog": "test-catalog",
+					"variant": "test-variant",
+					"path": "` + path + `"
+				},
+				"spec": {
+					"version": "1.0.0",
+					"sources": [
+						{
+							"name": "command-runner",
+							"runner": "system.commandrunner",
+							"config": {
+								"command": "python3 test.py"
+							}
+						}
+					],
+					"skills": [
+						{
+							"name": "run",
+							"description": "Run the command",
+							"source": "command-runner",
+							"inputSchema": {
+								"type": "object"
+							},
+							"outputSchema": {
+								"type": "object"
+							},
+							"exportedActions": ["system.skillset.use"]
+						}
+					],
+					"dependencies": ` + dependencies + `
+				}
+			}`
+		setRequestBodyAndHeader(t, httpReq, req)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	createSkillSet("base-tools", "/ops", `[
+		{"path": "/resources/resource1", "kind": "Resource", "alias": "first", "actions": ["system.resource.get"]}
+	]`)
+	createSkillSet("app-tools", "/", `[
+		{"path": "/skillsets/ops/base-tools", "kind": "SkillSet", "alias": "base", "actions": ["system.skillset.use"]},
+		{"path": "/resources/resource2", "kind": "Resource", "alias": "second", "actions": ["system.resource.get"]},
+		{"path": "/resources/resource1", "kind": "Resource", "alias": "first", "actions": ["system.resource.get"]},
+		{"kind": "Resource", "alias": "inline", "actions": ["system.resource.get"], "inline": {"value": 1}}
+	]`)
+	createSkillSet("broken-tools", "/", `[
+		{"path": "/skillsets/ops/base-tools", "kind": "SkillSet", "alias": "base", "actions": ["system.skillset.use"]},
+		{"path": "/skillsets/ops/missing-tools", "kind": "SkillSet", "alias": "missing", "actions": ["system.skillset.use"]},
+		{"path": "/resources/missing-resource", "kind": "Resource", "alias": "gone", "actions": ["system.resource.get"]}
+	]`)
+
+	type bundledObject struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name string `json:"name"`
+			Path string `json:"path"`
+		} `json:"metadata"`
+	}
+	export := func(query string) (int, []bundledObject, string) {
+		httpReq, _ := http.NewRequest("GET", query, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		if response.Code != http.StatusOK {
+			return response.Code, nil, response.Body.String()
+		}
+		var bundle struct {
+			Objects []bundledObject `json:"objects"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &bundle))
+		return response.Code, bundle.Objects, ""
+	}
+
+	// Without dependencies the bundle only holds the skillset
+	code, objects, _ := export("/skillsets/app-tools/export?variant=test-variant")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, objects, 1)
+	require.Equal(t, "SkillSet", objects[0].Kind)
+	require.Equal(t, "app-tools", objects[0].Metadata.Name)
+
+	// The transitive closure of dependencies is bundled once each, and inline dependencies are skipped
+	code, objects, body := export("/skillsets/app-tools/export?variant=test-variant&withDeps=true")
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, objects, 4)
+	require.Equal(t, "app-tools", objects[0].Metadata.Name)
+	require.Equal(t, "SkillSet", objects[1].Kind)
+	require.Equal(t, "base-tools", objects[1].Metadata.Name)
+	require.Equal(t, "/ops", objects[1].Metadata.Path)
+	require.Equal(t, "Resource", objects[2].Kind)
+	require.Equal(t, "resource2", objects[2].Metadata.Name)
+	require.Equal(t, "Resource", objects[3].Kind)
+	require.Equal(t, "resource1", objects[3].Metadata.Name)
+
+	// Every dangling reference is reported
+	code, _, body = export("/skillsets/broken-tools/export?variant=test-variant&withDeps=true")
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.Contains(t, body, "/skillsets/ops/missing-tools")
+	require.Contains(t, body, "/resources/missing-resource")
+
+	code, _, _ = export("/skillsets/app-tools/export?variant=test-variant&withDeps=maybe")
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _, _ = export("/skillsets/no-such-tools/export?variant=test-variant&withDeps=true")
+	require.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSkillSet(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	skillSet := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "SkillSet",
+			"metadata": {
+				"name": "invalid-tools",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"path": "/"
+			},
+			"spec": {
+				"version": "1.0.0",
+				"sources": [
+					{
+						"name": "command-runner",
+						"runner": "system.commandrunner",
+						"config": {
+							"command": "python3 test.py"
+						}
+					}
+				],
+				"skills": [
+					{
+						"name": "run",
+						"description": "Run the command",
+						"source": "missing-runner",
+						"inputSchema": {"type": "object"},
+						"outputSchema": {"type": "object"},
+						"exportedActions": ["system.skillset.use"]
+					},
+					{
+						"name": "check",
+						"description": "Check the command",
+						"source": "command-runner",
+						"inputSchema": {"type": "object"},
+						"outputSchema": {"type": "object"},
+						"maxConcurrent": -1,
+						"exportedActions": ["system.skillset.use"]
+					}
+				]
+			}
+		}`
+	validate := func(query string) *bytes.Buffer {
+		httpReq, _ := http.NewRequest("POST", "/skillsets/validate"+query, nil)
+		setRequestBodyAndHeader(t, httpReq, skillSet)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		return response.Body
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		var rsp struct {
+			Valid  bool `json:"valid"`
+			Errors []struct {
+				Field string `json:"field"`
+				Error string `json:"error"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(validate("").Bytes(), &rsp))
+		assert.False(t, rsp.Valid)
+		require.Len(t, rsp.Errors, 2)
+		assert.Equal(t, "spec.skills[0].source", rsp.Errors[0].Field)
+		assert.Equal(t, "spec.skills[1].maxConcurrent", rsp.Errors[1].Field)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var fields []string
+		scanner := bufio.NewScanner(validate("?stream=true"))
+		for scanner.Scan() {
+			var item struct {
+				Field string `json:"field"`
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &item))
+			assert.NotEmpty(t, item.Error)
+			fields = append(fields, item.Field)
+		}
+		assert.Equal(t, []string{"spec.skills[0].source", "spec.skills[1].maxConcurrent"}, fields)
+	})
+}
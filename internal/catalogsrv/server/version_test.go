@@ -61,6 +61,22 @@ func TestGetReadiness(t *testing.T) {
 	}, response.Body.String())
 }
 
+func TestGetDBPoolStats(t *testing.T) {
+	newDb()
+	req, _ := http.NewRequest("GET", "/ready/db", nil)
+	testContext := TestContext{
+		TenantId:  "tenant1",
+		ProjectId: "project1",
+	}
+	response := executeTestRequest(t, req, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var rsp DBPoolStatsRsp
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Equal(t, config.Config().DB.MaxOpenConns, rsp.MaxOpenConnections)
+	require.NotZero(t, rsp.ConnRequests)
+}
+
 func TestGetJWKS(t *testing.T) {
 	config.TestInit()
 	// Create a New Request
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"sigs.k8s.io/yaml"
+)
+
+func TestYAMLResources(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	postYAML := func(path, body string) *http.Response {
+		httpReq, _ := http.NewRequest("POST", path, strings.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/yaml")
+		httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+		return executeTestRequest(t, httpReq, nil, testContext).Result()
+	}
+	postJSON := func(path, body string) *http.Response {
+		httpReq, _ := http.NewRequest("POST", path, nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+		return executeTestRequest(t, httpReq, nil, testContext).Result()
+	}
+	// getWithoutName fetches an object and drops its name so objects created from YAML and JSON can be compared
+	getWithoutName := func(path string) map[string]any {
+		httpReq, _ := http.NewRequest("GET", path, nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var obj map[string]any
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &obj))
+		delete(obj["metadata"].(map[string]any), "name")
+		return obj
+	}
+
+	// Create a catalog and variant from YAML
+	rsp := postYAML("/catalogs", `
+apiVersion: "0.1.0-alpha.1"
+kind: Catalog
+metadata:
+  name: yaml-catalog
+  description: Catalog created from YAML
+`)
+	require.Equal(t, http.StatusCreated, rsp.StatusCode)
+	testContext.CatalogContext.Catalog = "yaml-catalog"
+
+	rsp = postYAML("/variants", `
+apiVersion: "0.1.0-alpha.1"
+kind: Variant
+metadata:
+  name: yaml-variant
+`)
+	require.Equal(t, http.StatusCreated, rsp.StatusCode)
+	testContext.CatalogContext.Variant = "yaml-variant"
+
+	skillsetYAML := `
+apiVersion: "0.1.0-alpha.1"
+kind: SkillSet
+metadata:
+  name: %s
+  catalog: yaml-catalog
+  variant: yaml-variant
+  path: /
+  description: A skillset
+spec:
+  version: "1.0.0"
+  sources:
+    - name: command-runner
+      runner: system.commandrunner
+      config:
+        command: python3 test.py
+  skills:
+    - name: test-skill
+      description: Test skill
+      source: command-runner
+      inputSchema:
+        type: object
+        properties:
+          input:
+            type: string
+      exportedActions:
+        - test.action
+`
+	var skillsetJSON map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(strings.Replace(skillsetYAML, "%s", "json-skillset", 1)), &skillsetJSON))
+	skillsetJSONBytes, _ := json.Marshal(skillsetJSON)
+
+	t.Run("skillset from YAML is stored like JSON", func(t *testing.T) {
+		rsp := postYAML("/skillsets", strings.Replace(skillsetYAML, "%s", "yaml-skillset", 1))
+		require.Equal(t, http.StatusCreated, rsp.StatusCode)
+		rsp = postJSON("/skillsets", string(skillsetJSONBytes))
+		require.Equal(t, http.StatusCreated, rsp.StatusCode)
+
+		assert.Equal(t, getWithoutName("/skillsets/json-skillset"), getWithoutName("/skillsets/yaml-skillset"))
+	})
+
+	t.Run("invalid YAML skillset is rejected by validation", func(t *testing.T) {
+		invalid := strings.Replace(strings.Replace(skillsetYAML, "%s", "invalid-skillset", 1), `  version: "1.0.0"`+"\n", "", 1)
+		rsp := postYAML("/skillsets", invalid)
+		assert.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+	})
+
+	t.Run("malformed YAML is rejected", func(t *testing.T) {
+		rsp := postYAML("/skillsets", "kind: [SkillSet")
+		assert.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+	})
+
+	viewYAML := `
+apiVersion: "0.1.0-alpha.1"
+kind: View
+metadata:
+  name: %s
+  catalog: yaml-catalog
+  description: A view
+spec:
+  rules:
+    - intent: Allow
+      actions:
+        - system.catalog.list
+      targets:
+        - res://variants/yaml-variant
+`
+	t.Run("view from YAML is stored like JSON", func(t *testing.T) {
+		var viewJSON map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(strings.Replace(viewYAML, "%s", "json-view", 1)), &viewJSON))
+		viewJSONBytes, _ := json.Marshal(viewJSON)
+
+		rsp := postYAML("/views", strings.Replace(viewYAML, "%s", "yaml-view", 1))
+		require.Equal(t, http.StatusCreated, rsp.StatusCode)
+		rsp = postJSON("/views", string(viewJSONBytes))
+		require.Equal(t, http.StatusCreated, rsp.StatusCode)
+
+		assert.Equal(t, getWithoutName("/views/json-view"), getWithoutName("/views/yaml-view"))
+	})
+
+	t.Run("response as YAML", func(t *testing.T) {
+		httpReq, _ := http.NewRequest("GET", "/skillsets/yaml-skillset", nil)
+		httpReq.Header.Set("Accept", "application/yaml")
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		assert.Equal(t, "application/yaml", response.Header().Get("Content-Type"))
+
+		var obj map[string]any
+		require.NoError(t, yaml.Unmarshal(response.Body.Bytes(), &obj))
+		assert.Equal(t, "SkillSet", obj["kind"])
+	})
+}
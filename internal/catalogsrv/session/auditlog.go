@@ -42,18 +42,21 @@ func WriteAuditLogFile(ctx context.Context, sessionID uuid.UUID, auditLog string
 	return logFilePath, nil
 }
 
-// EncodeAuditLogFile reads a plain or compressed log file and returns it base64 encoded.
-func EncodeAuditLogFile(ctx context.Context, sessionID uuid.UUID) (string, error) {
+// findAuditLogFile returns the path of the stored audit log for a session, preferring the compressed file.
+// Returns an empty string if no audit log has been written.
+func findAuditLogFile(sessionID uuid.UUID) string {
 	basePath := filepath.Join(config.Config().AuditLog.GetPath(), sessionID.String())
-	paths := []string{basePath + ".ztlog", basePath + ".tlog"}
-
-	var logFilePath string
-	for _, p := range paths {
+	for _, p := range []string{basePath + ".ztlog", basePath + ".tlog"} {
 		if _, err := os.Stat(p); err == nil {
-			logFilePath = p
-			break
+			return p
 		}
 	}
+	return ""
+}
+
+// EncodeAuditLogFile reads a plain or compressed log file and returns it base64 encoded.
+func EncodeAuditLogFile(ctx context.Context, sessionID uuid.UUID) (string, error) {
+	logFilePath := findAuditLogFile(sessionID)
 	if logFilePath == "" {
 		return "", fmt.Errorf("log file not found for session %s", sessionID)
 	}
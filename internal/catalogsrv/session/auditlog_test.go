@@ -3,15 +3,23 @@ package session
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang/snappy"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
 )
 
@@ -211,3 +219,240 @@ func TestDecodeAndUncompressAuditLogFileErrors(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "decompression failed")
 }
+
+func TestChunkedAuditLogUpload(t *testing.T) {
+	config.TestInit()
+	ctx := context.Background()
+
+	auditLogPath := config.Config().AuditLog.GetPath()
+	require.NoError(t, os.MkdirAll(auditLogPath, 0700))
+
+	var buf bytes.Buffer
+	snappyWriter := snappy.NewBufferedWriter(&buf)
+	_, err := snappyWriter.Write(bytes.Repeat([]byte("audit log entry\n"), 1024))
+	require.NoError(t, err)
+	require.NoError(t, snappyWriter.Close())
+	original := buf.Bytes()
+	encoded := base64.StdEncoding.EncodeToString(original)
+	sum := sha256.Sum256([]byte(encoded))
+	hash := hex.EncodeToString(sum[:])
+
+	const chunkSize = 100
+	var parts []string
+	for i := 0; i < len(encoded); i += chunkSize {
+		parts = append(parts, encoded[i:min(i+chunkSize, len(encoded))])
+	}
+	require.Greater(t, len(parts), 1)
+
+	t.Run("multi-part upload reassembles original", func(t *testing.T) {
+		sessionID := uuid.New()
+		uploadID, err := InitAuditLogUpload(ctx, sessionID)
+		require.NoError(t, err)
+
+		// upload out of order to ensure parts are assembled by number
+		for i := len(parts) - 1; i >= 0; i-- {
+			require.NoError(t, WriteAuditLogUploadPart(ctx, sessionID, uploadID, i, parts[i]))
+		}
+
+		logFilePath, err := CompleteAuditLogUpload(ctx, sessionID, uploadID, len(parts), hash)
+		require.NoError(t, err)
+		defer os.Remove(logFilePath)
+		assert.Equal(t, filepath.Join(auditLogPath, sessionID.String()+".ztlog"), logFilePath)
+
+		written, err := os.ReadFile(logFilePath)
+		require.NoError(t, err)
+		assert.Equal(t, original, written)
+		assert.Equal(t, logFilePath, findAuditLogFile(sessionID))
+
+		dir, err := auditLogUploadDir(sessionID, uploadID)
+		require.NoError(t, err)
+		_, err = os.Stat(dir)
+		assert.True(t, os.IsNotExist(err), "upload directory should be removed")
+		_, err = os.Stat(filepath.Dir(dir))
+		assert.True(t, os.IsNotExist(err), "session upload directory should be removed")
+	})
+
+	t.Run("aborted upload is removed", func(t *testing.T) {
+		sessionID := uuid.New()
+		uploadID, err := InitAuditLogUpload(ctx, sessionID)
+		require.NoError(t, err)
+		dir, err := auditLogUploadDir(sessionID, uploadID)
+		require.NoError(t, err)
+		defer os.RemoveAll(filepath.Dir(dir))
+		require.NoError(t, WriteAuditLogUploadPart(ctx, sessionID, uploadID, 0, parts[0]))
+
+		// another upload of the session in progress keeps the session directory
+		otherID, err := InitAuditLogUpload(ctx, sessionID)
+		require.NoError(t, err)
+
+		require.NoError(t, AbortAuditLogUpload(ctx, sessionID, uploadID))
+		_, err = os.Stat(dir)
+		assert.True(t, os.IsNotExist(err), "upload directory should be removed")
+		_, err = os.Stat(filepath.Dir(dir))
+		assert.NoError(t, err)
+
+		require.NoError(t, AbortAuditLogUpload(ctx, sessionID, otherID))
+		_, err = os.Stat(filepath.Dir(dir))
+		assert.True(t, os.IsNotExist(err), "session upload directory should be removed")
+
+		// aborting again is harmless
+		assert.NoError(t, AbortAuditLogUpload(ctx, sessionID, uploadID))
+	})
+
+	t.Run("missing part fails complete", func(t *testing.T) {
+		sessionID := uuid.New()
+		uploadID, err := InitAuditLogUpload(ctx, sessionID)
+		require.NoError(t, err)
+		dir, err := auditLogUploadDir(sessionID, uploadID)
+		require.NoError(t, err)
+		defer os.RemoveAll(filepath.Dir(dir))
+
+		for i, part := range parts {
+			if i == 1 {
+				continue
+			}
+			require.NoError(t, WriteAuditLogUploadPart(ctx, sessionID, uploadID, i, part))
+		}
+
+		_, err = CompleteAuditLogUpload(ctx, sessionID, uploadID, len(parts), hash)
+		assert.ErrorContains(t, err, "missing part 1")
+		assert.Empty(t, findAuditLogFile(sessionID))
+		_, err = os.Stat(dir)
+		assert.True(t, os.IsNotExist(err), "upload directory should be removed")
+	})
+
+	t.Run("hash mismatch fails complete", func(t *testing.T) {
+		sessionID := uuid.New()
+		uploadID, err := InitAuditLogUpload(ctx, sessionID)
+		require.NoError(t, err)
+		dir, err := auditLogUploadDir(sessionID, uploadID)
+		require.NoError(t, err)
+		defer os.RemoveAll(filepath.Dir(dir))
+
+		for i, part := range parts {
+			require.NoError(t, WriteAuditLogUploadPart(ctx, sessionID, uploadID, i, part))
+		}
+		_, err = CompleteAuditLogUpload(ctx, sessionID, uploadID, len(parts), "deadbeef")
+		assert.ErrorContains(t, err, "hash mismatch")
+		_, err = os.Stat(dir)
+		assert.True(t, os.IsNotExist(err), "upload directory should be removed")
+	})
+
+	t.Run("invalid upload ID", func(t *testing.T) {
+		err := WriteAuditLogUploadPart(ctx, uuid.New(), "../../etc", 0, "data")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetPolicyDecisions(t *testing.T) {
+	config.TestInit()
+	ctx := context.Background()
+	require.NoError(t, os.MkdirAll(config.Config().AuditLog.GetPath(), 0700))
+
+	entries := []string{
+		`{"payload":{"event":"session_start","time":1718000000000},"prevHash":"","hash":"h0","signature":"s0"}`,
+		`{"payload":{"event":"policy_decision","decision":"allowed","invocation_id":"inv-1","view":"dev-view","skill":"list_pods","actions":["kubernetes.pods.list"],"basis":{"allow":[{"intent":"Allow"}]},"time":1718000001000},"prevHash":"h0","hash":"h1","signature":"s1"}`,
+		`{"payload":{"event":"skill_end","status":"success","skill":"list_pods","time":1718000002000},"prevHash":"h1","hash":"h2","signature":"s2"}`,
+		`{"payload":{"event":"policy_decision","decision":"blocked","reason":"actions_not_authorized","invocation_id":"inv-2","view":"dev-view","skill":"restart_deployment","actions":["kubernetes.deployments.restart"],"time":1718000003000},"prevHash":"h2","hash":"h3","signature":"s3"}`,
+	}
+	var buf bytes.Buffer
+	snappyWriter := snappy.NewBufferedWriter(&buf)
+	for _, e := range entries {
+		_, err := snappyWriter.Write([]byte(e + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, snappyWriter.Close())
+
+	sessionID := uuid.New()
+	logPath, err := WriteAuditLogFile(ctx, sessionID, base64.StdEncoding.EncodeToString(buf.Bytes()))
+	require.NoError(t, err)
+	defer os.Remove(logPath)
+
+	newRequest := func(tokenSessionID uuid.UUID) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+sessionID.String()+"/decisions", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("sessionID", sessionID.String())
+		reqCtx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		return req.WithContext(catcommon.WithSessionID(reqCtx, tokenSessionID))
+	}
+
+	t.Run("allow and deny decisions are returned in order", func(t *testing.T) {
+		rsp, err := getPolicyDecisionsByID(newRequest(sessionID))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+		decisions := rsp.Response.(PolicyDecisionsRsp).Decisions
+		require.Len(t, decisions, 2)
+
+		assert.Equal(t, "allowed", decisions[0].Decision)
+		assert.Equal(t, "list_pods", decisions[0].Skill)
+		assert.Equal(t, []string{"kubernetes.pods.list"}, decisions[0].Actions)
+		assert.JSONEq(t, `{"allow":[{"intent":"Allow"}]}`, string(decisions[0].Basis))
+		assert.Equal(t, time.UnixMilli(1718000001000).UTC(), decisions[0].Time)
+
+		assert.Equal(t, "blocked", decisions[1].Decision)
+		assert.Equal(t, "actions_not_authorized", decisions[1].Reason)
+		assert.Equal(t, "restart_deployment", decisions[1].Skill)
+		assert.Equal(t, []string{"kubernetes.deployments.restart"}, decisions[1].Actions)
+		assert.Equal(t, int64(2), decisions[0].Seq)
+		assert.Equal(t, int64(4), decisions[1].Seq)
+	})
+
+	t.Run("decisions are paged with cursors", func(t *testing.T) {
+		req := newRequest(sessionID)
+		req.URL.RawQuery = "limit=1"
+		rsp, err := getPolicyDecisionsByID(req)
+		require.NoError(t, err)
+		first := rsp.Response.(PolicyDecisionsRsp)
+		require.Len(t, first.Decisions, 1)
+		assert.Equal(t, "allowed", first.Decisions[0].Decision)
+		assert.True(t, first.HasMore)
+
+		req = newRequest(sessionID)
+		req.URL.RawQuery = "limit=1&cursor=" + first.NextCursor
+		rsp, err = getPolicyDecisionsByID(req)
+		require.NoError(t, err)
+		second := rsp.Response.(PolicyDecisionsRsp)
+		require.Len(t, second.Decisions, 1)
+		assert.Equal(t, "blocked", second.Decisions[0].Decision)
+		assert.False(t, second.HasMore)
+	})
+
+	t.Run("token for another session is rejected", func(t *testing.T) {
+		_, err := getPolicyDecisionsByID(newRequest(uuid.New()))
+		assert.ErrorIs(t, err, ErrNotAuthorized)
+	})
+
+	t.Run("decisions are exported as CSV", func(t *testing.T) {
+		rsp, err := getPolicyDecisionsCSVByID(newRequest(sessionID))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+		assert.Equal(t, "text/csv", rsp.ContentType)
+		require.True(t, rsp.Chunked)
+
+		w := httptest.NewRecorder()
+		require.NoError(t, rsp.WriteChunks(w))
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"skill", "actions", "decision", "timestamp", "view", "reason", "invocation_id"},
+			{"list_pods", "kubernetes.pods.list", "allowed", "2024-06-10T06:13:21Z", "dev-view", "", "inv-1"},
+			{"restart_deployment", "kubernetes.deployments.restart", "blocked", "2024-06-10T06:13:23Z", "dev-view", "actions_not_authorized", "inv-2"},
+		}, records)
+	})
+
+	t.Run("CSV export requires a token for the session", func(t *testing.T) {
+		_, err := getPolicyDecisionsCSVByID(newRequest(uuid.New()))
+		assert.ErrorIs(t, err, ErrNotAuthorized)
+	})
+
+	t.Run("CSV export of a session without an audit log", func(t *testing.T) {
+		otherID := uuid.New()
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+otherID.String()+"/decisions.csv", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("sessionID", otherID.String())
+		req = req.WithContext(catcommon.WithSessionID(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), otherID))
+		_, err := getPolicyDecisionsCSVByID(req)
+		assert.ErrorIs(t, err, ErrAuditLogNotFound)
+	})
+}
@@ -0,0 +1,170 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// AuditLogNameHeader carries the file name of an audit log shipped to an external sink.
+const AuditLogNameHeader = "X-Tansive-Audit-Log-Name"
+
+var (
+	// auditLogSinkClient is the client used to ship audit logs to external sinks. It does not
+	// follow redirects and refuses to connect to link-local addresses such as cloud metadata
+	// endpoints, even if an allowed host resolves to one.
+	auditLogSinkClient = &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:       http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{Timeout: 10 * time.Second, Control: rejectLinkLocalAddress}).DialContext,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	// auditLogExportRetryDelay is the initial delay between audit log export attempts
+	auditLogExportRetryDelay = 1 * time.Second
+	// pendingAuditLogExports tracks audit logs being exported in the background
+	pendingAuditLogExports sync.WaitGroup
+)
+
+// WaitForBackgroundDeliveries blocks until the session callbacks and audit log exports
+// running in the background have finished, or until ctx is done.
+func WaitForBackgroundDeliveries(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		pendingCallbacks.Wait()
+		pendingAuditLogExports.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rejectLinkLocalAddress fails connections to link-local, multicast and unspecified addresses.
+func rejectLinkLocalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("audit log sink address %s is not allowed", host)
+	}
+	return nil
+}
+
+// exportAuditLog ships a stored audit log to the destination configured on the session's catalog.
+// The destination is resolved before returning and the log is delivered in the background.
+// It does nothing if the catalog has no audit log destination.
+func exportAuditLog(ctx context.Context, catalogID, sessionID uuid.UUID, logFilePath string) error {
+	catalog, err := db.DB(ctx).GetCatalogByID(ctx, catalogID)
+	if err != nil {
+		return fmt.Errorf("failed to load catalog: %w", err)
+	}
+	spec, err := catalogmanager.GetCatalogSpec(catalog)
+	if err != nil {
+		return err
+	}
+	if spec == nil || spec.AuditLog == nil || spec.AuditLog.Destination == "" {
+		return nil
+	}
+
+	dest, goerr := catalogmanager.ResolveAuditLogDestination(spec.AuditLog.Destination, catalogmanager.AuditLogDestinationVars{
+		Tenant:    string(catcommon.GetTenantID(ctx)),
+		Project:   string(catalog.ProjectID),
+		Catalog:   catalog.Name,
+		SessionID: sessionID.String(),
+	})
+	if goerr != nil {
+		return goerr
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	pendingAuditLogExports.Add(1)
+	go func() {
+		defer pendingAuditLogExports.Done()
+		if err := deliverAuditLog(ctx, dest, logFilePath); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("session_id", sessionID.String()).Msg("failed to export audit log to catalog destination")
+		}
+	}()
+	return nil
+}
+
+// deliverAuditLog copies the audit log to a file destination or posts it to an external sink,
+// retrying up to the configured number of attempts.
+func deliverAuditLog(ctx context.Context, dest *url.URL, logFilePath string) error {
+	return retry.Do(func() error {
+		f, err := os.Open(logFilePath)
+		if err != nil {
+			return retry.Unrecoverable(fmt.Errorf("failed to open audit log: %w", err))
+		}
+		defer f.Close()
+		if dest.Scheme == "file" {
+			return copyAuditLog(dest, f)
+		}
+		return postAuditLog(ctx, dest, f)
+	},
+		retry.Context(ctx),
+		retry.Attempts(uint(config.Config().AuditLog.ExportMaxAttempts)),
+		retry.Delay(auditLogExportRetryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true))
+}
+
+// copyAuditLog writes the audit log into the directory of a file destination.
+func copyAuditLog(dest *url.URL, f *os.File) error {
+	dir := filepath.FromSlash(dest.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create audit log destination: %w", err)
+	}
+	out, err := os.OpenFile(filepath.Join(dir, filepath.Base(f.Name())), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log file: %w", err)
+	}
+	if _, err := io.Copy(out, f); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy audit log: %w", err)
+	}
+	return out.Close()
+}
+
+// postAuditLog posts the audit log to an external sink.
+func postAuditLog(ctx context.Context, dest *url.URL, f *os.File) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dest.String(), f)
+	if err != nil {
+		return retry.Unrecoverable(fmt.Errorf("failed to create sink request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set(AuditLogNameHeader, filepath.Base(f.Name()))
+	rsp, err := auditLogSinkClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to ship audit log: %w", err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("audit log sink returned status %d", rsp.StatusCode)
+	}
+	return nil
+}
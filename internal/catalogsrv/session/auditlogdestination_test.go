@@ -0,0 +1,147 @@
+package session
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+)
+
+// setupAuditLogExportTest allows file destinations under baseDir and sinks on the loopback
+// test server, and shortens the delay between export attempts.
+func setupAuditLogExportTest(t *testing.T, baseDir string) {
+	t.Helper()
+	config.TestInit()
+	origDir, origHosts, origAttempts := config.Config().AuditLog.ExportBaseDir, config.Config().AuditLog.ExportAllowedHosts, config.Config().AuditLog.ExportMaxAttempts
+	origDelay := auditLogExportRetryDelay
+	t.Cleanup(func() {
+		config.Config().AuditLog.ExportBaseDir = origDir
+		config.Config().AuditLog.ExportAllowedHosts = origHosts
+		config.Config().AuditLog.ExportMaxAttempts = origAttempts
+		auditLogExportRetryDelay = origDelay
+	})
+	config.Config().AuditLog.ExportBaseDir = baseDir
+	config.Config().AuditLog.ExportAllowedHosts = []string{"127.0.0.1"}
+	config.Config().AuditLog.ExportMaxAttempts = 3
+	auditLogExportRetryDelay = time.Millisecond
+}
+
+func TestAuditLogDestinationPerCatalog(t *testing.T) {
+	ctx := context.Background()
+	logDir := t.TempDir()
+	logFile := filepath.Join(logDir, "session-1.tlog")
+	require.NoError(t, os.WriteFile(logFile, []byte("audit log contents"), 0600))
+
+	var sinkBody []byte
+	var sinkPath, sinkName string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sinkPath = r.URL.Path
+		sinkName = r.Header.Get(AuditLogNameHeader)
+		sinkBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	bucket := t.TempDir()
+	setupAuditLogExportTest(t, bucket)
+	catalogs := map[string]string{
+		"finance": "file://" + filepath.ToSlash(bucket) + "/{tenant}/{catalog}",
+		"hr":      sink.URL + "/ingest/{catalog}/{session}",
+	}
+
+	for catalog, template := range catalogs {
+		require.NoError(t, catalogmanager.ValidateAuditLogDestination(template))
+		dest, err := catalogmanager.ResolveAuditLogDestination(template, catalogmanager.AuditLogDestinationVars{
+			Tenant:    "tenant1",
+			Catalog:   catalog,
+			SessionID: "session-1",
+		})
+		require.NoError(t, err)
+		require.NoError(t, deliverAuditLog(ctx, dest, logFile))
+	}
+
+	data, err := os.ReadFile(filepath.Join(bucket, "tenant1", "finance", "session-1.tlog"))
+	require.NoError(t, err)
+	assert.Equal(t, "audit log contents", string(data))
+	_, err = os.Stat(filepath.Join(bucket, "tenant1", "hr"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Equal(t, "/ingest/hr/session-1", sinkPath)
+	assert.Equal(t, "session-1.tlog", sinkName)
+	assert.Equal(t, "audit log contents", string(sinkBody))
+}
+
+func TestAuditLogSinkFailure(t *testing.T) {
+	setupAuditLogExportTest(t, "")
+	logFile := filepath.Join(t.TempDir(), "session-1.tlog")
+	require.NoError(t, os.WriteFile(logFile, []byte("audit log contents"), 0600))
+
+	var calls atomic.Int32
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer sink.Close()
+
+	dest, err := catalogmanager.ResolveAuditLogDestination(sink.URL+"/{catalog}", catalogmanager.AuditLogDestinationVars{Catalog: "hr"})
+	require.NoError(t, err)
+	assert.Error(t, deliverAuditLog(context.Background(), dest, logFile))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestAuditLogSinkRetry(t *testing.T) {
+	setupAuditLogExportTest(t, "")
+	logFile := filepath.Join(t.TempDir(), "session-1.tlog")
+	require.NoError(t, os.WriteFile(logFile, []byte("audit log contents"), 0600))
+
+	var calls atomic.Int32
+	var body []byte
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	dest, err := catalogmanager.ResolveAuditLogDestination(sink.URL+"/{catalog}", catalogmanager.AuditLogDestinationVars{Catalog: "hr"})
+	require.NoError(t, err)
+	require.NoError(t, deliverAuditLog(context.Background(), dest, logFile))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "audit log contents", string(body))
+}
+
+func TestAuditLogSinkRejectsLinkLocalAddress(t *testing.T) {
+	assert.Error(t, rejectLinkLocalAddress("tcp", "169.254.169.254:80", nil))
+	assert.Error(t, rejectLinkLocalAddress("tcp6", "[fe80::1]:80", nil))
+	assert.Error(t, rejectLinkLocalAddress("tcp", "0.0.0.0:80", nil))
+	assert.NoError(t, rejectLinkLocalAddress("tcp", "10.1.2.3:443", nil))
+}
+
+func TestWaitForBackgroundDeliveries(t *testing.T) {
+	release := make(chan struct{})
+	pendingAuditLogExports.Add(1)
+	go func() {
+		defer pendingAuditLogExports.Done()
+		<-release
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, WaitForBackgroundDeliveries(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, WaitForBackgroundDeliveries(context.Background()))
+}
@@ -0,0 +1,241 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// auditLogPruneBatchSize is the number of sessions pruned per database query
+const auditLogPruneBatchSize = 100
+
+// prunableSessionStatuses are the status summaries of sessions whose audit logs may be pruned
+var prunableSessionStatuses = []string{
+	string(SessionStatusCompleted),
+	string(SessionStatusFailed),
+	string(SessionStatusExpired),
+	string(SessionStatusCancelled),
+	string(SessionStatusTerminated),
+	string(SessionStatusStale),
+}
+
+// AuditLogTombstone is kept in place of an audit log that was removed by the retention policy.
+// It retains the verification key and the hash of the removed log so that integrity claims
+// made with a copy of the log can still be checked.
+type AuditLogTombstone struct {
+	SessionID               uuid.UUID          `json:"sessionID"`
+	TenantID                catcommon.TenantId `json:"tenantID"`
+	StatusSummary           SessionStatus      `json:"statusSummary"`
+	EndedAt                 time.Time          `json:"endedAt"`
+	PrunedAt                time.Time          `json:"prunedAt"`
+	AuditLogHash            string             `json:"auditLogHash,omitempty"` // hex encoded SHA256 of the stored audit log
+	AuditLogVerificationKey []byte             `json:"auditLogVerificationKey"`
+}
+
+// auditLogTombstonePath returns the path of the tombstone of a session's pruned audit log.
+func auditLogTombstonePath(sessionID uuid.UUID) string {
+	return filepath.Join(config.Config().AuditLog.GetPath(), sessionID.String()+".tombstone.json")
+}
+
+// GetAuditLogTombstone returns the tombstone of a session's pruned audit log.
+// Returns os.ErrNotExist if the session's audit log has not been pruned.
+func GetAuditLogTombstone(sessionID uuid.UUID) (*AuditLogTombstone, error) {
+	data, err := os.ReadFile(auditLogTombstonePath(sessionID))
+	if err != nil {
+		return nil, err
+	}
+	var tombstone AuditLogTombstone
+	if err := json.Unmarshal(data, &tombstone); err != nil {
+		return nil, fmt.Errorf("invalid audit log tombstone: %w", err)
+	}
+	return &tombstone, nil
+}
+
+func writeAuditLogTombstone(tombstone *AuditLogTombstone) error {
+	data, err := json.Marshal(tombstone)
+	if err != nil {
+		return err
+	}
+	path := auditLogTombstonePath(tombstone.SessionID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write audit log tombstone: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// hashFile returns the hex encoded SHA256 of a file.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// pruneSessionAuditLog removes the stored audit log, transcript and upload staging files of a
+// session. The tombstone is written before anything is removed, so a failed prune never loses
+// the verification key.
+func pruneSessionAuditLog(session *models.Session, prunedAt time.Time) (*AuditLogTombstone, error) {
+	var status ExecutionStatus
+	if len(session.Status) > 0 {
+		if err := json.Unmarshal(session.Status, &status); err != nil {
+			return nil, fmt.Errorf("invalid session status: %w", err)
+		}
+	}
+
+	tombstone := &AuditLogTombstone{
+		SessionID:               session.SessionID,
+		TenantID:                session.TenantID,
+		StatusSummary:           SessionStatus(session.StatusSummary),
+		EndedAt:                 session.EndedAt,
+		PrunedAt:                prunedAt,
+		AuditLogVerificationKey: status.AuditLogVerificationKey,
+	}
+	logFilePath := findAuditLogFile(session.SessionID)
+	if logFilePath != "" {
+		hash, err := hashFile(logFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash audit log: %w", err)
+		}
+		tombstone.AuditLogHash = hash
+	}
+	if err := writeAuditLogTombstone(tombstone); err != nil {
+		return nil, err
+	}
+
+	basePath := filepath.Join(config.Config().AuditLog.GetPath(), session.SessionID.String())
+	for _, p := range []string{basePath + ".ztlog", basePath + ".tlog", transcriptFilePath(session.SessionID)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(config.Config().AuditLog.GetPath(), "uploads", session.SessionID.String())); err != nil {
+		return nil, fmt.Errorf("failed to remove audit log uploads: %w", err)
+	}
+	return tombstone, nil
+}
+
+// PruneAuditLogs removes the audit logs of sessions that ended longer than the configured
+// retention period before now, leaving a tombstone for each. If audit_log.prune_sessions is set,
+// the session records are deleted as well; otherwise the audit log is cleared from the session status.
+// Returns the number of audit logs pruned. Does nothing if no retention period is configured.
+// The context must carry a database connection.
+func PruneAuditLogs(ctx context.Context, now time.Time) (int, error) {
+	retention := config.Config().AuditLog.GetRetention()
+	if retention <= 0 {
+		return 0, nil
+	}
+	cutoff := now.Add(-retention)
+
+	pruned := 0
+	for {
+		sessions, err := db.DB(ctx).ListPrunableSessions(ctx, cutoff, prunableSessionStatuses, auditLogPruneBatchSize)
+		if err != nil {
+			return pruned, err
+		}
+		progressed := false
+		for _, s := range sessions {
+			if err := pruneSession(ctx, s, now); err != nil {
+				log.Ctx(ctx).Error().Err(err).Str("session_id", s.SessionID.String()).Msg("failed to prune audit log")
+				continue
+			}
+			progressed = true
+			pruned++
+		}
+		// stop when all prunable sessions were seen, or none in this batch could be pruned
+		if len(sessions) < auditLogPruneBatchSize || !progressed {
+			return pruned, nil
+		}
+	}
+}
+
+// pruneSession prunes the audit log of a single session and updates or deletes its record.
+func pruneSession(ctx context.Context, s *models.Session, now time.Time) error {
+	if _, err := pruneSessionAuditLog(s, now); err != nil {
+		return err
+	}
+
+	ctx = catcommon.WithTenantID(ctx, s.TenantID)
+	if config.Config().AuditLog.PruneSessions {
+		return db.DB(ctx).DeleteSession(ctx, s.SessionID)
+	}
+
+	var status ExecutionStatus
+	if len(s.Status) > 0 {
+		if err := json.Unmarshal(s.Status, &status); err != nil {
+			return fmt.Errorf("invalid session status: %w", err)
+		}
+	}
+	status.AuditLog = ""
+	prunedAt := now
+	status.AuditLogPrunedAt = &prunedAt
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := db.DB(ctx).UpdateSessionStatus(ctx, s.SessionID, s.StatusSummary, statusJSON); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RunAuditLogJanitor prunes expired audit logs every audit_log.prune_interval until ctx is done.
+// Returns immediately if no retention period is configured.
+func RunAuditLogJanitor(ctx context.Context) {
+	if config.Config().AuditLog.GetRetention() <= 0 {
+		return
+	}
+	interval, err := config.Config().AuditLog.GetPruneInterval()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("invalid audit log prune interval")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runAuditLogPrune(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runAuditLogPrune(ctx context.Context) {
+	dbCtx, err := db.ConnCtx(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to get db connection for audit log pruning")
+		return
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	pruned, err := PruneAuditLogs(dbCtx, time.Now())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to prune audit logs")
+	}
+	if pruned > 0 {
+		log.Ctx(ctx).Info().Int("count", pruned).Msg("pruned expired audit logs")
+	}
+}
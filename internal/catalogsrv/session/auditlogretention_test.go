@@ -0,0 +1,201 @@
+package session
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// writeTestAuditLog stores an audit log for a session and returns its status.
+func writeTestAuditLog(t *testing.T, sessionID uuid.UUID) ExecutionStatus {
+	t.Helper()
+	logFilePath, err := WriteAuditLogFile(context.Background(), sessionID, base64.StdEncoding.EncodeToString([]byte("audit log of "+sessionID.String())))
+	require.NoError(t, err)
+	return ExecutionStatus{
+		AuditLog:                logFilePath,
+		AuditLogVerificationKey: []byte("key-" + sessionID.String()),
+	}
+}
+
+func cleanupAuditLogFiles(sessionID uuid.UUID) {
+	if p := findAuditLogFile(sessionID); p != "" {
+		os.Remove(p)
+	}
+	os.Remove(transcriptFilePath(sessionID))
+	os.Remove(auditLogTombstonePath(sessionID))
+}
+
+func TestPruneSessionAuditLog(t *testing.T) {
+	config.TestInit()
+	sessionID := uuid.New()
+	defer cleanupAuditLogFiles(sessionID)
+
+	status := writeTestAuditLog(t, sessionID)
+	require.NoError(t, WriteTranscript(sessionID, &Transcript{Chunks: []TranscriptChunk{{Stream: TranscriptStreamStdout, Data: "output"}}}))
+	wantHash, err := hashFile(status.AuditLog)
+	require.NoError(t, err)
+	statusJSON, err := json.Marshal(status)
+	require.NoError(t, err)
+
+	_, err = GetAuditLogTombstone(sessionID)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	endedAt := time.Now().Add(-48 * time.Hour).UTC()
+	prunedAt := time.Now().UTC()
+	tombstone, err := pruneSessionAuditLog(&models.Session{
+		SessionID:     sessionID,
+		TenantID:      "TABCDE",
+		StatusSummary: string(SessionStatusCompleted),
+		Status:        statusJSON,
+		EndedAt:       endedAt,
+	}, prunedAt)
+	require.NoError(t, err)
+
+	assert.Empty(t, findAuditLogFile(sessionID))
+	_, err = GetTranscript(sessionID)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	stored, err := GetAuditLogTombstone(sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, tombstone, stored)
+	assert.Equal(t, status.AuditLogVerificationKey, stored.AuditLogVerificationKey)
+	assert.Equal(t, wantHash, stored.AuditLogHash)
+	assert.Equal(t, SessionStatusCompleted, stored.StatusSummary)
+	assert.True(t, endedAt.Equal(stored.EndedAt))
+	assert.True(t, prunedAt.Equal(stored.PrunedAt))
+}
+
+func TestPruneAuditLogs(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	auditLogConfig := config.Config().AuditLog
+	defer func() { config.Config().AuditLog = auditLogConfig }()
+	config.Config().AuditLog.RetentionDays = 30
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{Name: "test-catalog", ProjectID: projectID, Info: pgtype.JSONB{Status: pgtype.Null}}
+	require.NoError(t, db.DB(ctx).CreateCatalog(ctx, &catalog))
+	defer db.DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	now := time.Now()
+	createSession := func(statusSummary SessionStatus, endedAt time.Time) (uuid.UUID, ExecutionStatus) {
+		sessionID := uuid.New()
+		t.Cleanup(func() { cleanupAuditLogFiles(sessionID) })
+		status := writeTestAuditLog(t, sessionID)
+		statusJSON, err := json.Marshal(status)
+		require.NoError(t, err)
+		require.NoError(t, db.DB(ctx).UpsertSession(ctx, &models.Session{
+			SessionID:     sessionID,
+			SkillSet:      "test-skillset",
+			Skill:         "test-skill",
+			ViewID:        uuid.New(),
+			TangentID:     uuid.New(),
+			StatusSummary: string(statusSummary),
+			Status:        statusJSON,
+			UserID:        "users/testuser",
+			CatalogID:     catalog.CatalogID,
+			VariantID:     uuid.New(),
+			StartedAt:     endedAt.Add(-time.Hour),
+			EndedAt:       endedAt,
+			ExpiresAt:     endedAt.Add(time.Hour),
+		}))
+		return sessionID, status
+	}
+
+	t.Run("logs past retention are replaced by tombstones", func(t *testing.T) {
+		oldID, oldStatus := createSession(SessionStatusCompleted, now.Add(-40*24*time.Hour))
+		recentID, _ := createSession(SessionStatusFailed, now.Add(-24*time.Hour))
+		// a session that has not ended yet has no meaningful end time
+		runningID, _ := createSession(SessionStatusRunning, time.Time{})
+
+		pruned, err := PruneAuditLogs(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, pruned)
+
+		assert.Empty(t, findAuditLogFile(oldID))
+		assert.NotEmpty(t, findAuditLogFile(recentID))
+		assert.NotEmpty(t, findAuditLogFile(runningID))
+
+		tombstone, err := GetAuditLogTombstone(oldID)
+		require.NoError(t, err)
+		assert.Equal(t, oldStatus.AuditLogVerificationKey, tombstone.AuditLogVerificationKey)
+		assert.Equal(t, tenantID, tombstone.TenantID)
+		_, err = GetAuditLogTombstone(recentID)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		// the session record is kept without the audit log
+		session, err := db.DB(ctx).GetSession(ctx, oldID)
+		require.NoError(t, err)
+		var status ExecutionStatus
+		require.NoError(t, json.Unmarshal(session.Status, &status))
+		assert.Empty(t, status.AuditLog)
+		assert.NotNil(t, status.AuditLogPrunedAt)
+		assert.Equal(t, oldStatus.AuditLogVerificationKey, status.AuditLogVerificationKey)
+
+		// pruning again finds nothing
+		pruned, err = PruneAuditLogs(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, pruned)
+	})
+
+	t.Run("session records are deleted when configured", func(t *testing.T) {
+		config.Config().AuditLog.PruneSessions = true
+		defer func() { config.Config().AuditLog.PruneSessions = false }()
+
+		oldID, oldStatus := createSession(SessionStatusCancelled, now.Add(-31*24*time.Hour))
+
+		pruned, err := PruneAuditLogs(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, pruned)
+
+		_, err = db.DB(ctx).GetSession(ctx, oldID)
+		assert.Error(t, err)
+
+		// the verification key is still available from the tombstone
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+oldID.String()+"/auditlog/verification-key", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("sessionID", oldID.String())
+		req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+		rsp, err := getAuditLogVerificationKeyByID(req)
+		require.NoError(t, err)
+		assert.Equal(t, AuditLogVerificationKey{Key: oldStatus.AuditLogVerificationKey}, rsp.Response)
+
+		_, err = getAuditLogByID(req)
+		assert.ErrorIs(t, err, ErrAuditLogPruned)
+	})
+
+	t.Run("no retention keeps all logs", func(t *testing.T) {
+		config.Config().AuditLog.RetentionDays = 0
+		oldID, _ := createSession(SessionStatusCompleted, now.Add(-400*24*time.Hour))
+
+		pruned, err := PruneAuditLogs(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, pruned)
+		assert.NotEmpty(t, findAuditLogFile(oldID))
+	})
+}
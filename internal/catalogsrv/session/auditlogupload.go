@@ -0,0 +1,229 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// MaxAuditLogUploadParts is the maximum number of parts accepted for a single audit log upload.
+const MaxAuditLogUploadParts = 10000
+
+// AuditLogUploadInitRsp is returned when a chunked audit log upload is started.
+type AuditLogUploadInitRsp struct {
+	UploadID string `json:"uploadID"`
+}
+
+// AuditLogUploadPart carries one part of a base64-encoded audit log.
+type AuditLogUploadPart struct {
+	Data string `json:"data"`
+}
+
+// AuditLogUploadComplete finalizes a chunked upload. Hash is the hex encoded SHA256
+// of the reassembled base64-encoded audit log.
+type AuditLogUploadComplete struct {
+	Parts int    `json:"parts"`
+	Hash  string `json:"hash"`
+}
+
+// auditLogUploadDir returns the staging directory for an upload after validating the upload ID.
+func auditLogUploadDir(sessionID uuid.UUID, uploadID string) (string, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return "", fmt.Errorf("invalid upload ID: %s", uploadID)
+	}
+	return filepath.Join(config.Config().AuditLog.GetPath(), "uploads", sessionID.String(), uploadID), nil
+}
+
+// InitAuditLogUpload creates a staging area for a chunked audit log upload and returns its ID.
+func InitAuditLogUpload(ctx context.Context, sessionID uuid.UUID) (string, error) {
+	uploadID := uuid.New().String()
+	dir, err := auditLogUploadDir(sessionID, uploadID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	return uploadID, nil
+}
+
+// WriteAuditLogUploadPart stores a single part of a chunked audit log upload.
+// Re-sending a part overwrites the previous copy so that uploads can be resumed.
+func WriteAuditLogUploadPart(ctx context.Context, sessionID uuid.UUID, uploadID string, partNumber int, data string) error {
+	if partNumber < 0 || partNumber >= MaxAuditLogUploadParts {
+		return fmt.Errorf("invalid part number: %d", partNumber)
+	}
+	if data == "" {
+		return errors.New("audit log part is empty")
+	}
+	dir, err := auditLogUploadDir(sessionID, uploadID)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("upload not found: %s", uploadID)
+	}
+	partPath := filepath.Join(dir, strconv.Itoa(partNumber)+".part")
+	if err := os.WriteFile(partPath, []byte(data), 0600); err != nil {
+		return fmt.Errorf("failed to write part: %w", err)
+	}
+	return nil
+}
+
+// AbortAuditLogUpload removes the staging area of a chunked audit log upload that will not be
+// completed. Aborting an upload that does not exist is not an error.
+func AbortAuditLogUpload(ctx context.Context, sessionID uuid.UUID, uploadID string) error {
+	dir, err := auditLogUploadDir(sessionID, uploadID)
+	if err != nil {
+		return err
+	}
+	removeAuditLogUploadDir(dir)
+	return nil
+}
+
+// removeAuditLogUploadDir removes the staging directory of an upload, and the directory holding
+// the uploads of the session once no other upload of the session is in progress.
+func removeAuditLogUploadDir(dir string) {
+	os.RemoveAll(dir)
+	// fails while the directory still holds other uploads
+	os.Remove(filepath.Dir(dir))
+}
+
+// CompleteAuditLogUpload reassembles the uploaded parts in order, verifies the hash and
+// writes the audit log for the session. Returns the path of the written audit log.
+// The staging area is removed whether or not the upload completes successfully.
+func CompleteAuditLogUpload(ctx context.Context, sessionID uuid.UUID, uploadID string, parts int, hash string) (string, error) {
+	if parts <= 0 || parts > MaxAuditLogUploadParts {
+		return "", fmt.Errorf("invalid number of parts: %d", parts)
+	}
+	dir, err := auditLogUploadDir(sessionID, uploadID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("upload not found: %s", uploadID)
+	}
+	defer removeAuditLogUploadDir(dir)
+
+	var sb strings.Builder
+	for i := 0; i < parts; i++ {
+		data, err := os.ReadFile(filepath.Join(dir, strconv.Itoa(i)+".part"))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("missing part %d", i)
+			}
+			return "", fmt.Errorf("failed to read part %d: %w", i, err)
+		}
+		sb.Write(data)
+	}
+
+	auditLog := sb.String()
+	sum := sha256.Sum256([]byte(auditLog))
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), hash) {
+		return "", errors.New("audit log hash mismatch")
+	}
+
+	logFilePath, err := WriteAuditLogFile(ctx, sessionID, auditLog)
+	if err != nil {
+		return "", err
+	}
+	return logFilePath, nil
+}
+
+func initAuditLogUpload(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+	uploadID, err := InitAuditLogUpload(ctx, sessionID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to initialize audit log upload")
+		return nil, err
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Response:   &AuditLogUploadInitRsp{UploadID: uploadID},
+	}, nil
+}
+
+func putAuditLogUploadPart(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+	partNumber, err := strconv.Atoi(chi.URLParam(r, "partNumber"))
+	if err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid part number")
+	}
+	var part AuditLogUploadPart
+	if r.Body == nil {
+		return nil, ErrInvalidRequest.Msg("request body is required")
+	}
+	if err := json.NewDecoder(r.Body).Decode(&part); err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid request body")
+	}
+	if err := WriteAuditLogUploadPart(ctx, sessionID, chi.URLParam(r, "uploadID"), partNumber, part.Data); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to write audit log part")
+		return nil, ErrInvalidRequest.Msg(err.Error())
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   &AuditLogUploadPart{},
+	}, nil
+}
+
+func abortAuditLogUpload(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+	if err := AbortAuditLogUpload(ctx, sessionID, chi.URLParam(r, "uploadID")); err != nil {
+		return nil, ErrInvalidRequest.Msg(err.Error())
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+func completeAuditLogUpload(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+	var req AuditLogUploadComplete
+	if r.Body == nil {
+		return nil, ErrInvalidRequest.Msg("request body is required")
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid request body")
+	}
+	logFilePath, err := CompleteAuditLogUpload(ctx, sessionID, chi.URLParam(r, "uploadID"), req.Parts, req.Hash)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to complete audit log upload")
+		return nil, ErrInvalidRequest.Msg(err.Error())
+	}
+	log.Ctx(ctx).Info().Msgf("wrote audit log to %s", logFilePath)
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   &AuditLogUploadComplete{},
+	}, nil
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Page sizes for endpoints that list session history.
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// pageCursor is the content of an opaque pagination cursor. It holds the sequence number of
+// the last item the client has seen, so that items appended while paging are neither skipped
+// nor returned twice.
+type pageCursor struct {
+	Seq int64 `json:"seq"`
+}
+
+// encodeCursor returns an opaque cursor for the item with sequence number seq.
+func encodeCursor(seq int64) string {
+	data, _ := json.Marshal(pageCursor{Seq: seq})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the sequence number held in a cursor. An empty cursor starts at the beginning.
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Seq < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return c.Seq, nil
+}
+
+// parsePageParams reads the cursor and limit query parameters of a listing request.
+func parsePageParams(r *http.Request) (after int64, limit int, err error) {
+	after, err = decodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return 0, 0, err
+	}
+	limit = DefaultPageSize
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		limit = min(limit, MaxPageSize)
+	}
+	return after, limit, nil
+}
+
+// paginate returns up to limit items whose sequence number is greater than after, with the
+// cursor to pass for the next page and whether more items were available. items must be
+// ordered by sequence number. The next cursor is returned even when the page is empty or
+// short, so clients can poll for items appended later.
+func paginate[T any](items []T, seq func(T) int64, after int64, limit int) (page []T, next string, hasMore bool) {
+	page = []T{}
+	last := after
+	for _, item := range items {
+		s := seq(item)
+		if s <= after {
+			continue
+		}
+		if len(page) == limit {
+			hasMore = true
+			break
+		}
+		page = append(page, item)
+		last = s
+	}
+	return page, encodeCursor(last), hasMore
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	seq, err := decodeCursor(encodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), seq)
+
+	seq, err = decodeCursor("")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), seq)
+
+	for _, invalid := range []string{"not base64!", "bm90IGpzb24", encodeCursor(-1)} {
+		_, err := decodeCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPaginateAcrossConcurrentAppends(t *testing.T) {
+	const total = 200
+
+	var mu sync.Mutex
+	var chunks []TranscriptChunk
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= total; i++ {
+			mu.Lock()
+			chunks = append(chunks, TranscriptChunk{Seq: int64(i), Stream: TranscriptStreamStdout})
+			mu.Unlock()
+			if i%10 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	seen := make(map[int64]int)
+	// drained reports whether the writer has finished and every chunk has been read
+	drained := func() bool {
+		select {
+		case <-done:
+		default:
+			return false
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return len(seen) >= len(chunks)
+	}
+
+	cursor := ""
+	for !drained() {
+		after, err := decodeCursor(cursor)
+		require.NoError(t, err)
+		mu.Lock()
+		snapshot := append([]TranscriptChunk(nil), chunks...)
+		mu.Unlock()
+
+		page, next, _ := paginate(snapshot, func(c TranscriptChunk) int64 { return c.Seq }, after, 7)
+		for _, c := range page {
+			seen[c.Seq]++
+		}
+		cursor = next
+		if len(page) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	require.Len(t, seen, total)
+	for seq := int64(1); seq <= total; seq++ {
+		assert.Equal(t, 1, seen[seq], "chunk %d", seq)
+	}
+}
@@ -16,4 +16,9 @@ var (
 	ErrNotAuthorized      apperrors.Error = ErrSessionError.New("not authorized").SetStatusCode(http.StatusForbidden)
 	ErrInvalidRequest     apperrors.Error = ErrSessionError.New("invalid request").SetStatusCode(http.StatusBadRequest)
 	ErrUnableToGetSession apperrors.Error = ErrSessionError.New("unable to get session").SetStatusCode(http.StatusBadRequest)
+	ErrAuditLogNotFound   apperrors.Error = ErrSessionError.New("audit log not found").SetStatusCode(http.StatusNotFound)
+	ErrTranscriptNotFound apperrors.Error = ErrSessionError.New("transcript not found").SetStatusCode(http.StatusNotFound)
+	ErrAuditLogPruned     apperrors.Error = ErrSessionError.New("audit log was removed by the retention policy").SetStatusCode(http.StatusGone)
+	ErrTangentNotFound    apperrors.Error = ErrSessionError.New("tangent not found").SetStatusCode(http.StatusNotFound)
+	ErrSessionNotActive   apperrors.Error = ErrSessionError.New("session is not active").SetStatusCode(http.StatusConflict)
 )
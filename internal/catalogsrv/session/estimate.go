@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpx"
+)
+
+// SessionEstimate describes what running a skill involves, as declared by its skillset. It lets
+// orchestrators decide whether to run an expensive skill before creating a session for it.
+type SessionEstimate struct {
+	Skill           string             `json:"skill"`
+	Source          string             `json:"source"`
+	Runner          catcommon.RunnerID `json:"runner"`
+	CPULimitSeconds int                `json:"cpuLimitSeconds,omitempty"` // CPU time limit of the runner, 0 if not limited
+	MemoryLimitMB   int                `json:"memoryLimitMB,omitempty"`   // memory limit of the runner, 0 if not limited
+	MaxConcurrent   int                `json:"maxConcurrent,omitempty"`
+	MaxRestarts     int                `json:"maxRestarts,omitempty"`
+	DependencyCount int                `json:"dependencyCount"` // dependencies required with the session's variables
+	Transform       bool               `json:"transform"`       // whether the skill's input is transformed
+	Preflight       string             `json:"preflight,omitempty"`
+}
+
+// EstimateSession runs the checks performed by NewSession on a session spec and returns an
+// estimate of the skill it would run. No session is created and nothing is executed.
+func EstimateSession(ctx context.Context, rsrcSpec []byte) (*SessionEstimate, apperrors.Error) {
+	if err := validateRequiredIDs(ctx); err != nil {
+		return nil, err
+	}
+
+	sessionSpec, err := resolveSessionSpec(rsrcSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared, _, err := prepareSession(ctx, sessionSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return estimateSkill(prepared.skillSetManager, path.Base(sessionSpec.SkillPath), sessionSpec.Environment, prepared.sessionVariables)
+}
+
+// estimateSkill builds the estimate of a skill from its skillset definition, with the source
+// overrides of the environment applied.
+func estimateSkill(skillSetManager catalogmanager.SkillSetManager, skillName, environment string, sessionVariables map[string]any) (*SessionEstimate, apperrors.Error) {
+	skill, err := skillSetManager.GetSkill(skillName)
+	if err != nil {
+		return nil, err
+	}
+	source, err := skillSetManager.GetSourceForSkill(skill.Name)
+	if err != nil {
+		return nil, err
+	}
+	source = skillSetManager.ApplySourceOverrides(source, environment)
+
+	return &SessionEstimate{
+		Skill:           skill.Name,
+		Source:          source.Name,
+		Runner:          source.Runner,
+		CPULimitSeconds: intConfigValue(source.Config, "cpuLimit"),
+		MemoryLimitMB:   intConfigValue(source.Config, "memoryLimitMB"),
+		MaxConcurrent:   skill.MaxConcurrent,
+		MaxRestarts:     skill.MaxRestarts,
+		DependencyCount: len(skillSetManager.ResolveDependencies(sessionVariables)),
+		Transform:       !skill.Transform.IsNil(),
+		Preflight:       skillSetManager.GetPreflightSkill(),
+	}, nil
+}
+
+// intConfigValue returns the integer value of key in a runner config, or 0 if it is not set.
+func intConfigValue(config map[string]any, key string) int {
+	switch v := config[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// estimateSession returns the estimate of the skill a session spec would run.
+func estimateSession(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+
+	req, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	estimate, appErr := EstimateSession(ctx, req)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   estimate,
+	}, nil
+}
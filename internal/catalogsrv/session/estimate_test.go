@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+func TestEstimateSkill(t *testing.T) {
+	skillSetManager, err := catalogmanager.SkillSetManagerFromJSON(context.Background(), []byte(`{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "SkillSet",
+		"metadata": {"name": "deploy-tools", "catalog": "test-catalog", "path": "/skills"},
+		"spec": {
+			"version": "1.0.0",
+			"sources": [
+				{
+					"name": "script-runner",
+					"runner": "system.stdiorunner",
+					"config": {"version": "0.1.0-alpha.1", "runtime": "bash", "script": "deploy.sh", "cpuLimit": 30, "memoryLimitMB": 512}
+				},
+				{
+					"name": "mcp-runner",
+					"runner": "system.mcp.stdio",
+					"config": {"command": "npx"}
+				}
+			],
+			"overrides": {
+				"prod": {"script-runner": {"cpuLimit": 120}}
+			},
+			"dependencies": [
+				{"path": "/resources/cluster", "kind": "Resource", "alias": "cluster", "actions": ["read"]},
+				{"path": "/resources/prod-db", "kind": "Resource", "alias": "db", "actions": ["read"], "when": {"variable": "env", "equals": "prod"}}
+			],
+			"preflight": "authorize",
+			"skills": [
+				{"name": "authorize", "source": "script-runner", "exportedActions": ["test.action"]},
+				{"name": "deploy", "source": "script-runner", "maxConcurrent": 2, "maxRestarts": 1, "transform": "function(session, input) { return input; }", "exportedActions": ["test.action"]},
+				{"name": "list-repos", "source": "mcp-runner", "exportedActions": ["test.action"]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	t.Run("estimate reflects the skill's runner and limits", func(t *testing.T) {
+		estimate, err := estimateSkill(skillSetManager, "deploy", "", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, &SessionEstimate{
+			Skill:           "deploy",
+			Source:          "script-runner",
+			Runner:          catcommon.StdioRunnerID,
+			CPULimitSeconds: 30,
+			MemoryLimitMB:   512,
+			MaxConcurrent:   2,
+			MaxRestarts:     1,
+			DependencyCount: 1,
+			Transform:       true,
+			Preflight:       "authorize",
+		}, estimate)
+	})
+
+	t.Run("environment overrides and session variables are applied", func(t *testing.T) {
+		estimate, err := estimateSkill(skillSetManager, "deploy", "prod", map[string]any{"env": "prod"})
+		require.NoError(t, err)
+		assert.Equal(t, 120, estimate.CPULimitSeconds)
+		assert.Equal(t, 512, estimate.MemoryLimitMB)
+		assert.Equal(t, 2, estimate.DependencyCount)
+	})
+
+	t.Run("skill without limits or transform", func(t *testing.T) {
+		estimate, err := estimateSkill(skillSetManager, "list-repos", "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, catcommon.RunnerID(catcommon.MCPStdioRunnerID), estimate.Runner)
+		assert.Zero(t, estimate.CPULimitSeconds)
+		assert.Zero(t, estimate.MemoryLimitMB)
+		assert.False(t, estimate.Transform)
+	})
+
+	t.Run("unknown skill", func(t *testing.T) {
+		_, err := estimateSkill(skillSetManager, "rollback", "", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestEstimateSession(t *testing.T) {
+	config.TestInit()
+	Init()
+
+	ctx := catcommon.WithCatalogContext(context.Background(), &catcommon.CatalogContext{
+		CatalogID:   uuid.New(),
+		Catalog:     "test-catalog",
+		UserContext: &catcommon.UserContext{UserID: "users/testuser"},
+	})
+
+	t.Run("malformed spec", func(t *testing.T) {
+		_, err := EstimateSession(ctx, []byte(`{`))
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		_, err := EstimateSession(ctx, []byte(`{"skillPath": "invalid/path/format", "viewName": "test-view"}`))
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// staleSessionBatchSize is the number of stale sessions marked per database query
+const staleSessionBatchSize = 100
+
+// HeartbeatRsp is the response to a session heartbeat.
+type HeartbeatRsp struct {
+	SessionID  uuid.UUID `json:"sessionID"`
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// RecordHeartbeat records that the tangent running a session is still alive. Liveness tracking
+// of a session starts with its first heartbeat. Returns ErrSessionNotActive if the session has
+// already ended or was marked stale, in which case the tangent should stop running it.
+func RecordHeartbeat(ctx context.Context, sessionID uuid.UUID, now time.Time) apperrors.Error {
+	err := db.DB(ctx).UpdateSessionHeartbeat(ctx, sessionID, now, activeSessionStatuses)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrSessionNotActive.Msg("session " + sessionID.String() + " is not active")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("session_id", sessionID.String()).Msg("failed to record session heartbeat")
+		return ErrUnableToGetSession
+	}
+	return nil
+}
+
+// MarkStaleSessions marks the active sessions whose last heartbeat is older than the configured
+// heartbeat timeout before now as stale. Stale sessions have ended, so they no longer count
+// towards tenant quotas or tangent concurrency limits.
+// Returns the number of sessions marked stale. Does nothing if no heartbeat timeout is configured.
+// The context must carry a database connection.
+func MarkStaleSessions(ctx context.Context, now time.Time) (int, error) {
+	timeout := config.Config().Session.GetHeartbeatTimeout()
+	if timeout <= 0 {
+		return 0, nil
+	}
+	cutoff := now.Add(-timeout)
+
+	marked := 0
+	for {
+		sessions, err := db.DB(ctx).ListStaleSessions(ctx, cutoff, activeSessionStatuses, staleSessionBatchSize)
+		if err != nil {
+			return marked, err
+		}
+		progressed := false
+		for _, s := range sessions {
+			if err := markSessionStale(ctx, s); err != nil {
+				log.Ctx(ctx).Error().Err(err).Str("session_id", s.SessionID.String()).Msg("failed to mark session stale")
+				continue
+			}
+			progressed = true
+			marked++
+		}
+		// stop when all stale sessions were seen, or none in this batch could be marked
+		if len(sessions) < staleSessionBatchSize || !progressed {
+			return marked, nil
+		}
+	}
+}
+
+// markSessionStale moves a single session to the stale status and expires its access tokens, so
+// that a tangent that lost contact cannot keep acting for the session.
+func markSessionStale(ctx context.Context, s *models.Session) apperrors.Error {
+	ctx = catcommon.WithTenantID(ctx, s.TenantID)
+	manager := &sessionManager{session: s}
+	if err := manager.SetStatusSummary(ctx, SessionStatusStale); err != nil {
+		return err
+	}
+	if err := expireSessionTokens(ctx, s.SessionID); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().Str("session_id", s.SessionID.String()).Str("tangent_id", s.TangentID.String()).Msg("marked session stale after heartbeat lapsed")
+	return nil
+}
+
+// RunSessionWatchdog marks sessions with lapsed heartbeats as stale every session.watchdog_interval
+// until ctx is done. Returns immediately if no heartbeat timeout is configured.
+func RunSessionWatchdog(ctx context.Context) {
+	if config.Config().Session.GetHeartbeatTimeout() <= 0 {
+		return
+	}
+	interval, err := config.Config().Session.GetWatchdogInterval()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("invalid session watchdog interval")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runSessionWatchdog(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runSessionWatchdog(ctx context.Context) {
+	dbCtx, err := db.ConnCtx(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to get db connection for session watchdog")
+		return
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	marked, err := MarkStaleSessions(dbCtx, time.Now())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to mark stale sessions")
+	}
+	if marked > 0 {
+		log.Ctx(ctx).Info().Int("count", marked).Msg("marked sessions with lapsed heartbeats stale")
+	}
+}
+
+func sessionHeartbeat(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+
+	now := time.Now()
+	if err := RecordHeartbeat(ctx, sessionID, now); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &HeartbeatRsp{
+			SessionID:  sessionID,
+			ReceivedAt: now,
+		},
+	}, nil
+}
+
+// tangentHeartbeat records that the tangent in the route is alive. The request must be signed by
+// that tangent. Pinned sessions are only assigned to a tangent whose heartbeats are current.
+func tangentHeartbeat(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	tangentID, err := uuid.Parse(chi.URLParam(r, "tangentID"))
+	if err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid tangent ID")
+	}
+	headers := config.Config().Tangent.SignatureHeaders.WithDefaults()
+	if r.Header.Get(headers.TangentID) != tangentID.String() {
+		return nil, httpx.ErrUnAuthorized("heartbeat must be signed by the tangent it is for")
+	}
+
+	if err := db.DB(ctx).UpdateTangentHeartbeat(ctx, tangentID, time.Now()); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrInvalidRequest.Msg("tangent " + tangentID.String() + " is not registered")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", tangentID.String()).Msg("failed to record tangent heartbeat")
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+		Response:   nil,
+	}, nil
+}
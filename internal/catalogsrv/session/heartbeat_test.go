@@ -0,0 +1,116 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+func TestMarkStaleSessions(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	sessionConfig := config.Config().Session
+	defer func() { config.Config().Session = sessionConfig }()
+	config.Config().Session.HeartbeatTimeout = "5m"
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{Name: "test-catalog", ProjectID: projectID, Info: pgtype.JSONB{Status: pgtype.Null}}
+	require.NoError(t, db.DB(ctx).CreateCatalog(ctx, &catalog))
+	defer db.DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	now := time.Now()
+	createSession := func() uuid.UUID {
+		sessionID := uuid.New()
+		require.NoError(t, db.DB(ctx).UpsertSession(ctx, &models.Session{
+			SessionID:     sessionID,
+			SkillSet:      "test-skillset",
+			Skill:         "test-skill",
+			ViewID:        uuid.New(),
+			TangentID:     uuid.New(),
+			StatusSummary: string(SessionStatusRunning),
+			Status:        []byte(`{}`),
+			UserID:        "users/testuser",
+			CatalogID:     catalog.CatalogID,
+			VariantID:     uuid.New(),
+			StartedAt:     now.Add(-time.Hour),
+			ExpiresAt:     now.Add(time.Hour),
+		}))
+		return sessionID
+	}
+	statusOf := func(sessionID uuid.UUID) SessionStatus {
+		session, err := db.DB(ctx).GetSession(ctx, sessionID)
+		require.NoError(t, err)
+		return SessionStatus(session.StatusSummary)
+	}
+
+	lapsedID := createSession()
+	liveID := createSession()
+	// liveness tracking starts with the first heartbeat
+	silentID := createSession()
+
+	createToken := func(sessionID uuid.UUID) uuid.UUID {
+		token := &models.ViewToken{ViewID: uuid.New(), SessionID: sessionID, ExpireAt: now.Add(time.Hour)}
+		require.NoError(t, db.DB(ctx).CreateViewToken(ctx, token))
+		t.Cleanup(func() { db.DB(ctx).DeleteViewToken(ctx, token.TokenID) })
+		return token.TokenID
+	}
+	tokenExpired := func(tokenID uuid.UUID) bool {
+		token, err := db.DB(ctx).GetViewToken(ctx, tokenID)
+		require.NoError(t, err)
+		return !token.ExpireAt.After(time.Now())
+	}
+	lapsedToken, liveToken := createToken(lapsedID), createToken(liveID)
+
+	require.NoError(t, RecordHeartbeat(ctx, lapsedID, now.Add(-10*time.Minute)))
+	require.NoError(t, RecordHeartbeat(ctx, liveID, now.Add(-10*time.Minute)))
+	require.NoError(t, RecordHeartbeat(ctx, liveID, now.Add(-time.Minute)))
+
+	marked, err := MarkStaleSessions(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+
+	assert.Equal(t, SessionStatusStale, statusOf(lapsedID))
+	assert.Equal(t, SessionStatusRunning, statusOf(liveID))
+	assert.Equal(t, SessionStatusRunning, statusOf(silentID))
+	assert.True(t, tokenExpired(lapsedToken))
+	assert.False(t, tokenExpired(liveToken))
+
+	session, err := db.DB(ctx).GetSession(ctx, lapsedID)
+	require.NoError(t, err)
+	assert.False(t, session.EndedAt.IsZero())
+
+	// a late heartbeat does not revive a stale session
+	assert.ErrorIs(t, RecordHeartbeat(ctx, lapsedID, now), ErrSessionNotActive)
+	assert.Equal(t, SessionStatusStale, statusOf(lapsedID))
+
+	// marking again finds nothing
+	marked, err = MarkStaleSessions(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, marked)
+
+	t.Run("no heartbeat timeout disables the watchdog", func(t *testing.T) {
+		config.Config().Session.HeartbeatTimeout = ""
+		marked, err := MarkStaleSessions(ctx, now.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, marked)
+		assert.Equal(t, SessionStatusRunning, statusOf(liveID))
+	})
+}
@@ -0,0 +1,269 @@
+package session
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/snappy"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// PolicyDecision is a policy decision recorded in a session's audit log. Seq is the position of
+// the decision's entry in the audit log, starting at 1.
+type PolicyDecision struct {
+	Seq          int64           `json:"seq"`
+	Time         time.Time       `json:"time"`
+	Decision     string          `json:"decision"`
+	Reason       string          `json:"reason,omitempty"`
+	Skill        string          `json:"skill"`
+	InvocationID string          `json:"invocationID,omitempty"`
+	View         string          `json:"view,omitempty"`
+	Actions      []string        `json:"actions"`
+	Basis        json.RawMessage `json:"basis,omitempty"`
+}
+
+// PolicyDecisionsRsp is the response to a request for the policy decisions of a session.
+// NextCursor continues after the last decision returned.
+type PolicyDecisionsRsp struct {
+	SessionID  uuid.UUID        `json:"sessionID"`
+	Decisions  []PolicyDecision `json:"decisions"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+	HasMore    bool             `json:"hasMore,omitempty"`
+}
+
+// auditLogPayload holds the fields of an audit log entry payload used to extract policy decisions.
+type auditLogPayload struct {
+	Event        string          `json:"event"`
+	Time         json.Number     `json:"time"`
+	Decision     string          `json:"decision"`
+	Reason       string          `json:"reason"`
+	Skill        string          `json:"skill"`
+	InvocationID string          `json:"invocation_id"`
+	View         string          `json:"view"`
+	Actions      []string        `json:"actions"`
+	Basis        json.RawMessage `json:"basis"`
+}
+
+// parseAuditLogTime converts an audit log timestamp, which is written in Unix milliseconds, to a time.
+func parseAuditLogTime(t json.Number) time.Time {
+	if ms, err := t.Int64(); err == nil {
+		return time.UnixMilli(ms).UTC()
+	}
+	if parsed, err := time.Parse(time.RFC3339, t.String()); err == nil {
+		return parsed
+	}
+	return time.Time{}
+}
+
+// extractPolicyDecisions reads an uncompressed audit log and returns its policy decisions in log order.
+// Lines that are not valid log entries are skipped.
+func extractPolicyDecisions(r io.Reader) ([]PolicyDecision, error) {
+	decisions := []PolicyDecision{}
+	err := scanPolicyDecisions(r, func(d PolicyDecision) error {
+		decisions = append(decisions, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// scanPolicyDecisions reads an uncompressed audit log and calls fn with each of its policy
+// decisions in log order, stopping at the first error fn returns. Lines that are not valid log
+// entries are skipped.
+func scanPolicyDecisions(r io.Reader, fn func(PolicyDecision) error) error {
+	reader := bufio.NewReader(r)
+	var seq int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			seq++
+			var entry struct {
+				Payload auditLogPayload `json:"payload"`
+			}
+			if json.Unmarshal(line, &entry) == nil && entry.Payload.Event == "policy_decision" {
+				p := entry.Payload
+				if fnErr := fn(PolicyDecision{
+					Seq:          seq,
+					Time:         parseAuditLogTime(p.Time),
+					Decision:     p.Decision,
+					Reason:       p.Reason,
+					Skill:        p.Skill,
+					InvocationID: p.InvocationID,
+					View:         p.View,
+					Actions:      p.Actions,
+					Basis:        p.Basis,
+				}); fnErr != nil {
+					return fnErr
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// GetPolicyDecisions returns the policy decisions recorded in the stored audit log of a session.
+func GetPolicyDecisions(sessionID uuid.UUID) ([]PolicyDecision, error) {
+	decisions := []PolicyDecision{}
+	err := forEachPolicyDecision(sessionID, func(d PolicyDecision) error {
+		decisions = append(decisions, d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// forEachPolicyDecision calls fn with each policy decision recorded in the stored audit log of a
+// session, reading the log as the decisions are consumed.
+func forEachPolicyDecision(sessionID uuid.UUID, fn func(PolicyDecision) error) error {
+	logFilePath := findAuditLogFile(sessionID)
+	if logFilePath == "" {
+		return os.ErrNotExist
+	}
+
+	f, err := os.Open(logFilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if filepath.Ext(logFilePath) == ".ztlog" {
+		r = snappy.NewReader(f)
+	}
+	return scanPolicyDecisions(r, fn)
+}
+
+// getPolicyDecisionsByID returns a page of the policy decisions of the session that the session
+// token was issued for. The cursor query parameter continues from a previous page and limit sets
+// the number of decisions returned.
+func getPolicyDecisionsByID(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionUUID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+	after, limit, err := parsePageParams(r)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+	if tokenSessionID := catcommon.GetSessionID(ctx); tokenSessionID != sessionUUID {
+		return nil, ErrNotAuthorized.Msg("session token is not valid for this session")
+	}
+
+	decisions, err := GetPolicyDecisions(sessionUUID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrAuditLogNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to read policy decisions")
+		return nil, ErrUnableToGetSession.Msg("unable to read audit log")
+	}
+
+	rsp := PolicyDecisionsRsp{SessionID: sessionUUID}
+	rsp.Decisions, rsp.NextCursor, rsp.HasMore = paginate(decisions,
+		func(d PolicyDecision) int64 { return d.Seq }, after, limit)
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// policyDecisionsCSVHeader holds the columns of a policy decisions CSV export.
+var policyDecisionsCSVHeader = []string{"skill", "actions", "decision", "timestamp", "view", "reason", "invocation_id"}
+
+// policyDecisionsCSVFlushRows is the number of rows written between flushes of a CSV export.
+const policyDecisionsCSVFlushRows = 100
+
+// policyDecisionCSVRecord returns the CSV columns of a policy decision. Actions are joined with
+// semicolons so that each decision fits in a single row.
+func policyDecisionCSVRecord(d PolicyDecision) []string {
+	return []string{
+		d.Skill,
+		strings.Join(d.Actions, ";"),
+		d.Decision,
+		d.Time.UTC().Format(time.RFC3339Nano),
+		d.View,
+		d.Reason,
+		d.InvocationID,
+	}
+}
+
+// writePolicyDecisionsCSV streams the policy decisions of a session as CSV, one row per decision
+// after a header row. Rows are flushed as they are read so large audit logs are not held in memory.
+func writePolicyDecisionsCSV(w io.Writer, sessionID uuid.UUID) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(policyDecisionsCSVHeader); err != nil {
+		return err
+	}
+	flusher, _ := w.(http.Flusher)
+	rows := 0
+	err := forEachPolicyDecision(sessionID, func(d PolicyDecision) error {
+		if err := cw.Write(policyDecisionCSVRecord(d)); err != nil {
+			return err
+		}
+		if rows++; rows%policyDecisionsCSVFlushRows == 0 {
+			cw.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return cw.Error()
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// getPolicyDecisionsCSVByID streams the policy decisions of the session that the session token
+// was issued for as a CSV file.
+func getPolicyDecisionsCSVByID(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionUUID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+	if tokenSessionID := catcommon.GetSessionID(ctx); tokenSessionID != sessionUUID {
+		return nil, ErrNotAuthorized.Msg("session token is not valid for this session")
+	}
+	// the audit log is checked before the response starts, as errors cannot be reported after
+	if findAuditLogFile(sessionUUID) == "" {
+		return nil, ErrAuditLogNotFound
+	}
+
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "text/csv",
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			if err := writePolicyDecisionsCSV(w, sessionUUID); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("failed to write policy decisions")
+				return err
+			}
+			return nil
+		},
+	}, nil
+}
@@ -2,7 +2,6 @@ package session
 
 import (
 	"context"
-	"crypto/ed25519"
 	"encoding/base64"
 	"io"
 	"net/http"
@@ -14,9 +13,11 @@ import (
 	"github.com/tansive/tansive/internal/catalogsrv/apis"
 	"github.com/tansive/tansive/internal/catalogsrv/auth"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
 	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tansive/tansive/internal/common/uuid"
 )
 
@@ -26,6 +27,16 @@ var sessionHandlers = []policy.ResponseHandlerParam{
 		Path:    "/execution-state",
 		Handler: getExecutionState,
 	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/decisions",
+		Handler: getPolicyDecisionsByID,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/decisions.csv",
+		Handler: getPolicyDecisionsCSVByID,
+	},
 }
 
 var sessionTangentHandlers = []policy.ResponseHandlerParam{
@@ -44,6 +55,36 @@ var sessionTangentHandlers = []policy.ResponseHandlerParam{
 		Path:    "/stop",
 		Handler: initializeStopSession,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/auditlog/uploads",
+		Handler: initAuditLogUpload,
+	},
+	{
+		Method:  http.MethodPut,
+		Path:    "/auditlog/uploads/{uploadID}/parts/{partNumber}",
+		Handler: putAuditLogUploadPart,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/auditlog/uploads/{uploadID}/complete",
+		Handler: completeAuditLogUpload,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/auditlog/uploads/{uploadID}",
+		Handler: abortAuditLogUpload,
+	},
+	{
+		Method:  http.MethodPut,
+		Path:    "/transcript",
+		Handler: putTranscript,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/heartbeat",
+		Handler: sessionHeartbeat,
+	},
 }
 
 var sessionUserHandlers = []policy.ResponseHandlerParam{
@@ -52,11 +93,26 @@ var sessionUserHandlers = []policy.ResponseHandlerParam{
 		Path:    "/",
 		Handler: newSession,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/validate",
+		Handler: validateSession,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/estimate",
+		Handler: estimateSession,
+	},
 	{
 		Method:  http.MethodGet,
 		Path:    "/",
 		Handler: getSessions,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/summary/batch",
+		Handler: getSessionSummaryBatch,
+	},
 	{
 		Method:  http.MethodGet,
 		Path:    "/{sessionID}",
@@ -77,6 +133,53 @@ var sessionUserHandlers = []policy.ResponseHandlerParam{
 		Path:    "/{sessionID}/auditlog/verification-key",
 		Handler: getAuditLogVerificationKeyByID,
 	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/transcript",
+		Handler: getTranscriptByID,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/dependencies",
+		Handler: getSessionDependencies,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{sessionID}/replay",
+		Handler: replaySession,
+	},
+}
+
+// tangentSessionHandlers manage the sessions bound to a tangent and are served under /tangents.
+var tangentSessionHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:         http.MethodGet,
+		Path:           "/{tangentID}/sessions",
+		Handler:        getTangentSessions,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/{tangentID}/drain",
+		Handler:        drainTangentSessions,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/{tangentID}/undrain",
+		Handler:        undrainTangent,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+}
+
+// tangentHeartbeatHandlers are called by a tangent, signed with its access key, and are served
+// under /tangents.
+var tangentHeartbeatHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:  http.MethodPost,
+		Path:    "/{tangentID}/heartbeat",
+		Handler: tangentHeartbeat,
+	},
 }
 
 func Router() chi.Router {
@@ -104,6 +207,25 @@ func Router() chi.Router {
 	return r
 }
 
+// TangentRoutes registers the handlers that manage the sessions bound to a tangent on the
+// tangents router.
+func TangentRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(auth.ContextMiddleware)
+		r.Use(apis.CatalogContextLoader)
+		for _, handler := range tangentSessionHandlers {
+			policyEnforcedHandler := policy.EnforceViewPolicyMiddleware(handler)
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(policyEnforcedHandler))
+		}
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(tangentAuthMiddleware)
+		for _, handler := range tangentHeartbeatHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
+}
+
 func tangentAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -119,9 +241,11 @@ func tangentAuthMiddleware(next http.Handler) http.Handler {
 }
 
 func validateTangentSignature(ctx context.Context, r *http.Request) error {
-	signature := r.Header.Get("X-Tangent-Signature")
-	timestamp := r.Header.Get("X-Tangent-Signature-Timestamp")
-	tangentIDStr := r.Header.Get("X-TangentID")
+	headers := config.Config().Tangent.SignatureHeaders.WithDefaults()
+	signature := r.Header.Get(headers.Signature)
+	timestamp := r.Header.Get(headers.Timestamp)
+	algorithm := r.Header.Get(headers.Algorithm)
+	tangentIDStr := r.Header.Get(headers.TangentID)
 
 	if signature == "" || timestamp == "" || tangentIDStr == "" {
 		return ErrInvalidRequest.Msg("missing signature headers")
@@ -158,27 +282,26 @@ func validateTangentSignature(ctx context.Context, r *http.Request) error {
 		r.Body = io.NopCloser(strings.NewReader(string(body)))
 	}
 
-	requestPath := strings.TrimPrefix(r.URL.Path, "/")
-	requestPath = "/" + requestPath
-	stringToSign := strings.Join([]string{
-		r.Method,
-		requestPath,
-		r.URL.RawQuery,
-		string(body),
-		timestamp,
-	}, "\n")
+	stringToSign := tangentsig.StringToSign(r.Method, r.URL.Path, r.URL.RawQuery, body, timestamp)
 
 	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
 	if err != nil {
 		return ErrInvalidRequest.Msg("invalid signature format")
 	}
 
-	if len(tangent.PublicKey) != ed25519.PublicKeySize {
-		return ErrInvalidRequest.Msg("invalid public key size")
+	keyAlgorithm, err := tangent.KeyAlgorithm()
+	if err != nil {
+		return ErrInvalidRequest.Msg("unsupported tangent public key")
+	}
+	if algorithm == "" {
+		algorithm = tangentsig.DefaultAlgorithm
+	}
+	if algorithm != keyAlgorithm {
+		return ErrInvalidRequest.Msg("signature algorithm does not match registered key type")
 	}
 
-	publicKey := ed25519.PublicKey(tangent.PublicKey)
-	if !ed25519.Verify(publicKey, []byte(stringToSign), signatureBytes) {
+	if err := tangentsig.Verify(algorithm, tangent.PublicKey, []byte(stringToSign), signatureBytes); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", tangentIDStr).Str("algorithm", algorithm).Msg("tangent signature rejected")
 		return ErrInvalidRequest.Msg("signature verification failed")
 	}
 
@@ -3,21 +3,29 @@ package session
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"path"
 	"reflect"
+	"regexp"
+	"slices"
 	"time"
 
 	"encoding/json"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/tansive/tansive/internal/catalogsrv/auth"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
 	schemaerr "github.com/tansive/tansive/internal/catalogsrv/schema/errors"
@@ -34,6 +42,79 @@ type SessionSpec struct {
 	ViewName         string          `json:"viewName" validate:"required,resourceNameValidator"`
 	SessionVariables json.RawMessage `json:"sessionVariables" validate:"omitempty"`
 	InputArgs        json.RawMessage `json:"inputArgs" validate:"omitempty"`
+	CallbackURL      string          `json:"callbackURL,omitempty" validate:"omitempty"`
+	Environment      string          `json:"environment,omitempty" validate:"omitempty,resourceNameValidator"`
+	// ContextOverrides replaces the values of skillset contexts, keyed by context name, for
+	// this session only. The stored skillset is not changed.
+	ContextOverrides map[string]any `json:"contextOverrides,omitempty" validate:"omitempty"`
+	// InputArgsRef names a skillset context whose value is used as the input args, so that large
+	// inputs are not stored with the session. At most one of InputArgs and InputArgsRef may be set;
+	// with neither, the skill is run without input.
+	InputArgsRef string `json:"inputArgsRef,omitempty" validate:"omitempty,resourceNameValidator"`
+	// Labels tag the session for filtering and grouping, e.g. by team, pipeline or run ID.
+	Labels map[string]string `json:"labels,omitempty" validate:"omitempty"`
+	// Namespace resolves the session's skillset and resources in the given namespace instead of
+	// the view's. It must exist and be permitted by the view.
+	Namespace string `json:"namespace,omitempty" validate:"omitempty,resourceNameValidator"`
+	// TangentID and TangentURL pin the session to a registered tangent, e.g. for data locality,
+	// instead of letting the server choose one. At most one of them may be set.
+	TangentID  string `json:"tangentID,omitempty" validate:"omitempty"`
+	TangentURL string `json:"tangentURL,omitempty" validate:"omitempty"`
+	// LogLevel sets the verbosity of the session's log on the tangent, e.g. "debug" to
+	// troubleshoot a single session. The tangent's level is used if unset.
+	LogLevel string `json:"logLevel,omitempty" validate:"omitempty"`
+}
+
+// tangentPin returns the tangent the session is pinned to. The pin is unset if the session may
+// run on any tangent.
+func (s *SessionSpec) tangentPin() (tangent.TangentPin, error) {
+	if s.TangentID != "" && s.TangentURL != "" {
+		return tangent.TangentPin{}, fmt.Errorf("tangentID and tangentURL are mutually exclusive")
+	}
+	if s.TangentID != "" {
+		id, err := uuid.Parse(s.TangentID)
+		if err != nil {
+			return tangent.TangentPin{}, fmt.Errorf("invalid tangent ID %q", s.TangentID)
+		}
+		return tangent.TangentPin{ID: id}, nil
+	}
+	if s.TangentURL != "" {
+		u, err := url.Parse(s.TangentURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return tangent.TangentPin{}, fmt.Errorf("invalid tangent URL %q", s.TangentURL)
+		}
+		return tangent.TangentPin{URL: s.TangentURL}, nil
+	}
+	return tangent.TangentPin{}, nil
+}
+
+// Limits on session labels
+const (
+	MaxSessionLabels           = 32
+	MaxSessionLabelKeyLength   = 63
+	MaxSessionLabelValueLength = 256
+)
+
+var (
+	sessionLabelKeyRegex   = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
+	sessionLabelValueRegex = regexp.MustCompile(`^[a-zA-Z0-9._/:-]*$`)
+)
+
+// validateSessionLabels checks the number of labels and the length and characters of their keys
+// and values.
+func validateSessionLabels(labels map[string]string) error {
+	if len(labels) > MaxSessionLabels {
+		return fmt.Errorf("at most %d labels are allowed", MaxSessionLabels)
+	}
+	for key, value := range labels {
+		if len(key) > MaxSessionLabelKeyLength || !sessionLabelKeyRegex.MatchString(key) {
+			return fmt.Errorf("invalid label key %q: keys must start with a letter or digit, contain only letters, digits, '.', '_', '/' and '-', and be at most %d characters", key, MaxSessionLabelKeyLength)
+		}
+		if len(value) > MaxSessionLabelValueLength || !sessionLabelValueRegex.MatchString(value) {
+			return fmt.Errorf("invalid value of label %q: values may contain only letters, digits, '.', '_', '/', ':' and '-', and be at most %d characters", key, MaxSessionLabelValueLength)
+		}
+	}
+	return nil
 }
 
 // variableSchema defines the JSON schema for session variables
@@ -52,9 +133,16 @@ const variableSchema = `
 type SessionInfo struct {
 	SessionVariables map[string]any         `json:"sessionVariables" validate:"omitempty"`
 	InputArgs        map[string]any         `json:"inputArgs" validate:"omitempty"`
+	InputArgsRef     string                 `json:"inputArgsRef,omitempty" validate:"omitempty"`
 	ViewDefinition   *policy.ViewDefinition `json:"viewDefinition" validate:"omitempty"`
 	Interactive      bool                   `json:"interactive" validate:"omitempty"`
 	CodeChallenge    string                 `json:"codeChallenge" validate:"omitempty"`
+	CallbackURL      string                 `json:"callbackURL,omitempty" validate:"omitempty"`
+	Environment      string                 `json:"environment,omitempty" validate:"omitempty"`
+	ContextOverrides map[string]any         `json:"contextOverrides,omitempty" validate:"omitempty"`
+	Namespace        string                 `json:"namespace,omitempty" validate:"omitempty"`
+	AdoptionDepth    int                    `json:"adoptionDepth,omitempty" validate:"omitempty"`
+	LogLevel         string                 `json:"logLevel,omitempty" validate:"omitempty"`
 }
 
 var variableSchemaCompiled *jsonschema.Schema
@@ -104,6 +192,10 @@ func NewSession(ctx context.Context, rsrcSpec []byte, opts ...RequestOptions) (S
 		return nil, nil, err
 	}
 
+	if err := catalogmanager.CheckQuota(ctx, "active sessions", catalogmanager.TenantQuota(ctx).MaxActiveSessions, countActiveSessions); err != nil {
+		return nil, nil, err
+	}
+
 	requestOptions := &requestOptions{}
 	for _, opt := range opts {
 		opt(requestOptions)
@@ -114,84 +206,202 @@ func NewSession(ctx context.Context, rsrcSpec []byte, opts ...RequestOptions) (S
 		return nil, nil, err
 	}
 
-	// Validate session specification
-	if err := validateSessionSpec(ctx, sessionSpec); err != nil {
-		return nil, nil, err
-	}
-
-	// Parse input arguments and session variables
-	inputArgs, sessionVariables, err := parseSessionData(sessionSpec)
+	manager, tangent, _, err := buildSession(ctx, sessionSpec, requestOptions)
 	if err != nil {
 		return nil, nil, err
 	}
+	return manager, tangent, nil
+}
 
-	// Resolve view and skill set managers
-	viewManager, skillSetManager, skillObj, err := resolveManagersAndSkill(ctx, sessionSpec)
+// buildSession runs the checks NewSession performs on a validated session spec and returns the
+// session it creates, with the tangent to run it on. The session is not persisted. On failure,
+// it also returns the spec field the error relates to.
+func buildSession(ctx context.Context, sessionSpec SessionSpec, requestOptions *requestOptions) (*sessionManager, *tangent.Tangent, string, apperrors.Error) {
+	// Check policies and resolve the view, skillset and skill the spec refers to
+	prepared, field, err := prepareSession(ctx, sessionSpec)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	// Validate skill input and permissions
-	if err := validateSkillAndPermissions(ctx, skillObj, viewManager, skillSetManager, inputArgs); err != nil {
-		return nil, nil, err
+		return nil, nil, field, err
 	}
+	viewManager, skillSetManager := prepared.viewManager, prepared.skillSetManager
 
 	// Create session info
-	sessionInfo, err := createSessionInfo(sessionSpec, inputArgs, sessionVariables, viewManager, requestOptions)
+	sessionInfo, err := createSessionInfo(sessionSpec, prepared, requestOptions)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 
 	// Get Tangent
-	tangent, err := tangent.GetTangentWithCapabilities(ctx, skillSetManager.GetRunnerTypes())
+	tangent, err := resolveSessionTangent(ctx, sessionSpec, skillSetManager.GetRunnerTypes())
 	if err != nil {
-		return nil, nil, err
+		field := "tangentID"
+		if sessionSpec.TangentURL != "" {
+			field = "tangentURL"
+		}
+		return nil, nil, field, err
 	}
 
 	// Create session object
 	session, err := createSessionObject(ctx, sessionSpec, sessionInfo, viewManager, tangent)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
 
 	return &sessionManager{
 		session:         session,
 		skillSetManager: skillSetManager,
 		viewManager:     viewManager,
-	}, tangent, nil
+	}, tangent, "", nil
 }
 
-// validateRequiredIDs validates that required IDs are present in the context
-func validateRequiredIDs(ctx context.Context) apperrors.Error {
-	catalogID := catcommon.GetCatalogID(ctx)
-	if catalogID == uuid.Nil {
-		return ErrInvalidObject.Msg("unable to determine catalog")
+// resolveSessionTangent returns the tangent to run the session on: the tangent the spec pins the
+// session to, or otherwise one chosen for the runners the session's skillset needs.
+func resolveSessionTangent(ctx context.Context, sessionSpec SessionSpec, runners []catcommon.RunnerID) (*tangent.Tangent, apperrors.Error) {
+	tangentSessions, err := countActiveSessionsByTangent(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	userID := catcommon.GetUserID(ctx)
-	if userID == "" {
-		return ErrInvalidObject.Msg("user ID is required")
+	pin, goerr := sessionSpec.tangentPin()
+	if goerr != nil {
+		return nil, ErrInvalidSession.Msg(goerr.Error())
 	}
+	if pin.IsSet() {
+		return tangent.GetPinnedTangent(ctx, pin, runners, tangentSessions)
+	}
+	return tangent.GetTangentWithCapabilities(ctx, runners, tangentSessions)
+}
 
-	return nil
+// preparedSession holds the state resolved while checking a new session's spec.
+type preparedSession struct {
+	inputArgs        map[string]any
+	sessionVariables map[string]any
+	viewManager      policy.ViewManager
+	skillSetManager  catalogmanager.SkillSetManager
+	adoptionDepth    int
 }
 
-// validateSessionSpec validates the session specification
-func validateSessionSpec(ctx context.Context, sessionSpec SessionSpec) apperrors.Error {
-	// validate SkillSet Use policy
+// prepareSession runs the checks a validated session spec must pass before a session can be
+// created from it: adoption and use policies, view and skill resolution, input validation and
+// context overrides. On failure, it also returns the spec field the error relates to.
+func prepareSession(ctx context.Context, sessionSpec SessionSpec) (*preparedSession, string, apperrors.Error) {
+	// Validate skillset use and view adoption policies
 	if err := validateSkillSetUsePolicy(ctx, sessionSpec.SkillPath); err != nil {
-		return err
+		return nil, "skillPath", err
 	}
-
 	skill := path.Base(sessionSpec.SkillPath)
 	skillSetPath := path.Dir(sessionSpec.SkillPath)
 	if skill == "" || skillSetPath == "" {
-		return ErrInvalidObject.Msg("invalid skill path")
+		return nil, "skillPath", ErrInvalidObject.Msg("invalid skill path")
 	}
-
-	// Validate view policy
 	if err := validateViewPolicy(ctx, sessionSpec.ViewName); err != nil {
-		return err
+		return nil, "viewName", err
+	}
+	adoptionDepth, err := auth.NextAdoptionDepth(ctx)
+	if err != nil {
+		return nil, "viewName", err
+	}
+
+	// Parse input arguments and session variables
+	inputArgs, sessionVariables, err := parseSessionData(sessionSpec)
+	if err != nil {
+		return nil, "inputArgs", err
+	}
+
+	// Resolve the view, the session's scope, the skillset and the skill
+	viewManager, err := resolveViewByLabel(ctx, sessionSpec.ViewName)
+	if err != nil {
+		return nil, "viewName", err
+	}
+	if viewManager.GetViewDefinition().IsExpired(time.Now()) {
+		return nil, "viewName", policy.ErrViewExpired.Msg("view " + sessionSpec.ViewName + " has expired and cannot be adopted")
+	}
+	scope, err := resolveSessionScope(ctx, viewManager, sessionSpec.Namespace)
+	if err != nil {
+		return nil, "namespace", err
+	}
+	skillSetManager, err := resolveSkillSetManager(ctx, skillSetPath, scope)
+	if err != nil {
+		return nil, "skillPath", err
+	}
+	skillObj, err := skillSetManager.GetSkill(skill)
+	if err != nil {
+		return nil, "skillPath", err
+	}
+
+	// Resolve input args referenced from a context, with the session's context overrides applied
+	if sessionSpec.InputArgsRef != "" {
+		if err := skillSetManager.ApplyContextOverrides(sessionSpec.ContextOverrides); err != nil {
+			return nil, "contextOverrides", err
+		}
+		inputArgs, err = skillSetManager.GetContextInputArgs(sessionSpec.InputArgsRef, viewManager.GetViewDefinition())
+		if err != nil {
+			return nil, "inputArgsRef", err
+		}
+	}
+
+	// Validate skill input and permissions
+	if err := validateSkillAndPermissions(ctx, skillObj, viewManager, skillSetManager, sessionSpec.Namespace, inputArgs); err != nil {
+		if errors.Is(err, ErrDisallowedByPolicy) {
+			return nil, "skillPath", err
+		}
+		return nil, "inputArgs", err
+	}
+
+	// Validate context overrides against the skillset's contexts
+	if err := skillSetManager.ValidateContextOverrides(sessionSpec.ContextOverrides); err != nil {
+		return nil, "contextOverrides", err
+	}
+
+	// Validate the catalog's default actions of dependencies that list none against the view
+	if err := validateDependencyDefaults(ctx, skillSetManager, viewManager.GetViewDefinition(), sessionSpec.Namespace, sessionVariables); err != nil {
+		return nil, "skillPath", err
+	}
+
+	return &preparedSession{
+		inputArgs:        inputArgs,
+		sessionVariables: sessionVariables,
+		viewManager:      viewManager,
+		skillSetManager:  skillSetManager,
+		adoptionDepth:    adoptionDepth,
+	}, "", nil
+}
+
+// ValidateSession runs the checks performed by NewSession on a session spec, including the
+// choice of its tangent, without creating the session. Problems with the spec are returned as
+// validation errors against the field they relate to. An error is returned only if the spec
+// cannot be parsed or a check fails for reasons other than the spec itself.
+func ValidateSession(ctx context.Context, rsrcSpec []byte) (schemaerr.ValidationErrors, apperrors.Error) {
+	if err := validateRequiredIDs(ctx); err != nil {
+		return nil, err
+	}
+
+	sessionSpec := SessionSpec{}
+	if err := json.Unmarshal(rsrcSpec, &sessionSpec); err != nil {
+		return nil, ErrInvalidSession.Msg("invalid session spec: " + err.Error())
+	}
+	if validationErrors := sessionSpec.Validate(); len(validationErrors) > 0 {
+		return validationErrors, nil
+	}
+
+	if _, _, field, err := buildSession(ctx, sessionSpec, &requestOptions{}); err != nil {
+		if err.StatusCode() >= http.StatusInternalServerError {
+			return nil, err
+		}
+		return schemaerr.ValidationErrors{schemaerr.ErrInvalidValue(field, err.Error())}, nil
+	}
+
+	return nil, nil
+}
+
+// validateRequiredIDs validates that required IDs are present in the context
+func validateRequiredIDs(ctx context.Context) apperrors.Error {
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		return ErrInvalidObject.Msg("unable to determine catalog")
+	}
+
+	userID := catcommon.GetUserID(ctx)
+	if userID == "" {
+		return ErrInvalidObject.Msg("user ID is required")
 	}
 
 	return nil
@@ -204,6 +414,9 @@ func parseSessionData(sessionSpec SessionSpec) (map[string]any, map[string]any,
 		if err := json.Unmarshal(sessionSpec.InputArgs, &inputArgs); err != nil {
 			return nil, nil, ErrInvalidObject.Msg("failed to unmarshal input args: " + err.Error())
 		}
+		if err := catalogmanager.CheckJSONDepth("input args", inputArgs); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	sessionVariables := make(map[string]any)
@@ -211,66 +424,108 @@ func parseSessionData(sessionSpec SessionSpec) (map[string]any, map[string]any,
 		if err := json.Unmarshal(sessionSpec.SessionVariables, &sessionVariables); err != nil {
 			return nil, nil, ErrInvalidObject.Msg("failed to unmarshal session variables: " + err.Error())
 		}
+		if err := catalogmanager.CheckJSONDepth("session variables", sessionVariables); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	return inputArgs, sessionVariables, nil
 }
 
-// resolveManagersAndSkill resolves view manager, skill set manager, and skill object
-func resolveManagersAndSkill(ctx context.Context, sessionSpec SessionSpec) (policy.ViewManager, catalogmanager.SkillSetManager, catalogmanager.Skill, apperrors.Error) {
-	viewManager, err := resolveViewByLabel(ctx, sessionSpec.ViewName)
+// validateSkillAndPermissions validates skill input and action permissions
+func validateSkillAndPermissions(ctx context.Context, skillObj catalogmanager.Skill, viewManager policy.ViewManager, skillSetManager catalogmanager.SkillSetManager, namespace string, inputArgs map[string]any) apperrors.Error {
+	// Validate skill input, with the skill's default input args filling in missing keys. Skills
+	// that warn on invalid input are started with it and report it when they run.
+	err := skillObj.ValidateInput(skillObj.ApplyDefaultInputArgs(skillObj.NormalizeInput(inputArgs)))
 	if err != nil {
-		return nil, nil, catalogmanager.Skill{}, err
+		if !skillObj.WarnOnInvalidInput() {
+			return err
+		}
+		log.Ctx(ctx).Warn().Err(err).Str("skill", skillObj.Name).Msg("creating session with invalid input")
 	}
 
-	skillSetPath := path.Dir(sessionSpec.SkillPath)
-	skillSetManager, err := resolveSkillSetManager(ctx, skillSetPath, viewManager.Scope())
-	if err != nil {
-		return nil, nil, catalogmanager.Skill{}, err
+	// Blocked skills are denied regardless of the actions granted by the view
+	viewDef := viewManager.GetViewDefinition()
+	if viewDef.IsSkillBlocked(skillObj.Name, skillObj.Aliases...) {
+		return ErrDisallowedByPolicy.Msg("skill " + skillObj.Name + " is blocked by view")
 	}
 
-	skill := path.Base(sessionSpec.SkillPath)
-	skillObj, err := skillSetManager.GetSkill(skill)
+	// Validate action permissions
+	exportedActions := skillObj.GetExportedActions()
+	resourcePath := policy.NamespacedResourcePath(viewDef.Scope, namespace, skillSetManager.GetResourcePath())
+	allowed, _, err := policy.AreActionsAllowedOnResource(viewDef, resourcePath, exportedActions)
 	if err != nil {
-		return nil, nil, catalogmanager.Skill{}, err
+		return err
+	}
+	if !allowed {
+		return ErrDisallowedByPolicy.Msg("use of skill is blocked by policy")
 	}
 
-	return viewManager, skillSetManager, skillObj, nil
+	return nil
 }
 
-// validateSkillAndPermissions validates skill input and action permissions
-func validateSkillAndPermissions(ctx context.Context, skillObj catalogmanager.Skill, viewManager policy.ViewManager, skillSetManager catalogmanager.SkillSetManager, inputArgs map[string]any) apperrors.Error {
-	_ = ctx
-
-	// Validate skill input
-	err := skillObj.ValidateInput(inputArgs)
+// validateDependencyDefaults checks that the view grants the actions the catalog's dependency
+// defaults give to the session's dependencies that list no actions of their own. Actions listed
+// by a dependency are not checked here; they are reported by the session's dependency status.
+func validateDependencyDefaults(ctx context.Context, skillSetManager catalogmanager.SkillSetManager, viewDef *policy.ViewDefinition, namespace string, sessionVariables map[string]any) apperrors.Error {
+	deps := skillSetManager.ResolveDependencies(sessionVariables)
+	applied, err := applyDependencyDefaults(ctx, skillSetManager.Metadata().Catalog, deps)
 	if err != nil {
 		return err
 	}
+	for i, dep := range applied {
+		if len(deps[i].Actions) > 0 || dep.IsInline() {
+			continue
+		}
+		if len(dep.Actions) == 0 {
+			return ErrDisallowedByPolicy.Msg("dependency " + dep.Alias + " lists no actions and the catalog declares no defaults for " + string(dep.Kind) + " dependencies")
+		}
+		depPath := policy.NamespacedResourcePath(viewDef.Scope, namespace, dep.Path)
+		allowed, _, err := policy.AreActionsAllowedOnResource(viewDef, depPath, dep.Actions)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrDisallowedByPolicy.Msg("default actions of dependency " + dep.Alias + " are not allowed by view")
+		}
+	}
+	return nil
+}
 
-	// Validate action permissions
-	exportedActions := skillObj.GetExportedActions()
-	viewDef := viewManager.GetViewDefinition()
-	allowed, _, err := policy.AreActionsAllowedOnResource(viewDef, skillSetManager.GetResourcePath(), exportedActions)
-	if err != nil {
-		return err
+// applyDependencyDefaults returns the dependencies with the catalog's default actions set on
+// those that list none. The catalog is only read if some dependency lists no actions.
+func applyDependencyDefaults(ctx context.Context, catalog string, deps []catalogmanager.Dependency) ([]catalogmanager.Dependency, apperrors.Error) {
+	if !slices.ContainsFunc(deps, func(dep catalogmanager.Dependency) bool { return len(dep.Actions) == 0 }) {
+		return deps, nil
 	}
-	if !allowed {
-		return ErrDisallowedByPolicy.Msg("use of skill is blocked by policy")
+	defaults, err := catalogmanager.LoadDependencyDefaults(ctx, catalog)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	return defaults.Apply(deps), nil
 }
 
 // createSessionInfo creates the session info object
-func createSessionInfo(_ SessionSpec, inputArgs map[string]any, sessionVariables map[string]any, viewManager policy.ViewManager, requestOptions *requestOptions) ([]byte, apperrors.Error) {
-	viewDef := viewManager.GetViewDefinition()
+func createSessionInfo(sessionSpec SessionSpec, prepared *preparedSession, requestOptions *requestOptions) ([]byte, apperrors.Error) {
+	viewDef := prepared.viewManager.GetViewDefinition()
+	inputArgs := prepared.inputArgs
+	// Referenced input args are read from the skillset when the session runs
+	if sessionSpec.InputArgsRef != "" {
+		inputArgs = nil
+	}
 	sessionInfo := SessionInfo{
-		SessionVariables: sessionVariables,
+		SessionVariables: prepared.sessionVariables,
 		InputArgs:        inputArgs,
+		InputArgsRef:     sessionSpec.InputArgsRef,
 		ViewDefinition:   viewDef,
 		Interactive:      requestOptions.interactive,
 		CodeChallenge:    requestOptions.codeChallenge,
+		CallbackURL:      sessionSpec.CallbackURL,
+		Environment:      sessionSpec.Environment,
+		ContextOverrides: sessionSpec.ContextOverrides,
+		Namespace:        sessionSpec.Namespace,
+		AdoptionDepth:    prepared.adoptionDepth,
+		LogLevel:         sessionSpec.LogLevel,
 	}
 	sessionInfoJSON, goerr := json.Marshal(sessionInfo)
 	if goerr != nil {
@@ -288,6 +543,14 @@ func createSessionObject(ctx context.Context, sessionSpec SessionSpec, sessionIn
 	skill := path.Base(sessionSpec.SkillPath)
 	skillSetPath := path.Dir(sessionSpec.SkillPath)
 
+	var labels json.RawMessage
+	if len(sessionSpec.Labels) > 0 {
+		var goerr error
+		if labels, goerr = json.Marshal(sessionSpec.Labels); goerr != nil {
+			return nil, ErrInvalidObject.Msg("failed to marshal session labels: " + goerr.Error())
+		}
+	}
+
 	sessionID := uuid.New()
 	session := &models.Session{
 		SessionID:     sessionID,
@@ -304,11 +567,22 @@ func createSessionObject(ctx context.Context, sessionSpec SessionSpec, sessionIn
 		StartedAt:     time.Now(),
 		EndedAt:       time.Time{},
 		ExpiresAt:     time.Now().Add(config.Config().Session.GetExpirationTimeOrDefault()),
+		Labels:        labels,
 	}
 
 	return session, nil
 }
 
+// countActiveSessions returns the number of sessions of the tenant that have not ended.
+func countActiveSessions(ctx context.Context) (int, apperrors.Error) {
+	return db.DB(ctx).CountActiveSessions(ctx, activeSessionStatuses)
+}
+
+// countActiveSessionsByTangent returns the number of active sessions assigned to each tangent.
+func countActiveSessionsByTangent(ctx context.Context) (map[uuid.UUID]int, apperrors.Error) {
+	return db.DB(ctx).CountActiveSessionsByTangent(ctx, activeSessionStatuses)
+}
+
 // Save persists the session to the database.
 // Returns an error if the save operation fails.
 func (s *sessionManager) Save(ctx context.Context) apperrors.Error {
@@ -327,11 +601,101 @@ func GetSession(ctx context.Context, sessionID uuid.UUID) (SessionManager, apper
 	if err != nil {
 		return nil, ErrInvalidObject.Msg("failed to get session: " + err.Error())
 	}
+	return sessionManagerFromModel(ctx, session)
+}
+
+// ReplaySession creates a new session that reuses the skill path, view, session variables, input
+// args, context overrides and labels of an earlier session. Session variables in overrides replace those of the original session.
+// The replay goes through the same validation and policy checks as a new session, and it must
+// resolve to the view the original session adopted. The audit log and token of the original
+// session are not reused.
+func ReplaySession(ctx context.Context, original *models.Session, overrides map[string]any, opts ...RequestOptions) (SessionManager, *tangent.Tangent, apperrors.Error) {
+	var info SessionInfo
+	if err := json.Unmarshal(original.Info, &info); err != nil {
+		return nil, nil, ErrInvalidSession.Msg("unable to read inputs of session " + original.SessionID.String())
+	}
+
+	viewManager, err := resolveViewByID(ctx, original.ViewID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sessionVariables := make(map[string]any, len(info.SessionVariables)+len(overrides))
+	for k, v := range info.SessionVariables {
+		sessionVariables[k] = v
+	}
+	for k, v := range overrides {
+		sessionVariables[k] = v
+	}
+	variablesJSON, goerr := json.Marshal(sessionVariables)
+	if goerr != nil {
+		return nil, nil, ErrInvalidSession.Msg("failed to marshal session variables: " + goerr.Error())
+	}
+	var inputArgsJSON json.RawMessage
+	if info.InputArgsRef == "" {
+		inputArgsJSON, goerr = json.Marshal(info.InputArgs)
+		if goerr != nil {
+			return nil, nil, ErrInvalidSession.Msg("failed to marshal input args: " + goerr.Error())
+		}
+	}
+
+	spec, goerr := json.Marshal(SessionSpec{
+		SkillPath:        path.Join(original.SkillSet, original.Skill),
+		ViewName:         viewManager.Name(),
+		SessionVariables: variablesJSON,
+		InputArgs:        inputArgsJSON,
+		InputArgsRef:     info.InputArgsRef,
+		CallbackURL:      info.CallbackURL,
+		Environment:      info.Environment,
+		ContextOverrides: info.ContextOverrides,
+		Labels:           sessionLabels(ctx, original),
+		Namespace:        info.Namespace,
+		LogLevel:         info.LogLevel,
+	})
+	if goerr != nil {
+		return nil, nil, ErrInvalidSession.Msg("failed to marshal session spec: " + goerr.Error())
+	}
+
+	replay, t, err := NewSession(ctx, spec, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if replay.(*sessionManager).viewManager.ID() != original.ViewID {
+		return nil, nil, ErrDisallowedByPolicy.Msg("view " + viewManager.Name() + " has changed since the original session")
+	}
+	return replay, t, nil
+}
+
+// sessionLabels returns the labels of a stored session.
+func sessionLabels(ctx context.Context, session *models.Session) map[string]string {
+	if len(session.Labels) == 0 {
+		return nil
+	}
+	var labels map[string]string
+	if err := json.Unmarshal(session.Labels, &labels); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal session labels")
+		return nil
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// sessionManagerFromModel creates a session manager for a session loaded from the database,
+// resolving its view and skillset.
+func sessionManagerFromModel(ctx context.Context, session *models.Session) (SessionManager, apperrors.Error) {
 	viewManager, err := resolveViewByID(ctx, session.ViewID)
 	if err != nil {
 		return nil, err
 	}
-	skillSetManager, err := resolveSkillSetManager(ctx, session.SkillSet, viewManager.Scope())
+	var info SessionInfo
+	if len(session.Info) > 0 {
+		if err := json.Unmarshal(session.Info, &info); err != nil {
+			return nil, ErrInvalidSession.Msg("unable to read session info of session " + session.SessionID.String())
+		}
+	}
+	skillSetManager, err := resolveSkillSetManager(ctx, session.SkillSet, sessionScope(viewManager.Scope(), info.Namespace))
 	if err != nil {
 		return nil, err
 	}
@@ -365,14 +729,19 @@ func validateSessionVariables(variables json.RawMessage) schemaerr.ValidationErr
 		return nil
 	}
 
+	if maxSize := config.Config().Session.MaxVariablesSize; maxSize > 0 && len(variables) > maxSize {
+		msg := fmt.Sprintf("session variables size %d bytes exceeds the maximum of %d bytes", len(variables), maxSize)
+		return schemaerr.ValidationErrors{schemaerr.ErrInvalidValue("sessionVariables", msg)}
+	}
+
 	var parsed any
 	if err := json.Unmarshal(variables, &parsed); err != nil {
-		return schemaerr.ValidationErrors{schemaerr.ErrValidationFailed("invalid session variables: " + err.Error())}
+		return schemaerr.ValidationErrors{schemaerr.ErrInvalidValue("sessionVariables", "invalid session variables: "+err.Error())}
 	}
 
 	if err := variableSchemaCompiled.Validate(parsed); err != nil {
 		msg := fmt.Sprintf("session variables must be key-value json objects with max %d properties: %v", config.Config().Session.MaxVariables, err)
-		return schemaerr.ValidationErrors{schemaerr.ErrValidationFailed(msg)}
+		return schemaerr.ValidationErrors{schemaerr.ErrInvalidValue("sessionVariables", msg)}
 	}
 
 	return nil
@@ -430,6 +799,32 @@ func (s *SessionSpec) Validate() schemaerr.ValidationErrors {
 		validationErrors = append(validationErrors, errs...)
 	}
 
+	// Input args are either inline or referenced from a context
+	if len(s.InputArgs) > 0 && s.InputArgsRef != "" {
+		validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("inputArgsRef", "inputArgs and inputArgsRef are mutually exclusive"))
+	}
+
+	// Validate callback URL
+	if s.CallbackURL != "" {
+		if err := validateCallbackURL(s.CallbackURL); err != nil {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("callbackURL", err.Error()))
+		}
+	}
+
+	if err := validateSessionLabels(s.Labels); err != nil {
+		validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("labels", err.Error()))
+	}
+
+	if _, err := s.tangentPin(); err != nil {
+		validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("tangentID", err.Error()))
+	}
+
+	if s.LogLevel != "" {
+		if _, err := zerolog.ParseLevel(s.LogLevel); err != nil {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("logLevel", "unknown log level "+s.LogLevel))
+		}
+	}
+
 	return validationErrors
 }
 
@@ -451,6 +846,40 @@ func resolveViewByID(ctx context.Context, viewID uuid.UUID) (policy.ViewManager,
 	return viewManager, nil
 }
 
+// resolveSessionScope returns the scope the session's objects are resolved in. A namespace
+// override must match the namespace of a view bound to one, be permitted by the view's
+// namespaces, and exist in the view's variant.
+func resolveSessionScope(ctx context.Context, viewManager policy.ViewManager, namespace string) (policy.Scope, apperrors.Error) {
+	viewScope := viewManager.Scope()
+	if namespace == "" || namespace == viewScope.Namespace {
+		return viewScope, nil
+	}
+	if viewScope.Namespace != "" {
+		return policy.Scope{}, ErrDisallowedByPolicy.Msg("view " + viewManager.Name() + " is bound to namespace " + viewScope.Namespace)
+	}
+	if !viewManager.GetViewDefinition().Scope.AllowsNamespace(namespace) {
+		return policy.Scope{}, ErrDisallowedByPolicy.Msg("namespace " + namespace + " is not permitted by view " + viewManager.Name())
+	}
+	if namespace != catcommon.DefaultNamespace {
+		if _, err := db.DB(ctx).GetNamespace(ctx, namespace, catcommon.GetVariantID(ctx)); err != nil {
+			if errors.Is(err, dberror.ErrNotFound) {
+				return policy.Scope{}, ErrInvalidObject.Msg("namespace not found: " + namespace)
+			}
+			log.Ctx(ctx).Error().Err(err).Str("namespace", namespace).Msg("failed to load namespace")
+			return policy.Scope{}, err
+		}
+	}
+	return sessionScope(viewScope, namespace), nil
+}
+
+// sessionScope returns viewScope with the session's namespace override applied.
+func sessionScope(viewScope policy.Scope, namespace string) policy.Scope {
+	if namespace != "" {
+		viewScope.Namespace = namespace
+	}
+	return viewScope
+}
+
 // resolveSkillSetManager creates a new skill set manager for the given path
 func resolveSkillSetManager(ctx context.Context, skillSetPath string, viewScope policy.Scope) (catalogmanager.SkillSetManager, apperrors.Error) {
 	if skillSetPath == "" {
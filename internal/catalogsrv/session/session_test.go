@@ -1,8 +1,11 @@
 package session
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"encoding/json"
 
@@ -196,7 +199,7 @@ func TestNewSession(t *testing.T) {
 			{
 				"intent": "Allow",
 				"actions": ["system.catalog.adoptView"],
-				"targets": ["res://views/parent-view"]
+				"targets": ["res://views/parent-view", "res://views/temporary-view", "res://views/expired-view"]
 			},
 			{
 				"intent": "Allow",
@@ -219,6 +222,25 @@ func TestNewSession(t *testing.T) {
 	}
 	ctx = policy.WithViewDefinition(ctx, &viewDef)
 
+	// Create a view that expires in the future and one that has already expired.
+	// The expired view is written directly since CreateView rejects past expiry times.
+	temporaryView := strings.Replace(strings.Replace(parentView, `"name": "parent-view"`, `"name": "temporary-view"`, 1),
+		`"rules": [`, `"expiresAt": "`+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`", "rules": [`, 1)
+	_, err = policy.CreateView(ctx, []byte(temporaryView), metadata)
+	require.NoError(t, err)
+
+	expiredAt := time.Now().Add(-time.Hour)
+	expiredViewDef := viewDef.DeepCopy()
+	expiredViewDef.ExpiresAt = &expiredAt
+	expiredRules, jsonErr := expiredViewDef.ToJSON()
+	require.NoError(t, jsonErr)
+	require.NoError(t, db.DB(ctx).CreateView(ctx, &models.View{
+		Label:     "expired-view",
+		Rules:     expiredRules,
+		CatalogID: catalogID,
+		CreatedBy: "user/test-user",
+	}))
+
 	tests := []struct {
 		name        string
 		sessionSpec string
@@ -368,6 +390,29 @@ func TestNewSession(t *testing.T) {
 			wantErr: false,
 			errType: ErrInvalidSession,
 		},
+		{
+			name: "view not yet expired",
+			sessionSpec: `{
+				"skillPath": "/skills/test-skillset/test-skill",
+				"viewName": "temporary-view",
+				"inputArgs": {
+					"input": "test"
+				}
+			}`,
+			wantErr: false,
+		},
+		{
+			name: "expired view",
+			sessionSpec: `{
+				"skillPath": "/skills/test-skillset/test-skill",
+				"viewName": "expired-view",
+				"inputArgs": {
+					"input": "test"
+				}
+			}`,
+			wantErr: true,
+			errType: policy.ErrViewExpired,
+		},
 		{
 			name: "non-existent view",
 			sessionSpec: `{
@@ -409,6 +454,41 @@ func TestNewSession(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("validate without creating", func(t *testing.T) {
+		active, err := countActiveSessions(ctx)
+		require.NoError(t, err)
+
+		validationErrors, err := ValidateSession(ctx, []byte(`{
+			"skillPath": "/skills/test-skillset/test-skill",
+			"viewName": "parent-view",
+			"inputArgs": {"input": "test"}
+		}`))
+		require.NoError(t, err)
+		assert.Empty(t, validationErrors)
+
+		invalid := []struct {
+			name  string
+			spec  string
+			field string
+		}{
+			{"non-existent view", `{"skillPath": "/skills/test-skillset/test-skill", "viewName": "non-existent-view"}`, "viewName"},
+			{"expired view", `{"skillPath": "/skills/test-skillset/test-skill", "viewName": "expired-view"}`, "viewName"},
+			{"unknown skill", `{"skillPath": "/skills/test-skillset/unknown-skill", "viewName": "parent-view"}`, "skillPath"},
+			{"invalid input args", `{"skillPath": "/skills/test-skillset/test-skill", "viewName": "parent-view", "inputArgs": {"input": 42}}`, "inputArgs"},
+			{"unknown pinned tangent", `{"skillPath": "/skills/test-skillset/test-skill", "viewName": "parent-view", "inputArgs": {"input": "test"}, "tangentID": "` + uuid.New().String() + `"}`, "tangentID"},
+		}
+		for _, tt := range invalid {
+			validationErrors, err := ValidateSession(ctx, []byte(tt.spec))
+			require.NoError(t, err, tt.name)
+			require.Len(t, validationErrors, 1, tt.name)
+			assert.Equal(t, tt.field, validationErrors[0].Field, tt.name)
+		}
+
+		after, err := countActiveSessions(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, active, after, "validation must not create sessions")
+	})
 }
 
 func TestSessionSpec_Validate(t *testing.T) {
@@ -471,6 +551,142 @@ func TestSessionSpec_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "input args referenced from a context",
+			spec: SessionSpec{
+				SkillPath:    "/skills/test-skill",
+				ViewName:     "test-view",
+				InputArgsRef: "test-context",
+			},
+			wantErr: false,
+		},
+		{
+			name: "both inline and referenced input args",
+			spec: SessionSpec{
+				SkillPath:    "/skills/test-skill",
+				ViewName:     "test-view",
+				InputArgs:    json.RawMessage(`{"input": "test"}`),
+				InputArgsRef: "test-context",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no input args",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid inputArgsRef format",
+			spec: SessionSpec{
+				SkillPath:    "/skills/test-skill",
+				ViewName:     "test-view",
+				InputArgsRef: "invalid context name",
+			},
+			wantErr: true,
+		},
+		{
+			name: "pinned to a tangent ID",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				TangentID: uuid.New().String(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "pinned to a tangent URL",
+			spec: SessionSpec{
+				SkillPath:  "/skills/test-skill",
+				ViewName:   "test-view",
+				TangentURL: "https://tangent-eu.example.com:8468",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid tangent ID",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				TangentID: "tangent-1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "both tangent ID and URL",
+			spec: SessionSpec{
+				SkillPath:  "/skills/test-skill",
+				ViewName:   "test-view",
+				TangentID:  uuid.New().String(),
+				TangentURL: "https://tangent-eu.example.com:8468",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid labels",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				Labels:    map[string]string{"team": "data", "pipeline/run-id": "2024-01-01:42", "empty": ""},
+			},
+			wantErr: false,
+		},
+		{
+			name: "label key with invalid characters",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				Labels:    map[string]string{"team name": "data"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "label key too long",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				Labels:    map[string]string{strings.Repeat("k", MaxSessionLabelKeyLength+1): "data"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "label value with invalid characters",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				Labels:    map[string]string{"team": "data science"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "label value too long",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				Labels:    map[string]string{"team": strings.Repeat("v", MaxSessionLabelValueLength+1)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "debug log level",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				LogLevel:  "debug",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown log level",
+			spec: SessionSpec{
+				SkillPath: "/skills/test-skill",
+				ViewName:  "test-view",
+				LogLevel:  "verbose",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -485,6 +701,112 @@ func TestSessionSpec_Validate(t *testing.T) {
 	}
 }
 
+func TestSessionVariablesSizeLimit(t *testing.T) {
+	config.TestInit()
+	Init()
+
+	maxSize := config.Config().Session.MaxVariablesSize
+	config.Config().Session.MaxVariablesSize = 256
+	defer func() { config.Config().Session.MaxVariablesSize = maxSize }()
+
+	// variablesOfSize returns a single string variable whose JSON encoding is exactly size bytes
+	variablesOfSize := func(size int) json.RawMessage {
+		overhead := len(`{"key1":""}`)
+		return json.RawMessage(`{"key1":"` + strings.Repeat("a", size-overhead) + `"}`)
+	}
+
+	spec := SessionSpec{
+		SkillPath: "/skills/test-skill",
+		ViewName:  "test-view",
+	}
+
+	t.Run("payload at the limit is accepted", func(t *testing.T) {
+		spec.SessionVariables = variablesOfSize(256)
+		assert.Empty(t, spec.Validate())
+	})
+
+	t.Run("payload just under the limit is accepted", func(t *testing.T) {
+		spec.SessionVariables = variablesOfSize(255)
+		assert.Empty(t, spec.Validate())
+	})
+
+	t.Run("payload just over the limit is rejected", func(t *testing.T) {
+		spec.SessionVariables = variablesOfSize(257)
+		validationErrors := spec.Validate()
+		require.Len(t, validationErrors, 1)
+		assert.Contains(t, validationErrors.Error(), "257 bytes")
+
+		_, err := resolveSessionSpec(marshalJSON(t, spec))
+		assert.ErrorIs(t, err, ErrInvalidSession)
+		assert.Contains(t, err.Error(), "257 bytes exceeds the maximum of 256 bytes")
+	})
+}
+
+func TestValidateSession(t *testing.T) {
+	config.TestInit()
+	Init()
+
+	ctx := catcommon.WithCatalogContext(context.Background(), &catcommon.CatalogContext{
+		CatalogID:   uuid.New(),
+		Catalog:     "test-catalog",
+		UserContext: &catcommon.UserContext{UserID: "users/testuser"},
+	})
+
+	tests := []struct {
+		name   string
+		spec   string
+		fields []string
+	}{
+		{
+			name:   "missing skill path and view",
+			spec:   `{}`,
+			fields: []string{"skillPath", "viewName"},
+		},
+		{
+			name:   "invalid skill path",
+			spec:   `{"skillPath": "invalid/path/format", "viewName": "test-view"}`,
+			fields: []string{"skillPath"},
+		},
+		{
+			name:   "invalid namespace",
+			spec:   `{"skillPath": "/skills/test-skill", "viewName": "test-view", "namespace": "Invalid Namespace"}`,
+			fields: []string{"namespace"},
+		},
+		{
+			name:   "input args and input args ref",
+			spec:   `{"skillPath": "/skills/test-skill", "viewName": "test-view", "inputArgs": {"a": 1}, "inputArgsRef": "ctx"}`,
+			fields: []string{"inputArgsRef"},
+		},
+		{
+			name:   "invalid session variables",
+			spec:   `{"skillPath": "/skills/test-skill", "viewName": "test-view", "sessionVariables": {"invalid@key": "value"}}`,
+			fields: []string{"sessionVariables"},
+		},
+		{
+			name:   "invalid callback url",
+			spec:   `{"skillPath": "/skills/test-skill", "viewName": "test-view", "callbackURL": "not a url"}`,
+			fields: []string{"callbackURL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validationErrors, err := ValidateSession(ctx, []byte(tt.spec))
+			require.NoError(t, err)
+			var fields []string
+			for _, ve := range validationErrors {
+				fields = append(fields, ve.Field)
+			}
+			assert.ElementsMatch(t, tt.fields, fields)
+		})
+	}
+
+	t.Run("malformed spec", func(t *testing.T) {
+		_, err := ValidateSession(ctx, []byte(`{`))
+		assert.ErrorIs(t, err, ErrInvalidSession)
+	})
+}
+
 func marshalJSON(t *testing.T, v interface{}) []byte {
 	t.Helper()
 	jsonBytes, goerr := json.Marshal(v)
@@ -858,4 +1180,41 @@ func TestSessionSaveAndGet(t *testing.T) {
 		assert.Equal(t, "updated_input", retrievedInfo.InputArgs["input"])
 		assert.Equal(t, "new_value", retrievedInfo.InputArgs["new_param"])
 	})
+
+	t.Run("replay creates a new session with copied inputs", func(t *testing.T) {
+		originalSpec := `{
+			"skillPath": "/skills/test-skillset/test-skill",
+			"viewName": "parent-view",
+			"sessionVariables": {
+				"key1": "value1",
+				"key2": "value2"
+			},
+			"inputArgs": {
+				"input": "replayed_input"
+			}
+		}`
+		original, _, err := NewSession(ctx, []byte(originalSpec))
+		require.NoError(t, err)
+		require.NoError(t, original.Save(ctx))
+		originalModel := original.(*sessionManager).session
+
+		replay, _, err := ReplaySession(ctx, originalModel, map[string]any{"key2": "overridden"})
+		require.NoError(t, err)
+		require.NoError(t, replay.Save(ctx))
+
+		replayModel := replay.(*sessionManager).session
+		assert.NotEqual(t, originalModel.SessionID, replayModel.SessionID)
+		assert.Equal(t, originalModel.SkillSet, replayModel.SkillSet)
+		assert.Equal(t, originalModel.Skill, replayModel.Skill)
+		assert.Equal(t, originalModel.ViewID, replayModel.ViewID)
+		assert.Equal(t, string(SessionStatusCreated), replayModel.StatusSummary)
+
+		retrieved, err := GetSession(ctx, replayModel.SessionID)
+		require.NoError(t, err)
+		var replayInfo SessionInfo
+		require.NoError(t, json.Unmarshal(retrieved.(*sessionManager).session.Info, &replayInfo))
+		assert.Equal(t, "replayed_input", replayInfo.InputArgs["input"])
+		assert.Equal(t, "value1", replayInfo.SessionVariables["key1"])
+		assert.Equal(t, "overridden", replayInfo.SessionVariables["key2"])
+	})
 }
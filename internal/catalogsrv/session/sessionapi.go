@@ -3,12 +3,15 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/apis"
 	"github.com/tansive/tansive/internal/catalogsrv/auth"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
@@ -30,26 +33,77 @@ func newSession(r *http.Request) (*httpx.Response, error) {
 		return nil, httpx.ErrUnableToReadRequest()
 	}
 
+	interactive, codeChallenge, err := sessionRequestOptions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	session, tangent, err := NewSession(ctx, req, WithInteractive(interactive), WithCodeChallenge(codeChallenge))
+	if err != nil {
+		return nil, err
+	}
+
+	return createdSessionRsp(ctx, session, tangent, codeChallenge)
+}
+
+// ValidateSessionRsp is the response to a session validation request.
+type ValidateSessionRsp struct {
+	Valid  bool                   `json:"valid"`
+	Errors []apis.ValidationError `json:"errors,omitempty"`
+}
+
+// validateSession runs the checks performed when creating a session without creating it or
+// assigning it to a tangent.
+func validateSession(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+
+	req, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	validationErrors, appErr := ValidateSession(ctx, req)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	rsp := ValidateSessionRsp{Valid: len(validationErrors) == 0}
+	for _, ve := range validationErrors {
+		rsp.Errors = append(rsp.Errors, apis.NewValidationError(ve))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// Until we support a full Tangent-Server SSE connection, we use the user to mediate
+const tempOAuth = true
+
+// sessionRequestOptions reads the interactive flag and code challenge of a session creation request.
+func sessionRequestOptions(r *http.Request) (bool, string, error) {
 	interactive := r.URL.Query().Get("interactive") == "true"
-	// Until we support a full Tangent-Server SSE connection, we use the user to mediate
-	temp_oauth := true
 	codeChallenge := ""
-	if interactive || temp_oauth {
+	if interactive || tempOAuth {
 		//We need to create a oauth2.0 session, so look for a code challenge
 		codeChallenge = r.URL.Query().Get("code_challenge")
 		if codeChallenge == "" {
-			return nil, httpx.ErrInvalidRequest("code_challenge is required")
+			return false, "", httpx.ErrInvalidRequest("code_challenge is required")
 		}
 	}
+	return interactive, codeChallenge, nil
+}
 
-	session, tangent, err := NewSession(ctx, req, WithInteractive(interactive), WithCodeChallenge(codeChallenge))
-	if err != nil {
-		return nil, err
-	}
-
+// createdSessionRsp saves a newly created session and returns the response for its creator.
+func createdSessionRsp(ctx context.Context, session SessionManager, tangent *tangent.Tangent, codeChallenge string) (*httpx.Response, error) {
 	session.Save(ctx)
 
-	if interactive || temp_oauth {
+	if codeChallenge != "" {
 		log.Ctx(ctx).Info().Msgf("Creating auth code for session %s", session.ID().String())
 		authCode, err := CreateAuthCode(ctx, session, codeChallenge)
 		if err != nil {
@@ -73,6 +127,56 @@ func newSession(r *http.Request) (*httpx.Response, error) {
 	}, nil
 }
 
+// replaySession creates a new session from the skill path, view, session variables and input args
+// of an existing session, optionally overriding session variables. It is used to reproduce bugs.
+func replaySession(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	sessionID := chi.URLParam(r, "sessionID")
+	if sessionID == "" {
+		return nil, httpx.ErrInvalidRequest("sessionID is required")
+	}
+	sessionUUID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+
+	var req SessionReplayReq
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, httpx.ErrUnableToReadRequest()
+		}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &req); err != nil {
+				return nil, httpx.ErrInvalidRequest("invalid request body")
+			}
+		}
+	}
+
+	interactive, codeChallenge, err := sessionRequestOptions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	original, apperr := db.DB(ctx).GetSession(ctx, sessionUUID)
+	if apperr != nil {
+		log.Ctx(ctx).Error().Err(apperr).Msg("failed to get session")
+		return nil, ErrUnableToGetSession
+	}
+	if !canAccessSession(ctx, original) {
+		return nil, ErrUnableToGetSession
+	}
+
+	session, tangent, apperr := ReplaySession(ctx, original, req.SessionVariables, WithInteractive(interactive), WithCodeChallenge(codeChallenge))
+	if apperr != nil {
+		return nil, apperr
+	}
+	log.Ctx(ctx).Info().Str("original_session_id", sessionID).Str("session_id", session.ID().String()).Msg("replaying session")
+
+	return createdSessionRsp(ctx, session, tangent, codeChallenge)
+}
+
 func getExecutionState(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
 	sessionID := catcommon.GetSessionID(ctx)
@@ -159,7 +263,18 @@ func createSessionToken(ctx context.Context, session SessionManager) (string, ti
 		additionalClaims["created_by"] = "user/" + userID
 	}
 
-	token, expiry, err := auth.CreateAccessToken(ctx, view, auth.WithAdditionalClaims(additionalClaims))
+	// the session's token carries the adoption depth of its view, so that views adopted with it
+	// extend the same chain
+	var adoptionDepth int
+	if executionState := session.GetExecutionState(ctx); executionState != nil {
+		adoptionDepth = executionState.AdoptionDepth
+	}
+
+	token, expiry, err := auth.CreateAccessToken(ctx, view,
+		auth.WithAdditionalClaims(additionalClaims),
+		auth.WithAdoptionDepth(adoptionDepth),
+		auth.WithSessionID(session.ID()),
+	)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -224,12 +339,27 @@ func updateExecutionState(r *http.Request) (*httpx.Response, error) {
 		}
 		log.Ctx(ctx).Info().Msgf("wrote audit log to %s", logFilePath)
 		update.Status.AuditLog = logFilePath // replace the audit log with the file path
+	} else if logFilePath := findAuditLogFile(session.ID()); logFilePath != "" {
+		// the audit log was uploaded in parts before the status update
+		update.Status.AuditLog = logFilePath
+	}
+
+	if update.Status.AuditLog != "" {
+		if err := exportAuditLog(ctx, session.CatalogID(), session.ID(), update.Status.AuditLog); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to export audit log to catalog destination")
+			// the audit log remains available from the server
+		}
 	}
 
 	if !IsValidSessionStatus(update.StatusSummary) {
 		return nil, ErrInvalidRequest.Msg("invalid status summary")
 	}
 
+	// a session marked stale stays stale when its tangent reports its final state after reconnecting
+	if session.GetStatusSummaryInfo(ctx).StatusSummary == SessionStatusStale {
+		update.StatusSummary = SessionStatusStale
+	}
+
 	session.SetStatus(ctx, update.StatusSummary, update.Status)
 	return &httpx.Response{
 		StatusCode: http.StatusOK,
@@ -239,7 +369,19 @@ func updateExecutionState(r *http.Request) (*httpx.Response, error) {
 
 func getSessions(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
-	sessionList, err := db.DB(ctx).ListSessionsByCatalog(ctx, catcommon.GetCatalogID(ctx))
+
+	// label.<key>=<value> query parameters select the sessions carrying all of the given labels
+	labels := make(map[string]string)
+	for param := range r.URL.Query() {
+		if key, ok := strings.CutPrefix(param, "label."); ok {
+			labels[key] = r.URL.Query().Get(param)
+		}
+	}
+	if err := validateSessionLabels(labels); err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+
+	sessionList, err := db.DB(ctx).ListSessionsByCatalog(ctx, catcommon.GetCatalogID(ctx), labels)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to get session")
 		return nil, ErrUnableToGetSession
@@ -247,20 +389,7 @@ func getSessions(r *http.Request) (*httpx.Response, error) {
 
 	sessionListInfo := make([]SessionSummaryInfo, len(sessionList))
 	for i, session := range sessionList {
-		var status ExecutionStatus
-		if err := json.Unmarshal([]byte(session.Status), &status); err != nil {
-			log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal status")
-			status = ExecutionStatus{}
-		}
-		sessionListInfo[i] = SessionSummaryInfo{
-			SessionID:     session.SessionID,
-			UserID:        session.UserID,
-			CreatedAt:     session.CreatedAt,
-			StartedAt:     session.StartedAt,
-			UpdatedAt:     session.UpdatedAt,
-			StatusSummary: SessionStatus(session.StatusSummary),
-			Error:         status.Error,
-		}
+		sessionListInfo[i] = *(&sessionManager{session: session}).GetStatusSummaryInfo(ctx)
 	}
 
 	return &httpx.Response{
@@ -288,27 +417,125 @@ func getSessionSummaryByID(r *http.Request) (*httpx.Response, error) {
 		return nil, ErrUnableToGetSession
 	}
 
-	var status ExecutionStatus
-	if err := json.Unmarshal([]byte(session.Status), &status); err != nil {
-		log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal status")
-		status = ExecutionStatus{}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   (&sessionManager{session: session}).GetStatusSummaryInfo(ctx),
+	}, nil
+}
+
+// getSessionDependencies lists the dependencies of a session's skillset and whether the session's
+// view grants the actions each dependency requires.
+func getSessionDependencies(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	sessionID := chi.URLParam(r, "sessionID")
+	if sessionID == "" {
+		return nil, httpx.ErrInvalidRequest("sessionID is required")
+	}
+
+	sessionUUID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+
+	session, apperr := db.DB(ctx).GetSession(ctx, sessionUUID)
+	if apperr != nil {
+		log.Ctx(ctx).Error().Err(apperr).Msg("failed to get session")
+		return nil, ErrUnableToGetSession
+	}
+	if !canAccessSession(ctx, session) {
+		return nil, ErrUnableToGetSession
+	}
+
+	sm, apperr := sessionManagerFromModel(ctx, session)
+	if apperr != nil {
+		return nil, apperr
+	}
+	deps, apperr := sm.GetDependencyStatus(ctx)
+	if apperr != nil {
+		return nil, apperr
 	}
 
-	sessionSummaryInfo := SessionSummaryInfo{
-		SessionID:     session.SessionID,
-		UserID:        session.UserID,
-		CreatedAt:     session.CreatedAt,
-		StartedAt:     session.StartedAt,
-		UpdatedAt:     session.UpdatedAt,
-		StatusSummary: SessionStatus(session.StatusSummary),
-		Error:         status.Error,
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: SessionDependenciesRsp{
+			SessionID:    session.SessionID,
+			Dependencies: deps,
+		},
+	}, nil
+}
+
+// MaxSessionSummaryBatchSize is the maximum number of session IDs in a batch summary request
+const MaxSessionSummaryBatchSize = 500
+
+// getSessionSummaryBatch returns the summaries of a list of sessions in one response.
+// Sessions that do not exist, or that the caller may not access, are marked as not found.
+func getSessionSummaryBatch(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	var req SessionSummaryBatchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid request body")
 	}
+	if len(req.SessionIDs) == 0 {
+		return nil, ErrInvalidRequest.Msg("sessionIDs is required")
+	}
+	if len(req.SessionIDs) > MaxSessionSummaryBatchSize {
+		return nil, ErrInvalidRequest.Msg(fmt.Sprintf("at most %d session IDs may be requested", MaxSessionSummaryBatchSize))
+	}
+
+	sessionIDs := make([]uuid.UUID, 0, len(req.SessionIDs))
+	for _, id := range req.SessionIDs {
+		sessionUUID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, ErrInvalidRequest.Msg("invalid sessionID " + id)
+		}
+		sessionIDs = append(sessionIDs, sessionUUID)
+	}
+
+	sessions, err := db.DB(ctx).ListSessionsByIDs(ctx, sessionIDs)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to get sessions")
+		return nil, ErrUnableToGetSession
+	}
+	sessionsByID := make(map[uuid.UUID]*models.Session, len(sessions))
+	for _, session := range sessions {
+		if canAccessSession(ctx, session) {
+			sessionsByID[session.SessionID] = session
+		}
+	}
+
+	rsp := SessionSummaryBatchRsp{
+		Sessions: make([]SessionSummaryBatchItem, len(sessionIDs)),
+	}
+	for i, id := range sessionIDs {
+		item := SessionSummaryBatchItem{SessionID: req.SessionIDs[i]}
+		if session, ok := sessionsByID[id]; ok {
+			item.Summary = (&sessionManager{session: session}).GetStatusSummaryInfo(ctx)
+		} else {
+			item.NotFound = true
+		}
+		rsp.Sessions[i] = item
+	}
+
 	return &httpx.Response{
 		StatusCode: http.StatusOK,
-		Response:   sessionSummaryInfo,
+		Response:   rsp,
 	}, nil
 }
 
+// canAccessSession reports whether the caller may read a session. Sessions are visible within
+// their catalog, and a session token only grants access to the session it was issued for.
+func canAccessSession(ctx context.Context, session *models.Session) bool {
+	if session.CatalogID != catcommon.GetCatalogID(ctx) {
+		return false
+	}
+	if tokenSessionID := catcommon.GetSessionID(ctx); tokenSessionID != uuid.Nil && tokenSessionID != session.SessionID {
+		return false
+	}
+	return true
+}
+
 // This flow is temporary until we support a full Tangent-Server SSE connection
 func initializeStopSession(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
@@ -406,6 +633,12 @@ func getAuditLogByID(r *http.Request) (*httpx.Response, error) {
 		return nil, httpx.ErrInvalidRequest("invalid sessionID")
 	}
 
+	if findAuditLogFile(sessionUUID) == "" {
+		if _, err := GetAuditLogTombstone(sessionUUID); err == nil {
+			return nil, ErrAuditLogPruned
+		}
+	}
+
 	auditLog, err := EncodeAuditLogFile(ctx, sessionUUID)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to encode audit log")
@@ -430,6 +663,15 @@ func getAuditLogVerificationKeyByID(r *http.Request) (*httpx.Response, error) {
 		return nil, httpx.ErrInvalidRequest("invalid sessionID")
 	}
 
+	// the verification key of a pruned audit log is kept in its tombstone, which outlives the session record
+	if tombstone, err := GetAuditLogTombstone(sessionUUID); err == nil &&
+		tombstone.TenantID == catcommon.GetTenantID(ctx) && len(tombstone.AuditLogVerificationKey) > 0 {
+		return &httpx.Response{
+			StatusCode: http.StatusOK,
+			Response:   AuditLogVerificationKey{Key: tombstone.AuditLogVerificationKey},
+		}, nil
+	}
+
 	session, err := db.DB(ctx).GetSession(ctx, sessionUUID)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to get session")
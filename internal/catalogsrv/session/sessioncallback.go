@@ -0,0 +1,142 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/auth/keymanager"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/common/apperrors"
+)
+
+// Headers set on session callback requests. The signature is computed over
+// "<timestamp>\n<body>" with the server's active signing key, which is published
+// through the JWKS endpoint.
+const (
+	CallbackSignatureHeader          = "X-Tansive-Signature"
+	CallbackSignatureTimestampHeader = "X-Tansive-Signature-Timestamp"
+	CallbackKeyIDHeader              = "X-Tansive-Key-ID"
+)
+
+var (
+	// callbackHTTPClient is the client used to deliver session callbacks
+	callbackHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	// callbackRetryDelay is the initial delay between callback delivery attempts
+	callbackRetryDelay = 1 * time.Second
+	// getCallbackSigningKey returns the key used to sign callback payloads
+	getCallbackSigningKey = func(ctx context.Context) (*keymanager.SigningKey, apperrors.Error) {
+		return keymanager.GetKeyManager().GetActiveKey(ctx)
+	}
+	// pendingCallbacks tracks callbacks being delivered in the background
+	pendingCallbacks sync.WaitGroup
+)
+
+// isTerminalSessionStatus reports whether a session in the given status will not run again.
+func isTerminalSessionStatus(status SessionStatus) bool {
+	switch status {
+	case SessionStatusCompleted, SessionStatusFailed, SessionStatusExpired, SessionStatusCancelled, SessionStatusTerminated, SessionStatusStale:
+		return true
+	}
+	return false
+}
+
+// validateCallbackURL checks that the callback URL uses https and that its host is
+// in the configured allowlist. An entry of the form "*.example.com" matches any
+// subdomain of example.com.
+func validateCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback URL: %v", err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("callback URL must use https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("callback URL must include a host")
+	}
+	for _, allowed := range config.Config().Session.CallbackAllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed {
+			return nil
+		}
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok && strings.HasSuffix(host, "."+suffix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("callback host %s is not allowed", host)
+}
+
+// notifyStatusChange delivers the session summary to the session's callback URL in the
+// background when the session moves from a non-terminal to a terminal status.
+func (s *sessionManager) notifyStatusChange(ctx context.Context, previous, current SessionStatus) {
+	if isTerminalSessionStatus(previous) || !isTerminalSessionStatus(current) {
+		return
+	}
+	sessionInfo := SessionInfo{}
+	if err := json.Unmarshal(s.session.Info, &sessionInfo); err != nil || sessionInfo.CallbackURL == "" {
+		return
+	}
+	summary := s.GetStatusSummaryInfo(ctx)
+	ctx = context.WithoutCancel(ctx)
+	pendingCallbacks.Add(1)
+	go func() {
+		defer pendingCallbacks.Done()
+		if err := deliverSessionCallback(ctx, sessionInfo.CallbackURL, summary); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("session_id", summary.SessionID.String()).Msg("failed to deliver session callback")
+		}
+	}()
+}
+
+// deliverSessionCallback POSTs the signed session summary to the callback URL, retrying
+// up to the configured number of attempts until a 2xx response is received.
+func deliverSessionCallback(ctx context.Context, callbackURL string, summary *SessionSummaryInfo) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session summary: %w", err)
+	}
+	key, apperr := getCallbackSigningKey(ctx)
+	if apperr != nil {
+		return fmt.Errorf("failed to get signing key: %w", apperr)
+	}
+
+	return retry.Do(func() error {
+		timestamp := time.Now().UTC().Format(time.RFC3339)
+		signature := ed25519.Sign(key.PrivateKey, []byte(timestamp+"\n"+string(body)))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(CallbackSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+		req.Header.Set(CallbackSignatureTimestampHeader, timestamp)
+		req.Header.Set(CallbackKeyIDHeader, key.KeyID.String())
+
+		rsp, err := callbackHTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer rsp.Body.Close()
+		if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+			return fmt.Errorf("callback returned status %d", rsp.StatusCode)
+		}
+		return nil
+	},
+		retry.Context(ctx),
+		retry.Attempts(uint(config.Config().Session.CallbackMaxAttempts)),
+		retry.Delay(callbackRetryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true))
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/auth/keymanager"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// setupCallbackTest points session callbacks at a TLS test server and returns a counter of
+// received requests. handler decides the status code for each request.
+func setupCallbackTest(t *testing.T, handler func(n int32, r *http.Request) int) (*httptest.Server, *atomic.Int32, ed25519.PublicKey) {
+	t.Helper()
+	config.TestInit()
+
+	var calls atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.WriteHeader(handler(n, r))
+	}))
+	t.Cleanup(server.Close)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := uuid.New()
+
+	origClient, origDelay, origKey := callbackHTTPClient, callbackRetryDelay, getCallbackSigningKey
+	origHosts, origAttempts := config.Config().Session.CallbackAllowedHosts, config.Config().Session.CallbackMaxAttempts
+	t.Cleanup(func() {
+		callbackHTTPClient, callbackRetryDelay, getCallbackSigningKey = origClient, origDelay, origKey
+		config.Config().Session.CallbackAllowedHosts = origHosts
+		config.Config().Session.CallbackMaxAttempts = origAttempts
+	})
+
+	callbackHTTPClient = server.Client()
+	callbackRetryDelay = time.Millisecond
+	getCallbackSigningKey = func(ctx context.Context) (*keymanager.SigningKey, apperrors.Error) {
+		return &keymanager.SigningKey{KeyID: keyID, PrivateKey: priv, PublicKey: pub}, nil
+	}
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	config.Config().Session.CallbackAllowedHosts = []string{serverURL.Hostname()}
+	config.Config().Session.CallbackMaxAttempts = 3
+
+	return server, &calls, pub
+}
+
+func newCallbackSession(t *testing.T, callbackURL string, status SessionStatus) *sessionManager {
+	t.Helper()
+	info, err := json.Marshal(SessionInfo{CallbackURL: callbackURL})
+	require.NoError(t, err)
+	return &sessionManager{
+		session: &models.Session{
+			SessionID:     uuid.New(),
+			UserID:        "user1",
+			StatusSummary: string(status),
+			Info:          info,
+		},
+	}
+}
+
+func TestSessionCallback(t *testing.T) {
+	t.Run("completed session fires the callback once", func(t *testing.T) {
+		var body []byte
+		var headers http.Header
+		server, calls, pub := setupCallbackTest(t, func(n int32, r *http.Request) int {
+			body, _ = io.ReadAll(r.Body)
+			headers = r.Header.Clone()
+			return http.StatusOK
+		})
+		ctx := context.Background()
+		s := newCallbackSession(t, server.URL+"/hooks/session", SessionStatusRunning)
+
+		s.updateStatus(ctx, SessionStatusRunning, SessionStatusCompleted, nil)
+		pendingCallbacks.Wait()
+		// a repeated terminal update does not fire again
+		s.updateStatus(ctx, SessionStatusCompleted, SessionStatusCompleted, nil)
+		pendingCallbacks.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+
+		var summary SessionSummaryInfo
+		require.NoError(t, json.Unmarshal(body, &summary))
+		assert.Equal(t, s.session.SessionID, summary.SessionID)
+		assert.Equal(t, SessionStatusCompleted, summary.StatusSummary)
+
+		signature, err := base64.StdEncoding.DecodeString(headers.Get(CallbackSignatureHeader))
+		require.NoError(t, err)
+		timestamp := headers.Get(CallbackSignatureTimestampHeader)
+		assert.True(t, ed25519.Verify(pub, []byte(timestamp+"\n"+string(body)), signature))
+		assert.NotEmpty(t, headers.Get(CallbackKeyIDHeader))
+	})
+
+	t.Run("non-terminal status does not fire the callback", func(t *testing.T) {
+		server, calls, _ := setupCallbackTest(t, func(n int32, r *http.Request) int { return http.StatusOK })
+		s := newCallbackSession(t, server.URL, SessionStatusCreated)
+
+		s.updateStatus(context.Background(), SessionStatusCreated, SessionStatusRunning, nil)
+		pendingCallbacks.Wait()
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("failing callback is retried until it succeeds", func(t *testing.T) {
+		server, calls, _ := setupCallbackTest(t, func(n int32, r *http.Request) int {
+			if n < 3 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		})
+		s := newCallbackSession(t, server.URL, SessionStatusRunning)
+
+		err := deliverSessionCallback(context.Background(), server.URL, s.GetStatusSummaryInfo(context.Background()))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("failing callback stops after max attempts", func(t *testing.T) {
+		server, calls, _ := setupCallbackTest(t, func(n int32, r *http.Request) int {
+			return http.StatusInternalServerError
+		})
+		config.Config().Session.CallbackMaxAttempts = 2
+		s := newCallbackSession(t, server.URL, SessionStatusRunning)
+
+		err := deliverSessionCallback(context.Background(), server.URL, s.GetStatusSummaryInfo(context.Background()))
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	config.TestInit()
+	origHosts := config.Config().Session.CallbackAllowedHosts
+	defer func() { config.Config().Session.CallbackAllowedHosts = origHosts }()
+	config.Config().Session.CallbackAllowedHosts = []string{"hooks.example.com", "*.orchestrator.io"}
+
+	assert.NoError(t, validateCallbackURL("https://hooks.example.com/session"))
+	assert.NoError(t, validateCallbackURL("https://us-east.orchestrator.io:8443/done"))
+	assert.Error(t, validateCallbackURL("http://hooks.example.com/session"))
+	assert.Error(t, validateCallbackURL("https://evil.example.com/session"))
+	assert.Error(t, validateCallbackURL("https://orchestrator.io/done"))
+	assert.Error(t, validateCallbackURL("not a url"))
+
+	spec := SessionSpec{
+		SkillPath:   "/skills/test-skill",
+		ViewName:    "test-view",
+		CallbackURL: "https://evil.example.com/session",
+	}
+	assert.NotEmpty(t, spec.Validate())
+	spec.CallbackURL = "https://hooks.example.com/session"
+	assert.Empty(t, spec.Validate())
+}
@@ -3,8 +3,10 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
@@ -16,11 +18,13 @@ type SessionManager interface {
 	ID() uuid.UUID
 	UserID() string
 	TangentID() uuid.UUID
+	CatalogID() uuid.UUID
 	Save(ctx context.Context) apperrors.Error
 	GetViewManager(ctx context.Context) (policy.ViewManager, apperrors.Error)
 	GetExecutionState(ctx context.Context) *ExecutionState
 	SetStatusSummary(ctx context.Context, statusSummary SessionStatus) apperrors.Error
 	GetStatusSummaryInfo(ctx context.Context) *SessionSummaryInfo
+	GetDependencyStatus(ctx context.Context) ([]SessionDependencyStatus, apperrors.Error)
 	SetStatus(ctx context.Context, statusSummary SessionStatus, status ExecutionStatus) apperrors.Error
 }
 
@@ -36,6 +40,10 @@ func (s *sessionManager) TangentID() uuid.UUID {
 	return s.session.TangentID
 }
 
+func (s *sessionManager) CatalogID() uuid.UUID {
+	return s.session.CatalogID
+}
+
 func (s *sessionManager) GetViewManager(ctx context.Context) (policy.ViewManager, apperrors.Error) {
 	return s.viewManager, nil
 }
@@ -54,19 +62,24 @@ func (s *sessionManager) GetExecutionState(ctx context.Context) *ExecutionState
 		ViewDefinition:   s.viewManager.GetViewDefinition(),
 		SessionVariables: sessionInfo.SessionVariables,
 		InputArgs:        sessionInfo.InputArgs,
+		InputArgsRef:     sessionInfo.InputArgsRef,
 		Catalog:          s.viewManager.Scope().Catalog,
 		Variant:          s.viewManager.Scope().Variant,
-		Namespace:        s.viewManager.Scope().Namespace,
+		Namespace:        sessionScope(s.viewManager.Scope(), sessionInfo.Namespace).Namespace,
 		TenantID:         catcommon.GetTenantID(ctx),
+		Environment:      sessionInfo.Environment,
+		ContextOverrides: sessionInfo.ContextOverrides,
+		AdoptionDepth:    sessionInfo.AdoptionDepth,
+		LogLevel:         sessionInfo.LogLevel,
 	}
 }
 
 func (s *sessionManager) SetStatusSummary(ctx context.Context, statusSummary SessionStatus) apperrors.Error {
-	s.session.StatusSummary = string(statusSummary)
-	err := db.DB(ctx).UpdateSessionStatus(ctx, s.session.SessionID, string(statusSummary), s.session.Status)
+	previous, err := persistSessionStatus(ctx, s.session.SessionID, SessionStatus(s.session.StatusSummary), statusSummary, s.session.Status)
 	if err != nil {
 		return err
 	}
+	s.updateStatus(ctx, previous, statusSummary, s.session.Status)
 	return nil
 }
 
@@ -75,13 +88,38 @@ func (s *sessionManager) SetStatus(ctx context.Context, statusSummary SessionSta
 	if err != nil {
 		return ErrInvalidObject.Msg("failed to marshal status: " + err.Error())
 	}
-	err = db.DB(ctx).UpdateSessionStatus(ctx, s.session.SessionID, string(statusSummary), statusJSON)
-	if err != nil {
-		return ErrInvalidObject.Msg("failed to update session status: " + err.Error())
+	previous, apperr := persistSessionStatus(ctx, s.session.SessionID, SessionStatus(s.session.StatusSummary), statusSummary, statusJSON)
+	if apperr != nil {
+		return ErrInvalidObject.Msg("failed to update session status: " + apperr.Error())
 	}
+	s.updateStatus(ctx, previous, statusSummary, statusJSON)
 	return nil
 }
 
+// persistSessionStatus stores a session's status and returns the status it replaced. The end
+// time of the session is recorded when it reaches a terminal status. The replaced status of a
+// terminal update is read from the database, so that when two managers of the same session end
+// it, only one of them sees a non-terminal previous status and fires the session callback.
+func persistSessionStatus(ctx context.Context, sessionID uuid.UUID, previous, statusSummary SessionStatus, status json.RawMessage) (SessionStatus, apperrors.Error) {
+	if isTerminalSessionStatus(statusSummary) {
+		stored, err := db.DB(ctx).UpdateSessionEnd(ctx, sessionID, string(statusSummary), status)
+		return SessionStatus(stored), err
+	}
+	return previous, db.DB(ctx).UpdateSessionStatus(ctx, sessionID, string(statusSummary), status)
+}
+
+// updateStatus records a persisted status change on the in-memory session and fires the
+// session callback if the session has reached a terminal status.
+func (s *sessionManager) updateStatus(ctx context.Context, previous, statusSummary SessionStatus, status json.RawMessage) {
+	s.session.StatusSummary = string(statusSummary)
+	s.session.Status = status
+	s.session.UpdatedAt = time.Now()
+	if isTerminalSessionStatus(statusSummary) {
+		s.session.EndedAt = s.session.UpdatedAt
+	}
+	s.notifyStatusChange(ctx, previous, statusSummary)
+}
+
 func (s *sessionManager) GetStatusSummaryInfo(ctx context.Context) *SessionSummaryInfo {
 	var status ExecutionStatus
 	if err := json.Unmarshal(s.session.Status, &status); err != nil {
@@ -96,5 +134,74 @@ func (s *sessionManager) GetStatusSummaryInfo(ctx context.Context) *SessionSumma
 		UpdatedAt:     s.session.UpdatedAt,
 		StatusSummary: SessionStatus(s.session.StatusSummary),
 		Error:         status.Error,
+		Labels:        sessionLabels(ctx, s.session),
+	}
+}
+
+// GetDependencyStatus returns each dependency declared by the session's skillset along with
+// whether the session's view grants the actions the dependency requires. Dependencies that list
+// no actions require the catalog's default actions for their kind.
+func (s *sessionManager) GetDependencyStatus(ctx context.Context) ([]SessionDependencyStatus, apperrors.Error) {
+	var info SessionInfo
+	if len(s.session.Info) > 0 {
+		if err := json.Unmarshal(s.session.Info, &info); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal session info")
+			return nil, ErrInvalidObject.Msg("invalid session info")
+		}
+	}
+	viewDef := info.ViewDefinition
+	if viewDef == nil {
+		viewDef = s.viewManager.GetViewDefinition()
+	}
+	metadata, err := s.skillSetManager.GetSkillMetadata()
+	if err != nil {
+		return nil, err
+	}
+	deps, err := applyDependencyDefaults(ctx, s.skillSetManager.Metadata().Catalog, metadata.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	return dependencyStatus(deps, viewDef, info.Namespace, info.SessionVariables), nil
+}
+
+// dependencyStatus evaluates each dependency's actions against viewDef, with dependency paths
+// resolved in the session's namespace override if it has one.
+func dependencyStatus(deps []catalogmanager.Dependency, viewDef *policy.ViewDefinition, namespace string, sessionVariables map[string]any) []SessionDependencyStatus {
+	statuses := make([]SessionDependencyStatus, 0, len(deps))
+	for _, dep := range deps {
+		status := SessionDependencyStatus{
+			Path:     dep.Path,
+			Kind:     string(dep.Kind),
+			Alias:    dep.Alias,
+			Actions:  dep.Actions,
+			Required: dep.IsRequired(sessionVariables),
+			Inline:   dep.IsInline(),
+		}
+		// Inline dependencies are served by the tangent and never touch the catalog
+		if dep.IsInline() {
+			status.Granted = true
+			statuses = append(statuses, status)
+			continue
+		}
+		if viewDef == nil {
+			status.Error = "session has no view definition"
+			statuses = append(statuses, status)
+			continue
+		}
+		depPath := policy.NamespacedResourcePath(viewDef.Scope, namespace, dep.Path)
+		resolvedPath, err := policy.ResolveResourcePath(viewDef.Scope, depPath)
+		if err != nil {
+			status.Error = err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+		status.ResolvedPath = resolvedPath
+		granted, _, err := policy.AreActionsAllowedOnResource(viewDef, depPath, dep.Actions)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		status.Granted = granted
+		statuses = append(statuses, status)
 	}
+	return statuses
 }
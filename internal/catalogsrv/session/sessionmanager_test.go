@@ -0,0 +1,135 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+)
+
+func TestDependencyStatus(t *testing.T) {
+	viewDef := &policy.ViewDefinition{
+		Scope: policy.Scope{Catalog: "test-catalog", Variant: "dev"},
+		Rules: policy.Rules{
+			{
+				Intent:  policy.IntentAllow,
+				Actions: []policy.Action{policy.ActionResourceRead},
+				Targets: []policy.TargetResource{"res://resources/kubeconfig"},
+			},
+		},
+	}
+	deps := []catalogmanager.Dependency{
+		{Path: "/resources/kubeconfig", Kind: catalogmanager.KindResource, Alias: "kubeconfig", Actions: []policy.Action{policy.ActionResourceRead}},
+		{Path: "/resources/prod-db", Kind: catalogmanager.KindResource, Alias: "prod-db", Actions: []policy.Action{policy.ActionResourceRead}},
+	}
+
+	statuses := dependencyStatus(deps, viewDef, "", nil)
+	require.Len(t, statuses, 2)
+
+	granted := statuses[0]
+	assert.Equal(t, "kubeconfig", granted.Alias)
+	assert.True(t, granted.Granted)
+	assert.True(t, granted.Required)
+	assert.Contains(t, granted.ResolvedPath, "resources/kubeconfig")
+	assert.Empty(t, granted.Error)
+
+	ungranted := statuses[1]
+	assert.Equal(t, "prod-db", ungranted.Alias)
+	assert.False(t, ungranted.Granted)
+	assert.Contains(t, ungranted.ResolvedPath, "resources/prod-db")
+	assert.Equal(t, []policy.Action{policy.ActionResourceRead}, ungranted.Actions)
+
+	t.Run("condition not matching session variables", func(t *testing.T) {
+		conditional := deps[1]
+		conditional.When = &catalogmanager.DependencyCondition{Variable: "mode", Equals: "prod"}
+		statuses := dependencyStatus([]catalogmanager.Dependency{conditional}, viewDef, "", map[string]any{"mode": "dev"})
+		require.Len(t, statuses, 1)
+		assert.False(t, statuses[0].Required)
+	})
+
+	t.Run("inline dependency needs no grant", func(t *testing.T) {
+		inline := catalogmanager.Dependency{Kind: catalogmanager.KindResource, Alias: "kubeconfig", Actions: []policy.Action{policy.ActionResourceRead},
+			Inline: &catalogmanager.InlineResource{}}
+		statuses := dependencyStatus([]catalogmanager.Dependency{inline}, nil, "", nil)
+		require.Len(t, statuses, 1)
+		assert.True(t, statuses[0].Inline)
+		assert.True(t, statuses[0].Granted)
+		assert.Empty(t, statuses[0].Error)
+	})
+
+	t.Run("missing view definition", func(t *testing.T) {
+		statuses := dependencyStatus(deps[:1], nil, "", nil)
+		require.Len(t, statuses, 1)
+		assert.False(t, statuses[0].Granted)
+		assert.NotEmpty(t, statuses[0].Error)
+	})
+
+	t.Run("namespace override", func(t *testing.T) {
+		nsViewDef := &policy.ViewDefinition{
+			Scope: policy.Scope{Catalog: "test-catalog", Variant: "dev", Namespaces: []string{"team-a", "team-b"}},
+			Rules: policy.Rules{
+				{
+					Intent:  policy.IntentAllow,
+					Actions: []policy.Action{policy.ActionResourceRead},
+					Targets: []policy.TargetResource{"res://namespaces/team-a/resources/kubeconfig"},
+				},
+			},
+		}
+		statuses := dependencyStatus(deps[:1], nsViewDef, "team-a", nil)
+		require.Len(t, statuses, 1)
+		assert.Equal(t, "res://catalogs/test-catalog/variants/dev/namespaces/team-a/resources/kubeconfig", statuses[0].ResolvedPath)
+		assert.True(t, statuses[0].Granted)
+
+		// the same dependency in another permitted namespace is not covered by the rule
+		statuses = dependencyStatus(deps[:1], nsViewDef, "team-b", nil)
+		assert.Equal(t, "res://catalogs/test-catalog/variants/dev/namespaces/team-b/resources/kubeconfig", statuses[0].ResolvedPath)
+		assert.False(t, statuses[0].Granted)
+
+		// without the override the dependency is in the default namespace, which the view excludes
+		statuses = dependencyStatus(deps[:1], nsViewDef, "", nil)
+		assert.Equal(t, "res://catalogs/test-catalog/variants/dev/resources/kubeconfig", statuses[0].ResolvedPath)
+		assert.False(t, statuses[0].Granted)
+	})
+}
+
+// stubViewManager serves a fixed view definition.
+type stubViewManager struct {
+	policy.ViewManager
+	viewDef *policy.ViewDefinition
+}
+
+func (v stubViewManager) Name() string                              { return "test-view" }
+func (v stubViewManager) Scope() policy.Scope                       { return v.viewDef.Scope }
+func (v stubViewManager) GetViewDefinition() *policy.ViewDefinition { return v.viewDef }
+
+func TestResolveSessionScope(t *testing.T) {
+	ctx := context.Background()
+	restricted := stubViewManager{viewDef: &policy.ViewDefinition{
+		Scope: policy.Scope{Catalog: "test-catalog", Variant: "dev", Namespaces: []string{catcommon.DefaultNamespace, "team-a"}},
+	}}
+	bound := stubViewManager{viewDef: &policy.ViewDefinition{
+		Scope: policy.Scope{Catalog: "test-catalog", Variant: "dev", Namespace: "team-a"},
+	}}
+
+	scope, err := resolveSessionScope(ctx, restricted, "")
+	require.NoError(t, err)
+	assert.Empty(t, scope.Namespace)
+
+	scope, err = resolveSessionScope(ctx, restricted, catcommon.DefaultNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, catcommon.DefaultNamespace, scope.Namespace)
+
+	_, err = resolveSessionScope(ctx, restricted, "team-b")
+	assert.ErrorIs(t, err, ErrDisallowedByPolicy)
+
+	scope, err = resolveSessionScope(ctx, bound, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", scope.Namespace)
+
+	_, err = resolveSessionScope(ctx, bound, "team-b")
+	assert.ErrorIs(t, err, ErrDisallowedByPolicy)
+}
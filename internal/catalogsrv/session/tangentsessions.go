@@ -0,0 +1,187 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/internal/catalogsrv/tangent"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// DrainTangentRsp is the response to a tangent drain request.
+type DrainTangentRsp struct {
+	TangentID uuid.UUID            `json:"tangentID"`
+	Sessions  []SessionSummaryInfo `json:"sessions"` // the drained sessions, with their final status
+}
+
+// ListTangentSessions returns the active sessions of the catalog in the context that are bound
+// to the given tangent.
+func ListTangentSessions(ctx context.Context, tangentID uuid.UUID) ([]SessionSummaryInfo, apperrors.Error) {
+	sessions, err := activeTangentSessions(ctx, catcommon.GetCatalogID(ctx), tangentID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]SessionSummaryInfo, len(sessions))
+	for i, session := range sessions {
+		summaries[i] = *(&sessionManager{session: session}).GetStatusSummaryInfo(ctx)
+	}
+	return summaries, nil
+}
+
+// DrainTangent marks the given tangent as draining, so that no new sessions are assigned to it,
+// and terminates the active sessions bound to it in every catalog of the tenant, so that the
+// tangent can be decommissioned. Sessions hold their runtime state on the tangent they were
+// assigned to, so they are ended rather than moved to another tangent. The access tokens of the
+// terminated sessions are expired, and the tangent stops the sessions when their next heartbeat
+// is rejected. Returns the drained sessions with their final status.
+func DrainTangent(ctx context.Context, tangentID uuid.UUID) ([]SessionSummaryInfo, apperrors.Error) {
+	sessions, err := activeTangentSessions(ctx, uuid.Nil, tangentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DB(ctx).UpdateTangentStatus(ctx, tangentID, tangent.StatusDraining); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", tangentID.String()).Msg("failed to mark tangent as draining")
+		return nil, err
+	}
+	summaries := make([]SessionSummaryInfo, 0, len(sessions))
+	for _, session := range sessions {
+		manager := &sessionManager{session: session}
+		if err := manager.SetStatusSummary(ctx, SessionStatusTerminated); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("session_id", session.SessionID.String()).Msg("failed to terminate session")
+			return nil, err
+		}
+		if err := expireSessionTokens(ctx, session.SessionID); err != nil {
+			return nil, err
+		}
+		log.Ctx(ctx).Info().Str("session_id", session.SessionID.String()).Str("tangent_id", tangentID.String()).Msg("terminated session on tangent drain")
+		summaries = append(summaries, *manager.GetStatusSummaryInfo(ctx))
+	}
+	return summaries, nil
+}
+
+// UndrainTangent returns a draining tangent to service, so that new sessions can be assigned to
+// it again. A tangent stays draining until it is undrained, even if it registers again.
+func UndrainTangent(ctx context.Context, tangentID uuid.UUID) apperrors.Error {
+	if err := db.DB(ctx).UpdateTangentStatus(ctx, tangentID, tangent.StatusActive); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrTangentNotFound.Msg("tangent not found: " + tangentID.String())
+		}
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", tangentID.String()).Msg("failed to return tangent to service")
+		return err
+	}
+	return nil
+}
+
+// expireSessionTokens expires the access tokens issued to the session, so that they are rejected
+// from now on.
+func expireSessionTokens(ctx context.Context, sessionID uuid.UUID) apperrors.Error {
+	tokens, err := db.DB(ctx).ListSessionViewTokens(ctx, sessionID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("session_id", sessionID.String()).Msg("failed to list session tokens")
+		return err
+	}
+	now := time.Now()
+	for _, token := range tokens {
+		if err := db.DB(ctx).UpdateViewTokenExpiry(ctx, token.TokenID, now); err != nil && !errors.Is(err, dberror.ErrNotFound) {
+			log.Ctx(ctx).Error().Err(err).Str("session_id", sessionID.String()).Str("token_id", token.TokenID.String()).Msg("failed to expire session token")
+			return err
+		}
+	}
+	return nil
+}
+
+// activeTangentSessions returns the active sessions of the catalog that are bound to the given
+// tangent, or those of every catalog of the tenant if catalogID is nil.
+func activeTangentSessions(ctx context.Context, catalogID uuid.UUID, tangentID uuid.UUID) ([]*models.Session, apperrors.Error) {
+	if _, err := db.DB(ctx).GetTangent(ctx, tangentID); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrTangentNotFound.Msg("tangent not found: " + tangentID.String())
+		}
+		return nil, err
+	}
+	sessions, err := db.DB(ctx).ListSessionsByTangent(ctx, catalogID, tangentID, activeSessionStatuses)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", tangentID.String()).Msg("failed to list sessions of tangent")
+		return nil, ErrUnableToGetSession
+	}
+	return sessions, nil
+}
+
+func getTangentSessions(r *http.Request) (*httpx.Response, error) {
+	tangentID, err := tangentIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, appErr := ListTangentSessions(r.Context(), tangentID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   sessions,
+	}, nil
+}
+
+// drainTangentSessions drains a tangent. Tangents are shared by the catalogs of the tenant, so
+// only a tenant admin may drain one.
+func drainTangentSessions(r *http.Request) (*httpx.Response, error) {
+	tangentID, err := tangentIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.IsTenantAdmin(r.Context()) {
+		return nil, ErrNotAuthorized.Msg("draining a tangent requires a tenant admin")
+	}
+
+	sessions, appErr := DrainTangent(r.Context(), tangentID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &DrainTangentRsp{
+			TangentID: tangentID,
+			Sessions:  sessions,
+		},
+	}, nil
+}
+
+// undrainTangent returns a drained tangent to service. Like draining, it requires a tenant admin.
+func undrainTangent(r *http.Request) (*httpx.Response, error) {
+	tangentID, err := tangentIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.IsTenantAdmin(r.Context()) {
+		return nil, ErrNotAuthorized.Msg("undraining a tangent requires a tenant admin")
+	}
+	if appErr := UndrainTangent(r.Context(), tangentID); appErr != nil {
+		return nil, appErr
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+// tangentIDParam parses the tangent ID in the request path.
+func tangentIDParam(r *http.Request) (uuid.UUID, error) {
+	tangentID, err := uuid.Parse(chi.URLParam(r, "tangentID"))
+	if err != nil {
+		return uuid.Nil, httpx.ErrInvalidRequest("invalid tangentID")
+	}
+	return tangentID, nil
+}
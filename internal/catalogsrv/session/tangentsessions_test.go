@@ -0,0 +1,143 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/catalogsrv/tangent"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+func TestDrainTangent(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{Name: "test-catalog", ProjectID: projectID, Info: pgtype.JSONB{Status: pgtype.Null}}
+	require.NoError(t, db.DB(ctx).CreateCatalog(ctx, &catalog))
+	defer db.DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+	otherCatalog := models.Catalog{Name: "other-catalog", ProjectID: projectID, Info: pgtype.JSONB{Status: pgtype.Null}}
+	require.NoError(t, db.DB(ctx).CreateCatalog(ctx, &otherCatalog))
+	defer db.DB(ctx).DeleteCatalog(ctx, otherCatalog.CatalogID, "")
+	ctx = catcommon.WithCatalogContext(ctx, &catcommon.CatalogContext{
+		CatalogID:   catalog.CatalogID,
+		Catalog:     catalog.Name,
+		UserContext: &catcommon.UserContext{UserID: "users/testuser"},
+	})
+
+	createTangent := func() uuid.UUID {
+		tangent := &models.Tangent{ID: uuid.New(), Info: json.RawMessage(`{}`)}
+		require.NoError(t, db.DB(ctx).CreateTangent(ctx, tangent))
+		t.Cleanup(func() { db.DB(ctx).DeleteTangent(ctx, tangent.ID) })
+		return tangent.ID
+	}
+	draining, other := createTangent(), createTangent()
+
+	createSession := func(catalogID, tangentID uuid.UUID, statusSummary SessionStatus) uuid.UUID {
+		sessionID := uuid.New()
+		require.NoError(t, db.DB(ctx).UpsertSession(ctx, &models.Session{
+			SessionID:     sessionID,
+			SkillSet:      "test-skillset",
+			Skill:         "test-skill",
+			ViewID:        uuid.New(),
+			TangentID:     tangentID,
+			StatusSummary: string(statusSummary),
+			Status:        json.RawMessage(`{}`),
+			Info:          json.RawMessage(`{}`),
+			UserID:        "users/testuser",
+			CatalogID:     catalogID,
+			VariantID:     uuid.New(),
+			ExpiresAt:     time.Now().Add(time.Hour),
+		}))
+		return sessionID
+	}
+	running := createSession(catalog.CatalogID, draining, SessionStatusRunning)
+	created := createSession(catalog.CatalogID, draining, SessionStatusCreated)
+	completed := createSession(catalog.CatalogID, draining, SessionStatusCompleted)
+	otherRunning := createSession(catalog.CatalogID, other, SessionStatusRunning)
+	otherCatalogRunning := createSession(otherCatalog.CatalogID, draining, SessionStatusRunning)
+
+	createToken := func(sessionID uuid.UUID) uuid.UUID {
+		token := &models.ViewToken{ViewID: uuid.New(), SessionID: sessionID, ExpireAt: time.Now().Add(time.Hour)}
+		require.NoError(t, db.DB(ctx).CreateViewToken(ctx, token))
+		t.Cleanup(func() { db.DB(ctx).DeleteViewToken(ctx, token.TokenID) })
+		return token.TokenID
+	}
+	runningToken, otherToken := createToken(running), createToken(otherRunning)
+
+	statusOf := func(sessionID uuid.UUID) SessionStatus {
+		session, err := db.DB(ctx).GetSession(ctx, sessionID)
+		require.NoError(t, err)
+		return SessionStatus(session.StatusSummary)
+	}
+	sessionIDs := func(summaries []SessionSummaryInfo) []uuid.UUID {
+		ids := []uuid.UUID{}
+		for _, summary := range summaries {
+			ids = append(ids, summary.SessionID)
+		}
+		return ids
+	}
+
+	sessions, err := ListTangentSessions(ctx, draining)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{running, created}, sessionIDs(sessions))
+
+	drained, err := DrainTangent(ctx, draining)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{running, created, otherCatalogRunning}, sessionIDs(drained))
+	for _, summary := range drained {
+		assert.Equal(t, SessionStatusTerminated, summary.StatusSummary)
+	}
+
+	assert.Equal(t, SessionStatusTerminated, statusOf(running))
+	assert.Equal(t, SessionStatusTerminated, statusOf(created))
+	assert.Equal(t, SessionStatusCompleted, statusOf(completed))
+	assert.Equal(t, SessionStatusRunning, statusOf(otherRunning))
+	assert.Equal(t, SessionStatusTerminated, statusOf(otherCatalogRunning))
+
+	tokenExpired := func(tokenID uuid.UUID) bool {
+		token, err := db.DB(ctx).GetViewToken(ctx, tokenID)
+		require.NoError(t, err)
+		return !token.ExpireAt.After(time.Now())
+	}
+	assert.True(t, tokenExpired(runningToken))
+	assert.False(t, tokenExpired(otherToken))
+
+	tangentStatus := func(tangentID uuid.UUID) string {
+		tangent, err := db.DB(ctx).GetTangent(ctx, tangentID)
+		require.NoError(t, err)
+		return tangent.Status
+	}
+	assert.Equal(t, tangent.StatusDraining, tangentStatus(draining))
+	assert.NotEqual(t, tangent.StatusDraining, tangentStatus(other))
+
+	sessions, err = ListTangentSessions(ctx, draining)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	sessions, err = ListTangentSessions(ctx, other)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{otherRunning}, sessionIDs(sessions))
+
+	_, err = DrainTangent(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrTangentNotFound)
+
+	require.NoError(t, UndrainTangent(ctx, draining))
+	assert.Equal(t, tangent.StatusActive, tangentStatus(draining))
+	assert.ErrorIs(t, UndrainTangent(ctx, uuid.New()), ErrTangentNotFound)
+}
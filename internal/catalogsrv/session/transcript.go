@@ -0,0 +1,181 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/snappy"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/config"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// Streams recorded in an interactive session transcript.
+const (
+	TranscriptStreamStdout = "stdout"
+	TranscriptStreamStderr = "stderr"
+)
+
+// TranscriptChunk is a single piece of output written by a skill during an interactive session.
+// Seq numbers chunks in the order they were written, starting at 1.
+type TranscriptChunk struct {
+	Seq    int64     `json:"seq"`
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"`
+	Skill  string    `json:"skill,omitempty"`
+	Data   string    `json:"data"`
+}
+
+// Transcript is the ordered output of an interactive session. Truncated is set when the
+// tangent stopped recording because the transcript reached its size limit. When a transcript
+// is read a page at a time, NextCursor continues after the last chunk returned.
+type Transcript struct {
+	SessionID  uuid.UUID         `json:"sessionID"`
+	Truncated  bool              `json:"truncated,omitempty"`
+	Chunks     []TranscriptChunk `json:"chunks"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+	HasMore    bool              `json:"hasMore,omitempty"`
+}
+
+// transcriptFilePath returns the path of the stored transcript for a session.
+func transcriptFilePath(sessionID uuid.UUID) string {
+	return filepath.Join(config.Config().AuditLog.GetPath(), sessionID.String()+".transcript.sz")
+}
+
+// WriteTranscript stores the transcript of a session, replacing any previously stored transcript.
+// The transcript is kept next to the session's audit log as Snappy compressed JSON.
+func WriteTranscript(sessionID uuid.UUID, transcript *Transcript) error {
+	transcript.SessionID = sessionID
+	if transcript.Chunks == nil {
+		transcript.Chunks = []TranscriptChunk{}
+	}
+	for _, chunk := range transcript.Chunks {
+		if chunk.Stream != TranscriptStreamStdout && chunk.Stream != TranscriptStreamStderr {
+			return errors.New("invalid transcript stream: " + chunk.Stream)
+		}
+	}
+	numberTranscriptChunks(transcript.Chunks)
+	transcript.NextCursor = ""
+	transcript.HasMore = false
+
+	data, err := json.Marshal(transcript)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.Config().AuditLog.GetPath(), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(transcriptFilePath(sessionID), snappy.Encode(nil, data), 0600)
+}
+
+// numberTranscriptChunks numbers chunks by position unless they already carry increasing
+// sequence numbers, as transcripts from older tangents do not.
+func numberTranscriptChunks(chunks []TranscriptChunk) {
+	var prev int64
+	for _, chunk := range chunks {
+		if chunk.Seq <= prev {
+			for i := range chunks {
+				chunks[i].Seq = int64(i + 1)
+			}
+			return
+		}
+		prev = chunk.Seq
+	}
+}
+
+// GetTranscript returns the stored transcript of a session. Returns os.ErrNotExist if no
+// transcript was stored for the session.
+func GetTranscript(sessionID uuid.UUID) (*Transcript, error) {
+	compressed, err := os.ReadFile(transcriptFilePath(sessionID))
+	if err != nil {
+		return nil, err
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, err
+	}
+	var transcript Transcript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+// putTranscript stores the transcript uploaded by the tangent for the session in the token and
+// returns its location.
+func putTranscript(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+	if r.Body == nil {
+		return nil, ErrInvalidRequest.Msg("request body is required")
+	}
+	var transcript Transcript
+	if err := json.NewDecoder(r.Body).Decode(&transcript); err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid request body")
+	}
+	if err := WriteTranscript(sessionID, &transcript); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to write transcript")
+		return nil, ErrInvalidRequest.Msg(err.Error())
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/sessions/" + sessionID.String() + "/transcript",
+		Response:   nil,
+	}, nil
+}
+
+// getTranscriptByID returns a page of the stored transcript of a session. The cursor query
+// parameter continues from a previous page and limit sets the number of chunks returned.
+// The transcript of a session the caller cannot access is reported as not found.
+func getTranscriptByID(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionUUID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+	after, limit, err := parsePageParams(r)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+
+	session, apperr := db.DB(ctx).GetSession(ctx, sessionUUID)
+	if apperr != nil {
+		if errors.Is(apperr, dberror.ErrNotFound) {
+			return nil, ErrTranscriptNotFound
+		}
+		log.Ctx(ctx).Error().Err(apperr).Msg("failed to get session")
+		return nil, ErrUnableToGetSession
+	}
+	if !canAccessSession(ctx, session) {
+		return nil, ErrTranscriptNotFound
+	}
+
+	transcript, err := GetTranscript(sessionUUID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrTranscriptNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to read transcript")
+		return nil, ErrUnableToGetSession.Msg("unable to read transcript")
+	}
+	numberTranscriptChunks(transcript.Chunks)
+	transcript.Chunks, transcript.NextCursor, transcript.HasMore = paginate(transcript.Chunks,
+		func(c TranscriptChunk) int64 { return c.Seq }, after, limit)
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   transcript,
+	}, nil
+}
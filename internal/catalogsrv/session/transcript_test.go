@@ -0,0 +1,166 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/db"
+	"github.com/tansive/tansive/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+func TestSessionTranscript(t *testing.T) {
+	ctx := newDb()
+	defer db.DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, db.DB(ctx).CreateTenant(ctx, tenantID))
+	defer db.DB(ctx).DeleteTenant(ctx, tenantID)
+	require.NoError(t, db.DB(ctx).CreateProject(ctx, projectID))
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{Name: "test-catalog", ProjectID: projectID, Info: pgtype.JSONB{Status: pgtype.Null}}
+	require.NoError(t, db.DB(ctx).CreateCatalog(ctx, &catalog))
+	defer db.DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+	ctx = catcommon.WithCatalogID(ctx, catalog.CatalogID)
+
+	createSession := func(catalogID uuid.UUID) uuid.UUID {
+		sessionID := uuid.New()
+		require.NoError(t, db.DB(ctx).UpsertSession(ctx, &models.Session{
+			SessionID:     sessionID,
+			SkillSet:      "test-skillset",
+			Skill:         "test-skill",
+			ViewID:        uuid.New(),
+			TangentID:     uuid.New(),
+			StatusSummary: string(SessionStatusCompleted),
+			Status:        []byte(`{}`),
+			UserID:        "users/testuser",
+			CatalogID:     catalogID,
+			VariantID:     uuid.New(),
+			StartedAt:     time.Now(),
+			ExpiresAt:     time.Now().Add(time.Hour),
+		}))
+		return sessionID
+	}
+
+	sessionID := createSession(catalog.CatalogID)
+	defer os.Remove(transcriptFilePath(sessionID))
+
+	start := time.UnixMilli(1718000000000).UTC()
+	chunks := []TranscriptChunk{
+		{Seq: 1, Time: start, Stream: TranscriptStreamStdout, Skill: "list_pods", Data: "NAME READY STATUS"},
+		{Seq: 2, Time: start.Add(10 * time.Millisecond), Stream: TranscriptStreamStderr, Skill: "list_pods", Data: "warning: namespace not set"},
+		{Seq: 3, Time: start.Add(20 * time.Millisecond), Stream: TranscriptStreamStdout, Skill: "list_pods", Data: "api-server 1/1 Running"},
+	}
+
+	getRequest := func(id uuid.UUID) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+id.String()+"/transcript", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("sessionID", id.String())
+		return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("missing transcript is not found", func(t *testing.T) {
+		_, err := getTranscriptByID(getRequest(sessionID))
+		assert.ErrorIs(t, err, ErrTranscriptNotFound)
+	})
+
+	t.Run("uploaded transcript is stored and retrievable", func(t *testing.T) {
+		body, err := json.Marshal(Transcript{Chunks: chunks})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/sessions/transcript", bytes.NewReader(body))
+		req = req.WithContext(catcommon.WithSessionID(req.Context(), sessionID))
+		rsp, err := putTranscript(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+		assert.Equal(t, "/sessions/"+sessionID.String()+"/transcript", rsp.Location)
+
+		rsp, err = getTranscriptByID(getRequest(sessionID))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+		transcript := rsp.Response.(*Transcript)
+		assert.Equal(t, sessionID, transcript.SessionID)
+		assert.False(t, transcript.Truncated)
+		assert.Equal(t, chunks, transcript.Chunks)
+	})
+
+	t.Run("transcript of an inaccessible session is not found", func(t *testing.T) {
+		_, err := getTranscriptByID(getRequest(uuid.New()))
+		assert.ErrorIs(t, err, ErrTranscriptNotFound)
+
+		otherID := createSession(uuid.New())
+		defer os.Remove(transcriptFilePath(otherID))
+		require.NoError(t, WriteTranscript(otherID, &Transcript{Chunks: chunks}))
+		_, err = getTranscriptByID(getRequest(otherID))
+		assert.ErrorIs(t, err, ErrTranscriptNotFound)
+	})
+
+	t.Run("transcript is paged with cursors across appends", func(t *testing.T) {
+		pagedID := createSession(catalog.CatalogID)
+		defer os.Remove(transcriptFilePath(pagedID))
+		require.NoError(t, WriteTranscript(pagedID, &Transcript{Chunks: chunks[:2]}))
+
+		page := func(cursor string) *Transcript {
+			req := getRequest(pagedID)
+			req.URL.RawQuery = "limit=1&cursor=" + cursor
+			rsp, err := getTranscriptByID(req)
+			require.NoError(t, err)
+			return rsp.Response.(*Transcript)
+		}
+
+		first := page("")
+		require.Len(t, first.Chunks, 1)
+		assert.Equal(t, chunks[0].Data, first.Chunks[0].Data)
+		assert.True(t, first.HasMore)
+
+		// the tangent uploads the transcript again after more output was written
+		require.NoError(t, WriteTranscript(pagedID, &Transcript{Chunks: chunks}))
+
+		second := page(first.NextCursor)
+		require.Len(t, second.Chunks, 1)
+		assert.Equal(t, chunks[1].Data, second.Chunks[0].Data)
+		third := page(second.NextCursor)
+		require.Len(t, third.Chunks, 1)
+		assert.Equal(t, chunks[2].Data, third.Chunks[0].Data)
+		assert.False(t, third.HasMore)
+		assert.Empty(t, page(third.NextCursor).Chunks)
+	})
+
+	t.Run("chunks without sequence numbers are numbered by position", func(t *testing.T) {
+		legacyID := uuid.New()
+		defer os.Remove(transcriptFilePath(legacyID))
+		legacy := []TranscriptChunk{{Stream: TranscriptStreamStdout, Data: "a"}, {Stream: TranscriptStreamStdout, Data: "b"}}
+		require.NoError(t, WriteTranscript(legacyID, &Transcript{Chunks: legacy}))
+		stored, err := GetTranscript(legacyID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stored.Chunks[0].Seq)
+		assert.Equal(t, int64(2), stored.Chunks[1].Seq)
+	})
+
+	t.Run("invalid cursor is rejected", func(t *testing.T) {
+		req := getRequest(sessionID)
+		req.URL.RawQuery = "cursor=not-a-cursor"
+		_, err := getTranscriptByID(req)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown stream is rejected", func(t *testing.T) {
+		err := WriteTranscript(uuid.New(), &Transcript{Chunks: []TranscriptChunk{{Stream: "stdin", Data: "x"}}})
+		assert.Error(t, err)
+	})
+}
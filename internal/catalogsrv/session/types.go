@@ -21,6 +21,7 @@ const (
 	SessionStatusResumed    SessionStatus = "resumed"
 	SessionStatusSuspended  SessionStatus = "suspended"
 	SessionStatusTerminated SessionStatus = "terminated"
+	SessionStatusStale      SessionStatus = "stale" // the tangent stopped sending heartbeats for the session
 )
 
 var validSessionStatus = map[SessionStatus]struct{}{
@@ -34,6 +35,16 @@ var validSessionStatus = map[SessionStatus]struct{}{
 	SessionStatusResumed:    {},
 	SessionStatusSuspended:  {},
 	SessionStatusTerminated: {},
+	SessionStatusStale:      {},
+}
+
+// activeSessionStatuses are the status summaries of sessions that have not ended
+var activeSessionStatuses = []string{
+	string(SessionStatusCreated),
+	string(SessionStatusRunning),
+	string(SessionStatusPaused),
+	string(SessionStatusResumed),
+	string(SessionStatusSuspended),
 }
 
 func IsValidSessionStatus(status SessionStatus) bool {
@@ -46,6 +57,11 @@ type InteractiveSessionRsp struct {
 	TangentURL string `json:"tangentURL"`
 }
 
+// SessionReplayReq is the optional body of a session replay request
+type SessionReplayReq struct {
+	SessionVariables map[string]any `json:"sessionVariables,omitempty"` // overrides session variables of the original session
+}
+
 type SessionTokenRsp struct {
 	Token  string    `json:"token"`
 	Expiry time.Time `json:"expiry"`
@@ -59,15 +75,21 @@ type ExecutionState struct {
 	ViewDefinition   *policy.ViewDefinition `json:"viewDefinition"`
 	SessionVariables map[string]any         `json:"sessionVariables"`
 	InputArgs        map[string]any         `json:"inputArgs"`
+	InputArgsRef     string                 `json:"inputArgsRef,omitempty"`
 	Catalog          string                 `json:"catalog"`
 	Variant          string                 `json:"variant"`
 	Namespace        string                 `json:"namespace"`
 	TenantID         catcommon.TenantId     `json:"tenantID"`
+	Environment      string                 `json:"environment,omitempty"`
+	ContextOverrides map[string]any         `json:"contextOverrides,omitempty"`
+	AdoptionDepth    int                    `json:"adoptionDepth,omitempty"`
+	LogLevel         string                 `json:"logLevel,omitempty"`
 }
 
 type ExecutionStatus struct {
 	AuditLog                string         `json:"auditLog"`
 	AuditLogVerificationKey []byte         `json:"auditLogVerificationKey"`
+	AuditLogPrunedAt        *time.Time     `json:"auditLogPrunedAt,omitempty"`
 	Error                   map[string]any `json:"error"`
 }
 
@@ -77,13 +99,32 @@ type ExecutionStatusUpdate struct {
 }
 
 type SessionSummaryInfo struct {
-	SessionID     uuid.UUID      `json:"sessionID"`
-	UserID        string         `json:"userID"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	StartedAt     time.Time      `json:"startedAt"`
-	UpdatedAt     time.Time      `json:"updatedAt"`
-	StatusSummary SessionStatus  `json:"statusSummary"`
-	Error         map[string]any `json:"error"`
+	SessionID     uuid.UUID         `json:"sessionID"`
+	UserID        string            `json:"userID"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	StartedAt     time.Time         `json:"startedAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	StatusSummary SessionStatus     `json:"statusSummary"`
+	Error         map[string]any    `json:"error"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+// SessionSummaryBatchReq is the request body of a batch session summary query
+type SessionSummaryBatchReq struct {
+	SessionIDs []string `json:"sessionIDs"`
+}
+
+// SessionSummaryBatchItem is the result for one requested session ID. NotFound is set when the
+// session does not exist or is not accessible to the caller.
+type SessionSummaryBatchItem struct {
+	SessionID string              `json:"sessionID"`
+	NotFound  bool                `json:"notFound,omitempty"`
+	Summary   *SessionSummaryInfo `json:"summary,omitempty"`
+}
+
+// SessionSummaryBatchRsp holds the results of a batch session summary query in request order
+type SessionSummaryBatchRsp struct {
+	Sessions []SessionSummaryBatchItem `json:"sessions"`
 }
 
 type AuditLogVerificationKey struct {
@@ -93,3 +134,23 @@ type AuditLogVerificationKey struct {
 type SessionList struct {
 	SessionSummaryInfo []SessionSummaryInfo `json:"sessionSummaryInfo"`
 }
+
+// SessionDependencyStatus reports a dependency declared by the session's skillset and whether
+// the session's view grants the actions it requires.
+type SessionDependencyStatus struct {
+	Path         string          `json:"path"`
+	ResolvedPath string          `json:"resolvedPath"`
+	Kind         string          `json:"kind"`
+	Alias        string          `json:"alias"`
+	Actions      []policy.Action `json:"actions"`
+	Required     bool            `json:"required"`         // false if the dependency's condition does not match the session variables
+	Inline       bool            `json:"inline,omitempty"` // true if the skillset supplies the resource inline
+	Granted      bool            `json:"granted"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// SessionDependenciesRsp is the response of the session dependencies endpoint.
+type SessionDependenciesRsp struct {
+	SessionID    uuid.UUID                 `json:"sessionID"`
+	Dependencies []SessionDependencyStatus `json:"dependencies"`
+}
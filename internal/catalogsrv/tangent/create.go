@@ -13,6 +13,7 @@ import (
 	"github.com/tansive/tansive/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tansive/tansive/internal/common/uuid"
 )
 
@@ -41,6 +42,10 @@ func createTangent(r *http.Request) (*httpx.Response, error) {
 		return nil, httpx.ErrInvalidRequest("id is required")
 	}
 
+	if _, err := tangentsig.PublicKeyAlgorithm(req.PublicKeyAccessKey); err != nil {
+		return nil, httpx.ErrInvalidRequest("unsupported public key: " + err.Error())
+	}
+
 	info, err := json.Marshal(req)
 	if err != nil {
 		return nil, httpx.ErrInvalidRequest("invalid request body")
@@ -56,6 +61,10 @@ func createTangent(r *http.Request) (*httpx.Response, error) {
 	if err := db.DB(ctx).CreateTangent(ctx, &t); err != nil {
 		if errors.Is(err, dberror.ErrAlreadyExists) {
 			log.Ctx(ctx).Error().Err(err).Msg("tangent already exists, updating")
+			// a draining tangent that registers again stays draining
+			if existing, err := db.DB(ctx).GetTangent(ctx, t.ID); err == nil {
+				t.Status = existing.Status
+			}
 			if err := db.DB(ctx).UpdateTangent(ctx, &t); err != nil {
 				log.Ctx(ctx).Error().Err(err).Msg("failed to update tangent")
 				return nil, err
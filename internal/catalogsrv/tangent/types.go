@@ -3,7 +3,13 @@ package tangent
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/config"
 	"github.com/tansive/tansive/internal/catalogsrv/db"
@@ -16,17 +22,65 @@ type TangentInfo struct {
 	CreatedBy              string               `json:"createdBy"`
 	URL                    string               `json:"url"`
 	Capabilities           []catcommon.RunnerID `json:"capabilities"`
+	MaxSessions            int                  `json:"maxSessions,omitempty"` // maximum concurrent sessions, unlimited if zero
 	PublicKeyAccessKey     []byte               `json:"publicKeyAccessKey"`
 	PublicKeyLogSigningKey []byte               `json:"publicKeyLogSigningKey"`
 	OnboardingKey          string               `json:"onboardingKey"`
+	Status                 string               `json:"-"` // the registration status, kept in the tangent record
+	LastHeartbeatAt        time.Time            `json:"-"` // when the tangent last registered or sent a heartbeat
 }
 
+// StatusActive is the status of a tangent that new sessions can be assigned to.
+const StatusActive = ""
+
+// StatusDraining is the status of a tangent whose sessions are being drained so that it can be
+// decommissioned. No new sessions are assigned to a draining tangent.
+const StatusDraining = "draining"
+
 type Tangent struct {
 	ID uuid.UUID `json:"id"`
 	TangentInfo
 }
 
-func GetTangentWithCapabilities(ctx context.Context, capabilities []catcommon.RunnerID) (*Tangent, apperrors.Error) {
+var (
+	// ErrPinnedTangentNotFound is returned when the tangent a session is pinned to is not registered.
+	ErrPinnedTangentNotFound apperrors.Error = apperrors.New("pinned tangent not found").SetStatusCode(http.StatusBadRequest)
+	// ErrPinnedTangentUnavailable is returned when the tangent a session is pinned to cannot run it.
+	ErrPinnedTangentUnavailable apperrors.Error = apperrors.New("pinned tangent cannot run the session").SetStatusCode(http.StatusConflict)
+)
+
+// TangentPin identifies the tangent a session must run on, by ID or by URL.
+type TangentPin struct {
+	ID  uuid.UUID
+	URL string
+}
+
+// IsSet reports whether the pin identifies a tangent.
+func (p TangentPin) IsSet() bool {
+	return p.ID != uuid.Nil || p.URL != ""
+}
+
+// matches reports whether the pin identifies the tangent.
+func (p TangentPin) matches(info TangentInfo) bool {
+	if p.ID != uuid.Nil {
+		return info.ID == p.ID
+	}
+	return strings.EqualFold(strings.TrimSuffix(info.URL, "/"), strings.TrimSuffix(p.URL, "/"))
+}
+
+func (p TangentPin) String() string {
+	if p.ID != uuid.Nil {
+		return p.ID.String()
+	}
+	return p.URL
+}
+
+// GetTangentWithCapabilities returns a tangent to run a session that needs the given runners.
+// activeSessions holds the number of active sessions of each tangent. Draining tangents are never
+// chosen. Of the others, tangents that support every required runner and are below their session
+// limit are preferred; if none are, the session is assigned to a tangent that lacks a runner or is
+// full, and the tangent reports the missing runners or rejects the session when it starts.
+func GetTangentWithCapabilities(ctx context.Context, capabilities []catcommon.RunnerID, activeSessions map[uuid.UUID]int) (*Tangent, apperrors.Error) {
 	if config.IsTest() {
 		return &Tangent{
 			ID: uuid.New(),
@@ -38,16 +92,24 @@ func GetTangentWithCapabilities(ctx context.Context, capabilities []catcommon.Ru
 		}, nil
 	}
 
-	// Get all tangents
-	tangents, err := db.DB(ctx).ListTangents(ctx)
+	infos, err := listTangentInfos(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(infos) == 0 {
+		return nil, apperrors.New("no tangents registered")
+	}
+	infos = schedulableTangents(infos)
+	if len(infos) == 0 {
+		return nil, apperrors.New("all registered tangents are draining")
+	}
 
-	info := TangentInfo{}
-	goerr := json.Unmarshal(tangents[0].Info, &info)
-	if goerr != nil {
-		return nil, apperrors.New("failed to unmarshal tangent info: " + goerr.Error())
+	info := selectTangent(infos, capabilities, activeSessions)
+	if !hasCapacity(info, activeSessions) {
+		log.Ctx(ctx).Warn().Str("tangent_id", info.ID.String()).Int("max_sessions", info.MaxSessions).Msg("all tangents are at their session limit")
+	}
+	if missing := missingCapabilities(info.Capabilities, capabilities); len(missing) > 0 {
+		log.Ctx(ctx).Warn().Str("tangent_id", info.ID.String()).Any("missing_runners", missing).Msg("no tangent supports all required runners")
 	}
 
 	return &Tangent{
@@ -60,6 +122,129 @@ func GetTangentWithCapabilities(ctx context.Context, capabilities []catcommon.Ru
 	}, nil
 }
 
+// GetPinnedTangent returns the tangent identified by pin to run a session that needs the given
+// runners. Unlike GetTangentWithCapabilities, no other tangent is chosen in its place: the
+// session is rejected if the tangent is not registered, is draining, has not sent a heartbeat
+// within the session heartbeat timeout, lacks a required runner or is at its session limit.
+// activeSessions holds the number of active sessions of each tangent.
+func GetPinnedTangent(ctx context.Context, pin TangentPin, capabilities []catcommon.RunnerID, activeSessions map[uuid.UUID]int) (*Tangent, apperrors.Error) {
+	infos, err := listTangentInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var healthyAfter time.Time
+	if timeout := config.Config().Session.GetHeartbeatTimeout(); timeout > 0 {
+		healthyAfter = time.Now().Add(-timeout)
+	}
+	info, err := selectPinnedTangent(infos, pin, capabilities, activeSessions, healthyAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tangent{
+		ID: info.ID,
+		TangentInfo: TangentInfo{
+			CreatedBy:    "system",
+			URL:          info.URL,
+			Capabilities: capabilities,
+		},
+	}, nil
+}
+
+// selectPinnedTangent returns the tangent identified by pin if it is not draining, has sent a
+// heartbeat after healthyAfter, supports all required runners and has capacity for another
+// session. The heartbeat is not checked if healthyAfter is zero.
+func selectPinnedTangent(infos []TangentInfo, pin TangentPin, required []catcommon.RunnerID, activeSessions map[uuid.UUID]int, healthyAfter time.Time) (TangentInfo, apperrors.Error) {
+	idx := slices.IndexFunc(infos, pin.matches)
+	if idx < 0 {
+		return TangentInfo{}, ErrPinnedTangentNotFound.Msg("tangent " + pin.String() + " is not registered")
+	}
+	info := infos[idx]
+	if isDraining(info) {
+		return TangentInfo{}, ErrPinnedTangentUnavailable.Msg("tangent " + pin.String() + " is draining")
+	}
+	if !healthyAfter.IsZero() && info.LastHeartbeatAt.Before(healthyAfter) {
+		return TangentInfo{}, ErrPinnedTangentUnavailable.Msg(fmt.Sprintf("tangent %s has not sent a heartbeat since %s", pin, info.LastHeartbeatAt.UTC().Format(time.RFC3339)))
+	}
+	if missing := missingCapabilities(info.Capabilities, required); len(missing) > 0 {
+		return TangentInfo{}, ErrPinnedTangentUnavailable.Msg(fmt.Sprintf("tangent %s does not support runners %v", pin, missing))
+	}
+	if !hasCapacity(info, activeSessions) {
+		return TangentInfo{}, ErrPinnedTangentUnavailable.Msg(fmt.Sprintf("tangent %s is running its maximum of %d sessions", pin, info.MaxSessions))
+	}
+	return info, nil
+}
+
+// listTangentInfos returns the info of all registered tangents.
+func listTangentInfos(ctx context.Context) ([]TangentInfo, apperrors.Error) {
+	tangents, err := db.DB(ctx).ListTangents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]TangentInfo, 0, len(tangents))
+	for _, t := range tangents {
+		info := TangentInfo{}
+		goerr := json.Unmarshal(t.Info, &info)
+		if goerr != nil {
+			return nil, apperrors.New("failed to unmarshal tangent info: " + goerr.Error())
+		}
+		info.Status = t.Status
+		info.LastHeartbeatAt = t.LastHeartbeatAt
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// schedulableTangents returns the tangents new sessions can be assigned to, leaving out those
+// being drained.
+func schedulableTangents(infos []TangentInfo) []TangentInfo {
+	return slices.DeleteFunc(slices.Clone(infos), isDraining)
+}
+
+// isDraining reports whether the tangent is being drained.
+func isDraining(info TangentInfo) bool {
+	return info.Status == StatusDraining
+}
+
+// selectTangent returns the first tangent that supports all required runners and has capacity
+// for another session. Failing that, a tangent that supports all runners is preferred, as a full
+// tangent frees up while a missing runner does not, and then a tangent with capacity. infos must
+// not be empty.
+func selectTangent(infos []TangentInfo, required []catcommon.RunnerID, activeSessions map[uuid.UUID]int) TangentInfo {
+	selected, selectedRank := infos[0], -1
+	for _, info := range infos {
+		rank := 0
+		if len(missingCapabilities(info.Capabilities, required)) == 0 {
+			rank += 2
+		}
+		if hasCapacity(info, activeSessions) {
+			rank++
+		}
+		if rank > selectedRank {
+			selected, selectedRank = info, rank
+		}
+	}
+	return selected
+}
+
+// hasCapacity reports whether the tangent is below its session limit.
+func hasCapacity(info TangentInfo, activeSessions map[uuid.UUID]int) bool {
+	return info.MaxSessions == 0 || activeSessions[info.ID] < info.MaxSessions
+}
+
+// missingCapabilities returns the required runners absent from supported.
+func missingCapabilities(supported, required []catcommon.RunnerID) []catcommon.RunnerID {
+	var missing []catcommon.RunnerID
+	for _, r := range required {
+		if !slices.Contains(supported, r) && !slices.Contains(missing, r) {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
 func GetTangentByID(ctx context.Context, id uuid.UUID) (*Tangent, apperrors.Error) {
 	tangent, err := db.DB(ctx).GetTangent(ctx, id)
 	if err != nil {
@@ -0,0 +1,150 @@
+package tangent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+func TestSelectTangent(t *testing.T) {
+	stdioOnly := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}}
+	withPython := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID, catcommon.PythonRunnerID}}
+	infos := []TangentInfo{stdioOnly, withPython}
+
+	t.Run("prefers tangent supporting all runners", func(t *testing.T) {
+		selected := selectTangent(infos, []catcommon.RunnerID{catcommon.StdioRunnerID, catcommon.PythonRunnerID}, nil)
+		assert.Equal(t, withPython.ID, selected.ID)
+	})
+
+	t.Run("first tangent when it is capable", func(t *testing.T) {
+		selected := selectTangent(infos, []catcommon.RunnerID{catcommon.StdioRunnerID}, nil)
+		assert.Equal(t, stdioOnly.ID, selected.ID)
+	})
+
+	t.Run("falls back to first tangent", func(t *testing.T) {
+		selected := selectTangent(infos, []catcommon.RunnerID{catcommon.MCPRemoteRunnerID}, nil)
+		assert.Equal(t, stdioOnly.ID, selected.ID)
+	})
+}
+
+func TestSelectTangentSessionLimit(t *testing.T) {
+	full := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}, MaxSessions: 2}
+	available := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}, MaxSessions: 2}
+	unlimited := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}}
+	stdio := []catcommon.RunnerID{catcommon.StdioRunnerID}
+
+	t.Run("prefers tangent below its limit", func(t *testing.T) {
+		selected := selectTangent([]TangentInfo{full, available}, stdio, map[uuid.UUID]int{full.ID: 2, available.ID: 1})
+		assert.Equal(t, available.ID, selected.ID)
+	})
+
+	t.Run("unlimited tangent is never full", func(t *testing.T) {
+		selected := selectTangent([]TangentInfo{full, unlimited}, stdio, map[uuid.UUID]int{full.ID: 2, unlimited.ID: 100})
+		assert.Equal(t, unlimited.ID, selected.ID)
+	})
+
+	t.Run("capabilities preferred over capacity", func(t *testing.T) {
+		withPython := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.PythonRunnerID}, MaxSessions: 1}
+		selected := selectTangent([]TangentInfo{available, withPython}, []catcommon.RunnerID{catcommon.PythonRunnerID}, map[uuid.UUID]int{withPython.ID: 1})
+		assert.Equal(t, withPython.ID, selected.ID)
+	})
+
+	t.Run("falls back to first tangent when all are full", func(t *testing.T) {
+		selected := selectTangent([]TangentInfo{full, available}, stdio, map[uuid.UUID]int{full.ID: 2, available.ID: 2})
+		assert.Equal(t, full.ID, selected.ID)
+	})
+}
+
+func TestSelectPinnedTangent(t *testing.T) {
+	stdioOnly := TangentInfo{ID: uuid.New(), URL: "https://tangent-us.example.com:8468", Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}}
+	withPython := TangentInfo{ID: uuid.New(), URL: "https://tangent-eu.example.com:8468", Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID, catcommon.PythonRunnerID}, MaxSessions: 1}
+	infos := []TangentInfo{stdioOnly, withPython}
+	stdio := []catcommon.RunnerID{catcommon.StdioRunnerID}
+	python := []catcommon.RunnerID{catcommon.PythonRunnerID}
+
+	t.Run("pinned tangent is used when auto-selection would pick another", func(t *testing.T) {
+		selected, err := selectPinnedTangent(infos, TangentPin{ID: withPython.ID}, stdio, nil, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, withPython.ID, selected.ID)
+		assert.Equal(t, stdioOnly.ID, selectTangent(infos, stdio, nil).ID)
+	})
+
+	t.Run("pinned by URL", func(t *testing.T) {
+		selected, err := selectPinnedTangent(infos, TangentPin{URL: "https://tangent-eu.example.com:8468/"}, python, nil, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, withPython.ID, selected.ID)
+	})
+
+	t.Run("rejected when it lacks capabilities", func(t *testing.T) {
+		_, err := selectPinnedTangent(infos, TangentPin{ID: stdioOnly.ID}, python, nil, time.Time{})
+		assert.ErrorIs(t, err, ErrPinnedTangentUnavailable)
+	})
+
+	t.Run("rejected when it is full", func(t *testing.T) {
+		_, err := selectPinnedTangent(infos, TangentPin{ID: withPython.ID}, python, map[uuid.UUID]int{withPython.ID: 1}, time.Time{})
+		assert.ErrorIs(t, err, ErrPinnedTangentUnavailable)
+	})
+
+	t.Run("rejected when it is not registered", func(t *testing.T) {
+		_, err := selectPinnedTangent(infos, TangentPin{URL: "https://tangent-ap.example.com:8468"}, stdio, nil, time.Time{})
+		assert.ErrorIs(t, err, ErrPinnedTangentNotFound)
+	})
+}
+
+func TestDrainingTangentNotSelected(t *testing.T) {
+	draining := TangentInfo{ID: uuid.New(), URL: "https://tangent-us.example.com:8468", Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID, catcommon.PythonRunnerID}, Status: StatusDraining}
+	stdioOnly := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}, MaxSessions: 1}
+	infos := []TangentInfo{draining, stdioOnly}
+	python := []catcommon.RunnerID{catcommon.PythonRunnerID}
+
+	t.Run("skipped even when it is the only capable tangent", func(t *testing.T) {
+		schedulable := schedulableTangents(infos)
+		require.Len(t, schedulable, 1)
+		selected := selectTangent(schedulable, python, map[uuid.UUID]int{stdioOnly.ID: 1})
+		assert.Equal(t, stdioOnly.ID, selected.ID)
+		assert.Equal(t, StatusDraining, infos[0].Status, "infos must not be modified")
+	})
+
+	t.Run("none schedulable when all are draining", func(t *testing.T) {
+		assert.Empty(t, schedulableTangents([]TangentInfo{draining}))
+	})
+
+	t.Run("pinned tangent rejected", func(t *testing.T) {
+		_, err := selectPinnedTangent(infos, TangentPin{ID: draining.ID}, python, nil, time.Time{})
+		assert.ErrorIs(t, err, ErrPinnedTangentUnavailable)
+		_, err = selectPinnedTangent(infos, TangentPin{URL: draining.URL}, python, nil, time.Time{})
+		assert.ErrorIs(t, err, ErrPinnedTangentUnavailable)
+	})
+}
+
+func TestStalePinnedTangentRejected(t *testing.T) {
+	now := time.Now()
+	alive := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}, LastHeartbeatAt: now.Add(-10 * time.Second)}
+	stale := TangentInfo{ID: uuid.New(), Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID}, LastHeartbeatAt: now.Add(-5 * time.Minute)}
+	infos := []TangentInfo{alive, stale}
+	stdio := []catcommon.RunnerID{catcommon.StdioRunnerID}
+	healthyAfter := now.Add(-time.Minute)
+
+	selected, err := selectPinnedTangent(infos, TangentPin{ID: alive.ID}, stdio, nil, healthyAfter)
+	require.NoError(t, err)
+	assert.Equal(t, alive.ID, selected.ID)
+
+	_, err = selectPinnedTangent(infos, TangentPin{ID: stale.ID}, stdio, nil, healthyAfter)
+	assert.ErrorIs(t, err, ErrPinnedTangentUnavailable)
+	assert.Contains(t, err.Error(), "has not sent a heartbeat")
+
+	_, err = selectPinnedTangent(infos, TangentPin{ID: stale.ID}, stdio, nil, time.Time{})
+	assert.NoError(t, err, "heartbeat is not checked without a cutoff")
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	missing := missingCapabilities(
+		[]catcommon.RunnerID{catcommon.StdioRunnerID},
+		[]catcommon.RunnerID{catcommon.StdioRunnerID, catcommon.PythonRunnerID, catcommon.PythonRunnerID},
+	)
+	assert.Equal(t, []catcommon.RunnerID{catcommon.PythonRunnerID}, missing)
+}
@@ -51,6 +51,15 @@ Examples:
   # Create a session with input arguments
   tansive session create /valid-skillset/test-skill --input-args '{"input":"test input"}'
 
+  # Create a session whose input arguments are the value of a skillset context
+  tansive session create /valid-skillset/test-skill --view valid-view --input-args-ref test-context
+
+  # Create a session that uses the skillset's source overrides for prod
+  tansive session create /valid-skillset/test-skill --view valid-view --environment prod
+
+  # Create a session with a one-off value for a skillset context
+  tansive session create /valid-skillset/test-skill --view valid-view --context-overrides '{"test-context":{"name":"override"}}'
+
   # Create a session with all options
   tansive session create /valid-skillset/test-skill --view valid-view --session-vars '{"key1":"value1"}' --input-args '{"input":"test input"}'`,
 	Args: cobra.ExactArgs(1),
@@ -65,6 +74,10 @@ Examples:
 			}
 		}
 
+		if inputArgsStr != "" && inputArgsRef != "" {
+			return fmt.Errorf("--input-args and --input-args-ref cannot be used together")
+		}
+
 		var inputArgs map[string]any
 		if inputArgsStr != "" {
 			if err := json.Unmarshal([]byte(inputArgsStr), &inputArgs); err != nil {
@@ -72,6 +85,13 @@ Examples:
 			}
 		}
 
+		var contextOverrides map[string]any
+		if contextOverridesStr != "" {
+			if err := json.Unmarshal([]byte(contextOverridesStr), &contextOverrides); err != nil {
+				return fmt.Errorf("invalid context overrides JSON: %v", err)
+			}
+		}
+
 		requestBody := map[string]any{
 			"skillPath": skillPath,
 			"viewName":  viewName,
@@ -82,6 +102,15 @@ Examples:
 		if inputArgs != nil {
 			requestBody["inputArgs"] = inputArgs
 		}
+		if inputArgsRef != "" {
+			requestBody["inputArgsRef"] = inputArgsRef
+		}
+		if environment != "" {
+			requestBody["environment"] = environment
+		}
+		if contextOverrides != nil {
+			requestBody["contextOverrides"] = contextOverrides
+		}
 
 		bodyBytes, err := json.Marshal(requestBody)
 		if err != nil {
@@ -477,10 +506,13 @@ func generateCodeVerifier() (string, error) {
 }
 
 var (
-	sessionVarsStr string
-	inputArgsStr   string
-	viewName       string
-	interactive    bool
+	sessionVarsStr      string
+	inputArgsStr        string
+	inputArgsRef        string
+	contextOverridesStr string
+	viewName            string
+	environment         string
+	interactive         bool
 )
 
 // init initializes the session command and its subcommands
@@ -495,5 +527,8 @@ func init() {
 	createSessionCmd.MarkFlagRequired("view")
 	createSessionCmd.Flags().StringVar(&sessionVarsStr, "session-vars", "", "JSON string of session variables")
 	createSessionCmd.Flags().StringVar(&inputArgsStr, "input-args", "", "JSON string of input arguments")
+	createSessionCmd.Flags().StringVar(&inputArgsRef, "input-args-ref", "", "Name of a skillset context whose value is used as the input arguments")
+	createSessionCmd.Flags().StringVar(&contextOverridesStr, "context-overrides", "", "JSON object of skillset context values, keyed by context name, that apply to this session only")
+	createSessionCmd.Flags().StringVar(&environment, "environment", "", "Environment whose skillset source overrides apply to the session")
 	createSessionCmd.Flags().BoolVar(&interactive, "interactive", false, "Set interactive mode for the session (default: false)")
 }
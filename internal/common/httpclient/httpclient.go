@@ -6,9 +6,7 @@ package httpclient
 
 import (
 	"bytes"
-	"crypto/ed25519"
 	"crypto/tls"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tidwall/gjson"
 )
 
@@ -31,6 +30,20 @@ type Configurator interface {
 	GetTokenExpiry() time.Time
 }
 
+// SignatureHeadersConfigurator is implemented by Configurators that sign requests with
+// custom signature header names. Requests are signed with the default names otherwise.
+type SignatureHeadersConfigurator interface {
+	GetSignatureHeaders() tangentsig.Headers
+}
+
+// signatureHeaders returns the signature header names to use with config.
+func signatureHeaders(config Configurator) tangentsig.Headers {
+	if hc, ok := config.(SignatureHeadersConfigurator); ok {
+		return hc.GetSignatureHeaders().WithDefaults()
+	}
+	return tangentsig.DefaultHeaders
+}
+
 // ServerError represents an error response from the server with a result code and error message.
 type ServerError struct {
 	Result int    `json:"result"` // HTTP status code or result code from server
@@ -172,37 +185,14 @@ func (c *HTTPClient) setAuthHeaders(req *http.Request) {
 	}
 }
 
-// signRequest signs the request with an Ed25519 private key if available.
+// signRequest signs the request with the configured private key if available.
+// Ed25519 and ECDSA P-256 keys are supported; the algorithm is sent in a header.
 func (c *HTTPClient) signRequest(req *http.Request, opts RequestOptions, rawQuery string) {
 	keyID, privateKeyBytes := c.config.GetSigningKey()
-	if len(privateKeyBytes) != ed25519.PrivateKeySize {
+	if _, err := tangentsig.PrivateKeyAlgorithm(privateKeyBytes); err != nil {
 		return
 	}
-
-	privateKey := ed25519.PrivateKey(privateKeyBytes)
-	timestamp := time.Now().UTC().Format(time.RFC3339)
-
-	// Ensure the request path starts with a slash to match server expectation
-	requestPath := opts.Path
-	if !strings.HasPrefix(requestPath, "/") {
-		requestPath = "/" + requestPath
-	}
-
-	// Canonical string to sign - use requestPath to match server expectation
-	stringToSign := strings.Join([]string{
-		opts.Method,
-		requestPath,
-		rawQuery,
-		string(opts.Body),
-		timestamp,
-	}, "\n")
-
-	signature := ed25519.Sign(privateKey, []byte(stringToSign))
-	signatureB64 := base64.StdEncoding.EncodeToString(signature)
-
-	req.Header.Set("X-Tangent-Signature", signatureB64)
-	req.Header.Set("X-Tangent-Signature-Timestamp", timestamp)
-	req.Header.Set("X-TangentID", keyID)
+	_ = tangentsig.SignRequestWithHeaders(req, signatureHeaders(c.config), keyID, privateKeyBytes, opts.Path, rawQuery, opts.Body)
 }
 
 // handleErrorResponse creates an appropriate HTTPError based on the status code and response body.
@@ -2,8 +2,6 @@ package httpclient
 
 import (
 	"bytes"
-	"crypto/ed25519"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +13,7 @@ import (
 	"time"
 
 	"github.com/tansive/tansive/internal/catalogsrv/server"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tidwall/gjson"
 )
 
@@ -82,32 +81,10 @@ func (c *TestHTTPClient) DoRequest(opts RequestOptions) ([]byte, string, error)
 
 	// Sign request if SigningKey is present
 	keyID, privateKeyBytes := c.config.GetSigningKey()
-	if len(privateKeyBytes) == ed25519.PrivateKeySize {
-		privateKey := ed25519.PrivateKey(privateKeyBytes)
-
-		timestamp := time.Now().UTC().Format(time.RFC3339)
-
-		// Ensure the request path starts with a slash to match server expectation
-		requestPath := opts.Path
-		if !strings.HasPrefix(requestPath, "/") {
-			requestPath = "/" + requestPath
+	if _, err := tangentsig.PrivateKeyAlgorithm(privateKeyBytes); err == nil {
+		if err := tangentsig.SignRequestWithHeaders(req, signatureHeaders(c.config), keyID, privateKeyBytes, opts.Path, u.RawQuery, opts.Body); err != nil {
+			return nil, "", err
 		}
-
-		// Canonical string to sign
-		stringToSign := strings.Join([]string{
-			opts.Method,
-			requestPath,
-			u.RawQuery,
-			string(opts.Body),
-			timestamp,
-		}, "\n")
-
-		signature := ed25519.Sign(privateKey, []byte(stringToSign))
-		signatureB64 := base64.StdEncoding.EncodeToString(signature)
-
-		req.Header.Set("X-Tangent-Signature", signatureB64)
-		req.Header.Set("X-Tangent-Signature-Timestamp", timestamp)
-		req.Header.Set("X-TangentID", keyID)
 	}
 
 	rr := httptest.NewRecorder()
@@ -1,9 +1,13 @@
 package httpx
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/common/logtrace"
@@ -32,6 +36,13 @@ func SendJsonRsp(ctx context.Context, w http.ResponseWriter, statusCode int, msg
 			return
 		}
 	}
+	if isPrettyJSON(ctx) {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, msgJson, "", "  "); err == nil {
+			indented.WriteByte('\n')
+			msgJson = indented.Bytes()
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if statusCode == http.StatusCreated && len(location) > 0 {
 		w.Header().Set("Location", location[0])
@@ -39,3 +50,34 @@ func SendJsonRsp(ctx context.Context, w http.ResponseWriter, statusCode int, msg
 	w.WriteHeader(statusCode)
 	w.Write(msgJson)
 }
+
+type prettyJSONKey struct{}
+
+// WithPrettyJSON returns a context in which SendJsonRsp writes indented JSON.
+func WithPrettyJSON(ctx context.Context) context.Context {
+	return context.WithValue(ctx, prettyJSONKey{}, true)
+}
+
+func isPrettyJSON(ctx context.Context) bool {
+	pretty, _ := ctx.Value(prettyJSONKey{}).(bool)
+	return pretty
+}
+
+// PrettyJSONRequested reports whether a request asks for indented JSON, either with the pretty
+// query parameter or with a pretty parameter on an application/json media type it accepts,
+// e.g. "Accept: application/json; pretty=true".
+func PrettyJSONRequested(r *http.Request) bool {
+	if pretty, err := strconv.ParseBool(r.URL.Query().Get("pretty")); err == nil {
+		return pretty
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil || mediaType != "application/json" {
+			continue
+		}
+		if pretty, err := strconv.ParseBool(params["pretty"]); err == nil {
+			return pretty
+		}
+	}
+	return false
+}
@@ -12,4 +12,6 @@ var (
 	ErrInvalidJSFunction = ErrJSRuntime.New("invalid javascript function")
 	ErrJSRuntimeError    = ErrJSRuntime.New("jsruntime error").SetStatusCode(http.StatusBadRequest).SetExpandError(true)
 	ErrJSExecutionError  = ErrJSRuntime.New("js execution error").SetStatusCode(http.StatusUnprocessableEntity).SetExpandError(true)
+	ErrJSInputTooLarge   = ErrJSRuntime.New("jsruntime input too large").SetStatusCode(http.StatusRequestEntityTooLarge).SetExpandError(true)
+	ErrJSOutputTooLarge  = ErrJSRuntime.New("jsruntime output too large").SetStatusCode(http.StatusUnprocessableEntity).SetExpandError(true)
 )
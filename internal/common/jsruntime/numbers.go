@@ -0,0 +1,88 @@
+package jsruntime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// NumberMode controls how JavaScript numbers in a function's result are mapped to Go types.
+type NumberMode int
+
+const (
+	// NumberModeIntWhenWhole returns whole numbers as int64 and fractional numbers as float64.
+	// This is the default.
+	NumberModeIntWhenWhole NumberMode = iota
+	// NumberModeAlwaysFloat returns every number as float64, so a value such as 2.0 stays a float.
+	NumberModeAlwaysFloat
+	// NumberModeJSON returns every number as a json.Number holding its JSON representation.
+	// NaN and infinite values, which JSON cannot represent, are returned as nil.
+	NumberModeJSON
+)
+
+// ParseNumberMode returns the number mode named by s, which is one of "intWhenWhole",
+// "alwaysFloat" or "json". An empty string selects the default mode.
+func ParseNumberMode(s string) (NumberMode, error) {
+	switch s {
+	case "", "intWhenWhole":
+		return NumberModeIntWhenWhole, nil
+	case "alwaysFloat":
+		return NumberModeAlwaysFloat, nil
+	case "json":
+		return NumberModeJSON, nil
+	}
+	return NumberModeIntWhenWhole, fmt.Errorf("unknown number mode %q", s)
+}
+
+// convertNumbers returns v with its numbers mapped according to mode. Maps and slices are
+// copied rather than modified, since they may be shared with the function's arguments.
+func convertNumbers(v any, mode NumberMode) any {
+	if mode == NumberModeIntWhenWhole {
+		return v
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(val))
+		for k, item := range val {
+			converted[k] = convertNumbers(item, mode)
+		}
+		return converted
+	case []any:
+		converted := make([]any, len(val))
+		for i, item := range val {
+			converted[i] = convertNumbers(item, mode)
+		}
+		return converted
+	case int:
+		return convertInt(int64(val), mode)
+	case int32:
+		return convertInt(int64(val), mode)
+	case int64:
+		return convertInt(val, mode)
+	case float32:
+		return convertFloat(float64(val), mode)
+	case float64:
+		return convertFloat(val, mode)
+	default:
+		return v
+	}
+}
+
+func convertInt(i int64, mode NumberMode) any {
+	if mode == NumberModeJSON {
+		return json.Number(strconv.FormatInt(i, 10))
+	}
+	return float64(i)
+}
+
+func convertFloat(f float64, mode NumberMode) any {
+	if mode == NumberModeJSON {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil
+		}
+		data, _ := json.Marshal(f)
+		return json.Number(data)
+	}
+	return f
+}
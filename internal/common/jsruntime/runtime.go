@@ -2,6 +2,7 @@ package jsruntime
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -18,6 +19,11 @@ type JSFunction struct {
 type Options struct {
 	Timeout      time.Duration // max execution time
 	SkillInvoker SkillInvoker
+	NumberMode   NumberMode // how numbers in the result map to Go types
+	// MaxInputBytes limits the JSON encoded size of the session and input arguments. 0 for no limit.
+	MaxInputBytes int
+	// MaxOutputBytes limits the JSON encoded size of the result. 0 for no limit.
+	MaxOutputBytes int
 }
 
 type SkillInvoker func(skillName string, inputArgs map[string]any) ([]byte, apperrors.Error)
@@ -45,6 +51,16 @@ func New(ctx context.Context, jsCode string) (*JSFunction, apperrors.Error) {
 
 // Run executes the function with two JSON arguments, respecting timeout and returning JSON output.
 func (j *JSFunction) Run(ctx context.Context, sessionArgs, inputArgs map[string]any, opts Options) (map[string]any, apperrors.Error) {
+	if opts.MaxInputBytes > 0 {
+		size, err := jsonSize(sessionArgs, inputArgs)
+		if err != nil {
+			return nil, ErrJSExecutionError.Msg("unable to measure input: " + err.Error())
+		}
+		if size > opts.MaxInputBytes {
+			return nil, ErrJSInputTooLarge.Msg(fmt.Sprintf("transform input is %d bytes, limit is %d", size, opts.MaxInputBytes))
+		}
+	}
+
 	// New VM per run to isolate memory
 	vm := goja.New()
 	bindConsole(ctx, vm)
@@ -103,5 +119,28 @@ func (j *JSFunction) Run(ctx context.Context, sessionArgs, inputArgs map[string]
 		return nil, ErrJSExecutionError.Msg(msg)
 	}
 
+	resMap = convertNumbers(resMap, opts.NumberMode).(map[string]any)
+	if opts.MaxOutputBytes > 0 {
+		size, err := jsonSize(resMap)
+		if err != nil {
+			return nil, ErrJSExecutionError.Msg("unable to encode result: " + err.Error())
+		}
+		if size > opts.MaxOutputBytes {
+			return nil, ErrJSOutputTooLarge.Msg(fmt.Sprintf("transform output is %d bytes, limit is %d", size, opts.MaxOutputBytes))
+		}
+	}
 	return resMap, nil
 }
+
+// jsonSize returns the total size of the JSON encoding of values.
+func jsonSize(values ...any) (int, error) {
+	size := 0
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return 0, err
+		}
+		size += len(b)
+	}
+	return size, nil
+}
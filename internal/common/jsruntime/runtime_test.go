@@ -3,6 +3,7 @@ package jsruntime
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -536,3 +537,130 @@ func BenchmarkJSFunction_New(b *testing.B) {
 		}
 	}
 }
+
+func TestJSFunction_Run_NumberMode(t *testing.T) {
+	jsCode := "function(session, input) { return { whole: 2.0, fraction: 3.5, nested: { items: [1, 0.25] }, passed: input.value }; }"
+	inputArgs := map[string]any{"value": 7}
+
+	tests := []struct {
+		name       string
+		mode       NumberMode
+		wantResult map[string]any
+	}{
+		{
+			name: "int when whole",
+			mode: NumberModeIntWhenWhole,
+			wantResult: map[string]any{
+				"whole":    int64(2),
+				"fraction": 3.5,
+				"nested":   map[string]any{"items": []any{int64(1), 0.25}},
+				"passed":   int64(7),
+			},
+		},
+		{
+			name: "always float",
+			mode: NumberModeAlwaysFloat,
+			wantResult: map[string]any{
+				"whole":    2.0,
+				"fraction": 3.5,
+				"nested":   map[string]any{"items": []any{1.0, 0.25}},
+				"passed":   7.0,
+			},
+		},
+		{
+			name: "json",
+			mode: NumberModeJSON,
+			wantResult: map[string]any{
+				"whole":    json.Number("2"),
+				"fraction": json.Number("3.5"),
+				"nested":   map[string]any{"items": []any{json.Number("1"), json.Number("0.25")}},
+				"passed":   json.Number("7"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsFunc, err := New(context.Background(), jsCode)
+			require.NoError(t, err)
+
+			result, err := jsFunc.Run(context.Background(), nil, inputArgs, Options{NumberMode: tt.mode})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult, result)
+		})
+	}
+
+	t.Run("arguments are not modified", func(t *testing.T) {
+		session := map[string]any{"config": map[string]any{"count": 3}}
+		jsFunc, err := New(context.Background(), "function(session, input) { return session; }")
+		require.NoError(t, err)
+
+		result, err := jsFunc.Run(context.Background(), session, nil, Options{NumberMode: NumberModeAlwaysFloat})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"config": map[string]any{"count": 3.0}}, result)
+		assert.Equal(t, map[string]any{"count": 3}, session["config"])
+	})
+
+	t.Run("non-finite numbers are null in json mode", func(t *testing.T) {
+		jsFunc, err := New(context.Background(), "function(session, input) { return { nan: NaN, inf: Infinity }; }")
+		require.NoError(t, err)
+
+		result, err := jsFunc.Run(context.Background(), nil, nil, Options{NumberMode: NumberModeJSON})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"nan": nil, "inf": nil}, result)
+	})
+}
+
+func TestJSFunction_Run_SizeLimits(t *testing.T) {
+	jsFunc, err := New(context.Background(), "function(session, input) { return { text: input.text.repeat(input.times) }; }")
+	require.NoError(t, err)
+	opts := Options{MaxInputBytes: 100, MaxOutputBytes: 100}
+
+	t.Run("normal sized input and output pass", func(t *testing.T) {
+		result, err := jsFunc.Run(context.Background(), map[string]any{"user": "a"}, map[string]any{"text": "ab", "times": 3}, opts)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"text": "ababab"}, result)
+	})
+
+	t.Run("oversized input is rejected before execution", func(t *testing.T) {
+		input := map[string]any{"text": string(bytes.Repeat([]byte("x"), 200)), "times": 1}
+		result, err := jsFunc.Run(context.Background(), nil, input, opts)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrJSInputTooLarge)
+		assert.False(t, errors.Is(err, ErrJSOutputTooLarge))
+	})
+
+	t.Run("session arguments count towards the input", func(t *testing.T) {
+		session := map[string]any{"blob": string(bytes.Repeat([]byte("x"), 200))}
+		_, err := jsFunc.Run(context.Background(), session, map[string]any{"text": "a", "times": 1}, opts)
+		assert.ErrorIs(t, err, ErrJSInputTooLarge)
+	})
+
+	t.Run("oversized output is rejected", func(t *testing.T) {
+		result, err := jsFunc.Run(context.Background(), nil, map[string]any{"text": "ab", "times": 100}, opts)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrJSOutputTooLarge)
+		assert.False(t, errors.Is(err, ErrJSInputTooLarge))
+	})
+
+	t.Run("zero limits are unlimited", func(t *testing.T) {
+		result, err := jsFunc.Run(context.Background(), nil, map[string]any{"text": "ab", "times": 1000}, Options{})
+		require.NoError(t, err)
+		assert.Len(t, result["text"], 2000)
+	})
+}
+
+func TestParseNumberMode(t *testing.T) {
+	for name, want := range map[string]NumberMode{
+		"":             NumberModeIntWhenWhole,
+		"intWhenWhole": NumberModeIntWhenWhole,
+		"alwaysFloat":  NumberModeAlwaysFloat,
+		"json":         NumberModeJSON,
+	} {
+		mode, err := ParseNumberMode(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, mode)
+	}
+	_, err := ParseNumberMode("decimal")
+	assert.Error(t, err)
+}
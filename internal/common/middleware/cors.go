@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/cors"
+)
+
+// CORSConfig holds the cross-origin resource sharing settings of a server. Allowed origins are
+// exact origins such as "https://app.example.com", patterns with a single '*' wildcard such as
+// "https://*.example.com", or "*" to allow any origin.
+type CORSConfig struct {
+	AllowedOrigins   []string `toml:"allowed_origins"`   // Origins allowed to make cross-origin requests
+	AllowedMethods   []string `toml:"allowed_methods"`   // Methods allowed in cross-origin requests
+	AllowedHeaders   []string `toml:"allowed_headers"`   // Request headers allowed in cross-origin requests
+	ExposedHeaders   []string `toml:"exposed_headers"`   // Response headers exposed to the browser
+	AllowCredentials bool     `toml:"allow_credentials"` // Whether requests may carry credentials such as cookies
+	MaxAge           int      `toml:"max_age"`           // Seconds a preflight response may be cached
+}
+
+// Defaults used when the corresponding CORS settings are not set
+var (
+	DefaultCORSAllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	DefaultCORSAllowedHeaders = []string{"Accept", "Authorization", "Content-Type", "Content-Length", "Accept-Encoding"}
+	DefaultCORSExposedHeaders = []string{"Link", "Location", "X-Tansive-Request-Id"}
+)
+
+// DefaultCORSMaxAge is the preflight cache duration in seconds used when max_age is not set
+const DefaultCORSMaxAge = 300
+
+// Validate checks the origin patterns and fills in defaults for settings that are not set.
+// Allowing any origin together with credentials is rejected since it would let any site make
+// authenticated requests.
+func (c *CORSConfig) Validate() error {
+	if len(c.AllowedOrigins) == 0 {
+		return fmt.Errorf("cors.allowed_origins must not be empty")
+	}
+	for _, origin := range c.AllowedOrigins {
+		if err := validateOriginPattern(origin); err != nil {
+			return fmt.Errorf("invalid cors.allowed_origins entry %q: %w", origin, err)
+		}
+		if origin == "*" && c.AllowCredentials {
+			return fmt.Errorf("cors.allow_credentials cannot be used with the \"*\" origin")
+		}
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("cors.max_age must not be negative")
+	}
+	if c.MaxAge == 0 {
+		c.MaxAge = DefaultCORSMaxAge
+	}
+	if len(c.AllowedMethods) == 0 {
+		c.AllowedMethods = DefaultCORSAllowedMethods
+	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = DefaultCORSAllowedHeaders
+	}
+	if len(c.ExposedHeaders) == 0 {
+		c.ExposedHeaders = DefaultCORSExposedHeaders
+	}
+	return nil
+}
+
+// validateOriginPattern checks that origin is "*" or a scheme and host, optionally with a port and
+// a single '*' wildcard, and nothing else.
+func validateOriginPattern(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	if strings.Count(origin, "*") > 1 {
+		return fmt.Errorf("at most one wildcard is allowed")
+	}
+	u, err := url.Parse(strings.Replace(origin, "*", "wildcard", 1))
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("must consist of a scheme and host only")
+	}
+	return nil
+}
+
+// originMatcher reports whether an origin matches one of the allowed origin patterns.
+type originMatcher struct {
+	any      bool
+	exact    map[string]bool
+	wildcard [][2]string // prefix and suffix around the wildcard
+}
+
+func newOriginMatcher(patterns []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]bool)}
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		switch {
+		case p == "*":
+			m.any = true
+		case strings.Contains(p, "*"):
+			prefix, suffix, _ := strings.Cut(p, "*")
+			m.wildcard = append(m.wildcard, [2]string{prefix, suffix})
+		default:
+			m.exact[p] = true
+		}
+	}
+	return m
+}
+
+func (m *originMatcher) allowed(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if m.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if m.exact[origin] {
+		return true
+	}
+	for _, w := range m.wildcard {
+		if len(origin) > len(w[0])+len(w[1]) && strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS creates middleware that applies the given CORS settings, which must have been validated.
+// Preflight requests from origins that are not allowed are rejected with 403 Forbidden.
+func CORS(c CORSConfig) func(http.Handler) http.Handler {
+	origins := newOriginMatcher(c.AllowedOrigins)
+	handler := cors.Handler(cors.Options{
+		AllowOriginFunc:  func(_ *http.Request, origin string) bool { return origins.allowed(origin) },
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	})
+	return func(next http.Handler) http.Handler {
+		withCORS := handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			isPreflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if isPreflight && !origins.allowed(r.Header.Get("Origin")) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			withCORS.ServeHTTP(w, r)
+		})
+	}
+}
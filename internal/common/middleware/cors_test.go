@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      CORSConfig
+		expectError bool
+	}{
+		{name: "exact origin", config: CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}},
+		{name: "origin with port", config: CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}}},
+		{name: "wildcard subdomain", config: CORSConfig{AllowedOrigins: []string{"https://*.example.com"}}},
+		{name: "any origin", config: CORSConfig{AllowedOrigins: []string{"*"}}},
+		{name: "no origins", config: CORSConfig{}, expectError: true},
+		{name: "missing scheme", config: CORSConfig{AllowedOrigins: []string{"app.example.com"}}, expectError: true},
+		{name: "unsupported scheme", config: CORSConfig{AllowedOrigins: []string{"ftp://app.example.com"}}, expectError: true},
+		{name: "origin with path", config: CORSConfig{AllowedOrigins: []string{"https://app.example.com/ui"}}, expectError: true},
+		{name: "two wildcards", config: CORSConfig{AllowedOrigins: []string{"https://*.*.example.com"}}, expectError: true},
+		{name: "any origin with credentials", config: CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, expectError: true},
+		{name: "negative max age", config: CORSConfig{AllowedOrigins: []string{"*"}, MaxAge: -1}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, DefaultCORSAllowedMethods, tt.config.AllowedMethods)
+			assert.Equal(t, DefaultCORSMaxAge, tt.config.MaxAge)
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	config := CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.tools.example.com"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+	}
+	require.NoError(t, config.Validate())
+
+	handler := CORS(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/catalogs", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("preflight from an allowed origin", func(t *testing.T) {
+		for _, origin := range []string{"https://app.example.com", "https://ci.tools.example.com"} {
+			rr := preflight(origin)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, origin, rr.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, http.MethodPost, rr.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "Authorization", rr.Header().Get("Access-Control-Allow-Headers"))
+			assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, "300", rr.Header().Get("Access-Control-Max-Age"))
+		}
+	})
+
+	t.Run("preflight from a disallowed origin", func(t *testing.T) {
+		for _, origin := range []string{"https://evil.example.com", "https://tools.example.com", "http://app.example.com"} {
+			rr := preflight(origin)
+			assert.Equal(t, http.StatusForbidden, rr.Code)
+			assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+		}
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/catalogs", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+
+		// requests from other origins are served without CORS headers, so browsers block the response
+		req.Header.Set("Origin", "https://evil.example.com")
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive/internal/common/httpx"
+)
+
+// PrettyJSON creates middleware that indents the JSON responses of requests that ask for it with
+// ?pretty=true or an Accept header such as "application/json; pretty=true". Responses are
+// compact by default.
+func PrettyJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if httpx.PrettyJSONRequested(r) {
+			r = r.WithContext(httpx.WithPrettyJSON(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/common/httpx"
+)
+
+func TestPrettyJSON(t *testing.T) {
+	handler := PrettyJSON(httpx.WrapHttpRsp(func(r *http.Request) (*httpx.Response, error) {
+		return &httpx.Response{
+			StatusCode: http.StatusOK,
+			Response:   map[string]any{"name": "dev-view", "rules": []string{"allow"}},
+		}, nil
+	}))
+	get := func(target string, accept string) string {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		return rr.Body.String()
+	}
+
+	compact := `{"name":"dev-view","rules":["allow"]}`
+	pretty := "{\n  \"name\": \"dev-view\",\n  \"rules\": [\n    \"allow\"\n  ]\n}\n"
+
+	assert.Equal(t, compact, get("/views/dev-view", ""), "compact by default")
+	assert.Equal(t, pretty, get("/views/dev-view?pretty=true", ""))
+	assert.Equal(t, pretty, get("/views/dev-view?pretty=1", ""))
+	assert.Equal(t, compact, get("/views/dev-view?pretty=false", ""))
+	assert.Equal(t, pretty, get("/views/dev-view", "text/html, application/json; pretty=true"))
+	assert.Equal(t, compact, get("/views/dev-view?pretty=false", "application/json; pretty=true"), "query parameter takes precedence")
+}
@@ -0,0 +1,258 @@
+// Package tangentsig implements the request signing scheme used by tangents
+// when calling the catalog server. Requests are signed over a canonical string
+// built from the method, path, query, body and timestamp. Ed25519 is the default
+// algorithm; ECDSA with P-256 is supported for hardware modules that cannot
+// produce Ed25519 signatures.
+package tangentsig
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Header names carried on signed tangent requests.
+const (
+	SignatureHeader          = "X-Tangent-Signature"
+	SignatureTimestampHeader = "X-Tangent-Signature-Timestamp"
+	SignatureAlgorithmHeader = "X-Tangent-Signature-Algorithm"
+	TangentIDHeader          = "X-TangentID"
+)
+
+// Headers names the headers that carry a request signature. Deployments behind proxies that
+// strip or rename the default headers can use other names, as long as the tangent and the
+// catalog server are configured with the same ones. Empty names fall back to the defaults.
+type Headers struct {
+	Signature string `toml:"signature"`  // Header carrying the base64 encoded signature
+	Timestamp string `toml:"timestamp"`  // Header carrying the signing timestamp
+	Algorithm string `toml:"algorithm"`  // Header carrying the signing algorithm
+	TangentID string `toml:"tangent_id"` // Header carrying the ID of the signing tangent
+}
+
+// DefaultHeaders are the header names used when none are configured.
+var DefaultHeaders = Headers{
+	Signature: SignatureHeader,
+	Timestamp: SignatureTimestampHeader,
+	Algorithm: SignatureAlgorithmHeader,
+	TangentID: TangentIDHeader,
+}
+
+var headerNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// WithDefaults returns h with empty names replaced by the default names.
+func (h Headers) WithDefaults() Headers {
+	if h.Signature == "" {
+		h.Signature = DefaultHeaders.Signature
+	}
+	if h.Timestamp == "" {
+		h.Timestamp = DefaultHeaders.Timestamp
+	}
+	if h.Algorithm == "" {
+		h.Algorithm = DefaultHeaders.Algorithm
+	}
+	if h.TangentID == "" {
+		h.TangentID = DefaultHeaders.TangentID
+	}
+	return h
+}
+
+// Validate checks that the configured names are valid header names and distinct from each
+// other. Empty names are allowed and fall back to the defaults.
+func (h Headers) Validate() error {
+	h = h.WithDefaults()
+	seen := make(map[string]string, 4)
+	for _, f := range []struct{ field, name string }{
+		{"signature", h.Signature},
+		{"timestamp", h.Timestamp},
+		{"algorithm", h.Algorithm},
+		{"tangent_id", h.TangentID},
+	} {
+		if !headerNameRegex.MatchString(f.name) {
+			return fmt.Errorf("invalid %s header name %q", f.field, f.name)
+		}
+		canonical := http.CanonicalHeaderKey(f.name)
+		if other, ok := seen[canonical]; ok {
+			return fmt.Errorf("%s and %s headers must have different names", other, f.field)
+		}
+		seen[canonical] = f.field
+	}
+	return nil
+}
+
+// Supported signing algorithms.
+const (
+	AlgorithmEd25519   = "ed25519"
+	AlgorithmECDSAP256 = "ecdsa-p256"
+
+	// DefaultAlgorithm is assumed when a request carries no algorithm header.
+	DefaultAlgorithm = AlgorithmEd25519
+)
+
+// StringToSign builds the canonical string signed by the tangent. The path is
+// normalized to start with a slash so client and server agree on its form.
+func StringToSign(method, path, rawQuery string, body []byte, timestamp string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.Join([]string{
+		method,
+		path,
+		rawQuery,
+		string(body),
+		timestamp,
+	}, "\n")
+}
+
+// PrivateKeyAlgorithm returns the algorithm for a private key. Ed25519 keys are
+// raw 64 byte keys; ECDSA keys are DER encoded in SEC 1 or PKCS #8 form.
+func PrivateKeyAlgorithm(privateKey []byte) (string, error) {
+	if len(privateKey) == ed25519.PrivateKeySize {
+		return AlgorithmEd25519, nil
+	}
+	if _, err := parseECDSAPrivateKey(privateKey); err != nil {
+		return "", err
+	}
+	return AlgorithmECDSAP256, nil
+}
+
+// PublicKeyAlgorithm returns the algorithm for a registered public key. Ed25519
+// keys are raw 32 byte keys; ECDSA keys are PKIX DER encoded.
+func PublicKeyAlgorithm(publicKey []byte) (string, error) {
+	if len(publicKey) == ed25519.PublicKeySize {
+		return AlgorithmEd25519, nil
+	}
+	if _, err := parseECDSAPublicKey(publicKey); err != nil {
+		return "", err
+	}
+	return AlgorithmECDSAP256, nil
+}
+
+// Sign signs payload with privateKey and returns the signature along with the
+// algorithm that was used.
+func Sign(privateKey []byte, payload []byte) ([]byte, string, error) {
+	alg, err := PrivateKeyAlgorithm(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+	switch alg {
+	case AlgorithmEd25519:
+		return ed25519.Sign(ed25519.PrivateKey(privateKey), payload), alg, nil
+	default:
+		key, err := parseECDSAPrivateKey(privateKey)
+		if err != nil {
+			return nil, "", err
+		}
+		digest := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to sign payload: %w", err)
+		}
+		return sig, alg, nil
+	}
+}
+
+// Verify checks signature over payload using the registered publicKey. The
+// verifier is selected by the key type; a non-empty alg that does not match the
+// key type is rejected.
+func Verify(alg string, publicKey []byte, payload []byte, signature []byte) error {
+	keyAlg, err := PublicKeyAlgorithm(publicKey)
+	if err != nil {
+		return err
+	}
+	if alg == "" {
+		alg = DefaultAlgorithm
+	}
+	if alg != keyAlg {
+		return fmt.Errorf("signature algorithm %q does not match registered key type %q", alg, keyAlg)
+	}
+
+	switch keyAlg {
+	case AlgorithmEd25519:
+		if !ed25519.Verify(ed25519.PublicKey(publicKey), payload, signature) {
+			return fmt.Errorf("signature verification failed")
+		}
+	default:
+		key, err := parseECDSAPublicKey(publicKey)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256(payload)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return fmt.Errorf("signature verification failed")
+		}
+	}
+	return nil
+}
+
+// SignRequest sets the default signature headers on req. It does nothing when
+// privateKey is empty.
+func SignRequest(req *http.Request, keyID string, privateKey []byte, path, rawQuery string, body []byte) error {
+	return SignRequestWithHeaders(req, DefaultHeaders, keyID, privateKey, path, rawQuery, body)
+}
+
+// SignRequestWithHeaders is like SignRequest but sets the signature on the given headers.
+func SignRequestWithHeaders(req *http.Request, headers Headers, keyID string, privateKey []byte, path, rawQuery string, body []byte) error {
+	if len(privateKey) == 0 {
+		return nil
+	}
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	stringToSign := StringToSign(req.Method, path, rawQuery, body, timestamp)
+
+	signature, alg, err := Sign(privateKey, []byte(stringToSign))
+	if err != nil {
+		return err
+	}
+
+	headers = headers.WithDefaults()
+	req.Header.Set(headers.Signature, base64.StdEncoding.EncodeToString(signature))
+	req.Header.Set(headers.Timestamp, timestamp)
+	req.Header.Set(headers.Algorithm, alg)
+	req.Header.Set(headers.TangentID, keyID)
+	return nil
+}
+
+func parseECDSAPrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return checkP256Private(key)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported private key format")
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+	return checkP256Private(key)
+}
+
+func checkP256Private(key *ecdsa.PrivateKey) (*ecdsa.PrivateKey, error) {
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("unsupported ECDSA curve %s", key.Curve.Params().Name)
+	}
+	return key, nil
+}
+
+func parseECDSAPublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported public key format")
+	}
+	key, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T", parsed)
+	}
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("unsupported ECDSA curve %s", key.Curve.Params().Name)
+	}
+	return key, nil
+}
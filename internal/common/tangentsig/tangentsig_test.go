@@ -0,0 +1,169 @@
+package tangentsig
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	alg     string
+	private []byte
+	public  []byte
+}
+
+func newTestKeys(t *testing.T) []testKey {
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecPrivDER, err := x509.MarshalECPrivateKey(ecPriv)
+	require.NoError(t, err)
+	ecPubDER, err := x509.MarshalPKIXPublicKey(&ecPriv.PublicKey)
+	require.NoError(t, err)
+
+	return []testKey{
+		{alg: AlgorithmEd25519, private: edPriv, public: edPub},
+		{alg: AlgorithmECDSAP256, private: ecPrivDER, public: ecPubDER},
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	payload := []byte(StringToSign(http.MethodPost, "sessions/execution-state", "a=b", []byte(`{"x":1}`), "2025-01-01T00:00:00Z"))
+
+	for _, key := range newTestKeys(t) {
+		t.Run(key.alg, func(t *testing.T) {
+			alg, err := PublicKeyAlgorithm(key.public)
+			require.NoError(t, err)
+			assert.Equal(t, key.alg, alg)
+
+			sig, alg, err := Sign(key.private, payload)
+			require.NoError(t, err)
+			assert.Equal(t, key.alg, alg)
+
+			assert.NoError(t, Verify(alg, key.public, payload, sig))
+			assert.Error(t, Verify(alg, key.public, append(payload, 'x'), sig))
+		})
+	}
+}
+
+func TestVerifyDefaultsToEd25519(t *testing.T) {
+	keys := newTestKeys(t)
+	payload := []byte("payload")
+
+	edSig, _, err := Sign(keys[0].private, payload)
+	require.NoError(t, err)
+	assert.NoError(t, Verify("", keys[0].public, payload, edSig))
+
+	ecSig, _, err := Sign(keys[1].private, payload)
+	require.NoError(t, err)
+	assert.Error(t, Verify("", keys[1].public, payload, ecSig))
+}
+
+func TestVerifyRejectsMismatchedAlgorithm(t *testing.T) {
+	keys := newTestKeys(t)
+	payload := []byte("payload")
+
+	edSig, _, err := Sign(keys[0].private, payload)
+	require.NoError(t, err)
+	assert.Error(t, Verify(AlgorithmECDSAP256, keys[0].public, payload, edSig))
+
+	ecSig, _, err := Sign(keys[1].private, payload)
+	require.NoError(t, err)
+	assert.Error(t, Verify(AlgorithmEd25519, keys[1].public, payload, ecSig))
+	assert.Error(t, Verify("rsa-sha256", keys[1].public, payload, ecSig))
+}
+
+func TestUnsupportedKeys(t *testing.T) {
+	_, err := PublicKeyAlgorithm([]byte("not a key"))
+	assert.Error(t, err)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(p384)
+	require.NoError(t, err)
+	_, _, err = Sign(der, []byte("payload"))
+	assert.Error(t, err)
+}
+
+func TestSignRequest(t *testing.T) {
+	for _, key := range newTestKeys(t) {
+		t.Run(key.alg, func(t *testing.T) {
+			body := []byte(`{"k":"v"}`)
+			req, err := http.NewRequest(http.MethodPut, "http://localhost/sessions/123?x=1", nil)
+			require.NoError(t, err)
+			require.NoError(t, SignRequest(req, "tangent-id", key.private, "sessions/123", "x=1", body))
+
+			assert.Equal(t, key.alg, req.Header.Get(SignatureAlgorithmHeader))
+			assert.Equal(t, "tangent-id", req.Header.Get(TangentIDHeader))
+
+			sig, err := base64.StdEncoding.DecodeString(req.Header.Get(SignatureHeader))
+			require.NoError(t, err)
+			payload := StringToSign(http.MethodPut, "/sessions/123", "x=1", body, req.Header.Get(SignatureTimestampHeader))
+			assert.NoError(t, Verify(req.Header.Get(SignatureAlgorithmHeader), key.public, []byte(payload), sig))
+		})
+	}
+}
+
+func TestSignRequestWithHeaders(t *testing.T) {
+	headers := Headers{
+		Signature: "X-Proxy-Safe-Sig",
+		Timestamp: "X-Proxy-Safe-Ts",
+		Algorithm: "X-Proxy-Safe-Alg",
+		TangentID: "X-Proxy-Safe-Id",
+	}
+	require.NoError(t, headers.Validate())
+
+	for _, key := range newTestKeys(t) {
+		t.Run(key.alg, func(t *testing.T) {
+			body := []byte(`{"k":"v"}`)
+			req, err := http.NewRequest(http.MethodPut, "http://localhost/sessions/123?x=1", nil)
+			require.NoError(t, err)
+			require.NoError(t, SignRequestWithHeaders(req, headers, "tangent-id", key.private, "sessions/123", "x=1", body))
+
+			// the default headers are not set
+			assert.Empty(t, req.Header.Get(SignatureHeader))
+			assert.Empty(t, req.Header.Get(SignatureTimestampHeader))
+			assert.Empty(t, req.Header.Get(SignatureAlgorithmHeader))
+			assert.Empty(t, req.Header.Get(TangentIDHeader))
+
+			assert.Equal(t, key.alg, req.Header.Get(headers.Algorithm))
+			assert.Equal(t, "tangent-id", req.Header.Get(headers.TangentID))
+			sig, err := base64.StdEncoding.DecodeString(req.Header.Get(headers.Signature))
+			require.NoError(t, err)
+			payload := StringToSign(http.MethodPut, "/sessions/123", "x=1", body, req.Header.Get(headers.Timestamp))
+			assert.NoError(t, Verify(req.Header.Get(headers.Algorithm), key.public, []byte(payload), sig))
+		})
+	}
+
+	t.Run("partial configuration", func(t *testing.T) {
+		key := newTestKeys(t)[0]
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/sessions", nil)
+		require.NoError(t, err)
+		require.NoError(t, SignRequestWithHeaders(req, Headers{Signature: "X-Proxy-Safe-Sig"}, "tangent-id", key.private, "sessions", "", nil))
+		assert.NotEmpty(t, req.Header.Get("X-Proxy-Safe-Sig"))
+		assert.Empty(t, req.Header.Get(SignatureHeader))
+		assert.NotEmpty(t, req.Header.Get(SignatureTimestampHeader))
+		assert.Equal(t, "tangent-id", req.Header.Get(TangentIDHeader))
+	})
+}
+
+func TestHeadersValidate(t *testing.T) {
+	assert.NoError(t, Headers{}.Validate())
+	assert.Equal(t, DefaultHeaders, Headers{}.WithDefaults())
+	assert.NoError(t, Headers{TangentID: "Tangent-Id"}.Validate())
+	assert.Error(t, Headers{Signature: "X Signature"}.Validate())
+	assert.Error(t, Headers{Signature: "X-Signature:"}.Validate())
+	assert.Error(t, Headers{Signature: "-Signature"}.Validate())
+	assert.Error(t, Headers{Signature: "x-tangentid"}.Validate(), "names must differ regardless of case")
+	assert.Error(t, Headers{Timestamp: "X-Custom", Algorithm: "X-Custom"}.Validate())
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive/internal/common/certs"
+	"github.com/tansive/tansive/internal/common/jsruntime"
+	"github.com/tansive/tansive/internal/common/middleware"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 )
 
 // StdioRunnerConfig holds stdio runner related configuration
@@ -41,12 +46,191 @@ func (a *AuthConfig) GetTokenExpiryOrDefault() time.Duration {
 type TansiveServerConfig struct {
 	URL           string `toml:"url"`            // Tansive server URL
 	OnboardingKey string `toml:"onboarding_key"` // Onboarding key for the tansive server
+	// SignatureHeaders names the headers requests to the tansive server are signed with. It
+	// must match the server's tangent.signature_headers.
+	SignatureHeaders tangentsig.Headers `toml:"signature_headers"`
+	// HeartbeatInterval is how often the liveness of each active session is reported to the
+	// tansive server. It must be shorter than the server's session.heartbeat_timeout.
+	HeartbeatInterval string `toml:"heartbeat_interval"`
 }
 
+// DefaultHeartbeatInterval is used when tansive_server.heartbeat_interval is not set.
+const DefaultHeartbeatInterval = "1m"
+
 func (t *TansiveServerConfig) GetURL() string {
 	return t.URL
 }
 
+// GetHeartbeatInterval returns the interval between session heartbeats as time.Duration
+func (t *TansiveServerConfig) GetHeartbeatInterval() (time.Duration, error) {
+	return ParseDuration(t.HeartbeatInterval)
+}
+
+// AuditLogConfig holds audit log shipping related configuration
+type AuditLogConfig struct {
+	UploadChunkSize    int  `toml:"upload_chunk_size"`   // Maximum size in bytes of each part when uploading the encoded audit log
+	PersistTranscripts bool `toml:"persist_transcripts"` // Whether to persist the output of every interactive session
+	MaxTranscriptSize  int  `toml:"max_transcript_size"` // Maximum size in bytes of recorded output in a persisted transcript
+}
+
+// DefaultAuditLogUploadChunkSize is the part size used when upload_chunk_size is not set.
+// It is kept below the tansive server's default request body limit.
+const DefaultAuditLogUploadChunkSize = 512 * 1024
+
+// DefaultMaxTranscriptSize is the transcript size limit used when max_transcript_size is not set.
+const DefaultMaxTranscriptSize = 1024 * 1024
+
+// RunnerOutputLimits holds the maximum bytes of stdout and stderr captured per skill invocation
+type RunnerOutputLimits struct {
+	MaxStdoutBytes int `toml:"max_stdout_bytes" json:"maxStdoutBytes"` // Maximum bytes of stdout captured per skill invocation
+	MaxStderrBytes int `toml:"max_stderr_bytes" json:"maxStderrBytes"` // Maximum bytes of stderr captured per skill invocation
+}
+
+// RunnerOutputConfig holds limits on the output of interactive skills sent to the event bus and audit log
+type RunnerOutputConfig struct {
+	MaxStdoutBytes int                           `toml:"max_stdout_bytes"` // Default maximum bytes of stdout captured per skill invocation
+	MaxStderrBytes int                           `toml:"max_stderr_bytes"` // Default maximum bytes of stderr captured per skill invocation
+	Runners        map[string]RunnerOutputLimits `toml:"runners"`          // Per-runner overrides keyed by runner ID
+}
+
+// Limits returns the stdout and stderr limits for runnerID.
+// Per-runner values that are not set fall back to the defaults.
+func (r *RunnerOutputConfig) Limits(runnerID string) RunnerOutputLimits {
+	limits := RunnerOutputLimits{
+		MaxStdoutBytes: r.MaxStdoutBytes,
+		MaxStderrBytes: r.MaxStderrBytes,
+	}
+	if override, ok := r.Runners[runnerID]; ok {
+		if override.MaxStdoutBytes > 0 {
+			limits.MaxStdoutBytes = override.MaxStdoutBytes
+		}
+		if override.MaxStderrBytes > 0 {
+			limits.MaxStderrBytes = override.MaxStderrBytes
+		}
+	}
+	return limits
+}
+
+// DefaultMaxRunnerOutputSize is the stdout and stderr limit used when runner_output limits are not set.
+const DefaultMaxRunnerOutputSize = 1024 * 1024
+
+// TelemetryConfig holds tracing and metrics related configuration
+type TelemetryConfig struct {
+	OTLPEndpoint        string `toml:"otlp_endpoint"`         // OTLP/HTTP endpoint URL for exporting skill traces. Exporting is disabled if empty.
+	OTLPMetricsEndpoint string `toml:"otlp_metrics_endpoint"` // OTLP/HTTP endpoint URL for exporting skill metrics. Exporting is disabled if empty.
+}
+
+// SkillsetCacheConfig holds configuration for caching skillsets fetched from the tansive server
+type SkillsetCacheConfig struct {
+	TTL        string `toml:"ttl"`         // How long a fetched skillset is reused. Caching is disabled if empty.
+	MaxEntries int    `toml:"max_entries"` // Maximum number of skillsets held in the cache
+}
+
+// GetTTL returns the cache TTL as time.Duration, or 0 if caching is disabled
+func (s *SkillsetCacheConfig) GetTTL() time.Duration {
+	if s.TTL == "" {
+		return 0
+	}
+	ttl, err := ParseDuration(s.TTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// DefaultSkillsetCacheMaxEntries is the cache size used when max_entries is not set.
+const DefaultSkillsetCacheMaxEntries = 100
+
+// ResultCacheConfig bounds the per-session cache of results of cacheable skills
+type ResultCacheConfig struct {
+	MaxEntries int    `toml:"max_entries"` // Maximum number of results cached per session
+	MaxBytes   int    `toml:"max_bytes"`   // Maximum total size in bytes of the results cached per session
+	MaxTTL     string `toml:"max_ttl"`     // Upper bound on the cacheTTL of a skill, e.g. "1h"
+}
+
+// GetMaxTTL returns the maximum result TTL as time.Duration, or 0 if it is not set.
+func (r *ResultCacheConfig) GetMaxTTL() time.Duration {
+	if r.MaxTTL == "" {
+		return 0
+	}
+	ttl, err := ParseDuration(r.MaxTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// Defaults used when result_cache settings are not set.
+const (
+	DefaultResultCacheMaxEntries = 100
+	DefaultResultCacheMaxBytes   = 10 * 1024 * 1024 // 10MB
+	DefaultResultCacheMaxTTL     = "1h"
+)
+
+// SkillSetConfig holds limits on the skillsets a tangent loads and the JSON values it validates
+// against their schemas. They should match the limits of the tansive server. Limits that are not
+// set use the defaults of the tansive server.
+type SkillSetConfig struct {
+	MaxSources      int `toml:"max_sources"`      // Maximum number of sources in a skillset
+	MaxSkills       int `toml:"max_skills"`       // Maximum number of skills in a skillset
+	MaxContexts     int `toml:"max_contexts"`     // Maximum number of context entries in a skillset
+	MaxDependencies int `toml:"max_dependencies"` // Maximum number of dependencies in a skillset
+	MaxJSONDepth    int `toml:"max_json_depth"`   // Maximum nesting depth of input args, context values and session variables
+}
+
+// SecretsConfig holds configuration for resolving secret references in runner configs
+type SecretsConfig struct {
+	Provider string `toml:"provider"` // Secrets provider used to resolve secretRef values: "env", "file", or a registered provider
+	Dir      string `toml:"dir"`      // Directory holding secrets as <dir>/<name>/<key> for the file provider
+}
+
+// DefaultSecretsProvider is the secrets provider used when secrets.provider is not set.
+const DefaultSecretsProvider = "env"
+
+// RunnerEnvConfig holds the session variables that skillset sources may inject into the
+// environment of their runners with envFromVars
+type RunnerEnvConfig struct {
+	AllowedVars []string `toml:"allowed_vars"` // Session variable keys that may be injected
+	SecretVars  []string `toml:"secret_vars"`  // Session variable keys that may be injected, with their values redacted in logs
+}
+
+// IsAllowed reports whether the session variable key may be injected into a runner environment.
+func (r *RunnerEnvConfig) IsAllowed(key string) bool {
+	return slices.Contains(r.AllowedVars, key) || r.IsSecret(key)
+}
+
+// IsSecret reports whether the session variable key holds a secret.
+func (r *RunnerEnvConfig) IsSecret(key string) bool {
+	return slices.Contains(r.SecretVars, key)
+}
+
+// TransformConfig holds limits applied to JavaScript skill transforms
+type TransformConfig struct {
+	MaxInputBytes  int    `toml:"max_input_bytes"`  // Maximum JSON size of the session and input arguments passed to a transform
+	MaxOutputBytes int    `toml:"max_output_bytes"` // Maximum JSON size of the arguments returned by a transform
+	MaxChainLength int    `toml:"max_chain_length"` // Maximum transform executions per skill invocation, including those of skills it invokes
+	NumberMode     string `toml:"number_mode"`      // How numbers returned by a transform map to Go types: intWhenWhole, alwaysFloat or json
+}
+
+// GetNumberMode returns the number mode applied to transform results.
+func (t *TransformConfig) GetNumberMode() jsruntime.NumberMode {
+	mode, _ := jsruntime.ParseNumberMode(t.NumberMode)
+	return mode
+}
+
+// DefaultMaxTransformSize is the transform input and output limit used when transform limits are not set.
+const DefaultMaxTransformSize = 1024 * 1024
+
+// DefaultMaxTransformChainLength is the number of transform executions allowed per skill invocation
+// when transform.max_chain_length is not set.
+const DefaultMaxTransformChainLength = 32
+
+// DebugConfig holds configuration for debugging endpoints
+type DebugConfig struct {
+	EnableConfigEndpoint bool   `toml:"enable_config_endpoint"` // Whether GET /debug/config is served
+	Token                string `toml:"token"`                  // Bearer token required by debug endpoints
+}
+
 // MCPConfig holds MCP server related configuration
 type MCPConfig struct {
 	HostName   string `toml:"hostname"`    // MCP server hostname
@@ -70,6 +254,13 @@ type ConfigParam struct {
 	TLSCertPEM     []byte `toml:"-"`               // PEM encoded TLS certificate
 	TLSKeyPEM      []byte `toml:"-"`               // PEM encoded TLS key
 
+	// MaxConcurrentSessions caps the sessions active on the tangent at once. New sessions are
+	// rejected while the tangent is full. Sessions are not limited if zero.
+	MaxConcurrentSessions int `toml:"max_concurrent_sessions"`
+
+	// CORS configuration, applied when HandleCORS is set
+	CORS middleware.CORSConfig `toml:"cors"`
+
 	// Stdio runner configuration
 	StdioRunner StdioRunnerConfig `toml:"stdio_runner"`
 
@@ -81,6 +272,36 @@ type ConfigParam struct {
 
 	// MCP configuration
 	MCP MCPConfig `toml:"mcp"`
+
+	// Audit log configuration
+	AuditLog AuditLogConfig `toml:"audit_log"`
+
+	// Runner output configuration
+	RunnerOutput RunnerOutputConfig `toml:"runner_output"`
+
+	// Telemetry configuration
+	Telemetry TelemetryConfig `toml:"telemetry"`
+
+	// Skillset cache configuration
+	SkillsetCache SkillsetCacheConfig `toml:"skillset_cache"`
+
+	// Result cache configuration
+	ResultCache ResultCacheConfig `toml:"result_cache"`
+
+	// Skillset limits
+	SkillSet SkillSetConfig `toml:"skillset"`
+
+	// Secrets configuration
+	Secrets SecretsConfig `toml:"secrets"`
+
+	// Runner environment configuration
+	RunnerEnv RunnerEnvConfig `toml:"runner_env"`
+
+	// Transform configuration
+	Transform TransformConfig `toml:"transform"`
+
+	// Debug configuration
+	Debug DebugConfig `toml:"debug"`
 }
 
 var cfg *ConfigParam
@@ -146,6 +367,20 @@ func ValidateConfig(cfg *ConfigParam) error {
 		return fmt.Errorf("server_port is required")
 	}
 
+	if cfg.MaxConcurrentSessions < 0 {
+		return fmt.Errorf("max_concurrent_sessions must not be negative")
+	}
+
+	// CORS validation. Any origin is allowed unless cors.allowed_origins is set.
+	if cfg.HandleCORS {
+		if len(cfg.CORS.AllowedOrigins) == 0 {
+			cfg.CORS.AllowedOrigins = []string{"*"}
+		}
+		if err := cfg.CORS.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Auth validation
 	if cfg.Auth.TokenExpiry == "" {
 		return fmt.Errorf("auth.token_expiry is required")
@@ -158,6 +393,15 @@ func ValidateConfig(cfg *ConfigParam) error {
 	if cfg.TansiveServer.URL == "" {
 		return fmt.Errorf("tansive_server.url is required")
 	}
+	if err := cfg.TansiveServer.SignatureHeaders.Validate(); err != nil {
+		return fmt.Errorf("tansive_server.signature_headers: %v", err)
+	}
+	if cfg.TansiveServer.HeartbeatInterval == "" {
+		cfg.TansiveServer.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if interval, err := cfg.TansiveServer.GetHeartbeatInterval(); err != nil || interval <= 0 {
+		return fmt.Errorf("invalid tansive_server.heartbeat_interval: %s", cfg.TansiveServer.HeartbeatInterval)
+	}
 
 	// MCP configuration validation
 	// For MCP, don't expose local.tansive.dev due to potential
@@ -172,6 +416,100 @@ func ValidateConfig(cfg *ConfigParam) error {
 		cfg.MCP.Port = "8627"
 	}
 
+	if cfg.AuditLog.UploadChunkSize < 0 {
+		return fmt.Errorf("audit_log.upload_chunk_size must not be negative")
+	}
+	if cfg.AuditLog.UploadChunkSize == 0 {
+		cfg.AuditLog.UploadChunkSize = DefaultAuditLogUploadChunkSize
+	}
+	if cfg.AuditLog.MaxTranscriptSize < 0 {
+		return fmt.Errorf("audit_log.max_transcript_size must not be negative")
+	}
+	if cfg.AuditLog.MaxTranscriptSize == 0 {
+		cfg.AuditLog.MaxTranscriptSize = DefaultMaxTranscriptSize
+	}
+
+	if cfg.RunnerOutput.MaxStdoutBytes < 0 || cfg.RunnerOutput.MaxStderrBytes < 0 {
+		return fmt.Errorf("runner_output limits must not be negative")
+	}
+	if cfg.RunnerOutput.MaxStdoutBytes == 0 {
+		cfg.RunnerOutput.MaxStdoutBytes = DefaultMaxRunnerOutputSize
+	}
+	if cfg.RunnerOutput.MaxStderrBytes == 0 {
+		cfg.RunnerOutput.MaxStderrBytes = DefaultMaxRunnerOutputSize
+	}
+	for runnerID, limits := range cfg.RunnerOutput.Runners {
+		if limits.MaxStdoutBytes < 0 || limits.MaxStderrBytes < 0 {
+			return fmt.Errorf("runner_output.runners.%s limits must not be negative", runnerID)
+		}
+	}
+
+	if cfg.SkillsetCache.TTL != "" {
+		if _, err := ParseDuration(cfg.SkillsetCache.TTL); err != nil {
+			return fmt.Errorf("invalid skillset_cache.ttl: %v", err)
+		}
+	}
+	if cfg.SkillsetCache.MaxEntries < 0 {
+		return fmt.Errorf("skillset_cache.max_entries must not be negative")
+	}
+	if cfg.SkillsetCache.MaxEntries == 0 {
+		cfg.SkillsetCache.MaxEntries = DefaultSkillsetCacheMaxEntries
+	}
+
+	if cfg.ResultCache.MaxEntries < 0 || cfg.ResultCache.MaxBytes < 0 {
+		return fmt.Errorf("result_cache limits must not be negative")
+	}
+	if cfg.ResultCache.MaxEntries == 0 {
+		cfg.ResultCache.MaxEntries = DefaultResultCacheMaxEntries
+	}
+	if cfg.ResultCache.MaxBytes == 0 {
+		cfg.ResultCache.MaxBytes = DefaultResultCacheMaxBytes
+	}
+	if cfg.ResultCache.MaxTTL == "" {
+		cfg.ResultCache.MaxTTL = DefaultResultCacheMaxTTL
+	}
+	if _, err := ParseDuration(cfg.ResultCache.MaxTTL); err != nil {
+		return fmt.Errorf("invalid result_cache.max_ttl: %v", err)
+	}
+
+	if cfg.SkillSet.MaxSources < 0 || cfg.SkillSet.MaxSkills < 0 || cfg.SkillSet.MaxContexts < 0 ||
+		cfg.SkillSet.MaxDependencies < 0 || cfg.SkillSet.MaxJSONDepth < 0 {
+		return fmt.Errorf("skillset limits must not be negative")
+	}
+
+	if cfg.Secrets.Provider == "" {
+		cfg.Secrets.Provider = DefaultSecretsProvider
+	}
+	if cfg.Secrets.Provider == "file" && cfg.Secrets.Dir == "" {
+		return fmt.Errorf("secrets.dir is required for the file secrets provider")
+	}
+
+	for _, key := range append(append([]string{}, cfg.RunnerEnv.AllowedVars...), cfg.RunnerEnv.SecretVars...) {
+		if key == "" {
+			return fmt.Errorf("runner_env variable keys must not be empty")
+		}
+	}
+
+	if cfg.Transform.MaxInputBytes < 0 || cfg.Transform.MaxOutputBytes < 0 || cfg.Transform.MaxChainLength < 0 {
+		return fmt.Errorf("transform limits must not be negative")
+	}
+	if cfg.Transform.MaxInputBytes == 0 {
+		cfg.Transform.MaxInputBytes = DefaultMaxTransformSize
+	}
+	if cfg.Transform.MaxOutputBytes == 0 {
+		cfg.Transform.MaxOutputBytes = DefaultMaxTransformSize
+	}
+	if cfg.Transform.MaxChainLength == 0 {
+		cfg.Transform.MaxChainLength = DefaultMaxTransformChainLength
+	}
+	if _, err := jsruntime.ParseNumberMode(cfg.Transform.NumberMode); err != nil {
+		return fmt.Errorf("invalid transform.number_mode: %v", err)
+	}
+
+	if cfg.Debug.EnableConfigEndpoint && cfg.Debug.Token == "" {
+		return fmt.Errorf("debug.token is required when debug.enable_config_endpoint is set")
+	}
+
 	if cfg.WorkingDir == "" {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
@@ -225,6 +563,13 @@ func LoadConfig(filename string) error {
 		return fmt.Errorf("invalid configuration: %v", err)
 	}
 
+	catalogmanager.SetSkillSetLimits(catalogmanager.SkillSetLimits{
+		MaxSources:      cfg.SkillSet.MaxSources,
+		MaxSkills:       cfg.SkillSet.MaxSkills,
+		MaxContexts:     cfg.SkillSet.MaxContexts,
+		MaxDependencies: cfg.SkillSet.MaxDependencies,
+		MaxJSONDepth:    cfg.SkillSet.MaxJSONDepth,
+	})
 	RuntimeInit()
 
 	return nil
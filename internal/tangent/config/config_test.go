@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+)
+
+func TestLoadConfigAppliesSkillSetLimits(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("..", "..", "..", "tangent.conf"))
+	require.NoError(t, err)
+	conf := strings.Replace(string(content), "max_json_depth = 64", "max_json_depth = 2", 1)
+	require.NotEqual(t, string(content), conf)
+	filename := filepath.Join(t.TempDir(), "tangent.conf")
+	require.NoError(t, os.WriteFile(filename, []byte(conf), 0600))
+
+	require.NoError(t, LoadConfig(filename))
+	t.Cleanup(func() { catalogmanager.SetSkillSetLimits(catalogmanager.DefaultSkillSetLimits) })
+	assert.Equal(t, 2, Config().SkillSet.MaxJSONDepth)
+
+	assert.NoError(t, catalogmanager.CheckJSONDepth("input", map[string]any{"a": map[string]any{"b": 1}}))
+	assert.ErrorIs(t, catalogmanager.CheckJSONDepth("input", map[string]any{"a": map[string]any{"b": []any{1}}}), catalogmanager.ErrJSONTooDeep)
+}
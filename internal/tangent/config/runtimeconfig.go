@@ -14,6 +14,7 @@ import (
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	srvtangent "github.com/tansive/tansive/internal/catalogsrv/tangent"
 	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tansive/tansive/internal/common/uuid"
 )
 
@@ -60,6 +61,24 @@ func CreateAuditLogDir() {
 	}
 }
 
+// GetSessionStateDir returns the directory path for persisted session state.
+// Session state is kept so that sessions can be recovered after a restart.
+func GetSessionStateDir() string {
+	appDataDir := Config().WorkingDir
+	return filepath.Join(appDataDir, "sessions")
+}
+
+// CreateSessionStateDir creates the session state directory if it doesn't exist.
+// The directory is only accessible to the owner as session state holds access tokens.
+func CreateSessionStateDir() {
+	dir := GetSessionStateDir()
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			log.Fatal().Err(err).Msg("failed to create session state dir")
+		}
+	}
+}
+
 // GetRuntimeConfigDir returns the directory path for runtime configuration storage.
 // Uses test-specific directory when in test mode for isolation.
 func GetRuntimeConfigDir() string {
@@ -126,9 +145,18 @@ func LoadRuntimeConfig() error {
 func RuntimeInit() {
 	CreateRuntimeConfigDir()
 	CreateAuditLogDir()
+	CreateSessionStateDir()
 	LoadRuntimeConfig()
 }
 
+// Capabilities returns the runners this Tangent can execute.
+func Capabilities() []catcommon.RunnerID {
+	return []catcommon.RunnerID{
+		catcommon.StdioRunnerID,
+		catcommon.MCPStdioRunnerID,
+	}
+}
+
 // RegisterTangent registers this Tangent instance with the catalog server.
 // Sends registration request with capabilities and public keys.
 // Returns an error if registration fails after retry attempts.
@@ -142,11 +170,9 @@ func RegisterTangent() error {
 		URL:                    GetURL(),
 		PublicKeyAccessKey:     runtimeConfig.AccessKey.PublicKey,
 		PublicKeyLogSigningKey: runtimeConfig.LogSigningKey.PublicKey,
-		Capabilities: []catcommon.RunnerID{
-			catcommon.StdioRunnerID,
-			catcommon.MCPStdioRunnerID,
-		},
-		OnboardingKey: Config().TansiveServer.OnboardingKey,
+		Capabilities:           Capabilities(),
+		MaxSessions:            Config().MaxConcurrentSessions,
+		OnboardingKey:          Config().TansiveServer.OnboardingKey,
 	}
 
 	client := getHTTPClient(&clientConfig{
@@ -289,3 +315,11 @@ func (c *clientConfig) GetSigningKey() (string, []byte) {
 	}
 	return "", nil
 }
+
+// GetSignatureHeaders returns the header names requests are signed with.
+func (c *clientConfig) GetSignatureHeaders() tangentsig.Headers {
+	if Config() == nil {
+		return tangentsig.DefaultHeaders
+	}
+	return Config().TansiveServer.SignatureHeaders.WithDefaults()
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// Redacted replaces the value of secret settings in a sanitized configuration.
+const Redacted = "[REDACTED]"
+
+// SanitizedConfig is the effective configuration of a Tangent with secrets redacted.
+// It is served by the debug config endpoint to diagnose deployments.
+type SanitizedConfig struct {
+	FormatVersion  string               `json:"formatVersion"`
+	ServerHostName string               `json:"serverHostName"`
+	ServerPort     string               `json:"serverPort"`
+	URL            string               `json:"url"`
+	HandleCORS     bool                 `json:"handleCORS"`
+	WorkingDir     string               `json:"workingDir"`
+	SupportTLS     bool                 `json:"supportTLS"`
+	MaxSessions    int                  `json:"maxConcurrentSessions"`
+	TLSKey         string               `json:"tlsKey,omitempty"`
+	Capabilities   []catcommon.RunnerID `json:"capabilities"`
+	TangentID      uuid.UUID            `json:"tangentID"`
+	Registered     bool                 `json:"registered"`
+	StdioRunner    struct {
+		ScriptDir string `json:"scriptDir"`
+	} `json:"stdioRunner"`
+	Auth struct {
+		TokenExpiry string `json:"tokenExpiry"`
+	} `json:"auth"`
+	TansiveServer struct {
+		URL               string `json:"url"`
+		OnboardingKey     string `json:"onboardingKey,omitempty"`
+		HeartbeatInterval string `json:"heartbeatInterval"`
+	} `json:"tansiveServer"`
+	MCP struct {
+		HostName   string `json:"hostName"`
+		Port       string `json:"port"`
+		SupportTLS bool   `json:"supportTLS"`
+		TLSKey     string `json:"tlsKey,omitempty"`
+	} `json:"mcp"`
+	AuditLog struct {
+		UploadChunkSize    int  `json:"uploadChunkSize"`
+		PersistTranscripts bool `json:"persistTranscripts"`
+		MaxTranscriptSize  int  `json:"maxTranscriptSize"`
+	} `json:"auditLog"`
+	RunnerOutput struct {
+		MaxStdoutBytes int                           `json:"maxStdoutBytes"`
+		MaxStderrBytes int                           `json:"maxStderrBytes"`
+		Runners        map[string]RunnerOutputLimits `json:"runners,omitempty"`
+	} `json:"runnerOutput"`
+	Telemetry struct {
+		OTLPEndpoint        string `json:"otlpEndpoint"`
+		OTLPMetricsEndpoint string `json:"otlpMetricsEndpoint"`
+	} `json:"telemetry"`
+	SkillsetCache struct {
+		TTL        string `json:"ttl"`
+		MaxEntries int    `json:"maxEntries"`
+	} `json:"skillsetCache"`
+	ResultCache struct {
+		MaxEntries int    `json:"maxEntries"`
+		MaxBytes   int    `json:"maxBytes"`
+		MaxTTL     string `json:"maxTTL"`
+	} `json:"resultCache"`
+	SkillSet struct {
+		MaxSources      int `json:"maxSources"`
+		MaxSkills       int `json:"maxSkills"`
+		MaxContexts     int `json:"maxContexts"`
+		MaxDependencies int `json:"maxDependencies"`
+		MaxJSONDepth    int `json:"maxJSONDepth"`
+	} `json:"skillset"`
+	Secrets struct {
+		Provider string `json:"provider"`
+		Dir      string `json:"dir"`
+	} `json:"secrets"`
+	RunnerEnv struct {
+		AllowedVars []string `json:"allowedVars,omitempty"`
+		SecretVars  []string `json:"secretVars,omitempty"`
+	} `json:"runnerEnv"`
+	Transform struct {
+		MaxInputBytes  int `json:"maxInputBytes"`
+		MaxOutputBytes int `json:"maxOutputBytes"`
+		MaxChainLength int `json:"maxChainLength"`
+	} `json:"transform"`
+	Debug struct {
+		EnableConfigEndpoint bool   `json:"enableConfigEndpoint"`
+		Token                string `json:"token,omitempty"`
+	} `json:"debug"`
+}
+
+// Sanitize returns the configuration with keys, tokens and other secrets redacted.
+// Runtime settings such as the tangent ID are included when rc is not nil.
+func Sanitize(c *ConfigParam, rc *RuntimeConfig) *SanitizedConfig {
+	s := &SanitizedConfig{
+		FormatVersion:  c.FormatVersion,
+		ServerHostName: c.ServerHostName,
+		ServerPort:     c.ServerPort,
+		HandleCORS:     c.HandleCORS,
+		WorkingDir:     c.WorkingDir,
+		SupportTLS:     c.SupportTLS,
+		MaxSessions:    c.MaxConcurrentSessions,
+		TLSKey:         redact(string(c.TLSKeyPEM)),
+		Capabilities:   Capabilities(),
+	}
+	if c.SupportTLS {
+		s.URL = "https://" + c.ServerHostName + ":" + c.ServerPort
+	} else {
+		s.URL = "http://" + c.ServerHostName + ":" + c.ServerPort
+	}
+	if rc != nil {
+		s.TangentID = rc.TangentID
+		s.Registered = rc.Registered
+	}
+	s.StdioRunner.ScriptDir = c.StdioRunner.ScriptDir
+	s.Auth.TokenExpiry = c.Auth.TokenExpiry
+	s.TansiveServer.URL = c.TansiveServer.URL
+	s.TansiveServer.OnboardingKey = redact(c.TansiveServer.OnboardingKey)
+	s.TansiveServer.HeartbeatInterval = c.TansiveServer.HeartbeatInterval
+	s.MCP.HostName = c.MCP.HostName
+	s.MCP.Port = c.MCP.Port
+	s.MCP.SupportTLS = c.MCP.SupportTLS
+	s.MCP.TLSKey = redact(string(c.MCP.TLSKeyPEM))
+	s.AuditLog.UploadChunkSize = c.AuditLog.UploadChunkSize
+	s.AuditLog.PersistTranscripts = c.AuditLog.PersistTranscripts
+	s.AuditLog.MaxTranscriptSize = c.AuditLog.MaxTranscriptSize
+	s.RunnerOutput.MaxStdoutBytes = c.RunnerOutput.MaxStdoutBytes
+	s.RunnerOutput.MaxStderrBytes = c.RunnerOutput.MaxStderrBytes
+	s.RunnerOutput.Runners = c.RunnerOutput.Runners
+	s.Telemetry.OTLPEndpoint = c.Telemetry.OTLPEndpoint
+	s.Telemetry.OTLPMetricsEndpoint = c.Telemetry.OTLPMetricsEndpoint
+	s.SkillsetCache.TTL = c.SkillsetCache.TTL
+	s.SkillsetCache.MaxEntries = c.SkillsetCache.MaxEntries
+	s.ResultCache.MaxEntries = c.ResultCache.MaxEntries
+	s.ResultCache.MaxBytes = c.ResultCache.MaxBytes
+	s.ResultCache.MaxTTL = c.ResultCache.MaxTTL
+	s.SkillSet.MaxSources = c.SkillSet.MaxSources
+	s.SkillSet.MaxSkills = c.SkillSet.MaxSkills
+	s.SkillSet.MaxContexts = c.SkillSet.MaxContexts
+	s.SkillSet.MaxDependencies = c.SkillSet.MaxDependencies
+	s.SkillSet.MaxJSONDepth = c.SkillSet.MaxJSONDepth
+	s.Secrets.Provider = c.Secrets.Provider
+	s.Secrets.Dir = c.Secrets.Dir
+	s.RunnerEnv.AllowedVars = c.RunnerEnv.AllowedVars
+	s.RunnerEnv.SecretVars = c.RunnerEnv.SecretVars
+	s.Transform.MaxInputBytes = c.Transform.MaxInputBytes
+	s.Transform.MaxOutputBytes = c.Transform.MaxOutputBytes
+	s.Transform.MaxChainLength = c.Transform.MaxChainLength
+	s.Debug.EnableConfigEndpoint = c.Debug.EnableConfigEndpoint
+	s.Debug.Token = redact(c.Debug.Token)
+	return s
+}
+
+// redact hides a secret value, leaving unset values empty so it is clear they are not configured.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
@@ -0,0 +1,77 @@
+package runners
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// redactedEnvValue replaces the value of a secret session variable in logs.
+const redactedEnvValue = "***"
+
+// applyEnvFromVars returns a copy of the runner config with the session variables named by
+// envFromVars added to its env. Only the variables allowed by the runner_env configuration may
+// be injected, reserved environment variables such as PATH may not be set, and the values of
+// secret variables are redacted when logged. Variables that are
+// not set in the session are skipped. The original config is not modified.
+func applyEnvFromVars(ctx context.Context, envConfig config.RunnerEnvConfig, runnerConfig map[string]any, envFromVars map[string]string, sessionVariables map[string]any) (map[string]any, apperrors.Error) {
+	if len(envFromVars) == 0 {
+		return runnerConfig, nil
+	}
+
+	names := make([]string, 0, len(envFromVars))
+	for name := range envFromVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := map[string]any{}
+	if existing, ok := runnerConfig["env"].(map[string]any); ok {
+		maps.Copy(env, existing)
+	}
+	logger := log.Ctx(ctx)
+	for _, name := range names {
+		key := envFromVars[name]
+		if catalogmanager.IsReservedEnvVar(name) {
+			return nil, ErrEnvVarNotAllowed.Msg("environment variable " + name + " is reserved and may not be set from a session variable")
+		}
+		if !envConfig.IsAllowed(key) {
+			return nil, ErrEnvVarNotAllowed.Msg("session variable " + key + " may not be injected into the runner environment")
+		}
+		value, ok := sessionVariables[key]
+		if !ok {
+			logger.Debug().Str("env", name).Str("variable", key).Msg("session variable not set, not injected into runner environment")
+			continue
+		}
+		s := envValue(value)
+		env[name] = s
+
+		logged := s
+		if envConfig.IsSecret(key) {
+			logged = redactedEnvValue
+		}
+		logger.Debug().Str("env", name).Str("variable", key).Str("value", logged).Msg("injected session variable into runner environment")
+	}
+
+	injected := make(map[string]any, len(runnerConfig)+1)
+	maps.Copy(injected, runnerConfig)
+	injected["env"] = env
+	return injected, nil
+}
+
+// envValue converts a session variable to an environment value. Strings are used as is and
+// other values are JSON encoded. Session variables are decoded from JSON, so encoding them
+// cannot fail.
+func envValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
@@ -0,0 +1,109 @@
+package runners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+func TestNewRunnerInjectsEnvFromVars(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTest(t)
+	config.TestInit(t)
+	stdiorunner.TestInit()
+
+	orig := config.Config().RunnerEnv
+	t.Cleanup(func() { config.Config().RunnerEnv = orig })
+	config.Config().RunnerEnv = config.RunnerEnvConfig{
+		AllowedVars: []string{"region", "replicas", "zone"},
+		SecretVars:  []string{"api-key"},
+	}
+
+	newRunnerDef := func(envFromVars map[string]string) catalogmanager.SkillSetSource {
+		var runnerConfig map[string]any
+		require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{
+			"version": "%s",
+			"runtime": "bash",
+			"env": {"LOG_LEVEL": "debug"},
+			"script": "test_script.sh",
+			"security": {"type": "default"}
+		}`, stdiorunner.Version)), &runnerConfig))
+		return catalogmanager.SkillSetSource{
+			Name:        "test-runner",
+			Runner:      catcommon.StdioRunnerID,
+			Config:      runnerConfig,
+			EnvFromVars: envFromVars,
+		}
+	}
+	sessionVariables := map[string]any{
+		"region":   "us-west-2",
+		"replicas": 3,
+		"api-key":  "secret_api_key",
+		"internal": "not_allowed",
+	}
+
+	t.Run("mapped variables appear in the runner environment", func(t *testing.T) {
+		var logs strings.Builder
+		ctx := zerolog.New(&logs).Level(zerolog.DebugLevel).WithContext(context.Background())
+		var stdout, stderr strings.Builder
+		runnerDef := newRunnerDef(map[string]string{
+			"AWS_REGION": "region",
+			"REPLICAS":   "replicas",
+			"API_KEY":    "api-key",
+			"UNSET_VAR":  "zone",
+		})
+		runner, err := NewRunner(ctx, "test-session", runnerDef, sessionVariables, &tangentcommon.IOWriters{Out: &stdout, Err: &stderr})
+		require.NoError(t, err)
+
+		err = runner.Run(ctx, &api.SkillInputArgs{
+			InvocationID:     "test-invocation",
+			SessionID:        "test-session",
+			SkillName:        "test-skill",
+			InputArgs:        map[string]any{"check_env": true},
+			SessionVariables: sessionVariables,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "AWS_REGION=us-west-2")
+		assert.Contains(t, stdout.String(), "REPLICAS=3")
+		assert.Contains(t, stdout.String(), "API_KEY=secret_api_key")
+		assert.Contains(t, stdout.String(), "LOG_LEVEL=debug")
+		assert.NotContains(t, stdout.String(), "UNSET_VAR=")
+
+		// the source config is not modified
+		assert.NotContains(t, runnerDef.Config["env"], "AWS_REGION")
+
+		// secret values are redacted in logs
+		assert.Contains(t, logs.String(), "us-west-2")
+		assert.Contains(t, logs.String(), `"env":"API_KEY"`)
+		assert.NotContains(t, logs.String(), "secret_api_key")
+	})
+
+	t.Run("variables that are not allowed fail runner creation", func(t *testing.T) {
+		var stdout, stderr strings.Builder
+		runnerDef := newRunnerDef(map[string]string{"INTERNAL": "internal"})
+		_, err := NewRunner(context.Background(), "test-session", runnerDef, sessionVariables, &tangentcommon.IOWriters{Out: &stdout, Err: &stderr})
+		assert.ErrorIs(t, err, ErrEnvVarNotAllowed)
+	})
+
+	for _, name := range []string{"LD_PRELOAD", "PATH", "HOME", "TANSIVE_SERVER_URL"} {
+		t.Run("reserved variable "+name+" fails runner creation", func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			runnerDef := newRunnerDef(map[string]string{name: "region"})
+			_, err := NewRunner(context.Background(), "test-session", runnerDef, sessionVariables, &tangentcommon.IOWriters{Out: &stdout, Err: &stderr})
+			assert.ErrorIs(t, err, ErrEnvVarNotAllowed)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package runners
+
+import "github.com/tansive/tansive/internal/common/apperrors"
+
+// Error definitions for the package.
+// All errors are derived from ErrRunnerError.
+var (
+	// ErrRunnerError is the base error for the package.
+	ErrRunnerError = apperrors.New("runner error")
+
+	// ErrInvalidSecretRef is returned when a secretRef in a runner config is malformed.
+	// Occurs when the reference is not a string of the form "name/key".
+	ErrInvalidSecretRef = ErrRunnerError.New("invalid secret reference")
+
+	// ErrSecretNotResolved is returned when a secretRef cannot be resolved.
+	// Occurs when the configured secrets provider does not hold the referenced secret.
+	ErrSecretNotResolved = ErrRunnerError.New("unable to resolve secret")
+
+	// ErrEnvVarNotAllowed is returned when a source maps a session variable into the runner
+	// environment that the tangent's runner_env configuration does not allow.
+	ErrEnvVarNotAllowed = ErrRunnerError.New("session variable not allowed in runner environment")
+
+	// ErrPreviewNotSupported is returned when a runner cannot preview the command it would run.
+	ErrPreviewNotSupported = ErrRunnerError.New("runner preview not supported")
+)
@@ -0,0 +1,42 @@
+package runners
+
+import (
+	"context"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+// RunnerPreview describes the command a runner would execute, with secrets shown as
+// placeholders.
+type RunnerPreview = stdiorunner.Preview
+
+// Preview resolves the command that a runner created from runnerDef would execute for args,
+// without executing it. Secret references are validated but never resolved: each is replaced
+// with config.Redacted, so a preview reveals neither the value nor the existence of a secret.
+// Only the stdio runner supports previews.
+func Preview(ctx context.Context, sessionID string, runnerDef catalogmanager.SkillSetSource, args *api.SkillInputArgs) (*RunnerPreview, apperrors.Error) {
+	if runnerDef.Runner != catcommon.StdioRunnerID {
+		return nil, ErrPreviewNotSupported.Msg("runner " + string(runnerDef.Runner) + " does not support previews")
+	}
+	runnerConfig, err := resolveSecretRefsWith(ctx, placeholderSecretProvider{}, runnerDef.Config)
+	if err != nil {
+		return nil, err
+	}
+	runner, err := stdiorunner.New(ctx, sessionID, runnerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return runner.Preview(args)
+}
+
+// placeholderSecretProvider resolves every secret to config.Redacted without looking it up.
+type placeholderSecretProvider struct{}
+
+func (placeholderSecretProvider) GetSecret(_ context.Context, _, _ string) (string, error) {
+	return config.Redacted, nil
+}
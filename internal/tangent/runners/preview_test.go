@@ -0,0 +1,56 @@
+package runners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+func TestPreviewHidesSecrets(t *testing.T) {
+	ctx := context.Background()
+	stdiorunner.TestInit()
+	t.Setenv("TANSIVE_SECRET_DB_PASSWORD", "hunter2")
+
+	runnerDef := catalogmanager.SkillSetSource{
+		Name:   "script",
+		Runner: catcommon.StdioRunnerID,
+		Config: map[string]any{
+			"version":  "0.1.0-alpha.1",
+			"runtime":  "bash",
+			"script":   "test_script.sh",
+			"niceness": 5,
+			"env": map[string]any{
+				"DB_PASSWORD": map[string]any{"secretRef": "db/password"},
+				"DB_URL":      "postgres://app@db",
+			},
+		},
+	}
+	args := &api.SkillInputArgs{SkillName: "query", InputArgs: map[string]any{"sql": "select 1"}}
+
+	preview, err := Preview(ctx, "session-1", runnerDef, args)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nice", "-n", "5", "/bin/bash"}, preview.Command[:4])
+	assert.Contains(t, preview.Command[5], `"sql":"select 1"`)
+	assert.Equal(t, config.Redacted, preview.Env["DB_PASSWORD"])
+	assert.Equal(t, "postgres://app@db", preview.Env["DB_URL"])
+
+	// a secret that does not exist previews the same as one that does
+	runnerDef.Config["env"].(map[string]any)["DB_PASSWORD"] = map[string]any{"secretRef": "db/missing"}
+	preview, err = Preview(ctx, "session-1", runnerDef, args)
+	require.NoError(t, err)
+	assert.Equal(t, config.Redacted, preview.Env["DB_PASSWORD"])
+
+	runnerDef.Config["env"].(map[string]any)["DB_PASSWORD"] = map[string]any{"secretRef": "no-key"}
+	_, err = Preview(ctx, "session-1", runnerDef, args)
+	assert.ErrorIs(t, err, ErrInvalidSecretRef)
+
+	_, err = Preview(ctx, "session-1", catalogmanager.SkillSetSource{Runner: catcommon.MCPStdioRunnerID}, args)
+	assert.ErrorIs(t, err, ErrPreviewNotSupported)
+}
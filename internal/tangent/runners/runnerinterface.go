@@ -6,12 +6,14 @@ package runners
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
 	"github.com/tansive/tansive/internal/tangent/runners/mcpstdiorunner"
 	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
 	"github.com/tansive/tansive/internal/tangent/tangentcommon"
@@ -40,20 +42,44 @@ type Runner interface {
 	Stop(ctx context.Context)
 }
 
+// StreamingRunner is implemented by runners that accept further input after the skill has
+// started, such as an interactive REPL. Each chunk received on input is written to the skill's
+// stdin in order. Stdin is closed when input is closed, and RunStreaming returns when the skill exits.
+type StreamingRunner interface {
+	Runner
+	RunStreaming(ctx context.Context, args *api.SkillInputArgs, input <-chan []byte) apperrors.Error
+}
+
 // NewRunner creates a new runner instance based on the runner definition.
 // Returns the appropriate runner type and any error encountered during creation.
 // Currently supports stdio runners for script and command execution.
-func NewRunner(ctx context.Context, sessionID string, runnerDef catalogmanager.SkillSetSource, writers ...*tangentcommon.IOWriters) (Runner, apperrors.Error) {
+// Secret references in the runner config are resolved and the session variables mapped by the
+// source's envFromVars are added to the runner environment before the runner is created.
+func NewRunner(ctx context.Context, sessionID string, runnerDef catalogmanager.SkillSetSource, sessionVariables map[string]any, writers ...*tangentcommon.IOWriters) (Runner, apperrors.Error) {
+	runnerConfig, err := resolveSecretRefs(ctx, runnerDef.Config)
+	if err != nil {
+		return nil, err
+	}
+	runnerConfig, err = applyEnvFromVars(ctx, config.Config().RunnerEnv, runnerConfig, runnerDef.EnvFromVars, sessionVariables)
+	if err != nil {
+		return nil, err
+	}
 	switch runnerDef.Runner {
 	case catcommon.StdioRunnerID:
-		return stdiorunner.New(ctx, sessionID, runnerDef.Config, writers...)
+		return stdiorunner.New(ctx, sessionID, runnerConfig, writers...)
 	case catcommon.MCPStdioRunnerID:
-		return mcpstdiorunner.New(ctx, sessionID, runnerDef.Config, writers...)
+		return mcpstdiorunner.New(ctx, sessionID, runnerConfig, writers...)
 	default:
 		return nil, apperrors.New(fmt.Sprintf("invalid runner id: %s", runnerDef.Runner))
 	}
 }
 
+// IsCrash reports whether err is the result of the skill's process exiting abnormally after it
+// was started, as opposed to a failure to start it or a limit being exceeded.
+func IsCrash(err error) bool {
+	return errors.Is(err, stdiorunner.ErrProcessCrashed)
+}
+
 // Init initializes the runners package and its dependencies.
 // Must be called before using any runner functionality.
 func Init() {
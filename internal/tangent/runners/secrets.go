@@ -0,0 +1,172 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// SecretRefKey is the key of a runner config value that refers to a secret instead of
+// holding it. A value of the form {"secretRef": "name/key"} is replaced with the secret
+// before the runner is created, so skillsets never store plaintext secrets.
+const SecretRefKey = "secretRef"
+
+// SecretProvider resolves a secret by name and key.
+type SecretProvider interface {
+	GetSecret(ctx context.Context, name, key string) (string, error)
+}
+
+// Built-in secret provider names
+const (
+	SecretProviderEnv  = "env"
+	SecretProviderFile = "file"
+)
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{}
+)
+
+// RegisterSecretProvider makes a secret provider available under the given name, to be
+// selected with secrets.provider in the tangent config.
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[name] = provider
+}
+
+// getSecretProvider returns the secret provider selected by the secrets configuration.
+func getSecretProvider(secretsConfig config.SecretsConfig) (SecretProvider, apperrors.Error) {
+	switch secretsConfig.Provider {
+	case SecretProviderEnv:
+		return envSecretProvider{}, nil
+	case SecretProviderFile:
+		return fileSecretProvider{dir: secretsConfig.Dir}, nil
+	}
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	provider, ok := secretProviders[secretsConfig.Provider]
+	if !ok {
+		return nil, ErrSecretNotResolved.Msg("unknown secrets provider: " + secretsConfig.Provider)
+	}
+	return provider, nil
+}
+
+// envSecretProvider reads secret name/key from the environment variable
+// TANSIVE_SECRET_<NAME>_<KEY>, with non-alphanumeric characters replaced by underscores.
+type envSecretProvider struct{}
+
+func (envSecretProvider) GetSecret(_ context.Context, name, key string) (string, error) {
+	envVar := secretEnvVar(name, key)
+	value, ok := os.LookupEnv(envVar)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", envVar)
+	}
+	return value, nil
+}
+
+func secretEnvVar(name, key string) string {
+	return "TANSIVE_SECRET_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name+"_"+key)
+}
+
+// fileSecretProvider reads secret name/key from the file <dir>/<name>/<key>.
+// A single trailing newline is removed.
+type fileSecretProvider struct {
+	dir string
+}
+
+func (p fileSecretProvider) GetSecret(_ context.Context, name, key string) (string, error) {
+	if p.dir == "" {
+		return "", fmt.Errorf("secrets.dir is not configured")
+	}
+	if !filepath.IsLocal(name) || !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid secret path")
+	}
+	value, err := os.ReadFile(filepath.Join(p.dir, name, key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(value), "\n"), "\r"), nil
+}
+
+// resolveSecretRefs returns a copy of the runner config with every secretRef replaced by
+// the secret it refers to. The original config is not modified.
+func resolveSecretRefs(ctx context.Context, runnerConfig map[string]any) (map[string]any, apperrors.Error) {
+	provider, err := getSecretProvider(config.Config().Secrets)
+	if err != nil {
+		return nil, err
+	}
+	return resolveSecretRefsWith(ctx, provider, runnerConfig)
+}
+
+func resolveSecretRefsWith(ctx context.Context, provider SecretProvider, runnerConfig map[string]any) (map[string]any, apperrors.Error) {
+	if runnerConfig == nil {
+		return nil, nil
+	}
+	resolved, err := resolveSecretValue(ctx, provider, runnerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]any), nil
+}
+
+func resolveSecretValue(ctx context.Context, provider SecretProvider, value any) (any, apperrors.Error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if ref, ok := v[SecretRefKey]; ok && len(v) == 1 {
+			return resolveSecretRef(ctx, provider, ref)
+		}
+		resolved := make(map[string]any, len(v))
+		for k, item := range v {
+			r, err := resolveSecretValue(ctx, provider, item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = r
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(v))
+		for i, item := range v {
+			r, err := resolveSecretValue(ctx, provider, item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = r
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+func resolveSecretRef(ctx context.Context, provider SecretProvider, ref any) (string, apperrors.Error) {
+	refStr, ok := ref.(string)
+	if !ok {
+		return "", ErrInvalidSecretRef.Msg("secretRef must be a string")
+	}
+	name, key, ok := strings.Cut(refStr, "/")
+	if !ok || name == "" || key == "" {
+		return "", ErrInvalidSecretRef.Msg(fmt.Sprintf("secretRef %q must be of the form name/key", refStr))
+	}
+	value, err := provider.GetSecret(ctx, name, key)
+	if err != nil {
+		return "", ErrSecretNotResolved.Msg(fmt.Sprintf("secretRef %q: %v", refStr, err))
+	}
+	return value, nil
+}
@@ -0,0 +1,146 @@
+package runners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+func TestResolveSecretRefs(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("TANSIVE_SECRET_GITHUB_API_TOKEN", "ghp_test")
+
+	runnerConfig := map[string]any{
+		"command": "npx",
+		"args":    []any{"--token", map[string]any{"secretRef": "github/api-token"}},
+		"env": map[string]any{
+			"GITHUB_TOKEN": map[string]any{"secretRef": "github/api-token"},
+			"LOG_LEVEL":    "debug",
+		},
+	}
+
+	t.Run("env provider resolves refs without modifying the source", func(t *testing.T) {
+		resolved, err := resolveSecretRefsWith(ctx, envSecretProvider{}, runnerConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "ghp_test", resolved["env"].(map[string]any)["GITHUB_TOKEN"])
+		assert.Equal(t, "debug", resolved["env"].(map[string]any)["LOG_LEVEL"])
+		assert.Equal(t, []any{"--token", "ghp_test"}, resolved["args"])
+
+		assert.Equal(t, map[string]any{"secretRef": "github/api-token"}, runnerConfig["env"].(map[string]any)["GITHUB_TOKEN"])
+	})
+
+	t.Run("file provider resolves refs", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "github"), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "github", "api-token"), []byte("ghp_file\n"), 0600))
+
+		resolved, err := resolveSecretRefsWith(ctx, fileSecretProvider{dir: dir}, runnerConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "ghp_file", resolved["env"].(map[string]any)["GITHUB_TOKEN"])
+
+		_, err = resolveSecretRefsWith(ctx, fileSecretProvider{dir: dir}, map[string]any{
+			"env": map[string]any{"X": map[string]any{"secretRef": "../github/api-token"}},
+		})
+		assert.ErrorIs(t, err, ErrSecretNotResolved)
+	})
+
+	t.Run("unresolved ref errors", func(t *testing.T) {
+		_, err := resolveSecretRefsWith(ctx, envSecretProvider{}, map[string]any{
+			"env": map[string]any{"DB_PASSWORD": map[string]any{"secretRef": "postgres/password"}},
+		})
+		assert.ErrorIs(t, err, ErrSecretNotResolved)
+		assert.Contains(t, err.Error(), "TANSIVE_SECRET_POSTGRES_PASSWORD")
+	})
+
+	t.Run("malformed ref errors", func(t *testing.T) {
+		for _, ref := range []any{"no-key", "/key", 42} {
+			_, err := resolveSecretRefsWith(ctx, envSecretProvider{}, map[string]any{
+				"env": map[string]any{"X": map[string]any{"secretRef": ref}},
+			})
+			assert.ErrorIs(t, err, ErrInvalidSecretRef, "ref %v", ref)
+		}
+	})
+
+	t.Run("registered provider is used", func(t *testing.T) {
+		RegisterSecretProvider("static", staticSecretProvider{"vault/token": "s.abc"})
+		provider, err := getSecretProvider(config.SecretsConfig{Provider: "static"})
+		require.NoError(t, err)
+		resolved, err := resolveSecretRefsWith(ctx, provider, map[string]any{"token": map[string]any{"secretRef": "vault/token"}})
+		require.NoError(t, err)
+		assert.Equal(t, "s.abc", resolved["token"])
+
+		_, err = getSecretProvider(config.SecretsConfig{Provider: "unknown"})
+		assert.ErrorIs(t, err, ErrSecretNotResolved)
+	})
+}
+
+type staticSecretProvider map[string]string
+
+func (p staticSecretProvider) GetSecret(_ context.Context, name, key string) (string, error) {
+	value, ok := p[name+"/"+key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s not found", name, key)
+	}
+	return value, nil
+}
+
+func TestNewRunnerResolvesSecrets(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTest(t)
+	config.TestInit(t)
+	stdiorunner.TestInit()
+	t.Setenv("TANSIVE_SECRET_TEST_TOKEN", "resolved_secret_value")
+
+	newRunnerDef := func(ref string) catalogmanager.SkillSetSource {
+		var runnerConfig map[string]any
+		require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{
+			"version": "%s",
+			"runtime": "bash",
+			"env": {"API_TOKEN": {"secretRef": "%s"}},
+			"script": "test_script.sh",
+			"security": {"type": "default"}
+		}`, stdiorunner.Version, ref)), &runnerConfig))
+		return catalogmanager.SkillSetSource{
+			Name:   "test-runner",
+			Runner: catcommon.StdioRunnerID,
+			Config: runnerConfig,
+		}
+	}
+
+	t.Run("resolved secret reaches the runner", func(t *testing.T) {
+		ctx := context.Background()
+		var stdout, stderr strings.Builder
+		runner, err := NewRunner(ctx, "test-session", newRunnerDef("test/token"), nil, &tangentcommon.IOWriters{Out: &stdout, Err: &stderr})
+		require.NoError(t, err)
+
+		err = runner.Run(ctx, &api.SkillInputArgs{
+			InvocationID:     "test-invocation",
+			SessionID:        "test-session",
+			SkillName:        "test-skill",
+			InputArgs:        map[string]any{"check_env": true},
+			SessionVariables: map[string]any{},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, stdout.String(), "API_TOKEN=resolved_secret_value")
+	})
+
+	t.Run("unresolved ref fails runner creation", func(t *testing.T) {
+		var stdout, stderr strings.Builder
+		_, err := NewRunner(context.Background(), "test-session", newRunnerDef("test/missing"), nil, &tangentcommon.IOWriters{Out: &stdout, Err: &stderr})
+		assert.ErrorIs(t, err, ErrSecretNotResolved)
+	})
+}
@@ -56,4 +56,23 @@ var (
 	// ErrDos2UnixNotAvailable is returned when dos2unix command is not available.
 	// Occurs when dos2unix is not installed or not in PATH.
 	ErrDos2UnixNotAvailable = ErrShellCommandRunnerError.New("dos2unix not available")
+
+	// ErrInvalidWorkingDir is returned for invalid working directories.
+	// Occurs when the working directory is not absolute or does not exist.
+	ErrInvalidWorkingDir = ErrShellCommandRunnerError.New("invalid working directory")
+
+	// ErrInvalidResourceLimits is returned for invalid resource limits.
+	// Occurs when a limit is negative or niceness is out of range.
+	ErrInvalidResourceLimits = ErrShellCommandRunnerError.New("invalid resource limits")
+
+	// ErrMemoryLimitExceeded is returned when the command is killed for exceeding its memory limit.
+	ErrMemoryLimitExceeded = ErrExecutionFailed.New("memory limit exceeded")
+
+	// ErrCPULimitExceeded is returned when the command is killed for exceeding its CPU time limit.
+	ErrCPULimitExceeded = ErrExecutionFailed.New("cpu limit exceeded")
+
+	// ErrProcessCrashed is returned when the command exits with a non-zero status or is
+	// killed by a signal after it was started. It is not returned when the command was
+	// killed because its invocation was cancelled.
+	ErrProcessCrashed = ErrExecutionFailed.New("process crashed")
 )
@@ -0,0 +1,108 @@
+package stdiorunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// memoryPollInterval is how often the resident memory of a command with a memory limit is checked.
+var memoryPollInterval = 100 * time.Millisecond
+
+// memoryWatchdog kills a command whose process group exceeds its resident memory limit, so that
+// the memory of processes forked by the command counts towards the limit.
+// A nil watchdog is valid and never reports the limit as exceeded.
+type memoryWatchdog struct {
+	exceeded atomic.Bool
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startMemoryWatchdog starts watching the resident memory of the process group led by process.
+// It returns nil when limitMB is 0 or memory cannot be measured on this platform.
+func startMemoryWatchdog(ctx context.Context, process *os.Process, limitMB int) *memoryWatchdog {
+	if limitMB <= 0 {
+		return nil
+	}
+	if !memoryLimitSupported {
+		log.Ctx(ctx).Warn().Int("memory_limit_mb", limitMB).Msg("memory limits are not supported on this platform")
+		return nil
+	}
+	w := &memoryWatchdog{done: make(chan struct{})}
+	limit := uint64(limitMB) * 1024 * 1024
+	go func() {
+		ticker := time.NewTicker(memoryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				rss, err := groupResidentMemory(process.Pid)
+				if err != nil {
+					// the process group has most likely exited
+					continue
+				}
+				if rss > limit {
+					w.exceeded.Store(true)
+					if err := killProcessGroup(process); err != nil {
+						log.Ctx(ctx).Error().Err(err).Msg("failed to kill process exceeding memory limit")
+					}
+					return
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// stop stops the watchdog and reports whether the process was killed for exceeding its limit.
+func (w *memoryWatchdog) stop() bool {
+	if w == nil {
+		return false
+	}
+	w.stopOnce.Do(func() { close(w.done) })
+	return w.exceeded.Load()
+}
+
+// limitsPreamble returns the shell commands that apply the configured rlimits before the
+// command is executed.
+func (r *runner) limitsPreamble() string {
+	if r.config.CPULimit > 0 {
+		return fmt.Sprintf("ulimit -t %d\n", r.config.CPULimit)
+	}
+	return ""
+}
+
+// execPrefix returns the prefix of the exec line that applies the configured niceness.
+func (r *runner) execPrefix() string {
+	if r.config.Niceness > 0 {
+		return fmt.Sprintf("nice -n %d ", r.config.Niceness)
+	}
+	return ""
+}
+
+// isCPULimitKill reports whether err is the result of the command being killed for exceeding
+// its CPU time limit. A command killed because ctx was cancelled or timed out is not.
+func isCPULimitKill(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return false
+	}
+	return status.Signal() == syscall.SIGXCPU || status.Signal() == syscall.SIGKILL
+}
@@ -0,0 +1,91 @@
+package stdiorunner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const memoryLimitSupported = true
+
+// residentMemory returns the resident set size of a process in bytes.
+func residentMemory(pid int) (uint64, error) {
+	statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected statm format for process %d", pid)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
+
+// groupResidentMemory returns the total resident set size of the processes in a process group.
+func groupResidentMemory(pgid int) (uint64, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	found := false
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		if group, err := processGroup(pid); err != nil || group != pgid {
+			continue
+		}
+		rss, err := residentMemory(pid)
+		if err != nil {
+			// the process exited since the group was listed
+			continue
+		}
+		total += rss
+		found = true
+	}
+	if !found {
+		return 0, fmt.Errorf("no processes in group %d", pgid)
+	}
+	return total, nil
+}
+
+// processGroup returns the process group ID of a process.
+func processGroup(pid int) (int, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// the command name may contain spaces, so fields are counted from its closing parenthesis
+	i := strings.LastIndexByte(string(stat), ')')
+	if i < 0 {
+		return 0, fmt.Errorf("unexpected stat format for process %d", pid)
+	}
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 3 {
+		return 0, fmt.Errorf("unexpected stat format for process %d", pid)
+	}
+	return strconv.Atoi(fields[2])
+}
+
+// setProcessGroup starts the command in its own process group and kills the whole group when
+// the command's context is done, so that processes forked by the command do not outlive it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd.Process)
+	}
+}
+
+// killProcessGroup kills the process group led by process.
+func killProcessGroup(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGKILL)
+}
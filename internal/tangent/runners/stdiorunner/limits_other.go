@@ -0,0 +1,29 @@
+//go:build !linux
+
+package stdiorunner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+const memoryLimitSupported = false
+
+// residentMemory is not supported outside Linux.
+func residentMemory(pid int) (uint64, error) {
+	return 0, errors.New("resident memory is not available on this platform")
+}
+
+// groupResidentMemory is not supported outside Linux.
+func groupResidentMemory(pgid int) (uint64, error) {
+	return 0, errors.New("resident memory is not available on this platform")
+}
+
+// setProcessGroup leaves the command in the tangent's process group outside Linux.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills process outside Linux, where commands do not get their own group.
+func killProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
@@ -0,0 +1,70 @@
+package stdiorunner
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+// Preview describes the command the runner would execute for a set of input arguments.
+// Env only contains the variables set by the runner, not those inherited from the tangent.
+type Preview struct {
+	Command    []string          `json:"command"`    // resolved command line, including the input arguments
+	WorkingDir string            `json:"workingDir"` // directory the command is run in
+	Env        map[string]string `json:"env"`        // environment variables set for the command
+}
+
+// Preview resolves the command, working directory and environment that Run would use for args,
+// without creating any files or starting the command.
+func (r *runner) Preview(args *api.SkillInputArgs) (*Preview, apperrors.Error) {
+	if args == nil {
+		return nil, ErrInvalidArgs.Msg("args is nil")
+	}
+	if r.config.Security.Type != SecurityTypeDefault {
+		return nil, ErrInvalidSecurity.Msg("security type not supported: " + string(r.config.Security.Type))
+	}
+
+	scriptPath, err := r.resolveScriptPath()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonArgs, goerr := json.Marshal(args)
+	if goerr != nil {
+		return nil, ErrInvalidArgs.Msg("could not normalize JSON args: " + goerr.Error())
+	}
+
+	var command []string
+	if r.config.Niceness > 0 {
+		command = append(command, "nice", "-n", strconv.Itoa(r.config.Niceness))
+	}
+	runtimeCmd, goerr := resolveRuntimeCommand(r.config.Runtime)
+	if goerr != nil {
+		return nil, ErrInvalidRuntime.Msg(goerr.Error())
+	}
+	command = append(command, runtimeCmd...)
+	command = append(command, scriptPath, string(jsonArgs))
+
+	homeDirPath := r.sessionHomeDir()
+	workingDir := homeDirPath
+	if r.config.WorkingDir != "" {
+		if info, err := os.Stat(r.config.WorkingDir); err != nil || !info.IsDir() {
+			return nil, ErrInvalidWorkingDir.Msg("working directory not found: " + r.config.WorkingDir)
+		}
+		workingDir = r.config.WorkingDir
+	}
+
+	env := map[string]string{"HOME": homeDirPath}
+	for k, v := range r.config.Env {
+		env[k] = v
+	}
+
+	return &Preview{
+		Command:    command,
+		WorkingDir: workingDir,
+		Env:        env,
+	}, nil
+}
@@ -45,13 +45,13 @@ func (r *runner) AddWriters(writers ...*tangentcommon.IOWriters) {
 
 // New creates a new runner with the given configuration.
 // The configuration must be valid JSON that can be unmarshaled into a Config.
-// The writers must provide non-nil io.Writer implementations for both stdout and stderr.
+// The writers must provide non-nil io.Writer implementations for the streams they accept.
 // Returns an error if the configuration is invalid or writers are not properly configured.
 func New(ctx context.Context, sessionID string, configMap map[string]any, writers ...*tangentcommon.IOWriters) (*runner, apperrors.Error) {
 	var config Config
 
 	for _, writer := range writers {
-		if writer == nil || writer.Out == nil || writer.Err == nil {
+		if !writer.Valid() {
 			return nil, ErrInvalidWriters
 		}
 	}
@@ -93,22 +93,54 @@ func (r *runner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Er
 	}
 
 	if r.config.Security.Type == SecurityTypeDefault {
-		return r.runWithDefaultSecurity(ctx, args)
+		return r.runWithDefaultSecurity(ctx, args, nil)
 	}
 	return ErrInvalidSecurity.Msg("security type not supported: " + string(r.config.Security.Type))
 }
 
-func (r *runner) runWithDefaultSecurity(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+// RunStreaming executes the configured command and writes each chunk received on input to
+// its stdin. Stdin is closed when input is closed or the context is cancelled.
+// Returns when the command exits.
+func (r *runner) RunStreaming(ctx context.Context, args *api.SkillInputArgs, input <-chan []byte) apperrors.Error {
+	if args == nil {
+		return ErrInvalidArgs.Msg("args is nil")
+	}
+	if input == nil {
+		return ErrInvalidArgs.Msg("input is nil")
+	}
+
+	if r.config.Security.Type == SecurityTypeDefault {
+		return r.runWithDefaultSecurity(ctx, args, input)
+	}
+	return ErrInvalidSecurity.Msg("security type not supported: " + string(r.config.Security.Type))
+}
+
+// resolveScriptPath returns the path of the configured script within the trusted script directory.
+// Returns an error if the path escapes the directory or the script does not exist.
+func (r *runner) resolveScriptPath() (string, apperrors.Error) {
 	scriptPath := filepath.Join(runnerConfig.ScriptDir, filepath.Clean(r.config.Script))
 	if !strings.HasPrefix(scriptPath, filepath.Clean(runnerConfig.ScriptDir)+string(os.PathSeparator)) {
-		return ErrInvalidScript.Msg("script path escapes trusted directory")
+		return "", ErrInvalidScript.Msg("script path escapes trusted directory")
 	}
 
 	if _, err := os.Stat(scriptPath); err != nil {
-		return ErrInvalidScript.Msg("script not found: " + err.Error())
+		return "", ErrInvalidScript.Msg("script not found: " + err.Error())
+	}
+	return scriptPath, nil
+}
+
+// sessionHomeDir returns the home directory of the command for this session.
+func (r *runner) sessionHomeDir() string {
+	return filepath.Join(os.TempDir(), r.sessionID)
+}
+
+func (r *runner) runWithDefaultSecurity(ctx context.Context, args *api.SkillInputArgs, input <-chan []byte) apperrors.Error {
+	scriptPath, appErr := r.resolveScriptPath()
+	if appErr != nil {
+		return appErr
 	}
 
-	homeDirPath := filepath.Join(os.TempDir(), r.sessionID)
+	homeDirPath := r.sessionHomeDir()
 	if err := os.MkdirAll(homeDirPath, 0755); err != nil {
 		return ErrExecutionFailed.Msg("failed to create home directory: " + err.Error())
 	}
@@ -151,9 +183,18 @@ func (r *runner) runWithDefaultSecurity(ctx context.Context, args *api.SkillInpu
 	outWriter := NewWriter(StdoutWriter, r.writers...)
 	errWriter := NewWriter(StderrWriter, r.writers...)
 
+	workingDir := homeDirPath
+	if r.config.WorkingDir != "" {
+		if info, err := os.Stat(r.config.WorkingDir); err != nil || !info.IsDir() {
+			return ErrInvalidWorkingDir.Msg("working directory not found: " + r.config.WorkingDir)
+		}
+		workingDir = r.config.WorkingDir
+	}
+
 	cmd := exec.CommandContext(ctx, "/bin/bash", wrappedScriptPath)
-	cmd.Dir = homeDirPath
+	cmd.Dir = workingDir
 	cmd.Env = env
+	setProcessGroup(cmd)
 	// cmd.Stdout = outWriter
 	// cmd.Stderr = errWriter
 
@@ -169,10 +210,21 @@ func (r *runner) runWithDefaultSecurity(ctx context.Context, args *api.SkillInpu
 	if err != nil {
 		return ErrExecutionFailed.Msg("failed to get stderr pipe: " + err.Error())
 	}
+	var stdinPipe io.WriteCloser
+	if input != nil {
+		stdinPipe, err = cmd.StdinPipe()
+		if err != nil {
+			return ErrExecutionFailed.Msg("failed to get stdin pipe: " + err.Error())
+		}
+	}
 
 	if err := cmd.Start(); err != nil {
 		return ErrExecutionFailed.Msg("startcommand failed: " + err.Error())
 	}
+	watchdog := startMemoryWatchdog(ctx, cmd.Process, r.config.MemoryLimitMB)
+	if stdinPipe != nil {
+		go streamInput(ctx, stdinPipe, input)
+	}
 	var wg sync.WaitGroup
 	wg.Add(2)
 
@@ -189,13 +241,45 @@ func (r *runner) runWithDefaultSecurity(ctx context.Context, args *api.SkillInpu
 	err = cmd.Wait()
 	wg.Wait()
 
+	if watchdog.stop() {
+		return ErrMemoryLimitExceeded.Msg(fmt.Sprintf("command exceeded memory limit of %d MB", r.config.MemoryLimitMB))
+	}
+	if err != nil && r.config.CPULimit > 0 && isCPULimitKill(ctx, err) {
+		return ErrCPULimitExceeded.Msg(fmt.Sprintf("command exceeded cpu limit of %d seconds", r.config.CPULimit))
+	}
 	if err != nil {
+		// Only a command that exited with a non-zero status or was killed by a signal, other
+		// than by the invocation being cancelled, has crashed.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return ErrProcessCrashed.Msg("command execution failed: " + err.Error())
+		}
 		return ErrExecutionFailed.Msg("command execution failed: " + err.Error())
 	}
 
 	return nil
 }
 
+// streamInput writes input chunks to the command's stdin until input is closed or the
+// context is cancelled, then closes stdin so the command sees end of input.
+func streamInput(ctx context.Context, stdin io.WriteCloser, input <-chan []byte) {
+	defer stdin.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case chunk, ok := <-input:
+			if !ok {
+				return
+			}
+			if _, err := stdin.Write(chunk); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("failed to write input to skill")
+				return
+			}
+		}
+	}
+}
+
 func (r *runner) writeWrappedScript(wrappedPath, scriptPath string, args *api.SkillInputArgs) error {
 	jsonArgs, err := json.Marshal(args)
 	if err != nil {
@@ -216,9 +300,9 @@ func (r *runner) writeWrappedScript(wrappedPath, scriptPath string, args *api.Sk
 
 		content = fmt.Sprintf(`#!/bin/bash
 set -euo pipefail
-
-exec '%s' '%s'
-`, scriptPath, escapedArgs)
+%s
+exec %s'%s' '%s'
+`, r.limitsPreamble(), r.execPrefix(), scriptPath, escapedArgs)
 	} else {
 		runtimeCmd, err := resolveRuntimeCommand(r.config.Runtime)
 		if err != nil {
@@ -227,9 +311,9 @@ exec '%s' '%s'
 
 		content = fmt.Sprintf(`#!/bin/bash
 set -euo pipefail
-
-exec %s '%s' '%s'
-`, strings.Join(runtimeCmd, " "), scriptPath, escapedArgs)
+%s
+exec %s%s '%s' '%s'
+`, r.limitsPreamble(), r.execPrefix(), strings.Join(runtimeCmd, " "), scriptPath, escapedArgs)
 	}
 
 	return os.WriteFile(wrappedPath, []byte(content), 0644)
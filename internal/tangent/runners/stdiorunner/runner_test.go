@@ -3,13 +3,17 @@ package stdiorunner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tansive/tansive/internal/common/apperrors"
@@ -283,7 +287,7 @@ func TestRun(t *testing.T) {
 				"should_fail": true
 			}`),
 			wantErr:   true,
-			errorType: ErrExecutionFailed,
+			errorType: ErrProcessCrashed,
 		},
 	}
 
@@ -564,3 +568,154 @@ func TestShouldNormalizeLineEndings(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceLimits(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTest(t)
+	config.TestInit(t)
+	TestInit()
+
+	newLimitsRunner := func(t *testing.T, limits string, writers *tangentcommon.IOWriters) (*runner, apperrors.Error) {
+		var configMap map[string]any
+		require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{
+			"version": "%s",
+			"runtime": "bash",
+			"script": "limits_script.sh",
+			"security": {
+				"type": "default"
+			}%s
+		}`, Version, limits)), &configMap))
+		return New(context.Background(), "test-session", configMap, writers)
+	}
+	run := func(t *testing.T, r *runner, args map[string]any) apperrors.Error {
+		return r.Run(context.Background(), &api.SkillInputArgs{
+			InvocationID:     "test-invocation",
+			SessionID:        "test-session",
+			SkillName:        "test-skill",
+			InputArgs:        args,
+			SessionVariables: make(map[string]any),
+		})
+	}
+
+	t.Run("invalid limits", func(t *testing.T) {
+		writers := &tangentcommon.IOWriters{Out: io.Discard, Err: io.Discard}
+		invalid := map[string]apperrors.Error{
+			`, "workingDir": "relative/dir"`: ErrInvalidWorkingDir,
+			`, "cpuLimit": -1`:               ErrInvalidResourceLimits,
+			`, "memoryLimitMB": -1`:          ErrInvalidResourceLimits,
+			`, "niceness": 20`:               ErrInvalidResourceLimits,
+			`, "niceness": -5`:               ErrInvalidResourceLimits,
+		}
+		for limits, wantErr := range invalid {
+			r, err := newLimitsRunner(t, limits, writers)
+			assert.ErrorIs(t, err, wantErr, limits)
+			assert.Nil(t, r)
+		}
+	})
+
+	t.Run("working dir and niceness", func(t *testing.T) {
+		workingDir := t.TempDir()
+		var stdout strings.Builder
+		r, err := newLimitsRunner(t, fmt.Sprintf(`, "workingDir": %q, "niceness": 5, "cpuLimit": 30`, workingDir),
+			&tangentcommon.IOWriters{Out: &stdout, Err: io.Discard})
+		require.NoError(t, err)
+		require.NoError(t, run(t, r, map[string]any{}))
+		resolved, _ := filepath.EvalSymlinks(workingDir)
+		assert.Contains(t, stdout.String(), "Working directory: "+resolved)
+		assert.Contains(t, stdout.String(), "Niceness: 5")
+	})
+
+	t.Run("missing working dir", func(t *testing.T) {
+		r, err := newLimitsRunner(t, `, "workingDir": "/nonexistent/tansive-workdir"`,
+			&tangentcommon.IOWriters{Out: io.Discard, Err: io.Discard})
+		require.NoError(t, err)
+		assert.ErrorIs(t, run(t, r, map[string]any{}), ErrInvalidWorkingDir)
+	})
+
+	t.Run("cancelled invocation is not a crash", func(t *testing.T) {
+		r, err := newLimitsRunner(t, "", &tangentcommon.IOWriters{Out: io.Discard, Err: io.Discard})
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err = r.Run(ctx, &api.SkillInputArgs{
+			InvocationID:     "test-invocation",
+			SessionID:        "test-session",
+			SkillName:        "test-skill",
+			InputArgs:        map[string]any{"sleep": true},
+			SessionVariables: make(map[string]any),
+		})
+		assert.ErrorIs(t, err, ErrExecutionFailed)
+		assert.NotErrorIs(t, err, ErrProcessCrashed)
+	})
+
+	t.Run("memory limit", func(t *testing.T) {
+		if !memoryLimitSupported {
+			t.Skip("memory limits are not supported on this platform")
+		}
+		var stdout strings.Builder
+		r, err := newLimitsRunner(t, `, "memoryLimitMB": 32`, &tangentcommon.IOWriters{Out: &stdout, Err: io.Discard})
+		require.NoError(t, err)
+		err = run(t, r, map[string]any{"allocate_memory": true})
+		assert.ErrorIs(t, err, ErrMemoryLimitExceeded)
+		assert.ErrorIs(t, err, ErrExecutionFailed)
+		assert.NotContains(t, stdout.String(), "Allocated")
+	})
+
+	t.Run("memory limit includes forked children", func(t *testing.T) {
+		if !memoryLimitSupported {
+			t.Skip("memory limits are not supported on this platform")
+		}
+		var stdout strings.Builder
+		r, err := newLimitsRunner(t, `, "memoryLimitMB": 32`, &tangentcommon.IOWriters{Out: &stdout, Err: io.Discard})
+		require.NoError(t, err)
+		err = run(t, r, map[string]any{"allocate_child_memory": true})
+		assert.ErrorIs(t, err, ErrMemoryLimitExceeded)
+		assert.NotContains(t, stdout.String(), "Allocated")
+	})
+}
+
+func TestIsCPULimitKill(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "sleep", "10")
+	require.NoError(t, cmd.Start())
+	cancel()
+	err := cmd.Wait()
+	require.Error(t, err)
+
+	// a kill on cancellation is not a CPU limit kill, though the exit status is the same
+	assert.False(t, isCPULimitKill(ctx, err))
+	assert.True(t, isCPULimitKill(context.Background(), err))
+	assert.False(t, isCPULimitKill(context.Background(), errors.New("other failure")))
+}
+
+func TestWriterFiltering(t *testing.T) {
+	var allOut, allErr, errOnly, errLevel strings.Builder
+	writers := []*tangentcommon.IOWriters{
+		{Out: &allOut, Err: &allErr},
+		{Err: &errOnly, Source: tangentcommon.OutputSourceStderr},
+		{Out: &errLevel, Err: &errLevel, Level: zerolog.ErrorLevel},
+	}
+	for _, w := range writers {
+		require.True(t, w.Valid())
+	}
+
+	_, err := NewWriter(StdoutWriter, writers...).Write([]byte("to stdout\n"))
+	require.NoError(t, err)
+	_, err = NewWriter(StderrWriter, writers...).Write([]byte("to stderr\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "to stdout\n", allOut.String())
+	assert.Equal(t, "to stderr\n", allErr.String())
+	assert.Equal(t, "to stderr\n", errOnly.String())
+	assert.Equal(t, "to stderr\n", errLevel.String())
+
+	// output no writer accepts is dropped without failing the skill
+	n, err := NewWriter(StdoutWriter, writers[1]).Write([]byte("dropped\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("dropped\n"), n)
+	assert.Equal(t, "to stderr\n", errOnly.String())
+
+	// writers must provide the streams they accept
+	_, apperr := New(context.Background(), "test-session", map[string]any{}, &tangentcommon.IOWriters{Out: &allOut, Source: tangentcommon.OutputSourceStderr})
+	assert.ErrorIs(t, apperr, ErrInvalidWriters)
+}
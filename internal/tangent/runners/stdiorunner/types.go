@@ -1,6 +1,9 @@
 package stdiorunner
 
 import (
+	"fmt"
+	"path/filepath"
+
 	"github.com/tansive/tansive/internal/common/apperrors"
 )
 
@@ -21,7 +24,11 @@ import (
 //	  "script": "my-script.sh",
 //	  "security": {
 //	    "type": "default"
-//	  }
+//	  },
+//	  "workingDir": "/var/lib/skills",
+//	  "cpuLimit": 30,
+//	  "memoryLimitMB": 512,
+//	  "niceness": 10
 //	}
 type Config struct {
 	Version       string            `json:"version"`       // must be compatible with current version
@@ -30,8 +37,15 @@ type Config struct {
 	Env           map[string]string `json:"env"`           // optional environment variables
 	Script        string            `json:"script"`        // must be non-empty
 	Security      Security          `json:"security"`      // defaults to "default" if empty
+	WorkingDir    string            `json:"workingDir"`    // optional absolute path, defaults to the session home directory
+	CPULimit      int               `json:"cpuLimit"`      // optional CPU time limit in seconds, 0 for no limit
+	MemoryLimitMB int               `json:"memoryLimitMB"` // optional resident memory limit in MB, 0 for no limit
+	Niceness      int               `json:"niceness"`      // optional scheduling niceness between 0 and MaxNiceness
 }
 
+// MaxNiceness is the highest scheduling niceness a command may be run with.
+const MaxNiceness = 19
+
 // Runtime specifies the command execution environment.
 // The value must be one of the constants defined below.
 type Runtime string
@@ -114,6 +128,18 @@ func (c *Config) Validate() apperrors.Error {
 		return ErrInvalidScript
 	}
 
+	if c.WorkingDir != "" && !filepath.IsAbs(c.WorkingDir) {
+		return ErrInvalidWorkingDir.Msg("working directory must be an absolute path")
+	}
+
+	if c.CPULimit < 0 || c.MemoryLimitMB < 0 {
+		return ErrInvalidResourceLimits.Msg("resource limits must not be negative")
+	}
+
+	if c.Niceness < 0 || c.Niceness > MaxNiceness {
+		return ErrInvalidResourceLimits.Msg(fmt.Sprintf("niceness must be between 0 and %d", MaxNiceness))
+	}
+
 	if c.Env == nil {
 		c.Env = make(map[string]string)
 	}
@@ -19,11 +19,13 @@ type writer struct {
 }
 
 // Write writes the byte slice p to the appropriate stream (Out or Err) of each IOWriters target,
-// depending on the writerType (StdoutWriter or StderrWriter).
+// depending on the writerType (StdoutWriter or StderrWriter). Targets whose source or level
+// filter excludes the stream are skipped.
 //
 // It returns the number of bytes successfully written and an error if any writer failed or performed
 // a partial write. If at least one writer short-writes, io.ErrShortWrite is returned.
-// If no writers write anything, the first error encountered (if any) is returned.
+// If no writers write anything, the first error encountered (if any) is returned. Output that no
+// target accepts is dropped and reported as written, so that the skill is not failed for it.
 func (w *writer) Write(p []byte) (int, error) {
 	var (
 		minWritten int = len(p)
@@ -40,9 +42,13 @@ func (w *writer) Write(p []byte) (int, error) {
 		var target io.Writer
 		switch w.writerType {
 		case StdoutWriter:
-			target = wtr.Out
+			if wtr.Accepts(tangentcommon.OutputSourceStdout) {
+				target = wtr.Out
+			}
 		case StderrWriter:
-			target = wtr.Err
+			if wtr.Accepts(tangentcommon.OutputSourceStderr) {
+				target = wtr.Err
+			}
 		default:
 			continue
 		}
@@ -72,13 +78,11 @@ func (w *writer) Write(p []byte) (int, error) {
 	if anyShort {
 		return minWritten, io.ErrShortWrite
 	}
-	if wrote {
-		return len(p), firstErr
-	}
-	return 0, nil
+	return len(p), firstErr
 }
 
-// NewWriter constructs an io.Writer that delegates to the Out or Err streams of each IOWriters.
+// NewWriter constructs an io.Writer that delegates to the Out or Err streams of each IOWriters
+// that accepts output of the writer type.
 func NewWriter(writerType WriterType, writers ...*tangentcommon.IOWriters) io.Writer {
 	return &writer{
 		writerType: writerType,
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// debugAuthenticator requires the debug token configured for the tangent as a bearer token.
+func debugAuthenticator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		expected := config.Config().Debug.Token
+		if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			httpx.ErrUnAuthorized("invalid debug token").Send(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getDebugConfig returns the effective configuration of the tangent with secrets redacted.
+func (s *AgentServer) getDebugConfig(w http.ResponseWriter, r *http.Request) {
+	log.Ctx(r.Context()).Debug().Msg("GetDebugConfig")
+	httpx.SendJsonRsp(r.Context(), w, http.StatusOK, config.Sanitize(config.Config(), config.GetRuntimeConfig()))
+}
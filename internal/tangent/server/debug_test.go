@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/test"
+)
+
+func TestDebugConfig(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTestCatalog(t)
+	config.TestInit(t)
+	orig := config.Config().Debug
+	t.Cleanup(func() { config.Config().Debug = orig })
+
+	t.Run("disabled by default", func(t *testing.T) {
+		config.Config().Debug = config.DebugConfig{}
+		req, _ := http.NewRequest("GET", "/debug/config", nil)
+		response := executeTestRequest(t, req, nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	config.Config().Debug = config.DebugConfig{EnableConfigEndpoint: true, Token: "debug-secret"}
+
+	t.Run("requires the debug token", func(t *testing.T) {
+		for _, header := range []string{"", "Bearer wrong-token", "debug-secret"} {
+			req, _ := http.NewRequest("GET", "/debug/config", nil)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+			response := executeTestRequest(t, req, nil)
+			assert.Equal(t, http.StatusUnauthorized, response.Code, header)
+		}
+	})
+
+	t.Run("secrets are redacted", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/debug/config", nil)
+		req.Header.Set("Authorization", "Bearer debug-secret")
+		response := executeTestRequest(t, req, nil)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var rsp config.SanitizedConfig
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		assert.Equal(t, config.Config().ServerPort, rsp.ServerPort)
+		assert.Equal(t, config.Config().SupportTLS, rsp.SupportTLS)
+		assert.Equal(t, config.Config().Auth.TokenExpiry, rsp.Auth.TokenExpiry)
+		assert.NotEmpty(t, rsp.Capabilities)
+		assert.Equal(t, config.Redacted, rsp.Debug.Token)
+
+		body := response.Body.String()
+		assert.NotContains(t, body, "debug-secret")
+		assert.NotContains(t, body, "PRIVATE KEY")
+		if key := config.Config().TansiveServer.OnboardingKey; key != "" {
+			assert.NotContains(t, body, key)
+		}
+	})
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/cors"
 	"github.com/rs/zerolog/log"
 
 	"github.com/tansive/tansive/internal/common/httpx"
@@ -38,8 +37,9 @@ func CreateNewServer() (*AgentServer, error) {
 func (s *AgentServer) MountHandlers() {
 	s.Router.Use(middleware.RequestLogger)
 	s.Router.Use(middleware.PanicHandler)
+	s.Router.Use(middleware.PrettyJSON)
 	if config.Config().HandleCORS {
-		s.Router.Use(s.HandleCORS)
+		s.Router.Use(middleware.CORS(config.Config().CORS))
 	}
 	s.mountResourceHandlers(s.Router)
 	if logtrace.IsTraceEnabled() {
@@ -60,8 +60,17 @@ func (s *AgentServer) mountResourceHandlers(r chi.Router) {
 	r.Route("/sessions", func(r chi.Router) {
 		session.Router(r)
 	})
+	r.Route("/skillsets", func(r chi.Router) {
+		session.SkillsetRouter(r)
+	})
 	r.Get("/version", s.getVersion)
 	r.Get("/ready", s.getReadiness)
+	if config.Config().Debug.EnableConfigEndpoint {
+		r.Route("/debug", func(r chi.Router) {
+			r.Use(debugAuthenticator)
+			r.Get("/config", s.getDebugConfig)
+		})
+	}
 }
 
 // GetVersionRsp represents the response for version information.
@@ -93,16 +102,3 @@ func (s *AgentServer) getReadiness(w http.ResponseWriter, r *http.Request) {
 		"status": "ready",
 	})
 }
-
-// HandleCORS provides CORS middleware for cross-origin requests.
-// Configures allowed origins, methods, headers, and credentials handling.
-func (s *AgentServer) HandleCORS(next http.Handler) http.Handler {
-	return cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"}, //TODO: Change this to specific origin
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Content-Length", "Accept-Encoding"},
-		ExposedHeaders:   []string{"Link", "Location", "X-Tansive-Request-Id"},
-		AllowCredentials: false,
-		MaxAge:           300,
-	})(next)
-}
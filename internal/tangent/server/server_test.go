@@ -8,7 +8,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	uuidv7utils "github.com/tansive/tansive/internal/common/uuid"
+	"github.com/tansive/tansive/internal/tangent/config"
 	"github.com/tansive/tansive/internal/tangent/session/api"
+	"github.com/tansive/tansive/internal/tangent/test"
 )
 
 func TestCreateSession(t *testing.T) {
@@ -124,3 +126,18 @@ func TestCreateSession(t *testing.T) {
 	require.NoError(t, err, "Failed to unmarshal sessions response after deletion")
 	require.Equal(t, len(sl.Sessions), 1, "Expected 1 session after deletion, got %d", len(sl.Sessions))
 }
+
+func TestRunnerPreviewRequiresToken(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTestCatalog(t)
+	config.TestInit(t)
+
+	for _, header := range []string{"", "Basic abc", "Bearer "} {
+		req, _ := http.NewRequest("GET", "/skillsets/skillsets/kubernetes-demo/skills/list_pods/runner-preview", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		response := executeTestRequest(t, req, nil)
+		assert.Equal(t, http.StatusUnauthorized, response.Code, header)
+	}
+}
@@ -2,8 +2,11 @@ package session
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive/internal/catalogsrv/policy"
@@ -17,6 +20,7 @@ import (
 // activeSessions manages the collection of active sessions.
 // Provides thread-safe access to session storage and lifecycle management.
 type activeSessions struct {
+	mu       sync.RWMutex
 	sessions map[uuid.UUID]*session
 }
 
@@ -30,25 +34,42 @@ type ServerContext struct {
 	ViewDefinition   *policy.ViewDefinition `json:"view_definition"`   // policy view definition
 	SessionVariables map[string]any         `json:"session_variables"` // session-scoped variables
 	InputArgs        map[string]any         `json:"input_args"`        // input arguments for skill execution
+	InputArgsRef     string                 `json:"input_args_ref"`    // context holding the input arguments, used instead of InputArgs if set
 	Catalog          string                 `json:"catalog"`           // catalog name
 	Variant          string                 `json:"variant"`           // variant name
 	Namespace        string                 `json:"namespace"`         // namespace for resource isolation
 	TenantID         catcommon.TenantId     `json:"tenant_id"`         // tenant identifier
+	Environment      string                 `json:"environment"`       // environment selecting skillset source overrides
+	ContextOverrides map[string]any         `json:"context_overrides"` // context values replacing the skillset's for this session
+	AdoptionDepth    int                    `json:"adoption_depth"`    // number of view adoptions that led to the session's view
+	LogLevel         string                 `json:"log_level"`         // level of the session's log; the tangent's level if empty
 }
 
 var sessionManager *activeSessions
 
 // CreateSession creates a new session with the given context and authentication token.
 // Returns the created session and any error encountered during creation.
-// SessionID must be valid and unique within the session manager.
+// SessionID must be valid and unique within the session manager. Returns ErrTangentBusy if the
+// tangent already runs its maximum number of concurrent sessions.
 func (as *activeSessions) CreateSession(ctx context.Context, c *ServerContext, token string, tokenExpiry time.Time, sessionType tangentcommon.SessionType) (*session, apperrors.Error) {
+	return as.createSession(ctx, c, token, tokenExpiry, sessionType, config.Config().MaxConcurrentSessions)
+}
+
+// createSession creates a session, failing if maxSessions sessions are already active.
+// Sessions are not limited if maxSessions is zero.
+func (as *activeSessions) createSession(ctx context.Context, c *ServerContext, token string, tokenExpiry time.Time, sessionType tangentcommon.SessionType, maxSessions int) (*session, apperrors.Error) {
 	if c.SessionID == uuid.Nil {
 		return nil, ErrInvalidSession
 	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
 	// if a session with the same ID already exists, return an error
 	if _, exists := as.sessions[c.SessionID]; exists {
 		return nil, ErrAlreadyExists.New("session already exists")
 	}
+	if maxSessions > 0 && len(as.sessions) >= maxSessions {
+		return nil, ErrTangentBusy.Msg(fmt.Sprintf("tangent is running its maximum of %d sessions", maxSessions))
+	}
 	session := &session{
 		id:            c.SessionID,
 		context:       c,
@@ -59,13 +80,23 @@ func (as *activeSessions) CreateSession(ctx context.Context, c *ServerContext, t
 		callGraph:     toolgraph.NewCallGraph(3), // max depth of 3
 		invocationIDs: make(map[string]*policy.ViewDefinition),
 		sessionType:   sessionType,
+		resultCache:   newResultCache(),
 	}
 	logger := log.Ctx(ctx)
 	if logger == nil {
 		newLogger := log.With().Str("session_id", c.SessionID.String()).Logger()
 		logger = &newLogger
 	}
+	if c.LogLevel != "" {
+		level, err := zerolog.ParseLevel(c.LogLevel)
+		if err != nil {
+			return nil, ErrBadRequest.Msg("invalid log level " + c.LogLevel)
+		}
+		levelLogger := logger.Level(level)
+		logger = &levelLogger
+	}
 	session.logger = logger
+	session.logger.Debug().Str("skill", c.Skill).Str("view", c.View).Msg("session created")
 	session.auditLogInfo.auditLogger = session.getLogger(TopicAuditLog)
 	session.auditLogInfo.auditLogPubKey = config.GetRuntimeConfig().LogSigningKey.PublicKey
 	as.sessions[c.SessionID] = session
@@ -75,6 +106,8 @@ func (as *activeSessions) CreateSession(ctx context.Context, c *ServerContext, t
 // GetSession retrieves a session by its unique identifier.
 // Returns the session and any error encountered during retrieval.
 func (as *activeSessions) GetSession(id uuid.UUID) (*session, apperrors.Error) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
 	if session, exists := as.sessions[id]; exists {
 		return session, nil
 	}
@@ -84,6 +117,8 @@ func (as *activeSessions) GetSession(id uuid.UUID) (*session, apperrors.Error) {
 // ListSessions returns all active sessions in the session manager.
 // Returns the session list and any error encountered during listing.
 func (as *activeSessions) ListSessions() ([]*session, apperrors.Error) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
 	var sessionList []*session
 	for _, session := range as.sessions {
 		sessionList = append(sessionList, session)
@@ -94,6 +129,8 @@ func (as *activeSessions) ListSessions() ([]*session, apperrors.Error) {
 // DeleteSession removes a session from the session manager.
 // Cleans up associated event bus subscriptions and resources.
 func (as *activeSessions) DeleteSession(id uuid.UUID) apperrors.Error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
 	if _, exists := as.sessions[id]; !exists {
 		return ErrInvalidSession
 	}
@@ -5,14 +5,19 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/common/apperrors"
 	"github.com/tansive/tansive/internal/common/uuid"
 	"github.com/tansive/tansive/internal/tangent/config"
 	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
@@ -131,6 +136,97 @@ func isError(response *api.SkillResult) bool {
 	return ok
 }
 
+func TestMaxConcurrentSessions(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	const maxSessions = 3
+	prev := config.Config().MaxConcurrentSessions
+	config.Config().MaxConcurrentSessions = maxSessions
+	t.Cleanup(func() { config.Config().MaxConcurrentSessions = prev })
+	ctx := context.Background()
+	as := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+
+	// start one more session than the tangent allows, all at once
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var created []*session
+	var rejected []apperrors.Error
+	for i := 0; i < maxSessions+1; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := as.CreateSession(ctx, &ServerContext{SessionID: uuid.New()}, "token", time.Now().Add(time.Hour), tangentcommon.SessionTypeInteractive)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				rejected = append(rejected, err)
+				return
+			}
+			created = append(created, s)
+		}()
+	}
+	wg.Wait()
+	require.Len(t, created, maxSessions)
+	require.Len(t, rejected, 1)
+	assert.ErrorIs(t, rejected[0], ErrTangentBusy)
+
+	// completing a session frees a slot
+	require.NoError(t, as.DeleteSession(created[0].id))
+	_, err := as.CreateSession(ctx, &ServerContext{SessionID: uuid.New()}, "token", time.Now().Add(time.Hour), tangentcommon.SessionTypeInteractive)
+	require.NoError(t, err)
+	_, err = as.CreateSession(ctx, &ServerContext{SessionID: uuid.New()}, "token", time.Now().Add(time.Hour), tangentcommon.SessionTypeInteractive)
+	assert.ErrorIs(t, err, ErrTangentBusy)
+}
+
+func TestSessionLogLevel(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	as := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+	sm, err := catalogmanager.SkillSetManagerFromJSON(context.Background(), test.SkillsetDef("dev"))
+	require.NoError(t, err)
+
+	// the tangent logs at info; the session's run logs at the session's level
+	runSession := func(logLevel string) (*bytes.Buffer, apperrors.Error) {
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf).Level(zerolog.InfoLevel)
+		ctx := logger.WithContext(context.Background())
+		s, err := as.CreateSession(ctx, &ServerContext{
+			SessionID:      uuid.New(),
+			View:           "dev-view",
+			ViewDefinition: test.GetViewDefinition("dev"),
+			LogLevel:       logLevel,
+		}, "token", time.Now().Add(time.Hour), tangentcommon.SessionTypeNonInteractive)
+		if err != nil {
+			return nil, err
+		}
+		defer as.DeleteSession(s.id)
+		s.skillSet = sm
+		useTestRunner(t, &crashingRunner{})
+		err = s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		require.NoError(t, err)
+		return &buf, nil
+	}
+
+	debugLog, err := runSession("debug")
+	require.NoError(t, err)
+	assert.Contains(t, debugLog.String(), "starting skill invocation")
+
+	infoLog, err := runSession("info")
+	require.NoError(t, err)
+	assert.Contains(t, infoLog.String(), "requested skill")
+	assert.NotContains(t, infoLog.String(), "starting skill invocation")
+
+	defaultLog, err := runSession("")
+	require.NoError(t, err)
+	assert.NotContains(t, defaultLog.String(), "starting skill invocation")
+
+	_, err = runSession("verbose")
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
 func TestCreateMCPProxySession(t *testing.T) {
 	config.SetTestMode(true)
 	ts := test.SetupTestCatalog(t)
@@ -175,7 +271,7 @@ func TestCreateMCPProxySession(t *testing.T) {
 
 	testListTools(t, mcpService, uri, token)
 	testInvokeTool(t, mcpService, uri, token)
-
+	testToolRoundTrip(t, mcpService, token)
 }
 
 // JSON-RPC envelope struct
@@ -329,6 +425,24 @@ func testInvokeTool(t *testing.T, srv *mcpservice.MCPServer, uri string, token s
 	}
 }
 
+func testToolRoundTrip(t *testing.T, srv *mcpservice.MCPServer, token string) {
+	body := []byte(`{"arguments": {"schemas": ["public"]}}`)
+	req := httptest.NewRequest(http.MethodPost, "/session/mcp/tools/list_tables", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	srv.Router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result struct {
+		Content []mcp.TextContent `json:"content"`
+		IsError bool              `json:"isError,omitempty"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.False(t, result.IsError)
+	require.NotEmpty(t, result.Content)
+	require.Contains(t, result.Content[0].Text, "integration_tokens")
+}
+
 func meanStdDev(durations []time.Duration) (mean, stddev float64) {
 	n := float64(len(durations))
 	if n == 0 {
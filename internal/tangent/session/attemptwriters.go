@@ -0,0 +1,75 @@
+package session
+
+import (
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+)
+
+// attemptWriters buffers the output a skill writes to its caller's writers during a run attempt.
+// The output of an attempt that crashed and is restarted is discarded, so that callers only
+// receive the output of the final attempt. The output buffered per attempt is capped by the
+// runner output limits.
+type attemptWriters struct {
+	targets  []*tangentcommon.IOWriters
+	buffered []*tangentcommon.IOWriters
+	out      []*tangentcommon.BufferedWriter
+	err      []*tangentcommon.BufferedWriter
+	limiters []*tangentcommon.LimitedWriter
+}
+
+// newAttemptWriters returns buffers for targets, each holding at most the limits of stdout and
+// stderr. Each buffer keeps the source and level filter of the writers it stands in for.
+func newAttemptWriters(targets []*tangentcommon.IOWriters, limits config.RunnerOutputLimits) *attemptWriters {
+	a := &attemptWriters{targets: targets}
+	for _, target := range targets {
+		out, err := tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter()
+		outLimiter := tangentcommon.NewLimitedWriter(out, int64(limits.MaxStdoutBytes))
+		errLimiter := tangentcommon.NewLimitedWriter(err, int64(limits.MaxStderrBytes))
+		a.out = append(a.out, out)
+		a.err = append(a.err, err)
+		a.limiters = append(a.limiters, outLimiter, errLimiter)
+		a.buffered = append(a.buffered, &tangentcommon.IOWriters{
+			Out:    outLimiter,
+			Err:    errLimiter,
+			Source: target.Source,
+			Level:  target.Level,
+		})
+	}
+	return a
+}
+
+// writers returns the writers the runner writes to in place of the targets.
+func (a *attemptWriters) writers() []*tangentcommon.IOWriters {
+	return a.buffered
+}
+
+// discard drops the output of the current attempt.
+func (a *attemptWriters) discard() {
+	for i := range a.targets {
+		a.out[i].Reset()
+		a.err[i].Reset()
+	}
+	a.resetLimits()
+}
+
+// flush writes the output of the current attempt to the targets.
+func (a *attemptWriters) flush() {
+	for i, target := range a.targets {
+		if target.Out != nil && a.out[i].Len() > 0 {
+			target.Out.Write(a.out[i].Bytes())
+		}
+		if target.Err != nil && a.err[i].Len() > 0 {
+			target.Err.Write(a.err[i].Bytes())
+		}
+		a.out[i].Reset()
+		a.err[i].Reset()
+	}
+	a.resetLimits()
+}
+
+// resetLimits lets the next attempt buffer up to the limits again.
+func (a *attemptWriters) resetLimits() {
+	for _, limiter := range a.limiters {
+		limiter.Reset()
+	}
+}
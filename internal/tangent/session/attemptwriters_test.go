@@ -0,0 +1,27 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+)
+
+func TestAttemptWritersLimit(t *testing.T) {
+	out, errOut := tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter()
+	a := newAttemptWriters([]*tangentcommon.IOWriters{{Out: out, Err: errOut}},
+		config.RunnerOutputLimits{MaxStdoutBytes: 5, MaxStderrBytes: 3})
+	w := a.writers()[0]
+
+	// a discarded attempt does not use up the limit of the next one
+	w.Out.Write([]byte("crashed attempt"))
+	a.discard()
+
+	w.Out.Write([]byte("hello world"))
+	w.Err.Write([]byte("oops"))
+	a.flush()
+
+	assert.Equal(t, "hello"+tangentcommon.TruncationMarker, out.String())
+	assert.Equal(t, "oop"+tangentcommon.TruncationMarker, errOut.String())
+}
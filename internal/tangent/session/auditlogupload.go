@@ -0,0 +1,111 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	srvsession "github.com/tansive/tansive/internal/catalogsrv/session"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// splitAuditLog splits an encoded audit log into parts of at most chunkSize bytes.
+func splitAuditLog(auditLog string, chunkSize int) []string {
+	if chunkSize <= 0 || len(auditLog) <= chunkSize {
+		return []string{auditLog}
+	}
+	parts := make([]string, 0, (len(auditLog)+chunkSize-1)/chunkSize)
+	for i := 0; i < len(auditLog); i += chunkSize {
+		parts = append(parts, auditLog[i:min(i+chunkSize, len(auditLog))])
+	}
+	return parts
+}
+
+// prepareAuditLogForStatus uploads large audit logs in parts ahead of the status update.
+// Returns the audit log to embed in the execution status, which is empty if the log was
+// uploaded in parts. Small logs are returned unchanged and shipped in a single request.
+// If the chunked upload fails, the whole log is returned so that it is shipped in a single
+// request instead, along with the error from the chunked upload.
+func prepareAuditLogForStatus(ctx context.Context, client httpclient.HTTPClientInterface, auditLog string) (string, apperrors.Error) {
+	chunkSize := config.Config().AuditLog.UploadChunkSize
+	if auditLog == "" || chunkSize <= 0 || len(auditLog) <= chunkSize {
+		return auditLog, nil
+	}
+	if err := uploadAuditLogInParts(ctx, client, auditLog, chunkSize); err != nil {
+		return auditLog, err
+	}
+	return "", nil
+}
+
+// uploadAuditLogInParts ships the encoded audit log using the server's chunked upload API.
+// If the upload fails once started, it is aborted so that the server discards the parts.
+func uploadAuditLogInParts(ctx context.Context, client httpclient.HTTPClientInterface, auditLog string, chunkSize int) (apperr apperrors.Error) {
+	if len(auditLog) > chunkSize*srvsession.MaxAuditLogUploadParts {
+		return ErrFailedRequestToTansiveServer.Msg("audit log exceeds maximum upload size")
+	}
+
+	rsp, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "sessions/auditlog/uploads",
+	})
+	if err != nil {
+		return ErrFailedRequestToTansiveServer.Msg(err.Error())
+	}
+	var initRsp srvsession.AuditLogUploadInitRsp
+	if err := json.Unmarshal(rsp, &initRsp); err != nil || initRsp.UploadID == "" {
+		return ErrFailedRequestToTansiveServer.Msg("invalid response when initializing audit log upload")
+	}
+	defer func() {
+		if apperr == nil {
+			return
+		}
+		if _, _, err := client.DoRequest(httpclient.RequestOptions{
+			Method: http.MethodDelete,
+			Path:   "sessions/auditlog/uploads/" + initRsp.UploadID,
+		}); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("upload_id", initRsp.UploadID).Msg("failed to abort audit log upload")
+		}
+	}()
+
+	parts := splitAuditLog(auditLog, chunkSize)
+	for i, data := range parts {
+		body, err := json.Marshal(srvsession.AuditLogUploadPart{Data: data})
+		if err != nil {
+			return ErrFailedRequestToTansiveServer.Msg(err.Error())
+		}
+		_, _, err = client.DoRequest(httpclient.RequestOptions{
+			Method: http.MethodPut,
+			Path:   "sessions/auditlog/uploads/" + initRsp.UploadID + "/parts/" + strconv.Itoa(i),
+			Body:   body,
+		})
+		if err != nil {
+			return ErrFailedRequestToTansiveServer.Msg(err.Error())
+		}
+	}
+
+	sum := sha256.Sum256([]byte(auditLog))
+	body, err := json.Marshal(srvsession.AuditLogUploadComplete{
+		Parts: len(parts),
+		Hash:  hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		return ErrFailedRequestToTansiveServer.Msg(err.Error())
+	}
+	_, _, err = client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "sessions/auditlog/uploads/" + initRsp.UploadID + "/complete",
+		Body:   body,
+	})
+	if err != nil {
+		return ErrFailedRequestToTansiveServer.Msg(err.Error())
+	}
+
+	log.Ctx(ctx).Info().Int("parts", len(parts)).Msg("uploaded audit log in parts")
+	return nil
+}
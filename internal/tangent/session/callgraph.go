@@ -0,0 +1,56 @@
+package session
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+	"github.com/tansive/tansive/internal/tangent/session/toolgraph"
+)
+
+// CallGraphRsp is the call graph of a session. Calls holds the invocations made directly by the
+// session, each with the tree of nested invocations it made.
+type CallGraphRsp struct {
+	SessionID string                `json:"sessionID"`
+	Calls     []*toolgraph.CallNode `json:"calls"`
+}
+
+// getCallGraph handles GET /sessions/{id}/callgraph.
+// It returns the invocations of an active session as a tree, with the skill name and status of
+// each. The request must carry the session's access token.
+func getCallGraph(r *http.Request) (*httpx.Response, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid session ID")
+	}
+	session, err := sessionManager.GetSession(id)
+	if err != nil {
+		return nil, err
+	}
+	if !session.authorizeToken(r) {
+		return nil, httpx.ErrUnAuthorized("invalid session token")
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &CallGraphRsp{
+			SessionID: session.GetSessionID(),
+			Calls:     session.callGraph.Tree(),
+		},
+	}, nil
+}
+
+// authorizeToken reports whether the request carries the session's unexpired access token as a bearer token.
+func (s *session) authorizeToken(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || s.token == "" {
+		return false
+	}
+	if !s.tokenExpiry.IsZero() && time.Now().After(s.tokenExpiry) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
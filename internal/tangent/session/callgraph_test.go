@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/session/toolgraph"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+// nestingRunner runs list_pods by invoking restart_deployment in the same session, and calls
+// during once the nested call returns.
+type nestingRunner struct {
+	fakeRunner
+	s      *session
+	during func()
+}
+
+func (r *nestingRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	if args.SkillName != "list_pods" {
+		return nil
+	}
+	if err := r.s.Run(ctx, args.InvocationID, "restart_deployment", map[string]any{"deployment": "web"}); err != nil {
+		return err
+	}
+	r.during()
+	return nil
+}
+
+func TestGetCallGraph(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.token = "session-token"
+	s.auditLogInfo.auditLogger = zerolog.New(io.Discard)
+	sessionManager.sessions[s.id] = s
+	t.Cleanup(func() { delete(sessionManager.sessions, s.id) })
+
+	router := chi.NewRouter()
+	router.Route("/sessions", Router)
+	getGraph := func(sessionID, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+sessionID+"/callgraph", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	callGraph := func() []*toolgraph.CallNode {
+		rec := getGraph(s.id.String(), "session-token")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var rsp CallGraphRsp
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+		assert.Equal(t, s.id.String(), rsp.SessionID)
+		return rsp.Calls
+	}
+
+	// The graph is read while list_pods is still running, after its nested call has completed
+	var running []*toolgraph.CallNode
+	useTestRunner(t, &nestingRunner{s: s, during: func() { running = callGraph() }})
+	require.NoError(t, s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+		Out: tangentcommon.NewBufferedWriter(),
+		Err: tangentcommon.NewBufferedWriter(),
+	}))
+
+	require.Len(t, running, 1)
+	root := running[0]
+	assert.Equal(t, toolgraph.ToolName("list_pods"), root.Tool)
+	assert.Equal(t, toolgraph.CallStatusRunning, root.Status)
+	require.Len(t, root.Calls, 1)
+	assert.Equal(t, toolgraph.ToolName("restart_deployment"), root.Calls[0].Tool)
+	assert.Equal(t, toolgraph.CallStatusCompleted, root.Calls[0].Status)
+	assert.NotEqual(t, root.CallID, root.Calls[0].CallID)
+	assert.Empty(t, root.Calls[0].Calls)
+
+	finished := callGraph()
+	require.Len(t, finished, 1)
+	assert.Equal(t, root.CallID, finished[0].CallID)
+	assert.Equal(t, toolgraph.CallStatusCompleted, finished[0].Status)
+	require.Len(t, finished[0].Calls, 1)
+	assert.Equal(t, root.Calls[0].CallID, finished[0].Calls[0].CallID)
+
+	assert.Equal(t, http.StatusUnauthorized, getGraph(s.id.String(), "").Code)
+	assert.Equal(t, http.StatusUnauthorized, getGraph(s.id.String(), "other-token").Code)
+	assert.Equal(t, http.StatusBadRequest, getGraph("not-a-uuid", "session-token").Code)
+}
@@ -79,6 +79,10 @@ var (
 	// Occurs when a skill references a transform that is not available or properly configured.
 	ErrTransformUndefined apperrors.Error = ErrSessionError.New("transform is undefined").SetStatusCode(http.StatusBadRequest)
 
+	// ErrTransformChainTooLong is returned when a skill invocation runs more transforms than allowed.
+	// Occurs when transforms invoke skills whose transforms in turn invoke skills, beyond transform.max_chain_length.
+	ErrTransformChainTooLong apperrors.Error = ErrSessionError.New("transform chain too long").SetStatusCode(http.StatusBadRequest)
+
 	// ErrSkillNotMCP is returned when a skill is not an MCP server.
 	// Occurs when a skill is not annotated with the MCP type.
 	ErrSkillNotMCP apperrors.Error = ErrSessionError.New("skill is not an MCP server").SetStatusCode(http.StatusBadRequest)
@@ -86,4 +90,39 @@ var (
 	// ErrInvalidInput is returned when the input arguments are invalid.
 	// Occurs when the input arguments are not a map[string]any.
 	ErrInvalidInput apperrors.Error = ErrSessionError.New("invalid input arguments").SetStatusCode(http.StatusBadRequest)
+
+	// ErrInvalidSkillOutput is returned when a skill's output does not conform to its output schema.
+	// Occurs only for skills that enable output validation.
+	ErrInvalidSkillOutput apperrors.Error = ErrSessionError.New("invalid skill output").SetStatusCode(http.StatusUnprocessableEntity)
+
+	// ErrRunnerNotStreaming is returned when a streaming run is requested for a skill whose
+	// runner does not accept input after the skill has started.
+	ErrRunnerNotStreaming apperrors.Error = ErrSessionError.New("runner does not support streaming input").SetStatusCode(http.StatusBadRequest)
+
+	// ErrRunnerNotSupported is returned when a skillset requires a runner that this tangent
+	// does not advertise in its capabilities.
+	ErrRunnerNotSupported apperrors.Error = ErrSessionError.New("runner not supported by this tangent").SetStatusCode(http.StatusBadRequest)
+
+	// ErrSessionInterrupted is returned when a session is interrupted by a restart of the tangent.
+	ErrSessionInterrupted apperrors.Error = ErrSessionError.New("session interrupted").SetStatusCode(http.StatusServiceUnavailable)
+
+	// ErrTangentBusy is returned when a session is started on a tangent that already runs its
+	// maximum number of concurrent sessions.
+	ErrTangentBusy apperrors.Error = ErrSessionError.New("tangent is busy").SetStatusCode(http.StatusServiceUnavailable)
+
+	// ErrPreflightFailed is returned when the preflight skill of a skillset fails, which aborts
+	// the invocation of the skill it precedes.
+	ErrPreflightFailed apperrors.Error = ErrSessionError.New("preflight failed").SetStatusCode(http.StatusForbidden)
+
+	// ErrSessionRevoked is returned when the tansive server ended a session while it was still
+	// running on the tangent, such as after its heartbeats lapsed.
+	ErrSessionRevoked apperrors.Error = ErrSessionError.New("session revoked by tansive server").SetStatusCode(http.StatusGone)
+
+	// ErrInputNotStreaming is returned when input is sent to a session that was not started
+	// with streaming input.
+	ErrInputNotStreaming apperrors.Error = ErrSessionError.New("session does not accept streaming input").SetStatusCode(http.StatusBadRequest)
+
+	// ErrInputClosed is returned when input is sent to a session after its input was closed
+	// or its skill completed.
+	ErrInputClosed apperrors.Error = ErrSessionError.New("session input is closed").SetStatusCode(http.StatusConflict)
 )
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// RunHeartbeats reports the liveness of the tangent and of every active session to the tansive
+// server every tansive_server.heartbeat_interval until ctx is done. A failed heartbeat is retried on the next
+// tick, so heartbeats resume once the tansive server is reachable again. Sessions the server no
+// longer considers active are stopped.
+func RunHeartbeats(ctx context.Context) {
+	interval, err := config.Config().TansiveServer.GetHeartbeatInterval()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("invalid heartbeat interval")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sendTangentHeartbeat(ctx)
+			sendHeartbeats(ctx)
+		}
+	}
+}
+
+// sendTangentHeartbeat reports the liveness of the tangent itself, so that sessions pinned to it
+// are accepted even when it is not running any session.
+func sendTangentHeartbeat(ctx context.Context) {
+	runtimeConfig := config.GetRuntimeConfig()
+	if runtimeConfig == nil || !runtimeConfig.Registered {
+		return
+	}
+	client := getHTTPClient(&clientConfig{
+		serverURL: config.Config().TansiveServer.GetURL(),
+	})
+	opts := httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "tangents/" + runtimeConfig.TangentID.String() + "/heartbeat",
+	}
+	if _, _, err := client.DoRequest(opts); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to send tangent heartbeat")
+	}
+}
+
+// sendHeartbeats sends a heartbeat for each active session.
+func sendHeartbeats(ctx context.Context) {
+	sessions, err := ActiveSessionManager().ListSessions()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to list sessions for heartbeats")
+		return
+	}
+	for _, s := range sessions {
+		sessionCtx := log.Ctx(ctx).With().Str("session_id", s.id.String()).Logger().WithContext(ctx)
+		s.heartbeat(sessionCtx)
+	}
+}
+
+// heartbeat reports the liveness of the session to the tansive server. If the server has
+// already ended the session, for example because earlier heartbeats lapsed, or no longer accepts
+// the session's token, for example because the tangent is being drained, the session is stopped.
+func (s *session) heartbeat(ctx context.Context) {
+	err := s.sendHeartbeat()
+	if err == nil {
+		if s.heartbeatLapsed {
+			log.Ctx(ctx).Info().Msg("resumed session heartbeats")
+			s.heartbeatLapsed = false
+		}
+		return
+	}
+
+	var httpErr *httpclient.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusConflict:
+			log.Ctx(ctx).Warn().Err(err).Msg("session is no longer active on the tansive server, stopping session")
+			s.revoke(ctx)
+			return
+		case http.StatusUnauthorized:
+			log.Ctx(ctx).Warn().Err(err).Msg("session token was rejected by the tansive server, stopping session")
+			s.revoke(ctx)
+			return
+		}
+	}
+	if !s.heartbeatLapsed {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to send session heartbeat")
+		s.heartbeatLapsed = true
+	}
+}
+
+func (s *session) sendHeartbeat() error {
+	client := getHTTPClient(&clientConfig{
+		token:       s.token,
+		tokenExpiry: s.tokenExpiry,
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+
+	opts := httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "sessions/heartbeat",
+	}
+
+	_, _, err := client.DoRequest(opts)
+	return err
+}
+
+// revoke stops a session the tansive server no longer considers active. MCP proxy sessions are
+// stopped here. For interactive sessions, running skills are cancelled and no further skills
+// may start, which ends the session through its normal completion path, and pending input is
+// rejected. Either way the session is removed, so that it no longer takes up a slot on the
+// tangent or sends heartbeats.
+func (s *session) revoke(ctx context.Context) {
+	if s.mcpSession.random != "" {
+		s.Stop(ctx, ErrSessionRevoked)
+	} else {
+		s.cancelSkills(true)
+		if s.input != nil {
+			s.input.finish()
+		}
+	}
+	ActiveSessionManager().DeleteSession(s.id)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/common/tangentsig"
 	"github.com/tansive/tansive/internal/tangent/config"
 	"github.com/tansive/tansive/internal/tangent/runners"
 )
@@ -48,6 +49,14 @@ func (c *clientConfig) GetSigningKey() (string, []byte) {
 	return c.signingKeyID, c.signingKey
 }
 
+// GetSignatureHeaders returns the header names requests are signed with.
+func (c *clientConfig) GetSignatureHeaders() tangentsig.Headers {
+	if config.Config() == nil {
+		return tangentsig.DefaultHeaders
+	}
+	return config.Config().TansiveServer.SignatureHeaders.WithDefaults()
+}
+
 // getHTTPClient creates an HTTP client with the given configuration.
 // Returns a test client in test mode or a production client otherwise.
 func getHTTPClient(clientConfig *clientConfig) httpclient.HTTPClientInterface {
@@ -0,0 +1,100 @@
+package mcpservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// CallToolRequest is the body of a tool round-trip request.
+type CallToolRequest struct {
+	Arguments map[string]any `json:"arguments"` // Arguments passed to the tool
+}
+
+// handleTestListTools returns the tools the session currently exposes to MCP clients.
+// The listing is dispatched as a tools/list message so it goes through the same filtering as
+// the MCP endpoint.
+func (s *MCPServer) handleTestListTools(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+	dispatch(r.Context(), w, endpoint, mcp.MethodToolsList, struct{}{})
+}
+
+// handleTestCallTool invokes the named tool with the arguments in the request body and returns
+// its result. The call is dispatched as a tools/call message, so policy checks, input transforms
+// and auditing are applied exactly as for calls made by an MCP client.
+func (s *MCPServer) handleTestCallTool(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+	req := CallToolRequest{}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to read request body")
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+	}
+	if req.Arguments == nil {
+		req.Arguments = map[string]any{}
+	}
+	params := mcp.CallToolParams{
+		Name:      chi.URLParam(r, "name"),
+		Arguments: req.Arguments,
+	}
+	dispatch(r.Context(), w, endpoint, mcp.MethodToolsCall, params)
+}
+
+// dispatch sends a JSON-RPC request for method to the session's MCP server and writes the
+// result, or the JSON-RPC error mapped to an HTTP status.
+func dispatch(ctx context.Context, w http.ResponseWriter, endpoint *MCPEndpoint, method mcp.MCPMethod, params any) {
+	msg, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to encode request")
+		return
+	}
+	switch resp := endpoint.server.HandleMessage(ctx, msg).(type) {
+	case mcp.JSONRPCResponse:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp.Result)
+	case mcp.JSONRPCError:
+		log.Ctx(ctx).Error().Str("method", string(method)).Int("code", resp.Error.Code).Msg(resp.Error.Message)
+		writeError(w, rpcErrorStatus(resp.Error.Code), resp.Error.Message)
+	default:
+		writeError(w, http.StatusInternalServerError, "unexpected response from MCP server")
+	}
+}
+
+// rpcErrorStatus maps a JSON-RPC error code to an HTTP status.
+func rpcErrorStatus(code int) int {
+	switch code {
+	case mcp.INVALID_REQUEST, mcp.INVALID_PARAMS, mcp.METHOD_NOT_FOUND, mcp.PARSE_ERROR:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
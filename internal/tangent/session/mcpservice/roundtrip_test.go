@@ -0,0 +1,120 @@
+package mcpservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skillHandler stands in for a session whose MCP tools are backed by skills.
+type skillHandler struct {
+	calls []mcp.CallToolParams
+}
+
+func (h *skillHandler) MCPListTools(ctx context.Context) ([]mcp.Tool, error) {
+	return []mcp.Tool{
+		mcp.NewTool("list_tables", mcp.WithDescription("List tables"), mcp.WithArray("schemas")),
+		mcp.NewTool("drop_table", mcp.WithDescription("Drop a table"), mcp.WithString("table")),
+	}, nil
+}
+
+func (h *skillHandler) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	h.calls = append(h.calls, params)
+	args, _ := params.Arguments.(map[string]any)
+	return mcp.NewToolResultText(fmt.Sprintf("%s: %v", tool.Name, args["schemas"])), nil
+}
+
+// MCPFilterTools hides drop_table, as a view without the skill's exported actions would.
+func (h *skillHandler) MCPFilterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	filtered := []mcp.Tool{}
+	for _, tool := range tools {
+		if tool.Name != "drop_table" {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+func newTestSession(t *testing.T, handler MCPHandler) (*MCPServer, string) {
+	t.Helper()
+	svc := &MCPServer{Router: chi.NewRouter()}
+	svc.mountHandlers()
+	srv := server.NewMCPServer("test", "0.1.0",
+		server.WithToolCapabilities(true),
+		server.WithToolFilter(handler.MCPFilterTools),
+	)
+	require.NoError(t, loadTools(context.Background(), srv, handler))
+	random := generateRandomString(32)
+	sum := sha256.Sum256([]byte(random))
+	svc.sessions.Store(hex.EncodeToString(sum[:]), &MCPEndpoint{server: srv, handler: handler})
+	return svc, "tn_" + random
+}
+
+func TestToolRoundTrip(t *testing.T) {
+	handler := &skillHandler{}
+	svc, token := newTestSession(t, handler)
+
+	do := func(method, path, token string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		svc.Router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("list tools", func(t *testing.T) {
+		rr := do(http.MethodGet, "/session/mcp/tools", token, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var result mcp.ListToolsResult
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+		require.Len(t, result.Tools, 1)
+		assert.Equal(t, "list_tables", result.Tools[0].Name)
+	})
+
+	t.Run("call skill-backed tool", func(t *testing.T) {
+		body := []byte(`{"arguments": {"schemas": ["public"]}}`)
+		rr := do(http.MethodPost, "/session/mcp/tools/list_tables", token, body)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var result struct {
+			Content []mcp.TextContent `json:"content"`
+			IsError bool              `json:"isError"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+		assert.False(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, "list_tables: [public]", result.Content[0].Text)
+		require.Len(t, handler.calls, 1)
+		assert.Equal(t, "list_tables", handler.calls[0].Name)
+	})
+
+	t.Run("call unknown tool", func(t *testing.T) {
+		rr := do(http.MethodPost, "/session/mcp/tools/unknown_tool", token, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rr := do(http.MethodPost, "/session/mcp/tools/list_tables", token, []byte(`{`))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("requires auth", func(t *testing.T) {
+		rr := do(http.MethodGet, "/session/mcp/tools", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		rr = do(http.MethodPost, "/session/mcp/tools/list_tables", "tn_unknown", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
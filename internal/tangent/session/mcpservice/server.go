@@ -68,11 +68,33 @@ func (s *MCPServer) mountHandlers() {
 	s.Router.Use(middleware.PanicHandler)
 	s.Router.Route("/session/mcp", func(r chi.Router) {
 		r.Post("/", s.handleMCP)
+		r.Get("/tools", s.handleTestListTools)
+		r.Post("/tools/{name}", s.handleTestCallTool)
 	})
 }
 
 // handleMCP is a handler for the MCP endpoint.
 func (s *MCPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
+	handler, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+	log.Ctx(r.Context()).Info().Msg("handleMCP")
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"error": "Invalid JSON"}`)
+		return
+	}
+	resp := handler.server.HandleMessage(r.Context(), raw)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// authenticate resolves the MCP endpoint of the session token in the Authorization header.
+// It writes an error response and returns false if the token is missing or unknown.
+func (s *MCPServer) authenticate(w http.ResponseWriter, r *http.Request) (*MCPEndpoint, bool) {
 	authHeader := r.Header.Get("Authorization")
 	const bearerPrefix = "Bearer "
 	const tokenPrefix = "tn_"
@@ -80,36 +102,26 @@ func (s *MCPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		fmt.Fprintf(w, `{"error": "Missing or invalid Authorization header"}`)
-		return
+		return nil, false
 	}
 	sessionToken := authHeader[len(bearerPrefix):]
 	if len(sessionToken) <= len(tokenPrefix) || sessionToken[:len(tokenPrefix)] != tokenPrefix {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		fmt.Fprintf(w, `{"error": "Invalid session token"}`)
-		return
+		return nil, false
 	}
 	tokenRandom := sessionToken[len(tokenPrefix):]
 	sum := sha256.Sum256([]byte(tokenRandom))
 	random := hex.EncodeToString(sum[:])
 	if endpointVal, ok := s.sessions.Load(random); ok {
-		log.Ctx(r.Context()).Info().Msg("handleMCP")
 		handler, _ := endpointVal.(*MCPEndpoint)
-		var raw json.RawMessage
-		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			fmt.Fprintf(w, `{"error": "Invalid JSON"}`)
-			return
-		}
-		resp := handler.server.HandleMessage(r.Context(), raw)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
-		return
+		return handler, true
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNotFound)
 	fmt.Fprintf(w, `{"error": "Session not found"}`)
+	return nil, false
 }
 
 // ListenAndServe starts the MCP server on port 8627.
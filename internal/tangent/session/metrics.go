@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const skillInvocationsMetric = "tansive.skill.invocations"
+
+var (
+	meterMu          sync.RWMutex
+	skillInvocations metric.Int64Counter = newSkillInvocationsCounter(metricnoop.NewMeterProvider())
+)
+
+// InitMetrics configures export of skill metrics to the OTLP metrics endpoint in the tangent
+// config. Metrics are dropped when no endpoint is configured.
+// Returns a shutdown function that flushes pending metrics.
+func InitMetrics(ctx context.Context) (func(context.Context) error, error) {
+	endpoint := config.Config().Telemetry.OTLPMetricsEndpoint
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(resource.NewSchemaless(semconv.ServiceName("tangent"))),
+	)
+	setSkillInvocationsCounter(newSkillInvocationsCounter(mp))
+	return mp.Shutdown, nil
+}
+
+// newSkillInvocationsCounter creates the counter of skill invocations from the given provider.
+func newSkillInvocationsCounter(mp metric.MeterProvider) metric.Int64Counter {
+	counter, err := mp.Meter(tracerName).Int64Counter(skillInvocationsMetric,
+		metric.WithDescription("Number of completed skill invocations"),
+		metric.WithUnit("{invocation}"))
+	if err != nil {
+		counter, _ = metricnoop.NewMeterProvider().Meter(tracerName).Int64Counter(skillInvocationsMetric)
+	}
+	return counter
+}
+
+func setSkillInvocationsCounter(counter metric.Int64Counter) {
+	meterMu.Lock()
+	defer meterMu.Unlock()
+	skillInvocations = counter
+}
+
+func getSkillInvocationsCounter() metric.Int64Counter {
+	meterMu.RLock()
+	defer meterMu.RUnlock()
+	return skillInvocations
+}
+
+// skillMetricAttributes returns the labels of a skill's metrics: the skill name, the outcome of
+// the invocation and the metric tags declared by the skill.
+func skillMetricAttributes(skillName, status string, skill *catalogmanager.Skill) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("skill", skillName),
+		attribute.String("status", status),
+	}
+	if skill == nil {
+		return attrs
+	}
+	keys := make([]string, 0, len(skill.MetricTags))
+	for key := range skill.MetricTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attrs = append(attrs, attribute.String(key, skill.MetricTags[key]))
+	}
+	return attrs
+}
+
+// recordSkillInvocation counts a completed skill invocation, labelled with its outcome and the
+// metric tags declared by the skill.
+func (s *session) recordSkillInvocation(ctx context.Context, skillName string, failed bool) {
+	status := "success"
+	if failed {
+		status = "failed"
+	}
+	// a skill that cannot be resolved is still counted, without tags
+	skill, _ := s.resolveSkill(skillName)
+	getSkillInvocationsCounter().Add(ctx, 1, metric.WithAttributes(skillMetricAttributes(skillName, status, skill)...))
+}
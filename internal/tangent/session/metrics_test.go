@@ -0,0 +1,114 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tidwall/sjson"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// recordingCounter is a counter that records the labels of each measurement.
+type recordingCounter struct {
+	embedded.Int64Counter
+	mu     sync.Mutex
+	labels []map[string]string
+}
+
+func (c *recordingCounter) Add(_ context.Context, _ int64, options ...metric.AddOption) {
+	labels := map[string]string{}
+	set := metric.NewAddConfig(options).Attributes()
+	for _, kv := range set.ToSlice() {
+		labels[string(kv.Key)] = kv.Value.AsString()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.labels = append(c.labels, labels)
+}
+
+func TestSkillMetricTags(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	counter := &recordingCounter{}
+	prev := getSkillInvocationsCounter()
+	setSkillInvocationsCounter(counter)
+	defer setSkillInvocationsCounter(prev)
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.metricTags", map[string]string{
+		"team":     "platform",
+		"pipeline": "deploy",
+	})
+	require.NoError(t, err)
+	sm, err := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, err)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+
+	useTestRunner(t, &outputRunner{output: `{"pods": []}`})
+	require.NoError(t, s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+		Out: tangentcommon.NewBufferedWriter(),
+		Err: tangentcommon.NewBufferedWriter(),
+	}))
+
+	require.Len(t, counter.labels, 1)
+	assert.Equal(t, map[string]string{
+		"skill":    "list_pods",
+		"status":   "success",
+		"team":     "platform",
+		"pipeline": "deploy",
+	}, counter.labels[0])
+}
+
+func TestSkillMetricAttributes(t *testing.T) {
+	t.Run("without a skill only the invocation labels are set", func(t *testing.T) {
+		attrs := skillMetricAttributes("list_pods", "failed", nil)
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("skill", "list_pods"),
+			attribute.String("status", "failed"),
+		}, attrs)
+	})
+
+	t.Run("tags are appended in key order", func(t *testing.T) {
+		skill := &catalogmanager.Skill{MetricTags: map[string]string{"team": "platform", "pipeline": "deploy"}}
+		attrs := skillMetricAttributes("list_pods", "success", skill)
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("skill", "list_pods"),
+			attribute.String("status", "success"),
+			attribute.String("pipeline", "deploy"),
+			attribute.String("team", "platform"),
+		}, attrs)
+	})
+}
+
+func TestSkillInvocationsCounterExportsToProvider(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer mp.Shutdown(context.Background())
+
+	counter := newSkillInvocationsCounter(mp)
+	counter.Add(context.Background(), 1, metric.WithAttributes(skillMetricAttributes("list_pods", "success", nil)...))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, skillInvocationsMetric, m.Name)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+)
+
+// runPreflight runs the skillset's preflight skill before skillName and returns its output, which
+// is passed to the skill. The preflight skill is run like any other skill, with its own policy
+// check, and receives the name and input arguments of the skill it precedes. Returns nil if the
+// skillset has no preflight skill or skillName is the preflight skill itself.
+func (s *session) runPreflight(ctx context.Context, invokerID, invocationID, skillName string, inputArgs map[string]any) (json.RawMessage, apperrors.Error) {
+	preflight := s.skillSet.GetPreflightSkill()
+	if preflight == "" || preflight == skillName {
+		return nil, nil
+	}
+
+	outWriter := tangentcommon.NewBufferedWriter()
+	errWriter := tangentcommon.NewBufferedWriter()
+	err := s.Run(ctx, invokerID, preflight, map[string]any{
+		"skill":     skillName,
+		"inputArgs": inputArgs,
+	}, &tangentcommon.IOWriters{
+		Out: outWriter,
+		Err: errWriter,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("stderr", errWriter.String()).Str("preflight", preflight).Msg("preflight skill failed")
+		s.auditLogInfo.auditLogger.Error().
+			Str("event", "skill_preflight").
+			Str("status", "failed").
+			Str("invocation_id", invocationID).
+			Str("preflight", preflight).
+			Err(err).
+			Str("skill", skillName).
+			Msg("preflight failed")
+		return nil, ErrPreflightFailed.MsgErr("preflight skill "+preflight+" failed for "+skillName+": "+err.Error(), err)
+	}
+	s.auditLogInfo.auditLogger.Info().
+		Str("event", "skill_preflight").
+		Str("status", "success").
+		Str("invocation_id", invocationID).
+		Str("preflight", preflight).
+		Str("skill", skillName).
+		Msg("preflight passed")
+	return preflightResult(outWriter.Bytes()), nil
+}
+
+// preflightResult returns the output of a preflight skill as JSON. Output that is not a JSON
+// document is passed as a string.
+func preflightResult(output []byte) json.RawMessage {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil
+	}
+	if json.Valid(output) {
+		return json.RawMessage(output)
+	}
+	result, _ := json.Marshal(string(output))
+	return result
+}
@@ -0,0 +1,151 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tansive/tansive/pkg/api"
+	"github.com/tidwall/sjson"
+)
+
+// preflightTestRunner runs the preflight skill with a fixed outcome and records the arguments of
+// every skill it runs.
+type preflightTestRunner struct {
+	fakeRunner
+	preflightOutput string
+	preflightErr    apperrors.Error
+	runs            []*api.SkillInputArgs
+}
+
+func (r *preflightTestRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	r.runs = append(r.runs, args)
+	// writers are added for each run, so only those of this run are written to
+	writers := r.writers
+	r.writers = nil
+	if args.SkillName != "authorize" {
+		return nil
+	}
+	for _, w := range writers {
+		w.Out.Write([]byte(r.preflightOutput))
+	}
+	return r.preflightErr
+}
+
+func TestPreflightSkill(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.-1", map[string]any{
+		"name":   "authorize",
+		"source": "my-tools-script",
+		"inputSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"skill":     map[string]any{"type": "string"},
+				"inputArgs": map[string]any{"type": "object"},
+			},
+		},
+		"exportedActions": []string{"kubernetes.pods.list"},
+	})
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.preflight", "authorize")
+	require.NoError(t, err)
+	// the session loads the skillset with the fields it fetches from the tansive server
+	selected, apperr := catalogmanager.SelectSkillSetFields(def, skillSetFields)
+	require.NoError(t, apperr)
+	sm, apperr := catalogmanager.SkillSetManagerFromJSON(ctx, selected)
+	require.NoError(t, apperr)
+	require.Equal(t, "authorize", sm.GetPreflightSkill())
+
+	newSession := func(t *testing.T) (*session, *strings.Builder) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		auditLog := &strings.Builder{}
+		s.auditLogInfo.auditLogger = zerolog.New(auditLog)
+		return s, auditLog
+	}
+	run := func(s *session) apperrors.Error {
+		return s.Run(ctx, "", "list_pods", map[string]any{"labelSelector": "app=web"}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+	}
+
+	t.Run("passing preflight runs the skill with its result", func(t *testing.T) {
+		s, auditLog := newSession(t)
+		runner := &preflightTestRunner{preflightOutput: `{"approved": true}`}
+		useTestRunner(t, runner)
+		require.NoError(t, run(s))
+
+		require.Len(t, runner.runs, 2)
+		assert.Equal(t, "authorize", runner.runs[0].SkillName)
+		assert.Equal(t, "list_pods", runner.runs[0].InputArgs["skill"])
+		assert.Equal(t, map[string]any{"labelSelector": "app=web"}, runner.runs[0].InputArgs["inputArgs"])
+		assert.Empty(t, runner.runs[0].Preflight)
+
+		assert.Equal(t, "list_pods", runner.runs[1].SkillName)
+		assert.JSONEq(t, `{"approved": true}`, string(runner.runs[1].Preflight))
+		assert.Contains(t, auditLog.String(), `"event":"skill_preflight","status":"success"`)
+	})
+
+	t.Run("failing preflight blocks the skill", func(t *testing.T) {
+		s, auditLog := newSession(t)
+		runner := &preflightTestRunner{preflightErr: ErrExecutionFailed.Msg("not approved")}
+		useTestRunner(t, runner)
+		err := run(s)
+		assert.ErrorIs(t, err, ErrPreflightFailed)
+
+		require.Len(t, runner.runs, 1)
+		assert.Equal(t, "authorize", runner.runs[0].SkillName)
+		assert.Contains(t, auditLog.String(), `"event":"skill_preflight","status":"failed"`)
+	})
+
+	t.Run("preflight audit log redacts the input of the skill it precedes", func(t *testing.T) {
+		redactingDef, err := sjson.SetBytes(def, "spec.skills.0.redactInputPaths", []string{"/labelSelector"})
+		require.NoError(t, err)
+		selected, apperr := catalogmanager.SelectSkillSetFields(redactingDef, skillSetFields)
+		require.NoError(t, apperr)
+		redactingSM, apperr := catalogmanager.SkillSetManagerFromJSON(ctx, selected)
+		require.NoError(t, apperr)
+
+		s, auditLog := newSession(t)
+		s.skillSet = redactingSM
+		runner := &preflightTestRunner{preflightOutput: `{"approved": true}`}
+		useTestRunner(t, runner)
+		require.NoError(t, run(s))
+
+		// the skills receive the original values
+		assert.Equal(t, map[string]any{"labelSelector": "app=web"}, runner.runs[0].InputArgs["inputArgs"])
+		assert.Equal(t, "app=web", runner.runs[1].InputArgs["labelSelector"])
+		assert.NotContains(t, auditLog.String(), "app=web")
+		assert.Contains(t, auditLog.String(), `"input_args":{"inputArgs":{"labelSelector":"`+catalogmanager.RedactedInputValue+`"},"skill":"list_pods"}`)
+	})
+
+	t.Run("preflight skill is not preceded by itself", func(t *testing.T) {
+		s, _ := newSession(t)
+		runner := &preflightTestRunner{preflightOutput: "ok"}
+		useTestRunner(t, runner)
+		require.NoError(t, s.Run(ctx, "", "authorize", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		}))
+		require.Len(t, runner.runs, 1)
+	})
+}
+
+func TestPreflightResult(t *testing.T) {
+	assert.JSONEq(t, `{"approved": true}`, string(preflightResult([]byte(" {\"approved\": true}\n"))))
+	assert.Equal(t, json.RawMessage(`"approved"`), preflightResult([]byte("approved\n")))
+	assert.Nil(t, preflightResult([]byte("  ")))
+}
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+)
+
+// resultCacheEntry is the output of a successful invocation of a cacheable skill.
+type resultCacheEntry struct {
+	stdout    []byte
+	stderr    []byte
+	expiresAt time.Time
+}
+
+// replay writes the cached output to the writers, as the runner would have.
+func (e resultCacheEntry) replay(writers []*tangentcommon.IOWriters) {
+	for _, w := range writers {
+		if w == nil {
+			continue
+		}
+		if len(e.stdout) > 0 && w.Out != nil && w.Accepts(tangentcommon.OutputSourceStdout) {
+			w.Out.Write(e.stdout)
+		}
+		if len(e.stderr) > 0 && w.Err != nil && w.Accepts(tangentcommon.OutputSourceStderr) {
+			w.Err.Write(e.stderr)
+		}
+	}
+}
+
+// resultCache holds the results of a session's cacheable skills. It holds at most maxEntries
+// results totalling at most maxBytes, and no result is kept longer than maxTTL. A limit of 0 or
+// less is not enforced.
+type resultCache struct {
+	mu         sync.Mutex
+	now        func() time.Time // replaced in tests; time.Now if nil
+	maxEntries int
+	maxBytes   int
+	maxTTL     time.Duration
+	size       int // total size of the cached output
+	entries    map[string]resultCacheEntry
+}
+
+// newResultCache returns a result cache bounded by the result_cache settings of the tangent
+// config, or by their defaults if the config is not loaded.
+func newResultCache() *resultCache {
+	c := &resultCache{
+		maxEntries: config.DefaultResultCacheMaxEntries,
+		maxBytes:   config.DefaultResultCacheMaxBytes,
+		entries:    make(map[string]resultCacheEntry),
+	}
+	c.maxTTL, _ = config.ParseDuration(config.DefaultResultCacheMaxTTL)
+	if cfg := config.Config(); cfg != nil {
+		c.maxEntries = cfg.ResultCache.MaxEntries
+		c.maxBytes = cfg.ResultCache.MaxBytes
+		c.maxTTL = cfg.ResultCache.GetMaxTTL()
+	}
+	return c
+}
+
+func (c *resultCache) currentTime() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// get returns the cached result for key if present and not expired.
+func (c *resultCache) get(key string) (resultCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return resultCacheEntry{}, false
+	}
+	if !c.currentTime().Before(entry.expiresAt) {
+		c.remove(key)
+		return resultCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// put caches the output of an invocation under key for ttl, capped at the cache's maximum TTL.
+// Expired results are dropped first, and then the results closest to expiry until the new
+// result fits. A result larger than the cache is not cached.
+func (c *resultCache) put(key string, stdout, stderr []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxTTL > 0 && ttl > c.maxTTL {
+		ttl = c.maxTTL
+	}
+	entrySize := len(stdout) + len(stderr)
+	if ttl <= 0 || (c.maxBytes > 0 && entrySize > c.maxBytes) {
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]resultCacheEntry)
+	}
+	c.remove(key)
+
+	now := c.currentTime()
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			c.remove(k)
+		}
+	}
+	for len(c.entries) > 0 && ((c.maxEntries > 0 && len(c.entries) >= c.maxEntries) || (c.maxBytes > 0 && c.size+entrySize > c.maxBytes)) {
+		var oldestKey string
+		var oldest time.Time
+		first := true
+		for k, e := range c.entries {
+			if first || e.expiresAt.Before(oldest) {
+				oldestKey, oldest, first = k, e.expiresAt, false
+			}
+		}
+		c.remove(oldestKey)
+	}
+
+	c.entries[key] = resultCacheEntry{
+		stdout:    bytes.Clone(stdout),
+		stderr:    bytes.Clone(stderr),
+		expiresAt: now.Add(ttl),
+	}
+	c.size += entrySize
+}
+
+// remove drops the result cached under key. c.mu must be held.
+func (c *resultCache) remove(key string) {
+	if entry, ok := c.entries[key]; ok {
+		c.size -= len(entry.stdout) + len(entry.stderr)
+		delete(c.entries, key)
+	}
+}
+
+// resultCacheKey returns the key of an invocation's result: the skill, the session's view and a
+// hash of the input args and preflight result. Input args are marshaled with sorted map keys, so
+// identical args always hash the same.
+func resultCacheKey(skillName, view string, inputArgs map[string]any, preflight json.RawMessage) (string, error) {
+	input, err := json.Marshal(struct {
+		InputArgs map[string]any  `json:"inputArgs"`
+		Preflight json.RawMessage `json:"preflight,omitempty"`
+	}{inputArgs, preflight})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(input)
+	return skillName + "/" + view + "/" + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+// resultFormat returns the result format declared by the named skill, or an empty format if the
+// skill does not declare one or cannot be resolved.
+func (s *session) resultFormat(skillName string) api.ResultFormat {
+	skill, err := s.resolveSkill(skillName)
+	if err != nil {
+		return ""
+	}
+	// result formats are validated when the skillset is saved
+	format, _ := skill.GetResultFormat()
+	return format
+}
+
+// formatContent returns the content of a skill's output in the given result format. Without a
+// format, JSON output is returned as a JSON value and anything else as text.
+func formatContent(output []byte, format api.ResultFormat) map[string]any {
+	var parsed any
+	isJSON := json.Unmarshal(output, &parsed) == nil
+
+	switch format {
+	case api.ResultFormatText:
+		return map[string]any{"type": "text", "value": string(output)}
+	case api.ResultFormatMarkdown:
+		return map[string]any{"type": "markdown", "value": formatText(output, format)}
+	case api.ResultFormatJSON:
+		if !isJSON {
+			return map[string]any{"type": "string", "value": string(output)}
+		}
+	}
+
+	if isJSON {
+		return map[string]any{"type": detectJSONType(parsed), "value": parsed}
+	}
+	// Not JSON, treat as plaintext
+	return map[string]any{"type": "text", "value": string(output)}
+}
+
+// formatText returns a textual tool result in the given result format. JSON results are
+// rendered in a fenced code block for markdown, and other results are encoded as a JSON string
+// for json.
+func formatText(output []byte, format api.ResultFormat) string {
+	switch format {
+	case api.ResultFormatMarkdown:
+		var indented bytes.Buffer
+		if json.Indent(&indented, bytes.TrimSpace(output), "", "  ") == nil {
+			return "```json\n" + indented.String() + "\n```"
+		}
+	case api.ResultFormatJSON:
+		if !json.Valid(output) {
+			encoded, _ := json.Marshal(string(output))
+			return string(encoded)
+		}
+	}
+	return string(output)
+}
+
+// formatToolResult applies format to the text content of a successful MCP tool result.
+func formatToolResult(result *mcp.CallToolResult, format api.ResultFormat) {
+	if result == nil || result.IsError || format == "" {
+		return
+	}
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text = formatText([]byte(text.Text), format)
+			result.Content[i] = text
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+func TestResultFormat(t *testing.T) {
+	jsonOutput := `{"status": "ok", "count": 2}`
+	textOutput := "2 tables found"
+
+	output := func(s string) *tangentcommon.BufferedWriter {
+		w := tangentcommon.NewBufferedWriter()
+		_, err := w.Write([]byte(s))
+		require.NoError(t, err)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		format api.ResultFormat
+		output string
+		want   map[string]any
+	}{
+		{"default json", "", jsonOutput, map[string]any{"type": "object", "value": map[string]any{"status": "ok", "count": float64(2)}}},
+		{"default text", "", textOutput, map[string]any{"type": "text", "value": textOutput}},
+		{"json", api.ResultFormatJSON, jsonOutput, map[string]any{"type": "object", "value": map[string]any{"status": "ok", "count": float64(2)}}},
+		{"json from text", api.ResultFormatJSON, textOutput, map[string]any{"type": "string", "value": textOutput}},
+		{"text from json", api.ResultFormatText, jsonOutput, map[string]any{"type": "text", "value": jsonOutput}},
+		{"markdown from json", api.ResultFormatMarkdown, jsonOutput, map[string]any{"type": "markdown", "value": "```json\n{\n  \"status\": \"ok\",\n  \"count\": 2\n}\n```"}},
+		{"markdown from text", api.ResultFormatMarkdown, textOutput, map[string]any{"type": "markdown", "value": textOutput}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := processOutput(output(tt.output), tangentcommon.NewBufferedWriter(), nil, tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rsp["content"])
+		})
+	}
+
+	t.Run("mcp tool result", func(t *testing.T) {
+		result := mcp.NewToolResultText(jsonOutput)
+		formatToolResult(result, api.ResultFormatMarkdown)
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, "```json\n{\n  \"status\": \"ok\",\n  \"count\": 2\n}\n```", result.Content[0].(mcp.TextContent).Text)
+
+		result = mcp.NewToolResultText(textOutput)
+		formatToolResult(result, api.ResultFormatJSON)
+		assert.Equal(t, `"2 tables found"`, result.Content[0].(mcp.TextContent).Text)
+
+		result = mcp.NewToolResultError("failed")
+		formatToolResult(result, api.ResultFormatJSON)
+		assert.Equal(t, "failed", result.Content[0].(mcp.TextContent).Text)
+	})
+}
@@ -26,6 +26,21 @@ var resourceObjectHandlers = []ResponseHandlerParam{
 		Path:    "/",
 		Handler: stopSession,
 	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{id}/callgraph",
+		Handler: getCallGraph,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{id}/input",
+		Handler: sendSessionInput,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/{id}/input",
+		Handler: closeSessionInput,
+	},
 }
 
 // Router sets up HTTP routes for session management.
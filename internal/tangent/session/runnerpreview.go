@@ -0,0 +1,139 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+const runnerPreviewSuffix = "/runner-preview"
+
+type bearerTokenKey struct{}
+
+// SkillsetRouter sets up HTTP routes for inspecting skillsets on this tangent.
+// Requests must carry a Tansive access token, which is used to fetch the skillset from the
+// catalog server so that the caller can only inspect skillsets their view grants access to.
+func SkillsetRouter(r chi.Router) {
+	r.Use(BearerTokenAuthenticator)
+	r.Method(http.MethodGet, "/*", httpx.WrapHttpRsp(getRunnerPreview))
+}
+
+// BearerTokenAuthenticator requires a bearer token and stores it in the request context.
+func BearerTokenAuthenticator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			httpx.ErrUnAuthorized("missing bearer token").Send(w)
+			return
+		}
+		ctx := context.WithValue(r.Context(), bearerTokenKey{}, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// getRunnerPreview handles GET /skillsets/{path}/skills/{name}/runner-preview.
+// It returns the command, working directory and environment the skill's runner would use for
+// the JSON input in the input query parameter, without running the skill. Secrets are shown as
+// placeholders and are never looked up.
+// The optional environment query parameter selects the source overrides to apply.
+func getRunnerPreview(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	route, ok := strings.CutSuffix(chi.URLParam(r, "*"), runnerPreviewSuffix)
+	if !ok {
+		return nil, httpx.ErrInvalidRequest("unknown skillset endpoint")
+	}
+	idx := strings.LastIndex(route, "/skills/")
+	if idx <= 0 {
+		return nil, httpx.ErrInvalidRequest("expected /skillsets/{path}/skills/{name}/runner-preview")
+	}
+	skillsetPath, skillName := "/"+route[:idx], route[idx+len("/skills/"):]
+	if skillName == "" || strings.Contains(skillName, "/") {
+		return nil, httpx.ErrInvalidRequest("invalid skill name")
+	}
+
+	inputArgs := map[string]any{}
+	if input := r.URL.Query().Get("input"); input != "" {
+		if err := json.Unmarshal([]byte(input), &inputArgs); err != nil {
+			return nil, httpx.ErrInvalidRequest("input must be a JSON object: " + err.Error())
+		}
+	}
+
+	token, _ := ctx.Value(bearerTokenKey{}).(string)
+	client := getHTTPClient(&clientConfig{
+		token:     token,
+		serverURL: config.Config().TansiveServer.GetURL(),
+	})
+	sm, err := fetchSkillset(ctx, client, skillsetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := previewSkillRunner(ctx, sm, skillName, r.URL.Query().Get("environment"), inputArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   preview,
+	}, nil
+}
+
+// fetchSkillset retrieves a skillset from the catalog server without caching, since the
+// scope of the caller's token is not known to the tangent.
+func fetchSkillset(ctx context.Context, client httpclient.HTTPClientInterface, path string) (catalogmanager.SkillSetManager, apperrors.Error) {
+	return getSkillsetWithCache(ctx, client, nil, skillsetCacheKey{path: path})
+}
+
+// previewSkillRunner resolves the runner command for a skill in the skillset as it would be run
+// in a session in the given environment.
+func previewSkillRunner(ctx context.Context, sm catalogmanager.SkillSetManager, skillName, environment string, inputArgs map[string]any) (*runners.RunnerPreview, apperrors.Error) {
+	skill, err := sm.GetSkill(skillName)
+	if err != nil {
+		return nil, ErrInvalidObject.Msg(err.Error())
+	}
+	if err := skill.ValidateInput(inputArgs); err != nil {
+		return nil, err
+	}
+
+	runnerDef, err := sm.GetSourceForSkill(skill.Name)
+	if err != nil {
+		return nil, err
+	}
+	runnerDef = sm.ApplySourceOverrides(runnerDef, environment)
+	if runnerDef.Runner != catcommon.StdioRunnerID {
+		return nil, ErrBadRequest.Msg("runner " + string(runnerDef.Runner) + " does not support previews")
+	}
+
+	serviceEndpoint, goerr := config.GetSocketPath()
+	if goerr != nil {
+		return nil, ErrExecutionFailed.Msg("failed to get socket path")
+	}
+	sessionID := uuid.New().String()
+	args := &api.SkillInputArgs{
+		InvocationID:    uuid.New().String(),
+		ServiceEndpoint: serviceEndpoint,
+		SessionID:       sessionID,
+		SkillName:       skill.Name,
+		InputArgs:       inputArgs,
+	}
+
+	preview, err := runners.Preview(ctx, sessionID, runnerDef, args)
+	if err != nil {
+		return nil, ErrBadRequest.Msg(err.Error())
+	}
+	return preview, nil
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tidwall/sjson"
+)
+
+func TestPreviewSkillRunner(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	stdiorunner.TestInit()
+	ctx := context.Background()
+	t.Setenv("TANSIVE_SECRET_KUBE_TOKEN", "s3cr3t-token")
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.sources.1.config.env.KUBE_TOKEN", map[string]any{"secretRef": "kube/token"})
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.sources.1.config.env.AUTH_HEADER", "Bearer ")
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.overrides", map[string]any{
+		"prod": map[string]any{
+			"my-tools-script": map[string]any{"env": map[string]any{"TEST_VAR": "prod_value"}},
+		},
+	})
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+
+	t.Run("input and env are reflected", func(t *testing.T) {
+		preview, err := previewSkillRunner(ctx, sm, "list_pods", "", map[string]any{"labelSelector": "app=web"})
+		require.NoError(t, err)
+
+		require.Len(t, preview.Command, 3)
+		assert.Equal(t, "/bin/bash", preview.Command[0])
+		assert.True(t, strings.HasSuffix(preview.Command[1], "tools_script.sh"))
+		var args map[string]any
+		require.NoError(t, json.Unmarshal([]byte(preview.Command[2]), &args))
+		assert.Equal(t, "list_pods", args["skillName"])
+		assert.Equal(t, map[string]any{"labelSelector": "app=web"}, args["inputArgs"])
+
+		assert.Equal(t, "test_value", preview.Env["TEST_VAR"])
+		assert.Equal(t, preview.Env["HOME"], preview.WorkingDir)
+	})
+
+	t.Run("environment overrides are applied", func(t *testing.T) {
+		preview, err := previewSkillRunner(ctx, sm, "list_pods", "prod", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, "prod_value", preview.Env["TEST_VAR"])
+	})
+
+	t.Run("secrets are shown as placeholders", func(t *testing.T) {
+		preview, err := previewSkillRunner(ctx, sm, "list_pods", "", map[string]any{})
+		require.NoError(t, err)
+		assert.Equal(t, config.Redacted, preview.Env["KUBE_TOKEN"])
+		assert.Equal(t, "Bearer ", preview.Env["AUTH_HEADER"])
+
+		body, goerr := json.Marshal(preview)
+		require.NoError(t, goerr)
+		assert.NotContains(t, string(body), "s3cr3t-token")
+	})
+
+	t.Run("invalid input is rejected", func(t *testing.T) {
+		_, err := previewSkillRunner(ctx, sm, "list_pods", "", map[string]any{"labelSelector": 5})
+		assert.Error(t, err)
+		_, err = previewSkillRunner(ctx, sm, "no_such_skill", "", map[string]any{})
+		assert.ErrorIs(t, err, ErrInvalidObject)
+	})
+}
@@ -6,9 +6,15 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -34,19 +40,31 @@ import (
 // session represents an active execution session for skill invocation.
 // It manages the session state, skill execution, policy validation, and audit logging.
 type session struct {
-	id             uuid.UUID
-	context        *ServerContext
-	skillSet       catalogmanager.SkillSetManager
-	viewDef        *policy.ViewDefinition
-	token          string
-	tokenExpiry    time.Time
-	callGraph      *toolgraph.CallGraph
-	invocationIDs  map[string]*policy.ViewDefinition
-	auditLogInfo   auditLogInfo
-	logger         *zerolog.Logger
-	mcpSession     mcpSession
-	sessionType    tangentcommon.SessionType
-	skillCancelers []context.CancelFunc
+	id              uuid.UUID
+	context         *ServerContext
+	skillSet        catalogmanager.SkillSetManager
+	dependencies    []catalogmanager.Dependency
+	viewDef         *policy.ViewDefinition
+	token           string
+	tokenExpiry     time.Time
+	callGraph       *toolgraph.CallGraph
+	invocationIDs   map[string]*policy.ViewDefinition
+	auditLogInfo    auditLogInfo
+	logger          *zerolog.Logger
+	mcpSession      mcpSession
+	sessionType     tangentcommon.SessionType
+	skillsMu        sync.Mutex
+	skillCancelers  []context.CancelFunc // guarded by skillsMu
+	revoked         bool                 // guarded by skillsMu, set once the tansive server ended the session
+	invocationSpans sync.Map             // invocationID → trace.SpanContext
+	transcript      *transcriptRecorder
+	skillSlotsMu    sync.Mutex
+	skillSlots      map[string]chan struct{} // skill name → semaphore for skills with maxConcurrent set
+	stateMu         sync.Mutex
+	statePath       string // path of the persisted session state, empty if the state is not persisted
+	heartbeatLapsed bool   // set while heartbeats to the tansive server are failing
+	resultCache     *resultCache
+	input           *sessionInput // input streamed to the skill, nil unless the session streams input
 }
 
 // GetSessionID returns the unique identifier for this session.
@@ -58,15 +76,37 @@ func (s *session) GetSessionID() string {
 // The invokerID must be valid if provided, and the skill must be authorized by policy.
 // Returns an error if execution fails or policy validation fails.
 func (s *session) Run(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any, ioWriters ...*tangentcommon.IOWriters) apperrors.Error {
+	return s.run(ctx, invokerID, skillName, inputArgs, nil, ioWriters...)
+}
+
+// RunStreaming executes a skill like Run and feeds each chunk received on input to the
+// running skill's stdin until input is closed. Policy is validated once, before the skill
+// starts. The skill's runner must support streaming input. The caller should stop sending
+// once RunStreaming returns.
+func (s *session) RunStreaming(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any, input <-chan []byte, ioWriters ...*tangentcommon.IOWriters) apperrors.Error {
+	if input == nil {
+		return ErrInvalidInput.Msg("input channel is required for a streaming run")
+	}
+	return s.run(ctx, invokerID, skillName, inputArgs, input, ioWriters...)
+}
+
+// run validates policy for the skill, transforms its input and runs it. If input is not nil,
+// the skill is run with streaming input.
+func (s *session) run(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any, input <-chan []byte, ioWriters ...*tangentcommon.IOWriters) (retErr apperrors.Error) {
+	ctx = s.withLogLevel(ctx)
 	s.logger.Info().Str("skill", skillName).Msg("requested skill")
 	log.Ctx(ctx).Info().Msgf("requested skill: %s", skillName)
 	invocationID := uuid.New().String()
+	log.Ctx(ctx).Debug().Str("invoker_id", invokerID).Str("invocation_id", invocationID).Msg("starting skill invocation")
+	ctx = withTransformChain(ctx)
+	ctx, span := s.startSkillSpan(ctx, invokerID, invocationID, skillName)
+	defer func() { s.endSkillSpan(invocationID, span, retErr) }()
 	s.auditLogInfo.auditLogger.Info().
 		Str("event", "skill_start").
 		Str("invoker_id", invokerID).
 		Str("invocation_id", invocationID).
 		Str("skill", skillName).
-		Any("input_args", inputArgs).
+		Any("input_args", s.auditInputArgs(ctx, skillName, inputArgs)).
 		Msg("requested skill")
 	if invokerID != "" {
 		if _, ok := s.invocationIDs[invokerID]; !ok {
@@ -80,27 +120,47 @@ func (s *session) Run(ctx context.Context, invokerID string, skillName string, i
 		return err
 	}
 
+	if err := s.checkRunnerCapabilities(); err != nil {
+		s.logger.Error().Err(err).Msg("skillset requires unsupported runners")
+		return err
+	}
+
+	// A skill invoked by an alias runs under its canonical name
+	if skill, err := s.resolveSkill(skillName); err == nil && skill.Name != skillName {
+		s.auditLogInfo.auditLogger.Info().
+			Str("event", "skill_alias_resolved").
+			Str("invocation_id", invocationID).
+			Str("invoked_name", skillName).
+			Str("skill", skill.Name).
+			Msg("resolved skill alias")
+		skillName = skill.Name
+	}
+
 	isAllowed, basis, actions, err := s.ValidateRunPolicy(ctx, invokerID, skillName)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to validate run policy")
 		return err
 	}
 	if !isAllowed {
-		msg := fmt.Sprintf("blocked by Tansive policy: view '%s' does not authorize any of required actions - %v - to use this skill", s.context.View, actions)
+		msg, reason := s.blockedByPolicyMessage(skillName, actions, basis)
+		setSpanPolicyDecision(ctx, "blocked", reason)
 		s.logger.Error().Str("policy_decision", "true").Msg(msg)
 		log.Ctx(ctx).Error().Str("policy_decision", "true").Msg(msg)
 		s.auditLogInfo.auditLogger.Error().
 			Str("event", "policy_decision").
 			Str("decision", "blocked").
+			Str("reason", reason).
+			Strs("deny_reasons", denyReasons(basis)).
 			Str("invocation_id", invocationID).
 			Str("view", s.context.View).
 			Any("basis", basis).
 			Str("skill", skillName).
 			Any("actions", actions).
 			Msg("blocked by policy")
-		return ErrBlockedByPolicy.Msg(msg)
+		return s.blockedByPolicyError(msg, skillName, actions, basis)
 	}
 	msg := fmt.Sprintf("allowed by Tansive policy: view '%s' authorizes actions - %v - to use this skill", s.context.View, actions)
+	setSpanPolicyDecision(ctx, "allowed", "")
 	s.logger.Info().Str("policy_decision", "true").Msg(msg)
 	log.Ctx(ctx).Info().Str("policy_decision", "true").Msg(msg)
 	s.auditLogInfo.auditLogger.Info().
@@ -108,11 +168,16 @@ func (s *session) Run(ctx context.Context, invokerID string, skillName string, i
 		Str("decision", "allowed").
 		Str("invocation_id", invocationID).
 		Str("view", s.context.View).
-		Any("basis", basis).
+		Func(s.allowedBasis(basis)).
 		Str("skill", skillName).
 		Any("actions", actions).
 		Msg("allowed by policy")
 
+	preflight, err := s.runPreflight(ctx, invokerID, invocationID, skillName, inputArgs)
+	if err != nil {
+		return err
+	}
+
 	transformApplied, inputArgs, err := s.TransformInputForSkill(ctx, skillName, inputArgs, invocationID)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to transform input")
@@ -132,12 +197,25 @@ func (s *session) Run(ctx context.Context, invokerID string, skillName string, i
 			Str("status", "success").
 			Str("invocation_id", invocationID).
 			Str("skill", skillName).
-			Any("input_args", inputArgs).
+			Any("input_args", s.auditInputArgs(ctx, skillName, inputArgs)).
 			Msg("input transformed")
 	}
 
-	// We only support interactive skills for now
-	err = s.runSkill(ctx, invokerID, invocationID, skillName, inputArgs, ioWriters...)
+	// Cacheable skills return the output of an earlier invocation with the same input. Policy
+	// has been validated above, so a cached result is only returned to invocations allowed to run.
+	cacheKey, cacheTTL := s.resultCacheKey(ctx, skillName, inputArgs, preflight, input)
+	if cached, ok := s.resultCache.get(cacheKey); ok {
+		s.auditLogInfo.auditLogger.Info().
+			Str("event", "cache_hit").
+			Str("invocation_id", invocationID).
+			Str("skill", skillName).
+			Msg("returned cached skill result")
+		cached.replay(ioWriters)
+	} else {
+		// We only support interactive skills for now
+		err = s.runSkill(ctx, invokerID, invocationID, skillName, inputArgs, preflight, input, cacheKey, cacheTTL, ioWriters...)
+		s.recordSkillInvocation(ctx, skillName, err != nil)
+	}
 
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to run interactive skill")
@@ -176,18 +254,119 @@ func (s *session) ValidateRunPolicy(ctx context.Context, invokerID string, skill
 	}
 
 	actions := []string{}
-	allowed, basis, err := policy.AreActionsAllowedOnResource(s.viewDef, s.skillSet.GetResourcePath(), skill.GetExportedActions())
+	for _, action := range skill.GetExportedActions() {
+		actions = append(actions, string(action))
+	}
+
+	// Blocked skills are denied before any action evaluation
+	if s.viewDef.IsSkillBlocked(skill.Name, skill.Aliases...) {
+		return false, nil, actions, nil
+	}
+
+	allowed, basis, err := policy.AreActionsAllowedOnResource(s.viewDef, s.skillSetResourcePath(), skill.GetExportedActions())
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to validate run policy")
 		return false, nil, nil, err
 	}
-	for _, action := range skill.GetExportedActions() {
-		actions = append(actions, string(action))
-	}
 
 	return allowed, basis, actions, nil
 }
 
+// isSkillBlocked reports whether the view blocks the skill invoked as skillName, which may be
+// its name or one of its aliases.
+func (s *session) isSkillBlocked(skillName string) bool {
+	if skill, err := s.resolveSkill(skillName); err == nil {
+		return s.viewDef.IsSkillBlocked(skill.Name, skill.Aliases...)
+	}
+	return s.viewDef.IsSkillBlocked(skillName)
+}
+
+// blockedByPolicyMessage returns the user facing message and the audit reason for a
+// skill that was denied by ValidateRunPolicy.
+// Reasons attached to the deny rules in basis are appended to the message.
+func (s *session) blockedByPolicyMessage(skillName string, actions []string, basis map[policy.Intent][]policy.Rule) (string, string) {
+	if s.isSkillBlocked(skillName) {
+		return fmt.Sprintf("blocked by Tansive policy: skill '%s' is blocked by view '%s'", skillName, s.context.View), "skill_blocked"
+	}
+	msg := fmt.Sprintf("blocked by Tansive policy: view '%s' does not authorize any of required actions - %v - to use this skill", s.context.View, actions)
+	if reasons := denyReasons(basis); len(reasons) > 0 {
+		msg += " - reason: " + strings.Join(reasons, "; ")
+	}
+	return msg, "actions_not_authorized"
+}
+
+// denyReasons returns the distinct reasons attached to the deny rules in basis.
+func denyReasons(basis map[policy.Intent][]policy.Rule) []string {
+	var reasons []string
+	for _, rule := range basis[policy.IntentDeny] {
+		if rule.Reason != "" && !slices.Contains(reasons, rule.Reason) {
+			reasons = append(reasons, rule.Reason)
+		}
+	}
+	return reasons
+}
+
+// Reasons reported in PolicyBlock
+const (
+	PolicyBlockSkillBlocked    = "skill_blocked"
+	PolicyBlockDeniedByRule    = "denied_by_rule"
+	PolicyBlockNoMatchingAllow = "no_matching_allow"
+)
+
+// PolicyBlock describes why a skill invocation was blocked by policy. It is attached to
+// ErrBlockedByPolicy so that callers can decide whether to request elevated access.
+type PolicyBlock struct {
+	Skill           string        `json:"skill"`
+	RequiredActions []string      `json:"requiredActions"`
+	View            string        `json:"view"`
+	Reason          string        `json:"reason"`
+	DenyRules       []policy.Rule `json:"denyRules,omitempty"`
+	DenyReasons     []string      `json:"denyReasons,omitempty"`
+}
+
+func (b *PolicyBlock) Error() string {
+	return fmt.Sprintf("skill '%s' blocked by view '%s': %s", b.Skill, b.View, b.Reason)
+}
+
+// policyBlock builds the PolicyBlock for a skill that was denied by ValidateRunPolicy.
+func (s *session) policyBlock(skillName string, actions []string, basis map[policy.Intent][]policy.Rule) *PolicyBlock {
+	block := &PolicyBlock{
+		Skill:           skillName,
+		RequiredActions: actions,
+		View:            s.context.View,
+	}
+	switch {
+	case s.isSkillBlocked(skillName):
+		block.Reason = PolicyBlockSkillBlocked
+	case len(basis[policy.IntentDeny]) > 0:
+		block.Reason = PolicyBlockDeniedByRule
+		block.DenyRules = basis[policy.IntentDeny]
+		block.DenyReasons = denyReasons(basis)
+	default:
+		block.Reason = PolicyBlockNoMatchingAllow
+	}
+	return block
+}
+
+// blockedByPolicyError returns ErrBlockedByPolicy with the given message and the PolicyBlock attached.
+func (s *session) blockedByPolicyError(msg, skillName string, actions []string, basis map[policy.Intent][]policy.Rule) apperrors.Error {
+	return ErrBlockedByPolicy.MsgErr(msg, s.policyBlock(skillName, actions, basis))
+}
+
+// GetPolicyBlock returns the PolicyBlock attached to an ErrBlockedByPolicy error, if any.
+func GetPolicyBlock(err apperrors.Error) (*PolicyBlock, bool) {
+	if err == nil || !errors.Is(err, ErrBlockedByPolicy) {
+		return nil, false
+	}
+	for _, wrapped := range err.UnwrapAll() {
+		var block *PolicyBlock
+		if errors.As(wrapped, &block) {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
 // TransformInputForSkill applies JavaScript transformations to input arguments if defined.
 // Returns whether transformation was applied, the transformed arguments, and any error.
 func (s *session) TransformInputForSkill(ctx context.Context, skillName string, inputArgs map[string]any, invokerID string) (transformApplied bool, retArgs map[string]any, retErr apperrors.Error) {
@@ -195,19 +374,31 @@ func (s *session) TransformInputForSkill(ctx context.Context, skillName string,
 	if err != nil {
 		return false, inputArgs, err
 	}
+	// Top-level input keys are normalized to the skill's declared style both before the
+	// transform runs and on the transformed output
 	defer func() {
 		if retErr == nil {
-			retErr = skill.ValidateInput(retArgs)
+			retArgs = skill.NormalizeInput(retArgs)
+			retErr = s.validateSkillInput(ctx, skill, invokerID, retArgs)
 		}
 	}()
+	// Default input args fill in anything the caller did not provide
+	inputArgs = skill.ApplyDefaultInputArgs(skill.NormalizeInput(inputArgs))
 	if !skill.Transform.IsNil() {
+		ctx = withTransformChain(ctx)
+		if err := countTransform(ctx); err != nil {
+			return false, inputArgs, err
+		}
 		jsFunc, err := jsruntime.New(ctx, skill.Transform.String())
 		if err != nil {
 			return false, inputArgs, err
 		}
 		inputArgs, err = jsFunc.Run(ctx, s.context.SessionVariables, inputArgs, jsruntime.Options{
-			Timeout:      1000 * time.Millisecond,
-			SkillInvoker: s.skillInvoker(ctx, invokerID),
+			Timeout:        1000 * time.Millisecond,
+			SkillInvoker:   s.skillInvoker(ctx, invokerID),
+			MaxInputBytes:  config.Config().Transform.MaxInputBytes,
+			MaxOutputBytes: config.Config().Transform.MaxOutputBytes,
+			NumberMode:     config.Config().Transform.GetNumberMode(),
 		})
 		if err != nil {
 			return false, inputArgs, err
@@ -217,6 +408,33 @@ func (s *session) TransformInputForSkill(ctx context.Context, skillName string,
 	return false, inputArgs, nil
 }
 
+// transformChainKey is the context key of the number of transforms run for a skill invocation.
+type transformChainKey struct{}
+
+// withTransformChain returns ctx carrying a transform counter. Skills run by transforms and preflight
+// skills inherit the context of the invocation, so their transforms count towards the same chain.
+// The counter of ctx is kept if it already carries one.
+func withTransformChain(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(transformChainKey{}).(*atomic.Int32); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, transformChainKey{}, &atomic.Int32{})
+}
+
+// countTransform counts a transform run towards the chain of ctx. Returns ErrTransformChainTooLong
+// if the chain would exceed transform.max_chain_length.
+func countTransform(ctx context.Context) apperrors.Error {
+	count, ok := ctx.Value(transformChainKey{}).(*atomic.Int32)
+	if !ok {
+		return nil
+	}
+	limit := config.Config().Transform.MaxChainLength
+	if n := count.Add(1); limit > 0 && int(n) > limit {
+		return ErrTransformChainTooLong.Msg(fmt.Sprintf("skill invocation exceeds the maximum of %d transform executions", limit))
+	}
+	return nil
+}
+
 func (s *session) skillInvoker(ctx context.Context, invokerID string) func(skillName string, inputArgs map[string]any) ([]byte, apperrors.Error) {
 	return func(skillName string, inputArgs map[string]any) ([]byte, apperrors.Error) {
 		// Create writers to capture command outputs
@@ -253,9 +471,52 @@ func (s *session) ValidateInputForSkill(ctx context.Context, skillName string, i
 	return skill.ValidateInput(inputArgs)
 }
 
+// validateSkillInput validates input arguments against the skill's schema. A skill that warns on
+// invalid input is run with it, and the validation failure is logged and recorded in the audit
+// log instead of returned.
+func (s *session) validateSkillInput(ctx context.Context, skill *catalogmanager.Skill, invocationID string, inputArgs map[string]any) apperrors.Error {
+	err := skill.ValidateInput(inputArgs)
+	if err == nil || !skill.WarnOnInvalidInput() {
+		return err
+	}
+	s.logger.Warn().Err(err).Str("skill", skill.Name).Msg("running skill with invalid input")
+	log.Ctx(ctx).Warn().Err(err).Str("skill", skill.Name).Msg("running skill with invalid input")
+	s.auditLogInfo.auditLogger.Warn().
+		Str("event", "skill_input_validation").
+		Str("status", "warned").
+		Str("invocation_id", invocationID).
+		Err(err).
+		Str("skill", skill.Name).
+		Msg("input failed validation")
+	return nil
+}
+
+// resultCacheKey returns the result cache key of an invocation and how long its result is
+// cached. The key is empty if the skill is not cacheable or runs with streaming input.
+func (s *session) resultCacheKey(ctx context.Context, skillName string, inputArgs map[string]any, preflight json.RawMessage, input <-chan []byte) (string, time.Duration) {
+	if input != nil {
+		return "", 0
+	}
+	skill, err := s.resolveSkill(skillName)
+	if err != nil {
+		return "", 0
+	}
+	ttl := skill.GetCacheTTL()
+	if ttl <= 0 {
+		return "", 0
+	}
+	key, goerr := resultCacheKey(skill.Name, s.context.View, inputArgs, preflight)
+	if goerr != nil {
+		log.Ctx(ctx).Warn().Err(goerr).Str("skill", skillName).Msg("unable to compute result cache key, result will not be cached")
+		return "", 0
+	}
+	return key, ttl
+}
+
 // runSkill executes an skill with the given parameters.
-// Currently only skills are supported.
-func (s *session) runSkill(ctx context.Context, invokerID, invocationID string, skillName string, inputArgs map[string]any, ioWriters ...*tangentcommon.IOWriters) apperrors.Error {
+// Currently only skills are supported. If cacheKey is set, the output of a successful run is
+// cached under it for cacheTTL.
+func (s *session) runSkill(ctx context.Context, invokerID, invocationID string, skillName string, inputArgs map[string]any, preflight json.RawMessage, input <-chan []byte, cacheKey string, cacheTTL time.Duration, ioWriters ...*tangentcommon.IOWriters) (retErr apperrors.Error) {
 	if s.skillSet == nil {
 		return ErrUnableToGetSkillset.Msg("skillset not found")
 	}
@@ -264,22 +525,90 @@ func (s *session) runSkill(ctx context.Context, invokerID, invocationID string,
 	if err != nil {
 		return err
 	}
-	if err := skill.ValidateInput(inputArgs); err != nil {
+	// input of a skill that warns on invalid input was reported when it was transformed
+	if err := skill.ValidateInput(inputArgs); err != nil && !skill.WarnOnInvalidInput() {
+		return err
+	}
+
+	// A skill that is already in the invoker's ancestry would wait on its own slot, so the
+	// call graph is checked before waiting rather than when the call is registered.
+	if toolErr := s.callGraph.CheckCall(toolgraph.CallID(invokerID), toolgraph.ToolName(skillName)); toolErr != nil {
+		return ErrToolGraphError.Msg(toolErr.Error())
+	}
+
+	release, err := s.acquireSkillSlot(ctx, invocationID, skill)
+	if err != nil {
 		return err
 	}
+	defer release()
+
+	// Output of a cacheable skill is captured for the result cache.
+	var stdoutCapture, stderrCapture *tangentcommon.BufferedWriter
+	if cacheKey != "" {
+		stdoutCapture, stderrCapture = tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter()
+		ioWriters = append(ioWriters, &tangentcommon.IOWriters{Out: stdoutCapture, Err: stderrCapture})
+	}
+
+	// Output of a skill that may be restarted is buffered until its final attempt, so that the
+	// result cache only holds the output of that attempt. Streaming runs are never restarted, so
+	// their output is not held back.
+	var attempts *attemptWriters
+	if skill.MaxRestarts > 0 && input == nil {
+		runnerDef, err := s.skillSet.GetSourceForSkill(skillName)
+		if err != nil {
+			return err
+		}
+		attempts = newAttemptWriters(ioWriters, config.Config().RunnerOutput.Limits(string(runnerDef.Runner)))
+		ioWriters = attempts.writers()
+	}
 
 	runner, err := s.getRunner(ctx, skillName, ioWriters...)
 	if err != nil {
 		return err
 	}
+	setSpanRunner(ctx, runner.ID())
+	var streamingRunner runners.StreamingRunner
+	if input != nil {
+		var ok bool
+		if streamingRunner, ok = runner.(runners.StreamingRunner); !ok {
+			return ErrRunnerNotStreaming.Msg("runner " + runner.ID() + " does not accept streaming input")
+		}
+	}
 
+	// Output is captured for validation when the skill opts in
+	var outputCapture *tangentcommon.BufferedWriter
+	if skill.ValidateOutput {
+		outputCapture = tangentcommon.NewBufferedWriter()
+		runner.AddWriters(&tangentcommon.IOWriters{
+			Out: outputCapture,
+			Err: io.Discard,
+		})
+	}
+
+	// Interactive output is limited so that a chatty skill does not bloat the event bus and audit log.
+	// The interactive stream receives all output, while the audit log only records errors.
+	var stdoutLimiter, stderrLimiter *tangentcommon.LimitedWriter
 	if s.sessionType == tangentcommon.SessionTypeInteractive {
+		limits := config.Config().RunnerOutput.Limits(runner.ID())
+		stdoutLimiter = tangentcommon.NewLimitedWriter(
+			s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stdout").Str("runner", runner.ID()).Str("skill", skillName).Logger(),
+			int64(limits.MaxStdoutBytes))
+		stderrLimiter = tangentcommon.NewLimitedWriter(
+			s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stderr").Str("runner", runner.ID()).Str("skill", skillName).Logger(),
+			int64(limits.MaxStderrBytes))
 		interactiveIOWriters := &tangentcommon.IOWriters{
-			Out: s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stdout").Str("runner", runner.ID()).Str("skill", skillName).Logger(),
-			Err: s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stderr").Str("runner", runner.ID()).Str("skill", skillName).Logger(),
+			Out: stdoutLimiter,
+			Err: stderrLimiter,
+		}
+		auditIOWriters := &tangentcommon.IOWriters{
+			Err: tangentcommon.NewLimitedWriter(
+				s.auditLogInfo.auditLogger.With().Str("event", "skill_stderr").Str("invocation_id", invocationID).Str("skill", skillName).Logger(),
+				int64(limits.MaxStderrBytes)),
+			Source: tangentcommon.OutputSourceStderr,
+			Level:  zerolog.ErrorLevel,
 		}
 
-		runner.AddWriters(interactiveIOWriters)
+		runner.AddWriters(interactiveIOWriters, auditIOWriters)
 	}
 
 	serviceEndpoint, goerr := config.GetSocketPath()
@@ -294,6 +623,7 @@ func (s *session) runSkill(ctx context.Context, invokerID, invocationID string,
 		SkillName:        skillName,
 		InputArgs:        inputArgs,
 		SessionVariables: s.context.SessionVariables,
+		Preflight:        preflight,
 	}
 
 	if s.sessionType == tangentcommon.SessionTypeInteractive {
@@ -305,10 +635,17 @@ func (s *session) runSkill(ctx context.Context, invokerID, invocationID string,
 		return ErrToolGraphError.Msg(toolErr.Error())
 	}
 	s.invocationIDs[invocationID] = s.viewDef
+	s.persistState()
+	defer func() {
+		s.callGraph.SetStatus(toolgraph.CallID(invocationID), callStatus(retErr))
+		s.persistState()
+	}()
 
 	childCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	s.skillCancelers = append(s.skillCancelers, cancel)
+	if err := s.addSkillCanceler(cancel); err != nil {
+		return err
+	}
 
 	resultChan := make(chan apperrors.Error, 1)
 
@@ -327,8 +664,74 @@ func (s *session) runSkill(ctx context.Context, invokerID, invocationID string,
 			Str("runner", runner.ID()).
 			Str("invocation_id", invocationID).
 			Str("skill", skillName).
+			Bool("streaming", streamingRunner != nil).
 			Msg("starting runner")
-		err := runner.Run(ctx, &args)
+		var err apperrors.Error
+		for restarts := 0; ; restarts++ {
+			if streamingRunner != nil {
+				err = streamingRunner.RunStreaming(ctx, &args, input)
+			} else {
+				err = runner.Run(ctx, &args)
+			}
+			// Only crashes are retried. Streaming input has been consumed and cannot be replayed.
+			if restarts >= skill.MaxRestarts || streamingRunner != nil || ctx.Err() != nil || !runners.IsCrash(err) {
+				break
+			}
+			s.auditLogInfo.auditLogger.Warn().
+				Str("event", "runner_restart").
+				Str("runner", runner.ID()).
+				Str("invocation_id", invocationID).
+				Str("skill", skillName).
+				Int("restart", restarts+1).
+				Int("max_restarts", skill.MaxRestarts).
+				Err(err).
+				Msg("restarting crashed runner")
+			// Interactive output has already been streamed, so the start of each attempt is marked
+			if s.sessionType == tangentcommon.SessionTypeInteractive {
+				interactiveLogger := s.getLogger(TopicInteractiveLog)
+				interactiveLogger.Warn().
+					Str("event", "runner_restart").
+					Str("actor", "runner").
+					Str("runner", runner.ID()).
+					Str("skill", skillName).
+					Int("attempt", restarts+2).
+					Msg("runner crashed, output that follows is from a new attempt")
+			}
+			if attempts != nil {
+				attempts.discard()
+			}
+			if outputCapture != nil {
+				outputCapture.Reset()
+			}
+		}
+		if attempts != nil {
+			attempts.flush()
+		}
+		if err == nil && outputCapture != nil {
+			if err = skill.ValidateOutputAgainstSchema(outputCapture.Bytes()); err != nil {
+				s.auditLogInfo.auditLogger.Error().
+					Str("event", "output_validation").
+					Str("status", "failed").
+					Str("invocation_id", invocationID).
+					Str("skill", skillName).
+					Err(err).
+					Msg("skill output does not conform to output schema")
+				err = ErrInvalidSkillOutput.MsgErr("output of skill "+skillName+" does not conform to its output schema", err)
+			}
+		}
+		if err == nil && cacheKey != "" {
+			s.resultCache.put(cacheKey, stdoutCapture.Bytes(), stderrCapture.Bytes(), cacheTTL)
+		}
+		if stdoutLimiter != nil && (stdoutLimiter.Truncated() || stderrLimiter.Truncated()) {
+			s.auditLogInfo.auditLogger.Warn().
+				Str("event", "output_truncated").
+				Str("invocation_id", invocationID).
+				Str("runner", runner.ID()).
+				Str("skill", skillName).
+				Int64("stdout_dropped_bytes", stdoutLimiter.Dropped()).
+				Int64("stderr_dropped_bytes", stderrLimiter.Dropped()).
+				Msg("skill output exceeded the configured limit")
+		}
 		if err != nil {
 			s.logger.Error().Err(err).Msg("error running skill")
 			log.Ctx(ctx).Error().Err(err).Msgf("error running skill: %s", skillName)
@@ -387,6 +790,48 @@ func (s *session) runSkill(ctx context.Context, invokerID, invocationID string,
 	return <-resultChan
 }
 
+// acquireSkillSlot waits until the skill may run under its maxConcurrent limit and returns a
+// function that frees the slot. Skills without a limit are not restricted.
+func (s *session) acquireSkillSlot(ctx context.Context, invocationID string, skill *catalogmanager.Skill) (func(), apperrors.Error) {
+	if skill.MaxConcurrent <= 0 {
+		return func() {}, nil
+	}
+
+	s.skillSlotsMu.Lock()
+	if s.skillSlots == nil {
+		s.skillSlots = make(map[string]chan struct{})
+	}
+	slots, ok := s.skillSlots[skill.Name]
+	if !ok {
+		slots = make(chan struct{}, skill.MaxConcurrent)
+		s.skillSlots[skill.Name] = slots
+	}
+	s.skillSlotsMu.Unlock()
+
+	release := func() { <-slots }
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	s.auditLogInfo.auditLogger.Info().
+		Str("event", "skill_concurrency_wait").
+		Str("invocation_id", invocationID).
+		Str("skill", skill.Name).
+		Int("max_concurrent", skill.MaxConcurrent).
+		Msg("waiting for a running invocation of the skill to complete")
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ErrExecutionFailed.Msg("cancelled while waiting to run skill " + skill.Name)
+	}
+}
+
+// newRunner creates runners for skills. It is a variable so that tests can substitute runners.
+var newRunner = runners.NewRunner
+
 // getRunner creates a runner instance for the specified skill.
 // Returns the runner and any error encountered during creation.
 func (s *session) getRunner(ctx context.Context, skillName string, ioWriters ...*tangentcommon.IOWriters) (runners.Runner, apperrors.Error) {
@@ -398,7 +843,11 @@ func (s *session) getRunner(ctx context.Context, skillName string, ioWriters ...
 	if err != nil {
 		return nil, err
 	}
-	runner, err := runners.NewRunner(ctx, s.id.String(), runnerDef, ioWriters...)
+	runnerDef = s.skillSet.ApplySourceOverrides(runnerDef, s.context.Environment)
+	if missing := unsupportedRunners([]catcommon.RunnerID{runnerDef.Runner}, config.Capabilities()); len(missing) > 0 {
+		return nil, ErrRunnerNotSupported.Msg("runner " + string(runnerDef.Runner) + " required by skill " + skillName + " is not supported by this tangent")
+	}
+	runner, err := newRunner(ctx, s.id.String(), runnerDef, s.context.SessionVariables, ioWriters...)
 	if err != nil {
 		return nil, err
 	}
@@ -406,6 +855,40 @@ func (s *session) getRunner(ctx context.Context, skillName string, ioWriters ...
 	return runner, nil
 }
 
+// checkRunnerCapabilities verifies that this tangent supports every runner used by the
+// skillset. Missing runners are recorded in the audit log.
+func (s *session) checkRunnerCapabilities() apperrors.Error {
+	if s.skillSet == nil {
+		return nil
+	}
+	missing := unsupportedRunners(s.skillSet.GetRunnerTypes(), config.Capabilities())
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(missing))
+	for _, r := range missing {
+		names = append(names, string(r))
+	}
+	s.auditLogInfo.auditLogger.Error().
+		Str("event", "runner_capability_check").
+		Str("status", "failed").
+		Strs("missing_runners", names).
+		Msg("skillset requires runners not supported by this tangent")
+	return ErrRunnerNotSupported.Msg("runners not supported by this tangent: " + strings.Join(names, ", "))
+}
+
+// unsupportedRunners returns the required runners that are absent from capabilities,
+// without duplicates and in the order they are first required.
+func unsupportedRunners(required, capabilities []catcommon.RunnerID) []catcommon.RunnerID {
+	var missing []catcommon.RunnerID
+	for _, r := range required {
+		if !slices.Contains(capabilities, r) && !slices.Contains(missing, r) {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
 // fetchObjects retrieves the skillset and view definition from the catalog server.
 // Must be called before skill execution to ensure proper authorization.
 func (s *session) fetchObjects(ctx context.Context) apperrors.Error {
@@ -416,20 +899,102 @@ func (s *session) fetchObjects(ctx context.Context) apperrors.Error {
 	})
 
 	// get skillset
-	if s.skillSet == nil && s.context.SkillSet != "" {
-		skillset, err := getSkillset(ctx, client, s.context.SkillSet)
-		if err != nil {
-			return err
-		}
-		s.skillSet = skillset
+	if err := s.loadSkillSet(ctx, client, getSkillsetCache()); err != nil {
+		return err
 	}
 
 	// get view definition
 	s.viewDef = s.context.ViewDefinition
 
+	s.resolveDependencies()
+
+	return nil
+}
+
+// loadSkillSet fetches the session's skillset if it has not been loaded and applies the
+// session's context overrides to it. Overrides apply to this session's copy of the skillset only.
+func (s *session) loadSkillSet(ctx context.Context, client httpclient.HTTPClientInterface, cache *skillsetCache) apperrors.Error {
+	if s.skillSet != nil || s.context.SkillSet == "" {
+		return nil
+	}
+	skillset, err := getSkillsetWithCache(ctx, client, cache, skillsetCacheKey{
+		tenantID:  s.context.TenantID,
+		catalog:   s.context.Catalog,
+		variant:   s.context.Variant,
+		namespace: s.context.Namespace,
+		path:      s.context.SkillSet,
+	})
+	if err != nil {
+		return err
+	}
+	if err := skillset.ApplyContextOverrides(s.context.ContextOverrides); err != nil {
+		return ErrInvalidObject.Msg(err.Error())
+	}
+	s.skillSet = skillset
 	return nil
 }
 
+// sessionInputArgs returns the input args the session's skill is started with. If the session
+// references a context for its input args, the context value is read from the skillset, with
+// the session's context overrides applied.
+func (s *session) sessionInputArgs(ctx context.Context) (map[string]any, apperrors.Error) {
+	if s.context.InputArgsRef == "" {
+		return s.context.InputArgs, nil
+	}
+	if err := s.fetchObjects(ctx); err != nil {
+		return nil, err
+	}
+	if s.skillSet == nil {
+		return nil, ErrUnableToGetSkillset.Msg("skillset not found")
+	}
+	return s.skillSet.GetContextInputArgs(s.context.InputArgsRef, s.viewDef)
+}
+
+// allowedBasis adds the rule basis of an allowed policy decision to an audit event when the
+// session's view audits verbosely. Views in compact mode record allows without their basis.
+func (s *session) allowedBasis(basis map[policy.Intent][]policy.Rule) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		if s.viewDef.IsAuditVerbose() {
+			e.Any("basis", basis)
+		}
+	}
+}
+
+// withLogLevel returns ctx with its logger set to the log level requested for the session, so
+// that the skill's run logs at that level. ctx is returned unchanged if no level was requested.
+func (s *session) withLogLevel(ctx context.Context) context.Context {
+	if s.context == nil || s.context.LogLevel == "" {
+		return ctx
+	}
+	level, err := zerolog.ParseLevel(s.context.LogLevel)
+	if err != nil {
+		return ctx // validated when the session was created
+	}
+	return log.Ctx(ctx).Level(level).WithContext(ctx)
+}
+
+// callStatus returns the call graph status of an invocation that ended with err.
+func callStatus(err error) toolgraph.CallStatus {
+	if err != nil {
+		return toolgraph.CallStatusFailed
+	}
+	return toolgraph.CallStatusCompleted
+}
+
+// resolveDependencies records the skillset dependencies needed by this session. Dependencies
+// whose condition does not match the session variables are skipped.
+func (s *session) resolveDependencies() {
+	if s.skillSet == nil {
+		return
+	}
+	s.dependencies = s.skillSet.ResolveDependencies(s.context.SessionVariables)
+	aliases := make([]string, 0, len(s.dependencies))
+	for _, dep := range s.dependencies {
+		aliases = append(aliases, dep.Alias)
+	}
+	s.logger.Debug().Strs("dependencies", aliases).Msg("resolved skillset dependencies")
+}
+
 // resolveSkill finds and returns a skill by name from the current skillset.
 // Returns an error if the skill is not found or skillset is unavailable.
 func (s *session) resolveSkill(skillName string) (*catalogmanager.Skill, apperrors.Error) {
@@ -444,16 +1009,60 @@ func (s *session) resolveSkill(skillName string) (*catalogmanager.Skill, apperro
 	return &skill, nil
 }
 
-// getSkillset retrieves a skillset manager from the catalog server.
-// Returns the skillset manager and any error encountered during retrieval.
-func getSkillset(ctx context.Context, client httpclient.HTTPClientInterface, skillset string) (catalogmanager.SkillSetManager, apperrors.Error) {
-	response, err := client.GetResource(catcommon.KindNameSkillsets, skillset, nil, "")
+// auditInputArgs returns the input arguments of a skill invocation as they are recorded in the
+// audit log, with the values at the skill's redacted input paths masked. The arguments passed to
+// the skill are not modified. If the skillset cannot be loaded, the arguments are omitted so that
+// values the skill redacts are never logged. The preflight skill receives the input arguments of
+// the skill it precedes under /inputArgs, and these are masked with that skill's redacted paths.
+func (s *session) auditInputArgs(ctx context.Context, skillName string, inputArgs map[string]any) map[string]any {
+	if s.skillSet == nil {
+		if err := s.fetchObjects(ctx); err != nil {
+			return nil
+		}
+	}
+	skill, err := s.resolveSkill(skillName)
+	if err != nil {
+		// tools that are not skills of the skillset have nothing to redact
+		return inputArgs
+	}
+	redacted := skill.RedactInput(inputArgs)
+	if skill.Name != s.skillSet.GetPreflightSkill() {
+		return redacted
+	}
+	invoked, _ := redacted["skill"].(string)
+	invokedArgs, ok := redacted["inputArgs"].(map[string]any)
+	if !ok {
+		return redacted
+	}
+	invokedSkill, err := s.resolveSkill(invoked)
 	if err != nil {
-		httpErr, ok := err.(*httpclient.HTTPError)
-		if ok {
-			return nil, ErrUnableToGetSkillset.Msg(httpErr.Message)
+		return redacted
+	}
+	preflightArgs := maps.Clone(redacted)
+	preflightArgs["inputArgs"] = invokedSkill.RedactInput(invokedArgs)
+	return preflightArgs
+}
+
+// getSkillsetWithCache retrieves a skillset manager from the catalog server, reusing a cached
+// copy of the skillset if one is available. Each call returns a separate manager.
+func getSkillsetWithCache(ctx context.Context, client httpclient.HTTPClientInterface, cache *skillsetCache, key skillsetCacheKey) (catalogmanager.SkillSetManager, apperrors.Error) {
+	response, ok := cache.get(key)
+	if !ok {
+		var err error
+		queryParams := namespaceQuery(key.namespace)
+		if queryParams == nil {
+			queryParams = map[string]string{}
 		}
-		return nil, ErrUnableToGetSkillset.Msg(err.Error())
+		queryParams["fields"] = strings.Join(skillSetFields, ",")
+		response, err = client.GetResource(catcommon.KindNameSkillsets, key.path, queryParams, "")
+		if err != nil {
+			httpErr, ok := err.(*httpclient.HTTPError)
+			if ok {
+				return nil, ErrUnableToGetSkillset.Msg(httpErr.Message)
+			}
+			return nil, ErrUnableToGetSkillset.Msg(err.Error())
+		}
+		cache.put(key, response)
 	}
 
 	// create new skillset manager
@@ -465,6 +1074,28 @@ func getSkillset(ctx context.Context, client httpclient.HTTPClientInterface, ski
 	return sm, nil
 }
 
+// skillSetFields are the skillset spec sections a session uses. Sections only used for
+// authoring, such as annotations, are not fetched.
+var skillSetFields = []string{"sources", "context", "skills", "dependencies", "overrides", "preflight"}
+
+// namespaceQuery returns the query parameters that resolve a catalog object in namespace.
+func namespaceQuery(namespace string) map[string]string {
+	if namespace == "" {
+		return nil
+	}
+	return map[string]string{"namespace": namespace}
+}
+
+// skillSetResourcePath returns the path of the session's skillset that view rules are
+// evaluated against, qualified by the session's namespace.
+func (s *session) skillSetResourcePath() string {
+	var scope policy.Scope
+	if s.viewDef != nil {
+		scope = s.viewDef.Scope
+	}
+	return policy.NamespacedResourcePath(scope, s.context.Namespace, s.skillSet.GetResourcePath())
+}
+
 // getLogger creates a logger instance for the specified event type.
 func (s *session) getLogger(eventType string) zerolog.Logger {
 	return eventlogger.NewLogger(GetEventBus(), s.getTopic(eventType)).With().Str("session_id", s.id.String()).Logger()
@@ -476,14 +1107,15 @@ func (s *session) getTopic(eventType string) string {
 }
 
 // getSkillsAsLLMTools converts available skills to LLM tool format.
+// If category is not empty, only skills in that category are included.
 // Returns the tools array and any error encountered during conversion.
-func (s *session) getSkillsAsLLMTools() ([]api.LLMTool, apperrors.Error) {
+func (s *session) getSkillsAsLLMTools(category string) ([]api.LLMTool, apperrors.Error) {
 	if s.skillSet == nil {
 		return nil, ErrUnableToGetSkillset.Msg("skillset not found")
 	}
 	// We'll return all tools and block it while executing the skill. This will allow LLM to prompt
 	// user to obtain permission or to log tickets to ask for permission.
-	return s.skillSet.GetAllSkillsAsLLMTools(nil), nil
+	return s.skillSet.GetAllSkillsAsLLMTools(nil, category), nil
 }
 
 // getContext retrieves a context value for the specified invocation and name.
@@ -498,6 +1130,18 @@ func (s *session) getContext(invocationID string, name string) (any, apperrors.E
 	}
 	value, err := s.skillSet.GetContextValue(name, s.viewDef)
 	if err != nil {
+		// contexts that fail open yield nil when their value cannot be obtained
+		if ctxDef, defErr := s.skillSet.GetContext(name); defErr == nil && ctxDef.FailsOpen() {
+			s.auditLogInfo.auditLogger.Warn().
+				Str("event", "context_get").
+				Str("invocation_id", invocationID).
+				Str("skill", string(skillName)).
+				Str("context_name", name).
+				Str("status", "fail_open").
+				Err(err).
+				Msg("context value unavailable, failing open")
+			return types.NilAny(), nil
+		}
 		s.auditLogInfo.auditLogger.Error().
 			Str("event", "context_get").
 			Str("invocation_id", invocationID).
@@ -518,6 +1162,59 @@ func (s *session) getContext(invocationID string, name string) (any, apperrors.E
 	return value, err
 }
 
+// getResource returns the value of the resource dependency with the given alias. Inline
+// dependencies are served from the skillset; path dependencies are read from the catalog
+// server with the session's token, so the view still governs access to them.
+func (s *session) getResource(ctx context.Context, invocationID string, alias string) (value any, ret apperrors.Error) {
+	skillName := s.callGraph.GetToolName(toolgraph.CallID(invocationID))
+	if skillName == "" {
+		return nil, ErrUnableToGetSkillset.Msg("invocationID not valid")
+	}
+	defer func() {
+		event := s.auditLogInfo.auditLogger.Info()
+		status := "success"
+		if ret != nil {
+			event = s.auditLogInfo.auditLogger.Error().Err(ret)
+			status = "failed"
+		}
+		event.Str("event", "resource_get").
+			Str("invocation_id", invocationID).
+			Str("skill", string(skillName)).
+			Str("alias", alias).
+			Str("status", status).
+			Msg("dependency resource retrieval")
+	}()
+
+	idx := slices.IndexFunc(s.dependencies, func(dep catalogmanager.Dependency) bool {
+		return dep.Alias == alias && dep.Kind == catalogmanager.KindResource
+	})
+	if idx < 0 {
+		return nil, ErrInvalidObject.Msg("no resource dependency with alias " + alias)
+	}
+	dep := s.dependencies[idx]
+	if !dep.IsRequired(s.context.SessionVariables) {
+		return nil, ErrInvalidObject.Msg("resource dependency " + alias + " is not required by the session variables")
+	}
+
+	if dep.IsInline() {
+		return dep.Inline.Value.Get(), nil
+	}
+
+	client := getHTTPClient(&clientConfig{
+		token:       s.token,
+		tokenExpiry: s.tokenExpiry,
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+	rsp, err := client.GetResource(catcommon.KindNameResources, strings.TrimPrefix(dep.Path, "/"+catcommon.KindNameResources), namespaceQuery(s.context.Namespace), "")
+	if err != nil {
+		return nil, ErrFailedRequestToTansiveServer.Msg(err.Error())
+	}
+	if err := json.Unmarshal(rsp, &value); err != nil {
+		return nil, ErrInvalidObject.Msg("unable to parse resource value: " + err.Error())
+	}
+	return value, nil
+}
+
 var _ = (&session{}).setContext
 
 // setContext stores a context value for the specified invocation and name.
@@ -571,6 +1268,12 @@ func (s *session) Finalize(ctx context.Context, apperr apperrors.Error) apperror
 		log.Ctx(ctx).Error().Msg("audit log not complete after 10 seconds")
 	}
 
+	client := getHTTPClient(&clientConfig{
+		token:       s.token,
+		tokenExpiry: s.tokenExpiry,
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+
 	if auditLogPath != "" {
 		var err error
 		auditLog, err = srvsession.CompressAndEncodeAuditLogFile(auditLogPath)
@@ -579,6 +1282,15 @@ func (s *session) Finalize(ctx context.Context, apperr apperrors.Error) apperror
 		}
 	}
 
+	auditLog, uploadErr := prepareAuditLogForStatus(ctx, client, auditLog)
+	if uploadErr != nil {
+		log.Ctx(ctx).Error().Err(uploadErr).Msg("failed to upload audit log in parts, falling back to single upload")
+	}
+
+	if transcriptErr := s.uploadTranscript(ctx, client); transcriptErr != nil {
+		log.Ctx(ctx).Error().Err(transcriptErr).Msg("failed to upload session transcript")
+	}
+
 	sessionStatus := srvsession.ExecutionStatusUpdate{
 		StatusSummary: srvsession.SessionStatusCompleted,
 		Status: srvsession.ExecutionStatus{
@@ -593,12 +1305,6 @@ func (s *session) Finalize(ctx context.Context, apperr apperrors.Error) apperror
 		}
 	}
 
-	client := getHTTPClient(&clientConfig{
-		token:       s.token,
-		tokenExpiry: s.tokenExpiry,
-		serverURL:   config.Config().TansiveServer.GetURL(),
-	})
-
 	body, err := json.Marshal(sessionStatus)
 	if err != nil {
 		return ErrFailedRequestToTansiveServer.Msg(err.Error())
@@ -612,9 +1318,12 @@ func (s *session) Finalize(ctx context.Context, apperr apperrors.Error) apperror
 
 	_, _, err = client.DoRequest(opts)
 	if err != nil {
+		// keep the persisted state and audit log so the report is retried on recovery
 		return ErrFailedRequestToTansiveServer.Msg(err.Error())
 	}
 
+	// the session is reported to the catalog server and no longer needs recovery
+	s.removeState()
 	return nil
 }
 
@@ -623,6 +1332,12 @@ func (s *session) shipAuditLog(ctx context.Context) apperrors.Error {
 	auditLogPubKey := s.auditLogInfo.auditLogPubKey
 	var auditLog string
 
+	client := getHTTPClient(&clientConfig{
+		token:       s.token,
+		tokenExpiry: s.tokenExpiry,
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+
 	if auditLogPath != "" {
 		var err error
 		auditLog, err = srvsession.CompressAndEncodeAuditLogFile(auditLogPath)
@@ -631,6 +1346,11 @@ func (s *session) shipAuditLog(ctx context.Context) apperrors.Error {
 		}
 	}
 
+	auditLog, apperr := prepareAuditLogForStatus(ctx, client, auditLog)
+	if apperr != nil {
+		log.Ctx(ctx).Error().Err(apperr).Msg("failed to upload audit log in parts, falling back to single upload")
+	}
+
 	sessionStatus := srvsession.ExecutionStatusUpdate{
 		StatusSummary: srvsession.SessionStatusRunning,
 		Status: srvsession.ExecutionStatus{
@@ -639,12 +1359,6 @@ func (s *session) shipAuditLog(ctx context.Context) apperrors.Error {
 		},
 	}
 
-	client := getHTTPClient(&clientConfig{
-		token:       s.token,
-		tokenExpiry: s.tokenExpiry,
-		serverURL:   config.Config().TansiveServer.GetURL(),
-	})
-
 	body, err := json.Marshal(sessionStatus)
 	if err != nil {
 		return ErrFailedRequestToTansiveServer.Msg(err.Error())
@@ -671,10 +1385,38 @@ func (s *session) Stop(ctx context.Context, apperr apperrors.Error) apperrors.Er
 	if s.mcpSession.random != "" {
 		mcpservice.StopMCPSession(ctx, s.mcpSession.random)
 	}
-	for _, cancel := range s.skillCancelers {
-		cancel()
+	if s.mcpSession.span != nil {
+		s.endSkillSpan(s.mcpSession.invocationID, s.mcpSession.span, nil)
 	}
+	s.cancelSkills(false)
 	s.auditLogInfo.auditLogCancel()
 	s.Finalize(ctx, apperr)
 	return nil
 }
+
+// addSkillCanceler registers the cancel function of a running skill, so that the skill is
+// cancelled when the session is stopped. Skills may not start once the session is revoked.
+func (s *session) addSkillCanceler(cancel context.CancelFunc) apperrors.Error {
+	s.skillsMu.Lock()
+	defer s.skillsMu.Unlock()
+	if s.revoked {
+		return ErrSessionRevoked
+	}
+	s.skillCancelers = append(s.skillCancelers, cancel)
+	return nil
+}
+
+// cancelSkills cancels the running skills of the session. If revoke is set, skills that have
+// not started yet are also prevented from running.
+func (s *session) cancelSkills(revoke bool) {
+	s.skillsMu.Lock()
+	if revoke {
+		s.revoked = true
+	}
+	cancelers := slices.Clone(s.skillCancelers)
+	s.skillsMu.Unlock()
+
+	for _, cancel := range cancelers {
+		cancel()
+	}
+}
@@ -0,0 +1,1358 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/catalogsrv/policy"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/jsruntime"
+	"github.com/tansive/tansive/internal/common/uuid"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners"
+	"github.com/tansive/tansive/internal/tangent/runners/stdiorunner"
+	"github.com/tansive/tansive/internal/tangent/session/toolgraph"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tansive/tansive/pkg/api"
+	"github.com/tansive/tansive/pkg/types"
+	"github.com/tidwall/sjson"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newTestSession creates a session backed by the test skillset without contacting the catalog server.
+func newTestSession(t *testing.T, viewDef *policy.ViewDefinition) *session {
+	t.Helper()
+	sm, err := catalogmanager.SkillSetManagerFromJSON(context.Background(), test.SkillsetDef("dev"))
+	require.NoError(t, err)
+	logger := log.With().Str("test", t.Name()).Logger()
+	return &session{
+		id: uuid.New(),
+		context: &ServerContext{
+			View:           "dev-view",
+			ViewDefinition: viewDef,
+		},
+		skillSet:      sm,
+		viewDef:       viewDef,
+		callGraph:     toolgraph.NewCallGraph(3),
+		invocationIDs: make(map[string]*policy.ViewDefinition),
+		logger:        &logger,
+		resultCache:   newResultCache(),
+	}
+}
+
+func TestValidateRunPolicyBlockedSkills(t *testing.T) {
+	ctx := context.Background()
+	viewDef := test.GetViewDefinition("dev")
+	viewDef.BlockedSkills = []string{"restart_deployment"}
+	s := newTestSession(t, viewDef)
+
+	// restart_deployment is granted by the view's actions but is blocked
+	allowed, basis, actions, err := s.ValidateRunPolicy(ctx, "", "restart_deployment")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Nil(t, basis)
+	assert.Equal(t, []string{"kubernetes.deployments.restart"}, actions)
+
+	msg, reason := s.blockedByPolicyMessage("restart_deployment", actions, basis)
+	assert.Equal(t, "skill_blocked", reason)
+	assert.Contains(t, msg, "restart_deployment")
+
+	// list_pods is not blocked and proceeds to action evaluation
+	allowed, _, _, err = s.ValidateRunPolicy(ctx, "", "list_pods")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	t.Run("renamed skill stays blocked under its old name", func(t *testing.T) {
+		def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.1.name", "bounce_deployment")
+		require.NoError(t, err)
+		def, err = sjson.SetBytes(def, "spec.skills.1.aliases", []string{"restart_deployment"})
+		require.NoError(t, err)
+		sm, apperr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+		require.NoError(t, apperr)
+		s.skillSet = sm
+
+		for _, name := range []string{"bounce_deployment", "restart_deployment"} {
+			allowed, _, actions, err := s.ValidateRunPolicy(ctx, "", name)
+			require.NoError(t, err)
+			assert.False(t, allowed, name)
+			_, reason := s.blockedByPolicyMessage(name, actions, nil)
+			assert.Equal(t, "skill_blocked", reason, name)
+		}
+	})
+}
+
+func TestBlockedByPolicyDetails(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deny rule", func(t *testing.T) {
+		viewDef := test.GetViewDefinition("dev")
+		denyRule := policy.Rule{
+			Intent:  policy.IntentDeny,
+			Actions: []policy.Action{"kubernetes.deployments.restart"},
+			Targets: []policy.TargetResource{"res://skillsets/skillsets/kubernetes-demo"},
+		}
+		viewDef.Rules = append(viewDef.Rules, denyRule)
+		s := newTestSession(t, viewDef)
+
+		allowed, basis, actions, err := s.ValidateRunPolicy(ctx, "", "restart_deployment")
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		msg, _ := s.blockedByPolicyMessage("restart_deployment", actions, basis)
+		rsp, err := processOutput(tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter(),
+			s.blockedByPolicyError(msg, "restart_deployment", actions, basis), "")
+		require.NoError(t, err)
+		assert.Contains(t, rsp["error"], "blocked by Tansive policy")
+
+		block, ok := rsp["blocked"].(*PolicyBlock)
+		require.True(t, ok)
+		assert.Equal(t, "restart_deployment", block.Skill)
+		assert.Equal(t, []string{"kubernetes.deployments.restart"}, block.RequiredActions)
+		assert.Equal(t, "dev-view", block.View)
+		assert.Equal(t, PolicyBlockDeniedByRule, block.Reason)
+		require.Len(t, block.DenyRules, 1)
+		assert.Equal(t, policy.IntentDeny, block.DenyRules[0].Intent)
+
+		b, jsonErr := json.Marshal(rsp["blocked"])
+		require.NoError(t, jsonErr)
+		assert.JSONEq(t, `{
+			"skill": "restart_deployment",
+			"requiredActions": ["kubernetes.deployments.restart"],
+			"view": "dev-view",
+			"reason": "denied_by_rule",
+			"denyRules": [{"intent": "Deny", "actions": ["kubernetes.deployments.restart"], "targets": ["res://catalogs/test-catalog/variants/dev/skillsets/skillsets/kubernetes-demo"]}]
+		}`, string(b))
+	})
+
+	t.Run("deny rule with reason", func(t *testing.T) {
+		config.SetTestMode(true)
+		config.TestInit(t)
+		viewDef := test.GetViewDefinition("dev")
+		viewDef.Rules = append(viewDef.Rules, policy.Rule{
+			Intent:  policy.IntentDeny,
+			Actions: []policy.Action{"kubernetes.deployments.restart"},
+			Targets: []policy.TargetResource{"res://skillsets/skillsets/kubernetes-demo"},
+			Reason:  "restarts are frozen during the release window",
+		})
+		s := newTestSession(t, viewDef)
+		created := useTestRunner(t, &fakeRunner{})
+
+		err := s.Run(ctx, "", "restart_deployment", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		require.ErrorIs(t, err, ErrBlockedByPolicy)
+		assert.Equal(t, 0, *created)
+		assert.Contains(t, err.Error(), "reason: restarts are frozen during the release window")
+
+		block, ok := GetPolicyBlock(err)
+		require.True(t, ok)
+		assert.Equal(t, PolicyBlockDeniedByRule, block.Reason)
+		assert.Equal(t, []string{"restarts are frozen during the release window"}, block.DenyReasons)
+		require.Len(t, block.DenyRules, 1)
+		assert.Equal(t, "restarts are frozen during the release window", block.DenyRules[0].Reason)
+	})
+
+	t.Run("no matching allow", func(t *testing.T) {
+		// the prod view does not grant kubernetes.deployments.restart
+		s := newTestSession(t, test.GetViewDefinition("prod"))
+
+		allowed, basis, actions, err := s.ValidateRunPolicy(ctx, "", "restart_deployment")
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		msg, _ := s.blockedByPolicyMessage("restart_deployment", actions, basis)
+		blockErr := s.blockedByPolicyError(msg, "restart_deployment", actions, basis)
+		assert.ErrorIs(t, blockErr, ErrBlockedByPolicy)
+
+		rsp, err := processOutput(tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter(), blockErr, "")
+		require.NoError(t, err)
+		block, ok := rsp["blocked"].(*PolicyBlock)
+		require.True(t, ok)
+		assert.Equal(t, "restart_deployment", block.Skill)
+		assert.Equal(t, []string{"kubernetes.deployments.restart"}, block.RequiredActions)
+		assert.Equal(t, "dev-view", block.View)
+		assert.Equal(t, PolicyBlockNoMatchingAllow, block.Reason)
+		assert.Empty(t, block.DenyRules)
+	})
+
+	t.Run("other errors carry no details", func(t *testing.T) {
+		rsp, err := processOutput(tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter(), ErrSessionError.Msg("failed"), "")
+		require.NoError(t, err)
+		assert.NotContains(t, rsp, "blocked")
+	})
+}
+
+func TestSplitAuditLog(t *testing.T) {
+	auditLog := "abcdefghij"
+
+	assert.Equal(t, []string{auditLog}, splitAuditLog(auditLog, 10))
+	assert.Equal(t, []string{auditLog}, splitAuditLog(auditLog, 0))
+
+	parts := splitAuditLog(auditLog, 3)
+	assert.Equal(t, []string{"abc", "def", "ghi", "j"}, parts)
+	assert.Equal(t, auditLog, strings.Join(parts, ""))
+}
+
+func TestSkillSpansFollowCallGraph(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	setTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer setTracerProvider(noop.NewTracerProvider())
+
+	ctx := context.Background()
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+
+	// root -> child -> grandchild, plus a second root-level call
+	calls := []struct {
+		invokerID    string
+		invocationID string
+		skill        string
+	}{
+		{"", "inv-root", "list_pods"},
+		{"inv-root", "inv-child", "restart_deployment"},
+		{"inv-child", "inv-grandchild", "check_health"},
+		{"", "inv-other", "list_pods"},
+	}
+	// invokers stay running while their children run, so spans are ended in reverse order
+	started := make([]trace.Span, len(calls))
+	for i, c := range calls {
+		spanCtx, span := s.startSkillSpan(ctx, c.invokerID, c.invocationID, c.skill)
+		require.NoError(t, s.callGraph.RegisterCall(toolgraph.CallID(c.invokerID), toolgraph.ToolName(c.skill), toolgraph.CallID(c.invocationID)))
+		setSpanPolicyDecision(spanCtx, "allowed", "")
+		setSpanRunner(spanCtx, "system.stdiorunner")
+		started[i] = span
+	}
+	for i := len(calls) - 1; i >= 0; i-- {
+		s.endSkillSpan(calls[i].invocationID, started[i], nil)
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, len(calls))
+	s.invocationSpans.Range(func(key, _ any) bool {
+		t.Errorf("span of invocation %v not released after it ended", key)
+		return true
+	})
+
+	spanIDs := map[string]trace.SpanID{}
+	for _, span := range spans {
+		attrs := map[attribute.Key]string{}
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value.AsString()
+		}
+		assert.Equal(t, "allowed", attrs["tansive.policy.decision"])
+		assert.Equal(t, "system.stdiorunner", attrs["tansive.skill.runner"])
+		spanIDs[attrs["tansive.skill.invocation_id"]] = span.SpanContext().SpanID()
+	}
+
+	parents := map[toolgraph.CallID]toolgraph.CallID{}
+	var collectParents func(parentID toolgraph.CallID, nodes []*toolgraph.CallNode)
+	collectParents = func(parentID toolgraph.CallID, nodes []*toolgraph.CallNode) {
+		for _, node := range nodes {
+			parents[node.CallID] = parentID
+			collectParents(node.CallID, node.Calls)
+		}
+	}
+	collectParents("", s.callGraph.Tree())
+
+	for _, span := range spans {
+		var invocationID string
+		for _, kv := range span.Attributes() {
+			if kv.Key == "tansive.skill.invocation_id" {
+				invocationID = kv.Value.AsString()
+			}
+		}
+		parentID := parents[toolgraph.CallID(invocationID)]
+		if parentID == "" {
+			assert.False(t, span.Parent().IsValid(), "root invocation %s should have no parent span", invocationID)
+			continue
+		}
+		assert.Equal(t, spanIDs[string(parentID)], span.Parent().SpanID(), "parent span of %s", invocationID)
+		assert.Equal(t, span.Parent().TraceID(), span.SpanContext().TraceID())
+	}
+}
+
+// outputRunner is a runner that writes a fixed output.
+type outputRunner struct {
+	fakeRunner
+	output string
+}
+
+func (r *outputRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	for _, w := range r.writers {
+		w.Out.Write([]byte(r.output))
+	}
+	return nil
+}
+
+func TestRunValidatesOutput(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	newSession := func(t *testing.T, validateOutput bool) *session {
+		def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.validateOutput", validateOutput)
+		require.NoError(t, err)
+		def, err = sjson.SetRawBytes(def, "spec.skills.0.outputSchema", []byte(`{"type": "object", "properties": {"pods": {"type": "array"}}, "required": ["pods"]}`))
+		require.NoError(t, err)
+		sm, err := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+		require.NoError(t, err)
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		return s
+	}
+
+	run := func(s *session) (string, apperrors.Error) {
+		out := tangentcommon.NewBufferedWriter()
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: out,
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		return out.String(), err
+	}
+
+	t.Run("conforming output", func(t *testing.T) {
+		s := newSession(t, true)
+		useTestRunner(t, &outputRunner{output: `{"pods": ["api-server"]}`})
+		out, err := run(s)
+		require.NoError(t, err)
+		assert.Equal(t, `{"pods": ["api-server"]}`, out)
+	})
+
+	t.Run("non-conforming output fails the invocation", func(t *testing.T) {
+		s := newSession(t, true)
+		useTestRunner(t, &outputRunner{output: `{"items": []}`})
+		_, err := run(s)
+		assert.ErrorIs(t, err, ErrInvalidSkillOutput)
+		assert.ErrorIs(t, err, catalogmanager.ErrInvalidOutput)
+	})
+
+	t.Run("output is not validated unless enabled", func(t *testing.T) {
+		s := newSession(t, false)
+		useTestRunner(t, &outputRunner{output: "NAME READY STATUS"})
+		out, err := run(s)
+		require.NoError(t, err)
+		assert.Equal(t, "NAME READY STATUS", out)
+	})
+}
+
+// countingRunner is a runner that counts its runs and writes the run number.
+type countingRunner struct {
+	fakeRunner
+	runs atomic.Int32
+}
+
+func (r *countingRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	n := r.runs.Add(1)
+	for _, w := range r.writers {
+		w.Out.Write([]byte("run " + strconv.Itoa(int(n))))
+	}
+	return nil
+}
+
+func TestRunCachesResults(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.cacheable", true)
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.skills.0.cacheTTL", "1m")
+	require.NoError(t, err)
+	sm, apperr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, apperr)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+	now := time.Now()
+	s.resultCache.now = func() time.Time { return now }
+
+	runner := &countingRunner{}
+	useTestRunner(t, runner)
+	run := func(inputArgs map[string]any) (string, apperrors.Error) {
+		out := tangentcommon.NewBufferedWriter()
+		err := s.Run(ctx, "", "list_pods", inputArgs, &tangentcommon.IOWriters{
+			Out: out,
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		return out.String(), err
+	}
+
+	out, err := run(map[string]any{"labelSelector": "app=api"})
+	require.NoError(t, err)
+	assert.Equal(t, "run 1", out)
+
+	t.Run("cache hit does not invoke the runner", func(t *testing.T) {
+		out, err := run(map[string]any{"labelSelector": "app=api"})
+		require.NoError(t, err)
+		assert.Equal(t, "run 1", out)
+		assert.EqualValues(t, 1, runner.runs.Load())
+	})
+
+	t.Run("different input is not a hit", func(t *testing.T) {
+		out, err := run(map[string]any{"labelSelector": "app=web"})
+		require.NoError(t, err)
+		assert.Equal(t, "run 2", out)
+	})
+
+	t.Run("policy is validated on a cache hit", func(t *testing.T) {
+		s.viewDef.BlockedSkills = []string{"list_pods"}
+		defer func() { s.viewDef.BlockedSkills = nil }()
+		_, err := run(map[string]any{"labelSelector": "app=api"})
+		assert.ErrorIs(t, err, ErrBlockedByPolicy)
+	})
+
+	t.Run("expired result is recomputed", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		out, err := run(map[string]any{"labelSelector": "app=api"})
+		require.NoError(t, err)
+		assert.Equal(t, "run 3", out)
+		assert.EqualValues(t, 3, runner.runs.Load())
+	})
+}
+
+func TestResultCacheBounds(t *testing.T) {
+	now := time.Now()
+	newCache := func() *resultCache {
+		return &resultCache{maxEntries: 2, maxBytes: 10, maxTTL: time.Minute, now: func() time.Time { return now }}
+	}
+
+	t.Run("results closest to expiry are evicted when full", func(t *testing.T) {
+		c := newCache()
+		c.put("a", []byte("1"), nil, 10*time.Second)
+		c.put("b", []byte("2"), nil, 20*time.Second)
+		c.put("c", []byte("3"), nil, 30*time.Second)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		_, ok = c.get("b")
+		assert.True(t, ok)
+		_, ok = c.get("c")
+		assert.True(t, ok)
+	})
+
+	t.Run("total size is bounded", func(t *testing.T) {
+		c := newCache()
+		c.put("a", []byte("123456"), nil, time.Minute)
+		c.put("b", []byte("123"), []byte("456"), time.Minute)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		_, ok = c.get("b")
+		assert.True(t, ok)
+		assert.Equal(t, 6, c.size)
+
+		c.put("c", []byte("too large to cache"), nil, time.Minute)
+		_, ok = c.get("c")
+		assert.False(t, ok)
+		assert.Equal(t, 6, c.size)
+	})
+
+	t.Run("ttl is capped", func(t *testing.T) {
+		c := newCache()
+		c.put("a", []byte("1"), nil, time.Hour)
+		now = now.Add(2 * time.Minute)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		assert.Zero(t, c.size)
+	})
+}
+
+func TestRunCachesOnlyFinalAttempt(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.cacheable", true)
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.skills.0.cacheTTL", "1m")
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.skills.0.maxRestarts", 1)
+	require.NoError(t, err)
+	sm, apperr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, apperr)
+
+	t.Run("output of a crashed attempt is not cached", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		useTestRunner(t, &crashingRunner{crashes: 1})
+
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		require.NoError(t, err)
+		require.Len(t, s.resultCache.entries, 1)
+		for _, entry := range s.resultCache.entries {
+			assert.Equal(t, `{"pods": []}`, string(entry.stdout))
+			assert.Empty(t, entry.stderr)
+		}
+	})
+
+	t.Run("failed run is not cached", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		useTestRunner(t, &crashingRunner{crashes: 2})
+
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		assert.ErrorIs(t, err, stdiorunner.ErrProcessCrashed)
+		assert.Empty(t, s.resultCache.entries)
+	})
+}
+
+func TestGetRunnerAppliesEnvironmentOverrides(t *testing.T) {
+	ctx := context.Background()
+	def, err := sjson.SetRawBytes(test.SkillsetDef("dev"), "spec.overrides",
+		[]byte(`{"prod": {"my-tools-script": {"script": "prod_tools.sh", "env": {"TEST_VAR": "prod_value"}}}}`))
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+
+	var runnerDef catalogmanager.SkillSetSource
+	orig := newRunner
+	t.Cleanup(func() { newRunner = orig })
+	newRunner = func(ctx context.Context, sessionID string, def catalogmanager.SkillSetSource, sessionVariables map[string]any, writers ...*tangentcommon.IOWriters) (runners.Runner, apperrors.Error) {
+		runnerDef = def
+		return &fakeRunner{}, nil
+	}
+
+	t.Run("override applied for the selected environment", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		s.context.Environment = "prod"
+
+		_, err := s.getRunner(ctx, "list_pods")
+		require.NoError(t, err)
+		assert.Equal(t, "prod_tools.sh", runnerDef.Config["script"])
+		assert.Equal(t, map[string]any{"TEST_VAR": "prod_value"}, runnerDef.Config["env"])
+		assert.Equal(t, "bash", runnerDef.Config["runtime"])
+	})
+
+	t.Run("override ignored for other environments", func(t *testing.T) {
+		for _, env := range []string{"", "staging"} {
+			s := newTestSession(t, test.GetViewDefinition("dev"))
+			s.skillSet = sm
+			s.context.Environment = env
+
+			_, err := s.getRunner(ctx, "list_pods")
+			require.NoError(t, err)
+			assert.Equal(t, "tools_script.sh", runnerDef.Config["script"], "environment %q", env)
+			assert.Equal(t, map[string]any{"TEST_VAR": "test_value"}, runnerDef.Config["env"], "environment %q", env)
+		}
+	})
+}
+
+func TestResolveDependencies(t *testing.T) {
+	def, err := sjson.SetRawBytes(test.SkillsetDef("dev"), "spec.dependencies", []byte(`[
+		{"path": "/resources/kubeconfig", "kind": "Resource", "alias": "kubeconfig", "actions": ["system.resource.read"]},
+		{"path": "/resources/prod-db", "kind": "Resource", "alias": "prod-db", "actions": ["system.resource.read"], "when": {"variable": "mode", "equals": "prod"}}
+	]`))
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(context.Background(), def)
+	require.NoError(t, appErr)
+
+	aliases := func(s *session) []string {
+		names := []string{}
+		for _, dep := range s.dependencies {
+			names = append(names, dep.Alias)
+		}
+		return names
+	}
+
+	t.Run("dependency included when the condition matches", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		s.context.SessionVariables = map[string]any{"mode": "prod"}
+		s.resolveDependencies()
+		assert.Equal(t, []string{"kubeconfig", "prod-db"}, aliases(s))
+	})
+
+	t.Run("dependency skipped when the condition does not match", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		s.context.SessionVariables = map[string]any{"mode": "dev"}
+		s.resolveDependencies()
+		assert.Equal(t, []string{"kubeconfig"}, aliases(s))
+	})
+}
+
+// resourceRunner is a runner that fetches a dependency from the session while it runs and
+// writes the value to its output.
+type resourceRunner struct {
+	fakeRunner
+	session *session
+	alias   string
+}
+
+func (r *resourceRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	value, err := r.session.getResource(ctx, args.InvocationID, r.alias)
+	if err != nil {
+		return err
+	}
+	out, _ := json.Marshal(value)
+	for _, w := range r.writers {
+		w.Out.Write(out)
+	}
+	return nil
+}
+
+func TestInlineDependencyServedToRunner(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTestCatalog(t)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetRawBytes(test.SkillsetDef("dev"), "spec.dependencies", []byte(`[
+		{"kind": "Resource", "alias": "kubeconfig", "actions": ["system.resource.read"],
+		 "inline": {"schema": {"type": "object"}, "value": {"cluster": "kind-dev"}}}
+	]`))
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+	s.resolveDependencies()
+
+	run := func(alias string) (string, apperrors.Error) {
+		useTestRunner(t, &resourceRunner{session: s, alias: alias})
+		out := tangentcommon.NewBufferedWriter()
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: out,
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		return out.String(), err
+	}
+
+	out, runErr := run("kubeconfig")
+	require.NoError(t, runErr)
+	assert.JSONEq(t, `{"cluster": "kind-dev"}`, out)
+
+	_, runErr = run("prod-db")
+	assert.ErrorIs(t, runErr, ErrInvalidObject)
+
+	// a conditional dependency is not served once the session variables no longer match
+	def, err = sjson.SetRawBytes(def, "spec.dependencies.1", []byte(`
+		{"kind": "Resource", "alias": "prod-db", "actions": ["system.resource.read"],
+		 "when": {"variable": "env", "equals": "prod"},
+		 "inline": {"value": {"host": "db.prod"}}}`))
+	require.NoError(t, err)
+	s.skillSet, appErr = catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+	s.context.SessionVariables = map[string]any{"env": "prod"}
+	s.resolveDependencies()
+
+	out, runErr = run("prod-db")
+	require.NoError(t, runErr)
+	assert.JSONEq(t, `{"host": "db.prod"}`, out)
+
+	s.context.SessionVariables = map[string]any{"env": "dev"}
+	_, runErr = run("prod-db")
+	assert.ErrorIs(t, runErr, ErrInvalidObject)
+}
+
+func TestRunnerCapabilities(t *testing.T) {
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.sources.1.runner", catcommon.PythonRunnerID)
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(context.Background(), def)
+	require.NoError(t, appErr)
+
+	t.Run("supported runners pass the check", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		assert.NoError(t, s.checkRunnerCapabilities())
+	})
+
+	t.Run("missing runner is reported", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		err := s.checkRunnerCapabilities()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrRunnerNotSupported)
+		assert.Contains(t, err.Error(), catcommon.PythonRunnerID)
+	})
+
+	t.Run("getRunner rejects unsupported runner", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		_, err := s.getRunner(context.Background(), "list_pods")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrRunnerNotSupported)
+	})
+}
+
+// concurrencyRunner is a runner that records the largest number of invocations running at once.
+type concurrencyRunner struct {
+	fakeRunner
+	active    atomic.Int32
+	maxActive atomic.Int32
+}
+
+func (r *concurrencyRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	n := r.active.Add(1)
+	defer r.active.Add(-1)
+	for {
+		m := r.maxActive.Load()
+		if n <= m || r.maxActive.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	return nil
+}
+
+func TestSkillMaxConcurrent(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTestCatalog(t)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.maxConcurrent", 1)
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+
+	runner := &concurrencyRunner{}
+	useTestRunner(t, runner)
+
+	var wg sync.WaitGroup
+	errs := make([]apperrors.Error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+				Out: tangentcommon.NewBufferedWriter(),
+				Err: tangentcommon.NewBufferedWriter(),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), runner.maxActive.Load(), "invocations of a maxConcurrent:1 skill must be serialized")
+}
+
+// crashingRunner is a runner whose process crashes the given number of times and then succeeds.
+type crashingRunner struct {
+	fakeRunner
+	crashes       int
+	invocationIDs []string
+}
+
+func (r *crashingRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	r.invocationIDs = append(r.invocationIDs, args.InvocationID)
+	if len(r.invocationIDs) <= r.crashes {
+		for _, w := range r.writers {
+			w.Out.Write([]byte(`{"pods": [`))
+			w.Err.Write([]byte("segmentation fault"))
+		}
+		return stdiorunner.ErrProcessCrashed.Msg("command execution failed: exit status 1")
+	}
+	for _, w := range r.writers {
+		w.Out.Write([]byte(`{"pods": []}`))
+	}
+	return nil
+}
+
+func TestSkillMaxRestarts(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	newSession := func(maxRestarts int) (*session, *strings.Builder) {
+		def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.maxRestarts", maxRestarts)
+		require.NoError(t, err)
+		sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+		require.NoError(t, appErr)
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		auditLog := &strings.Builder{}
+		s.auditLogInfo.auditLogger = zerolog.New(auditLog)
+		return s, auditLog
+	}
+	restarts := func(auditLog *strings.Builder) []map[string]any {
+		var entries []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(auditLog.String()), "\n") {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			if entry["event"] == "runner_restart" {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+
+	t.Run("crashed runner is restarted", func(t *testing.T) {
+		s, auditLog := newSession(2)
+		runner := &crashingRunner{crashes: 1}
+		useTestRunner(t, runner)
+
+		out, errOut := tangentcommon.NewBufferedWriter(), tangentcommon.NewBufferedWriter()
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: out,
+			Err: errOut,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `{"pods": []}`, out.String(), "output of the crashed attempt is discarded")
+		assert.Empty(t, errOut.String())
+
+		require.Len(t, runner.invocationIDs, 2)
+		assert.Equal(t, runner.invocationIDs[0], runner.invocationIDs[1], "a restart keeps the invocation ID")
+
+		entries := restarts(auditLog)
+		require.Len(t, entries, 1)
+		assert.Equal(t, runner.invocationIDs[0], entries[0]["invocation_id"])
+		assert.Equal(t, float64(1), entries[0]["restart"])
+		assert.Equal(t, float64(2), entries[0]["max_restarts"])
+		assert.Equal(t, toolgraph.CallStatusCompleted, s.callGraph.Tree()[0].Status)
+	})
+
+	t.Run("restarts are off by default", func(t *testing.T) {
+		s, auditLog := newSession(0)
+		runner := &crashingRunner{crashes: 1}
+		useTestRunner(t, runner)
+
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		assert.ErrorIs(t, err, stdiorunner.ErrProcessCrashed)
+		assert.Len(t, runner.invocationIDs, 1)
+		assert.Empty(t, restarts(auditLog))
+	})
+
+	t.Run("session fails once restarts are exhausted", func(t *testing.T) {
+		s, auditLog := newSession(1)
+		runner := &crashingRunner{crashes: 3}
+		useTestRunner(t, runner)
+
+		errOut := tangentcommon.NewBufferedWriter()
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: errOut,
+		})
+		assert.ErrorIs(t, err, stdiorunner.ErrProcessCrashed)
+		assert.Len(t, runner.invocationIDs, 2)
+		assert.Len(t, restarts(auditLog), 1)
+		assert.Equal(t, "segmentation fault", errOut.String(), "only the output of the last attempt is kept")
+	})
+
+	t.Run("other failures are not retried", func(t *testing.T) {
+		s, _ := newSession(2)
+		failing := &failingRunner{err: stdiorunner.ErrMemoryLimitExceeded.Msg("command exceeded memory limit of 1 MB")}
+		useTestRunner(t, failing)
+
+		err := s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		assert.ErrorIs(t, err, stdiorunner.ErrMemoryLimitExceeded)
+		assert.Equal(t, 1, failing.runs)
+	})
+}
+
+// failingRunner is a runner that always fails with err.
+type failingRunner struct {
+	fakeRunner
+	err  apperrors.Error
+	runs int
+}
+
+func (r *failingRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	r.runs++
+	return r.err
+}
+
+func TestTransformSizeLimits(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+	orig := config.Config().Transform
+	t.Cleanup(func() { config.Config().Transform = orig })
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.transform",
+		"function(session, input) { return { labelSelector: (input.labelSelector || '').repeat(50) }; }")
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+
+	applied, args, appErr := s.TransformInputForSkill(ctx, "list_pods", map[string]any{"labelSelector": "a"}, "")
+	require.NoError(t, appErr)
+	assert.True(t, applied)
+	assert.Len(t, args["labelSelector"], 50)
+
+	config.Config().Transform.MaxOutputBytes = 100
+	_, _, appErr = s.TransformInputForSkill(ctx, "list_pods", map[string]any{"labelSelector": "app=web"}, "")
+	assert.ErrorIs(t, appErr, jsruntime.ErrJSOutputTooLarge)
+
+	config.Config().Transform.MaxInputBytes = 10
+	_, _, appErr = s.TransformInputForSkill(ctx, "list_pods", map[string]any{"labelSelector": "app=web"}, "")
+	assert.ErrorIs(t, appErr, jsruntime.ErrJSInputTooLarge)
+}
+
+func TestTransformNumberMode(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+	orig := config.Config().Transform
+	t.Cleanup(func() { config.Config().Transform = orig })
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.transform",
+		"function(session, input) { return { labelSelector: input.labelSelector, replicas: 2.0 }; }")
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+
+	_, args, appErr := s.TransformInputForSkill(ctx, "list_pods", map[string]any{"labelSelector": "app=web"}, "")
+	require.NoError(t, appErr)
+	assert.Equal(t, int64(2), args["replicas"])
+
+	config.Config().Transform.NumberMode = "alwaysFloat"
+	_, args, appErr = s.TransformInputForSkill(ctx, "list_pods", map[string]any{"labelSelector": "app=web"}, "")
+	require.NoError(t, appErr)
+	assert.Equal(t, 2.0, args["replicas"])
+}
+
+func TestTransformChainLength(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+	orig := config.Config().Transform
+	t.Cleanup(func() { config.Config().Transform = orig })
+
+	// list_pods is preceded by the authorize preflight skill, and both have a transform, so an
+	// invocation of list_pods runs two transforms
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.-1", map[string]any{
+		"name":            "authorize",
+		"source":          "my-tools-script",
+		"inputSchema":     map[string]any{"type": "object"},
+		"transform":       "function(session, input) { return input; }",
+		"exportedActions": []string{"kubernetes.pods.list"},
+	})
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.preflight", "authorize")
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.skills.0.transform", "function(session, input) { return input; }")
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+
+	run := func(t *testing.T) (*preflightTestRunner, apperrors.Error) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		runner := &preflightTestRunner{preflightOutput: `{"approved": true}`}
+		useTestRunner(t, runner)
+		return runner, s.Run(ctx, "", "list_pods", map[string]any{"labelSelector": "app=web"}, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+	}
+
+	t.Run("chain at the limit runs", func(t *testing.T) {
+		config.Config().Transform.MaxChainLength = 2
+		runner, err := run(t)
+		require.NoError(t, err)
+		assert.Len(t, runner.runs, 2)
+	})
+
+	t.Run("chain over the limit is rejected", func(t *testing.T) {
+		config.Config().Transform.MaxChainLength = 1
+		runner, err := run(t)
+		assert.ErrorIs(t, err, ErrTransformChainTooLong)
+		// the preflight skill used up the chain, so list_pods never runs
+		require.Len(t, runner.runs, 1)
+		assert.Equal(t, "authorize", runner.runs[0].SkillName)
+	})
+}
+
+func TestContextOverrides(t *testing.T) {
+	ctx := context.Background()
+	client := &countingSkillsetClient{document: test.SkillsetDef("dev")}
+	cache := newSkillsetCache(time.Minute, 10)
+	override := map[string]any{"kubeconfig": "b3ZlcnJpZGRlbg=="}
+
+	loadSession := func(overrides map[string]any) (*session, apperrors.Error) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = nil
+		s.context.SkillSet = "/skillsets/kubernetes-demo"
+		s.context.ContextOverrides = overrides
+		if err := s.loadSkillSet(ctx, client, cache); err != nil {
+			return nil, err
+		}
+		require.NoError(t, s.callGraph.RegisterCall("", "list_pods", "call-1"))
+		return s, nil
+	}
+
+	overridden, err := loadSession(map[string]any{"kubeconfig": override})
+	require.NoError(t, err)
+	value, err := overridden.getContext("call-1", "kubeconfig")
+	require.NoError(t, err)
+	assert.Equal(t, override, value.(types.NullableAny).Get())
+
+	// the stored skillset and other sessions keep the original value
+	plain, err := loadSession(nil)
+	require.NoError(t, err)
+	value, err = plain.getContext("call-1", "kubeconfig")
+	require.NoError(t, err)
+	assert.NotEqual(t, override, value.(types.NullableAny).Get())
+	assert.Equal(t, 1, client.fetches)
+
+	// overrides are validated against the context schema
+	_, err = loadSession(map[string]any{"kubeconfig": map[string]any{"kubeconfig": 42}})
+	assert.ErrorIs(t, err, ErrInvalidObject)
+	_, err = loadSession(map[string]any{"missing": "value"})
+	assert.ErrorIs(t, err, ErrInvalidObject)
+}
+
+func TestSessionInputArgsRef(t *testing.T) {
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.context.-1", map[string]any{
+		"name":   "restart-input",
+		"schema": map[string]any{"type": "object"},
+		"value":  map[string]any{"deployment": "web"},
+	})
+	require.NoError(t, err)
+	def, err = sjson.SetBytes(def, "spec.context.-1", map[string]any{
+		"name":   "scalar-input",
+		"schema": map[string]any{"type": "string"},
+		"value":  "web",
+	})
+	require.NoError(t, err)
+	sm, err := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, err)
+
+	newSession := func(ref string) *session {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		s.context.Skill = "restart_deployment"
+		s.context.InputArgs = map[string]any{"deployment": "inline"}
+		s.context.InputArgsRef = ref
+		return s
+	}
+
+	// inline input args are used when no context is referenced
+	inputArgs, apperr := newSession("").sessionInputArgs(ctx)
+	require.NoError(t, apperr)
+	assert.Equal(t, map[string]any{"deployment": "inline"}, inputArgs)
+
+	// the referenced context value is used and is valid input for the skill
+	inputArgs, apperr = newSession("restart-input").sessionInputArgs(ctx)
+	require.NoError(t, apperr)
+	assert.Equal(t, map[string]any{"deployment": "web"}, inputArgs)
+	skill, apperr := sm.GetSkill("restart_deployment")
+	require.NoError(t, apperr)
+	assert.NoError(t, skill.ValidateInput(skill.ApplyDefaultInputArgs(skill.NormalizeInput(inputArgs))))
+
+	_, apperr = newSession("missing-input").sessionInputArgs(ctx)
+	assert.ErrorIs(t, apperr, catalogmanager.ErrInvalidInput)
+	_, apperr = newSession("scalar-input").sessionInputArgs(ctx)
+	assert.ErrorIs(t, apperr, catalogmanager.ErrInvalidInput)
+}
+
+func TestPolicyDecisionAuditMode(t *testing.T) {
+	config.TestInit(t)
+	ctx := context.Background()
+
+	// the transform fails so that the skill stops after its policy decision is recorded
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.transform", "function(session, input) { throw new Error('stop'); }")
+	require.NoError(t, err)
+	sm, err := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, err)
+
+	policyDecision := func(auditMode policy.AuditMode) map[string]any {
+		viewDef := test.GetViewDefinition("dev")
+		viewDef.AuditMode = auditMode
+		s := newTestSession(t, viewDef)
+		s.skillSet = sm
+		auditLog := &strings.Builder{}
+		s.auditLogInfo.auditLogger = zerolog.New(auditLog)
+
+		require.Error(t, s.Run(ctx, "", "list_pods", map[string]any{}))
+		for _, line := range strings.Split(strings.TrimSpace(auditLog.String()), "\n") {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			if entry["event"] == "policy_decision" {
+				assert.Equal(t, "allowed", entry["decision"])
+				return entry
+			}
+		}
+		t.Fatal("no policy decision was audited")
+		return nil
+	}
+
+	verbose := policyDecision(policy.AuditModeVerbose)
+	assert.Contains(t, verbose, "basis")
+	assert.NotEmpty(t, verbose["basis"])
+
+	for _, auditMode := range []policy.AuditMode{policy.AuditModeCompact, ""} {
+		compact := policyDecision(auditMode)
+		assert.NotContains(t, compact, "basis", auditMode)
+		assert.Equal(t, "list_pods", compact["skill"])
+	}
+}
+
+// unavailableContextSkillSet simulates a context provider that cannot be reached.
+type unavailableContextSkillSet struct {
+	catalogmanager.SkillSetManager
+}
+
+func (u *unavailableContextSkillSet) GetContextValue(name string, viewDef ...*policy.ViewDefinition) (types.NullableAny, apperrors.Error) {
+	return types.NilAny(), catalogmanager.ErrUnableToLoadObject.Msg("context provider unreachable")
+}
+
+func TestContextFailMode(t *testing.T) {
+	ctx := context.Background()
+	newSession := func(failMode string) (*session, *strings.Builder) {
+		def := test.SkillsetDef("dev")
+		if failMode != "" {
+			var err error
+			def, err = sjson.SetBytes(def, "spec.context.0.failMode", failMode)
+			require.NoError(t, err)
+		}
+		sm, err := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+		require.NoError(t, err)
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = &unavailableContextSkillSet{SkillSetManager: sm}
+		auditLog := &strings.Builder{}
+		s.auditLogInfo.auditLogger = zerolog.New(auditLog)
+		require.NoError(t, s.callGraph.RegisterCall("", "list_pods", "call-1"))
+		return s, auditLog
+	}
+
+	t.Run("fail closed by default", func(t *testing.T) {
+		for _, failMode := range []string{"", "closed"} {
+			s, auditLog := newSession(failMode)
+			_, err := s.getContext("call-1", "kubeconfig")
+			assert.ErrorIs(t, err, catalogmanager.ErrUnableToLoadObject)
+			assert.Contains(t, auditLog.String(), `"status":"failed"`)
+		}
+	})
+
+	t.Run("fail open yields nil", func(t *testing.T) {
+		s, auditLog := newSession("open")
+		value, err := s.getContext("call-1", "kubeconfig")
+		require.NoError(t, err)
+		assert.True(t, value.(types.NullableAny).IsNil())
+		assert.Contains(t, auditLog.String(), `"status":"fail_open"`)
+		assert.Contains(t, auditLog.String(), "context provider unreachable")
+
+		// unknown contexts still fail
+		_, err = s.getContext("call-1", "missing")
+		assert.Error(t, err)
+	})
+}
+
+// inputRunner is a runner that records the input arguments it is run with.
+type inputRunner struct {
+	fakeRunner
+	inputArgs map[string]any
+}
+
+func (r *inputRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	r.inputArgs = args.InputArgs
+	return nil
+}
+
+func TestRunRedactsInputInAuditLog(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.redactInputPaths", []string{"/labelSelector"})
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+	auditLog := &strings.Builder{}
+	s.auditLogInfo.auditLogger = zerolog.New(auditLog)
+
+	runner := &inputRunner{}
+	useTestRunner(t, runner)
+	inputArgs := map[string]any{"labelSelector": "owner=jane.doe@example.com"}
+	require.NoError(t, s.Run(ctx, "", "list_pods", inputArgs, &tangentcommon.IOWriters{
+		Out: tangentcommon.NewBufferedWriter(),
+		Err: tangentcommon.NewBufferedWriter(),
+	}))
+
+	assert.Equal(t, "owner=jane.doe@example.com", runner.inputArgs["labelSelector"], "the runner receives the original value")
+	assert.Equal(t, "owner=jane.doe@example.com", inputArgs["labelSelector"], "the caller's arguments are not modified")
+	assert.NotContains(t, auditLog.String(), "jane.doe@example.com")
+
+	var skillStart map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(auditLog.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["event"] == "skill_start" {
+			skillStart = entry
+		}
+	}
+	require.NotNil(t, skillStart)
+	assert.Equal(t, map[string]any{"labelSelector": catalogmanager.RedactedInputValue}, skillStart["input_args"])
+}
+
+func TestInputValidationMode(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	// labelSelector must be a string
+	invalidInput := map[string]any{"labelSelector": 42}
+
+	newSession := func(t *testing.T, mode string) (*session, *strings.Builder) {
+		def := test.SkillsetDef("dev")
+		if mode != "" {
+			var err error
+			def, err = sjson.SetBytes(def, "spec.skills.0.inputValidation", mode)
+			require.NoError(t, err)
+		}
+		sm, err := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+		require.NoError(t, err)
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.skillSet = sm
+		auditLog := &strings.Builder{}
+		s.auditLogInfo.auditLogger = zerolog.New(auditLog)
+		return s, auditLog
+	}
+	run := func(s *session) apperrors.Error {
+		return s.Run(ctx, "", "list_pods", invalidInput, &tangentcommon.IOWriters{
+			Out: tangentcommon.NewBufferedWriter(),
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+	}
+
+	t.Run("warn runs the skill with the invalid input", func(t *testing.T) {
+		s, auditLog := newSession(t, "warn")
+		runner := &inputRunner{}
+		useTestRunner(t, runner)
+		require.NoError(t, run(s))
+		assert.EqualValues(t, 42, runner.inputArgs["labelSelector"])
+		assert.Contains(t, auditLog.String(), `"event":"skill_input_validation"`)
+		assert.Contains(t, auditLog.String(), `"status":"warned"`)
+	})
+
+	for _, mode := range []string{"reject", ""} {
+		t.Run("reject fails the invocation with mode "+strconv.Quote(mode), func(t *testing.T) {
+			s, auditLog := newSession(t, mode)
+			runner := &inputRunner{}
+			useTestRunner(t, runner)
+			assert.ErrorIs(t, run(s), catalogmanager.ErrInvalidInput)
+			assert.Nil(t, runner.inputArgs)
+			assert.NotContains(t, auditLog.String(), `"event":"skill_input_validation"`)
+		})
+	}
+}
+
+func TestAuditSessionVariablesRedactsSecrets(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+
+	orig := config.Config().RunnerEnv
+	t.Cleanup(func() { config.Config().RunnerEnv = orig })
+	config.Config().RunnerEnv = config.RunnerEnvConfig{
+		AllowedVars: []string{"region"},
+		SecretVars:  []string{"api-key"},
+	}
+
+	vars := map[string]any{"region": "us-west-2", "api-key": "secret_api_key"}
+	assert.Equal(t, map[string]any{
+		"region":  "us-west-2",
+		"api-key": catalogmanager.RedactedInputValue,
+	}, auditSessionVariables(vars))
+	assert.Equal(t, "secret_api_key", vars["api-key"], "session variables must not be modified")
+}
+
+func TestRevokeEndsInteractiveSession(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.sessionType = tangentcommon.SessionTypeInteractive
+	s.input = newSessionInput()
+	sessionManager.mu.Lock()
+	sessionManager.sessions[s.id] = s
+	sessionManager.mu.Unlock()
+	t.Cleanup(func() { ActiveSessionManager().DeleteSession(s.id) })
+
+	s.revoke(ctx)
+
+	_, err := ActiveSessionManager().GetSession(s.id)
+	assert.Error(t, err, "revoked session must be removed")
+	assert.ErrorIs(t, s.input.send(ctx, []byte("input")), ErrInputClosed)
+
+	useTestRunner(t, &outputRunner{output: `{"pods": []}`})
+	err = s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+		Out: tangentcommon.NewBufferedWriter(),
+		Err: tangentcommon.NewBufferedWriter(),
+	})
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+}
+
+// reentrantRunner is a runner that invokes a skill from within its own run.
+type reentrantRunner struct {
+	fakeRunner
+	s         *session
+	skillName string
+	nestedErr apperrors.Error
+}
+
+func (r *reentrantRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	r.nestedErr = r.s.Run(ctx, args.InvocationID, r.skillName, map[string]any{}, &tangentcommon.IOWriters{
+		Out: tangentcommon.NewBufferedWriter(),
+		Err: tangentcommon.NewBufferedWriter(),
+	})
+	return nil
+}
+
+func TestSkillMaxConcurrentReentry(t *testing.T) {
+	config.SetTestMode(true)
+	test.SetupTestCatalog(t)
+	config.TestInit(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	def, err := sjson.SetBytes(test.SkillsetDef("dev"), "spec.skills.0.maxConcurrent", 1)
+	require.NoError(t, err)
+	sm, appErr := catalogmanager.SkillSetManagerFromJSON(ctx, def)
+	require.NoError(t, appErr)
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.skillSet = sm
+
+	runner := &reentrantRunner{s: s, skillName: "list_pods"}
+	useTestRunner(t, runner)
+
+	appErr = s.Run(ctx, "", "list_pods", map[string]any{}, &tangentcommon.IOWriters{
+		Out: tangentcommon.NewBufferedWriter(),
+		Err: tangentcommon.NewBufferedWriter(),
+	})
+	assert.NoError(t, appErr)
+	assert.ErrorIs(t, runner.nestedErr, ErrToolGraphError, "a skill calling itself must fail instead of waiting on its own slot")
+	assert.NoError(t, ctx.Err())
+}
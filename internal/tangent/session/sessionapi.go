@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
 	srvsession "github.com/tansive/tansive/internal/catalogsrv/session"
 	"github.com/tansive/tansive/internal/common/apperrors"
 	"github.com/tansive/tansive/internal/common/httpclient"
@@ -124,6 +125,7 @@ func processMCPProxySession(ctx context.Context, req *tangentcommon.SessionCreat
 	}
 	url, token, err := runMCPProxySession(ctx, session)
 	if err != nil {
+		ActiveSessionManager().DeleteSession(session.id)
 		return nil, err
 	}
 
@@ -145,11 +147,16 @@ func processInteractiveSession(ctx context.Context, req *tangentcommon.SessionCr
 	if err != nil {
 		return nil, err
 	}
+	if req.StreamInput {
+		session.input = newSessionInput()
+	}
 	rsp = &httpx.Response{
 		StatusCode:  http.StatusOK,
 		ContentType: "application/x-ndjson",
 		Chunked:     true,
 		WriteChunks: func(w http.ResponseWriter) error {
+			// the session frees its slot on the tangent once it completes
+			defer ActiveSessionManager().DeleteSession(session.id)
 			ctx := log.Ctx(ctx).With().Str("session_id", session.id.String()).Logger().WithContext(ctx)
 			return runInteractiveSession(ctx, w, session)
 		},
@@ -251,10 +258,15 @@ func createActiveSession(ctx context.Context, executionState *srvsession.Executi
 		ViewDefinition:   executionState.ViewDefinition,
 		SessionVariables: executionState.SessionVariables,
 		InputArgs:        executionState.InputArgs,
+		InputArgsRef:     executionState.InputArgsRef,
 		Catalog:          executionState.Catalog,
 		Variant:          executionState.Variant,
 		Namespace:        executionState.Namespace,
 		TenantID:         executionState.TenantID,
+		Environment:      executionState.Environment,
+		ContextOverrides: executionState.ContextOverrides,
+		AdoptionDepth:    executionState.AdoptionDepth,
+		LogLevel:         executionState.LogLevel,
 	}
 
 	session, err := ActiveSessionManager().CreateSession(ctx, serverCtx, token, tokenExpiry, sessionType)
@@ -262,6 +274,8 @@ func createActiveSession(ctx context.Context, executionState *srvsession.Executi
 		log.Ctx(ctx).Error().Err(err).Msg("unable to create session")
 		return nil, err
 	}
+	session.statePath = getSessionStatePath(session.id.String())
+	session.persistState()
 
 	return session, nil
 }
@@ -291,6 +305,8 @@ func runInteractiveSession(ctx context.Context, w http.ResponseWriter, session *
 	defer unsubSessionLog()
 	interactiveLog, unsubInteractiveLog := GetEventBus().Subscribe(session.getTopic(TopicInteractiveLog), 100)
 	defer unsubInteractiveLog()
+	session.transcript = newTranscriptRecorder(config.Config().AuditLog.MaxTranscriptSize)
+	session.transcript.enabled = session.transcriptEnabled
 
 	logCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -314,6 +330,7 @@ func runInteractiveSession(ctx context.Context, w http.ResponseWriter, session *
 				if !ok {
 					continue
 				}
+				session.recordTranscript(data)
 				w.Write(data)
 				flusher.Flush()
 			}
@@ -323,13 +340,21 @@ func runInteractiveSession(ctx context.Context, w http.ResponseWriter, session *
 	// Run will block until the session is complete
 	session.auditLogInfo.auditLogger.Info().
 		Str("event", "session_start").
-		Any("session_variables", session.context.SessionVariables).
+		Any("session_variables", auditSessionVariables(session.context.SessionVariables)).
 		Msg("starting session")
 
 	log.Ctx(ctx).Info().Str("skill", session.context.Skill).Msg("running session")
 	runCtx := session.getLogger(TopicSessionLog).With().Str("skill", session.context.Skill).Str("actor", "system").Logger().WithContext(ctx)
 
-	apperr = session.Run(runCtx, "", session.context.Skill, session.context.InputArgs)
+	inputArgs, apperr := session.sessionInputArgs(runCtx)
+	if apperr == nil {
+		if session.input != nil {
+			apperr = session.RunStreaming(runCtx, "", session.context.Skill, inputArgs, session.input.ch)
+			session.input.finish()
+		} else {
+			apperr = session.Run(runCtx, "", session.context.Skill, inputArgs)
+		}
+	}
 
 	if apperr != nil {
 		log.Ctx(ctx).Error().Err(apperr).Msg("session failed")
@@ -365,12 +390,15 @@ func runMCPProxySession(ctx context.Context, session *session) (url string, toke
 	// Run will block until the session is complete
 	session.auditLogInfo.auditLogger.Info().
 		Str("event", "session_start").
-		Any("session_variables", session.context.SessionVariables).
+		Any("session_variables", auditSessionVariables(session.context.SessionVariables)).
 		Msg("starting session")
 
 	log.Ctx(ctx).Info().Str("skill", session.context.Skill).Msg("running session")
 
-	url, token, apperr = session.RunMCPProxy(ctx, "", session.context.Skill, session.context.InputArgs)
+	inputArgs, apperr := session.sessionInputArgs(ctx)
+	if apperr == nil {
+		url, token, apperr = session.RunMCPProxy(ctx, "", session.context.Skill, inputArgs)
+	}
 	if apperr != nil {
 		log.Ctx(ctx).Error().Err(apperr).Msg("session failed")
 		session.auditLogInfo.auditLogger.Error().Str("event", "session_end").Err(apperr).Msg("session failed")
@@ -381,6 +409,24 @@ func runMCPProxySession(ctx context.Context, session *session) (url string, toke
 	return url, token, nil
 }
 
+// auditSessionVariables returns the session variables as they are recorded in the audit log, with
+// the values of the secret variables of the runner_env configuration redacted. The variables
+// themselves are not modified.
+func auditSessionVariables(vars map[string]any) map[string]any {
+	runnerEnv := config.Config().RunnerEnv
+	if len(runnerEnv.SecretVars) == 0 || vars == nil {
+		return vars
+	}
+	redacted := make(map[string]any, len(vars))
+	for key, value := range vars {
+		if runnerEnv.IsSecret(key) {
+			value = catalogmanager.RedactedInputValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
 func processStopSession(ctx context.Context, sessionID uuid.UUID) apperrors.Error {
 	session, err := ActiveSessionManager().GetSession(sessionID)
 	if err != nil {
@@ -0,0 +1,121 @@
+package session
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpx"
+	"github.com/tansive/tansive/internal/common/uuid"
+)
+
+// sessionInput carries the input streamed to the skill of an interactive session. Chunks are
+// delivered to the skill's stdin in the order they are sent, and stdin is closed when the input
+// is closed. Sends fail once the skill has completed.
+type sessionInput struct {
+	mu        sync.RWMutex
+	ch        chan []byte
+	closed    bool
+	done      chan struct{}
+	closeDone sync.Once
+}
+
+func newSessionInput() *sessionInput {
+	return &sessionInput{
+		ch:   make(chan []byte),
+		done: make(chan struct{}),
+	}
+}
+
+// send delivers a chunk of input to the running skill, blocking until the skill accepts it.
+func (in *sessionInput) send(ctx context.Context, data []byte) apperrors.Error {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	if in.closed {
+		return ErrInputClosed
+	}
+	select {
+	case in.ch <- data:
+		return nil
+	case <-in.done:
+		return ErrInputClosed.Msg("skill has completed")
+	case <-ctx.Done():
+		return ErrInputClosed.Msg(ctx.Err().Error())
+	}
+}
+
+// close signals the end of input to the skill. Closing more than once has no effect.
+func (in *sessionInput) close() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if !in.closed {
+		in.closed = true
+		close(in.ch)
+	}
+}
+
+// finish marks the skill as completed, failing pending and future sends.
+func (in *sessionInput) finish() {
+	in.closeDone.Do(func() { close(in.done) })
+}
+
+// getStreamingSession returns the session in the route after checking the request carries
+// the session's token and that the session accepts streamed input.
+func getStreamingSession(r *http.Request) (*session, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid session ID")
+	}
+	session, err := sessionManager.GetSession(id)
+	if err != nil {
+		return nil, err
+	}
+	if !session.authorizeToken(r) {
+		return nil, httpx.ErrUnAuthorized("invalid session token")
+	}
+	if session.input == nil {
+		return nil, ErrInputNotStreaming
+	}
+	return session, nil
+}
+
+// sendSessionInput sends the request body as the next chunk of input to the session's skill.
+func sendSessionInput(r *http.Request) (*httpx.Response, error) {
+	session, err := getStreamingSession(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	if len(data) == 0 {
+		return nil, httpx.ErrInvalidRequest("input is empty")
+	}
+	if err := session.input.send(r.Context(), data); err != nil {
+		return nil, err
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusAccepted,
+		Response:   nil,
+	}, nil
+}
+
+// closeSessionInput closes the stdin of the session's skill.
+func closeSessionInput(r *http.Request) (*httpx.Response, error) {
+	session, err := getStreamingSession(r)
+	if err != nil {
+		return nil, err
+	}
+	session.input.close()
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+		Response:   nil,
+	}, nil
+}
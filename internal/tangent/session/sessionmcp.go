@@ -15,6 +15,7 @@ import (
 	"github.com/tansive/tansive/internal/tangent/session/mcpservice"
 	"github.com/tansive/tansive/internal/tangent/session/toolgraph"
 	"github.com/tansive/tansive/pkg/api"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -31,10 +32,12 @@ type mcpSession struct {
 	random       string         // Random session token or identifier
 	filter       string         // MCP tool filter annotation for access control
 	invocationID string         // Current invocation ID for tracking tool calls
+	span         trace.Span     // Span of the proxy invocation, ended when the session stops
 }
 
 // RunMCPProxy executes a skill via the MCP proxy, handling policy checks, input transformation, auditing, and session setup. Returns the session URL or an error.
-func (s *session) RunMCPProxy(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any) (string, string, apperrors.Error) {
+func (s *session) RunMCPProxy(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any) (_ string, _ string, retErr apperrors.Error) {
+	ctx = s.withLogLevel(ctx)
 	log.Ctx(ctx).Info().Msgf("requested skill: %s", skillName)
 	invocationID := uuid.New().String()
 	s.mcpSession.invocationID = invocationID
@@ -43,12 +46,24 @@ func (s *session) RunMCPProxy(ctx context.Context, invokerID string, skillName s
 		return "", "", ErrToolGraphError.Msg(toolErr.Error())
 	}
 	s.invocationIDs[invocationID] = s.viewDef
+	s.persistState()
+	ctx, span := s.startSkillSpan(ctx, invokerID, invocationID, skillName)
+	// the proxy keeps running for the life of the session unless it fails to start
+	defer func() {
+		if retErr != nil {
+			s.callGraph.SetStatus(toolgraph.CallID(invocationID), toolgraph.CallStatusFailed)
+			s.persistState()
+			s.endSkillSpan(invocationID, span, retErr)
+			return
+		}
+		s.mcpSession.span = span
+	}()
 	s.auditLogInfo.auditLogger.Info().
 		Str("event", "skill_start").
 		Str("invoker_id", invokerID).
 		Str("invocation_id", invocationID).
 		Str("skill", skillName).
-		Any("input_args", inputArgs).
+		Any("input_args", s.auditInputArgs(ctx, skillName, inputArgs)).
 		Msg("requested skill")
 	if invokerID != "" {
 		if _, ok := s.invocationIDs[invokerID]; !ok {
@@ -62,26 +77,35 @@ func (s *session) RunMCPProxy(ctx context.Context, invokerID string, skillName s
 		return "", "", err
 	}
 
+	if err := s.checkRunnerCapabilities(); err != nil {
+		s.logger.Error().Err(err).Msg("skillset requires unsupported runners")
+		return "", "", err
+	}
+
 	isAllowed, basis, actions, err := s.ValidateRunPolicy(ctx, invokerID, skillName)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to validate run policy")
 		return "", "", err
 	}
 	if !isAllowed {
-		msg := fmt.Sprintf("blocked by Tansive policy: view '%s' does not authorize any of required actions - %v - to use this skill", s.context.View, actions)
+		msg, reason := s.blockedByPolicyMessage(skillName, actions, basis)
 		s.logger.Error().Str("policy_decision", "true").Msg(msg)
 		log.Ctx(ctx).Error().Str("policy_decision", "true").Msg(msg)
 		s.auditLogInfo.auditLogger.Error().
 			Str("event", "policy_decision").
 			Str("decision", "blocked").
+			Str("reason", reason).
+			Strs("deny_reasons", denyReasons(basis)).
 			Str("invocation_id", invocationID).
 			Str("view", s.context.View).
 			Any("basis", basis).
 			Str("skill", skillName).
 			Any("actions", actions).
 			Msg("blocked by policy")
-		return "", "", ErrBlockedByPolicy.Msg(msg)
+		setSpanPolicyDecision(ctx, "blocked", reason)
+		return "", "", s.blockedByPolicyError(msg, skillName, actions, basis)
 	}
+	setSpanPolicyDecision(ctx, "allowed", "")
 	msg := fmt.Sprintf("allowed by Tansive policy: view '%s' authorizes actions - %v - to use this skill", s.context.View, actions)
 	s.logger.Info().Str("policy_decision", "true").Msg(msg)
 	log.Ctx(ctx).Info().Str("policy_decision", "true").Msg(msg)
@@ -90,7 +114,7 @@ func (s *session) RunMCPProxy(ctx context.Context, invokerID string, skillName s
 		Str("decision", "allowed").
 		Str("invocation_id", invocationID).
 		Str("view", s.context.View).
-		Any("basis", basis).
+		Func(s.allowedBasis(basis)).
 		Str("skill", skillName).
 		Any("actions", actions).
 		Msg("allowed by policy")
@@ -114,7 +138,7 @@ func (s *session) RunMCPProxy(ctx context.Context, invokerID string, skillName s
 			Str("status", "success").
 			Str("invocation_id", invocationID).
 			Str("skill", skillName).
-			Any("input_args", inputArgs).
+			Any("input_args", s.auditInputArgs(ctx, skillName, inputArgs)).
 			Msg("input transformed")
 	}
 
@@ -150,7 +174,7 @@ func (s *session) startMCPProxySession(ctx context.Context, invokerID string, sk
 		return "", "", err
 	}
 
-	if err := skill.ValidateInput(inputArgs); err != nil {
+	if err := s.validateSkillInput(ctx, skill, "", skill.ApplyDefaultInputArgs(skill.NormalizeInput(inputArgs))); err != nil {
 		return "", "", err
 	}
 
@@ -167,6 +191,7 @@ func (s *session) startMCPProxySession(ctx context.Context, invokerID string, sk
 	}
 	s.mcpSession.runner = runner
 	s.mcpSession.source = skill.Source
+	setSpanRunner(ctx, runner.ID())
 
 	url, token, random, err := mcpservice.NewMCPSession(ctx, s)
 	if err != nil {
@@ -199,7 +224,7 @@ outer:
 	for _, tool := range tools {
 		for _, skill := range skills {
 			if skill.Source == s.mcpSession.source && skill.Name == tool.Name {
-				allowed, _, err := policy.AreActionsAllowedOnResource(s.viewDef, s.skillSet.GetResourcePath(), skill.ExportedActions)
+				allowed, _, err := policy.AreActionsAllowedOnResource(s.viewDef, s.skillSetResourcePath(), skill.ExportedActions)
 				if err != nil || !allowed {
 					continue outer
 				}
@@ -239,7 +264,7 @@ func (s *session) MCPListTools(ctx context.Context) ([]mcp.Tool, error) {
 }
 
 // MCPCallTool invokes a specific MCP tool, performing policy checks, input transformation, auditing, and error handling. Returns the tool's result or an error.
-func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.CallToolParams) (*mcp.CallToolResult, error) {
+func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.CallToolParams) (retResult *mcp.CallToolResult, retErr error) {
 	inputArgs, ok := params.Arguments.(map[string]any)
 	if !ok {
 		return nil, ErrInvalidInput.Msg("invalid input arguments")
@@ -251,13 +276,26 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 		return nil, ErrToolGraphError.Msg(toolErr.Error())
 	}
 	s.invocationIDs[invocationID] = s.viewDef
+	s.persistState()
+	ctx, span := s.startSkillSpan(ctx, invokerID, invocationID, tool.Name)
+	defer func() {
+		status := callStatus(retErr)
+		spanErr := retErr
+		if retResult != nil && retResult.IsError {
+			status = toolgraph.CallStatusFailed
+			spanErr = ErrExecutionFailed.Msg("tool returned an error result")
+		}
+		s.callGraph.SetStatus(toolgraph.CallID(invocationID), status)
+		s.persistState()
+		s.endSkillSpan(invocationID, span, spanErr)
+	}()
 
 	s.auditLogInfo.auditLogger.Info().
 		Str("event", "skill_start").
 		Str("invoker_id", invokerID).
 		Str("invocation_id", invocationID).
 		Str("skill", tool.Name).
-		Any("input_args", inputArgs).
+		Any("input_args", s.auditInputArgs(ctx, tool.Name, inputArgs)).
 		Msg("requested skill")
 
 	if s.mcpSession.filter != FilterNoFilter {
@@ -277,12 +315,14 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 			}
 
 			if !isAllowed {
-				msg := fmt.Sprintf("blocked by Tansive policy: view '%s' does not authorize any of required actions - %v - to use this skill", s.context.View, actions)
+				msg, reason := s.blockedByPolicyMessage(skill.Name, actions, basis)
 				s.logger.Error().Str("policy_decision", "true").Msg(msg)
 				log.Ctx(ctx).Error().Str("policy_decision", "true").Msg(msg)
 				s.auditLogInfo.auditLogger.Error().
 					Str("event", "policy_decision").
 					Str("decision", "blocked").
+					Str("reason", reason).
+					Strs("deny_reasons", denyReasons(basis)).
 					Str("invoker_id", invokerID).
 					Str("invocation_id", invocationID).
 					Str("view", s.context.View).
@@ -290,6 +330,7 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 					Str("skill", skill.Name).
 					Any("actions", actions).
 					Msg("blocked by policy")
+				setSpanPolicyDecision(ctx, "blocked", reason)
 
 				result := &mcp.CallToolResult{
 					IsError: true,
@@ -309,7 +350,7 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 				Str("invoker_id", invokerID).
 				Str("invocation_id", invocationID).
 				Str("view", s.context.View).
-				Any("basis", basis).
+				Func(s.allowedBasis(basis)).
 				Str("skill", skill.Name).
 				Any("actions", actions).
 				Msg("allowed by policy")
@@ -344,7 +385,7 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 					Str("status", "success").
 					Str("invocation_id", invocationID).
 					Str("skill", skill.Name).
-					Any("input_args", inputArgs).
+					Any("input_args", skill.RedactInput(inputArgs)).
 					Msg("input transformed")
 			}
 		} else {
@@ -372,6 +413,8 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 			Msg("allowed by policy")
 	}
 
+	setSpanPolicyDecision(ctx, "allowed", "")
+	setSpanRunner(ctx, s.mcpSession.runner.ID())
 	result, err := s.mcpSession.runner.RunMCP(ctx, &api.SkillInputArgs{
 		InvocationID: s.mcpSession.invocationID,
 		SkillName:    tool.Name,
@@ -396,5 +439,6 @@ func (s *session) MCPCallTool(ctx context.Context, tool mcp.Tool, params mcp.Cal
 		Str("skill", tool.Name).
 		Msg("skill completed")
 
+	formatToolResult(result, s.resultFormat(tool.Name))
 	return result, nil
 }
@@ -0,0 +1,173 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/session/toolgraph"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+)
+
+// sessionState is the state of a session persisted to disk, from which the session can be
+// recovered if the tangent restarts before the session is finalized.
+type sessionState struct {
+	Context     *ServerContext            `json:"context"`
+	Token       string                    `json:"token"`
+	TokenExpiry time.Time                 `json:"token_expiry"`
+	SessionType tangentcommon.SessionType `json:"session_type"`
+	Calls       []*toolgraph.CallNode     `json:"calls"`
+}
+
+// getSessionStatePath returns the file path of a session's persisted state.
+func getSessionStatePath(sessionID string) string {
+	return filepath.Join(config.GetSessionStateDir(), sessionID+".json")
+}
+
+// saveState persists the state of the session. The state is written to a temporary file that
+// replaces the previous state, so that a restart never observes a partially written state.
+func (s *session) saveState() error {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	if s.statePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(&sessionState{
+		Context:     s.context,
+		Token:       s.token,
+		TokenExpiry: s.tokenExpiry,
+		SessionType: s.sessionType,
+		Calls:       s.callGraph.Tree(),
+	})
+	if err != nil {
+		return err
+	}
+	tmpPath := s.statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.statePath)
+}
+
+// persistState saves the state of the session, logging any failure. A session whose state cannot
+// be saved continues to run but cannot be recovered after a restart.
+func (s *session) persistState() {
+	if err := s.saveState(); err != nil {
+		s.logger.Error().Err(err).Msg("unable to save session state")
+	}
+}
+
+// removeState deletes the persisted state of the session once it no longer needs recovery.
+func (s *session) removeState() {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	if s.statePath == "" {
+		return
+	}
+	if err := os.Remove(s.statePath); err != nil && !os.IsNotExist(err) {
+		s.logger.Error().Err(err).Msg("unable to remove session state")
+	}
+	s.statePath = ""
+}
+
+// loadSessionStates reads the persisted state of all sessions, keyed by the path of each state.
+// States that cannot be read are skipped.
+func loadSessionStates(ctx context.Context) map[string]*sessionState {
+	states := make(map[string]*sessionState)
+	entries, err := os.ReadDir(config.GetSessionStateDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Ctx(ctx).Error().Err(err).Msg("unable to read session state dir")
+		}
+		return states
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(config.GetSessionStateDir(), entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("path", path).Msg("unable to read session state")
+			continue
+		}
+		state := &sessionState{}
+		if err := json.Unmarshal(data, state); err != nil || state.Context == nil {
+			log.Ctx(ctx).Error().Err(err).Str("path", path).Msg("invalid session state")
+			continue
+		}
+		states[path] = state
+	}
+	return states
+}
+
+// restoreSessions recreates the sessions persisted to disk in the session manager. Invocations
+// that were running when the tangent stopped are marked failed, since their runners did not
+// survive the restart. The state of sessions whose token has expired is discarded, as they can
+// no longer report to the catalog server.
+func (as *activeSessions) restoreSessions(ctx context.Context) []*session {
+	var restored []*session
+	for path, state := range loadSessionStates(ctx) {
+		logger := log.With().Str("session_id", state.Context.SessionID.String()).Logger()
+		if !state.TokenExpiry.IsZero() && time.Now().After(state.TokenExpiry) {
+			logger.Info().Msg("discarding state of expired session")
+			os.Remove(path)
+			continue
+		}
+		// restored sessions are finalized right away, so they are not held to the session limit
+		session, err := as.createSession(logger.WithContext(ctx), state.Context, state.Token, state.TokenExpiry, state.SessionType, 0)
+		if err != nil {
+			logger.Error().Err(err).Msg("unable to restore session")
+			continue
+		}
+		session.callGraph.Restore(markInterrupted(state.Calls))
+		session.statePath = path
+		restored = append(restored, session)
+	}
+	return restored
+}
+
+// markInterrupted marks the running invocations of a call tree as failed.
+func markInterrupted(calls []*toolgraph.CallNode) []*toolgraph.CallNode {
+	for _, call := range calls {
+		if call.Status == toolgraph.CallStatusRunning {
+			call.Status = toolgraph.CallStatusFailed
+		}
+		markInterrupted(call.Calls)
+	}
+	return calls
+}
+
+// RecoverSessions finalizes the sessions that were active when the tangent last stopped.
+// Each session is reattached with the state persisted to disk, and reported to the catalog
+// server as failed along with the audit log recorded before the restart.
+// Must be called after the tangent is registered and before it serves requests.
+func RecoverSessions(ctx context.Context) {
+	for _, session := range sessionManager.restoreSessions(ctx) {
+		sessionManager.finalizeInterrupted(ctx, session)
+	}
+}
+
+// finalizeInterrupted finalizes a session whose skills were interrupted by a tangent restart
+// and removes it from the session manager.
+func (as *activeSessions) finalizeInterrupted(ctx context.Context, s *session) {
+	ctx = s.logger.WithContext(ctx)
+	s.auditLogInfo.auditLogComplete = make(chan string, 1)
+	auditLogPath := GetAuditLogPath(s.id.String())
+	if _, err := os.Stat(auditLogPath); err != nil {
+		auditLogPath = ""
+	}
+	s.auditLogInfo.auditLogComplete <- auditLogPath
+
+	if err := s.Finalize(ctx, ErrSessionInterrupted.Msg("session interrupted by tangent restart")); err != nil {
+		s.logger.Error().Err(err).Msg("unable to finalize interrupted session")
+	} else {
+		s.logger.Info().Msg("finalized interrupted session")
+	}
+	as.DeleteSession(s.id)
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/common/uuid"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/session/toolgraph"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+)
+
+func TestRecoverSessionAfterRestart(t *testing.T) {
+	config.SetTestMode(true)
+	ts := test.SetupTestCatalog(t)
+	config.TestInit(t)
+	SetTestMode(true)
+	Init()
+	token, expiresAt := test.AdoptView(t, ts.Catalog, "dev-view", ts.Token)
+	ctx := context.Background()
+
+	// a session with a completed invocation and a nested invocation still running
+	serverContext := &ServerContext{
+		SessionID:      uuid.New(),
+		TenantID:       ts.TenantID,
+		Catalog:        ts.Catalog,
+		Variant:        "dev",
+		SkillSet:       test.SkillsetPath(),
+		Skill:          test.SkillsetAgent(),
+		View:           "dev-view",
+		ViewDefinition: test.GetViewDefinition("dev"),
+	}
+	before := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+	s, err := before.CreateSession(ctx, serverContext, token, expiresAt, tangentcommon.SessionTypeInteractive)
+	require.NoError(t, err)
+	s.statePath = getSessionStatePath(s.GetSessionID())
+	require.NoError(t, s.callGraph.RegisterCall("", "k8s_troubleshooter", "inv-root"))
+	require.NoError(t, s.callGraph.RegisterCall("inv-root", "list_pods", "inv-list"))
+	require.NoError(t, s.callGraph.RegisterCall("inv-root", "restart_deployment", "inv-restart"))
+	s.callGraph.SetStatus("inv-list", toolgraph.CallStatusCompleted)
+	require.NoError(t, s.saveState())
+
+	// simulate a restart with an empty session manager
+	after := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+	restored := after.restoreSessions(ctx)
+	require.Len(t, restored, 1)
+
+	r := restored[0]
+	assert.Equal(t, s.id, r.id)
+	assert.Equal(t, token, r.token)
+	assert.WithinDuration(t, expiresAt, r.tokenExpiry, time.Second)
+	assert.Equal(t, tangentcommon.SessionTypeInteractive, r.sessionType)
+	assert.Equal(t, "dev-view", r.context.View)
+	assert.Equal(t, test.SkillsetPath(), r.context.SkillSet)
+	assert.Equal(t, serverContext.ViewDefinition, r.context.ViewDefinition)
+	assert.Equal(t, []*toolgraph.CallNode{
+		{CallID: "inv-root", Tool: "k8s_troubleshooter", Status: toolgraph.CallStatusFailed, Calls: []*toolgraph.CallNode{
+			{CallID: "inv-list", Tool: "list_pods", Status: toolgraph.CallStatusCompleted},
+			{CallID: "inv-restart", Tool: "restart_deployment", Status: toolgraph.CallStatusFailed},
+		}},
+	}, r.callGraph.Tree())
+
+	restoredSession, err := after.GetSession(s.id)
+	require.NoError(t, err)
+	assert.Same(t, r, restoredSession)
+
+	// finalizing the session discards its state so it is not recovered again
+	after.finalizeInterrupted(ctx, r)
+	_, err = after.GetSession(s.id)
+	assert.Error(t, err)
+	_, statErr := os.Stat(getSessionStatePath(s.GetSessionID()))
+	assert.True(t, os.IsNotExist(statErr))
+	assert.Empty(t, after.restoreSessions(ctx))
+}
+
+func TestRestoreSessionsDiscardsExpiredSessions(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	before := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+	s, err := before.CreateSession(ctx, &ServerContext{SessionID: uuid.New()}, "token", time.Now().Add(-time.Minute), tangentcommon.SessionTypeInteractive)
+	require.NoError(t, err)
+	s.statePath = getSessionStatePath(s.GetSessionID())
+	require.NoError(t, s.saveState())
+
+	after := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+	assert.Empty(t, after.restoreSessions(ctx))
+	_, statErr := os.Stat(getSessionStatePath(s.GetSessionID()))
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestFinalizeKeepsStateUntilReported(t *testing.T) {
+	config.SetTestMode(true)
+	ts := test.SetupTestCatalog(t)
+	config.TestInit(t)
+	SetTestMode(true)
+	Init()
+	ctx := context.Background()
+
+	as := &activeSessions{sessions: make(map[uuid.UUID]*session)}
+	serverContext := &ServerContext{SessionID: uuid.New(), TenantID: ts.TenantID, Catalog: ts.Catalog}
+	s, err := as.CreateSession(ctx, serverContext, "invalid-token", time.Now().Add(time.Hour), tangentcommon.SessionTypeInteractive)
+	require.NoError(t, err)
+	s.statePath = getSessionStatePath(s.GetSessionID())
+	require.NoError(t, s.saveState())
+	t.Cleanup(s.removeState)
+
+	// the catalog server rejects the report, so the session must still be recoverable
+	s.auditLogInfo.auditLogComplete = make(chan string, 1)
+	s.auditLogInfo.auditLogComplete <- ""
+	assert.Error(t, s.Finalize(ctx, nil))
+	_, statErr := os.Stat(getSessionStatePath(s.GetSessionID()))
+	assert.NoError(t, statErr)
+}
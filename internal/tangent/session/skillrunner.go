@@ -2,7 +2,6 @@ package session
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"strings"
 
@@ -36,9 +35,9 @@ func CreateSkillService() (*skillservice.SkillService, apperrors.Error) {
 // Provides skill listing, context management, and skill execution capabilities.
 type skillRunner struct{}
 
-// GetSkills retrieves available skills for a session as LLM tools.
+// GetSkills retrieves available skills for a session as LLM tools, optionally limited to a category.
 // Returns the skills list and any error encountered during retrieval.
-func (s *skillRunner) GetSkills(ctx context.Context, sessionID string) ([]api.LLMTool, apperrors.Error) {
+func (s *skillRunner) GetSkills(ctx context.Context, sessionID, category string) ([]api.LLMTool, apperrors.Error) {
 	sessionUUID, err := uuid.Parse(sessionID)
 	if err != nil {
 		return nil, ErrSessionError.Msg("invalid sessionID")
@@ -47,7 +46,7 @@ func (s *skillRunner) GetSkills(ctx context.Context, sessionID string) ([]api.LL
 	if err != nil {
 		return nil, ErrSessionError.Msg(err.Error())
 	}
-	return session.getSkillsAsLLMTools()
+	return session.getSkillsAsLLMTools(category)
 }
 
 // GetContext retrieves a context value for a session and invocation.
@@ -64,6 +63,20 @@ func (s *skillRunner) GetContext(ctx context.Context, sessionID, invocationID, n
 	return session.getContext(invocationID, name)
 }
 
+// GetResource retrieves a resource dependency of the session's skillset by alias.
+// Returns the resource value and any error encountered during retrieval.
+func (s *skillRunner) GetResource(ctx context.Context, sessionID, invocationID, alias string) (any, apperrors.Error) {
+	sessionUUID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, ErrSessionError.Msg("invalid sessionID")
+	}
+	session, err := ActiveSessionManager().GetSession(sessionUUID)
+	if err != nil {
+		return nil, ErrSessionError.Msg(err.Error())
+	}
+	return session.getResource(ctx, invocationID, alias)
+}
+
 // Run executes a skill with the given parameters.
 // Validates parameters, retrieves the session, and executes the skill.
 // Returns the skill output and any error encountered during execution.
@@ -106,18 +119,21 @@ func (s *skillRunner) Run(ctx context.Context, params *tangentcommon.RunParams)
 		Err: errWriter,
 	})
 
-	return processOutput(outWriter, errWriter, apperr)
+	return processOutput(outWriter, errWriter, apperr, session.resultFormat(params.SkillName))
 }
 
 // processOutput processes the output from skill execution.
-// Formats output based on content type and error conditions.
+// Formats output based on content type, the skill's result format and error conditions.
 // Returns the processed response and any error encountered during processing.
-func processOutput(outWriter *tangentcommon.BufferedWriter, errWriter *tangentcommon.BufferedWriter, err apperrors.Error) (map[string]any, apperrors.Error) {
+func processOutput(outWriter *tangentcommon.BufferedWriter, errWriter *tangentcommon.BufferedWriter, err apperrors.Error, format api.ResultFormat) (map[string]any, apperrors.Error) {
 	response := make(map[string]any)
 
 	if err != nil {
 		if errors.Is(err, ErrBlockedByPolicy) {
 			response["error"] = err.Error() + " Please contact the administrator of your Tansive system to request access."
+			if block, ok := GetPolicyBlock(err); ok {
+				response["blocked"] = block
+			}
 		} else {
 			response["error"] = err.Error()
 		}
@@ -134,21 +150,7 @@ func processOutput(outWriter *tangentcommon.BufferedWriter, errWriter *tangentco
 		return response, nil
 	}
 
-	output := outWriter.Bytes()
-	var parsed any
-	if json.Unmarshal(output, &parsed) == nil {
-		response["content"] = map[string]any{
-			"type":  detectJSONType(parsed),
-			"value": parsed,
-		}
-	} else {
-		// Not JSON, treat as plaintext
-		response["content"] = map[string]any{
-			"type":  "text",
-			"value": outWriter.String(),
-		}
-	}
-
+	response["content"] = formatContent(outWriter.Bytes(), format)
 	return response, nil
 }
 
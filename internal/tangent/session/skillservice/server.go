@@ -79,9 +79,11 @@ func (s *SkillService) handleInvokeSkill(r *http.Request) (*httpx.Response, erro
 }
 
 // handleGetSkills retrieves available skills for a session.
+// The optional category query parameter limits the skills to that category.
 // Returns the list of skills as LLM tools and any error encountered during retrieval.
 func (s *SkillService) handleGetSkills(r *http.Request) (*httpx.Response, error) {
-	tools, err := s.skillManager.GetSkills(r.Context(), r.URL.Query().Get("session_id"))
+	query := r.URL.Query()
+	tools, err := s.skillManager.GetSkills(r.Context(), query.Get("session_id"), query.Get("category"))
 	if err != nil {
 		return nil, ErrSkillServiceError.Msg(err.Error())
 	}
@@ -107,12 +109,27 @@ func (s *SkillService) handleGetContext(r *http.Request) (*httpx.Response, error
 	}, nil
 }
 
+// handleGetResource retrieves a resource dependency of a session's skillset by alias.
+// Returns the resource value and any error encountered during retrieval.
+func (s *SkillService) handleGetResource(r *http.Request) (*httpx.Response, error) {
+	query := r.URL.Query()
+	resource, err := s.skillManager.GetResource(r.Context(), query.Get("session_id"), query.Get("invocation_id"), query.Get("alias"))
+	if err != nil {
+		return nil, ErrSkillServiceError.Msg(err.Error())
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   resource,
+	}, nil
+}
+
 // MountHandlers registers HTTP handlers for skill service endpoints.
-// Sets up routes for skill invocation, skill listing, and context operations.
+// Sets up routes for skill invocation, skill listing, context and resource operations.
 func (s *SkillService) MountHandlers() {
 	s.Router.Post("/skill-invocations", httpx.WrapHttpRsp(s.handleInvokeSkill))
 	s.Router.Get("/skills", httpx.WrapHttpRsp(s.handleGetSkills))
 	s.Router.Get("/context", httpx.WrapHttpRsp(s.handleGetContext))
+	s.Router.Get("/resources", httpx.WrapHttpRsp(s.handleGetResource))
 }
 
 // StartServer starts the skill service on a Unix domain socket.
@@ -31,21 +31,35 @@ func (m *mockSession) Run(ctx context.Context, params *tangentcommon.RunParams)
 	}, nil
 }
 
-func (m *mockSession) GetSkills(ctx context.Context, sessionID string) ([]api.LLMTool, apperrors.Error) {
-	return []api.LLMTool{
+func (m *mockSession) GetSkills(ctx context.Context, sessionID, category string) ([]api.LLMTool, apperrors.Error) {
+	tools := []api.LLMTool{}
+	for _, tool := range []api.LLMTool{
 		{
 			Name:         "test-skill",
 			Description:  "test skill description",
+			Category:     "testing",
 			InputSchema:  []byte("{}"),
 			OutputSchema: []byte("{}"),
 		},
-	}, nil
+	} {
+		if category == "" || tool.Category == category {
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
 }
 
 func (m *mockSession) GetContext(ctx context.Context, sessionID, invocationID, name string) (any, apperrors.Error) {
 	return 5, nil
 }
 
+func (m *mockSession) GetResource(ctx context.Context, sessionID, invocationID, alias string) (any, apperrors.Error) {
+	if alias != "kubeconfig" {
+		return nil, apperrors.New("unknown alias")
+	}
+	return map[string]any{"cluster": "kind-dev"}, nil
+}
+
 func TestSkillService(t *testing.T) {
 	test.SetupTestCatalog(t)
 	config.SetTestMode(true)
@@ -94,6 +108,20 @@ func TestSkillService(t *testing.T) {
 		require.Equal(t, json.RawMessage("{}"), skills[0].OutputSchema)
 	})
 
+	t.Run("GetToolsInCategory", func(t *testing.T) {
+		ctx := context.Background()
+		sessionID := "test-session"
+
+		skills, err := client.GetSkillsInCategory(ctx, sessionID, "testing")
+		require.NoError(t, err)
+		require.Len(t, skills, 1)
+		require.Equal(t, "testing", skills[0].Category)
+
+		skills, err = client.GetSkillsInCategory(ctx, sessionID, "payments")
+		require.NoError(t, err)
+		require.Empty(t, skills)
+	})
+
 	t.Run("GetContext", func(t *testing.T) {
 		ctx := context.Background()
 		sessionID := "test-session"
@@ -103,6 +131,16 @@ func TestSkillService(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, context)
 	})
+
+	t.Run("GetResource", func(t *testing.T) {
+		ctx := context.Background()
+		resource, err := client.GetResource(ctx, "test-session", "test-invocation-id", "kubeconfig")
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"cluster": "kind-dev"}, resource)
+
+		_, err = client.GetResource(ctx, "test-session", "test-invocation-id", "missing")
+		require.Error(t, err)
+	})
 }
 
 func TestServerStartStop(t *testing.T) {
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tansive/tansive/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// skillsetCacheKey identifies a cached skillset document. Skillset paths are only unique
+// within a tenant, catalog, variant and namespace, so the scope is part of the key.
+type skillsetCacheKey struct {
+	tenantID  catcommon.TenantId
+	catalog   string
+	variant   string
+	namespace string
+	path      string
+}
+
+type skillsetCacheEntry struct {
+	document  []byte
+	fetchedAt time.Time
+}
+
+// skillsetCache is a read-through cache of skillset documents fetched from the catalog server.
+// Documents are cached rather than skillset managers so that each session gets its own manager.
+type skillsetCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	entries    map[skillsetCacheKey]skillsetCacheEntry
+}
+
+func newSkillsetCache(ttl time.Duration, maxEntries int) *skillsetCache {
+	return &skillsetCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[skillsetCacheKey]skillsetCacheEntry),
+	}
+}
+
+// enabled reports whether documents are cached at all.
+func (c *skillsetCache) enabled() bool {
+	return c != nil && c.ttl > 0 && c.maxEntries > 0
+}
+
+// get returns the cached document for key if present and not expired.
+func (c *skillsetCache) get(key skillsetCacheKey) ([]byte, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(entry.fetchedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.document, true
+}
+
+// put caches document under key, evicting the oldest entry if the cache is full.
+func (c *skillsetCache) put(key skillsetCacheKey, document []byte) {
+	if !c.enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		var oldestKey skillsetCacheKey
+		var oldest time.Time
+		first := true
+		for k, e := range c.entries {
+			if first || e.fetchedAt.Before(oldest) {
+				oldestKey, oldest, first = k, e.fetchedAt, false
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = skillsetCacheEntry{document: document, fetchedAt: c.now()}
+}
+
+var (
+	skillsetCacheOnce   sync.Once
+	sharedSkillsetCache *skillsetCache
+)
+
+// getSkillsetCache returns the tangent-wide skillset cache configured from the tangent config.
+func getSkillsetCache() *skillsetCache {
+	skillsetCacheOnce.Do(func() {
+		var ttl time.Duration
+		var maxEntries int
+		if cfg := config.Config(); cfg != nil {
+			ttl = cfg.SkillsetCache.GetTTL()
+			maxEntries = cfg.SkillsetCache.MaxEntries
+		}
+		sharedSkillsetCache = newSkillsetCache(ttl, maxEntries)
+	})
+	return sharedSkillsetCache
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/tangent/test"
+)
+
+// countingSkillsetClient serves the test skillset and counts fetches.
+type countingSkillsetClient struct {
+	httpclient.HTTPClientInterface
+	document []byte
+	fetches  int
+}
+
+func (c *countingSkillsetClient) GetResource(resourceType string, resourceName string, queryParams map[string]string, objectType string) ([]byte, error) {
+	c.fetches++
+	return c.document, nil
+}
+
+func TestSkillsetCache(t *testing.T) {
+	ctx := context.Background()
+	key := skillsetCacheKey{catalog: "test-catalog", variant: "dev", path: "/skillsets/kubernetes-demo"}
+
+	t.Run("cache hit avoids a second fetch", func(t *testing.T) {
+		client := &countingSkillsetClient{document: test.SkillsetDef("dev")}
+		cache := newSkillsetCache(time.Minute, 10)
+
+		sm1, err := getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+		sm2, err := getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, client.fetches)
+		// each session gets its own manager
+		assert.NotSame(t, sm1, sm2)
+
+		// a different scope is cached separately
+		otherVariant := key
+		otherVariant.variant = "prod"
+		_, err = getSkillsetWithCache(ctx, client, cache, otherVariant)
+		require.NoError(t, err)
+		assert.Equal(t, 2, client.fetches)
+	})
+
+	t.Run("ttl expiry forces a refetch", func(t *testing.T) {
+		client := &countingSkillsetClient{document: test.SkillsetDef("dev")}
+		cache := newSkillsetCache(time.Minute, 10)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		_, err := getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+		now = now.Add(30 * time.Second)
+		_, err = getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+		assert.Equal(t, 1, client.fetches)
+
+		now = now.Add(time.Minute)
+		_, err = getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+		assert.Equal(t, 2, client.fetches)
+	})
+
+	t.Run("oldest entry is evicted when full", func(t *testing.T) {
+		cache := newSkillsetCache(time.Minute, 1)
+		other := key
+		other.path = "/skillsets/other"
+		cache.put(key, []byte(`{}`))
+		cache.put(other, []byte(`{}`))
+
+		_, ok := cache.get(key)
+		assert.False(t, ok)
+		_, ok = cache.get(other)
+		assert.True(t, ok)
+	})
+
+	t.Run("disabled cache always fetches", func(t *testing.T) {
+		client := &countingSkillsetClient{document: test.SkillsetDef("dev")}
+		cache := newSkillsetCache(0, 10)
+
+		_, err := getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+		_, err = getSkillsetWithCache(ctx, client, cache, key)
+		require.NoError(t, err)
+		assert.Equal(t, 2, client.fetches)
+	})
+}
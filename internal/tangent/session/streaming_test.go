@@ -0,0 +1,187 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/tangent/config"
+	"github.com/tansive/tansive/internal/tangent/runners"
+	"github.com/tansive/tansive/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive/internal/tangent/test"
+	"github.com/tansive/tansive/pkg/api"
+)
+
+// fakeRunner is a runner that only accepts one-shot runs.
+type fakeRunner struct {
+	writers []*tangentcommon.IOWriters
+}
+
+func (r *fakeRunner) ID() string { return "test.fake" }
+
+func (r *fakeRunner) AddWriters(writers ...*tangentcommon.IOWriters) {
+	r.writers = append(r.writers, writers...)
+}
+
+func (r *fakeRunner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
+	return nil
+}
+
+func (r *fakeRunner) RunMCP(ctx context.Context, args *api.SkillInputArgs) (*mcp.CallToolResult, apperrors.Error) {
+	return nil, nil
+}
+
+func (r *fakeRunner) FetchTools(ctx context.Context) ([]*api.LLMTool, apperrors.Error) {
+	return nil, nil
+}
+
+func (r *fakeRunner) Stop(ctx context.Context) {}
+
+// echoRunner is a streaming runner that writes back every input chunk it receives.
+type echoRunner struct {
+	fakeRunner
+}
+
+func (r *echoRunner) RunStreaming(ctx context.Context, args *api.SkillInputArgs, input <-chan []byte) apperrors.Error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case chunk, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, w := range r.writers {
+				w.Out.Write([]byte("echo: " + string(chunk) + "\n"))
+			}
+		}
+	}
+}
+
+func useTestRunner(t *testing.T, runner runners.Runner) *int {
+	t.Helper()
+	created := 0
+	orig := newRunner
+	t.Cleanup(func() { newRunner = orig })
+	newRunner = func(ctx context.Context, sessionID string, runnerDef catalogmanager.SkillSetSource, sessionVariables map[string]any, writers ...*tangentcommon.IOWriters) (runners.Runner, apperrors.Error) {
+		created++
+		runner.AddWriters(writers...)
+		return runner, nil
+	}
+	return &created
+}
+
+func TestRunStreaming(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	t.Run("inputs are echoed in order", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		useTestRunner(t, &echoRunner{})
+
+		inputs := []string{"first", "second", "third"}
+		input := make(chan []byte)
+		go func() {
+			defer close(input)
+			for _, in := range inputs {
+				input <- []byte(in)
+			}
+		}()
+
+		out := tangentcommon.NewBufferedWriter()
+		err := s.RunStreaming(ctx, "", "list_pods", map[string]any{}, input, &tangentcommon.IOWriters{
+			Out: out,
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo: first", "echo: second", "echo: third"}, strings.Split(strings.TrimSpace(out.String()), "\n"))
+	})
+
+	t.Run("runner without streaming support is rejected", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		useTestRunner(t, &fakeRunner{})
+
+		input := make(chan []byte)
+		close(input)
+		err := s.RunStreaming(ctx, "", "list_pods", map[string]any{}, input)
+		assert.True(t, errors.Is(err, ErrRunnerNotStreaming))
+	})
+
+	t.Run("policy is validated before the runner starts", func(t *testing.T) {
+		viewDef := test.GetViewDefinition("dev")
+		viewDef.BlockedSkills = []string{"list_pods"}
+		s := newTestSession(t, viewDef)
+		created := useTestRunner(t, &echoRunner{})
+
+		input := make(chan []byte)
+		close(input)
+		err := s.RunStreaming(ctx, "", "list_pods", map[string]any{}, input)
+		assert.True(t, errors.Is(err, ErrBlockedByPolicy))
+		assert.Equal(t, 0, *created)
+	})
+}
+
+func TestSessionInputEndpoint(t *testing.T) {
+	config.SetTestMode(true)
+	config.TestInit(t)
+	ctx := context.Background()
+
+	s := newTestSession(t, test.GetViewDefinition("dev"))
+	s.token = "session-token"
+	s.auditLogInfo.auditLogger = zerolog.New(io.Discard)
+	sessionManager.sessions[s.id] = s
+	t.Cleanup(func() { delete(sessionManager.sessions, s.id) })
+	useTestRunner(t, &echoRunner{})
+
+	router := chi.NewRouter()
+	router.Route("/sessions", Router)
+	do := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/sessions/"+s.id.String()+"/input", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer session-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// sessions are not streamed unless requested
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "first").Code)
+
+	s.input = newSessionInput()
+	out := tangentcommon.NewBufferedWriter()
+	done := make(chan apperrors.Error)
+	go func() {
+		err := s.RunStreaming(ctx, "", "list_pods", map[string]any{}, s.input.ch, &tangentcommon.IOWriters{
+			Out: out,
+			Err: tangentcommon.NewBufferedWriter(),
+		})
+		s.input.finish()
+		done <- err
+	}()
+
+	for _, in := range []string{"first", "second"} {
+		rec := do(http.MethodPost, in)
+		require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+	req := httptest.NewRequest(http.MethodPost, "/sessions/"+s.id.String()+"/input", strings.NewReader("third"))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "").Code)
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{"echo: first", "echo: second"}, strings.Split(strings.TrimSpace(out.String()), "\n"))
+
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "late").Code)
+}
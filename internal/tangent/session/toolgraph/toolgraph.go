@@ -15,21 +15,41 @@ type CallID string
 // ToolName represents the name of a tool being invoked.
 type ToolName string
 
+// CallStatus represents the state of a tool invocation.
+type CallStatus string
+
+const (
+	CallStatusRunning   CallStatus = "running"
+	CallStatusCompleted CallStatus = "completed"
+	CallStatusFailed    CallStatus = "failed"
+)
+
 // CallGraph provides functionality to track tool invocation relationships.
 // Prevents infinite loops and enforces depth limits for tool call chains.
 type CallGraph struct {
 	mu        sync.RWMutex
-	parents   map[CallID]CallID   // childID → parentID
-	toolNames map[CallID]ToolName // callID → tool name
+	parents   map[CallID]CallID     // childID → parentID
+	toolNames map[CallID]ToolName   // callID → tool name
+	statuses  map[CallID]CallStatus // callID → status
+	calls     []CallID              // callIDs in registration order
 	maxDepth  int
 }
 
+// CallNode is an invocation in the tree returned by Tree, with the invocations it made.
+type CallNode struct {
+	CallID CallID      `json:"callID"`
+	Tool   ToolName    `json:"skill"`
+	Status CallStatus  `json:"status"`
+	Calls  []*CallNode `json:"calls,omitempty"`
+}
+
 // NewCallGraph creates a new call graph with the specified maximum depth.
 // Returns a call graph instance configured to prevent loops and enforce depth limits.
 func NewCallGraph(maxDepth int) *CallGraph {
 	return &CallGraph{
 		parents:   make(map[CallID]CallID),
 		toolNames: make(map[CallID]ToolName),
+		statuses:  make(map[CallID]CallStatus),
 		maxDepth:  maxDepth,
 	}
 }
@@ -40,6 +60,29 @@ func (g *CallGraph) RegisterCall(parentID CallID, toolName ToolName, newCallID C
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	if err := g.checkCall(parentID, toolName); err != nil {
+		return err
+	}
+
+	// Safe to register
+	g.parents[newCallID] = parentID
+	g.toolNames[newCallID] = toolName
+	g.statuses[newCallID] = CallStatusRunning
+	g.calls = append(g.calls, newCallID)
+	return nil
+}
+
+// CheckCall reports the error RegisterCall would return for a call to toolName made by
+// parentID, without registering it.
+func (g *CallGraph) CheckCall(parentID CallID, toolName ToolName) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.checkCall(parentID, toolName)
+}
+
+// checkCall walks the ancestry of parentID looking for toolName and enforcing maxDepth.
+// Callers must hold g.mu.
+func (g *CallGraph) checkCall(parentID CallID, toolName ToolName) error {
 	depth := 0
 	for id := parentID; id != ""; id = g.parents[id] {
 		if g.toolNames[id] == toolName {
@@ -50,13 +93,59 @@ func (g *CallGraph) RegisterCall(parentID CallID, toolName ToolName, newCallID C
 			return fmt.Errorf("call depth limit exceeded: limit=%d", g.maxDepth)
 		}
 	}
-
-	// Safe to register
-	g.parents[newCallID] = parentID
-	g.toolNames[newCallID] = toolName
 	return nil
 }
 
+// SetStatus records the status of a registered call. Unknown callIDs are ignored.
+func (g *CallGraph) SetStatus(callID CallID, status CallStatus) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.toolNames[callID]; ok {
+		g.statuses[callID] = status
+	}
+}
+
+// Tree returns the root invocations of the graph, each with the tree of invocations it made.
+// Siblings are ordered by registration. The returned nodes are a snapshot of the graph.
+func (g *CallGraph) Tree() []*CallNode {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nodes := make(map[CallID]*CallNode, len(g.calls))
+	roots := []*CallNode{}
+	for _, id := range g.calls {
+		node := &CallNode{CallID: id, Tool: g.toolNames[id], Status: g.statuses[id]}
+		nodes[id] = node
+		if parent, ok := nodes[g.parents[id]]; ok {
+			parent.Calls = append(parent.Calls, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// Restore registers the invocations of a tree returned by Tree, with their statuses.
+// Calls already in the graph are replaced.
+func (g *CallGraph) Restore(calls []*CallNode) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var restore func(parentID CallID, nodes []*CallNode)
+	restore = func(parentID CallID, nodes []*CallNode) {
+		for _, node := range nodes {
+			if _, ok := g.toolNames[node.CallID]; !ok {
+				g.calls = append(g.calls, node.CallID)
+			}
+			g.parents[node.CallID] = parentID
+			g.toolNames[node.CallID] = node.Tool
+			g.statuses[node.CallID] = node.Status
+			restore(node.CallID, node.Calls)
+		}
+	}
+	restore("", calls)
+}
+
 // GetToolName returns the tool name for a given callID.
 func (g *CallGraph) GetToolName(callID CallID) ToolName {
 	g.mu.RLock()
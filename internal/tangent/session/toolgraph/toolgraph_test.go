@@ -38,6 +38,17 @@ func TestRegisterCall_LoopDetection(t *testing.T) {
 	assert.ErrorContains(t, err, "loop detected")
 }
 
+func TestCheckCall(t *testing.T) {
+	g := NewCallGraph(0)
+
+	_ = g.RegisterCall("", "ToolA", "a1")
+	_ = g.RegisterCall("a1", "ToolB", "b1")
+
+	assert.NoError(t, g.CheckCall("b1", "ToolC"))
+	assert.ErrorContains(t, g.CheckCall("b1", "ToolA"), "loop detected")
+	assert.Equal(t, ToolName(""), g.GetToolName("a2"), "CheckCall must not register the call")
+}
+
 func TestRegisterCall_DepthLimit(t *testing.T) {
 	g := NewCallGraph(4)
 
@@ -106,3 +117,44 @@ func TestRegisterCall_ParallelBranches(t *testing.T) {
 	err = g.RegisterCall("d2", "ToolA", "a2")
 	assert.ErrorContains(t, err, "loop detected")
 }
+
+func TestTree(t *testing.T) {
+	g := NewCallGraph(0)
+	assert.Empty(t, g.Tree())
+
+	_ = g.RegisterCall("", "ToolA", "a1")
+	_ = g.RegisterCall("a1", "ToolB", "b1")
+	_ = g.RegisterCall("a1", "ToolC", "c1")
+	_ = g.RegisterCall("b1", "ToolC", "c2")
+	_ = g.RegisterCall("", "ToolD", "d1")
+	g.SetStatus("b1", CallStatusCompleted)
+	g.SetStatus("c1", CallStatusFailed)
+	g.SetStatus("unknown", CallStatusCompleted)
+
+	assert.Equal(t, []*CallNode{
+		{CallID: "a1", Tool: "ToolA", Status: CallStatusRunning, Calls: []*CallNode{
+			{CallID: "b1", Tool: "ToolB", Status: CallStatusCompleted, Calls: []*CallNode{
+				{CallID: "c2", Tool: "ToolC", Status: CallStatusRunning},
+			}},
+			{CallID: "c1", Tool: "ToolC", Status: CallStatusFailed},
+		}},
+		{CallID: "d1", Tool: "ToolD", Status: CallStatusRunning},
+	}, g.Tree())
+	assert.Equal(t, ToolName(""), g.GetToolName("unknown"))
+}
+
+func TestRestore(t *testing.T) {
+	g := NewCallGraph(3)
+	_ = g.RegisterCall("", "ToolA", "a1")
+	_ = g.RegisterCall("a1", "ToolB", "b1")
+	_ = g.RegisterCall("", "ToolC", "c1")
+	g.SetStatus("b1", CallStatusCompleted)
+
+	restored := NewCallGraph(3)
+	restored.Restore(g.Tree())
+	assert.Equal(t, g.Tree(), restored.Tree())
+
+	// restored calls keep enforcing loop detection and depth limits
+	assert.ErrorContains(t, restored.RegisterCall("b1", "ToolA", "a2"), "loop detected")
+	assert.NoError(t, restored.RegisterCall("b1", "ToolD", "d1"))
+}
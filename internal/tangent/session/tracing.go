@@ -0,0 +1,106 @@
+package session
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tansive/tansive/internal/tangent/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/tansive/tansive/internal/tangent/session"
+
+var (
+	tracerMu    sync.RWMutex
+	skillTracer trace.Tracer = noop.NewTracerProvider().Tracer(tracerName)
+)
+
+// InitTracing configures export of skill invocation spans to the OTLP endpoint in the tangent config.
+// Exporting is disabled when no endpoint is configured.
+// Returns a shutdown function that flushes pending spans.
+func InitTracing(ctx context.Context) (func(context.Context) error, error) {
+	endpoint := config.Config().Telemetry.OTLPEndpoint
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("tangent"))),
+	)
+	setTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// setTracerProvider replaces the provider used to create skill spans.
+func setTracerProvider(tp trace.TracerProvider) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+	skillTracer = tp.Tracer(tracerName)
+}
+
+func getTracer() trace.Tracer {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+	return skillTracer
+}
+
+// startSkillSpan starts a span for a skill invocation. The span is parented to the span of the
+// invoking skill, mirroring the call graph, and is a new root if the skill has no invoker.
+func (s *session) startSkillSpan(ctx context.Context, invokerID, invocationID, skillName string) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			attribute.String("tansive.session_id", s.id.String()),
+			attribute.String("tansive.skill.name", skillName),
+			attribute.String("tansive.skill.invocation_id", invocationID),
+		),
+	}
+	if parent, ok := s.invocationSpans.Load(invokerID); ok {
+		ctx = trace.ContextWithSpanContext(ctx, parent.(trace.SpanContext))
+		opts = append(opts, trace.WithAttributes(attribute.String("tansive.skill.invoker_id", invokerID)))
+	} else {
+		opts = append(opts, trace.WithNewRoot())
+	}
+
+	ctx, span := getTracer().Start(ctx, "skill "+skillName, opts...)
+	s.invocationSpans.Store(invocationID, span.SpanContext())
+	return ctx, span
+}
+
+// endSkillSpan records the outcome of a skill invocation and ends its span. The invocation is
+// removed from the spans tracked for parenting, since its children have completed by now.
+func (s *session) endSkillSpan(invocationID string, span trace.Span, err error) {
+	defer s.invocationSpans.Delete(invocationID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
+
+// setSpanPolicyDecision records the policy decision for a skill invocation on its span.
+func setSpanPolicyDecision(ctx context.Context, decision, reason string) {
+	attrs := []attribute.KeyValue{attribute.String("tansive.policy.decision", decision)}
+	if reason != "" {
+		attrs = append(attrs, attribute.String("tansive.policy.reason", reason))
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// setSpanRunner records the runner that executed a skill invocation on its span.
+func setSpanRunner(ctx context.Context, runnerID string) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tansive.skill.runner", runnerID))
+}
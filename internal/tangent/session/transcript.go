@@ -0,0 +1,160 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	srvsession "github.com/tansive/tansive/internal/catalogsrv/session"
+	"github.com/tansive/tansive/internal/common/apperrors"
+	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/tangent/config"
+)
+
+// TranscriptPersistAnnotation is the skill annotation that enables transcript persistence for
+// interactive sessions running the skill when persist_transcripts is not set in the configuration.
+const TranscriptPersistAnnotation = "transcript:persist"
+
+// transcriptRecorder collects the output written by skills during an interactive session.
+// If enabled is set, it is consulted once, on the first skill output, to decide whether
+// the session's output is recorded at all.
+type transcriptRecorder struct {
+	mu          sync.Mutex
+	maxSize     int
+	size        int
+	truncated   bool
+	chunks      []srvsession.TranscriptChunk
+	enabled     func() bool
+	enabledOnce sync.Once
+	disabled    bool
+}
+
+func newTranscriptRecorder(maxSize int) *transcriptRecorder {
+	return &transcriptRecorder{maxSize: maxSize}
+}
+
+// interactiveLogEntry holds the fields of an interactive log event used to build a transcript chunk.
+type interactiveLogEntry struct {
+	Time    json.RawMessage `json:"time"`
+	Source  string          `json:"source"`
+	Skill   string          `json:"skill"`
+	Message string          `json:"message"`
+}
+
+// parseLogTime converts a log timestamp, written either in Unix milliseconds or RFC3339, to a time.
+func parseLogTime(raw json.RawMessage) time.Time {
+	var ms int64
+	if err := json.Unmarshal(raw, &ms); err == nil {
+		return time.UnixMilli(ms).UTC()
+	}
+	var t time.Time
+	if err := json.Unmarshal(raw, &t); err == nil {
+		return t.UTC()
+	}
+	return time.Time{}
+}
+
+// record adds an interactive log event to the transcript. Events that are not skill output
+// are ignored. Once the recorded output reaches the size limit, further output is dropped
+// and the transcript is marked as truncated.
+func (t *transcriptRecorder) record(data []byte) {
+	var entry interactiveLogEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return
+	}
+	if entry.Source != srvsession.TranscriptStreamStdout && entry.Source != srvsession.TranscriptStreamStderr {
+		return
+	}
+
+	if t.enabled != nil {
+		t.enabledOnce.Do(func() { t.disabled = !t.enabled() })
+		if t.disabled {
+			return
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.truncated {
+		return
+	}
+	if t.maxSize > 0 && t.size+len(entry.Message) > t.maxSize {
+		t.truncated = true
+		return
+	}
+	t.size += len(entry.Message)
+	t.chunks = append(t.chunks, srvsession.TranscriptChunk{
+		Seq:    int64(len(t.chunks) + 1),
+		Time:   parseLogTime(entry.Time),
+		Stream: entry.Source,
+		Skill:  entry.Skill,
+		Data:   entry.Message,
+	})
+}
+
+// transcript returns a snapshot of the recorded transcript.
+func (t *transcriptRecorder) transcript() *srvsession.Transcript {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	chunks := make([]srvsession.TranscriptChunk, len(t.chunks))
+	copy(chunks, t.chunks)
+	return &srvsession.Transcript{
+		Truncated: t.truncated,
+		Chunks:    chunks,
+	}
+}
+
+// transcriptEnabled reports whether the transcript of this session should be persisted, either
+// because it is enabled for all sessions or because the session's skill opts in. The skill is
+// resolved when this is called, so it must not be called before the session's objects are fetched.
+func (s *session) transcriptEnabled() bool {
+	if config.Config().AuditLog.PersistTranscripts {
+		return true
+	}
+	if s.skillSet == nil || s.context == nil {
+		return false
+	}
+	skill, err := s.skillSet.GetSkill(s.context.Skill)
+	if err != nil {
+		return false
+	}
+	return skill.Annotations[TranscriptPersistAnnotation] == "true"
+}
+
+// recordTranscript adds an interactive log event to the session transcript if persistence is enabled.
+func (s *session) recordTranscript(data []byte) {
+	if s.transcript == nil {
+		return
+	}
+	s.transcript.record(data)
+}
+
+// uploadTranscript ships the recorded transcript to the tansive server. Does nothing if
+// nothing was recorded.
+func (s *session) uploadTranscript(ctx context.Context, client httpclient.HTTPClientInterface) apperrors.Error {
+	if s.transcript == nil {
+		return nil
+	}
+	transcript := s.transcript.transcript()
+	if len(transcript.Chunks) == 0 && !transcript.Truncated {
+		return nil
+	}
+
+	body, err := json.Marshal(transcript)
+	if err != nil {
+		return ErrFailedRequestToTansiveServer.Msg(err.Error())
+	}
+	_, _, err = client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPut,
+		Path:   "sessions/transcript",
+		Body:   body,
+	})
+	if err != nil {
+		return ErrFailedRequestToTansiveServer.Msg(err.Error())
+	}
+	log.Ctx(ctx).Info().Int("chunks", len(transcript.Chunks)).Msg("uploaded session transcript")
+	return nil
+}
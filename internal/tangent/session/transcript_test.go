@@ -0,0 +1,120 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	srvsession "github.com/tansive/tansive/internal/catalogsrv/session"
+	"github.com/tansive/tansive/internal/common/httpclient"
+	"github.com/tansive/tansive/internal/tangent/test"
+)
+
+// capturingTranscriptClient records the requests made to the tansive server.
+type capturingTranscriptClient struct {
+	httpclient.HTTPClientInterface
+	requests []httpclient.RequestOptions
+}
+
+func (c *capturingTranscriptClient) DoRequest(opts httpclient.RequestOptions) ([]byte, string, error) {
+	c.requests = append(c.requests, opts)
+	return nil, "", nil
+}
+
+// emitInteractiveOutput writes output the way a runner does during an interactive session
+// and feeds the published events to the session's transcript recorder.
+func emitInteractiveOutput(t *testing.T, s *session, writes []srvsession.TranscriptChunk) {
+	t.Helper()
+	events, unsubscribe := GetEventBus().Subscribe(s.getTopic(TopicInteractiveLog), 100)
+	defer unsubscribe()
+
+	for _, w := range writes {
+		writer := s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", w.Stream).Str("runner", "test").Str("skill", w.Skill).Logger()
+		_, err := writer.Write([]byte(w.Data))
+		require.NoError(t, err)
+	}
+	// system messages share the topic but are not skill output
+	systemLogger := s.getLogger(TopicInteractiveLog)
+	systemLogger.Info().Str("actor", "system").Msg("running skill")
+
+	for range len(writes) + 1 {
+		select {
+		case event := <-events:
+			data, ok := event.Data.([]byte)
+			require.True(t, ok)
+			s.transcript.record(data)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for interactive log event")
+		}
+	}
+}
+
+func TestSessionTranscript(t *testing.T) {
+	writes := []srvsession.TranscriptChunk{
+		{Stream: srvsession.TranscriptStreamStdout, Skill: "list_pods", Data: "NAME READY STATUS"},
+		{Stream: srvsession.TranscriptStreamStderr, Skill: "list_pods", Data: "warning: namespace not set"},
+		{Stream: srvsession.TranscriptStreamStdout, Skill: "list_pods", Data: "api-server 1/1 Running"},
+	}
+
+	t.Run("recorded chunks match the emitted output", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.transcript = newTranscriptRecorder(0)
+		emitInteractiveOutput(t, s, writes)
+
+		transcript := s.transcript.transcript()
+		assert.False(t, transcript.Truncated)
+		require.Len(t, transcript.Chunks, len(writes))
+		for i, chunk := range transcript.Chunks {
+			assert.Equal(t, writes[i].Stream, chunk.Stream)
+			assert.Equal(t, writes[i].Skill, chunk.Skill)
+			assert.Equal(t, writes[i].Data, chunk.Data)
+			assert.Equal(t, int64(i+1), chunk.Seq)
+			assert.False(t, chunk.Time.IsZero())
+		}
+
+		client := &capturingTranscriptClient{}
+		require.NoError(t, s.uploadTranscript(context.Background(), client))
+		require.Len(t, client.requests, 1)
+		assert.Equal(t, "sessions/transcript", client.requests[0].Path)
+
+		var uploaded srvsession.Transcript
+		require.NoError(t, json.Unmarshal(client.requests[0].Body, &uploaded))
+		assert.Equal(t, transcript.Chunks, uploaded.Chunks)
+	})
+
+	t.Run("output beyond the size limit is dropped", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.transcript = newTranscriptRecorder(len(writes[0].Data) + len(writes[1].Data))
+		emitInteractiveOutput(t, s, writes)
+
+		transcript := s.transcript.transcript()
+		assert.True(t, transcript.Truncated)
+		assert.Len(t, transcript.Chunks, 2)
+	})
+
+	t.Run("skill is resolved once per session", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.transcript = newTranscriptRecorder(0)
+		calls := 0
+		s.transcript.enabled = func() bool {
+			calls++
+			return false
+		}
+		emitInteractiveOutput(t, s, writes)
+
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, s.transcript.transcript().Chunks)
+	})
+
+	t.Run("empty transcript is not uploaded", func(t *testing.T) {
+		s := newTestSession(t, test.GetViewDefinition("dev"))
+		s.transcript = newTranscriptRecorder(0)
+
+		client := &capturingTranscriptClient{}
+		require.NoError(t, s.uploadTranscript(context.Background(), client))
+		assert.Empty(t, client.requests)
+	})
+}
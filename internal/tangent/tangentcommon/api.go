@@ -15,4 +15,5 @@ type SessionCreateRequest struct {
 	SessionType  SessionType `json:"sessionType"`   // type of session to create
 	CodeVerifier string      `json:"code_verifier"` // PKCE code verifier for OAuth flow
 	Code         string      `json:"code"`          // authorization code for session creation
+	StreamInput  bool        `json:"streamInput"`   // stream input to the skill through the session's input endpoint
 }
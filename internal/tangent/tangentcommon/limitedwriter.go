@@ -0,0 +1,84 @@
+package tangentcommon
+
+import (
+	"io"
+	"sync"
+)
+
+// TruncationMarker is written once to a LimitedWriter's destination when output is truncated.
+const TruncationMarker = "\n[output truncated]\n"
+
+// LimitedWriter forwards at most a fixed number of bytes to an underlying writer.
+// Writes beyond the limit are dropped and counted, and a truncation marker is written once.
+// It is safe for concurrent use.
+type LimitedWriter struct {
+	mu        sync.Mutex
+	w         io.Writer // destination writer
+	limit     int64     // maximum bytes forwarded; 0 or less means unlimited
+	written   int64     // bytes forwarded so far
+	dropped   int64     // bytes dropped after the limit was reached
+	truncated bool      // whether the truncation marker has been written
+}
+
+// NewLimitedWriter constructs a LimitedWriter that forwards at most limit bytes to w.
+// A limit of 0 or less disables truncation.
+func NewLimitedWriter(w io.Writer, limit int64) *LimitedWriter {
+	return &LimitedWriter{w: w, limit: limit}
+}
+
+// Write implements io.Writer interface.
+// It always reports the full length of p as written so that callers are not failed by truncation.
+func (l *LimitedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		n, err := l.w.Write(p)
+		l.written += int64(n)
+		return n, err
+	}
+
+	remaining := l.limit - l.written
+	if remaining > int64(len(p)) {
+		remaining = int64(len(p))
+	}
+	if remaining > 0 {
+		n, err := l.w.Write(p[:remaining])
+		l.written += int64(n)
+		if err != nil {
+			return n, err
+		}
+	}
+	if overflow := int64(len(p)) - remaining; overflow > 0 {
+		l.dropped += overflow
+		if !l.truncated {
+			l.truncated = true
+			if _, err := io.WriteString(l.w, TruncationMarker); err != nil {
+				return len(p), err
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of bytes discarded because the limit was reached.
+func (l *LimitedWriter) Dropped() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped
+}
+
+// Truncated reports whether any output has been dropped.
+func (l *LimitedWriter) Truncated() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.truncated
+}
+
+// Reset clears the bytes forwarded and dropped so far, so that the limit applies afresh to
+// subsequent writes. The destination writer is not reset.
+func (l *LimitedWriter) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.written, l.dropped, l.truncated = 0, 0, false
+}
@@ -0,0 +1,86 @@
+package tangentcommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitedWriter(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		writes        []string
+		expected      string
+		expectDropped int64
+	}{
+		{
+			name:     "below limit",
+			limit:    10,
+			writes:   []string{"hello"},
+			expected: "hello",
+		},
+		{
+			name:     "exactly at limit",
+			limit:    10,
+			writes:   []string{"hello", "world"},
+			expected: "helloworld",
+		},
+		{
+			name:          "one byte over limit",
+			limit:         10,
+			writes:        []string{"helloworld!"},
+			expected:      "helloworld" + TruncationMarker,
+			expectDropped: 1,
+		},
+		{
+			name:          "write crossing limit",
+			limit:         7,
+			writes:        []string{"hello", "world"},
+			expected:      "hellowo" + TruncationMarker,
+			expectDropped: 3,
+		},
+		{
+			name:          "writes after limit are dropped",
+			limit:         5,
+			writes:        []string{"hello", "world", "again"},
+			expected:      "hello" + TruncationMarker,
+			expectDropped: 10,
+		},
+		{
+			name:     "no limit",
+			limit:    0,
+			writes:   []string{"hello", "world"},
+			expected: "helloworld",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewBufferedWriter()
+			w := NewLimitedWriter(buf, tt.limit)
+			for _, s := range tt.writes {
+				n, err := w.Write([]byte(s))
+				assert.NoError(t, err)
+				assert.Equal(t, len(s), n, "writes always report full length")
+			}
+			assert.Equal(t, tt.expected, buf.String())
+			assert.Equal(t, tt.expectDropped, w.Dropped())
+			assert.Equal(t, tt.expectDropped > 0, w.Truncated())
+		})
+	}
+}
+
+func TestLimitedWriterReset(t *testing.T) {
+	buf := NewBufferedWriter()
+	w := NewLimitedWriter(buf, 3)
+	w.Write([]byte("hello"))
+	assert.True(t, w.Truncated())
+
+	buf.Reset()
+	w.Reset()
+	assert.False(t, w.Truncated())
+	assert.Equal(t, int64(0), w.Dropped())
+	w.Write([]byte("abc"))
+	assert.Equal(t, "abc", buf.String())
+}
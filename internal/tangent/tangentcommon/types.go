@@ -7,15 +7,57 @@ import (
 	"context"
 	"io"
 
+	"github.com/rs/zerolog"
 	"github.com/tansive/tansive/internal/common/apperrors"
 	"github.com/tansive/tansive/pkg/api"
 )
 
+// OutputSource identifies the stream a skill's output was written to.
+type OutputSource string
+
+const (
+	OutputSourceStdout OutputSource = "stdout"
+	OutputSourceStderr OutputSource = "stderr"
+)
+
+// Level returns the level of output written to the source: error for stderr and info otherwise.
+func (s OutputSource) Level() zerolog.Level {
+	if s == OutputSourceStderr {
+		return zerolog.ErrorLevel
+	}
+	return zerolog.InfoLevel
+}
+
 // IOWriters provides stdout and stderr writers for command output.
-// Both Out and Err must implement io.Writer for proper I/O handling.
+// Source and Level filter the output the writers receive; by default they receive all output.
+// Out and Err must be set for each source the writers accept.
 type IOWriters struct {
-	Out io.Writer // stdout writer, must implement io.Writer
-	Err io.Writer // stderr writer, must implement io.Writer
+	Out    io.Writer     // stdout writer
+	Err    io.Writer     // stderr writer
+	Source OutputSource  // if set, only output from this source is written
+	Level  zerolog.Level // minimum level of output written; see OutputSource.Level
+}
+
+// Accepts reports whether output from source passes the writers' source and level filters.
+func (w *IOWriters) Accepts(source OutputSource) bool {
+	if w.Source != "" && w.Source != source {
+		return false
+	}
+	return source.Level() >= w.Level
+}
+
+// Valid reports whether a writer is set for every source the writers accept.
+func (w *IOWriters) Valid() bool {
+	if w == nil {
+		return false
+	}
+	if w.Accepts(OutputSourceStdout) && w.Out == nil {
+		return false
+	}
+	if w.Accepts(OutputSourceStderr) && w.Err == nil {
+		return false
+	}
+	return true
 }
 
 // RunParams defines parameters for skill execution.
@@ -31,11 +73,15 @@ type RunParams struct {
 // Provides methods for skill listing, context management, and skill execution.
 type SkillManager interface {
 	// GetSkills retrieves available skills for a session as LLM tools.
-	GetSkills(ctx context.Context, sessionID string) ([]api.LLMTool, apperrors.Error)
+	// If category is not empty, only skills in that category are returned.
+	GetSkills(ctx context.Context, sessionID, category string) ([]api.LLMTool, apperrors.Error)
 
 	// GetContext retrieves a context value for a session and invocation.
 	GetContext(ctx context.Context, sessionID, invocationID, name string) (any, apperrors.Error)
 
+	// GetResource retrieves the value of a resource dependency of the session's skillset by alias.
+	GetResource(ctx context.Context, sessionID, invocationID, alias string) (any, apperrors.Error)
+
 	// Run executes a skill with the given parameters.
 	Run(ctx context.Context, params *RunParams) (map[string]any, apperrors.Error)
 }
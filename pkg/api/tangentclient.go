@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -162,9 +163,21 @@ func (c *Client) InvokeSkill(ctx context.Context, sessionID, invocationID, skill
 // It sends a GET request to the skills endpoint and returns the available LLM tools.
 // Returns an error if the request fails after all retry attempts.
 func (c *Client) GetSkills(ctx context.Context, sessionID string) ([]LLMTool, error) {
+	return c.GetSkillsInCategory(ctx, sessionID, "")
+}
+
+// GetSkillsInCategory retrieves the skills of a session that belong to the given category.
+// An empty category returns all skills, as GetSkills does.
+// Returns an error if the request fails after all retry attempts.
+func (c *Client) GetSkillsInCategory(ctx context.Context, sessionID, category string) ([]LLMTool, error) {
+	query := url.Values{}
+	query.Set("session_id", sessionID)
+	if category != "" {
+		query.Set("category", category)
+	}
 	var lastErr error
 	for i := 0; i < c.config.maxRetries; i++ {
-		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("http://unix/skills?session_id=%s", sessionID), nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", "http://unix/skills?"+query.Encode(), nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
@@ -224,3 +237,40 @@ func (c *Client) GetContext(ctx context.Context, sessionID, invocationID, name s
 
 	return nil, fmt.Errorf("failed to get context after %d retries: %w", c.config.maxRetries, lastErr)
 }
+
+// GetResource retrieves the value of a resource dependency of the session's skillset by alias.
+// It sends a GET request to the resources endpoint of the skill service.
+// Returns the resource value or an error if the request fails.
+func (c *Client) GetResource(ctx context.Context, sessionID, invocationID, alias string) (any, error) {
+	var lastErr error
+	for i := 0; i < c.config.maxRetries; i++ {
+		query := url.Values{}
+		query.Set("session_id", sessionID)
+		query.Set("invocation_id", invocationID)
+		query.Set("alias", alias)
+		req, err := http.NewRequestWithContext(ctx, "GET", "http://unix/resources?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			time.Sleep(c.config.retryDelay)
+			continue
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("get resource failed: %s", string(respBody))
+		}
+
+		var resource any
+		if err := json.NewDecoder(resp.Body).Decode(&resource); err != nil {
+			return nil, fmt.Errorf("failed to decode resource: %w", err)
+		}
+		return resource, nil
+	}
+
+	return nil, fmt.Errorf("failed to get resource after %d retries: %w", c.config.maxRetries, lastErr)
+}
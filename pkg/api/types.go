@@ -4,13 +4,40 @@ package api
 import "encoding/json"
 
 // LLMTool represents a skill or tool that can be invoked by the LLM.
-// It contains metadata about the tool including its name, description, and input/output schemas.
+// It contains metadata about the tool including its name, description, input/output schemas,
+// example outputs and the format in which its results are returned.
 type LLMTool struct {
-	Name         string          `json:"name"`
-	Description  string          `json:"description,omitempty"`
-	InputSchema  json.RawMessage `json:"inputSchema,omitempty"`
-	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
-	Annotations  json.RawMessage `json:"annotations,omitempty"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description,omitempty"`
+	Category     string            `json:"category,omitempty"`
+	InputSchema  json.RawMessage   `json:"inputSchema,omitempty"`
+	OutputSchema json.RawMessage   `json:"outputSchema,omitempty"`
+	Examples     []json.RawMessage `json:"examples,omitempty"`
+	Annotations  json.RawMessage   `json:"annotations,omitempty"`
+	ResultFormat ResultFormat      `json:"resultFormat,omitempty"`
+}
+
+// ResultFormat is the format in which a tool's results are returned to an LLM.
+type ResultFormat string
+
+const (
+	// ResultFormatJSON returns results as JSON values.
+	ResultFormatJSON ResultFormat = "json"
+
+	// ResultFormatText returns results as plain text, exactly as produced by the skill.
+	ResultFormatText ResultFormat = "text"
+
+	// ResultFormatMarkdown returns results as markdown, with JSON output in a fenced code block.
+	ResultFormatMarkdown ResultFormat = "markdown"
+)
+
+// IsValid reports whether f is a supported result format.
+func (f ResultFormat) IsValid() bool {
+	switch f {
+	case ResultFormatJSON, ResultFormatText, ResultFormatMarkdown:
+		return true
+	}
+	return false
 }
 
 // RunMode defines the execution mode for skill invocations.
@@ -29,14 +56,16 @@ const (
 
 // SkillInputArgs contains all the input parameters required for skill execution.
 // It includes session information, invocation details, and the actual input arguments for the skill.
+// Preflight holds the output of the skillset's preflight skill, if it has one.
 type SkillInputArgs struct {
-	InvocationID     string         `json:"invocationID"`
-	ServiceEndpoint  string         `json:"serviceEndpoint"`
-	RunMode          RunMode        `json:"runMode"`
-	SessionID        string         `json:"sessionID"`
-	SkillName        string         `json:"skillName"`
-	InputArgs        map[string]any `json:"inputArgs"`
-	SessionVariables map[string]any `json:"sessionVariables"`
+	InvocationID     string          `json:"invocationID"`
+	ServiceEndpoint  string          `json:"serviceEndpoint"`
+	RunMode          RunMode         `json:"runMode"`
+	SessionID        string          `json:"sessionID"`
+	SkillName        string          `json:"skillName"`
+	InputArgs        map[string]any  `json:"inputArgs"`
+	SessionVariables map[string]any  `json:"sessionVariables"`
+	Preflight        json.RawMessage `json:"preflight,omitempty"`
 }
 
 // TansiveSystemMessage is the standard system message that should be used
@@ -133,6 +133,51 @@ EXECUTE FUNCTION set_updated_at();
 CREATE INDEX IF NOT EXISTS idx_skillset_directory_hash_gin
 ON skillset_directory USING GIN (jsonb_path_query_array(directory, '$.*.hash'));
 
+-- Searchable text for skillsets, keyed by their path in the skillset directory.
+CREATE TABLE IF NOT EXISTS skillset_search (
+  tenant_id VARCHAR(10) NOT NULL,
+  directory_id UUID NOT NULL,
+  path VARCHAR(512) NOT NULL,
+  name VARCHAR(512) NOT NULL,
+  namespace VARCHAR(128) NOT NULL DEFAULT '',
+  description TEXT,
+  skills TEXT,
+  search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', replace(name, '/', ' ') || ' ' || coalesce(description, '') || ' ' || coalesce(skills, ''))
+  ) STORED,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, directory_id, path),
+  FOREIGN KEY (tenant_id, directory_id) REFERENCES skillset_directory(tenant_id, directory_id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER update_skillset_search_updated_at
+BEFORE UPDATE ON skillset_search
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_skillset_search_vector
+ON skillset_search USING GIN (search_vector);
+
+-- Saved versions of each skillset, keyed by their path in the skillset directory and spec version.
+-- Each version refers to the stored skillset object by its hash.
+CREATE TABLE IF NOT EXISTS skillset_versions (
+  tenant_id VARCHAR(10) NOT NULL,
+  directory_id UUID NOT NULL,
+  path VARCHAR(512) NOT NULL,
+  version VARCHAR(128) NOT NULL,
+  hash CHAR(128) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, directory_id, path, version),
+  FOREIGN KEY (tenant_id, directory_id) REFERENCES skillset_directory(tenant_id, directory_id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER update_skillset_versions_updated_at
+BEFORE UPDATE ON skillset_versions
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 CREATE TABLE IF NOT EXISTS namespaces (
   name VARCHAR(128) NOT NULL,
   variant_id UUID NOT NULL,
@@ -157,6 +202,7 @@ CREATE TABLE IF NOT EXISTS views (
   description VARCHAR(1024),
   info JSONB,
   rules JSONB NOT NULL,
+  token_epoch BIGINT NOT NULL DEFAULT 0,
   catalog_id UUID NOT NULL,
   created_by VARCHAR(128) NOT NULL,
   updated_by VARCHAR(128) NOT NULL,
@@ -166,6 +212,9 @@ CREATE TABLE IF NOT EXISTS views (
   UNIQUE (tenant_id, catalog_id, label),
   PRIMARY KEY (tenant_id, view_id),
   FOREIGN KEY (tenant_id, catalog_id) REFERENCES catalogs(tenant_id, catalog_id) ON DELETE CASCADE,
+  search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(label, '') || ' ' || coalesce(description, ''))
+  ) STORED,
   CHECK (label IS NULL OR label ~ '^[A-Za-z0-9_-]+$')  -- CHECK constraint to allow only alphanumeric and underscore in label
 );
 
@@ -174,16 +223,24 @@ BEFORE UPDATE ON views
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+CREATE INDEX IF NOT EXISTS idx_views_search_vector
+ON views USING GIN (search_vector);
+
 CREATE TABLE IF NOT EXISTS view_tokens (
   token_id UUID NOT NULL DEFAULT uuid_generate_v4(),
   view_id UUID NOT NULL,
   tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  session_id UUID,
   expire_at TIMESTAMPTZ NOT NULL,
   created_at TIMESTAMPTZ DEFAULT NOW(),
   updated_at TIMESTAMPTZ DEFAULT NOW(),
   PRIMARY KEY (tenant_id, token_id)
 );
 
+CREATE INDEX IF NOT EXISTS idx_view_tokens_session
+ON view_tokens (tenant_id, session_id)
+WHERE session_id IS NOT NULL;
+
 CREATE TRIGGER update_view_tokens_updated_at
 BEFORE UPDATE ON view_tokens
 FOR EACH ROW
@@ -226,6 +283,8 @@ CREATE TABLE IF NOT EXISTS sessions (
   ended_at TIMESTAMPTZ NOT NULL,
   updated_at TIMESTAMPTZ DEFAULT NOW(),
   expires_at TIMESTAMPTZ NOT NULL,
+  heartbeat_at TIMESTAMPTZ,
+  labels JSONB NOT NULL DEFAULT '{}',
   PRIMARY KEY (tenant_id, session_id),
   FOREIGN KEY (tenant_id, catalog_id) REFERENCES catalogs(tenant_id, catalog_id) ON DELETE CASCADE
 );
@@ -238,12 +297,23 @@ EXECUTE FUNCTION set_updated_at();
 CREATE INDEX IF NOT EXISTS idx_sessions_tenant_catalog_status
 ON sessions (tenant_id, catalog_id, status_summary);
 
+CREATE INDEX IF NOT EXISTS idx_sessions_ended_at
+ON sessions (ended_at);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_labels
+ON sessions USING GIN (labels);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_heartbeat_at
+ON sessions (heartbeat_at)
+WHERE heartbeat_at IS NOT NULL;
+
 CREATE TABLE IF NOT EXISTS tangents (
   id UUID NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
   public_key BYTEA NOT NULL,
   info JSONB,
   status VARCHAR(128) NOT NULL,
   tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  last_heartbeat_at TIMESTAMPTZ DEFAULT NOW(),
   created_at TIMESTAMPTZ DEFAULT NOW(),
   updated_at TIMESTAMPTZ DEFAULT NOW()
 );
@@ -254,6 +324,54 @@ BEFORE UPDATE ON tangents
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+-- Number of objects of each kind owned by a tenant, checked against the tenant's quota. The
+-- server counts new objects in the transaction that creates them. Deleted catalogs, variants and
+-- skillset directories, including those deleted by cascade, are counted by the triggers below.
+CREATE TABLE IF NOT EXISTS tenant_usage (
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  kind VARCHAR(32) NOT NULL,
+  count INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, kind)
+);
+
+CREATE TRIGGER update_tenant_usage_updated_at
+BEFORE UPDATE ON tenant_usage
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Releases the usage of a deleted row. The kind is passed as the trigger argument; a skillset
+-- directory releases one skillset per path it holds.
+CREATE OR REPLACE FUNCTION release_tenant_usage()
+RETURNS TRIGGER AS $$
+DECLARE
+  released INTEGER := 1;
+BEGIN
+  IF TG_ARGV[0] = 'skillsets' THEN
+    SELECT COUNT(*) INTO released FROM jsonb_object_keys(OLD.directory);
+  END IF;
+  UPDATE tenant_usage SET count = GREATEST(count - released, 0)
+  WHERE tenant_id = OLD.tenant_id AND kind = TG_ARGV[0];
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER release_catalogs_usage
+AFTER DELETE ON catalogs
+FOR EACH ROW
+EXECUTE FUNCTION release_tenant_usage('catalogs');
+
+CREATE TRIGGER release_variants_usage
+AFTER DELETE ON variants
+FOR EACH ROW
+EXECUTE FUNCTION release_tenant_usage('variants');
+
+CREATE TRIGGER release_skillsets_usage
+AFTER DELETE ON skillset_directory
+FOR EACH ROW
+EXECUTE FUNCTION release_tenant_usage('skillsets');
+
 GRANT ALL PRIVILEGES ON TABLE
 	tenants,
 	projects,
@@ -262,12 +380,15 @@ GRANT ALL PRIVILEGES ON TABLE
   catalog_objects,
   resource_directory,
   skillset_directory,
+  skillset_search,
+  skillset_versions,
   namespaces,
   views,
   view_tokens,
   signing_keys,
   sessions,
-  tangents
+  tangents,
+  tenant_usage
 TO catalogrw;
 
 GRANT USAGE, SELECT ON SEQUENCE catalog_objects_id_seq TO catalogrw;
@@ -0,0 +1,38 @@
+-- Adds catalog search to a database created before it was introduced. Safe to run more than once.
+-- Existing views are indexed when the generated column is added. Existing skillsets are indexed
+-- by the server when it starts.
+
+ALTER TABLE views ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+  to_tsvector('simple', coalesce(label, '') || ' ' || coalesce(description, ''))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_views_search_vector
+ON views USING GIN (search_vector);
+
+CREATE TABLE IF NOT EXISTS skillset_search (
+  tenant_id VARCHAR(10) NOT NULL,
+  directory_id UUID NOT NULL,
+  path VARCHAR(512) NOT NULL,
+  name VARCHAR(512) NOT NULL,
+  namespace VARCHAR(128) NOT NULL DEFAULT '',
+  description TEXT,
+  skills TEXT,
+  search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', replace(name, '/', ' ') || ' ' || coalesce(description, '') || ' ' || coalesce(skills, ''))
+  ) STORED,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, directory_id, path),
+  FOREIGN KEY (tenant_id, directory_id) REFERENCES skillset_directory(tenant_id, directory_id) ON DELETE CASCADE
+);
+
+DROP TRIGGER IF EXISTS update_skillset_search_updated_at ON skillset_search;
+CREATE TRIGGER update_skillset_search_updated_at
+BEFORE UPDATE ON skillset_search
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_skillset_search_vector
+ON skillset_search USING GIN (search_vector);
+
+GRANT ALL PRIVILEGES ON TABLE skillset_search TO catalogrw;
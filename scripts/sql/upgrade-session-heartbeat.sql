@@ -0,0 +1,9 @@
+-- Adds the time of the last heartbeat of each session to a database created before sessions sent
+-- heartbeats. Safe to run more than once. Liveness tracking of existing sessions starts with their
+-- first heartbeat after the upgrade.
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_heartbeat_at
+ON sessions (heartbeat_at)
+WHERE heartbeat_at IS NOT NULL;
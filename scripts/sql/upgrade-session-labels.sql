@@ -0,0 +1,7 @@
+-- Adds labels to the sessions of a database created before sessions could be labeled. Safe to run
+-- more than once. Existing sessions have no labels.
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_sessions_labels
+ON sessions USING GIN (labels);
@@ -0,0 +1,5 @@
+-- Adds the time of the last heartbeat of each tangent to a database created before tangents sent
+-- heartbeats. Safe to run more than once. Existing tangents are treated as alive at the time of
+-- the upgrade.
+
+ALTER TABLE tangents ADD COLUMN IF NOT EXISTS last_heartbeat_at TIMESTAMPTZ DEFAULT NOW();
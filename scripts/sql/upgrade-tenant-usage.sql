@@ -0,0 +1,60 @@
+-- Adds the tenant usage counters checked against tenant quotas to a database created before they
+-- were introduced. Safe to run more than once. The counters of existing tenants are set from the
+-- objects they own.
+
+CREATE TABLE IF NOT EXISTS tenant_usage (
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  kind VARCHAR(32) NOT NULL,
+  count INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, kind)
+);
+
+DROP TRIGGER IF EXISTS update_tenant_usage_updated_at ON tenant_usage;
+CREATE TRIGGER update_tenant_usage_updated_at
+BEFORE UPDATE ON tenant_usage
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE OR REPLACE FUNCTION release_tenant_usage()
+RETURNS TRIGGER AS $$
+DECLARE
+  released INTEGER := 1;
+BEGIN
+  IF TG_ARGV[0] = 'skillsets' THEN
+    SELECT COUNT(*) INTO released FROM jsonb_object_keys(OLD.directory);
+  END IF;
+  UPDATE tenant_usage SET count = GREATEST(count - released, 0)
+  WHERE tenant_id = OLD.tenant_id AND kind = TG_ARGV[0];
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS release_catalogs_usage ON catalogs;
+CREATE TRIGGER release_catalogs_usage
+AFTER DELETE ON catalogs
+FOR EACH ROW
+EXECUTE FUNCTION release_tenant_usage('catalogs');
+
+DROP TRIGGER IF EXISTS release_variants_usage ON variants;
+CREATE TRIGGER release_variants_usage
+AFTER DELETE ON variants
+FOR EACH ROW
+EXECUTE FUNCTION release_tenant_usage('variants');
+
+DROP TRIGGER IF EXISTS release_skillsets_usage ON skillset_directory;
+CREATE TRIGGER release_skillsets_usage
+AFTER DELETE ON skillset_directory
+FOR EACH ROW
+EXECUTE FUNCTION release_tenant_usage('skillsets');
+
+INSERT INTO tenant_usage (tenant_id, kind, count)
+SELECT tenant_id, 'catalogs', COUNT(*) FROM catalogs GROUP BY tenant_id
+UNION ALL
+SELECT tenant_id, 'variants', COUNT(*) FROM variants GROUP BY tenant_id
+UNION ALL
+SELECT d.tenant_id, 'skillsets', COUNT(*) FROM skillset_directory d, jsonb_object_keys(d.directory) k GROUP BY d.tenant_id
+ON CONFLICT (tenant_id, kind) DO UPDATE SET count = EXCLUDED.count;
+
+GRANT ALL PRIVILEGES ON TABLE tenant_usage TO catalogrw;
@@ -0,0 +1,5 @@
+-- Adds the token epoch used to revoke the tokens of a view to a database created before view
+-- tokens could be revoked. Safe to run more than once. Existing views start at epoch 0, so the
+-- tokens already issued for them remain valid until they expire or are revoked.
+
+ALTER TABLE views ADD COLUMN IF NOT EXISTS token_epoch BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- Records the session a view token was issued to in a database created before view tokens were
+-- linked to sessions. Safe to run more than once. Tokens issued before the upgrade are not linked
+-- to their session and are not revoked when the session's tangent is drained.
+
+ALTER TABLE view_tokens ADD COLUMN IF NOT EXISTS session_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_view_tokens_session
+ON view_tokens (tenant_id, session_id)
+WHERE session_id IS NOT NULL;